@@ -2,4 +2,15 @@ module anthropic-oauth-flow
 
 go 1.25.3
 
-require gopkg.in/yaml.v3 v3.0.1 // indirect
+require github.com/cecil-the-coder/ai-provider-kit/examples/config v0.0.0-00010101000000-000000000000
+
+require (
+	github.com/cecil-the-coder/ai-provider-kit v0.0.0-00010101000000-000000000000 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/cecil-the-coder/ai-provider-kit => ../..
+
+replace github.com/cecil-the-coder/ai-provider-kit/examples/config => ../config
@@ -16,7 +16,7 @@ import (
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 )
 
 // Anthropic OAuth Constants
@@ -40,11 +40,6 @@ type TokenResponse struct {
 	Scope        string `json:"scope"`
 }
 
-// Config represents the structure of the config.yaml file
-type Config struct {
-	Providers map[string]interface{} `yaml:"providers"`
-}
-
 // State holds the OAuth flow state
 type State struct {
 	codeVerifier string
@@ -311,73 +306,29 @@ func saveToConfig(accessToken, refreshToken string, expiresAt time.Time) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Read existing config or create new one
-	var config Config
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to read config: %w", err)
-		}
-		// Config doesn't exist, create new one
-		config.Providers = make(map[string]interface{})
-	} else {
-		// Parse existing config
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse config: %w", err)
-		}
-		if config.Providers == nil {
-			config.Providers = make(map[string]interface{})
-		}
-	}
-
-	// Get or create anthropic provider config
-	var anthropicConfig map[string]interface{}
-	if existing, ok := config.Providers["anthropic"].(map[string]interface{}); ok {
-		anthropicConfig = existing
-	} else {
-		anthropicConfig = make(map[string]interface{})
-		config.Providers["anthropic"] = anthropicConfig
-	}
-
-	// Create or update oauth_credentials array
-	credentialSet := map[string]interface{}{
-		"id":            "default",
-		"client_id":     AnthropicClientID,
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"expires_at":    expiresAt.Format(time.RFC3339),
-		"scopes":        strings.Split(AnthropicScope, " "),
-	}
-
-	// Check if oauth_credentials exists and update it
-	if existingCreds, ok := anthropicConfig["oauth_credentials"].([]interface{}); ok && len(existingCreds) > 0 {
-		// Update the first credential set
-		existingCreds[0] = credentialSet
-		anthropicConfig["oauth_credentials"] = existingCreds
-	} else {
-		// Create new oauth_credentials array
-		anthropicConfig["oauth_credentials"] = []interface{}{credentialSet}
+	// Create an empty config file if none exists, as UpdateProviderConfig edits an existing one
+	file, err := os.OpenFile(configPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err == nil {
+		file.Close()
+	} else if !os.IsExist(err) {
+		return fmt.Errorf("failed to create config: %w", err)
 	}
 
-	// Marshal config back to YAML
-	newData, err := yaml.Marshal(&config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	// Write to temp file first (atomic write)
-	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, newData, 0600); err != nil {
-		return fmt.Errorf("failed to write temp config: %w", err)
-	}
-
-	// Rename temp file to actual config
-	if err := os.Rename(tempPath, configPath); err != nil {
-		os.Remove(tempPath) // Clean up temp file on error
-		return fmt.Errorf("failed to rename config: %w", err)
-	}
-
-	return nil
+	// Update only the anthropic section under the shared config lock, so a concurrent
+	// token refresh writing the same file isn't lost and other settings are kept
+	return config.UpdateProviderConfig(configPath, "anthropic", func(section map[string]interface{}) error {
+		// Create or update the first credential set
+		credentialSet := config.OAuthCredentialEntry{
+			ID:           "default",
+			ClientID:     AnthropicClientID,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			ExpiresAt:    expiresAt.Format(time.RFC3339),
+			Scopes:       strings.Split(AnthropicScope, " "),
+		}
+		config.SetOAuthCredential(section, credentialSet)
+		return nil
+	})
 }
 
 // testToken tests the OAuth token by making a simple API call
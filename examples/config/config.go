@@ -119,6 +119,11 @@ func LoadConfig(filename string) (*DemoConfig, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	return parseConfig(data)
+}
+
+// parseConfig parses YAML configuration data
+func parseConfig(data []byte) (*DemoConfig, error) {
 	var config DemoConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
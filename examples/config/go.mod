@@ -6,5 +6,8 @@ replace github.com/cecil-the-coder/ai-provider-kit => ../..
 
 require (
 	github.com/cecil-the-coder/ai-provider-kit v0.0.0-00010101000000-000000000000
+	github.com/fsnotify/fsnotify v1.10.1
 	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/sys v0.13.0 // indirect
@@ -0,0 +1,34 @@
+//go:build !unix
+
+package config
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed to have been left
+// behind by a process that exited without releasing it
+const staleLockAge = 30 * time.Second
+
+// lockFile takes an exclusive lock by creating the lock file at path, waiting while
+// another holder has it. The lock is released by the returned function, which removes
+// the file.
+func lockFile(path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateConfig applies modify to the configuration file at path and saves the result,
+// holding an advisory lock for the whole read-modify-write. Concurrent updates, from
+// other goroutines or from other processes using UpdateConfig, are serialized, so
+// several OAuth credentials refreshing at once don't overwrite each other's tokens.
+//
+// The file is written atomically through a temporary file and rename, keeping the
+// original file's permissions. If modify returns an error, the file is left unchanged.
+// Comments and fields not described by DemoConfig are not preserved; use
+// UpdateProviderConfig to change one provider without touching the rest of the file.
+func UpdateConfig(path string, modify func(*DemoConfig) error) error {
+	return UpdateConfigFile(path, func(data []byte) ([]byte, error) {
+		config, err := parseConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := modify(config); err != nil {
+			return nil, err
+		}
+		data, err = yaml.Marshal(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return data, nil
+	})
+}
+
+// UpdateConfigFile is UpdateConfig for callers that edit the raw contents of the file,
+// such as to keep comments or a layout other than DemoConfig. modify receives the
+// current contents, read under the lock, and returns the contents to save.
+func UpdateConfigFile(path string, modify func(data []byte) ([]byte, error)) error {
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock config file: %w", err)
+	}
+	defer unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	data, err = modify(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, info.Mode().Perm())
+}
+
+// UpdateProviderConfig applies modify to the providers.<provider> section of the
+// configuration file at path, creating the section if it is missing, under the same
+// lock as UpdateConfig. modify receives the section decoded into a map; only the keys
+// it adds, changes or deletes are rewritten. The rest of the file, including comments,
+// other providers and keys DemoConfig does not describe, is kept as it is.
+func UpdateProviderConfig(path, provider string, modify func(section map[string]interface{}) error) error {
+	return UpdateConfigFile(path, func(data []byte) ([]byte, error) {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+		if doc.Kind == 0 { // Empty file
+			doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+		}
+
+		providers, err := mappingValue(doc.Content[0], "providers")
+		if err != nil {
+			return nil, err
+		}
+		node, err := mappingValue(providers, provider)
+		if err != nil {
+			return nil, err
+		}
+
+		var before, section map[string]interface{}
+		if err := node.Decode(&before); err != nil {
+			return nil, fmt.Errorf("failed to parse provider %q: %w", provider, err)
+		}
+		if err := node.Decode(&section); err != nil {
+			return nil, fmt.Errorf("failed to parse provider %q: %w", provider, err)
+		}
+		if section == nil {
+			section = map[string]interface{}{}
+		}
+		if err := modify(section); err != nil {
+			return nil, err
+		}
+
+		for key := range before {
+			if _, ok := section[key]; !ok {
+				deleteMappingKey(node, key)
+			}
+		}
+		keys := make([]string, 0, len(section))
+		for key := range section {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys) // Deterministic order for added keys
+		for _, key := range keys {
+			if old, ok := before[key]; ok && reflect.DeepEqual(old, section[key]) {
+				continue
+			}
+			if err := setMappingValue(node, key, section[key]); err != nil {
+				return nil, fmt.Errorf("failed to marshal provider %q: %w", provider, err)
+			}
+		}
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return buf.Bytes(), nil
+	})
+}
+
+// SetOAuthCredential replaces the first entry of the oauth_credentials list in a
+// provider section passed to UpdateProviderConfig, or adds the list if it is empty.
+// Any further credentials are kept.
+func SetOAuthCredential(section map[string]interface{}, credential OAuthCredentialEntry) {
+	credentials, _ := section["oauth_credentials"].([]interface{})
+	if len(credentials) == 0 {
+		section["oauth_credentials"] = []interface{}{credential}
+		return
+	}
+	credentials[0] = credential
+}
+
+// mappingValue returns the value of key in mapping, adding an empty mapping under key
+// if it is missing. A null node is turned into an empty mapping.
+func mappingValue(mapping *yaml.Node, key string) (*yaml.Node, error) {
+	if err := ensureMapping(mapping); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			if err := ensureMapping(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			return value, nil
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value, nil
+}
+
+func ensureMapping(node *yaml.Node) error {
+	switch {
+	case node.Kind == yaml.MappingNode:
+		return nil
+	case node.Kind == yaml.ScalarNode && node.Tag == "!!null":
+		node.Kind, node.Tag, node.Value = yaml.MappingNode, "!!map", ""
+		return nil
+	default:
+		return fmt.Errorf("config is not a mapping at line %d", node.Line)
+	}
+}
+
+// setMappingValue encodes value under key in mapping, in place if key already exists
+func setMappingValue(mapping *yaml.Node, key string, value interface{}) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = &node
+			return nil
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &node)
+	return nil
+}
+
+func deleteMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over
+// path, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace config: %w", err)
+	}
+	return nil
+}
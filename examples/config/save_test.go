@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testConfig = `providers:
+  enabled: [anthropic]
+  anthropic:
+    oauth_credentials:
+      - id: default
+        access_token: old
+`
+
+func TestUpdateConfig_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each writer adds its own credential, as concurrent token refreshes of different
+	// credentials would; without locking, most updates are lost
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- UpdateConfig(path, func(cfg *DemoConfig) error {
+				entry := cfg.Providers.Anthropic
+				entry.OAuthCredentials = append(entry.OAuthCredentials, OAuthCredentialEntry{
+					ID:          fmt.Sprintf("writer-%d", i),
+					AccessToken: fmt.Sprintf("token-%d", i),
+				})
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("UpdateConfig failed: %v", err)
+		}
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials := cfg.Providers.Anthropic.OAuthCredentials
+	if len(credentials) != writers+1 {
+		t.Fatalf("expected %d credentials, got %d: updates were lost", writers+1, len(credentials))
+	}
+	seen := make(map[string]bool)
+	for _, credential := range credentials {
+		seen[credential.ID] = true
+	}
+	for i := 0; i < writers; i++ {
+		if !seen[fmt.Sprintf("writer-%d", i)] {
+			t.Errorf("update from writer-%d was lost", i)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600 to be kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestUpdateConfig_ModifyError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	errAbort := errors.New("abort")
+	err := UpdateConfig(path, func(cfg *DemoConfig) error {
+		cfg.Providers.Enabled = nil
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected the modify error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testConfig {
+		t.Errorf("file changed after a failed update:\n%s", data)
+	}
+}
+
+func TestUpdateConfig_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+	if err := UpdateConfig(path, func(*DemoConfig) error { return nil }); err == nil {
+		t.Fatal("expected an error for a missing config file")
+	}
+}
+
+func TestUpdateConfigFile_KeepsRawContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "# OAuth providers\n" + testConfig
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateConfigFile(path, func(data []byte) ([]byte, error) {
+		return bytes.Replace(data, []byte("access_token: old"), []byte("access_token: new"), 1), nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfigFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Replace(original, "access_token: old", "access_token: new", 1); string(data) != want {
+		t.Errorf("expected only the token to change, got:\n%s", data)
+	}
+}
+
+func TestUpdateProviderConfig_KeepsOtherSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := `# OAuth providers
+providers:
+  enabled: [anthropic, custom]
+  custom:
+    endpoint: http://localhost:8080 # not modeled by DemoConfig
+  anthropic:
+    default_model: claude-sonnet-4
+    extra_headers:
+      x-team: demo
+    oauth_credentials:
+      - id: default
+        access_token: old
+      - id: backup
+        access_token: backup-token
+logging:
+  level: debug
+`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateProviderConfig(path, "anthropic", func(section map[string]interface{}) error {
+		SetOAuthCredential(section, OAuthCredentialEntry{ID: "default", AccessToken: "new"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateProviderConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# OAuth providers",
+		"endpoint: http://localhost:8080 # not modeled by DemoConfig",
+		"default_model: claude-sonnet-4",
+		"x-team: demo",
+		"access_token: new",
+		"access_token: backup-token",
+		"level: debug",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in updated config:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "access_token: old") {
+		t.Errorf("expected the first credential to be replaced:\n%s", data)
+	}
+}
+
+func TestUpdateProviderConfig_AddsSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateProviderConfig(path, "qwen", func(section map[string]interface{}) error {
+		SetOAuthCredential(section, OAuthCredentialEntry{ID: "default", AccessToken: "token"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateProviderConfig failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := cfg.Providers.Qwen.OAuthCredentials
+	if len(creds) != 1 || creds[0].AccessToken != "token" {
+		t.Errorf("expected the new credential, got %+v", creds)
+	}
+}
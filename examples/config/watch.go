@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long the file must stay unchanged before it is reloaded
+const DefaultWatchDebounce = 250 * time.Millisecond
+
+// Watcher reloads a configuration file when it changes. Create one with Watch.
+type Watcher struct {
+	path     string
+	onChange func(*DemoConfig)
+	debounce time.Duration
+	notify   *fsnotify.Watcher
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Watch watches the configuration file at path and calls onChange with the reparsed
+// config each time its content changes, so that tokens refreshed by another process or
+// an external re-auth tool are picked up by a long-running one.
+//
+// The file's directory is watched rather than the file itself, so the atomic temp-file
+// rename used by UpdateConfig, which replaces the file, is followed. Bursts of writes
+// are debounced: the file is reloaded once it has stopped changing for
+// DefaultWatchDebounce. A change that fails to parse is logged and skipped, leaving the
+// previous config in effect until the next valid write.
+//
+// onChange is called from the watcher's goroutine. Call Close to stop watching.
+func Watch(path string, onChange func(*DemoConfig)) (*Watcher, error) {
+	return watch(path, onChange, DefaultWatchDebounce)
+}
+
+func watch(path string, onChange func(*DemoConfig), debounce time.Duration) (*Watcher, error) {
+	path = filepath.Clean(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := notify.Add(filepath.Dir(path)); err != nil {
+		_ = notify.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		path:     path,
+		onChange: onChange,
+		debounce: debounce,
+		notify:   notify,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run(data)
+	return w, nil
+}
+
+// Close stops watching and waits for a callback in progress to return
+func (w *Watcher) Close() error {
+	var err error
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		err = w.notify.Close()
+	})
+	<-w.done
+	return err
+}
+
+func (w *Watcher) run(content []byte) {
+	defer close(w.done)
+
+	// reload fires once the file has stopped changing for the debounce period
+	reload := time.NewTimer(w.debounce)
+	reload.Stop()
+	defer reload.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case event, ok := <-w.notify.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != w.path || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			reload.Reset(w.debounce)
+			continue
+		case err, ok := <-w.notify.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("[config] Error watching %s: %v", w.path, err)
+			continue
+		case <-reload.C:
+		}
+
+		data, err := os.ReadFile(w.path)
+		if err != nil || bytes.Equal(data, content) {
+			// Missing mid-rename or removed; keep the last config until the next event
+			continue
+		}
+		content = data
+
+		config, err := parseConfig(data)
+		if err != nil {
+			log.Printf("[config] Ignoring invalid change to %s: %v", w.path, err)
+			continue
+		}
+		w.onChange(config)
+	}
+}
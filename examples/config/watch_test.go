@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan *DemoConfig, 10)
+	w, err := watch(path, func(cfg *DemoConfig) { changes <- cfg }, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	// UpdateConfig replaces the file through a rename, which must be followed
+	err = UpdateConfig(path, func(cfg *DemoConfig) error {
+		cfg.Providers.Anthropic.OAuthCredentials[0].AccessToken = "new"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case cfg := <-changes:
+		if got := cfg.Providers.Anthropic.OAuthCredentials[0].AccessToken; got != "new" {
+			t.Errorf("expected the reloaded token to be %q, got %q", "new", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config to reload")
+	}
+
+	// An invalid change is skipped, and a later valid one is picked up
+	if err := os.WriteFile(path, []byte("providers: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case cfg := <-changes:
+		if got := cfg.Providers.Anthropic.OAuthCredentials[0].AccessToken; got != "old" {
+			t.Errorf("expected the reloaded token to be %q, got %q", "old", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config to reload")
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no further reloads, got %d", len(changes))
+	}
+}
+
+func TestWatch_MissingFile(t *testing.T) {
+	if _, err := Watch(filepath.Join(t.TempDir(), "missing.yaml"), func(*DemoConfig) {}); err == nil {
+		t.Fatal("expected an error for a missing config file")
+	}
+}
+
+func TestWatcher_CloseStopsCallbacks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan *DemoConfig, 10)
+	w, err := watch(path, func(cfg *DemoConfig) { changes <- cfg }, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	_ = w.Close() // Closing twice is safe
+
+	if err := os.WriteFile(path, []byte("providers:\n  enabled: [openai]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(changes) != 0 {
+		t.Errorf("expected no reloads after Close, got %d", len(changes))
+	}
+}
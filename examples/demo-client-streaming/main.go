@@ -330,6 +330,8 @@ func testProviderComparison(factory *factory.DefaultProviderFactory, cfg *config
 		fmt.Printf("%s└─────────────────────────────────────────────────────────┘%s\n\n", colorGreen, colorReset)
 		return
 	}
+	// Pace the output for a typewriter effect even when the provider sends bursts
+	stream = types.SmoothStream(ctx, stream, 200)
 
 	fmt.Print("│ ")
 	response = ""
@@ -350,10 +352,8 @@ func testProviderComparison(factory *factory.DefaultProviderFactory, cfg *config
 			response += chunk.Content
 			chunkCount++
 
-			// Simulate real-time display with slight delay
 			for _, char := range chunk.Content {
 				fmt.Print(string(char))
-				time.Sleep(5 * time.Millisecond) // Visual effect
 				col++
 				if char == '\n' {
 					fmt.Print("│ ")
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	democonfig "github.com/cecil-the-coder/ai-provider-kit/examples/config"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 	"gopkg.in/yaml.v3"
 )
@@ -243,28 +244,19 @@ func (cm *ConfigManager) CreateTokenRefreshCallback(providerName string) func(st
 	}
 }
 
-// SaveConfig saves the current configuration back to the YAML file
+// SaveConfig saves the current configuration back to the YAML file. The file is
+// updated under the shared config lock, so concurrent token refreshes don't lose
+// each other's updates.
 func (cm *ConfigManager) SaveConfig() error {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
-	// Read the original file to preserve formatting and comments
-	originalData, err := ioutil.ReadFile(cm.configPath)
-	if err != nil {
-		return fmt.Errorf("failed to read original config: %w", err)
-	}
-
-	// For OAuth providers, update only the token fields
-	lines := strings.Split(string(originalData), "\n")
-	updatedLines := cm.updateTokenFields(lines)
-
-	// Write back
-	updatedData := strings.Join(updatedLines, "\n")
-	if err := ioutil.WriteFile(cm.configPath, []byte(updatedData), 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
-	}
-
-	return nil
+	// Edit the current file in place to preserve formatting and comments, updating
+	// only the token fields of OAuth providers
+	return democonfig.UpdateConfigFile(cm.configPath, func(data []byte) ([]byte, error) {
+		lines := cm.updateTokenFields(strings.Split(string(data), "\n"))
+		return []byte(strings.Join(lines, "\n")), nil
+	})
 }
 
 // updateTokenFields updates only token-related fields in the config
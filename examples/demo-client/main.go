@@ -14,7 +14,6 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/factory"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
-	"gopkg.in/yaml.v3"
 )
 
 // TokenManager handles OAuth token updates and persistence
@@ -44,64 +43,50 @@ func (tm *TokenManager) CreateMultiOAuthCallback(providerName, credentialID stri
 		}
 		fmt.Printf("   Expires At: %s\n", expiresAt.Format(time.RFC3339))
 
-		// Load current config using shared package
-		data, err := os.ReadFile(tm.configPath)
-		if err != nil {
-			return fmt.Errorf("failed to read config: %w", err)
-		}
-
-		var cfg config.DemoConfig
-		if err := yaml.Unmarshal(data, &cfg); err != nil {
-			return fmt.Errorf("failed to parse config: %w", err)
-		}
-
-		// Find and update the specific credential in the specific provider
-		var provider *config.ProviderConfigEntry
-		switch providerName {
-		case "anthropic":
-			provider = cfg.Providers.Anthropic
-		case "cerebras":
-			provider = cfg.Providers.Cerebras
-		case "gemini":
-			provider = cfg.Providers.Gemini
-		case "qwen":
-			provider = cfg.Providers.Qwen
-		default:
-			// Check custom providers
-			if cfg.Providers.Custom != nil {
-				if customConfig, ok := cfg.Providers.Custom[providerName]; ok {
-					provider = &customConfig
-					defer func() {
-						if provider != nil {
-							cfg.Providers.Custom[providerName] = *provider
-						}
-					}()
+		// Update the config file under a lock, so concurrent refreshes don't lose updates
+		err := config.UpdateConfig(tm.configPath, func(cfg *config.DemoConfig) error {
+			// Find and update the specific credential in the specific provider
+			var provider *config.ProviderConfigEntry
+			switch providerName {
+			case "anthropic":
+				provider = cfg.Providers.Anthropic
+			case "cerebras":
+				provider = cfg.Providers.Cerebras
+			case "gemini":
+				provider = cfg.Providers.Gemini
+			case "qwen":
+				provider = cfg.Providers.Qwen
+			default:
+				// Check custom providers
+				if cfg.Providers.Custom != nil {
+					if customConfig, ok := cfg.Providers.Custom[providerName]; ok {
+						provider = &customConfig
+						defer func() {
+							if provider != nil {
+								cfg.Providers.Custom[providerName] = *provider
+							}
+						}()
+					}
 				}
 			}
-		}
 
-		if provider != nil && len(provider.OAuthCredentials) > 0 {
-			// Find and update the specific credential
-			for i := range provider.OAuthCredentials {
-				if provider.OAuthCredentials[i].ID == credentialID {
-					provider.OAuthCredentials[i].AccessToken = accessToken
-					if refreshToken != "" {
-						provider.OAuthCredentials[i].RefreshToken = refreshToken
+			if provider != nil && len(provider.OAuthCredentials) > 0 {
+				// Find and update the specific credential
+				for i := range provider.OAuthCredentials {
+					if provider.OAuthCredentials[i].ID == credentialID {
+						provider.OAuthCredentials[i].AccessToken = accessToken
+						if refreshToken != "" {
+							provider.OAuthCredentials[i].RefreshToken = refreshToken
+						}
+						provider.OAuthCredentials[i].ExpiresAt = expiresAt.Format(time.RFC3339)
+						break
 					}
-					provider.OAuthCredentials[i].ExpiresAt = expiresAt.Format(time.RFC3339)
-					break
 				}
 			}
-		}
-
-		// Save updated config
-		updatedData, err := yaml.Marshal(&cfg)
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("failed to marshal config: %w", err)
-		}
-
-		if err := os.WriteFile(tm.configPath, updatedData, 0644); err != nil {
-			return fmt.Errorf("failed to write config: %w", err)
+			return fmt.Errorf("failed to persist tokens: %w", err)
 		}
 
 		fmt.Printf("✅ Successfully persisted refreshed tokens to config.yaml\n\n")
@@ -241,6 +226,10 @@ func TestProviderWithOptions(provider types.Provider, providerName string, promp
 	if metrics.AverageLatency > 0 {
 		fmt.Printf("   Average Latency: %v\n", metrics.AverageLatency)
 	}
+	if metrics.EWMALatency > 0 {
+		fmt.Printf("   Recent Latency (EWMA): %v (p50 %v, p95 %v, p99 %v)\n",
+			metrics.EWMALatency, metrics.P50Latency, metrics.P95Latency, metrics.P99Latency)
+	}
 }
 
 // TestProviderWithStreaming tests streaming capability
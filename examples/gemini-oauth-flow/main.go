@@ -18,7 +18,7 @@ import (
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 )
 
 // Google OAuth Constants (from official gemini-cli - safe to embed per Google's OAuth guidelines)
@@ -50,11 +50,6 @@ type TokenResponse struct {
 	Scope        string `json:"scope"`
 }
 
-// Config represents the structure of the config.yaml file
-type Config struct {
-	Providers map[string]interface{} `yaml:"providers"`
-}
-
 // CallbackResult represents the result of OAuth callback
 type CallbackResult struct {
 	Code  string
@@ -348,75 +343,35 @@ func saveToConfig(accessToken, refreshToken string, expiresAt time.Time) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Read existing config or create new one
-	var config Config
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to read config: %w", err)
+	// Create an empty config file if none exists, as UpdateProviderConfig edits an existing one
+	file, err := os.OpenFile(configPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err == nil {
+		file.Close()
+	} else if !os.IsExist(err) {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+
+	// Update only the gemini section under the shared config lock, so a concurrent
+	// token refresh writing the same file isn't lost and other settings are kept
+	return config.UpdateProviderConfig(configPath, "gemini", func(section map[string]interface{}) error {
+		// Create or update the first credential set
+		credentialSet := config.OAuthCredentialEntry{
+			ID:           "default",
+			ClientID:     GoogleClientID,
+			ClientSecret: GoogleClientSecret,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			ExpiresAt:    expiresAt.Format(time.RFC3339),
+			Scopes:       []string{GoogleScope},
 		}
-		config.Providers = make(map[string]interface{})
-	} else {
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse config: %w", err)
-		}
-		if config.Providers == nil {
-			config.Providers = make(map[string]interface{})
-		}
-	}
+		config.SetOAuthCredential(section, credentialSet)
 
-	// Get or create gemini provider config
-	var geminiConfig map[string]interface{}
-	if existing, ok := config.Providers["gemini"].(map[string]interface{}); ok {
-		geminiConfig = existing
-	} else {
-		geminiConfig = make(map[string]interface{})
-		config.Providers["gemini"] = geminiConfig
-	}
-
-	// Create or update oauth_credentials array
-	credentialSet := map[string]interface{}{
-		"id":            "default",
-		"client_id":     GoogleClientID,
-		"client_secret": GoogleClientSecret,
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"expires_at":    expiresAt.Format(time.RFC3339),
-		"scopes":        []string{GoogleScope},
-	}
-
-	// Check if oauth_credentials exists and update it
-	if existingCreds, ok := geminiConfig["oauth_credentials"].([]interface{}); ok && len(existingCreds) > 0 {
-		existingCreds[0] = credentialSet
-		geminiConfig["oauth_credentials"] = existingCreds
-	} else {
-		geminiConfig["oauth_credentials"] = []interface{}{credentialSet}
-	}
-
-	// Set default model if not present
-	if _, ok := geminiConfig["default_model"]; !ok {
-		geminiConfig["default_model"] = "gemini-2.5-pro"
-	}
-
-	// Marshal config back to YAML
-	newData, err := yaml.Marshal(&config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	// Write to temp file first (atomic write)
-	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, newData, 0600); err != nil {
-		return fmt.Errorf("failed to write temp config: %w", err)
-	}
-
-	// Rename temp file to actual config
-	if err := os.Rename(tempPath, configPath); err != nil {
-		os.Remove(tempPath)
-		return fmt.Errorf("failed to rename config: %w", err)
-	}
-
-	return nil
+		// Set default model if not present
+		if model, _ := section["default_model"].(string); model == "" {
+			section["default_model"] = "gemini-2.5-pro"
+		}
+		return nil
+	})
 }
 
 // testToken tests the OAuth token by making a simple API call
@@ -14,6 +14,7 @@ import (
 
 	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/factory"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -114,17 +115,17 @@ func (fm *FallbackManager) tryProvider(providerName string, prompt string) Provi
 		}
 	}
 
-	// Run health check first
+	// Warm up first: refreshes credentials and loads models, failing fast on a broken provider
 	ctx, cancel := context.WithTimeout(context.Background(), fm.timeout)
 	defer cancel()
 
-	fmt.Printf("   Running health check for %s...\n", providerName)
-	if err := provider.HealthCheck(ctx); err != nil {
-		result.Error = fmt.Errorf("health check failed: %w", err)
+	fmt.Printf("   Warming up %s...\n", providerName)
+	if err := common.Warmup(ctx, provider, common.WarmupOptions{}); err != nil {
+		result.Error = fmt.Errorf("warmup failed: %w", err)
 		result.Metrics = provider.GetMetrics()
 		return result
 	}
-	fmt.Printf("   Health check passed\n")
+	fmt.Printf("   Provider ready\n")
 
 	// Attempt chat completion
 	fmt.Printf("   Sending prompt to %s...\n", providerName)
@@ -12,7 +12,7 @@ import (
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 )
 
 // OpenAI API Constants
@@ -20,11 +20,6 @@ const (
 	OpenAIAPIBaseURL = "https://api.openai.com/v1"
 )
 
-// Config represents the structure of the config.yaml file
-type Config struct {
-	Providers map[string]interface{} `yaml:"providers"`
-}
-
 // OpenAIModelsResponse represents the response from the models API
 type OpenAIModelsResponse struct {
 	Object string        `json:"object"`
@@ -234,61 +229,26 @@ func saveToConfig(apiKey string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Read existing config or create new one
-	var config Config
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to read config: %w", err)
-		}
-		// Config doesn't exist, create new one
-		config.Providers = make(map[string]interface{})
-	} else {
-		// Parse existing config
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse config: %w", err)
-		}
-		if config.Providers == nil {
-			config.Providers = make(map[string]interface{})
-		}
+	// Create an empty config file if none exists, as UpdateProviderConfig edits an existing one
+	file, err := os.OpenFile(configPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err == nil {
+		file.Close()
+	} else if !os.IsExist(err) {
+		return fmt.Errorf("failed to create config: %w", err)
 	}
 
-	// Get or create openai provider config
-	var openaiConfig map[string]interface{}
-	if existing, ok := config.Providers["openai"].(map[string]interface{}); ok {
-		openaiConfig = existing
-	} else {
-		openaiConfig = make(map[string]interface{})
-		config.Providers["openai"] = openaiConfig
-	}
+	// Update only the openai section under the shared config lock, so a concurrent
+	// token refresh writing the same file isn't lost and other settings are kept
+	return config.UpdateProviderConfig(configPath, "openai", func(section map[string]interface{}) error {
+		// Set the API key
+		section["api_key"] = apiKey
 
-	// Set the API key
-	openaiConfig["api_key"] = apiKey
-
-	// Optionally set default model if not already set
-	if _, exists := openaiConfig["default_model"]; !exists {
-		openaiConfig["default_model"] = "gpt-4o"
-	}
-
-	// Marshal config back to YAML
-	newData, err := yaml.Marshal(&config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	// Write to temp file first (atomic write)
-	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, newData, 0600); err != nil {
-		return fmt.Errorf("failed to write temp config: %w", err)
-	}
-
-	// Rename temp file to actual config
-	if err := os.Rename(tempPath, configPath); err != nil {
-		os.Remove(tempPath) // Clean up temp file on error
-		return fmt.Errorf("failed to rename config: %w", err)
-	}
-
-	return nil
+		// Optionally set default model if not already set
+		if model, _ := section["default_model"].(string); model == "" {
+			section["default_model"] = "gpt-4o"
+		}
+		return nil
+	})
 }
 
 // isChatModel checks if a model ID is a chat model
@@ -16,8 +16,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 	"github.com/google/uuid"
-	"gopkg.in/yaml.v3"
 )
 
 // Qwen OAuth Constants
@@ -57,11 +57,6 @@ type TokenErrorResponse struct {
 	ErrorDescription string `json:"error_description"`
 }
 
-// Config represents the structure of the config.yaml file
-type Config struct {
-	Providers map[string]interface{} `yaml:"providers"`
-}
-
 func main() {
 	fmt.Println("=======================================================================")
 	fmt.Println("Qwen OAuth Authentication")
@@ -280,73 +275,29 @@ func saveToConfig(accessToken, refreshToken string, expiresAt time.Time) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Read existing config or create new one
-	var config Config
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to read config: %w", err)
-		}
-		// Config doesn't exist, create new one
-		config.Providers = make(map[string]interface{})
-	} else {
-		// Parse existing config
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse config: %w", err)
-		}
-		if config.Providers == nil {
-			config.Providers = make(map[string]interface{})
-		}
-	}
-
-	// Get or create qwen provider config
-	var qwenConfig map[string]interface{}
-	if existing, ok := config.Providers["qwen"].(map[string]interface{}); ok {
-		qwenConfig = existing
-	} else {
-		qwenConfig = make(map[string]interface{})
-		config.Providers["qwen"] = qwenConfig
-	}
-
-	// Create or update oauth_credentials array
-	credentialSet := map[string]interface{}{
-		"id":            "default",
-		"client_id":     QwenClientID,
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"expires_at":    expiresAt.Format(time.RFC3339),
-		"scopes":        []string{QwenScope},
+	// Create an empty config file if none exists, as UpdateProviderConfig edits an existing one
+	file, err := os.OpenFile(configPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err == nil {
+		file.Close()
+	} else if !os.IsExist(err) {
+		return fmt.Errorf("failed to create config: %w", err)
 	}
 
-	// Check if oauth_credentials exists and update it
-	if existingCreds, ok := qwenConfig["oauth_credentials"].([]interface{}); ok && len(existingCreds) > 0 {
-		// Update the first credential set
-		existingCreds[0] = credentialSet
-		qwenConfig["oauth_credentials"] = existingCreds
-	} else {
-		// Create new oauth_credentials array
-		qwenConfig["oauth_credentials"] = []interface{}{credentialSet}
-	}
-
-	// Marshal config back to YAML
-	newData, err := yaml.Marshal(&config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	// Write to temp file first (atomic write)
-	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, newData, 0600); err != nil {
-		return fmt.Errorf("failed to write temp config: %w", err)
-	}
-
-	// Rename temp file to actual config
-	if err := os.Rename(tempPath, configPath); err != nil {
-		os.Remove(tempPath) // Clean up temp file on error
-		return fmt.Errorf("failed to rename config: %w", err)
-	}
-
-	return nil
+	// Update only the qwen section under the shared config lock, so a concurrent
+	// token refresh writing the same file isn't lost and other settings are kept
+	return config.UpdateProviderConfig(configPath, "qwen", func(section map[string]interface{}) error {
+		// Create or update the first credential set
+		credentialSet := config.OAuthCredentialEntry{
+			ID:           "default",
+			ClientID:     QwenClientID,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			ExpiresAt:    expiresAt.Format(time.RFC3339),
+			Scopes:       []string{QwenScope},
+		}
+		config.SetOAuthCredential(section, credentialSet)
+		return nil
+	})
 }
 
 // testToken tests the OAuth token by making a simple API call
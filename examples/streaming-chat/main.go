@@ -7,8 +7,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
@@ -124,30 +124,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Track statistics
-	var totalContent string
+	// Track statistics; the tee captures the full response when the stream is closed
+	var transcript strings.Builder
+	stream = types.TeeStream(stream, &transcript)
 	var chunkCount int
 	var usage types.Usage
 
-	// Read stream tokens
-	for {
-		chunk, err := stream.Next()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			// Check for EOF-like errors (some providers use custom error messages)
-			if err.Error() == "EOF" || err.Error() == "no more chunks" {
-				break
-			}
-			_, _ = fmt.Fprintf(os.Stderr, "\nError reading stream: %v\n", err)
-			break
-		}
-
+	// Read stream tokens; the stream is closed when StreamToCallback returns
+	err = types.StreamToCallback(stream, func(chunk types.ChatCompletionChunk) error {
 		// Print content immediately without newline for streaming effect
 		if chunk.Content != "" {
 			fmt.Print(chunk.Content)
-			totalContent += chunk.Content
 			chunkCount++
 		}
 
@@ -155,19 +142,14 @@ func main() {
 		if chunk.Usage.TotalTokens > 0 {
 			usage = chunk.Usage
 		}
-
-		// Check if stream is done
-		if chunk.Done {
-			break
-		}
-	}
-
-	// Close the stream
-	if closer, ok := stream.(interface{ Close() error }); ok {
-		closer.Close()
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "\nError reading stream: %v\n", err)
 	}
 
 	duration := time.Since(startTime)
+	totalContent := transcript.String()
 
 	// Print final statistics
 	fmt.Println()
@@ -13,6 +13,7 @@ import (
 
 	"github.com/cecil-the-coder/ai-provider-kit/examples/config"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/factory"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/toolvalidator"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -207,8 +208,8 @@ func processStreamingResponse(provider types.Provider, ctx context.Context, opti
 	}
 
 	var response types.ChatMessage
-	response.Role = "assistant"                      // Default role for assistant responses
-	toolCallsMap := make(map[string]*types.ToolCall) // Track tool calls by ID for accumulation
+	response.Role = "assistant"                     // Default role for assistant responses
+	toolCalls := streaming.NewToolCallAccumulator() // Merges tool call deltas by index or ID
 
 	for {
 		chunk, err := stream.Next()
@@ -229,19 +230,16 @@ func processStreamingResponse(provider types.Provider, ctx context.Context, opti
 			}
 		}
 
-		response, toolCallsMap = processChunk(response, chunk, toolCallsMap)
+		response = processChunk(response, chunk, toolCalls)
 	}
 
-	// Convert map to slice
-	for _, tc := range toolCallsMap {
-		response.ToolCalls = append(response.ToolCalls, *tc)
-	}
+	response.ToolCalls = toolCalls.ToolCalls()
 
 	return response, nil
 }
 
 // processChunk processes a single chunk from the streaming response
-func processChunk(response types.ChatMessage, chunk types.ChatCompletionChunk, toolCallsMap map[string]*types.ToolCall) (types.ChatMessage, map[string]*types.ToolCall) {
+func processChunk(response types.ChatMessage, chunk types.ChatCompletionChunk, toolCalls *streaming.ToolCallAccumulator) types.ChatMessage {
 	// Access delta from choices
 	if len(chunk.Choices) > 0 {
 		delta := chunk.Choices[0].Delta
@@ -251,39 +249,15 @@ func processChunk(response types.ChatMessage, chunk types.ChatCompletionChunk, t
 		if delta.Content != "" {
 			response.Content += delta.Content
 		}
-		// Accumulate tool calls by ID
-		for _, tc := range delta.ToolCalls {
-			if tc.ID != "" {
-				// New tool call or tool call with ID
-				if _, exists := toolCallsMap[tc.ID]; !exists {
-					toolCallsMap[tc.ID] = &types.ToolCall{
-						ID:   tc.ID,
-						Type: tc.Type,
-						Function: types.ToolCallFunction{
-							Name:      tc.Function.Name,
-							Arguments: tc.Function.Arguments,
-						},
-					}
-				} else {
-					// Accumulate arguments
-					toolCallsMap[tc.ID].Function.Arguments += tc.Function.Arguments
-				}
-			} else if len(toolCallsMap) > 0 {
-				// No ID means it's a continuation of the last tool call
-				// Get the last tool call (assuming single tool call for now)
-				for _, existingTC := range toolCallsMap {
-					existingTC.Function.Arguments += tc.Function.Arguments
-					break // Only update the first/last one
-				}
-			}
-		}
 	}
+	// Accumulate tool call deltas (by index for OpenAI, by ID for Anthropic)
+	toolCalls.AddChunk(chunk)
 	// Also check convenience Content field
 	if chunk.Content != "" {
 		response.Content += chunk.Content
 	}
 
-	return response, toolCallsMap
+	return response
 }
 
 // executeToolCallsAndExecute executes tool calls and updates the conversation
@@ -10,6 +10,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
 // HTTPClient provides a reusable HTTP client with common patterns for AI providers
@@ -28,6 +30,8 @@ type HTTPClient struct {
 // HTTPClientConfig configures the HTTP client
 type HTTPClientConfig struct {
 	Timeout             time.Duration       `json:"timeout,omitempty"`
+	ConnectTimeout      time.Duration       `json:"connect_timeout,omitempty"` // Dial, TLS and response headers; see NewConnectTimeoutTransport
+	RequestTimeout      time.Duration       `json:"request_timeout,omitempty"` // Replaces Timeout without cutting off streams; see NewRequestTimeoutTransport
 	MaxRetries          int                 `json:"max_retries,omitempty"`
 	BaseRetryDelay      time.Duration       `json:"base_retry_delay,omitempty"`
 	MaxRetryDelay       time.Duration       `json:"max_retry_delay,omitempty"`
@@ -35,9 +39,13 @@ type HTTPClientConfig struct {
 	RetryableErrors     []string            `json:"retryable_errors,omitempty"`
 	Headers             map[string]string   `json:"headers,omitempty"`
 	UserAgent           string              `json:"user_agent,omitempty"`
+	CompressRequests    bool                `json:"compress_requests,omitempty"`
 	EnableMetrics       bool                `json:"enable_metrics,omitempty"`
 	RequestInterceptor  RequestInterceptor  `json:"-"`
 	ResponseInterceptor ResponseInterceptor `json:"-"`
+
+	// WrapTransport wraps the client's transport, outermost; see WithTransportWrapper
+	WrapTransport func(http.RoundTripper) http.RoundTripper `json:"-"`
 	// Transport configuration
 	MaxIdleConns          int           `json:"max_idle_conns,omitempty"`
 	MaxIdleConnsPerHost   int           `json:"max_idle_conns_per_host,omitempty"`
@@ -120,13 +128,22 @@ func NewHTTPClient(config HTTPClientConfig) *HTTPClient {
 	}
 
 	// Create custom transport with connection pooling settings
-	transport := createTransport(config)
+	var transport http.RoundTripper = NewConnectTimeoutTransport(createTransport(config), config.ConnectTimeout)
+	if needsCompressionTransport(config.Headers, config.CompressRequests) {
+		transport = NewCompressionTransport(transport, config.CompressRequests)
+	}
+	transport = NewRequestTimeoutTransport(transport, config.RequestTimeout)
+	timeout := config.Timeout
+	if config.RequestTimeout > 0 {
+		timeout = 0
+	}
 
 	client := &HTTPClient{
-		client: &http.Client{
-			Timeout:   config.Timeout,
-			Transport: transport,
-		},
+		client: WithTransportWrapper(&http.Client{
+			Timeout: timeout,
+			// Apply user-supplied headers to the raw client too, so callers using Client() get them
+			Transport: NewHeaderTransport(transport, config.Headers, config.UserAgent),
+		}, config.WrapTransport),
 		config:       config,
 		metrics:      &ClientMetrics{ErrorsByType: make(map[int]int64)},
 		retryHandler: &RetryHandler{config: config},
@@ -219,7 +236,7 @@ func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 		// Make the request
 		resp, err = c.client.Do(retryReq)
 		if err != nil {
-			if c.shouldRetryError(err, attempts) {
+			if c.shouldRetryError(err, attempts) && types.AcquireRetry(ctx) {
 				continue
 			}
 			break
@@ -234,7 +251,7 @@ func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 		}
 
 		// Check if we should retry based on status code
-		if c.shouldRetryStatus(resp.StatusCode, attempts) {
+		if c.shouldRetryStatus(resp.StatusCode, attempts) && types.AcquireRetry(ctx) {
 			_ = resp.Body.Close() //nolint:errcheck // Best effort close
 			continue
 		}
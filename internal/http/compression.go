@@ -0,0 +1,172 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultCompressMinSize is the smallest request body CompressionTransport compresses.
+// Smaller bodies gain little and cost a gzip header and CPU.
+const DefaultCompressMinSize = 8 * 1024
+
+// CompressionTransport is an http.RoundTripper that negotiates gzip responses and
+// decompresses them transparently, including streamed (SSE) responses, which are
+// decompressed as they are read. With CompressRequests set, it also gzips request
+// bodies of at least MinSize bytes.
+//
+// Request compression only suits APIs that accept Content-Encoding: gzip request
+// bodies. Requests that already have a Content-Encoding, and requests signed over
+// their payload (X-Amz-Content-Sha256, as AWS Signature V4 does), are never changed;
+// such requests must be compressed with GzipRequestBody before they are signed.
+type CompressionTransport struct {
+	Base             http.RoundTripper
+	CompressRequests bool
+	MinSize          int
+}
+
+// NewCompressionTransport wraps base with gzip response decompression and, when
+// compressRequests is set, gzip compression of large request bodies
+func NewCompressionTransport(base http.RoundTripper, compressRequests bool) *CompressionTransport {
+	return &CompressionTransport{
+		Base:             base,
+		CompressRequests: compressRequests,
+		MinSize:          DefaultCompressMinSize,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CompressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+
+	if t.CompressRequests && t.shouldCompress(req) {
+		if err := GzipRequestBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Setting Accept-Encoding disables the standard transport's own decompression,
+	// so responses are decompressed below whatever transport is underneath
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") && resp.Body != nil && resp.Body != http.NoBody {
+		resp.Body = &gzipResponseBody{body: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// shouldCompress reports whether req's body may and should be compressed
+func (t *CompressionTransport) shouldCompress(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return false
+	}
+	if req.Header.Get("Content-Encoding") != "" || req.Header.Get("X-Amz-Content-Sha256") != "" {
+		return false
+	}
+	minSize := t.MinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	// Unknown lengths (-1) are compressed; GzipRequestBody reads the body anyway
+	return req.ContentLength < 0 || req.ContentLength >= int64(minSize)
+}
+
+// GzipRequestBody replaces req's body with its gzip compression and sets
+// Content-Encoding and Content-Length accordingly. Call it before signing a request
+// whose signature covers the payload, so the signature is computed over the
+// compressed bytes that are sent.
+func GzipRequestBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read request body for compression: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	compressed := buf.Bytes()
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// gzipResponseBody decompresses a response body lazily, so a streamed response is
+// decompressed as its events arrive rather than after the stream ends
+type gzipResponseBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipResponseBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+func (b *gzipResponseBody) Close() error {
+	return b.body.Close()
+}
+
+// needsCompressionTransport reports whether a client needs a CompressionTransport.
+// http.Transport already requests gzip and decompresses it lazily on its own, unless
+// the caller sets Accept-Encoding, as a configured header does.
+func needsCompressionTransport(headers map[string]string, compressRequests bool) bool {
+	if compressRequests {
+		return true
+	}
+	for k := range headers {
+		if strings.EqualFold(k, "Accept-Encoding") {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCompression returns a client whose requests go through a CompressionTransport.
+// The client is modified in place; a nil client is replaced by a new one.
+func WithCompression(client *http.Client, compressRequests bool) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	client.Transport = NewCompressionTransport(client.Transport, compressRequests)
+	return client
+}
@@ -0,0 +1,197 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressionTransport_DecompressesResponse(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(`{"ok":true}`))
+		_ = zw.Close()
+	}))
+	defer server.Close()
+
+	client := WithCompression(&http.Client{}, false)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("expected decompressed body, got %q", body)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("expected Accept-Encoding gzip, got %q", acceptEncoding)
+	}
+	if resp.Header.Get("Content-Encoding") != "" || !resp.Uncompressed {
+		t.Errorf("expected response to be marked uncompressed, got headers %v", resp.Header)
+	}
+}
+
+func TestCompressionTransport_DecompressesStreamIncrementally(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte("data: first\n\n"))
+		_ = zw.Flush()
+		w.(http.Flusher).Flush()
+
+		// The second event is only sent once the client has read the first
+		<-release
+		_, _ = zw.Write([]byte("data: second\n\n"))
+		_ = zw.Close()
+	}))
+	defer server.Close()
+
+	client := WithCompression(&http.Client{}, false)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		close(release)
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	close(release)
+	if err != nil || line != "data: first\n" {
+		t.Fatalf("expected first event before the stream ends, got %q (%v)", line, err)
+	}
+
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error reading stream: %v", err)
+	}
+	if !strings.Contains(string(rest), "data: second") {
+		t.Errorf("expected second event, got %q", rest)
+	}
+}
+
+func TestCompressionTransport_CompressesLargeRequests(t *testing.T) {
+	var encoding string
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		body := io.Reader(r.Body)
+		if encoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		received, _ = io.ReadAll(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	post := func(t *testing.T, client *http.Client, body []byte, header string) {
+		t.Helper()
+		req, _ := http.NewRequest("POST", server.URL, bytes.NewReader(body))
+		if header != "" {
+			req.Header.Set(header, "set")
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+		if !bytes.Equal(received, body) {
+			t.Errorf("expected server to receive the original body")
+		}
+	}
+
+	large := bytes.Repeat([]byte("a"), DefaultCompressMinSize)
+	small := []byte(`{"prompt":"hi"}`)
+
+	t.Run("large body is compressed", func(t *testing.T) {
+		post(t, WithCompression(&http.Client{}, true), large, "")
+		if encoding != "gzip" {
+			t.Errorf("expected gzip request, got Content-Encoding %q", encoding)
+		}
+	})
+
+	t.Run("small body is sent as is", func(t *testing.T) {
+		post(t, WithCompression(&http.Client{}, true), small, "")
+		if encoding != "" {
+			t.Errorf("expected uncompressed request, got Content-Encoding %q", encoding)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		post(t, WithCompression(&http.Client{}, false), large, "")
+		if encoding != "" {
+			t.Errorf("expected uncompressed request, got Content-Encoding %q", encoding)
+		}
+	})
+
+	t.Run("signed body is sent as is", func(t *testing.T) {
+		post(t, WithCompression(&http.Client{}, true), large, "X-Amz-Content-Sha256")
+		if encoding != "" {
+			t.Errorf("expected signed request to be left alone, got Content-Encoding %q", encoding)
+		}
+	})
+}
+
+func TestGzipRequestBody(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://example.com", strings.NewReader("hello"))
+	if err := GzipRequestBody(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	compressed, _ := io.ReadAll(req.Body)
+	if req.ContentLength != int64(len(compressed)) {
+		t.Errorf("expected Content-Length %d, got %d", len(compressed), req.ContentLength)
+	}
+	if req.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("expected Content-Encoding gzip, got %q", req.Header.Get("Content-Encoding"))
+	}
+
+	// GetBody must replay the compressed body for retries and redirects
+	replay, err := req.GetBody()
+	if err != nil {
+		t.Fatalf("unexpected GetBody error: %v", err)
+	}
+	replayed, _ := io.ReadAll(replay)
+	if !bytes.Equal(replayed, compressed) {
+		t.Error("expected GetBody to return the compressed body")
+	}
+}
+
+func TestNewHTTPClient_CompressionTransport(t *testing.T) {
+	if _, ok := NewHTTPClient(HTTPClientConfig{}).client.Transport.(*http.Transport); !ok {
+		t.Error("expected the standard transport to handle gzip responses by default")
+	}
+	if _, ok := NewHTTPClient(HTTPClientConfig{CompressRequests: true}).client.Transport.(*CompressionTransport); !ok {
+		t.Error("expected CompressionTransport when CompressRequests is set")
+	}
+
+	// A configured Accept-Encoding disables the standard transport's decompression
+	client := NewHTTPClient(HTTPClientConfig{Headers: map[string]string{"Accept-Encoding": "gzip"}})
+	header, ok := client.client.Transport.(*HeaderTransport)
+	if !ok {
+		t.Fatalf("expected HeaderTransport, got %T", client.client.Transport)
+	}
+	if _, ok := header.Base.(*CompressionTransport); !ok {
+		t.Errorf("expected CompressionTransport beneath configured headers, got %T", header.Base)
+	}
+}
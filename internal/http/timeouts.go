@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Connection and request timeouts.
+//
+// ConnectTimeout bounds establishing a connection and receiving the response headers:
+// dialing, the TLS handshake, and waiting for the first byte of the response. It applies
+// to streaming and non-streaming requests alike, so a dead endpoint fails fast.
+//
+// RequestTimeout bounds a whole request, including reading the response body. Streamed
+// responses (Server-Sent Events and NDJSON) are bounded by it only until their headers
+// arrive, so a long generation is not cut off mid-stream; bound those with the request
+// context instead.
+//
+// Both work alongside the request context: whichever of the context's deadline and the
+// timeout comes first ends the request.
+
+// streamingContentTypes are the response media types treated as streams
+var streamingContentTypes = map[string]bool{
+	"text/event-stream":    true,
+	"application/x-ndjson": true,
+}
+
+// IsStreamingResponse reports whether resp is a streamed response: Server-Sent Events
+// or newline-delimited JSON
+func IsStreamingResponse(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && streamingContentTypes[mediaType]
+}
+
+// NewConnectTimeoutTransport returns a transport whose dial, TLS handshake and wait for
+// the response headers are each bounded by timeout. base must be nil, which uses
+// http.DefaultTransport's settings, or an *http.Transport, which is cloned; any other
+// base is returned unchanged, as its connections cannot be configured. A timeout of 0
+// returns base unchanged.
+func NewConnectTimeoutTransport(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return base
+	}
+
+	transport = transport.Clone()
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+	return transport
+}
+
+// RequestTimeoutTransport is an http.RoundTripper that bounds each request, including
+// reading its response body, by Timeout. Streamed responses are bounded only until their
+// headers arrive; see IsStreamingResponse.
+type RequestTimeoutTransport struct {
+	Base    http.RoundTripper
+	Timeout time.Duration
+}
+
+// NewRequestTimeoutTransport wraps base so each request is bounded by timeout.
+// A timeout of 0 returns base unchanged.
+func NewRequestTimeoutTransport(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return base
+	}
+	return &RequestTimeoutTransport{Base: base, Timeout: timeout}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RequestTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// A timer rather than a context deadline, so it can be stopped once a stream starts
+	timeoutErr := fmt.Errorf("request timeout of %s exceeded: %w", t.Timeout, context.DeadlineExceeded)
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timer := time.AfterFunc(t.Timeout, func() { cancel(timeoutErr) })
+	stop := func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		stop()
+		if context.Cause(ctx) == timeoutErr {
+			return nil, timeoutErr
+		}
+		return nil, err
+	}
+
+	if IsStreamingResponse(resp) {
+		timer.Stop()
+	}
+	resp.Body = &timeoutBody{ReadCloser: resp.Body, ctx: ctx, timeoutErr: timeoutErr, stop: stop}
+	return resp, nil
+}
+
+// timeoutBody reports reads cut off by the request timeout as timeouts, and releases the
+// request's timer when closed
+type timeoutBody struct {
+	io.ReadCloser
+	ctx        context.Context
+	timeoutErr error
+	stop       func()
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && context.Cause(b.ctx) == b.timeoutErr {
+		return n, b.timeoutErr
+	}
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.stop()
+	return err
+}
+
+// WithTimeouts returns a client whose requests are bounded by the given connect and
+// request timeouts; see NewConnectTimeoutTransport and NewRequestTimeoutTransport. A
+// positive requestTimeout replaces the client's Timeout, which would also cut off
+// streams. The client is modified in place; a nil client is replaced by a new one. Apply
+// it before WithHeaders and WithCompression, so the connect timeout can configure the
+// client's base transport.
+func WithTimeouts(client *http.Client, connectTimeout, requestTimeout time.Duration) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	client.Transport = NewConnectTimeoutTransport(client.Transport, connectTimeout)
+	if requestTimeout > 0 {
+		client.Transport = NewRequestTimeoutTransport(client.Transport, requestTimeout)
+		client.Timeout = 0
+	}
+	return client
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeoutTransport_BoundsNonStreamingBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := WithTimeouts(&http.Client{Timeout: time.Minute}, 0, 50*time.Millisecond)
+	if client.Timeout != 0 {
+		t.Errorf("expected RequestTimeout to replace the client timeout, got %v", client.Timeout)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, err = io.ReadAll(resp.Body)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the body read to time out, got %v", err)
+	}
+}
+
+func TestRequestTimeoutTransport_ReleasesStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(150 * time.Millisecond)
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := WithTimeouts(nil, 0, 50*time.Millisecond)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected the stream to outlive the request timeout, got %v", err)
+	}
+	if string(body) != "data: [DONE]\n\n" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestRequestTimeoutTransport_BoundsStreamHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+	defer server.Close()
+
+	client := WithTimeouts(nil, 0, 50*time.Millisecond)
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected waiting for headers to time out, got %v", err)
+	}
+}
+
+func TestNewConnectTimeoutTransport(t *testing.T) {
+	base := &http.Transport{}
+	if got := NewConnectTimeoutTransport(base, 0); got != base {
+		t.Errorf("expected base transport to be returned unchanged, got %T", got)
+	}
+
+	transport, ok := NewConnectTimeoutTransport(base, 2*time.Second).(*http.Transport)
+	if !ok {
+		t.Fatalf("expected an *http.Transport")
+	}
+	if transport == base {
+		t.Error("expected the base transport to be cloned")
+	}
+	if transport.TLSHandshakeTimeout != 2*time.Second || transport.ResponseHeaderTimeout != 2*time.Second {
+		t.Errorf("expected TLS and response header timeouts of 2s, got %v and %v",
+			transport.TLSHandshakeTimeout, transport.ResponseHeaderTimeout)
+	}
+	if transport.DialContext == nil {
+		t.Error("expected a dial timeout")
+	}
+}
+
+func TestNewHTTPClient_RequestTimeoutReplacesTimeout(t *testing.T) {
+	client := NewHTTPClient(HTTPClientConfig{Timeout: 30 * time.Second, RequestTimeout: time.Minute})
+	if client.Client().Timeout != 0 {
+		t.Errorf("expected no client timeout, got %v", client.Client().Timeout)
+	}
+
+	client = NewHTTPClient(HTTPClientConfig{Timeout: 30 * time.Second})
+	if client.Client().Timeout != 30*time.Second {
+		t.Errorf("expected the 30s client timeout, got %v", client.Client().Timeout)
+	}
+}
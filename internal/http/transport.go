@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+)
+
+// HeaderTransport is an http.RoundTripper that adds fixed headers to every request.
+// It is used to apply user-configured headers (e.g. a custom User-Agent or OpenRouter's
+// HTTP-Referer and X-Title) to requests made through the raw *http.Client, which does
+// not go through HTTPClient.Do.
+type HeaderTransport struct {
+	Base      http.RoundTripper
+	Headers   map[string]string
+	UserAgent string
+}
+
+// NewHeaderTransport wraps base so every request carries the given headers and user agent.
+// Configured headers override any header of the same name set by the provider.
+// Returns base unchanged when there is nothing to add.
+func NewHeaderTransport(base http.RoundTripper, headers map[string]string, userAgent string) http.RoundTripper {
+	if len(headers) == 0 && userAgent == "" {
+		return base
+	}
+
+	// Copy so later changes to the caller's map don't race with in-flight requests
+	copied := make(map[string]string, len(headers))
+	for k, v := range headers {
+		copied[k] = v
+	}
+
+	return &HeaderTransport{
+		Base:      base,
+		Headers:   copied,
+		UserAgent: userAgent,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, value := range t.Headers {
+		req.Header.Set(key, value)
+	}
+	if t.UserAgent != "" {
+		req.Header.Set("User-Agent", t.UserAgent)
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// WithHeaders returns a client whose requests carry the given headers and user agent.
+// The client is modified in place; a nil client is replaced by a new one.
+func WithHeaders(client *http.Client, headers map[string]string, userAgent string) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	client.Transport = NewHeaderTransport(client.Transport, headers, userAgent)
+	return client
+}
+
+// WithTransportWrapper returns a client whose transport is wrapped by wrap, e.g. to run
+// middleware for every request. Apply it last: the wrapper is then outermost, seeing each
+// request as the provider built it and each response and error as the client returns
+// them. The client is modified in place; a nil client is replaced by a new one, and a nil
+// wrap leaves the client unchanged.
+func WithTransportWrapper(client *http.Client, wrap func(http.RoundTripper) http.RoundTripper) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	if wrap != nil {
+		base := client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		client.Transport = wrap(base)
+	}
+	return client
+}
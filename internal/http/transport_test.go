@@ -0,0 +1,123 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHeaderTransport_NothingToAdd(t *testing.T) {
+	base := &http.Transport{}
+	if got := NewHeaderTransport(base, nil, ""); got != base {
+		t.Errorf("expected base transport to be returned unchanged, got %T", got)
+	}
+}
+
+func TestHeaderTransport_AppliesHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	headers := map[string]string{
+		"HTTP-Referer": "https://example.com",
+		"X-Title":      "Example App",
+	}
+	client := WithHeaders(&http.Client{}, headers, "example-app/2.0")
+
+	// Mutating the caller's map after construction must not affect requests
+	headers["X-Title"] = "changed"
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("X-Title", "provider default")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if got := received.Get("User-Agent"); got != "example-app/2.0" {
+		t.Errorf("expected User-Agent 'example-app/2.0', got %q", got)
+	}
+	if got := received.Get("HTTP-Referer"); got != "https://example.com" {
+		t.Errorf("expected HTTP-Referer header, got %q", got)
+	}
+	if got := received.Get("X-Title"); got != "Example App" {
+		t.Errorf("expected configured X-Title to override provider default, got %q", got)
+	}
+
+	// The caller's request must not be modified
+	if got := req.Header.Get("X-Title"); got != "provider default" {
+		t.Errorf("expected original request to be untouched, got %q", got)
+	}
+	if got := req.Header.Get("User-Agent"); got != "" {
+		t.Errorf("expected original request to have no User-Agent, got %q", got)
+	}
+}
+
+func TestNewHTTPClient_RawClientCarriesConfiguredHeaders(t *testing.T) {
+	var userAgent, custom string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		custom = r.Header.Get("X-Custom")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{
+		Headers:   map[string]string{"X-Custom": "value"},
+		UserAgent: "custom-agent/1.0",
+	})
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Client().Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if userAgent != "custom-agent/1.0" {
+		t.Errorf("expected User-Agent 'custom-agent/1.0', got %q", userAgent)
+	}
+	if custom != "value" {
+		t.Errorf("expected X-Custom 'value', got %q", custom)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestWithTransportWrapper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var wrappedUserAgent string
+	wrap := func(base http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			wrappedUserAgent = req.Header.Get("User-Agent")
+			return base.RoundTrip(req)
+		})
+	}
+	client := NewHTTPClient(HTTPClientConfig{UserAgent: "example-app/2.0", WrapTransport: wrap}).Client()
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	// The wrapper is outermost, so it runs before the header transport applies headers
+	if wrappedUserAgent != "" {
+		t.Errorf("expected the wrapper to see the request before headers are applied, got User-Agent %q", wrappedUserAgent)
+	}
+
+	if got := WithTransportWrapper(&http.Client{}, nil).Transport; got != nil {
+		t.Errorf("expected a nil wrapper to leave the transport unset, got %T", got)
+	}
+}
@@ -427,6 +427,11 @@ func (m *APIKeyManagerImpl) ExecuteWithFailover(ctx context.Context, operation f
 	}
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
+		// Failing over to another key is a retry of the request
+		if attempt > 0 && !types.AcquireRetry(ctx) {
+			return "", nil, fmt.Errorf("%s: retry budget exhausted after %d attempts, last error: %w", m.providerName, attempt, lastErr)
+		}
+
 		// Get next available key
 		key, err := m.GetNextKey()
 		if err != nil {
@@ -440,6 +445,11 @@ func (m *APIKeyManagerImpl) ExecuteWithFailover(ctx context.Context, operation f
 		// Execute the operation
 		result, usage, err := operation(ctx, key)
 		if err != nil {
+			// The key is fine when the model itself is unavailable; with another model to
+			// fall back to, leave the error to it rather than trying the other keys
+			if types.HasModelFallback(ctx) && types.IsModelUnavailable(err) {
+				return "", nil, err
+			}
 			lastErr = err
 			m.ReportFailure(key, err)
 			continue
@@ -473,6 +483,11 @@ func (m *APIKeyManagerImpl) ExecuteWithFailoverMessage(ctx context.Context, oper
 	}
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
+		// Failing over to another key is a retry of the request
+		if attempt > 0 && !types.AcquireRetry(ctx) {
+			return types.ChatMessage{}, nil, fmt.Errorf("%s: retry budget exhausted after %d attempts, last error: %w", m.providerName, attempt, lastErr)
+		}
+
 		// Get next available key
 		key, err := m.GetNextKey()
 		if err != nil {
@@ -486,6 +501,11 @@ func (m *APIKeyManagerImpl) ExecuteWithFailoverMessage(ctx context.Context, oper
 		// Execute the operation
 		result, usage, err := operation(ctx, key)
 		if err != nil {
+			// The key is fine when the model itself is unavailable; with another model to
+			// fall back to, leave the error to it rather than trying the other keys
+			if types.HasModelFallback(ctx) && types.IsModelUnavailable(err) {
+				return types.ChatMessage{}, nil, err
+			}
 			lastErr = err
 			m.ReportFailure(key, err)
 			continue
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -185,6 +186,65 @@ func TestReportSuccessFailure(t *testing.T) {
 	})
 }
 
+func TestExecuteWithFailover_ModelUnavailable(t *testing.T) {
+	keys := []string{"key1", "key2"}
+	config := &APIKeyConfig{
+		Health: HealthConfig{
+			Enabled:          true,
+			FailureThreshold: 1,
+		},
+		Failover: FailoverConfig{
+			Enabled:     true,
+			MaxAttempts: 2,
+		},
+	}
+	modelErr := types.NewServerError(types.ProviderTypeOpenAI, 503, "model overloaded")
+
+	t.Run("WithModelFallback", func(t *testing.T) {
+		manager, _ := NewAPIKeyManager("test", keys, config)
+		calls := 0
+		_, _, err := manager.ExecuteWithFailover(types.WithModelFallback(context.Background()), func(ctx context.Context, apiKey string) (string, *types.Usage, error) {
+			calls++
+			return "", nil, modelErr
+		})
+
+		if !errors.Is(err, modelErr) {
+			t.Errorf("Expected model error to be returned unchanged, got: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected no failover to other keys, got %d calls", calls)
+		}
+
+		// The key must not be penalized for a model error left to the next model
+		for _, key := range keys {
+			if !manager.keyHealth[key].isHealthy {
+				t.Errorf("Expected key %s to remain healthy", key)
+			}
+		}
+	})
+
+	t.Run("WithoutModelFallback", func(t *testing.T) {
+		manager, _ := NewAPIKeyManager("test", keys, config)
+		calls := 0
+		_, _, err := manager.ExecuteWithFailover(context.Background(), func(ctx context.Context, apiKey string) (string, *types.Usage, error) {
+			calls++
+			return "", nil, modelErr
+		})
+
+		if !errors.Is(err, modelErr) {
+			t.Errorf("Expected the last error to be wrapped, got: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("Expected every key to be tried, got %d calls", calls)
+		}
+		for _, key := range keys {
+			if manager.keyHealth[key].isHealthy {
+				t.Errorf("Expected the failure of key %s to be reported", key)
+			}
+		}
+	})
+}
+
 func TestExecuteWithFailover(t *testing.T) {
 	t.Run("SuccessFirstTry", func(t *testing.T) {
 		keys := []string{"key1", "key2", "key3"}
@@ -259,6 +319,31 @@ func TestExecuteWithFailover(t *testing.T) {
 			t.Error("Expected error when all keys fail")
 		}
 	})
+
+	t.Run("RetryBudgetExhausted", func(t *testing.T) {
+		keys := []string{"key1", "key2", "key3"}
+		config := &APIKeyConfig{
+			Failover: FailoverConfig{
+				Enabled:     true,
+				MaxAttempts: 3,
+			},
+		}
+		manager, _ := NewAPIKeyManager("test", keys, config)
+
+		attempts := 0
+		ctx := types.WithRetryBudget(context.Background(), types.NewRetryBudget(1))
+		_, _, err := manager.ExecuteWithFailover(ctx, func(ctx context.Context, apiKey string) (string, *types.Usage, error) {
+			attempts++
+			return "", nil, errors.New("key failed")
+		})
+
+		if attempts != 2 {
+			t.Errorf("Expected the budget to allow one failover, got %d attempts", attempts)
+		}
+		if err == nil || !strings.Contains(err.Error(), "retry budget exhausted") || !strings.Contains(err.Error(), "key failed") {
+			t.Errorf("Expected the budget error wrapping the last error, got: %v", err)
+		}
+	})
 }
 
 func TestGetStatus(t *testing.T) {
@@ -219,6 +219,12 @@ func (c *CustomAuthenticator) GetAuthMethod() types.AuthMethod {
 
 // ExampleNewFileTokenStorage shows how to use token encryption
 func ExampleNewFileTokenStorage() {
+	fileTokenStorageExample("./tokens")
+}
+
+// fileTokenStorageExample runs ExampleNewFileTokenStorage with its tokens stored in
+// directory
+func fileTokenStorageExample(directory string) {
 	config := &EncryptionConfig{
 		Enabled:   false, // Disabled for testing
 		Key:       "my-encryption-key-32-bytes-long!",
@@ -231,7 +237,7 @@ func ExampleNewFileTokenStorage() {
 	}
 
 	storageConfig := &FileStorageConfig{
-		Directory:            "./test-tokens",
+		Directory:            directory,
 		FilePermissions:      "0600",
 		DirectoryPermissions: "0700",
 	}
@@ -293,5 +299,5 @@ func TestExampleNewAuthenticatorFactory(t *testing.T) {
 }
 
 func TestExampleNewFileTokenStorage(t *testing.T) {
-	ExampleNewFileTokenStorage()
+	fileTokenStorageExample(t.TempDir())
 }
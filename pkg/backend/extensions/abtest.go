@@ -0,0 +1,142 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// MetadataKeyABVariant is the response metadata key holding the assigned variant name
+const MetadataKeyABVariant = "ab_variant"
+
+// Variant is one arm of an A/B test. Weight is relative to the other variants, so
+// weights of 90 and 10 send 10% of traffic to the second variant.
+//
+// Provider and Model override the request when set. A variant with neither set is a
+// control group: its requests take the default path unchanged.
+type Variant struct {
+	Name     string
+	Weight   int
+	Provider string
+	Model    string
+}
+
+// IsControl reports whether the variant leaves requests unchanged
+func (v Variant) IsControl() bool {
+	return v.Provider == "" && v.Model == ""
+}
+
+// ABTestInterceptor routes a weighted share of traffic to experimental providers or
+// models and tags each response with the variant it was served by.
+type ABTestInterceptor struct {
+	variants    []Variant
+	totalWeight int
+	assignKey   func(req *GenerateRequest) string
+}
+
+// NewABTestInterceptor creates an interceptor that assigns each request to one of the
+// variants in proportion to their weights.
+//
+// Assignment is sticky: requests for which assignKey returns the same key (for example
+// a user ID) always get the same variant, as long as the variants are unchanged.
+// Requests with an empty key, or when assignKey is nil, are assigned at random.
+//
+// The assigned variant name is stored in the response metadata under
+// MetadataKeyABVariant for downstream analysis.
+//
+//	ab, err := NewABTestInterceptor([]Variant{
+//	    {Name: "control", Weight: 90},
+//	    {Name: "claude", Weight: 10, Provider: "anthropic", Model: "claude-sonnet-4"},
+//	}, func(req *GenerateRequest) string {
+//	    userID, _ := req.Metadata["user_id"].(string)
+//	    return userID
+//	})
+func NewABTestInterceptor(variants []Variant, assignKey func(req *GenerateRequest) string) (*ABTestInterceptor, error) {
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("at least one variant is required")
+	}
+
+	seen := make(map[string]bool, len(variants))
+	total := 0
+	for _, v := range variants {
+		if v.Name == "" {
+			return nil, fmt.Errorf("variant name is required")
+		}
+		if seen[v.Name] {
+			return nil, fmt.Errorf("duplicate variant %s", v.Name)
+		}
+		seen[v.Name] = true
+		if v.Weight < 0 {
+			return nil, fmt.Errorf("variant %s has negative weight %d", v.Name, v.Weight)
+		}
+		total += v.Weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("total variant weight must be positive")
+	}
+
+	copied := make([]Variant, len(variants))
+	copy(copied, variants)
+
+	return &ABTestInterceptor{
+		variants:    copied,
+		totalWeight: total,
+		assignKey:   assignKey,
+	}, nil
+}
+
+// Assign returns the variant for a request without executing it
+func (a *ABTestInterceptor) Assign(req *GenerateRequest) Variant {
+	var key string
+	if a.assignKey != nil {
+		key = a.assignKey(req)
+	}
+
+	var point int
+	if key == "" {
+		point = rand.Intn(a.totalWeight) //nolint:gosec // G404: math/rand is sufficient for traffic splitting
+	} else {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		point = int(h.Sum64() % uint64(a.totalWeight))
+	}
+
+	for _, v := range a.variants {
+		if point < v.Weight {
+			return v
+		}
+		point -= v.Weight
+	}
+	return a.variants[len(a.variants)-1]
+}
+
+// Intercept applies the assigned variant's overrides and tags the response
+func (a *ABTestInterceptor) Intercept(ctx context.Context, req *GenerateRequest, next ProviderFunc) (*GenerateResponse, error) {
+	variant := a.Assign(req)
+
+	if !variant.IsControl() {
+		// Copy so the caller's request is not modified
+		overridden := *req
+		if variant.Provider != "" {
+			overridden.Provider = variant.Provider
+		}
+		if variant.Model != "" {
+			overridden.Model = variant.Model
+		}
+		req = &overridden
+	}
+
+	resp, err := next(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp != nil {
+		if resp.Metadata == nil {
+			resp.Metadata = make(map[string]interface{})
+		}
+		resp.Metadata[MetadataKeyABVariant] = variant.Name
+	}
+	return resp, nil
+}
@@ -0,0 +1,127 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userKey(req *GenerateRequest) string {
+	userID, _ := req.Metadata["user_id"].(string)
+	return userID
+}
+
+func TestNewABTestInterceptor_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []Variant
+		errMsg   string
+	}{
+		{name: "no variants", variants: nil, errMsg: "at least one variant"},
+		{name: "missing name", variants: []Variant{{Weight: 1}}, errMsg: "name is required"},
+		{name: "duplicate name", variants: []Variant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}, errMsg: "duplicate variant a"},
+		{name: "negative weight", variants: []Variant{{Name: "a", Weight: -1}}, errMsg: "negative weight"},
+		{name: "zero total weight", variants: []Variant{{Name: "a"}, {Name: "b"}}, errMsg: "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ab, err := NewABTestInterceptor(tt.variants, userKey)
+			require.Error(t, err)
+			assert.Nil(t, ab)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestABTestInterceptor_StickyAssignment(t *testing.T) {
+	ab, err := NewABTestInterceptor([]Variant{
+		{Name: "control", Weight: 50},
+		{Name: "experiment", Weight: 50, Provider: "anthropic"},
+	}, userKey)
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		req := &GenerateRequest{Metadata: map[string]interface{}{"user_id": fmt.Sprintf("user-%d", i)}}
+		first := ab.Assign(req)
+		for j := 0; j < 5; j++ {
+			assert.Equal(t, first.Name, ab.Assign(req).Name, "assignment should be sticky per key")
+		}
+	}
+}
+
+func TestABTestInterceptor_WeightedDistribution(t *testing.T) {
+	ab, err := NewABTestInterceptor([]Variant{
+		{Name: "control", Weight: 80},
+		{Name: "experiment", Weight: 20, Model: "new-model"},
+		{Name: "disabled", Weight: 0, Model: "never"},
+	}, userKey)
+	require.NoError(t, err)
+
+	counts := make(map[string]int)
+	const users = 10000
+	for i := 0; i < users; i++ {
+		req := &GenerateRequest{Metadata: map[string]interface{}{"user_id": fmt.Sprintf("user-%d", i)}}
+		counts[ab.Assign(req).Name]++
+	}
+
+	assert.InDelta(t, 0.8, float64(counts["control"])/users, 0.05)
+	assert.InDelta(t, 0.2, float64(counts["experiment"])/users, 0.05)
+	assert.Zero(t, counts["disabled"])
+}
+
+func TestABTestInterceptor_Intercept(t *testing.T) {
+	t.Run("experiment variant overrides provider and model", func(t *testing.T) {
+		ab, err := NewABTestInterceptor([]Variant{
+			{Name: "experiment", Weight: 1, Provider: "anthropic", Model: "claude-sonnet-4"},
+		}, userKey)
+		require.NoError(t, err)
+
+		req := &GenerateRequest{Provider: "openai", Model: "gpt-4o", Prompt: "hi"}
+		var seen GenerateRequest
+		resp, err := ab.Intercept(context.Background(), req, func(ctx context.Context, r *GenerateRequest) (*GenerateResponse, error) {
+			seen = *r
+			return &GenerateResponse{Content: "ok"}, nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "anthropic", seen.Provider)
+		assert.Equal(t, "claude-sonnet-4", seen.Model)
+		assert.Equal(t, "openai", req.Provider, "caller's request should not be modified")
+		assert.Equal(t, "gpt-4o", req.Model)
+		assert.Equal(t, "experiment", resp.Metadata[MetadataKeyABVariant])
+	})
+
+	t.Run("control variant takes the default path", func(t *testing.T) {
+		ab, err := NewABTestInterceptor([]Variant{{Name: "control", Weight: 1}}, nil)
+		require.NoError(t, err)
+
+		req := &GenerateRequest{Provider: "openai", Model: "gpt-4o"}
+		var seen *GenerateRequest
+		resp, err := ab.Intercept(context.Background(), req, func(ctx context.Context, r *GenerateRequest) (*GenerateResponse, error) {
+			seen = r
+			return &GenerateResponse{Content: "ok", Metadata: map[string]interface{}{"existing": true}}, nil
+		})
+
+		require.NoError(t, err)
+		assert.Same(t, req, seen)
+		assert.Equal(t, "control", resp.Metadata[MetadataKeyABVariant])
+		assert.Equal(t, true, resp.Metadata["existing"])
+	})
+
+	t.Run("errors are passed through", func(t *testing.T) {
+		ab, err := NewABTestInterceptor([]Variant{{Name: "control", Weight: 1}}, userKey)
+		require.NoError(t, err)
+
+		providerErr := fmt.Errorf("provider down")
+		resp, err := ab.Intercept(context.Background(), &GenerateRequest{}, func(ctx context.Context, r *GenerateRequest) (*GenerateResponse, error) {
+			return nil, providerErr
+		})
+
+		assert.ErrorIs(t, err, providerErr)
+		assert.Nil(t, resp)
+	})
+}
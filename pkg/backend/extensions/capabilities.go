@@ -30,10 +30,20 @@ type BeforeGenerateHook interface {
 
 // AfterGenerateHook defines extensions that need to process responses after generation.
 // Implement this to modify, log, or analyze responses from providers.
+// Changes to resp are returned to the caller; returning an error fails the request.
 type AfterGenerateHook interface {
 	AfterGenerate(ctx context.Context, req *GenerateRequest, resp *GenerateResponse) error
 }
 
+// StreamChunkHook defines extensions that observe streamed responses chunk by chunk.
+// OnChunk is called for each chunk before it is sent to the client and may modify it.
+// Returning an error aborts the stream: no further chunks are read or sent, the provider
+// stream is closed (cancelling generation), and the client receives an error event.
+// Implement this for live content moderation or other real-time filtering.
+type StreamChunkHook interface {
+	OnChunk(ctx context.Context, req *GenerateRequest, chunk *types.ChatCompletionChunk) error
+}
+
 // ProviderErrorHandler defines extensions that handle provider errors.
 // Implement this to add custom error handling, logging, or retry logic.
 type ProviderErrorHandler interface {
@@ -53,7 +63,8 @@ type RouteProvider interface {
 }
 
 // DependencyDeclarer defines extensions that depend on other extensions.
-// Implement this to ensure your extension initializes after its dependencies.
+// Implement this to ensure your extension initializes, and its hooks run, after its
+// dependencies. Dependency cycles are rejected when the extension is registered.
 type DependencyDeclarer interface {
 	Dependencies() []string
 }
@@ -92,6 +103,10 @@ func GetCapabilities(ext interface{}) []string {
 		capabilities = append(capabilities, "AfterGenerateHook")
 	}
 
+	if _, ok := ext.(StreamChunkHook); ok {
+		capabilities = append(capabilities, "StreamChunkHook")
+	}
+
 	if _, ok := ext.(ProviderErrorHandler); ok {
 		capabilities = append(capabilities, "ProviderErrorHandler")
 	}
@@ -140,6 +155,9 @@ func HasCapability(ext interface{}, capability string) bool {
 	case "AfterGenerateHook":
 		_, ok := ext.(AfterGenerateHook)
 		return ok
+	case "StreamChunkHook":
+		_, ok := ext.(StreamChunkHook)
+		return ok
 	case "ProviderErrorHandler":
 		_, ok := ext.(ProviderErrorHandler)
 		return ok
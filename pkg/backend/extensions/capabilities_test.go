@@ -189,6 +189,13 @@ func TestHasCapability(t *testing.T) {
 		assert.True(t, HasCapability(ext, "PriorityProvider"))
 		assert.False(t, HasCapability(ext, "ProviderErrorHandler"))
 		assert.False(t, HasCapability(ext, "RouteProvider"))
+		assert.False(t, HasCapability(ext, "StreamChunkHook"))
+	})
+
+	t.Run("BaseExtension provides StreamChunkHook", func(t *testing.T) {
+		ext := &struct{ BaseExtension }{}
+		assert.True(t, HasCapability(ext, "StreamChunkHook"))
+		assert.Contains(t, GetCapabilities(ext), "StreamChunkHook")
 	})
 
 	t.Run("hook only extension has hook capabilities", func(t *testing.T) {
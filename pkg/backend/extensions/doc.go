@@ -24,6 +24,10 @@
 // or the actual provider. An interceptor can skip calling next() to short-circuit
 // execution (e.g., return a cached response).
 //
+// To check the configured order, Describe returns the interceptors' names and String
+// shows the whole chain, e.g. "logger -> timeout -> cache -> provider". Names come from
+// an interceptor's Name() method, or its Go type; AddNamed overrides them.
+//
 // The InterceptorRegistry provides a way to manage named interceptors globally:
 //
 //	registry := NewInterceptorRegistry()
@@ -36,6 +40,19 @@
 //   - CachingInterceptor: Caches responses based on request prompts
 //   - MetricsInterceptor: Tracks call counts and durations
 //
+// Built-in interceptors:
+//   - ABTestInterceptor: Routes a weighted, sticky share of traffic to experimental
+//     providers or models and records the variant in response metadata
+//   - ShadowInterceptor: Mirrors a sample of requests to a candidate provider in the
+//     background and records latency, token and output differences
+//   - QuotaInterceptor: Enforces per-tenant token quotas, reserving an estimate before
+//     each call and settling it against the actual usage afterwards
+//   - ModerationInterceptor: Runs the last user message through a Moderator, such as
+//     OpenAI's moderations API, and blocks or flags disallowed content before the
+//     provider is called
+//   - ErrorCaptureInterceptor: Records every failed call, with its masked request and
+//     rich error context, to a pluggable ErrorSink in the background
+//
 // # Per-Request Extension Configuration
 //
 // Extensions can be configured or disabled on a per-request basis using the
@@ -97,6 +114,53 @@
 // An extension is disabled only when:
 //   - Extension config has "enabled": false
 //
+// # Execution Order
+//
+// The registry runs hooks in priority order, lowest first (see PrioritySecurity,
+// PriorityCache, PriorityTransform and PriorityLogging), with extensions of equal
+// priority in registration order. Dependencies declared through Dependencies()
+// override priority: an extension always runs after the extensions it depends on,
+// so a cache extension that needs the caller identity can depend on "auth".
+// Register returns an error for an extension that would create a dependency cycle.
+// Initialize uses the same order.
+//
+// Security extensions (authentication, authorization, rate limiting) should use
+// PrioritySecurity and declare no dependencies on lower-priority extensions, so they
+// run before everything else regardless of the order in which they were registered.
+//
+// # Response Hooks
+//
+// AfterGenerate runs once generation has finished and may inspect or mutate the
+// response before it is returned, for example to redact PII or append a disclaimer.
+// Hooks run in priority order (lowest first) and each sees the changes made by the
+// previous one. Returning an error converts the response into a failure: the caller
+// receives an EXTENSION_ERROR instead of the generated content.
+//
+//	func (e *RedactionExtension) AfterGenerate(ctx context.Context, req *GenerateRequest, resp *GenerateResponse) error {
+//	    resp.Content = e.redact(resp.Content)
+//	    return nil
+//	}
+//
+// For streaming requests the content has already been sent to the client when
+// AfterGenerate runs, so changes to the response are not delivered; an error is
+// reported as a final SSE error event.
+//
+// # Streaming Hooks
+//
+// Extensions implementing StreamChunkHook see each streamed chunk before it is sent
+// to the client. OnChunk may modify the chunk in place; returning an error aborts the
+// stream mid-generation: no further chunks are sent, the provider stream is closed,
+// and the client receives an EXTENSION_ERROR event instead of the completion event.
+//
+//	func (e *ModerationExtension) OnChunk(ctx context.Context, req *GenerateRequest, chunk *types.ChatCompletionChunk) error {
+//	    if e.flagged(chunk.Content) {
+//	        return errors.New("content blocked by moderation policy")
+//	    }
+//	    return nil
+//	}
+//
+// BaseExtension provides a no-op OnChunk.
+//
 // # Security Considerations
 //
 // Security-critical extensions (e.g., authentication, authorization, rate limiting)
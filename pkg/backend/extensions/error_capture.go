@@ -0,0 +1,240 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	commonerrors "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/errors"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ErrorRecord is a failed provider call, as captured by an ErrorCaptureInterceptor.
+// Credentials are masked in every field.
+type ErrorRecord struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Provider  string        `json:"provider,omitempty"`
+	Model     string        `json:"model,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error"`
+
+	Request  *GenerateRequest  `json:"request"`
+	Response *GenerateResponse `json:"response,omitempty"` // Set when the call returned a partial response
+
+	// Context is the error's rich context: that of a *commonerrors.RichError in the
+	// error chain, or else the one ErrorContextMiddleware stored in the request's
+	// context, with its masked HTTP request and response snapshots
+	Context *commonerrors.ErrorContext `json:"context,omitempty"`
+}
+
+// ErrorSink stores captured errors. Implementations must be safe for concurrent use.
+type ErrorSink interface {
+	Capture(ctx context.Context, record *ErrorRecord) error
+}
+
+// WriterErrorSink is an ErrorSink writing each record as a line of JSON
+type WriterErrorSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewWriterErrorSink creates a sink writing JSON lines to w
+func NewWriterErrorSink(w io.Writer) *WriterErrorSink {
+	return &WriterErrorSink{writer: w}
+}
+
+// NewStdoutErrorSink creates a sink writing JSON lines to standard output
+func NewStdoutErrorSink() *WriterErrorSink {
+	return NewWriterErrorSink(os.Stdout)
+}
+
+// NewFileErrorSink creates a sink appending JSON lines to the file at path, creating
+// it if needed. Close the sink to close the file.
+func NewFileErrorSink(path string) (*WriterErrorSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // G304: path is chosen by the application
+	if err != nil {
+		return nil, fmt.Errorf("failed to open error sink file: %w", err)
+	}
+	return NewWriterErrorSink(file), nil
+}
+
+// Capture implements ErrorSink
+func (s *WriterErrorSink) Capture(_ context.Context, record *ErrorRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal error record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying writer if it is an io.Closer other than standard output
+func (s *WriterErrorSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if closer, ok := s.writer.(io.Closer); ok && s.writer != os.Stdout {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ErrorCaptureInterceptor records every failed provider call to an ErrorSink for later
+// analysis. Records are written asynchronously; capturing never delays the call or
+// changes the error it returns. Errors raised while reading a stream, after the call
+// has returned, are not seen by interceptors and so are not captured.
+type ErrorCaptureInterceptor struct {
+	sink   ErrorSink
+	masker commonerrors.CredentialMasker
+
+	mu          sync.Mutex
+	onSinkError func(error)
+	wg          sync.WaitGroup
+}
+
+// NewErrorCaptureInterceptor creates an interceptor capturing failed calls to sink, or
+// to standard output when sink is nil. Prompts, messages, metadata and response content
+// are masked with the default credential masker before they reach the sink.
+func NewErrorCaptureInterceptor(sink ErrorSink) *ErrorCaptureInterceptor {
+	if sink == nil {
+		sink = NewStdoutErrorSink()
+	}
+	return &ErrorCaptureInterceptor{
+		sink:   sink,
+		masker: commonerrors.DefaultCredentialMasker(),
+	}
+}
+
+// WithMasker sets the masker applied to captured requests and responses
+func (c *ErrorCaptureInterceptor) WithMasker(masker commonerrors.CredentialMasker) *ErrorCaptureInterceptor {
+	c.masker = masker
+	return c
+}
+
+// OnSinkError registers a function called with errors from the sink, which are
+// otherwise dropped. It is called from the capturing goroutine.
+func (c *ErrorCaptureInterceptor) OnSinkError(fn func(error)) *ErrorCaptureInterceptor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onSinkError = fn
+	return c
+}
+
+// Wait blocks until all in-flight captures have been written
+func (c *ErrorCaptureInterceptor) Wait() {
+	c.wg.Wait()
+}
+
+// Intercept calls next and captures its error, if any
+func (c *ErrorCaptureInterceptor) Intercept(ctx context.Context, req *GenerateRequest, next ProviderFunc) (*GenerateResponse, error) {
+	start := time.Now()
+	resp, err := next(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	// Build the record now, before the caller can modify the request or response
+	record := c.newRecord(ctx, req, resp, err, time.Since(start))
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if sinkErr := c.sink.Capture(context.WithoutCancel(ctx), record); sinkErr != nil {
+			c.mu.Lock()
+			onSinkError := c.onSinkError
+			c.mu.Unlock()
+			if onSinkError != nil {
+				onSinkError(sinkErr)
+			}
+		}
+	}()
+
+	return resp, err
+}
+
+// newRecord builds the masked record of a failed call
+func (c *ErrorCaptureInterceptor) newRecord(ctx context.Context, req *GenerateRequest, resp *GenerateResponse, err error, duration time.Duration) *ErrorRecord {
+	record := &ErrorRecord{
+		Timestamp: time.Now(),
+		Duration:  duration,
+		Error:     c.masker.MaskString(err.Error()),
+		Request:   c.maskRequest(req),
+		Response:  c.maskResponse(resp),
+	}
+	if req != nil {
+		record.Provider = req.Provider
+		record.Model = req.Model
+	}
+
+	var errCtx *commonerrors.ErrorContext
+	var richErr *commonerrors.RichError
+	if errors.As(err, &richErr) {
+		errCtx = richErr.Context()
+	} else {
+		errCtx = commonerrors.GetErrorContext(ctx)
+	}
+	if errCtx != nil {
+		// Copy the context, which the middleware may still be updating
+		snapshot := *errCtx
+		record.Context = &snapshot
+		if record.Provider == "" {
+			record.Provider = string(snapshot.Provider)
+		}
+		if record.Model == "" {
+			record.Model = snapshot.Model
+		}
+	}
+	return record
+}
+
+func (c *ErrorCaptureInterceptor) maskRequest(req *GenerateRequest) *GenerateRequest {
+	if req == nil {
+		return nil
+	}
+	masked := *req
+	masked.Context = nil
+	masked.Prompt = c.masker.MaskString(req.Prompt)
+	masked.Metadata = c.maskMetadata(req.Metadata)
+	if req.Messages != nil {
+		masked.Messages = make([]types.ChatMessage, len(req.Messages))
+		for i, message := range req.Messages {
+			masked.Messages[i] = types.ChatMessage{
+				Role:    message.Role,
+				Content: c.masker.MaskString(message.GetTextContent()),
+			}
+		}
+	}
+	return &masked
+}
+
+func (c *ErrorCaptureInterceptor) maskResponse(resp *GenerateResponse) *GenerateResponse {
+	if resp == nil {
+		return nil
+	}
+	masked := *resp
+	masked.Content = c.masker.MaskString(resp.Content)
+	masked.Metadata = c.maskMetadata(resp.Metadata)
+	return &masked
+}
+
+// maskMetadata copies metadata, masking its string values
+func (c *ErrorCaptureInterceptor) maskMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		if s, ok := value.(string); ok {
+			value = c.masker.MaskString(s)
+		}
+		masked[key] = value
+	}
+	return masked
+}
@@ -0,0 +1,158 @@
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	commonerrors "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/errors"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryErrorSink struct {
+	mu      sync.Mutex
+	records []*ErrorRecord
+	err     error
+}
+
+func (s *memoryErrorSink) Capture(_ context.Context, record *ErrorRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return s.err
+}
+
+func failingProvider(err error) ProviderFunc {
+	return func(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+		return nil, err
+	}
+}
+
+func TestErrorCaptureInterceptor_CapturesFailures(t *testing.T) {
+	sink := &memoryErrorSink{}
+	capture := NewErrorCaptureInterceptor(sink)
+
+	providerErr := types.NewProviderError(types.ProviderTypeOpenAI, types.ErrCodeRateLimit, "rate limited")
+	req := &GenerateRequest{
+		Provider: "openai",
+		Model:    "gpt-4o",
+		Prompt:   `my api_key=sk-secret123 please`,
+		Messages: []types.ChatMessage{{Role: "user", Content: "Bearer abc.def"}},
+		Metadata: map[string]interface{}{"password": "password: hunter2", "tokens": 3},
+	}
+
+	_, err := capture.Intercept(context.Background(), req, failingProvider(providerErr))
+	assert.Same(t, providerErr, err, "the returned error must be unchanged")
+	capture.Wait()
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "openai", record.Provider)
+	assert.Equal(t, "gpt-4o", record.Model)
+	assert.Contains(t, record.Error, "rate limited")
+	assert.NotContains(t, record.Request.Prompt, "sk-secret123")
+	assert.Equal(t, "Bearer ***MASKED***", record.Request.Messages[0].Content)
+	assert.NotContains(t, record.Request.Metadata["password"], "hunter2")
+	assert.Equal(t, 3, record.Request.Metadata["tokens"])
+	assert.Contains(t, req.Prompt, "sk-secret123", "the caller's request must not be modified")
+}
+
+func TestErrorCaptureInterceptor_IgnoresSuccess(t *testing.T) {
+	sink := &memoryErrorSink{}
+	capture := NewErrorCaptureInterceptor(sink)
+
+	calls := 0
+	_, err := capture.Intercept(context.Background(), &GenerateRequest{Prompt: "hi"}, countingProvider(&calls))
+	require.NoError(t, err)
+	capture.Wait()
+	assert.Empty(t, sink.records)
+}
+
+func TestErrorCaptureInterceptor_RichErrorContext(t *testing.T) {
+	sink := &memoryErrorSink{}
+	capture := NewErrorCaptureInterceptor(sink)
+
+	richErr := commonerrors.NewRichError(errors.New("upstream failed")).
+		WithProvider(types.ProviderTypeAnthropic).
+		WithModel("claude-sonnet-4").
+		WithRequestID("req-1")
+
+	_, err := capture.Intercept(context.Background(), &GenerateRequest{Prompt: "hi"}, failingProvider(richErr))
+	assert.ErrorIs(t, err, richErr)
+	capture.Wait()
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	require.NotNil(t, record.Context)
+	assert.Equal(t, "req-1", record.Context.RequestID)
+	assert.Equal(t, "anthropic", record.Provider, "provider is taken from the error context")
+	assert.Equal(t, "claude-sonnet-4", record.Model)
+}
+
+func TestErrorCaptureInterceptor_MiddlewareContext(t *testing.T) {
+	sink := &memoryErrorSink{}
+	capture := NewErrorCaptureInterceptor(sink)
+
+	errCtx := commonerrors.NewErrorContext().WithCorrelationID("corr-1")
+	ctx := context.WithValue(context.Background(), commonerrors.ContextKeyErrorContext, errCtx)
+
+	_, _ = capture.Intercept(ctx, &GenerateRequest{Prompt: "hi"}, failingProvider(errors.New("boom")))
+	capture.Wait()
+
+	require.Len(t, sink.records, 1)
+	require.NotNil(t, sink.records[0].Context)
+	assert.Equal(t, "corr-1", sink.records[0].Context.CorrelationID)
+}
+
+func TestErrorCaptureInterceptor_SinkError(t *testing.T) {
+	sinkErr := errors.New("disk full")
+	capture := NewErrorCaptureInterceptor(&memoryErrorSink{err: sinkErr})
+
+	var reported error
+	capture.OnSinkError(func(err error) { reported = err })
+
+	providerErr := errors.New("boom")
+	_, err := capture.Intercept(context.Background(), &GenerateRequest{}, failingProvider(providerErr))
+	capture.Wait()
+
+	assert.Same(t, providerErr, err)
+	assert.Same(t, sinkErr, reported)
+}
+
+func TestWriterErrorSink(t *testing.T) {
+	var buf bytes.Buffer
+	capture := NewErrorCaptureInterceptor(NewWriterErrorSink(&buf))
+
+	for i := 0; i < 2; i++ {
+		_, _ = capture.Intercept(context.Background(), &GenerateRequest{Provider: "openai"}, failingProvider(errors.New("boom")))
+	}
+	capture.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "boom", record["error"])
+	assert.Equal(t, "openai", record["provider"])
+}
+
+func TestFileErrorSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	sink, err := NewFileErrorSink(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Capture(context.Background(), &ErrorRecord{Error: "boom"}))
+	require.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"error":"boom"`)
+}
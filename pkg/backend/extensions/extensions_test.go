@@ -892,3 +892,89 @@ func TestRegistry_PriorityOrdering(t *testing.T) {
 		assert.Equal(t, 900, PriorityLogging)
 	})
 }
+
+// TestRegistry_DependencyOrdering tests that dependencies constrain execution order
+func TestRegistry_DependencyOrdering(t *testing.T) {
+	names := func(exts []Extension) []string {
+		result := make([]string, 0, len(exts))
+		for _, ext := range exts {
+			result = append(result, ext.Name())
+		}
+		return result
+	}
+
+	t.Run("dependency runs before dependent regardless of priority", func(t *testing.T) {
+		reg := NewRegistry()
+
+		// cache has the higher priority but needs the identity established by auth
+		require.NoError(t, reg.Register(&mockExtension{name: "cache", priority: PriorityCache, deps: []string{"auth"}}))
+		require.NoError(t, reg.Register(&mockExtension{name: "logging", priority: PriorityLogging}))
+		require.NoError(t, reg.Register(&mockExtension{name: "auth", priority: PriorityTransform}))
+
+		assert.Equal(t, []string{"auth", "cache", "logging"}, names(reg.List()))
+	})
+
+	t.Run("security extension runs first regardless of registration order", func(t *testing.T) {
+		reg := NewRegistry()
+
+		require.NoError(t, reg.Register(&mockExtension{name: "logging", priority: PriorityLogging}))
+		require.NoError(t, reg.Register(&mockExtension{name: "cache", priority: PriorityCache, deps: []string{"logging"}}))
+		require.NoError(t, reg.Register(&mockExtension{name: "auth", priority: PrioritySecurity}))
+
+		assert.Equal(t, []string{"auth", "logging", "cache"}, names(reg.List()))
+	})
+
+	t.Run("unregistered dependencies are ignored", func(t *testing.T) {
+		reg := NewRegistry()
+
+		require.NoError(t, reg.Register(&mockExtension{name: "ext1", deps: []string{"missing"}}))
+		require.NoError(t, reg.Register(&mockExtension{name: "ext2"}))
+
+		assert.Equal(t, []string{"ext1", "ext2"}, names(reg.List()))
+	})
+
+	t.Run("register rejects dependency cycle", func(t *testing.T) {
+		reg := NewRegistry()
+
+		require.NoError(t, reg.Register(&mockExtension{name: "a", deps: []string{"b"}}))
+		require.NoError(t, reg.Register(&mockExtension{name: "b", deps: []string{"c"}}))
+
+		err := reg.Register(&mockExtension{name: "c", deps: []string{"a"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dependency cycle detected: a -> b -> c -> a")
+
+		_, ok := reg.Get("c")
+		assert.False(t, ok, "extension closing the cycle should not be registered")
+		assert.Equal(t, []string{"b", "a"}, names(reg.List()))
+	})
+
+	t.Run("register rejects self dependency", func(t *testing.T) {
+		reg := NewRegistry()
+
+		err := reg.Register(&mockExtension{name: "a", deps: []string{"a"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "a -> a")
+	})
+
+	t.Run("hooks run in dependency order", func(t *testing.T) {
+		reg := NewRegistry()
+
+		var calls []string
+		require.NoError(t, reg.Register(&orderedHookExtension{mockExtension: mockExtension{name: "cache", priority: PriorityCache, deps: []string{"auth"}}, calls: &calls}))
+		require.NoError(t, reg.Register(&orderedHookExtension{mockExtension: mockExtension{name: "auth", priority: PriorityTransform}, calls: &calls}))
+
+		require.NoError(t, reg.(*registry).CallBeforeGenerate(context.Background(), &GenerateRequest{}))
+		assert.Equal(t, []string{"auth", "cache"}, calls)
+	})
+}
+
+// orderedHookExtension records the order in which BeforeGenerate is called
+type orderedHookExtension struct {
+	mockExtension
+	calls *[]string
+}
+
+func (e *orderedHookExtension) BeforeGenerate(ctx context.Context, req *GenerateRequest) error {
+	*e.calls = append(*e.calls, e.name)
+	return nil
+}
@@ -3,6 +3,7 @@ package extensions
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -27,6 +28,7 @@ type ProviderInterceptor interface {
 type InterceptorChain struct {
 	mu           sync.RWMutex
 	interceptors []ProviderInterceptor
+	names        []string // Display names, parallel to interceptors
 }
 
 // NewInterceptorChain creates a new empty interceptor chain.
@@ -39,9 +41,39 @@ func NewInterceptorChain() *InterceptorChain {
 // Add appends an interceptor to the chain.
 // Interceptors are executed in the order they are added.
 func (c *InterceptorChain) Add(interceptor ProviderInterceptor) {
+	c.AddNamed(InterceptorName(interceptor), interceptor)
+}
+
+// AddNamed appends an interceptor to the chain under name, which Describe and String
+// show instead of the interceptor's own name.
+func (c *InterceptorChain) AddNamed(name string, interceptor ProviderInterceptor) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.interceptors = append(c.interceptors, interceptor)
+	c.names = append(c.names, name)
+}
+
+// Describe returns the names of the chain's interceptors in execution order.
+func (c *InterceptorChain) Describe() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make([]string, len(c.names))
+	copy(result, c.names)
+	return result
+}
+
+// String shows the chain's execution order, e.g. "logger -> cache -> provider".
+func (c *InterceptorChain) String() string {
+	return strings.Join(append(c.Describe(), "provider"), " -> ")
+}
+
+// InterceptorName returns the display name of an interceptor: the result of its
+// Name() method if it has one, otherwise its Go type.
+func InterceptorName(interceptor ProviderInterceptor) string {
+	if named, ok := interceptor.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", interceptor)
 }
 
 // Execute runs the interceptor chain followed by the provider function.
@@ -199,6 +199,28 @@ func TestInterceptorChain_Add(t *testing.T) {
 	})
 }
 
+// namedInterceptor passes calls through and names itself
+type namedInterceptor struct{ name string }
+
+func (n *namedInterceptor) Name() string { return n.name }
+
+func (n *namedInterceptor) Intercept(ctx context.Context, req *GenerateRequest, next ProviderFunc) (*GenerateResponse, error) {
+	return next(ctx, req)
+}
+
+func TestInterceptorChain_Describe(t *testing.T) {
+	chain := NewInterceptorChain()
+	assert.Empty(t, chain.Describe())
+	assert.Equal(t, "provider", chain.String())
+
+	chain.Add(&namedInterceptor{name: "cache"})
+	chain.Add(NewLoggingInterceptor())
+	chain.AddNamed("timeout", NewTimeoutInterceptor(time.Second))
+
+	assert.Equal(t, []string{"cache", "*extensions.LoggingInterceptor", "timeout"}, chain.Describe())
+	assert.Equal(t, "cache -> *extensions.LoggingInterceptor -> timeout -> provider", chain.String())
+}
+
 func TestInterceptorChain_Execute(t *testing.T) {
 	t.Run("execute with no interceptors", func(t *testing.T) {
 		chain := NewInterceptorChain()
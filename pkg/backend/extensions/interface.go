@@ -29,6 +29,7 @@ type GenerateRequest struct {
 	Provider    string                 `json:"provider,omitempty"`
 	Model       string                 `json:"model,omitempty"`
 	Prompt      string                 `json:"prompt"`
+	Messages    []types.ChatMessage    `json:"messages,omitempty"`
 	MaxTokens   int                    `json:"max_tokens,omitempty"`
 	Temperature float64                `json:"temperature,omitempty"`
 	Stream      bool                   `json:"stream,omitempty"`
@@ -41,6 +42,7 @@ type GenerateResponse struct {
 	Content  string                 `json:"content"`
 	Model    string                 `json:"model"`
 	Provider string                 `json:"provider"`
+	Usage    *types.Usage           `json:"usage,omitempty"` // Nil when unknown, e.g. until a stream ends
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -117,6 +119,9 @@ func (b *BaseExtension) BeforeGenerate(ctx context.Context, req *GenerateRequest
 func (b *BaseExtension) AfterGenerate(ctx context.Context, req *GenerateRequest, resp *GenerateResponse) error {
 	return nil
 }
+func (b *BaseExtension) OnChunk(ctx context.Context, req *GenerateRequest, chunk *types.ChatCompletionChunk) error {
+	return nil
+}
 func (b *BaseExtension) OnProviderError(ctx context.Context, provider types.Provider, err error) error {
 	return nil
 }
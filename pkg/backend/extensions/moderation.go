@@ -0,0 +1,293 @@
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// MetadataKeyModerationCategories is the request and response metadata key holding the
+// categories a ModerationFlag interceptor flagged, when any were
+const MetadataKeyModerationCategories = "moderation_categories"
+
+// ModerationResult is a moderator's verdict on a piece of content
+type ModerationResult struct {
+	// Flagged is the moderator's own overall verdict
+	Flagged bool
+
+	// Categories maps each category (e.g. "hate", "violence") to whether the moderator
+	// flagged it, and CategoryScores to its confidence from 0.0 to 1.0
+	Categories     map[string]bool
+	CategoryScores map[string]float64
+}
+
+// Moderator checks content against a content policy. Implementations must be safe for
+// concurrent use.
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// ModerationAction is what a ModerationInterceptor does with disallowed content
+type ModerationAction string
+
+const (
+	// ModerationBlock rejects the request with a *ContentPolicyError
+	ModerationBlock ModerationAction = "block"
+
+	// ModerationFlag sends the request on, recording the flagged categories under
+	// MetadataKeyModerationCategories in the request and response metadata
+	ModerationFlag ModerationAction = "flag"
+)
+
+// ContentPolicyError is returned when a ModerationBlock interceptor rejects a request
+type ContentPolicyError struct {
+	Categories []string           // Flagged categories, sorted
+	Scores     map[string]float64 // Scores of the flagged categories
+}
+
+func (e *ContentPolicyError) Error() string {
+	if len(e.Categories) == 0 {
+		return "request blocked by content policy"
+	}
+	return "request blocked by content policy: " + strings.Join(e.Categories, ", ")
+}
+
+// ModerationInterceptor runs the last user message of each request through a Moderator
+// before the provider is called, so disallowed content never reaches an expensive
+// provider
+type ModerationInterceptor struct {
+	moderator  Moderator
+	action     ModerationAction
+	categories map[string]bool
+	thresholds map[string]float64
+}
+
+// NewModerationInterceptor creates an interceptor that moderates requests with
+// moderator and blocks or flags disallowed content according to action.
+//
+// By default content is disallowed when the moderator flags it. WithCategories and
+// WithThresholds narrow this to chosen categories. An error from the moderator fails
+// the request, since its content could not be checked.
+//
+//	moderation := NewModerationInterceptor(NewOpenAIModerator(apiKey), ModerationBlock).
+//	    WithThresholds(map[string]float64{"violence": 0.8})
+func NewModerationInterceptor(moderator Moderator, action ModerationAction) *ModerationInterceptor {
+	return &ModerationInterceptor{moderator: moderator, action: action}
+}
+
+// WithCategories disallows content the moderator flags in any of categories. Other
+// categories are then ignored unless given a threshold.
+func (m *ModerationInterceptor) WithCategories(categories ...string) *ModerationInterceptor {
+	if m.categories == nil {
+		m.categories = make(map[string]bool)
+	}
+	for _, category := range categories {
+		m.categories[category] = true
+	}
+	return m
+}
+
+// WithThresholds disallows content scoring at or above a category's threshold,
+// whether or not the moderator flagged it. Other categories are then ignored unless
+// given to WithCategories.
+func (m *ModerationInterceptor) WithThresholds(thresholds map[string]float64) *ModerationInterceptor {
+	if m.thresholds == nil {
+		m.thresholds = make(map[string]float64)
+	}
+	for category, threshold := range thresholds {
+		m.thresholds[category] = threshold
+	}
+	return m
+}
+
+// Intercept moderates the request and, unless it is blocked, calls next
+func (m *ModerationInterceptor) Intercept(ctx context.Context, req *GenerateRequest, next ProviderFunc) (*GenerateResponse, error) {
+	text := lastUserMessage(req)
+	if strings.TrimSpace(text) == "" {
+		return next(ctx, req)
+	}
+
+	result, err := m.moderator.Moderate(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("moderation check failed: %w", err)
+	}
+
+	categories := m.violations(result)
+	if len(categories) == 0 {
+		return next(ctx, req)
+	}
+
+	if m.action != ModerationFlag {
+		scores := make(map[string]float64, len(categories))
+		for _, category := range categories {
+			scores[category] = result.CategoryScores[category]
+		}
+		return nil, &ContentPolicyError{Categories: categories, Scores: scores}
+	}
+
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]interface{})
+	}
+	req.Metadata[MetadataKeyModerationCategories] = categories
+
+	resp, err := next(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]interface{})
+	}
+	resp.Metadata[MetadataKeyModerationCategories] = categories
+	return resp, nil
+}
+
+// violations returns the sorted categories of result that are disallowed
+func (m *ModerationInterceptor) violations(result *ModerationResult) []string {
+	if result == nil {
+		return nil
+	}
+
+	var categories []string
+	if len(m.categories) == 0 && len(m.thresholds) == 0 {
+		for category, flagged := range result.Categories {
+			if flagged {
+				categories = append(categories, category)
+			}
+		}
+		if len(categories) == 0 && result.Flagged {
+			categories = append(categories, "flagged")
+		}
+	} else {
+		seen := make(map[string]bool)
+		for category := range m.categories {
+			if result.Categories[category] {
+				seen[category] = true
+			}
+		}
+		for category, threshold := range m.thresholds {
+			if score, ok := result.CategoryScores[category]; ok && score >= threshold {
+				seen[category] = true
+			}
+		}
+		for category := range seen {
+			categories = append(categories, category)
+		}
+	}
+
+	sort.Strings(categories)
+	return categories
+}
+
+// lastUserMessage returns the text of the request's last user message, or its prompt
+// when it has no messages
+func lastUserMessage(req *GenerateRequest) string {
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			return req.Messages[i].GetTextContent()
+		}
+	}
+	return req.Prompt
+}
+
+// defaultModerationBaseURL is the OpenAI API the OpenAIModerator calls by default
+const defaultModerationBaseURL = "https://api.openai.com/v1"
+
+// OpenAIModerator is a Moderator using OpenAI's moderations API
+type OpenAIModerator struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOpenAIModerator creates a moderator calling OpenAI's moderations API with apiKey,
+// using the API's default moderation model
+func NewOpenAIModerator(apiKey string) *OpenAIModerator {
+	return &OpenAIModerator{
+		apiKey:     apiKey,
+		baseURL:    defaultModerationBaseURL,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// WithBaseURL sets the API endpoint, for proxies and OpenAI-compatible servers
+func (o *OpenAIModerator) WithBaseURL(baseURL string) *OpenAIModerator {
+	o.baseURL = strings.TrimRight(baseURL, "/")
+	return o
+}
+
+// WithModel sets the moderation model, e.g. "omni-moderation-latest"
+func (o *OpenAIModerator) WithModel(model string) *OpenAIModerator {
+	o.model = model
+	return o
+}
+
+// WithHTTPClient sets the client used for API calls
+func (o *OpenAIModerator) WithHTTPClient(client *http.Client) *OpenAIModerator {
+	o.httpClient = client
+	return o
+}
+
+type openAIModerationRequest struct {
+	Model string `json:"model,omitempty"`
+	Input string `json:"input"`
+}
+
+type openAIModerationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+// Moderate implements Moderator
+func (o *OpenAIModerator) Moderate(ctx context.Context, text string) (*ModerationResult, error) {
+	body, err := json.Marshal(openAIModerationRequest{Model: o.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal moderation request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create moderation request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	httpResp, err := o.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("moderation request failed: %w", err)
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moderation response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation API returned status %d: %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var parsed openAIModerationResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation response: %w", err)
+	}
+	if len(parsed.Results) == 0 {
+		return nil, fmt.Errorf("moderation response has no results")
+	}
+
+	result := parsed.Results[0]
+	return &ModerationResult{
+		Flagged:        result.Flagged,
+		Categories:     result.Categories,
+		CategoryScores: result.CategoryScores,
+	}, nil
+}
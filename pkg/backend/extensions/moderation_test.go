@@ -0,0 +1,193 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubModerator struct {
+	result *ModerationResult
+	err    error
+	texts  []string
+}
+
+func (m *stubModerator) Moderate(_ context.Context, text string) (*ModerationResult, error) {
+	m.texts = append(m.texts, text)
+	return m.result, m.err
+}
+
+func violentResult() *ModerationResult {
+	return &ModerationResult{
+		Flagged:        true,
+		Categories:     map[string]bool{"violence": true, "hate": false},
+		CategoryScores: map[string]float64{"violence": 0.91, "hate": 0.4},
+	}
+}
+
+func countingProvider(calls *int) ProviderFunc {
+	return func(_ context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		*calls++
+		return &GenerateResponse{Content: "ok", Provider: "openai"}, nil
+	}
+}
+
+func TestModerationInterceptor_BlocksFlaggedContent(t *testing.T) {
+	moderator := &stubModerator{result: violentResult()}
+	interceptor := NewModerationInterceptor(moderator, ModerationBlock)
+
+	calls := 0
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "something violent"}, countingProvider(&calls))
+
+	var policyErr *ContentPolicyError
+	require.True(t, errors.As(err, &policyErr))
+	assert.Equal(t, []string{"violence"}, policyErr.Categories)
+	assert.Equal(t, 0.91, policyErr.Scores["violence"])
+	assert.Equal(t, 0, calls, "blocked request must not reach the provider")
+}
+
+func TestModerationInterceptor_AllowsCleanContent(t *testing.T) {
+	moderator := &stubModerator{result: &ModerationResult{}}
+	interceptor := NewModerationInterceptor(moderator, ModerationBlock)
+
+	calls := 0
+	resp, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "hello"}, countingProvider(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Content)
+	assert.Equal(t, 1, calls)
+	assert.NotContains(t, resp.Metadata, MetadataKeyModerationCategories)
+}
+
+func TestModerationInterceptor_ChecksLastUserMessage(t *testing.T) {
+	moderator := &stubModerator{result: &ModerationResult{}}
+	interceptor := NewModerationInterceptor(moderator, ModerationBlock)
+
+	req := &GenerateRequest{
+		Prompt: "ignored",
+		Messages: []types.ChatMessage{
+			{Role: "user", Content: "first question"},
+			{Role: "assistant", Content: "answer"},
+			{Role: "user", Content: "follow-up"},
+			{Role: "assistant", Content: "prefill"},
+		},
+	}
+	calls := 0
+	_, err := interceptor.Intercept(context.Background(), req, countingProvider(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"follow-up"}, moderator.texts)
+}
+
+func TestModerationInterceptor_SkipsEmptyContent(t *testing.T) {
+	moderator := &stubModerator{err: errors.New("should not be called")}
+	interceptor := NewModerationInterceptor(moderator, ModerationBlock)
+
+	calls := 0
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{}, countingProvider(&calls))
+	require.NoError(t, err)
+	assert.Empty(t, moderator.texts)
+	assert.Equal(t, 1, calls)
+}
+
+func TestModerationInterceptor_FlagAnnotates(t *testing.T) {
+	moderator := &stubModerator{result: violentResult()}
+	interceptor := NewModerationInterceptor(moderator, ModerationFlag)
+
+	var seen interface{}
+	req := &GenerateRequest{Prompt: "something violent"}
+	resp, err := interceptor.Intercept(context.Background(), req, func(_ context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		seen = req.Metadata[MetadataKeyModerationCategories]
+		return &GenerateResponse{Content: "ok"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"violence"}, seen)
+	assert.Equal(t, []string{"violence"}, resp.Metadata[MetadataKeyModerationCategories])
+}
+
+func TestModerationInterceptor_Categories(t *testing.T) {
+	moderator := &stubModerator{result: violentResult()}
+
+	calls := 0
+	interceptor := NewModerationInterceptor(moderator, ModerationBlock).WithCategories("hate", "sexual")
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "x"}, countingProvider(&calls))
+	require.NoError(t, err, "violence is not a configured category")
+
+	interceptor = NewModerationInterceptor(moderator, ModerationBlock).WithCategories("violence")
+	_, err = interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "x"}, countingProvider(&calls))
+	var policyErr *ContentPolicyError
+	require.True(t, errors.As(err, &policyErr))
+	assert.Equal(t, []string{"violence"}, policyErr.Categories)
+}
+
+func TestModerationInterceptor_Thresholds(t *testing.T) {
+	moderator := &stubModerator{result: violentResult()}
+
+	calls := 0
+	interceptor := NewModerationInterceptor(moderator, ModerationBlock).
+		WithThresholds(map[string]float64{"violence": 0.95})
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "x"}, countingProvider(&calls))
+	require.NoError(t, err, "violence scores below its threshold")
+
+	// A threshold applies even when the moderator did not flag the category
+	interceptor = NewModerationInterceptor(moderator, ModerationBlock).
+		WithThresholds(map[string]float64{"hate": 0.3, "violence": 0.95})
+	_, err = interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "x"}, countingProvider(&calls))
+	var policyErr *ContentPolicyError
+	require.True(t, errors.As(err, &policyErr))
+	assert.Equal(t, []string{"hate"}, policyErr.Categories)
+	assert.Equal(t, 1, calls)
+}
+
+func TestModerationInterceptor_ModeratorError(t *testing.T) {
+	moderatorErr := errors.New("moderation unavailable")
+	interceptor := NewModerationInterceptor(&stubModerator{err: moderatorErr}, ModerationFlag)
+
+	calls := 0
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "x"}, countingProvider(&calls))
+	assert.ErrorIs(t, err, moderatorErr)
+	assert.Equal(t, 0, calls)
+}
+
+func TestOpenAIModerator_Moderate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderations", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "omni-moderation-latest", body["model"])
+		assert.Equal(t, "some text", body["input"])
+
+		_, _ = w.Write([]byte(`{"id":"modr-1","model":"omni-moderation-latest","results":[{
+			"flagged": true,
+			"categories": {"violence": true, "hate": false},
+			"category_scores": {"violence": 0.87, "hate": 0.01}
+		}]}`))
+	}))
+	defer server.Close()
+
+	moderator := NewOpenAIModerator("test-key").WithBaseURL(server.URL + "/").WithModel("omni-moderation-latest")
+	result, err := moderator.Moderate(context.Background(), "some text")
+	require.NoError(t, err)
+	assert.True(t, result.Flagged)
+	assert.True(t, result.Categories["violence"])
+	assert.Equal(t, 0.87, result.CategoryScores["violence"])
+}
+
+func TestOpenAIModerator_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad key"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAIModerator("bad").WithBaseURL(server.URL).Moderate(context.Background(), "text")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
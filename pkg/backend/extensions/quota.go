@@ -0,0 +1,269 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
+)
+
+// MetadataKeyQuotaRemaining is the response metadata key holding the tenant's remaining
+// tokens after the request's reservation
+const MetadataKeyQuotaRemaining = "quota_remaining"
+
+// QuotaExceededError is returned when a tenant has too few tokens left for a request
+type QuotaExceededError struct {
+	Key       string
+	Limit     int
+	Used      int
+	Requested int
+	ResetAt   time.Time // Zero when the store does not reset quotas
+}
+
+// Remaining returns the tokens left in the quota
+func (e *QuotaExceededError) Remaining() int {
+	if remaining := e.Limit - e.Used; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+func (e *QuotaExceededError) Error() string {
+	msg := fmt.Sprintf("token quota exceeded for %s: requested %d, %d of %d remaining",
+		e.Key, e.Requested, e.Remaining(), e.Limit)
+	if !e.ResetAt.IsZero() {
+		msg += fmt.Sprintf(", resets at %s", e.ResetAt.Format(time.RFC3339))
+	}
+	return msg
+}
+
+// QuotaStore tracks token usage against per-key quotas. Implementations must be safe
+// for concurrent use; a shared store (e.g. Redis) lets several backends enforce one
+// quota.
+type QuotaStore interface {
+	// Reserve debits tokens from key's quota and returns the tokens remaining, or a
+	// *QuotaExceededError, debiting nothing, when fewer than tokens remain
+	Reserve(ctx context.Context, key string, tokens int) (remaining int, err error)
+
+	// Adjust corrects key's usage by delta once the actual usage is known: positive to
+	// debit more, negative to refund. It never fails for exceeding the quota.
+	Adjust(ctx context.Context, key string, delta int) error
+}
+
+// MemoryQuotaStore is an in-memory QuotaStore whose quotas reset daily at midnight UTC
+type MemoryQuotaStore struct {
+	mu           sync.Mutex
+	defaultLimit int
+	limits       map[string]int
+	used         map[string]int
+	period       time.Time // Start of the day the usage counts belong to
+	now          func() time.Time
+}
+
+// NewMemoryQuotaStore creates a store giving every key dailyLimit tokens a day. Use
+// SetLimit for per-key limits.
+func NewMemoryQuotaStore(dailyLimit int) *MemoryQuotaStore {
+	return &MemoryQuotaStore{
+		defaultLimit: dailyLimit,
+		limits:       make(map[string]int),
+		used:         make(map[string]int),
+		now:          time.Now,
+	}
+}
+
+// SetLimit sets key's daily limit, overriding the default
+func (s *MemoryQuotaStore) SetLimit(key string, dailyLimit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits[key] = dailyLimit
+}
+
+// Used returns the tokens key has used today
+func (s *MemoryQuotaStore) Used(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetIfNewDay()
+	return s.used[key]
+}
+
+// Reserve implements QuotaStore
+func (s *MemoryQuotaStore) Reserve(_ context.Context, key string, tokens int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetIfNewDay()
+
+	limit := s.limit(key)
+	used := s.used[key]
+	if used+tokens > limit {
+		return 0, &QuotaExceededError{
+			Key:       key,
+			Limit:     limit,
+			Used:      used,
+			Requested: tokens,
+			ResetAt:   s.period.AddDate(0, 0, 1),
+		}
+	}
+	s.used[key] = used + tokens
+	return limit - s.used[key], nil
+}
+
+// Adjust implements QuotaStore. Usage never drops below zero, so refunds for a
+// reservation made before a reset do not carry over into the new day.
+func (s *MemoryQuotaStore) Adjust(_ context.Context, key string, delta int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetIfNewDay()
+
+	used := s.used[key] + delta
+	if used < 0 {
+		used = 0
+	}
+	s.used[key] = used
+	return nil
+}
+
+func (s *MemoryQuotaStore) limit(key string) int {
+	if limit, ok := s.limits[key]; ok {
+		return limit
+	}
+	return s.defaultLimit
+}
+
+// resetIfNewDay clears usage at the first call of each UTC day. Callers must hold mu.
+func (s *MemoryQuotaStore) resetIfNewDay() {
+	now := s.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.Equal(s.period) {
+		s.period = day
+		s.used = make(map[string]int)
+	}
+}
+
+// QuotaInterceptor enforces per-key token quotas. Before each call it reserves an
+// estimate of the request's tokens, rejecting the request with a *QuotaExceededError
+// when the quota cannot cover it; after the call it settles the reservation against the
+// actual usage.
+type QuotaInterceptor struct {
+	store QuotaStore
+	keyFn func(req *GenerateRequest) string
+}
+
+// NewQuotaInterceptor creates an interceptor that meters requests against store under
+// the key returned by keyFn, typically a tenant ID. Requests with an empty key, or all
+// requests when keyFn is nil, are not metered.
+//
+// The estimate reserved is the prompt's tokens, estimated with utils, plus MaxTokens,
+// the most the response can use. It is settled against the response's Usage, or an
+// estimate from its content when the provider reports none. A failed call refunds the
+// reservation.
+//
+// Streamed responses report usage when the stream ends, after the call returns. When a
+// request is streamed and its response has no Usage, the reservation stays held until
+// ReportUsage is called with the context passed to the provider; if it never is, the
+// estimate is kept as the usage.
+//
+//	quota := NewQuotaInterceptor(NewMemoryQuotaStore(1_000_000), func(req *GenerateRequest) string {
+//	    tenantID, _ := req.Metadata["tenant_id"].(string)
+//	    return tenantID
+//	})
+func NewQuotaInterceptor(store QuotaStore, keyFn func(req *GenerateRequest) string) *QuotaInterceptor {
+	return &QuotaInterceptor{store: store, keyFn: keyFn}
+}
+
+// EstimateTokens returns the tokens reserved for a request before it is sent
+func (q *QuotaInterceptor) EstimateTokens(req *GenerateRequest) int {
+	return promptTokens(req) + req.MaxTokens
+}
+
+// promptTokens estimates the input tokens of a request, from its messages when present
+// as they are what is sent, otherwise from its prompt
+func promptTokens(req *GenerateRequest) int {
+	if len(req.Messages) > 0 {
+		return utils.EstimateTokensFromMessages(req.Messages)
+	}
+	return utils.EstimateTokensFromString(req.Prompt)
+}
+
+// Intercept reserves the request's estimated tokens, calls next, and settles the reservation
+func (q *QuotaInterceptor) Intercept(ctx context.Context, req *GenerateRequest, next ProviderFunc) (*GenerateResponse, error) {
+	var key string
+	if q.keyFn != nil {
+		key = q.keyFn(req)
+	}
+	if key == "" {
+		return next(ctx, req)
+	}
+
+	reserved := q.EstimateTokens(req)
+	remaining, err := q.store.Reserve(ctx, key, reserved)
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	settle := func(actual int) {
+		once.Do(func() {
+			// Settle even if the request's context has been cancelled
+			_ = q.store.Adjust(context.WithoutCancel(ctx), key, actual-reserved)
+		})
+	}
+
+	if req.Stream {
+		ctx = withUsageSink(ctx, func(usage types.Usage) { settle(usageTokens(usage)) })
+	}
+
+	resp, err := next(ctx, req)
+	if err != nil {
+		settle(0)
+		return resp, err
+	}
+
+	switch {
+	case resp == nil:
+		settle(0)
+		return resp, nil
+	case resp.Usage != nil:
+		settle(usageTokens(*resp.Usage))
+	case !req.Stream:
+		settle(promptTokens(req) + utils.EstimateTokensFromString(resp.Content))
+	}
+
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]interface{})
+	}
+	resp.Metadata[MetadataKeyQuotaRemaining] = remaining
+	return resp, nil
+}
+
+// usageTokens returns the tokens a usage report accounts for
+func usageTokens(usage types.Usage) int {
+	if usage.TotalTokens > 0 {
+		return usage.TotalTokens
+	}
+	return usage.PromptTokens + usage.CompletionTokens
+}
+
+type usageSinkKey struct{}
+
+// withUsageSink returns a context whose ReportUsage calls sink, after any sinks already
+// in ctx
+func withUsageSink(ctx context.Context, sink func(types.Usage)) context.Context {
+	existing, _ := ctx.Value(usageSinkKey{}).([]func(types.Usage))
+	sinks := make([]func(types.Usage), len(existing), len(existing)+1)
+	copy(sinks, existing)
+	return context.WithValue(ctx, usageSinkKey{}, append(sinks, sink))
+}
+
+// ReportUsage reports a streamed response's usage once the stream has ended, settling
+// any QuotaInterceptor reservations made for it. ctx must be the context the provider
+// call received. It reports whether any interceptor was waiting for the usage.
+func ReportUsage(ctx context.Context, usage types.Usage) bool {
+	sinks, _ := ctx.Value(usageSinkKey{}).([]func(types.Usage))
+	for _, sink := range sinks {
+		sink(usage)
+	}
+	return len(sinks) > 0
+}
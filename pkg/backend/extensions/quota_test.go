@@ -0,0 +1,158 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tenantKey(req *GenerateRequest) string {
+	tenantID, _ := req.Metadata["tenant_id"].(string)
+	return tenantID
+}
+
+func tenantRequest(tenantID string, maxTokens int) *GenerateRequest {
+	return &GenerateRequest{
+		Prompt:    "Hello",
+		MaxTokens: maxTokens,
+		Metadata:  map[string]interface{}{"tenant_id": tenantID},
+	}
+}
+
+func TestMemoryQuotaStore_ResetsDaily(t *testing.T) {
+	store := NewMemoryQuotaStore(100)
+	now := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	remaining, err := store.Reserve(ctx, "acme", 80)
+	require.NoError(t, err)
+	assert.Equal(t, 20, remaining)
+
+	_, err = store.Reserve(ctx, "acme", 30)
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, 20, quotaErr.Remaining())
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), quotaErr.ResetAt)
+	assert.Equal(t, 80, store.Used("acme"), "a rejected reservation debits nothing")
+
+	now = now.Add(2 * time.Hour)
+	_, err = store.Reserve(ctx, "acme", 30)
+	require.NoError(t, err)
+	assert.Equal(t, 30, store.Used("acme"))
+}
+
+func TestMemoryQuotaStore_PerKeyLimits(t *testing.T) {
+	store := NewMemoryQuotaStore(100)
+	store.SetLimit("enterprise", 1000)
+
+	_, err := store.Reserve(context.Background(), "enterprise", 500)
+	assert.NoError(t, err)
+	_, err = store.Reserve(context.Background(), "free", 500)
+	assert.Error(t, err)
+}
+
+func TestQuotaInterceptor_SettlesActualUsage(t *testing.T) {
+	store := NewMemoryQuotaStore(1000)
+	quota := NewQuotaInterceptor(store, tenantKey)
+
+	resp, err := quota.Intercept(context.Background(), tenantRequest("acme", 500), func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		assert.Equal(t, quota.EstimateTokens(req), store.Used("acme"), "the estimate is reserved during the call")
+		return &GenerateResponse{Content: "Hi", Usage: &types.Usage{PromptTokens: 5, CompletionTokens: 7, TotalTokens: 12}}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 12, store.Used("acme"))
+	assert.Equal(t, 1000-quota.EstimateTokens(tenantRequest("acme", 500)), resp.Metadata[MetadataKeyQuotaRemaining])
+}
+
+func TestQuotaInterceptor_RejectsWhenExceeded(t *testing.T) {
+	quota := NewQuotaInterceptor(NewMemoryQuotaStore(100), tenantKey)
+
+	called := false
+	_, err := quota.Intercept(context.Background(), tenantRequest("acme", 500), func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		called = true
+		return &GenerateResponse{}, nil
+	})
+
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, "acme", quotaErr.Key)
+	assert.False(t, called, "the provider must not be called")
+}
+
+func TestQuotaInterceptor_RefundsFailedCalls(t *testing.T) {
+	store := NewMemoryQuotaStore(1000)
+	quota := NewQuotaInterceptor(store, tenantKey)
+
+	_, err := quota.Intercept(context.Background(), tenantRequest("acme", 500), func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		return nil, errors.New("provider down")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 0, store.Used("acme"))
+}
+
+func TestQuotaInterceptor_EstimatesMissingUsage(t *testing.T) {
+	store := NewMemoryQuotaStore(1000)
+	quota := NewQuotaInterceptor(store, tenantKey)
+
+	_, err := quota.Intercept(context.Background(), tenantRequest("acme", 500), func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		return &GenerateResponse{Content: "A response of a few tokens"}, nil
+	})
+	require.NoError(t, err)
+	used := store.Used("acme")
+	assert.Greater(t, used, 0)
+	assert.Less(t, used, 500, "the unused MaxTokens headroom is refunded")
+}
+
+func TestQuotaInterceptor_EstimatesMessages(t *testing.T) {
+	quota := NewQuotaInterceptor(NewMemoryQuotaStore(1000), tenantKey)
+
+	req := &GenerateRequest{
+		Messages: []types.ChatMessage{
+			{Role: "system", Content: "You are a helpful assistant that answers briefly."},
+			{Role: "user", Content: "What is the capital of France?"},
+		},
+		MaxTokens: 100,
+	}
+	assert.Equal(t, utils.EstimateTokensFromMessages(req.Messages)+100, quota.EstimateTokens(req),
+		"a request without a prompt is estimated from its messages")
+}
+
+func TestQuotaInterceptor_StreamUsageArrivesLate(t *testing.T) {
+	store := NewMemoryQuotaStore(1000)
+	quota := NewQuotaInterceptor(store, tenantKey)
+	req := tenantRequest("acme", 500)
+	req.Stream = true
+
+	var streamCtx context.Context
+	_, err := quota.Intercept(context.Background(), req, func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		streamCtx = ctx
+		return &GenerateResponse{}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, quota.EstimateTokens(req), store.Used("acme"), "the reservation is held until the stream ends")
+
+	assert.True(t, ReportUsage(streamCtx, types.Usage{PromptTokens: 5, CompletionTokens: 40}))
+	assert.Equal(t, 45, store.Used("acme"))
+
+	ReportUsage(streamCtx, types.Usage{TotalTokens: 999})
+	assert.Equal(t, 45, store.Used("acme"), "usage is settled once")
+
+	assert.False(t, ReportUsage(context.Background(), types.Usage{TotalTokens: 1}))
+}
+
+func TestQuotaInterceptor_UnkeyedRequestsPassThrough(t *testing.T) {
+	store := NewMemoryQuotaStore(0)
+	quota := NewQuotaInterceptor(store, tenantKey)
+
+	_, err := quota.Intercept(context.Background(), &GenerateRequest{Prompt: "Hello"}, func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		return &GenerateResponse{Content: "Hi"}, nil
+	})
+	assert.NoError(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
@@ -33,6 +34,13 @@ func (r *registry) Register(ext Extension) error {
 
 	r.extensions[name] = ext
 	r.order = append(r.order, name)
+
+	// Reject the extension if it closes a dependency cycle
+	if cycle := r.findCycle(); cycle != nil {
+		delete(r.extensions, name)
+		r.order = r.order[:len(r.order)-1]
+		return fmt.Errorf("extension %s not registered: %w", name, cycleError(cycle))
+	}
 	return nil
 }
 
@@ -43,21 +51,35 @@ func (r *registry) Get(name string) (Extension, bool) {
 	return ext, ok
 }
 
+// List returns the extensions in hook execution order: by priority (lower runs
+// first), except that an extension always runs after the extensions it depends on.
+// Extensions with the same priority keep their registration order.
 func (r *registry) List() []Extension {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	return r.list()
+}
 
+// list is List without locking; callers must hold r.mu
+func (r *registry) list() []Extension {
 	result := make([]Extension, 0, len(r.extensions))
+
+	sorted, err := r.topologicalSort()
+	if err == nil {
+		for _, name := range sorted {
+			result = append(result, r.extensions[name])
+		}
+		return result
+	}
+
+	// Cycles are rejected at registration, so this is only reachable if an
+	// extension changed its dependencies afterwards; fall back to priority order
 	for _, name := range r.order {
 		result = append(result, r.extensions[name])
 	}
-
-	// Sort by priority (lower runs first), using stable sort to preserve
-	// registration order for extensions with the same priority
 	sort.SliceStable(result, func(i, j int) bool {
 		return result[i].Priority() < result[j].Priority()
 	})
-
 	return result
 }
 
@@ -96,57 +118,117 @@ func (r *registry) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// topologicalSort orders the registered extensions so that every extension comes
+// after its dependencies. Among the extensions whose dependencies are satisfied, the
+// lowest priority is placed first, with ties broken by registration order.
+// Dependencies on extensions that are not registered are ignored.
 func (r *registry) topologicalSort() ([]string, error) {
-	visited := make(map[string]bool)
-	result := make([]string, 0, len(r.extensions))
-
-	var visit func(name string) error
-	visit = func(name string) error {
-		if visited[name] {
-			return nil
+	placed := make(map[string]bool, len(r.order))
+	result := make([]string, 0, len(r.order))
+
+	for len(result) < len(r.order) {
+		next := ""
+		for _, name := range r.order {
+			if placed[name] || !r.dependenciesPlaced(name, placed) {
+				continue
+			}
+			if next == "" || r.extensions[name].Priority() < r.extensions[next].Priority() {
+				next = name
+			}
 		}
-		visited[name] = true
 
-		ext, ok := r.extensions[name]
-		if !ok {
-			return nil
+		if next == "" {
+			if cycle := r.findCycle(); cycle != nil {
+				return nil, cycleError(cycle)
+			}
+			return nil, fmt.Errorf("unable to order extensions")
 		}
 
-		// Use capability-based dependency checking
-		var deps []string
-		if declarer, ok := ext.(DependencyDeclarer); ok {
-			deps = declarer.Dependencies()
-		} else {
-			deps = ext.Dependencies()
+		placed[next] = true
+		result = append(result, next)
+	}
+
+	return result, nil
+}
+
+// dependenciesPlaced reports whether every registered dependency of name is placed
+func (r *registry) dependenciesPlaced(name string, placed map[string]bool) bool {
+	for _, dep := range dependenciesOf(r.extensions[name]) {
+		if _, registered := r.extensions[dep]; registered && !placed[dep] {
+			return false
 		}
+	}
+	return true
+}
 
-		for _, dep := range deps {
-			if err := visit(dep); err != nil {
-				return err
+// findCycle returns the names along a dependency cycle, starting and ending with the
+// same extension, or nil if the registered extensions are acyclic
+func (r *registry) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(r.order))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
 			}
 		}
 
-		result = append(result, name)
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependenciesOf(r.extensions[name]) {
+			if _, registered := r.extensions[dep]; !registered {
+				continue
+			}
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
 		return nil
 	}
 
 	for _, name := range r.order {
-		if err := visit(name); err != nil {
-			return nil, err
+		if cycle := visit(name); cycle != nil {
+			return cycle
 		}
 	}
+	return nil
+}
+
+// dependenciesOf returns the names of the extensions ext depends on
+func dependenciesOf(ext Extension) []string {
+	// Use capability-based dependency checking
+	if declarer, ok := ext.(DependencyDeclarer); ok {
+		return declarer.Dependencies()
+	}
+	return ext.Dependencies()
+}
 
-	return result, nil
+func cycleError(cycle []string) error {
+	return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
 }
 
 // CallBeforeGenerate invokes BeforeGenerate hooks on all registered extensions that implement it.
-// Extensions are called in priority order (lowest priority first).
+// Extensions are called in List order (priority, then dependencies).
 // If any extension returns an error, iteration stops and the error is returned.
 func (r *registry) CallBeforeGenerate(ctx context.Context, req *GenerateRequest) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	exts := r.List()
+	exts := r.list()
 	for _, ext := range exts {
 		if hook, ok := ext.(BeforeGenerateHook); ok {
 			if err := hook.BeforeGenerate(ctx, req); err != nil {
@@ -158,13 +240,13 @@ func (r *registry) CallBeforeGenerate(ctx context.Context, req *GenerateRequest)
 }
 
 // CallAfterGenerate invokes AfterGenerate hooks on all registered extensions that implement it.
-// Extensions are called in priority order (lowest priority first).
+// Extensions are called in List order (priority, then dependencies).
 // If any extension returns an error, iteration stops and the error is returned.
 func (r *registry) CallAfterGenerate(ctx context.Context, req *GenerateRequest, resp *GenerateResponse) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	exts := r.List()
+	exts := r.list()
 	for _, ext := range exts {
 		if hook, ok := ext.(AfterGenerateHook); ok {
 			if err := hook.AfterGenerate(ctx, req, resp); err != nil {
@@ -176,13 +258,13 @@ func (r *registry) CallAfterGenerate(ctx context.Context, req *GenerateRequest,
 }
 
 // CallOnProviderError invokes OnProviderError hooks on all registered extensions that implement it.
-// Extensions are called in priority order (lowest priority first).
+// Extensions are called in List order (priority, then dependencies).
 // If any extension returns an error, iteration stops and the error is returned.
 func (r *registry) CallOnProviderError(ctx context.Context, provider types.Provider, err error) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	exts := r.List()
+	exts := r.list()
 	for _, ext := range exts {
 		if handler, ok := ext.(ProviderErrorHandler); ok {
 			if hookErr := handler.OnProviderError(ctx, provider, err); hookErr != nil {
@@ -194,13 +276,13 @@ func (r *registry) CallOnProviderError(ctx context.Context, provider types.Provi
 }
 
 // CallOnProviderSelected invokes OnProviderSelected hooks on all registered extensions that implement it.
-// Extensions are called in priority order (lowest priority first).
+// Extensions are called in List order (priority, then dependencies).
 // If any extension returns an error, iteration stops and the error is returned.
 func (r *registry) CallOnProviderSelected(ctx context.Context, provider types.Provider) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	exts := r.List()
+	exts := r.list()
 	for _, ext := range exts {
 		if hook, ok := ext.(ProviderSelectionHook); ok {
 			if err := hook.OnProviderSelected(ctx, provider); err != nil {
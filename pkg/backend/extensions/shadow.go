@@ -0,0 +1,223 @@
+package extensions
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
+)
+
+// defaultShadowTimeout bounds shadow calls for requests without a deadline
+const defaultShadowTimeout = 60 * time.Second
+
+// ShadowComparison records how a shadow call compared to the primary call it mirrored
+type ShadowComparison struct {
+	Timestamp       time.Time
+	PrimaryProvider string
+	ShadowProvider  string
+
+	PrimaryLatency time.Duration
+	ShadowLatency  time.Duration
+
+	// Token counts are estimated from the response content so both sides are
+	// measured the same way; TokenDelta is shadow minus primary
+	PrimaryTokens int
+	ShadowTokens  int
+	TokenDelta    int
+
+	// Diverged is true when the outputs differ after trimming whitespace
+	Diverged bool
+
+	// Error is the shadow call's error, if any; the other shadow fields are then zero
+	Error error
+}
+
+// LatencyDelta returns the shadow latency minus the primary latency
+func (c ShadowComparison) LatencyDelta() time.Duration {
+	return c.ShadowLatency - c.PrimaryLatency
+}
+
+// ShadowStats aggregates the comparisons recorded by a ShadowInterceptor
+type ShadowStats struct {
+	Sampled      int64
+	Errors       int64
+	Diverged     int64
+	LatencyDelta time.Duration // Sum over successful shadow calls
+	TokenDelta   int64         // Sum over successful shadow calls
+}
+
+// AverageLatencyDelta returns the mean shadow-minus-primary latency of successful shadow calls
+func (s ShadowStats) AverageLatencyDelta() time.Duration {
+	completed := s.Sampled - s.Errors
+	if completed <= 0 {
+		return 0
+	}
+	return s.LatencyDelta / time.Duration(completed)
+}
+
+// ShadowInterceptor mirrors a sample of requests to a second provider after the
+// primary call returns and records how the two compared. Shadow calls run
+// asynchronously; they never delay the response and their errors are never
+// surfaced to the caller.
+type ShadowInterceptor struct {
+	shadowProvider types.Provider
+	sampleRate     float64
+	timeout        time.Duration
+
+	mu           sync.Mutex
+	stats        ShadowStats
+	onComparison func(ShadowComparison)
+	wg           sync.WaitGroup
+}
+
+// NewShadowInterceptor creates an interceptor that sends sampleRate (0.0-1.0) of
+// successful requests to shadowProvider as well. The shadow request uses the same
+// prompt and generation parameters but no model, so the shadow provider uses its
+// configured default model.
+func NewShadowInterceptor(shadowProvider types.Provider, sampleRate float64) *ShadowInterceptor {
+	if sampleRate < 0 {
+		sampleRate = 0
+	} else if sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &ShadowInterceptor{
+		shadowProvider: shadowProvider,
+		sampleRate:     sampleRate,
+		timeout:        defaultShadowTimeout,
+	}
+}
+
+// WithTimeout sets the maximum duration of a shadow call for requests whose context
+// has no deadline. Requests with a deadline bound their shadow call to that deadline.
+func (s *ShadowInterceptor) WithTimeout(timeout time.Duration) *ShadowInterceptor {
+	s.timeout = timeout
+	return s
+}
+
+// OnComparison registers a function called with each comparison, for example to log
+// it or export it as metrics. It is called from the shadow goroutine.
+func (s *ShadowInterceptor) OnComparison(fn func(ShadowComparison)) *ShadowInterceptor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onComparison = fn
+	return s
+}
+
+// Stats returns the aggregate comparison statistics
+func (s *ShadowInterceptor) Stats() ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Wait blocks until all in-flight shadow calls have finished
+func (s *ShadowInterceptor) Wait() {
+	s.wg.Wait()
+}
+
+// Intercept runs the primary call and, for sampled successful requests, starts the shadow call
+func (s *ShadowInterceptor) Intercept(ctx context.Context, req *GenerateRequest, next ProviderFunc) (*GenerateResponse, error) {
+	start := time.Now()
+	resp, err := next(ctx, req)
+	if err != nil || resp == nil || !s.sampled() {
+		return resp, err
+	}
+
+	comparison := ShadowComparison{
+		Timestamp:       start,
+		PrimaryProvider: resp.Provider,
+		ShadowProvider:  s.shadowProvider.Name(),
+		PrimaryLatency:  time.Since(start),
+		PrimaryTokens:   utils.EstimateTokensFromString(resp.Content),
+	}
+	primaryContent := resp.Content
+	shadowReq := *req
+
+	// Detach from the request's cancellation, which typically fires as soon as the
+	// response is written, but keep its deadline so the shadow call stays within
+	// the request budget
+	shadowCtx, cancel := s.shadowContext(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.record(s.runShadow(shadowCtx, &shadowReq, primaryContent, comparison))
+	}()
+
+	return resp, nil
+}
+
+func (s *ShadowInterceptor) sampled() bool {
+	return s.sampleRate > 0 && rand.Float64() < s.sampleRate //nolint:gosec // G404: math/rand is sufficient for sampling
+}
+
+func (s *ShadowInterceptor) shadowContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return context.WithTimeout(detached, s.timeout)
+}
+
+// runShadow sends the request to the shadow provider and completes the comparison
+func (s *ShadowInterceptor) runShadow(ctx context.Context, req *GenerateRequest, primaryContent string, comparison ShadowComparison) ShadowComparison {
+	start := time.Now()
+
+	options := types.GenerateOptions{
+		Prompt:      req.Prompt,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Messages:    req.Messages,
+		ContextObj:  ctx,
+	}
+	if len(options.Messages) == 0 {
+		options.Messages = []types.ChatMessage{{Role: "user", Content: req.Prompt}}
+	}
+
+	stream, err := s.shadowProvider.GenerateChatCompletion(ctx, options)
+	if err != nil {
+		comparison.Error = err
+		return comparison
+	}
+
+	var content strings.Builder
+	err = types.StreamToCallback(stream, func(chunk types.ChatCompletionChunk) error {
+		content.WriteString(chunk.Content)
+		return nil
+	})
+	if err != nil {
+		comparison.Error = err
+		return comparison
+	}
+
+	comparison.ShadowLatency = time.Since(start)
+	comparison.ShadowTokens = utils.EstimateTokensFromString(content.String())
+	comparison.TokenDelta = comparison.ShadowTokens - comparison.PrimaryTokens
+	comparison.Diverged = strings.TrimSpace(content.String()) != strings.TrimSpace(primaryContent)
+	return comparison
+}
+
+func (s *ShadowInterceptor) record(comparison ShadowComparison) {
+	s.mu.Lock()
+	s.stats.Sampled++
+	if comparison.Error != nil {
+		s.stats.Errors++
+	} else {
+		if comparison.Diverged {
+			s.stats.Diverged++
+		}
+		s.stats.LatencyDelta += comparison.LatencyDelta()
+		s.stats.TokenDelta += int64(comparison.TokenDelta)
+	}
+	onComparison := s.onComparison
+	s.mu.Unlock()
+
+	if onComparison != nil {
+		onComparison(comparison)
+	}
+}
@@ -0,0 +1,205 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shadowTestStream returns a single content chunk and then io.EOF
+type shadowTestStream struct {
+	content string
+	sent    bool
+}
+
+func (s *shadowTestStream) Next() (types.ChatCompletionChunk, error) {
+	if s.sent {
+		return types.ChatCompletionChunk{}, io.EOF
+	}
+	s.sent = true
+	return types.ChatCompletionChunk{Content: s.content}, nil
+}
+
+func (s *shadowTestStream) Close() error { return nil }
+
+// shadowTestProvider answers with fixed content, optionally blocking until its context ends
+type shadowTestProvider struct {
+	mockProvider
+	content string
+	err     error
+	block   bool
+
+	mu      sync.Mutex
+	calls   int
+	options types.GenerateOptions
+}
+
+func (p *shadowTestProvider) GenerateChatCompletion(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+	p.mu.Lock()
+	p.calls++
+	p.options = options
+	p.mu.Unlock()
+
+	if p.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &shadowTestStream{content: p.content}, nil
+}
+
+func primaryReturning(content string) ProviderFunc {
+	return func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		return &GenerateResponse{Content: content, Provider: "primary"}, nil
+	}
+}
+
+func TestShadowInterceptor_RecordsComparison(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, content: "a much longer answer from the shadow provider"}
+	interceptor := NewShadowInterceptor(shadow, 1.0)
+
+	var comparisons []ShadowComparison
+	interceptor.OnComparison(func(c ShadowComparison) { comparisons = append(comparisons, c) })
+
+	req := &GenerateRequest{Model: "gpt-4o", Prompt: "hello", MaxTokens: 100, Temperature: 0.5}
+	resp, err := interceptor.Intercept(context.Background(), req, primaryReturning("short"))
+	require.NoError(t, err)
+	assert.Equal(t, "short", resp.Content)
+
+	interceptor.Wait()
+
+	require.Len(t, comparisons, 1)
+	c := comparisons[0]
+	assert.NoError(t, c.Error)
+	assert.Equal(t, "primary", c.PrimaryProvider)
+	assert.Equal(t, "shadow", c.ShadowProvider)
+	assert.True(t, c.Diverged)
+	assert.Greater(t, c.TokenDelta, 0)
+	assert.Equal(t, c.ShadowTokens-c.PrimaryTokens, c.TokenDelta)
+
+	assert.Equal(t, "hello", shadow.options.Prompt)
+	assert.Equal(t, 100, shadow.options.MaxTokens)
+	assert.Empty(t, shadow.options.Model, "shadow should use its own default model")
+
+	stats := interceptor.Stats()
+	assert.Equal(t, int64(1), stats.Sampled)
+	assert.Equal(t, int64(1), stats.Diverged)
+	assert.Zero(t, stats.Errors)
+}
+
+func TestShadowInterceptor_MatchingOutputs(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, content: "same answer\n"}
+	interceptor := NewShadowInterceptor(shadow, 1.0)
+
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "hi"}, primaryReturning("same answer"))
+	require.NoError(t, err)
+	interceptor.Wait()
+
+	stats := interceptor.Stats()
+	assert.Equal(t, int64(1), stats.Sampled)
+	assert.Zero(t, stats.Diverged)
+}
+
+func TestShadowInterceptor_SendsMessages(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, content: "Paris"}
+	interceptor := NewShadowInterceptor(shadow, 1.0)
+
+	messages := []types.ChatMessage{
+		{Role: "system", Content: "Answer briefly."},
+		{Role: "user", Content: "What is the capital of France?"},
+	}
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{Messages: messages}, primaryReturning("Paris"))
+	require.NoError(t, err)
+	interceptor.Wait()
+
+	assert.Equal(t, messages, shadow.options.Messages, "the shadow provider gets the same conversation")
+}
+
+func TestShadowInterceptor_ShadowErrorsNotSurfaced(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, err: errors.New("shadow unavailable")}
+	interceptor := NewShadowInterceptor(shadow, 1.0)
+
+	var comparison ShadowComparison
+	interceptor.OnComparison(func(c ShadowComparison) { comparison = c })
+
+	resp, err := interceptor.Intercept(context.Background(), &GenerateRequest{Prompt: "hi"}, primaryReturning("ok"))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Content)
+
+	interceptor.Wait()
+	assert.EqualError(t, comparison.Error, "shadow unavailable")
+	assert.Equal(t, int64(1), interceptor.Stats().Errors)
+}
+
+func TestShadowInterceptor_SkipsFailedPrimary(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, content: "x"}
+	interceptor := NewShadowInterceptor(shadow, 1.0)
+
+	primaryErr := errors.New("primary failed")
+	_, err := interceptor.Intercept(context.Background(), &GenerateRequest{}, func(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+		return nil, primaryErr
+	})
+	interceptor.Wait()
+
+	assert.ErrorIs(t, err, primaryErr)
+	assert.Zero(t, shadow.calls)
+}
+
+func TestShadowInterceptor_SampleRate(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, content: "x"}
+	interceptor := NewShadowInterceptor(shadow, 0)
+
+	for i := 0; i < 20; i++ {
+		_, err := interceptor.Intercept(context.Background(), &GenerateRequest{}, primaryReturning("x"))
+		require.NoError(t, err)
+	}
+	interceptor.Wait()
+
+	assert.Zero(t, shadow.calls)
+	assert.Zero(t, interceptor.Stats().Sampled)
+}
+
+func TestShadowInterceptor_DoesNotBlockResponse(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, block: true}
+	interceptor := NewShadowInterceptor(shadow, 1.0).WithTimeout(50 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	_, err := interceptor.Intercept(ctx, &GenerateRequest{}, primaryReturning("ok"))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// Cancelling the request must not cancel the shadow call; the timeout ends it
+	cancel()
+	interceptor.Wait()
+
+	stats := interceptor.Stats()
+	assert.Equal(t, int64(1), stats.Errors)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestShadowInterceptor_RespectsRequestDeadline(t *testing.T) {
+	shadow := &shadowTestProvider{mockProvider: mockProvider{name: "shadow"}, block: true}
+	interceptor := NewShadowInterceptor(shadow, 1.0).WithTimeout(time.Hour)
+
+	var comparison ShadowComparison
+	interceptor.OnComparison(func(c ShadowComparison) { comparison = c })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err := interceptor.Intercept(ctx, &GenerateRequest{}, primaryReturning("ok"))
+	require.NoError(t, err)
+	interceptor.Wait()
+
+	assert.ErrorIs(t, comparison.Error, context.DeadlineExceeded)
+}
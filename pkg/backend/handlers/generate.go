@@ -222,6 +222,7 @@ func convertToExtensionRequest(req *backendtypes.GenerateRequest) *extensions.Ge
 		Provider:    req.Provider,
 		Model:       req.Model,
 		Prompt:      req.Prompt,
+		Messages:    req.Messages,
 		MaxTokens:   req.MaxTokens,
 		Temperature: req.Temperature,
 		Stream:      req.Stream,
@@ -234,6 +235,7 @@ func updateFromExtensionRequest(req *backendtypes.GenerateRequest, extReq *exten
 	req.Provider = extReq.Provider
 	req.Model = extReq.Model
 	req.Prompt = extReq.Prompt
+	req.Messages = extReq.Messages
 	req.MaxTokens = extReq.MaxTokens
 	req.Temperature = extReq.Temperature
 	req.Stream = extReq.Stream
@@ -250,6 +252,32 @@ func convertToExtensionResponse(resp *backendtypes.GenerateResponse) *extensions
 	}
 }
 
+// chunkHooks returns the extensions implementing StreamChunkHook, in registry order.
+// Listing sorts the registry, so it is done once per stream rather than per chunk.
+func chunkHooks(registry extensions.ExtensionRegistry) []extensions.StreamChunkHook {
+	if registry == nil {
+		return nil
+	}
+	var hooks []extensions.StreamChunkHook
+	for _, ext := range registry.List() {
+		if hook, ok := ext.(extensions.StreamChunkHook); ok {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// runChunkHooks calls OnChunk on each hook in order. A returned error means the
+// stream must be aborted.
+func runChunkHooks(ctx context.Context, hooks []extensions.StreamChunkHook, extReq *extensions.GenerateRequest, chunk *types.ChatCompletionChunk) error {
+	for _, hook := range hooks {
+		if err := hook.OnChunk(ctx, extReq, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // updateFromExtensionResponse updates backendtypes.GenerateResponse from extensions.GenerateResponse
 func updateFromExtensionResponse(resp *backendtypes.GenerateResponse, extResp *extensions.GenerateResponse) {
 	resp.Content = extResp.Content
@@ -306,7 +334,7 @@ func (h *GenerateHandler) handleStreamingRequest(w http.ResponseWriter, r *http.
 	}()
 
 	// Process and send stream chunks
-	fullContent, usage, streamErr := h.processSSEStreamChunks(stream, sseWriter)
+	fullContent, usage, streamErr := h.processSSEStreamChunks(ctx, req, stream, sseWriter)
 	if streamErr != nil {
 		return // Error already sent via SSE
 	}
@@ -336,9 +364,12 @@ func (h *GenerateHandler) handleStreamingRequest(w http.ResponseWriter, r *http.
 }
 
 // processSSEStreamChunks processes all chunks from the stream and sends them via SSE
-func (h *GenerateHandler) processSSEStreamChunks(stream types.ChatCompletionStream, sseWriter *SSEWriter) (string, *backendtypes.UsageInfo, error) {
+// OnChunk extension hooks run before each chunk is sent; a hook error aborts the stream.
+func (h *GenerateHandler) processSSEStreamChunks(ctx context.Context, req *backendtypes.GenerateRequest, stream types.ChatCompletionStream, sseWriter *SSEWriter) (string, *backendtypes.UsageInfo, error) {
 	var fullContent string
 	var usage *backendtypes.UsageInfo
+	extReq := convertToExtensionRequest(req)
+	hooks := chunkHooks(h.extensions)
 
 	for {
 		chunk, err := stream.Next()
@@ -358,6 +389,11 @@ func (h *GenerateHandler) processSSEStreamChunks(stream types.ChatCompletionStre
 			break
 		}
 
+		if err := runChunkHooks(ctx, hooks, extReq, &chunk); err != nil {
+			sseWriter.WriteError("EXTENSION_ERROR", "OnChunk hook failed: "+err.Error())
+			return "", nil, err
+		}
+
 		// Extract content from chunk
 		if chunk.Content != "" {
 			fullContent += chunk.Content
@@ -15,6 +15,7 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backend/extensions"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backend/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backendtypes"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/virtual/racing"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -918,6 +919,86 @@ func TestGenerateHandler_Generate_Streaming(t *testing.T) {
 	}
 }
 
+// chunkHookExtension applies onChunk to every streamed chunk
+type chunkHookExtension struct {
+	extensions.BaseExtension
+	onChunk func(chunk *types.ChatCompletionChunk) error
+}
+
+func (m *chunkHookExtension) Name() string                                   { return "chunk-hook" }
+func (m *chunkHookExtension) Version() string                                { return "1.0.0" }
+func (m *chunkHookExtension) Description() string                            { return "Inspects streamed chunks" }
+func (m *chunkHookExtension) Initialize(config map[string]interface{}) error { return nil }
+
+func (m *chunkHookExtension) OnChunk(ctx context.Context, req *extensions.GenerateRequest, chunk *types.ChatCompletionChunk) error {
+	return m.onChunk(chunk)
+}
+
+func TestGenerateHandler_Generate_StreamingOnChunk(t *testing.T) {
+	tests := []struct {
+		name        string
+		onChunk     func(chunk *types.ChatCompletionChunk) error
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "hook mutates chunk",
+			onChunk: func(chunk *types.ChatCompletionChunk) error {
+				chunk.Content = strings.ReplaceAll(chunk.Content, "secret", "******")
+				return nil
+			},
+			contains:    []string{"the ****** plan", "[DONE]"},
+			notContains: []string{"secret"},
+		},
+		{
+			name: "hook error aborts stream",
+			onChunk: func(chunk *types.ChatCompletionChunk) error {
+				if strings.Contains(chunk.Content, "secret") {
+					return errors.New("content blocked by moderation")
+				}
+				return nil
+			},
+			contains:    []string{"EXTENSION_ERROR", "content blocked by moderation"},
+			notContains: []string{"the secret plan", "[DONE]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mockProvider{
+				name:             "test",
+				generateResponse: &types.ChatCompletionChunk{Content: "the secret plan"},
+			}
+			providers := map[string]types.Provider{"test": provider}
+
+			registry := &mockExtensionRegistry{}
+			_ = registry.Register(&chunkHookExtension{onChunk: tt.onChunk})
+
+			body, _ := json.Marshal(backendtypes.GenerateRequest{Prompt: "Test prompt", Stream: true})
+
+			for name, serve := range map[string]http.HandlerFunc{
+				"generate": NewGenerateHandler(providers, registry, "test").Generate,
+				"stream":   NewStreamHandler(providers, registry, "test").StreamGenerate,
+			} {
+				w := httptest.NewRecorder()
+				serve(w, newRequestWithContext("POST", "/api/generate", body))
+
+				responseBody := w.Body.String()
+				for _, want := range tt.contains {
+					if !strings.Contains(responseBody, want) {
+						t.Errorf("%s: expected %q in response, got: %s", name, want, responseBody)
+					}
+				}
+				for _, unwanted := range tt.notContains {
+					if strings.Contains(responseBody, unwanted) {
+						t.Errorf("%s: expected %q not to be in response, got: %s", name, unwanted, responseBody)
+					}
+				}
+			}
+		})
+	}
+}
+
 // ============================================================================
 // Metrics Handler Tests (metrics.go)
 // ============================================================================
@@ -944,6 +1025,97 @@ func TestMetricsHandler_GetProviderMetrics(t *testing.T) {
 	}
 }
 
+func TestMetricsHandler_GetMetricsSnapshot(t *testing.T) {
+	providers := map[string]types.Provider{
+		"a": &mockProvider{name: "a", metrics: types.ProviderMetrics{
+			RequestCount: 10, SuccessCount: 8, ErrorCount: 2, TokensUsed: 100,
+			TotalLatency: 8 * time.Second, P95Latency: 2 * time.Second,
+		}},
+		"b": &mockProvider{name: "b", metrics: types.ProviderMetrics{
+			RequestCount: 10, SuccessCount: 10, TokensUsed: 50, TotalLatency: 4 * time.Second,
+		}},
+	}
+	handler := NewMetricsHandler(providers)
+
+	w := httptest.NewRecorder()
+	r := newRequestWithContext("GET", "/api/metrics", nil)
+
+	handler.GetMetricsSnapshot(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Data MetricsSnapshotResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	totals := response.Data.Totals
+	if totals.RequestCount != 20 || totals.SuccessCount != 18 || totals.ErrorCount != 2 || totals.TokensUsed != 150 {
+		t.Errorf("Unexpected totals: %+v", totals)
+	}
+	if totals.SuccessRate != 0.9 {
+		t.Errorf("Expected success rate 0.9, got %v", totals.SuccessRate)
+	}
+	if totals.AverageLatency != 12*time.Second/18 {
+		t.Errorf("Expected average latency %v, got %v", 12*time.Second/18, totals.AverageLatency)
+	}
+	if got := response.Data.Providers["a"].P95Latency; got != 2*time.Second {
+		t.Errorf("Expected provider p95 latency 2s, got %v", got)
+	}
+	if response.Data.Uptime == "" {
+		t.Error("Expected uptime in response")
+	}
+	if response.Data.Models != nil {
+		t.Error("Expected no model breakdown without a collector")
+	}
+}
+
+func TestMetricsHandler_GetMetricsSnapshot_ModelBreakdown(t *testing.T) {
+	collector := metrics.NewDefaultMetricsCollector()
+	for _, eventType := range []types.MetricEventType{types.MetricEventRequest, types.MetricEventSuccess} {
+		_ = collector.RecordEvent(context.Background(), types.MetricEvent{
+			Type:         eventType,
+			ProviderName: "openai",
+			ProviderType: types.ProviderTypeOpenAI,
+			ModelID:      "gpt-4o",
+			Latency:      time.Second,
+		})
+	}
+	handler := NewMetricsHandler(nil).WithMetricsCollector(collector)
+
+	w := httptest.NewRecorder()
+	r := newRequestWithContext("GET", "/api/metrics", nil)
+
+	handler.GetMetricsSnapshot(w, r)
+
+	var response struct {
+		Data MetricsSnapshotResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if _, ok := response.Data.Models["gpt-4o"]; !ok {
+		t.Errorf("Expected gpt-4o in model breakdown, got %v", response.Data.Models)
+	}
+}
+
+func TestMetricsHandler_GetMetricsSnapshot_WrongMethod(t *testing.T) {
+	handler := NewMetricsHandler(nil)
+
+	w := httptest.NewRecorder()
+	r := newRequestWithContext("POST", "/api/metrics", nil)
+
+	handler.GetMetricsSnapshot(w, r)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
+
 func TestMetricsHandler_GetSystemMetrics(t *testing.T) {
 	handler := NewMetricsHandler(nil)
 
@@ -2123,6 +2295,67 @@ func TestGenerateHandler_Generate_AfterGenerateError(t *testing.T) {
 	}
 }
 
+// mutatingExtension rewrites the response content in AfterGenerate
+type mutatingExtension struct {
+	extensions.BaseExtension
+	name   string
+	mutate func(resp *extensions.GenerateResponse)
+}
+
+func (m *mutatingExtension) Name() string                                   { return m.name }
+func (m *mutatingExtension) Version() string                                { return "1.0.0" }
+func (m *mutatingExtension) Description() string                            { return "Mutates responses" }
+func (m *mutatingExtension) Initialize(config map[string]interface{}) error { return nil }
+
+func (m *mutatingExtension) AfterGenerate(ctx context.Context, req *extensions.GenerateRequest, resp *extensions.GenerateResponse) error {
+	m.mutate(resp)
+	return nil
+}
+
+func TestGenerateHandler_Generate_AfterGenerateMutatesResponse(t *testing.T) {
+	provider := &mockProvider{
+		name:         "test",
+		defaultModel: "test-model",
+		generateResponse: &types.ChatCompletionChunk{
+			Content: "The password is hunter2",
+		},
+	}
+	providers := map[string]types.Provider{"test": provider}
+
+	registry := &mockExtensionRegistry{}
+	_ = registry.Register(&mutatingExtension{name: "redact", mutate: func(resp *extensions.GenerateResponse) {
+		resp.Content = strings.ReplaceAll(resp.Content, "hunter2", "[REDACTED]")
+	}})
+	_ = registry.Register(&mutatingExtension{name: "disclaimer", mutate: func(resp *extensions.GenerateResponse) {
+		resp.Content += " (AI-generated)"
+	}})
+
+	handler := NewGenerateHandler(providers, registry, "test")
+
+	body, _ := json.Marshal(backendtypes.GenerateRequest{Prompt: "Test prompt"})
+	w := httptest.NewRecorder()
+	r := newRequestWithContext("POST", "/api/generate", body)
+
+	handler.Generate(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		Data backendtypes.GenerateResponse `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// Hooks run in order, each seeing the previous hook's changes
+	expected := "The password is [REDACTED] (AI-generated)"
+	if response.Data.Content != expected {
+		t.Errorf("Expected content %q, got %q", expected, response.Data.Content)
+	}
+}
+
 func TestConvertFunctions(t *testing.T) {
 	// Test convertToExtensionRequest
 	req := &backendtypes.GenerateRequest{
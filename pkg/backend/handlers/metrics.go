@@ -12,6 +12,7 @@ import (
 // MetricsHandler handles metrics endpoints
 type MetricsHandler struct {
 	providers map[string]types.Provider
+	collector types.MetricsCollector
 	startTime time.Time
 }
 
@@ -23,6 +24,13 @@ func NewMetricsHandler(providers map[string]types.Provider) *MetricsHandler {
 	}
 }
 
+// WithMetricsCollector sets the collector whose per-model breakdown GetMetricsSnapshot
+// includes
+func (h *MetricsHandler) WithMetricsCollector(collector types.MetricsCollector) *MetricsHandler {
+	h.collector = collector
+	return h
+}
+
 // ProviderMetricsResponse represents the response for provider metrics
 type ProviderMetricsResponse struct {
 	Providers map[string]types.ProviderMetrics `json:"providers"`
@@ -46,6 +54,74 @@ type RacingMetricsResponse struct {
 	Timestamp       time.Time                                   `json:"timestamp"`
 }
 
+// MetricsSnapshotResponse is the JSON metrics document returned by GetMetricsSnapshot
+type MetricsSnapshotResponse struct {
+	Uptime        string                           `json:"uptime"`
+	UptimeSeconds int64                            `json:"uptime_seconds"`
+	Totals        MetricsTotals                    `json:"totals"`
+	Providers     map[string]types.ProviderMetrics `json:"providers"`
+
+	// Models is the per-model breakdown of the handler's metrics collector, if it has one
+	Models map[string]*types.ModelMetricsSnapshot `json:"models,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MetricsTotals aggregates the metrics of all providers
+type MetricsTotals struct {
+	RequestCount   int64         `json:"request_count"`
+	SuccessCount   int64         `json:"success_count"`
+	ErrorCount     int64         `json:"error_count"`
+	SuccessRate    float64       `json:"success_rate"` // Calculated: successes/requests
+	TokensUsed     int64         `json:"tokens_used"`
+	AverageLatency time.Duration `json:"average_latency"` // Over all providers' successful requests
+}
+
+// GetMetricsSnapshot handles GET /api/metrics
+// Returns every provider's metrics, their totals and the server uptime as one JSON
+// document, for dashboards and debugging without Prometheus. It only reads counters,
+// so it is cheap to poll.
+func (h *MetricsHandler) GetMetricsSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		SendError(w, r, "METHOD_NOT_ALLOWED", "Only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	providerMetrics := make(map[string]types.ProviderMetrics, len(h.providers))
+	var totals MetricsTotals
+	var totalLatency time.Duration
+	for name, provider := range h.providers {
+		metrics := provider.GetMetrics()
+		providerMetrics[name] = metrics
+
+		totals.RequestCount += metrics.RequestCount
+		totals.SuccessCount += metrics.SuccessCount
+		totals.ErrorCount += metrics.ErrorCount
+		totals.TokensUsed += metrics.TokensUsed
+		totalLatency += metrics.TotalLatency
+	}
+	if totals.RequestCount > 0 {
+		totals.SuccessRate = float64(totals.SuccessCount) / float64(totals.RequestCount)
+	}
+	if totals.SuccessCount > 0 {
+		totals.AverageLatency = totalLatency / time.Duration(totals.SuccessCount)
+	}
+
+	uptime := time.Since(h.startTime)
+	response := MetricsSnapshotResponse{
+		Uptime:        uptime.String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Totals:        totals,
+		Providers:     providerMetrics,
+		Timestamp:     time.Now(),
+	}
+	if h.collector != nil {
+		response.Models = h.collector.GetSnapshot().ModelBreakdown
+	}
+
+	SendSuccess(w, r, response)
+}
+
 // GetProviderMetrics handles GET /api/metrics/providers
 // Returns metrics for all registered providers
 func (h *MetricsHandler) GetProviderMetrics(w http.ResponseWriter, r *http.Request) {
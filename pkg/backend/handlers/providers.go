@@ -128,6 +128,10 @@ func (h *ProviderHandler) UpdateProvider(w http.ResponseWriter, r *http.Request)
 	newConfig.SupportsResponsesAPI = currentConfig.SupportsResponsesAPI
 	newConfig.MaxTokens = currentConfig.MaxTokens
 	newConfig.Timeout = currentConfig.Timeout
+	newConfig.ConnectTimeout = currentConfig.ConnectTimeout
+	newConfig.RequestTimeout = currentConfig.RequestTimeout
+	newConfig.HedgeAfter = currentConfig.HedgeAfter
+	newConfig.CompressRequests = currentConfig.CompressRequests
 	newConfig.ToolFormat = currentConfig.ToolFormat
 
 	// Apply new configuration
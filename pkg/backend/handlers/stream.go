@@ -68,7 +68,7 @@ func (h *StreamHandler) StreamGenerate(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	// Process and send stream chunks
-	fullContent, usage, streamErr := h.processStreamChunks(stream, sseWriter)
+	fullContent, usage, streamErr := h.processStreamChunks(ctx, req, stream, sseWriter)
 	if streamErr != nil {
 		return // Error already sent via SSE
 	}
@@ -183,10 +183,13 @@ func (h *StreamHandler) runProviderErrorHooks(ctx context.Context, provider type
 	}
 }
 
-// processStreamChunks processes all chunks from the stream and sends them via SSE
-func (h *StreamHandler) processStreamChunks(stream types.ChatCompletionStream, sseWriter *SSEWriter) (string, *backendtypes.UsageInfo, error) {
+// processStreamChunks processes all chunks from the stream and sends them via SSE.
+// OnChunk extension hooks run before each chunk is sent; a hook error aborts the stream.
+func (h *StreamHandler) processStreamChunks(ctx context.Context, req *backendtypes.GenerateRequest, stream types.ChatCompletionStream, sseWriter *SSEWriter) (string, *backendtypes.UsageInfo, error) {
 	var fullContent string
 	var usage *backendtypes.UsageInfo
+	extReq := convertToExtensionRequest(req)
+	hooks := chunkHooks(h.extensions)
 
 	for {
 		chunk, err := stream.Next()
@@ -200,6 +203,11 @@ func (h *StreamHandler) processStreamChunks(stream types.ChatCompletionStream, s
 			break
 		}
 
+		if err := runChunkHooks(ctx, hooks, extReq, &chunk); err != nil {
+			sseWriter.WriteError("EXTENSION_ERROR", "OnChunk hook failed: "+err.Error())
+			return "", nil, err
+		}
+
 		fullContent += h.extractChunkContent(&chunk)
 
 		if err := sseWriter.WriteChunk(chunk); err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backend/handlers"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backend/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backendtypes"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -20,18 +21,32 @@ type Server struct {
 	httpServer *http.Server
 	providers  map[string]types.Provider
 	extensions extensions.ExtensionRegistry
+	metrics    types.MetricsCollector
 	mux        *http.ServeMux
 }
 
-// NewServer creates a new backend server with the given configuration and providers
+// NewServer creates a new backend server with the given configuration and providers.
+// Providers that accept a metrics collector and don't have one yet report to the
+// server's, whose per-model breakdown is served by /api/metrics. A collector already
+// set, e.g. by the provider factory, is kept.
 func NewServer(config backendtypes.BackendConfig, providers map[string]types.Provider) *Server {
 	s := &Server{
 		config:     config,
 		providers:  providers,
 		extensions: extensions.NewRegistry(),
+		metrics:    metrics.NewDefaultMetricsCollector(),
 		mux:        http.NewServeMux(),
 	}
 
+	for _, provider := range providers {
+		if current, ok := provider.(interface{ GetMetricsCollector() types.MetricsCollector }); ok && current.GetMetricsCollector() != nil {
+			continue
+		}
+		if metricProvider, ok := provider.(interface{ SetMetricsCollector(types.MetricsCollector) }); ok {
+			metricProvider.SetMetricsCollector(s.metrics)
+		}
+	}
+
 	// Initialize extensions if configured
 	if len(config.Extensions) > 0 {
 		// Convert backendtypes.ExtensionConfig to extensions.ExtensionConfig
@@ -58,6 +73,7 @@ func (s *Server) setupRoutes() {
 	// Create handlers
 	healthHandler := handlers.NewHealthHandler(s.providers, s.config.Server.Version)
 	providerHandler := handlers.NewProviderHandler(s.providers)
+	metricsHandler := handlers.NewMetricsHandler(s.providers).WithMetricsCollector(s.metrics)
 
 	// Determine default provider (first one in the map if not specified)
 	defaultProvider := ""
@@ -72,6 +88,9 @@ func (s *Server) setupRoutes() {
 	s.mux.HandleFunc("/status", healthHandler.Status)
 	s.mux.HandleFunc("/version", healthHandler.Version)
 
+	// Metrics endpoints
+	s.mux.HandleFunc("/api/metrics", metricsHandler.GetMetricsSnapshot)
+
 	// Provider management endpoints
 	s.mux.HandleFunc("/api/providers", providerHandler.ListProviders)
 	s.mux.HandleFunc("/api/providers/", s.routeProviderRequests(providerHandler))
@@ -212,6 +231,11 @@ func (s *Server) GetProviders() map[string]types.Provider {
 	return s.providers
 }
 
+// GetMetricsCollector returns the metrics collector the providers report to
+func (s *Server) GetMetricsCollector() types.MetricsCollector {
+	return s.metrics
+}
+
 // GetConfig returns the server configuration
 func (s *Server) GetConfig() backendtypes.BackendConfig {
 	return s.config
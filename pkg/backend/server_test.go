@@ -2,14 +2,17 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/backendtypes"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockProvider is a mock implementation of types.Provider for testing
@@ -827,3 +830,67 @@ func BenchmarkServer_MiddlewareChain(b *testing.B) {
 		handler.ServeHTTP(w, req)
 	}
 }
+
+// collectingProvider records the metrics collector it is given
+type collectingProvider struct {
+	*MockProvider
+	collector types.MetricsCollector
+}
+
+func (p *collectingProvider) SetMetricsCollector(collector types.MetricsCollector) {
+	p.collector = collector
+}
+
+func (p *collectingProvider) GetMetricsCollector() types.MetricsCollector {
+	return p.collector
+}
+
+// TestNewServer_KeepsProviderMetricsCollector tests that NewServer doesn't replace a
+// collector the provider already reports to
+func TestNewServer_KeepsProviderMetricsCollector(t *testing.T) {
+	own := metrics.NewDefaultMetricsCollector()
+	configured := &collectingProvider{MockProvider: NewMockProvider("openai", types.ProviderTypeOpenAI), collector: own}
+	unconfigured := &collectingProvider{MockProvider: NewMockProvider("anthropic", types.ProviderTypeAnthropic)}
+
+	server := NewServer(backendtypes.BackendConfig{}, map[string]types.Provider{
+		"openai":    configured,
+		"anthropic": unconfigured,
+	})
+
+	assert.Same(t, own, configured.collector)
+	assert.Same(t, server.GetMetricsCollector(), unconfigured.collector)
+}
+
+// TestServer_MetricsEndpoint tests that /api/metrics serves the per-model breakdown
+// of the collector the providers report to
+func TestServer_MetricsEndpoint(t *testing.T) {
+	provider := &collectingProvider{MockProvider: NewMockProvider("openai", types.ProviderTypeOpenAI)}
+	server := NewServer(backendtypes.BackendConfig{}, map[string]types.Provider{"openai": provider})
+	require.NotNil(t, provider.collector)
+	assert.Same(t, server.GetMetricsCollector(), provider.collector)
+
+	require.NoError(t, provider.collector.RecordEvent(context.Background(), types.MetricEvent{
+		Type:         types.MetricEventSuccess,
+		ProviderName: "openai",
+		ProviderType: types.ProviderTypeOpenAI,
+		ModelID:      "gpt-4o",
+		Timestamp:    time.Now(),
+		Latency:      100 * time.Millisecond,
+		TokensUsed:   42,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/metrics", nil)
+	w := httptest.NewRecorder()
+	server.mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Models map[string]types.ModelMetricsSnapshot `json:"models"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Contains(t, response.Data.Models, "gpt-4o")
+	assert.Equal(t, int64(1), response.Data.Models["gpt-4o"].SuccessfulRequests)
+	assert.Equal(t, int64(42), response.Data.Models["gpt-4o"].Tokens.TotalTokens)
+}
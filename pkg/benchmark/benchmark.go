@@ -0,0 +1,229 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
+)
+
+// Config is the workload of a benchmark
+type Config struct {
+	// Prompts are sent in order, once per iteration
+	Prompts []string
+
+	// Iterations is how many times each prompt is sent; zero sends each once
+	Iterations int
+
+	// Warmup requests with the first prompt are sent before measuring and left out of
+	// the results, so connection setup doesn't skew the first samples
+	Warmup int
+
+	// Options is the request template. Each request is a copy with Prompt set to one
+	// of Prompts and Stream enabled, so time to first token can be measured.
+	Options types.GenerateOptions
+
+	// CostCalculator prices each request by its token usage. Nil reports no cost.
+	CostCalculator metrics.CostCalculator
+}
+
+// Sample is the measurement of one request
+type Sample struct {
+	Prompt    int `json:"prompt"` // Index into Config.Prompts
+	Iteration int `json:"iteration"`
+
+	// TimeToFirstToken is the time from sending the request to the first content,
+	// reasoning or tool call delta
+	TimeToFirstToken time.Duration `json:"time_to_first_token"`
+	Latency          time.Duration `json:"latency"` // Until the end of the stream
+
+	// TokensPerSecond is the completion tokens over the time after the first token,
+	// or over the whole latency when the response arrived at once
+	TokensPerSecond float64 `json:"tokens_per_second"`
+
+	Usage types.Usage `json:"usage"`
+
+	// EstimatedUsage is true when the provider reported no usage for a side of the
+	// request and it was estimated from the prompt and the streamed text
+	EstimatedUsage bool `json:"estimated_usage,omitempty"`
+
+	Cost  float64 `json:"cost"`
+	Error string  `json:"error,omitempty"` // Set when the request failed
+}
+
+// Stats summarizes the samples of a measure
+type Stats[T time.Duration | float64] struct {
+	Count int `json:"count"`
+	Mean  T   `json:"mean"`
+	Min   T   `json:"min"`
+	Max   T   `json:"max"`
+	P50   T   `json:"p50"`
+	P90   T   `json:"p90"`
+	P95   T   `json:"p95"`
+	P99   T   `json:"p99"`
+}
+
+// Result is the outcome of a benchmark. Provider is the provider's Name and Model the
+// requested model, or the provider's default. Statistics cover the successful samples.
+type Result struct {
+	Provider string   `json:"provider"`
+	Model    string   `json:"model"`
+	Samples  []Sample `json:"samples"`
+	Failures int      `json:"failures"`
+
+	TimeToFirstToken Stats[time.Duration] `json:"time_to_first_token"`
+	Latency          Stats[time.Duration] `json:"latency"`
+	TokensPerSecond  Stats[float64]       `json:"tokens_per_second"`
+	Cost             Stats[float64]       `json:"cost"` // Per request
+
+	TotalCost float64 `json:"total_cost"`
+	Currency  string  `json:"currency,omitempty"`
+}
+
+// Run benchmarks provider on the workload of config. Requests are sent one at a
+// time, iteration by iteration, each iteration sending every prompt in order.
+//
+// Failed requests are recorded in Result.Samples and Result.Failures. When ctx is
+// cancelled Run stops and returns the result of the samples taken so far with the
+// context's error.
+func Run(ctx context.Context, provider types.ChatProvider, config Config) (*Result, error) {
+	return newRunner(time.Now).run(ctx, provider, config)
+}
+
+// runner holds the clock of a benchmark, replaced in tests
+type runner struct {
+	now func() time.Time
+}
+
+func newRunner(now func() time.Time) *runner {
+	return &runner{now: now}
+}
+
+func (r *runner) run(ctx context.Context, provider types.ChatProvider, config Config) (*Result, error) {
+	if len(config.Prompts) == 0 {
+		return nil, types.NewValidationError("benchmark requires at least one prompt")
+	}
+	iterations := config.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	result := &Result{Model: config.Options.Model}
+	if named, ok := provider.(interface{ Name() string }); ok {
+		result.Provider = named.Name()
+	}
+	if defaults, ok := provider.(interface{ GetDefaultModel() string }); ok && result.Model == "" {
+		result.Model = defaults.GetDefaultModel()
+	}
+
+	for i := 0; i < config.Warmup; i++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		r.measure(ctx, provider, config, config.Prompts[0])
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		for index, prompt := range config.Prompts {
+			if err := ctx.Err(); err != nil {
+				result.summarize()
+				return result, err
+			}
+			sample := r.measure(ctx, provider, config, prompt)
+			sample.Prompt = index
+			sample.Iteration = iteration
+			if sample.Error == "" && config.CostCalculator != nil {
+				cost := config.CostCalculator.CalculateCost(result.Provider, result.Model,
+					int64(sample.Usage.PromptTokens), int64(sample.Usage.CompletionTokens))
+				sample.Cost = cost.TotalCost
+				result.Currency = cost.Currency
+			}
+			result.Samples = append(result.Samples, sample)
+		}
+	}
+
+	result.summarize()
+	return result, nil
+}
+
+// measure sends one request and times its stream
+func (r *runner) measure(ctx context.Context, provider types.ChatProvider, config Config, prompt string) Sample {
+	var sample Sample
+
+	options := config.Options
+	options.Prompt = prompt
+	options.Stream = true
+
+	start := r.now()
+	stream, err := provider.GenerateChatCompletion(ctx, options)
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+
+	var usage types.Usage
+	var streamed int
+	err = types.StreamToCallback(utils.CountStreamTokens(stream), func(chunk types.ChatCompletionChunk) error {
+		if sample.TimeToFirstToken == 0 && chunk.TokenCount.Delta > 0 {
+			sample.TimeToFirstToken = r.now().Sub(start)
+		}
+		streamed = chunk.TokenCount.Total
+		if chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 {
+			usage = chunk.Usage
+		}
+		return nil
+	})
+	sample.Latency = r.now().Sub(start)
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+	if sample.TimeToFirstToken == 0 {
+		sample.TimeToFirstToken = sample.Latency
+	}
+
+	if usage.PromptTokens == 0 {
+		usage.PromptTokens = utils.EstimateTokensFromString(prompt)
+		sample.EstimatedUsage = true
+	}
+	if usage.CompletionTokens == 0 && streamed > 0 {
+		usage.CompletionTokens = streamed
+		sample.EstimatedUsage = true
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	sample.Usage = usage
+
+	generation := sample.Latency - sample.TimeToFirstToken
+	if generation <= 0 {
+		generation = sample.Latency
+	}
+	if generation > 0 {
+		sample.TokensPerSecond = float64(usage.CompletionTokens) / generation.Seconds()
+	}
+	return sample
+}
+
+// summarize computes the statistics of the successful samples
+func (r *Result) summarize() {
+	var ttft, latency []time.Duration
+	var speed, cost []float64
+	r.Failures = 0
+	r.TotalCost = 0
+	for _, sample := range r.Samples {
+		if sample.Error != "" {
+			r.Failures++
+			continue
+		}
+		ttft = append(ttft, sample.TimeToFirstToken)
+		latency = append(latency, sample.Latency)
+		speed = append(speed, sample.TokensPerSecond)
+		cost = append(cost, sample.Cost)
+		r.TotalCost += sample.Cost
+	}
+	r.TimeToFirstToken = summarize(ttft)
+	r.Latency = summarize(latency)
+	r.TokensPerSecond = summarize(speed)
+	r.Cost = summarize(cost)
+}
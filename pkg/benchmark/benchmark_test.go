@@ -0,0 +1,188 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is advanced by the streams of clockedProvider
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// clockedProvider streams its chunks, advancing the clock by firstDelay before the
+// first and by chunkDelay before each of the others
+type clockedProvider struct {
+	clock      *fakeClock
+	chunks     []types.ChatCompletionChunk
+	firstDelay time.Duration
+	chunkDelay time.Duration
+	failOn     string // Prompt whose requests fail
+	prompts    []string
+}
+
+func (p *clockedProvider) GenerateChatCompletion(_ context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+	p.prompts = append(p.prompts, options.Prompt)
+	if options.Prompt == p.failOn {
+		return nil, errors.New("rate limited")
+	}
+	return &clockedStream{provider: p}, nil
+}
+
+func (p *clockedProvider) Name() string { return "clocked" }
+
+type clockedStream struct {
+	provider *clockedProvider
+	index    int
+}
+
+func (s *clockedStream) Next() (types.ChatCompletionChunk, error) {
+	if s.index >= len(s.provider.chunks) {
+		return types.ChatCompletionChunk{}, io.EOF
+	}
+	if s.index == 0 {
+		s.provider.clock.now = s.provider.clock.now.Add(s.provider.firstDelay)
+	} else {
+		s.provider.clock.now = s.provider.clock.now.Add(s.provider.chunkDelay)
+	}
+	chunk := s.provider.chunks[s.index]
+	s.index++
+	return chunk, nil
+}
+
+func (s *clockedStream) Close() error { return nil }
+
+// flatPricing charges per token, regardless of model
+type flatPricing struct{}
+
+func (flatPricing) CalculateCost(_, _ string, inputTokens, outputTokens int64) metrics.Cost {
+	input, output := float64(inputTokens)*0.001, float64(outputTokens)*0.002
+	return metrics.Cost{InputCost: input, OutputCost: output, TotalCost: input + output, Currency: "USD"}
+}
+
+func (flatPricing) GetPricing(_, _ string) (float64, float64, bool) {
+	return 1, 2, true
+}
+
+func TestRun(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	provider := &clockedProvider{
+		clock:      clock,
+		firstDelay: 200 * time.Millisecond,
+		chunkDelay: 100 * time.Millisecond,
+		chunks: []types.ChatCompletionChunk{
+			{Content: "Hello"},
+			{Content: " there"},
+			{Done: true, Usage: types.Usage{PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30}},
+		},
+	}
+
+	result, err := newRunner(clock.Now).run(context.Background(), provider, Config{
+		Prompts:        []string{"a", "b"},
+		Iterations:     3,
+		Warmup:         1,
+		Options:        types.GenerateOptions{Model: "test-model"},
+		CostCalculator: flatPricing{},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "a", "b", "a", "b", "a", "b"}, provider.prompts)
+	assert.Equal(t, "clocked", result.Provider)
+	assert.Equal(t, "test-model", result.Model)
+	require.Len(t, result.Samples, 6)
+	assert.Equal(t, 1, result.Samples[3].Prompt)
+	assert.Equal(t, 1, result.Samples[3].Iteration)
+	assert.Zero(t, result.Failures)
+
+	sample := result.Samples[0]
+	assert.Equal(t, 200*time.Millisecond, sample.TimeToFirstToken)
+	assert.Equal(t, 400*time.Millisecond, sample.Latency)
+	assert.InDelta(t, 100, sample.TokensPerSecond, 0.001) // 20 tokens in 200ms
+	assert.False(t, sample.EstimatedUsage)
+	assert.InDelta(t, 0.05, sample.Cost, 1e-9)
+
+	assert.Equal(t, 6, result.Latency.Count)
+	assert.Equal(t, 400*time.Millisecond, result.Latency.P50)
+	assert.Equal(t, 200*time.Millisecond, result.TimeToFirstToken.P99)
+	assert.InDelta(t, 0.3, result.TotalCost, 1e-9)
+	assert.Equal(t, "USD", result.Currency)
+}
+
+func TestRun_EstimatesMissingUsage(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	provider := &clockedProvider{
+		clock:      clock,
+		firstDelay: time.Second,
+		chunks:     []types.ChatCompletionChunk{{Content: "A reply of some length.", Done: true}},
+	}
+
+	result, err := newRunner(clock.Now).run(context.Background(), provider, Config{Prompts: []string{"Hello there"}})
+	require.NoError(t, err)
+	require.Len(t, result.Samples, 1)
+
+	sample := result.Samples[0]
+	assert.True(t, sample.EstimatedUsage)
+	assert.Positive(t, sample.Usage.PromptTokens)
+	assert.Positive(t, sample.Usage.CompletionTokens)
+
+	// The whole response arrived at once, so the speed is over the whole latency
+	assert.Equal(t, time.Second, sample.TimeToFirstToken)
+	assert.InDelta(t, float64(sample.Usage.CompletionTokens), sample.TokensPerSecond, 0.001)
+	assert.Zero(t, sample.Cost)
+}
+
+func TestRun_Failures(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	provider := &clockedProvider{
+		clock:      clock,
+		firstDelay: time.Second,
+		chunks:     []types.ChatCompletionChunk{{Content: "ok", Done: true}},
+		failOn:     "bad",
+	}
+
+	result, err := newRunner(clock.Now).run(context.Background(), provider, Config{Prompts: []string{"good", "bad"}, Iterations: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Failures)
+	assert.Equal(t, "rate limited", result.Samples[1].Error)
+	assert.Equal(t, 2, result.Latency.Count)
+	assert.Equal(t, time.Second, result.Latency.Mean)
+}
+
+func TestRun_Validation(t *testing.T) {
+	_, err := Run(context.Background(), &clockedProvider{}, Config{})
+	assert.True(t, types.IsValidationError(err))
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := Run(ctx, &clockedProvider{clock: &fakeClock{}}, Config{Prompts: []string{"a"}})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.Empty(t, result.Samples)
+}
+
+func TestSummarize(t *testing.T) {
+	stats := summarize([]float64{4, 1, 3, 2, 5})
+	assert.Equal(t, 5, stats.Count)
+	assert.Equal(t, 3.0, stats.Mean)
+	assert.Equal(t, 1.0, stats.Min)
+	assert.Equal(t, 5.0, stats.Max)
+	assert.Equal(t, 3.0, stats.P50)
+	assert.InDelta(t, 4.6, stats.P90, 1e-9)
+
+	assert.Equal(t, Stats[time.Duration]{}, summarize[time.Duration](nil))
+}
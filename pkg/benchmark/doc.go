@@ -0,0 +1,18 @@
+// Package benchmark measures providers on a workload: time to first token, total
+// latency, generation speed and cost, summarized as percentiles over repeated runs.
+//
+// Runs are sequential and in a fixed order, so results are comparable between
+// providers and between benchmark sessions:
+//
+//	result, err := benchmark.Run(ctx, provider, benchmark.Config{
+//	    Prompts:        []string{"Summarize this ticket: ...", "Write a SQL query that ..."},
+//	    Iterations:     5,
+//	    Options:        types.GenerateOptions{Model: "gpt-4o-mini", MaxTokens: 256},
+//	    CostCalculator: myPricing,
+//	})
+//	fmt.Printf("TTFT p50 %v, p95 %v, %.1f tokens/s, $%.4f total\n",
+//	    result.TimeToFirstToken.P50, result.TimeToFirstToken.P95,
+//	    result.TokensPerSecond.Mean, result.TotalCost)
+//
+// A run that fails is recorded with its error and left out of the statistics.
+package benchmark
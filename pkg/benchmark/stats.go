@@ -0,0 +1,44 @@
+package benchmark
+
+import (
+	"slices"
+	"time"
+)
+
+// summarize computes the statistics of samples
+func summarize[T time.Duration | float64](samples []T) Stats[T] {
+	if len(samples) == 0 {
+		return Stats[T]{}
+	}
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	var total float64
+	for _, sample := range sorted {
+		total += float64(sample)
+	}
+
+	return Stats[T]{
+		Count: len(sorted),
+		Mean:  T(total / float64(len(sorted))),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P95:   percentile(sorted, 95),
+		P99:   percentile(sorted, 99),
+	}
+}
+
+// percentile returns the value at p percent of sorted, interpolating linearly
+// between the closest ranks as the metrics histogram does
+func percentile[T time.Duration | float64](sorted []T, p int) T {
+	rank := float64(p) / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank - float64(lower)
+	return T(float64(sorted[lower]) + fraction*float64(sorted[lower+1]-sorted[lower]))
+}
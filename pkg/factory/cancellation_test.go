@@ -0,0 +1,105 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAIEvents are the first events of an OpenAI-compatible stream
+const openAIEvents = "data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" world\"}}]}\n\n"
+
+// TestProviders_StreamCancellation checks that cancelling the context mid-stream aborts
+// the in-flight HTTP request and makes Next return the context error promptly, even
+// when further events have already been received
+func TestProviders_StreamCancellation(t *testing.T) {
+	tests := []struct {
+		providerType types.ProviderType
+		model        string
+		events       string
+	}{
+		{types.ProviderTypeOpenAI, "gpt-4o", openAIEvents},
+		{types.ProviderTypeCerebras, "llama3.1-8b", openAIEvents},
+		{types.ProviderTypeOpenRouter, "openai/gpt-4o", openAIEvents},
+		{types.ProviderTypeQwen, "qwen-plus", openAIEvents},
+		{types.ProviderTypeAnthropic, "claude-sonnet-4-20250514", "event: message_start\n" +
+			"data: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"model\":\"claude-sonnet-4-20250514\",\"usage\":{\"input_tokens\":1}}}\n\n" +
+			"event: content_block_start\n" +
+			"data: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n" +
+			"event: content_block_delta\n" +
+			"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hello\"}}\n\n" +
+			"event: content_block_delta\n" +
+			"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\" world\"}}\n\n"},
+		{types.ProviderTypeGemini, "gemini-1.5-pro", "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hello\"}]}}]}\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\" world\"}]}}]}\n\n"},
+		{types.ProviderTypeOllama, "llama3.1:8b", "{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\"Hello\"},\"done\":false}\n" +
+			"{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\" world\"},\"done\":false}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.providerType), func(t *testing.T) {
+			disconnected := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					http.NotFound(w, r) // e.g. OpenRouter's key lookup
+					return
+				}
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.WriteHeader(http.StatusOK)
+				_, _ = fmt.Fprint(w, tt.events)
+				w.(http.Flusher).Flush()
+
+				// Hold the stream open until the client goes away
+				select {
+				case <-r.Context().Done():
+					close(disconnected)
+				case <-time.After(10 * time.Second):
+				}
+			}))
+			defer server.Close()
+
+			factory := NewProviderFactory()
+			RegisterDefaultProviders(factory)
+			provider, err := factory.CreateProvider(tt.providerType, types.ProviderConfig{
+				Type:    tt.providerType,
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream, err := provider.GenerateChatCompletion(ctx, types.GenerateOptions{
+				Model:    tt.model,
+				Messages: []types.ChatMessage{{Role: "user", Content: "Hi"}},
+				Stream:   true,
+			})
+			require.NoError(t, err)
+			defer func() { _ = stream.Close() }()
+
+			_, err = stream.Next()
+			require.NoError(t, err)
+
+			cancel()
+			start := time.Now()
+			_, err = stream.Next()
+			assert.True(t, errors.Is(err, context.Canceled), "Next after cancel returned %v", err)
+			assert.Less(t, time.Since(start), time.Second, "Next must return promptly")
+
+			select {
+			case <-disconnected:
+			case <-time.After(2 * time.Second):
+				t.Error("the HTTP request was not aborted")
+			}
+		})
+	}
+}
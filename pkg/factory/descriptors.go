@@ -0,0 +1,127 @@
+package factory
+
+import (
+	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// DescribeProvider returns the configuration fields providerType reads, their types and
+// which are required, so config validation and UIs can be driven by data rather than
+// knowledge of each provider. Use ProviderDescriptor.Validate to check a config against
+// it. Unknown provider types get a descriptor without auth methods or fields.
+func DescribeProvider(providerType types.ProviderType) types.ProviderDescriptor {
+	descriptor := types.ProviderDescriptor{Type: providerType}
+
+	switch providerType {
+	case types.ProviderTypeOpenAI, types.ProviderTypeCerebras, types.ProviderTypeOpenRouter, types.ProviderTypexAI,
+		types.ProviderTypeDeepseek:
+		descriptor.AuthMethods = []types.AuthMethod{types.AuthMethodAPIKey}
+		descriptor.Fields = append(descriptor.Fields, apiKeyField(true))
+	case types.ProviderTypeAnthropic, types.ProviderTypeQwen:
+		descriptor.AuthMethods = []types.AuthMethod{types.AuthMethodAPIKey, types.AuthMethodOAuth}
+		descriptor.Fields = append(descriptor.Fields, apiKeyField(true), oauthField())
+	case types.ProviderTypeGemini:
+		descriptor.AuthMethods = []types.AuthMethod{types.AuthMethodAPIKey, types.AuthMethodOAuth}
+		descriptor.Fields = append(descriptor.Fields, apiKeyField(true), oauthField(), types.ConfigField{
+			Name:        "provider_config.project_id",
+			Type:        types.ConfigFieldString,
+			Description: "Google Cloud project used by the Cloud Code API",
+			Required:    true,
+			AuthMethod:  types.AuthMethodOAuth,
+			EnvVar:      "GOOGLE_CLOUD_PROJECT",
+		})
+	case types.ProviderTypeOllama, types.ProviderTypeLMStudio, types.ProviderTypeLlamaCpp:
+		// Local model servers need no credentials
+		descriptor.Fields = append(descriptor.Fields, apiKeyField(false))
+	case types.ProviderTypeFallback, types.ProviderTypeLoadBalance:
+		descriptor.Fields = append(descriptor.Fields, types.ConfigField{
+			Name:        "provider_config.providers",
+			Type:        types.ConfigFieldList,
+			Description: "Names of the providers to route requests to, in order",
+			Required:    true,
+		})
+		if providerType == types.ProviderTypeFallback {
+			descriptor.Fields = append(descriptor.Fields, types.ConfigField{
+				Name:        "provider_config.max_retries",
+				Type:        types.ConfigFieldInt,
+				Description: "Providers tried before giving up",
+				Default:     "3",
+			})
+		} else {
+			descriptor.Fields = append(descriptor.Fields, types.ConfigField{
+				Name:        "provider_config.strategy",
+				Type:        types.ConfigFieldString,
+				Description: "How requests are spread across providers",
+				Default:     "round_robin",
+			})
+		}
+		return descriptor
+	case types.ProviderTypeRacing:
+		descriptor.Fields = append(descriptor.Fields,
+			types.ConfigField{
+				Name:        "provider_config.virtual_models",
+				Type:        types.ConfigFieldObject,
+				Description: "Virtual models, each naming the providers and models that race for it",
+				Required:    true,
+			},
+			types.ConfigField{
+				Name:        "provider_config.timeout_ms",
+				Type:        types.ConfigFieldInt,
+				Description: "How long to wait for a winning response, in milliseconds",
+				Default:     "5000",
+			},
+		)
+		return descriptor
+	default:
+		return descriptor
+	}
+
+	return withEndpointFields(descriptor)
+}
+
+// withEndpointFields adds the fields every concrete provider accepts
+func withEndpointFields(descriptor types.ProviderDescriptor) types.ProviderDescriptor {
+	baseURL := commonconfig.NewConfigHelper(string(descriptor.Type), descriptor.Type).ExtractBaseURL(types.ProviderConfig{})
+	if descriptor.Type == types.ProviderTypeOllama {
+		baseURL = "http://localhost:11434"
+	}
+
+	descriptor.Fields = append(descriptor.Fields,
+		types.ConfigField{
+			Name:        "base_url",
+			Type:        types.ConfigFieldURL,
+			Description: "API endpoint",
+			Required:    baseURL == "",
+			Default:     baseURL,
+		},
+		types.ConfigField{
+			Name:        "default_model",
+			Type:        types.ConfigFieldString,
+			Description: "Model used when a request names none",
+		},
+	)
+	return descriptor
+}
+
+func apiKeyField(required bool) types.ConfigField {
+	field := types.ConfigField{
+		Name:        "api_key",
+		Type:        types.ConfigFieldSecret,
+		Description: "API key",
+		Required:    required,
+	}
+	if required {
+		field.AuthMethod = types.AuthMethodAPIKey
+	}
+	return field
+}
+
+func oauthField() types.ConfigField {
+	return types.ConfigField{
+		Name:        "oauth_credentials",
+		Type:        types.ConfigFieldOAuthSet,
+		Description: "OAuth credential sets, tried in order with failover",
+		Required:    true,
+		AuthMethod:  types.AuthMethodOAuth,
+	}
+}
@@ -0,0 +1,54 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeProvider(t *testing.T) {
+	gemini := DescribeProvider(types.ProviderTypeGemini)
+	assert.Equal(t, []types.AuthMethod{types.AuthMethodAPIKey, types.AuthMethodOAuth}, gemini.AuthMethods)
+
+	projectID, ok := gemini.Field("provider_config.project_id")
+	require.True(t, ok)
+	assert.True(t, projectID.Required)
+	assert.Equal(t, types.AuthMethodOAuth, projectID.AuthMethod)
+
+	baseURL, ok := gemini.Field("base_url")
+	require.True(t, ok)
+	assert.False(t, baseURL.Required)
+	assert.Equal(t, "https://generativelanguage.googleapis.com/v1beta", baseURL.Default)
+
+	ollama := DescribeProvider(types.ProviderTypeOllama)
+	assert.Empty(t, ollama.AuthMethods)
+	apiKey, ok := ollama.Field("api_key")
+	require.True(t, ok)
+	assert.False(t, apiKey.Required)
+
+	unknown := DescribeProvider("unknown")
+	assert.Empty(t, unknown.Fields)
+}
+
+func TestDescribeProvider_CoversRegisteredProviders(t *testing.T) {
+	factory := NewProviderFactory()
+	RegisterDefaultProviders(factory)
+
+	for _, providerType := range factory.GetSupportedProviders() {
+		assert.NotEmpty(t, DescribeProvider(providerType).Fields, "no descriptor for %s", providerType)
+	}
+}
+
+func TestDescribeProvider_Validate(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+
+	assert.NoError(t, DescribeProvider(types.ProviderTypeOpenAI).Validate(types.ProviderConfig{APIKey: "sk-test"}))
+	assert.Error(t, DescribeProvider(types.ProviderTypeOpenAI).Validate(types.ProviderConfig{}))
+	assert.NoError(t, DescribeProvider(types.ProviderTypeOllama).Validate(types.ProviderConfig{}))
+
+	oauth := types.ProviderConfig{OAuthCredentials: []*types.OAuthCredentialSet{{ID: "default"}}}
+	assert.NoError(t, DescribeProvider(types.ProviderTypeAnthropic).Validate(oauth))
+	assert.ErrorContains(t, DescribeProvider(types.ProviderTypeGemini).Validate(oauth), "project_id")
+}
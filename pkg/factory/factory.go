@@ -5,9 +5,13 @@ package factory
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
 	"sync"
 	"time"
 
+	commonerrors "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/errors"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -16,16 +20,41 @@ type DefaultProviderFactory struct {
 	providers        map[types.ProviderType]func(types.ProviderConfig) types.Provider
 	mutex            sync.RWMutex
 	metricsCollector types.MetricsCollector
+	middlewareChain  middleware.MiddlewareChain
+	transportMetrics map[types.ProviderType]*middleware.HTTPMetrics
 }
 
 // NewProviderFactory creates a new provider factory
 func NewProviderFactory() *DefaultProviderFactory {
 	return &DefaultProviderFactory{
-		providers: make(map[types.ProviderType]func(types.ProviderConfig) types.Provider),
-		mutex:     sync.RWMutex{},
+		providers:        make(map[types.ProviderType]func(types.ProviderConfig) types.Provider),
+		mutex:            sync.RWMutex{},
+		transportMetrics: make(map[types.ProviderType]*middleware.HTTPMetrics),
 	}
 }
 
+// SetMiddlewareChain sets the middleware chain run by the default transport of the
+// providers this factory creates from now on; see CreateProvider
+func (f *DefaultProviderFactory) SetMiddlewareChain(chain middleware.MiddlewareChain) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.middlewareChain = chain
+}
+
+// TransportMetrics returns the HTTP metrics recorded by the default transports of the
+// providers of a type this factory created, and false if it created none. They count
+// HTTP round trips, while a provider's GetMetrics counts calls to the provider.
+func (f *DefaultProviderFactory) TransportMetrics(providerType types.ProviderType) (types.ProviderMetrics, bool) {
+	f.mutex.RLock()
+	metrics, ok := f.transportMetrics[providerType]
+	f.mutex.RUnlock()
+
+	if !ok {
+		return types.ProviderMetrics{}, false
+	}
+	return metrics.Snapshot(), true
+}
+
 // SetMetricsCollector sets the metrics collector for the factory
 // When set, all providers created by this factory will have the collector configured
 func (f *DefaultProviderFactory) SetMetricsCollector(collector types.MetricsCollector) {
@@ -42,17 +71,39 @@ func (f *DefaultProviderFactory) RegisterProvider(providerType types.ProviderTyp
 	f.providers[providerType] = factoryFunc
 }
 
-// CreateProvider creates a provider instance
+// CreateProvider creates a provider instance.
+//
+// Unless config.WrapTransport is set, the provider's HTTP client gets an instrumented
+// transport (see middleware.InstrumentedTransport) that sets the standard middleware
+// context keys, runs the factory's middleware chain, records TransportMetrics and masks
+// credentials in transport errors.
 func (f *DefaultProviderFactory) CreateProvider(providerType types.ProviderType, config types.ProviderConfig) (types.Provider, error) {
 	f.mutex.RLock()
 	factoryFunc, exists := f.providers[providerType]
 	collector := f.metricsCollector
+	chain := f.middlewareChain
 	f.mutex.RUnlock()
 
 	if !exists {
 		return nil, fmt.Errorf("provider type %s not registered", providerType)
 	}
 
+	if config.WrapTransport == nil {
+		name := config.Name
+		if name == "" {
+			name = string(providerType)
+		}
+		metrics := f.httpMetrics(providerType)
+		config.WrapTransport = func(base http.RoundTripper) http.RoundTripper {
+			return middleware.NewInstrumentedTransport(base, middleware.InstrumentedTransportConfig{
+				Provider: name,
+				Chain:    chain,
+				Masker:   transportMasker(),
+				Metrics:  metrics,
+			})
+		}
+	}
+
 	provider := factoryFunc(config)
 
 	// If a metrics collector is configured and the provider supports it, set it
@@ -65,6 +116,46 @@ func (f *DefaultProviderFactory) CreateProvider(providerType types.ProviderType,
 	return provider, nil
 }
 
+// transportMasker returns the credential masker of default transports: the default
+// patterns plus credentials in query parameters, such as Gemini's key parameter
+func transportMasker() *commonerrors.DefaultMasker {
+	masker := commonerrors.DefaultCredentialMasker()
+	masker.AddPattern(
+		regexp.MustCompile(`([?&])(key|api[_-]?key|access[_-]?token|token)=([^&\s]+)`),
+		"$1$2=***MASKED***",
+	)
+	return masker
+}
+
+// httpMetrics returns the transport metrics shared by providers of a type
+func (f *DefaultProviderFactory) httpMetrics(providerType types.ProviderType) *middleware.HTTPMetrics {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	metrics, ok := f.transportMetrics[providerType]
+	if !ok {
+		metrics = &middleware.HTTPMetrics{}
+		f.transportMetrics[providerType] = metrics
+	}
+	return metrics
+}
+
+// CreateImageProvider creates a provider instance and returns its image generation capability
+// Returns an error if the provider type is not registered or does not support image generation
+func (f *DefaultProviderFactory) CreateImageProvider(providerType types.ProviderType, config types.ProviderConfig) (types.ImageProvider, error) {
+	provider, err := f.CreateProvider(providerType, config)
+	if err != nil {
+		return nil, err
+	}
+
+	imageProvider, ok := provider.(types.ImageProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider type %s does not support image generation", providerType)
+	}
+
+	return imageProvider, nil
+}
+
 // GetSupportedProviders returns all supported provider types
 func (f *DefaultProviderFactory) GetSupportedProviders() []types.ProviderType {
 	f.mutex.RLock()
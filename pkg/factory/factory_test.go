@@ -114,6 +114,37 @@ func TestDefaultProviderFactory_CreateProvider_UnknownProvider(t *testing.T) {
 	assert.Contains(t, err.Error(), "provider type unknown-provider not registered")
 }
 
+// TestDefaultProviderFactory_CreateImageProvider tests creation of image-capable providers
+func TestDefaultProviderFactory_CreateImageProvider(t *testing.T) {
+	factory := NewProviderFactory()
+	RegisterDefaultProviders(factory)
+
+	t.Run("SupportedProvider", func(t *testing.T) {
+		imageProvider, err := factory.CreateImageProvider(types.ProviderTypeOpenAI, types.ProviderConfig{
+			Type:   types.ProviderTypeOpenAI,
+			APIKey: "sk-test-key",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, imageProvider)
+	})
+
+	t.Run("UnsupportedProvider", func(t *testing.T) {
+		imageProvider, err := factory.CreateImageProvider(types.ProviderTypeLMStudio, types.ProviderConfig{
+			Type: types.ProviderTypeLMStudio,
+		})
+		assert.Error(t, err)
+		assert.Nil(t, imageProvider)
+		assert.Contains(t, err.Error(), "does not support image generation")
+	})
+
+	t.Run("UnknownProvider", func(t *testing.T) {
+		imageProvider, err := factory.CreateImageProvider(types.ProviderType("unknown"), types.ProviderConfig{})
+		assert.Error(t, err)
+		assert.Nil(t, imageProvider)
+		assert.Contains(t, err.Error(), "not registered")
+	})
+}
+
 // TestDefaultProviderFactory_CreateProvider_ConcurrentAccess tests thread safety of provider creation
 func TestDefaultProviderFactory_CreateProvider_ConcurrentAccess(t *testing.T) {
 	factory := NewProviderFactory()
@@ -0,0 +1,59 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// CreateHealthyProvider creates a provider and verifies that it can serve requests
+// before returning it, so a misconfigured provider fails at startup rather than on its
+// first request. The provider is configured with config, authenticated with
+// config.APIKey when one is set, and health checked; providers that implement
+// types.TestableProvider also have their connectivity tested. Any failure is returned,
+// naming the step that failed, instead of the provider.
+func (f *DefaultProviderFactory) CreateHealthyProvider(ctx context.Context, providerType types.ProviderType, config types.ProviderConfig) (types.Provider, error) {
+	provider, err := f.CreateProvider(providerType, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := provider.Configure(config); err != nil {
+		return nil, fmt.Errorf("provider %s: configure: %w", providerType, err)
+	}
+
+	if config.APIKey != "" {
+		authConfig := types.AuthConfig{
+			Method:       types.AuthMethodAPIKey,
+			APIKey:       config.APIKey,
+			BaseURL:      config.BaseURL,
+			DefaultModel: config.DefaultModel,
+		}
+		if err := provider.Authenticate(ctx, authConfig); err != nil {
+			return nil, fmt.Errorf("provider %s: authenticate: %w", providerType, err)
+		}
+	}
+
+	if err := provider.HealthCheck(ctx); err != nil {
+		return nil, fmt.Errorf("provider %s: health check: %w", providerType, err)
+	}
+
+	if testable, ok := provider.(types.TestableProvider); ok {
+		if err := testable.TestConnectivity(ctx); err != nil {
+			return nil, fmt.Errorf("provider %s: connectivity test: %w", providerType, err)
+		}
+	}
+
+	return provider, nil
+}
+
+// CreateHealthyProvider creates a provider of a default provider type (see
+// RegisterDefaultProviders) and verifies it as DefaultProviderFactory.CreateHealthyProvider
+// does.
+func CreateHealthyProvider(ctx context.Context, providerType types.ProviderType, config types.ProviderConfig) (types.Provider, error) {
+	factory := NewProviderFactory()
+	RegisterDefaultProviders(factory)
+
+	return factory.CreateHealthyProvider(ctx, providerType, config)
+}
@@ -0,0 +1,75 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unhealthyProvider is a MockProvider whose authentication, health check or
+// connectivity test can be made to fail
+type unhealthyProvider struct {
+	MockProvider
+	authErr         error
+	healthErr       error
+	connectivityErr error
+	authConfig      *types.AuthConfig
+}
+
+func (p *unhealthyProvider) Authenticate(ctx context.Context, authConfig types.AuthConfig) error {
+	p.authConfig = &authConfig
+	return p.authErr
+}
+func (p *unhealthyProvider) HealthCheck(ctx context.Context) error      { return p.healthErr }
+func (p *unhealthyProvider) TestConnectivity(ctx context.Context) error { return p.connectivityErr }
+
+func TestDefaultProviderFactory_CreateHealthyProvider(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		provider *unhealthyProvider
+		wantErr  string
+	}{
+		{name: "healthy", provider: &unhealthyProvider{}},
+		{name: "authentication fails", provider: &unhealthyProvider{authErr: errFailed}, wantErr: "authenticate"},
+		{name: "health check fails", provider: &unhealthyProvider{healthErr: errFailed}, wantErr: "health check"},
+		{name: "unreachable", provider: &unhealthyProvider{connectivityErr: errFailed}, wantErr: "connectivity test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewProviderFactory()
+			factory.RegisterProvider(types.ProviderTypeOpenAI, func(config types.ProviderConfig) types.Provider {
+				return tt.provider
+			})
+
+			config := types.ProviderConfig{Type: types.ProviderTypeOpenAI, APIKey: "sk-test"}
+			provider, err := factory.CreateHealthyProvider(context.Background(), types.ProviderTypeOpenAI, config)
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.Same(t, tt.provider, provider)
+				require.NotNil(t, tt.provider.authConfig)
+				assert.Equal(t, "sk-test", tt.provider.authConfig.APIKey)
+				assert.Equal(t, config, tt.provider.GetConfig())
+				return
+			}
+			assert.Nil(t, provider)
+			assert.ErrorIs(t, err, errFailed)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDefaultProviderFactory_CreateHealthyProvider_UnknownProvider(t *testing.T) {
+	factory := NewProviderFactory()
+
+	provider, err := factory.CreateHealthyProvider(context.Background(), types.ProviderTypeOpenAI, types.ProviderConfig{})
+	assert.Nil(t, provider)
+	assert.Error(t, err)
+}
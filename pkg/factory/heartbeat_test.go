@@ -0,0 +1,115 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAIHeartbeatEvents interleave an OpenAI-compatible stream with keep-alive
+// comments, a role-only delta and empty deltas, around whitespace-only content
+const openAIHeartbeatEvents = ": keep-alive\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\"}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\n" +
+	": keep-alive\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"\"}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" \"}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"world\"}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"\\n\"}}]}\n\n" +
+	"data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+	"data: [DONE]\n\n"
+
+// TestProviders_StreamHeartbeats checks that keep-alive comments and empty deltas are
+// not returned as chunks, while whitespace-only content is
+func TestProviders_StreamHeartbeats(t *testing.T) {
+	tests := []struct {
+		providerType types.ProviderType
+		model        string
+		events       string
+	}{
+		{types.ProviderTypeOpenAI, "gpt-4o", openAIHeartbeatEvents},
+		{types.ProviderTypeCerebras, "llama3.1-8b", openAIHeartbeatEvents},
+		{types.ProviderTypeOpenRouter, "openai/gpt-4o", openAIHeartbeatEvents},
+		{types.ProviderTypeQwen, "qwen-plus", openAIHeartbeatEvents},
+		{types.ProviderTypeAnthropic, "claude-sonnet-4-20250514", "event: content_block_start\n" +
+			"data: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n" +
+			"event: ping\ndata: {\"type\":\"ping\"}\n\n" +
+			"event: content_block_delta\n" +
+			"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hello\"}}\n\n" +
+			"event: content_block_delta\n" +
+			"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\" \"}}\n\n" +
+			"event: ping\ndata: {\"type\":\"ping\"}\n\n" +
+			"event: content_block_delta\n" +
+			"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"world\"}}\n\n" +
+			"event: content_block_delta\n" +
+			"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"\\n\"}}\n\n" +
+			"event: content_block_stop\ndata: {\"type\":\"content_block_stop\",\"index\":0}\n\n" +
+			"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"},
+		{types.ProviderTypeGemini, "gemini-1.5-pro", ": keep-alive\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hello\"}]}}]}\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"\"}]}}]}\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\" \"}]}}]}\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"world\"}]}}]}\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"\\n\"}]}}]}\n\n" +
+			"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"\"}]},\"finishReason\":\"STOP\"}]}\n\n"},
+		{types.ProviderTypeOllama, "llama3.1:8b", "{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\"Hello\"},\"done\":false}\n" +
+			"{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\"\"},\"done\":false}\n" +
+			"{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\" \"},\"done\":false}\n" +
+			"{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\"world\"},\"done\":false}\n" +
+			"{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\"\\n\"},\"done\":false}\n" +
+			"{\"model\":\"llama3.1:8b\",\"message\":{\"role\":\"assistant\",\"content\":\"\"},\"done\":true}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.providerType), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = fmt.Fprint(w, tt.events)
+			}))
+			defer server.Close()
+
+			factory := NewProviderFactory()
+			RegisterDefaultProviders(factory)
+			provider, err := factory.CreateProvider(tt.providerType, types.ProviderConfig{
+				Type:    tt.providerType,
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+			require.NoError(t, err)
+
+			stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+				Model:    tt.model,
+				Messages: []types.ChatMessage{{Role: "user", Content: "Hi"}},
+				Stream:   true,
+			})
+			require.NoError(t, err)
+			defer func() { _ = stream.Close() }()
+
+			var contents []string
+			for {
+				chunk, err := stream.Next()
+				finished := chunk.Done || (len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != "")
+				if !finished {
+					contents = append(contents, chunk.Content)
+				}
+				if err == io.EOF || chunk.Done {
+					break
+				}
+				require.NoError(t, err)
+			}
+			assert.Equal(t, []string{"Hello", " ", "world", "\n"}, contents)
+		})
+	}
+}
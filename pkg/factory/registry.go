@@ -5,6 +5,7 @@ package factory
 import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/anthropic"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/cerebras"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/deepseek"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/gemini"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/ollama"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/openai"
@@ -13,6 +14,7 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/virtual/fallback"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/virtual/loadbalance"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/virtual/racing"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/xai"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -59,6 +61,16 @@ func registerPrimaryProviders(factory *DefaultProviderFactory) {
 	factory.RegisterProvider(types.ProviderTypeOllama, func(config types.ProviderConfig) types.Provider {
 		return ollama.NewOllamaProvider(config)
 	})
+
+	// Register xAI Grok provider, built on the OpenAI implementation
+	factory.RegisterProvider(types.ProviderTypexAI, func(config types.ProviderConfig) types.Provider {
+		return xai.NewXAIProvider(config)
+	})
+
+	// Register DeepSeek provider, built on the OpenAI implementation
+	factory.RegisterProvider(types.ProviderTypeDeepseek, func(config types.ProviderConfig) types.Provider {
+		return deepseek.NewDeepSeekProvider(config)
+	})
 }
 
 // registerStubProviders registers stub providers for local/model-server providers
@@ -34,6 +34,8 @@ func TestRegisterDefaultProviders(t *testing.T) {
 		types.ProviderTypeLMStudio,
 		types.ProviderTypeLlamaCpp,
 		types.ProviderTypeOllama,
+		types.ProviderTypexAI,
+		types.ProviderTypeDeepseek,
 		types.ProviderTypeRacing,
 		types.ProviderTypeFallback,
 		types.ProviderTypeLoadBalance,
@@ -154,6 +156,26 @@ func TestRegisterDefaultProviders_ProviderCreation(t *testing.T) {
 			},
 			expectName: "Ollama",
 		},
+		{
+			name:         "Grok Provider",
+			providerType: types.ProviderTypeGrok,
+			config: types.ProviderConfig{
+				Type:   types.ProviderTypeGrok,
+				Name:   "grok-test",
+				APIKey: "xai-test-key",
+			},
+			expectName: "xAI",
+		},
+		{
+			name:         "DeepSeek Provider",
+			providerType: types.ProviderTypeDeepseek,
+			config: types.ProviderConfig{
+				Type:   types.ProviderTypeDeepseek,
+				Name:   "deepseek-test",
+				APIKey: "deepseek-test-key",
+			},
+			expectName: "DeepSeek",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -177,7 +199,7 @@ func TestRegisterDefaultProviders_DuplicateRegistration(t *testing.T) {
 
 	// Should still have the same number of providers (no duplicates)
 	supportedProviders := factory.GetSupportedProviders()
-	expectedCount := 12 // Number of default providers (9 regular + 3 virtual)
+	expectedCount := 14 // Number of default providers (11 regular + 3 virtual)
 	assert.Len(t, supportedProviders, expectedCount)
 }
 
@@ -200,7 +222,7 @@ func TestRegisterDefaultProviders_ConcurrentAccess(t *testing.T) {
 
 	// Verify providers were registered correctly (no duplicates)
 	supportedProviders := factory.GetSupportedProviders()
-	expectedCount := 12 // Number of default providers (9 regular + 3 virtual)
+	expectedCount := 14 // Number of default providers (11 regular + 3 virtual)
 	assert.Len(t, supportedProviders, expectedCount)
 
 	// Verify we can still create providers
@@ -244,6 +266,8 @@ func TestRegisterDefaultProviders_MixedWithCustomProviders(t *testing.T) {
 		types.ProviderTypeLMStudio,
 		types.ProviderTypeLlamaCpp,
 		types.ProviderTypeOllama,
+		types.ProviderTypexAI,
+		types.ProviderTypeDeepseek,
 	}
 
 	for _, providerType := range defaultProviders {
@@ -251,7 +275,7 @@ func TestRegisterDefaultProviders_MixedWithCustomProviders(t *testing.T) {
 	}
 
 	// Verify total count
-	assert.Len(t, supportedProviders, 13) // 12 default + 1 custom
+	assert.Len(t, supportedProviders, 15) // 14 default + 1 custom
 }
 
 // TestInitializeDefaultProviders_vs_RegisterDefaultProviders tests both initialization methods
@@ -275,7 +299,7 @@ func TestInitializeDefaultProviders_vs_RegisterDefaultProviders(t *testing.T) {
 	}
 	assert.Len(t, providers1, len(expectedStubProviders))
 
-	// RegisterDefaultProviders should have all providers (12 total)
+	// RegisterDefaultProviders should have all providers (14 total)
 	expectedAllProviders := []types.ProviderType{
 		types.ProviderTypeOpenAI,
 		types.ProviderTypeAnthropic,
@@ -284,6 +308,8 @@ func TestInitializeDefaultProviders_vs_RegisterDefaultProviders(t *testing.T) {
 		types.ProviderTypeCerebras,
 		types.ProviderTypeOpenRouter,
 		types.ProviderTypeOllama,
+		types.ProviderTypexAI,
+		types.ProviderTypeDeepseek,
 		types.ProviderTypeLMStudio,
 		types.ProviderTypeLlamaCpp,
 		types.ProviderTypeRacing,
@@ -318,6 +344,8 @@ func TestInitializeDefaultProviders_vs_RegisterDefaultProviders(t *testing.T) {
 		types.ProviderTypeCerebras,
 		types.ProviderTypeOpenRouter,
 		types.ProviderTypeOllama,
+		types.ProviderTypexAI,
+		types.ProviderTypeDeepseek,
 	}
 
 	for _, realProvider := range realImplementationProviders {
@@ -0,0 +1,122 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateProvider_DefaultTransport checks that providers send requests through an
+// instrumented transport running the factory's middleware chain
+func TestCreateProvider_DefaultTransport(t *testing.T) {
+	tests := []struct {
+		providerType types.ProviderType
+		model        string
+		response     string
+	}{
+		{types.ProviderTypeOpenAI, "gpt-4o", `{"id":"1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}]}`},
+		{types.ProviderTypeCerebras, "llama3.1-8b", `{"id":"1","model":"llama3.1-8b","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}]}`},
+		{types.ProviderTypeAnthropic, "claude-sonnet-4-20250514", `{"id":"1","type":"message","role":"assistant","model":"claude-sonnet-4-20250514","content":[{"type":"text","text":"Hi"}],"stop_reason":"end_turn"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.providerType), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			var mu sync.Mutex
+			var seen []context.Context
+			chain := middleware.NewMiddlewareChain()
+			chain.Add(middleware.RequestMiddlewareFunc(func(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				seen = append(seen, ctx)
+				return ctx, req, nil
+			}))
+
+			factory := NewProviderFactory()
+			RegisterDefaultProviders(factory)
+			factory.SetMiddlewareChain(chain)
+			provider, err := factory.CreateProvider(tt.providerType, types.ProviderConfig{
+				Type:    tt.providerType,
+				Name:    "primary",
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+			require.NoError(t, err)
+
+			stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+				Model:    tt.model,
+				Messages: []types.ChatMessage{{Role: "user", Content: "Hi"}},
+			})
+			require.NoError(t, err)
+			_ = stream.Close()
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.NotEmpty(t, seen)
+			ctx := seen[len(seen)-1]
+			assert.NotEmpty(t, ctx.Value(middleware.ContextKeyRequestID))
+			assert.Equal(t, "primary", ctx.Value(middleware.ContextKeyProvider))
+			assert.Equal(t, tt.model, ctx.Value(middleware.ContextKeyModel))
+
+			metrics, ok := factory.TransportMetrics(tt.providerType)
+			require.True(t, ok)
+			assert.Equal(t, int64(len(seen)), metrics.RequestCount)
+			assert.Equal(t, metrics.RequestCount, metrics.SuccessCount)
+		})
+	}
+}
+
+func TestCreateProvider_WrapTransportOverridesDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"id":"1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	wrapped := 0
+	factory := NewProviderFactory()
+	RegisterDefaultProviders(factory)
+	provider, err := factory.CreateProvider(types.ProviderTypeOpenAI, types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+		WrapTransport: func(base http.RoundTripper) http.RoundTripper {
+			return middleware.NewTransport(base, middleware.NewMiddlewareChain().Add(
+				middleware.RequestMiddlewareFunc(func(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+					wrapped++
+					return ctx, req, nil
+				})))
+		},
+	})
+	require.NoError(t, err)
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Model:    "gpt-4o",
+		Messages: []types.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	require.NoError(t, err)
+	_ = stream.Close()
+
+	assert.Positive(t, wrapped)
+	_, ok := factory.TransportMetrics(types.ProviderTypeOpenAI)
+	assert.False(t, ok)
+}
+
+func TestTransportMasker(t *testing.T) {
+	masked := transportMasker().MaskString(`Post "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent?key=AIza-test_key&alt=sse": EOF`)
+	assert.NotContains(t, masked, "AIza-test_key")
+	assert.Contains(t, masked, "key=***MASKED***&alt=sse")
+}
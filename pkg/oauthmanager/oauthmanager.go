@@ -153,6 +153,11 @@ func (m *OAuthKeyManager) ExecuteWithFailover(
 	attemptsLimit := min(len(credentials), 3) // Try up to 3 credentials or all, whichever is less
 
 	for attempt := 0; attempt < attemptsLimit; attempt++ {
+		// Failing over to another credential is a retry of the request
+		if attempt > 0 && !types.AcquireRetry(ctx) {
+			return "", nil, fmt.Errorf("%s: retry budget exhausted after %d attempts, last error: %w", m.providerName, attempt, lastErr)
+		}
+
 		// Get next available credential
 		cred, err := m.GetNextCredential(ctx)
 		if err != nil {
@@ -408,6 +413,11 @@ func (m *OAuthKeyManager) ExecuteWithFailoverMessage(
 	attemptsLimit := min(len(credentials), 3) // Try up to 3 credentials or all, whichever is less
 
 	for attempt := 0; attempt < attemptsLimit; attempt++ {
+		// Failing over to another credential is a retry of the request
+		if attempt > 0 && !types.AcquireRetry(ctx) {
+			return types.ChatMessage{}, nil, fmt.Errorf("%s: retry budget exhausted after %d attempts, last error: %w", m.providerName, attempt, lastErr)
+		}
+
 		// Get next available credential
 		cred, err := m.GetNextCredential(ctx)
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -238,6 +239,23 @@ func TestOAuthKeyManager_ExecuteWithFailover(t *testing.T) {
 			t.Error("ExecuteWithFailover() expected error when all credentials fail")
 		}
 	})
+
+	t.Run("failover takes retries from the budget", func(t *testing.T) {
+		callCount := 0
+		operation := func(ctx context.Context, cred *types.OAuthCredentialSet) (string, *types.Usage, error) {
+			callCount++
+			return "", nil, errors.New("operation failed")
+		}
+
+		budgetCtx := types.WithRetryBudget(ctx, types.NewRetryBudget(1))
+		_, _, err := NewOAuthKeyManager("TestProvider", credentials, nil).ExecuteWithFailover(budgetCtx, operation)
+		if callCount != 2 {
+			t.Errorf("operation called %d times, expected the budget to allow one failover", callCount)
+		}
+		if err == nil || !strings.Contains(err.Error(), "retry budget exhausted") || !strings.Contains(err.Error(), "operation failed") {
+			t.Errorf("expected the budget error wrapping the last error, got: %v", err)
+		}
+	})
 }
 
 // TestOAuthKeyManager_HealthTracking tests health tracking and backoff
@@ -18,10 +18,13 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
 )
 
 // Constants for Anthropic models
@@ -57,6 +60,11 @@ type AnthropicConfig struct {
 	BaseURL       string   `json:"base_url,omitempty"`
 	Model         string   `json:"model,omitempty"`
 	OAuthClientID string   `json:"oauth_client_id,omitempty"`
+
+	// Header management; see HeaderConfig
+	AnthropicVersion string   `json:"anthropic_version,omitempty"`
+	Betas            []string `json:"betas,omitempty"`
+	Context1M        bool     `json:"context_1m,omitempty"`
 }
 
 // NewAnthropicProvider creates a new Anthropic provider
@@ -69,7 +77,13 @@ func NewAnthropicProvider(config types.ProviderConfig) *AnthropicProvider {
 
 	// Create HTTP client using internal/http package
 	httpClient := pkghttp.NewHTTPClient(pkghttp.HTTPClientConfig{
-		Timeout: configHelper.ExtractTimeout(mergedConfig),
+		Timeout:          configHelper.ExtractTimeout(mergedConfig),
+		ConnectTimeout:   mergedConfig.ConnectTimeout,
+		RequestTimeout:   mergedConfig.RequestTimeout,
+		Headers:          mergedConfig.Headers,
+		UserAgent:        mergedConfig.UserAgent,
+		CompressRequests: mergedConfig.CompressRequests,
+		WrapTransport:    mergedConfig.WrapTransport,
 	})
 
 	// Extract the underlying http.Client for compatibility with existing code
@@ -128,6 +142,16 @@ func NewAnthropicProvider(config types.ProviderConfig) *AnthropicProvider {
 	return provider
 }
 
+// applyHeaders sets the anthropic-version and anthropic-beta headers a request needs,
+// after the auth helper's defaults. request is the Messages body, or nil.
+func (p *AnthropicProvider) applyHeaders(req *http.Request, request *AnthropicRequest) {
+	HeaderConfig{
+		Version:   p.config.AnthropicVersion,
+		Betas:     p.config.Betas,
+		Context1M: p.config.Context1M,
+	}.SetHeaders(req, request)
+}
+
 func (p *AnthropicProvider) Name() string {
 	if p.displayName != "" {
 		return p.displayName
@@ -378,6 +402,10 @@ func (p *AnthropicProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+
 	log.Printf("🟣 [Anthropic] GenerateChatCompletion ENTRY - options.Model=%s, options.Stream=%v", options.Model, options.Stream)
 	log.Printf("🟣 [Anthropic] authHelper=%p, OAuthManager=%p, KeyManager=%p",
 		p.authHelper,
@@ -413,11 +441,23 @@ func (p *AnthropicProvider) GenerateChatCompletion(
 		log.Printf("🟣 [Anthropic] Using specified model: %s", model)
 	}
 
-	// Check rate limits before making request
-	maxTokens := options.MaxTokens
-	if maxTokens == 0 {
-		maxTokens = 4096 // Default max tokens
+	// Validate before any network I/O
+	if err := common.ValidateGenerateOptions(model, options); err != nil {
+		return nil, err
 	}
+	ctx = middleware.WithModel(ctx, model)
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return nil, err
+	}
+	if err := validateSystemMessagePlacement(options.Messages); err != nil {
+		return nil, err
+	}
+	if err := validateAnthropicOptions(options); err != nil {
+		return nil, err
+	}
+
+	// Check rate limits before making request
+	maxTokens := common.ResolveMaxTokens(p.Type(), model, options.MaxTokens)
 
 	p.rateLimitHelper.CheckRateLimitAndWait(model, maxTokens)
 
@@ -487,18 +527,30 @@ func (p *AnthropicProvider) GenerateChatCompletion(
 	}
 
 	chunk := types.ChatCompletionChunk{
-		Content: responseMessage.Content,
-		Done:    true,
-		Usage:   usageValue,
+		Content:   responseMessage.Content,
+		Citations: responseMessage.Citations,
+		Done:      true,
+		Usage:     usageValue,
 	}
 
-	// Include tool calls if present
-	if len(responseMessage.ToolCalls) > 0 {
-		chunk.Choices = []types.ChatChoice{
-			{
-				Message: responseMessage,
-			},
-		}
+	if uses, ok := responseMessage.Metadata[metadataKeyServerToolUses].([]types.ServerToolUse); ok {
+		chunk.ServerToolUses = uses
+	}
+	stopReason, _ := responseMessage.Metadata[metadataKeyStopReason].(string)
+	chunk.Warnings, _ = responseMessage.Metadata[metadataKeyWarnings].([]types.Warning)
+	delete(responseMessage.Metadata, metadataKeyServerToolUses)
+	delete(responseMessage.Metadata, metadataKeyStopReason)
+	delete(responseMessage.Metadata, metadataKeyWarnings)
+	if len(responseMessage.Metadata) == 0 {
+		responseMessage.Metadata = nil
+	}
+
+	chunk.Choices = []types.ChatChoice{
+		{
+			Message:         responseMessage,
+			FinishReason:    anthropicFinishReason(stopReason, responseMessage.ToolCalls),
+			RawFinishReason: stopReason,
+		},
 	}
 
 	return streaming.NewMockStream([]types.ChatCompletionChunk{chunk}), nil
@@ -506,10 +558,7 @@ func (p *AnthropicProvider) GenerateChatCompletion(
 
 // executeStreamWithAuth handles streaming requests with authentication
 func (p *AnthropicProvider) executeStreamWithAuth(ctx context.Context, options types.GenerateOptions, model string) (types.ChatCompletionStream, error) {
-	maxTokens := options.MaxTokens
-	if maxTokens == 0 {
-		maxTokens = 4096 // Default max tokens
-	}
+	maxTokens := common.ResolveMaxTokens(p.Type(), model, options.MaxTokens)
 	requestData := p.prepareRequest(options, model, maxTokens)
 	requestData.Stream = true
 
@@ -524,6 +573,11 @@ func (p *AnthropicProvider) executeStreamWithAuth(ctx context.Context, options t
 		creds := p.authHelper.OAuthManager.GetCredentials()
 		var lastErr error
 		for _, cred := range creds {
+			// Failing over to another credential is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICallWithOAuth(ctx, requestData, cred.AccessToken)
 			if err == nil {
 				return stream, nil
@@ -543,6 +597,11 @@ func (p *AnthropicProvider) executeStreamWithAuth(ctx context.Context, options t
 		keys := p.authHelper.KeyManager.GetKeys()
 		var lastErr error
 		for _, apiKey := range keys {
+			// Failing over to another key is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICallWithKey(ctx, requestData, apiKey)
 			if err == nil {
 				return stream, nil
@@ -559,6 +618,17 @@ func (p *AnthropicProvider) executeStreamWithAuth(ctx context.Context, options t
 		WithOperation("executeStreamWithAuth")
 }
 
+// validateSystemMessagePlacement checks that system messages only appear before the
+// conversation starts. Anthropic takes the system prompt as a top-level field, so leading
+// system messages are hoisted into it (joined with blank lines); a system message after a
+// user or assistant message has no equivalent position and is rejected.
+func validateSystemMessagePlacement(messages []types.ChatMessage) error {
+	if err := utils.ValidateLeadingSystemMessages(messages, types.ProviderTypeAnthropic); err != nil {
+		return types.NewValidationError(err.Error())
+	}
+	return nil
+}
+
 // prepareRequest prepares the API request payload
 func (p *AnthropicProvider) prepareRequest(options types.GenerateOptions, model string, maxTokens int) AnthropicRequest {
 	log.Printf("🔧 [Anthropic] prepareRequest ENTRY - model=%s, Messages count=%d, Prompt=%q", model, len(options.Messages), options.Prompt)
@@ -576,11 +646,20 @@ func (p *AnthropicProvider) prepareRequest(options types.GenerateOptions, model
 	// Anthropic API requires system prompts in a separate System field, not in Messages
 	if len(options.Messages) > 0 {
 		log.Printf("🔧 [Anthropic] Processing %d messages", len(options.Messages))
-		for i, msg := range options.Messages {
+		// A trailing assistant message is a prefill the model continues. The API rejects
+		// one ending in whitespace, or empty, so it is trimmed and dropped if nothing is left.
+		conversation := options.Messages
+		if prefill, ok := options.Prefill(); ok {
+			conversation = options.WithoutPrefill().Messages
+			if prefill = strings.TrimRight(prefill, " \t\r\n"); prefill != "" {
+				conversation = append(conversation, types.ChatMessage{Role: "assistant", Content: prefill})
+			}
+		}
+		for i, msg := range conversation {
 			log.Printf("🔧 [Anthropic] Message %d: role=%s, content_length=%d", i, msg.Role, len(msg.Content))
 			if msg.Role == "system" {
 				// Collect system message content to add to System field
-				systemPrompts = append(systemPrompts, msg.Content)
+				systemPrompts = append(systemPrompts, msg.GetTextContent())
 				log.Printf("🟠 [Anthropic] Extracted system message from Messages array: %.100s...", msg.Content)
 			} else {
 				// Add non-system messages to the messages array
@@ -671,6 +750,11 @@ func (p *AnthropicProvider) prepareRequest(options types.GenerateOptions, model
 
 	log.Printf("🔧 [Anthropic] Request prepared: model=%s, messages_count=%d, has_system=%v", model, len(messages), systemField != nil)
 
+	// Add Anthropic-defined tools; invalid provider options are rejected by GenerateChatCompletion
+	if anthropicOpts, _ := anthropicOptionsFrom(options); anthropicOpts != nil {
+		request.ServerTools = anthropicOpts.ServerTools
+	}
+
 	// Convert tools if provided
 	if len(options.Tools) > 0 {
 		request.Tools = convertToAnthropicTools(options.Tools)
@@ -747,6 +831,7 @@ func (p *AnthropicProvider) makeAPICallWithKey(ctx context.Context, requestData
 	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
 	p.authHelper.SetProviderSpecificHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
+	p.applyHeaders(req, &requestData)
 
 	p.LogRequest("POST", url, map[string]string{
 		"Content-Type":      "application/json",
@@ -757,7 +842,7 @@ func (p *AnthropicProvider) makeAPICallWithKey(ctx context.Context, requestData
 	// Make the request
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("request failed: %w", err)
+		return nil, nil, types.NewRequestError(types.ProviderTypeAnthropic, "request failed", err)
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -771,13 +856,8 @@ func (p *AnthropicProvider) makeAPICallWithKey(ctx context.Context, requestData
 
 	// Check status code using response parser
 	if resp.StatusCode != http.StatusOK {
-		// Read body for error message
 		body, _ := io.ReadAll(resp.Body)
-		var errorResponse AnthropicErrorResponse
-		if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-			return nil, nil, fmt.Errorf("anthropic API error: %d - %s", resp.StatusCode, errorResponse.Error.Message)
-		}
-		return nil, nil, fmt.Errorf("anthropic API error: %d - %s", resp.StatusCode, string(body))
+		return nil, nil, newAPIError(resp, body, "makeAPICallWithKey")
 	}
 
 	// Parse successful response using response parser
@@ -791,11 +871,10 @@ func (p *AnthropicProvider) makeAPICallWithKey(ctx context.Context, requestData
 	}
 
 	// Convert usage to standard format
-	usage := &types.Usage{
-		PromptTokens:     response.Usage.InputTokens,
-		CompletionTokens: response.Usage.OutputTokens,
-		TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
-	}
+	converted := anthropicUsage(response.Usage)
+	usage := &converted
+
+	response.Warnings = responseWarnings(resp.Header, requestData.Model, response.Model)
 
 	return &response, usage, nil
 }
@@ -849,6 +928,7 @@ func (p *AnthropicProvider) makeAPICallWithOAuthMessage(ctx context.Context, req
 	p.authHelper.SetAuthHeaders(req, accessToken, "oauth")
 	p.authHelper.SetProviderSpecificHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
+	p.applyHeaders(req, &requestData)
 
 	p.LogRequest("POST", url, map[string]string{
 		"Content-Type":      "application/json",
@@ -859,7 +939,7 @@ func (p *AnthropicProvider) makeAPICallWithOAuthMessage(ctx context.Context, req
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return types.ChatMessage{}, nil, fmt.Errorf("request failed: %w", err)
+		return types.ChatMessage{}, nil, types.NewRequestError(types.ProviderTypeAnthropic, "request failed", err)
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -874,11 +954,7 @@ func (p *AnthropicProvider) makeAPICallWithOAuthMessage(ctx context.Context, req
 	// Check status code and parse response
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		var errorResponse AnthropicErrorResponse
-		if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-			return types.ChatMessage{}, nil, fmt.Errorf("anthropic API error: %d - %s", resp.StatusCode, errorResponse.Error.Message)
-		}
-		return types.ChatMessage{}, nil, fmt.Errorf("anthropic API error: %d - %s", resp.StatusCode, string(body))
+		return types.ChatMessage{}, nil, newAPIError(resp, body, "makeAPICallWithOAuthMessage")
 	}
 
 	// Parse successful response using response parser
@@ -897,21 +973,18 @@ func (p *AnthropicProvider) makeAPICallWithOAuthMessage(ctx context.Context, req
 	}
 
 	// Extract text content
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			message.Content = block.Text
-			break
-		}
-	}
+	message.Content = anthropicTextContent(response.Content)
+	message.Citations = convertAnthropicCitations(response.Content)
 
 	// Extract tool calls
 	message.ToolCalls = convertAnthropicContentToToolCalls(response.Content)
 
-	usage := &types.Usage{
-		PromptTokens:     response.Usage.InputTokens,
-		CompletionTokens: response.Usage.OutputTokens,
-		TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
-	}
+	// Carried to the response chunk by GenerateChatCompletion
+	response.Warnings = responseWarnings(resp.Header, requestData.Model, response.Model)
+	message.Metadata = anthropicResponseMetadata(&response)
+
+	converted := anthropicUsage(response.Usage)
+	usage := &converted
 
 	return message, usage, nil
 }
@@ -929,16 +1002,15 @@ func (p *AnthropicProvider) makeAPICallWithKeyMessage(ctx context.Context, reque
 	}
 
 	// Extract text content
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			message.Content = block.Text
-			break
-		}
-	}
+	message.Content = anthropicTextContent(response.Content)
+	message.Citations = convertAnthropicCitations(response.Content)
 
 	// Extract tool calls
 	message.ToolCalls = convertAnthropicContentToToolCalls(response.Content)
 
+	// Carried to the response chunk by GenerateChatCompletion
+	message.Metadata = anthropicResponseMetadata(response)
+
 	return message, usage, nil
 }
 
@@ -1013,49 +1085,133 @@ func convertAnthropicContentToToolCalls(content []AnthropicContentBlock) []types
 	return toolCalls
 }
 
+// anthropicTextContent joins the text blocks of a response. Responses that use server
+// tools interleave several text blocks with the tool blocks.
+func anthropicTextContent(content []AnthropicContentBlock) string {
+	var text strings.Builder
+	for _, block := range content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String()
+}
+
+// convertAnthropicCitations returns the citations of a response's text blocks, each
+// located at its block's span of the joined text content
+func convertAnthropicCitations(content []AnthropicContentBlock) []types.Citation {
+	var citations []types.Citation
+	offset := 0
+	for _, block := range content {
+		if block.Type != "text" {
+			continue
+		}
+		for _, citation := range block.Citations {
+			title := citation.Title
+			if title == "" {
+				title = citation.DocumentTitle
+			}
+			citations = append(citations, types.Citation{
+				Title:      title,
+				URL:        citation.URL,
+				Snippet:    citation.CitedText,
+				Text:       block.Text,
+				StartIndex: offset,
+				EndIndex:   offset + len(block.Text),
+			})
+		}
+		offset += len(block.Text)
+	}
+	return citations
+}
+
 // convertAnthropicResponseToChunk converts Anthropic response to universal chat completion chunk
 func convertAnthropicResponseToChunk(response *AnthropicResponse) types.ChatCompletionChunk {
 	toolCalls := convertAnthropicContentToToolCalls(response.Content)
 
 	// Extract text content
-	var textContent string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			textContent = block.Text
-			break
-		}
-	}
+	textContent := anthropicTextContent(response.Content)
+	citations := convertAnthropicCitations(response.Content)
 
 	chunk := types.ChatCompletionChunk{
-		ID:      response.ID,
-		Object:  "chat.completion",
-		Model:   response.Model,
-		Done:    true,
-		Content: textContent,
-		Usage: types.Usage{
-			PromptTokens:     response.Usage.InputTokens,
-			CompletionTokens: response.Usage.OutputTokens,
-			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
-		},
+		ID:             response.ID,
+		Object:         "chat.completion",
+		Model:          response.Model,
+		Done:           true,
+		Content:        textContent,
+		ServerToolUses: convertAnthropicContentToServerToolUses(response.Content),
+		Citations:      citations,
+		Usage:          anthropicUsage(response.Usage),
 		Choices: []types.ChatChoice{
 			{
-				Index:        0,
-				FinishReason: "stop",
+				Index:           0,
+				FinishReason:    anthropicFinishReason(response.StopReason, toolCalls),
+				RawFinishReason: response.StopReason,
 				Message: types.ChatMessage{
 					Role:      response.Role,
 					Content:   textContent,
 					ToolCalls: toolCalls,
+					Citations: citations,
 				},
 			},
 		},
 	}
 
-	// Set proper finish reason for tool calls
+	return chunk
+}
+
+// anthropicUsage converts a response's usage. Anthropic counts cached prompt tokens
+// apart from input_tokens, so they are added back into PromptTokens.
+func anthropicUsage(usage AnthropicUsage) types.Usage {
+	promptTokens := usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
+	return types.Usage{
+		PromptTokens:        promptTokens,
+		CompletionTokens:    usage.OutputTokens,
+		TotalTokens:         promptTokens + usage.OutputTokens,
+		CacheReadTokens:     usage.CacheReadInputTokens,
+		CacheCreationTokens: usage.CacheCreationInputTokens,
+	}
+}
+
+// anthropicFinishReason normalizes a stop reason, inferring it from the tool calls
+// when the response has none
+func anthropicFinishReason(stopReason string, toolCalls []types.ToolCall) string {
+	if reason := types.NormalizeFinishReason(types.ProviderTypeAnthropic, stopReason); reason != "" {
+		return reason
+	}
 	if len(toolCalls) > 0 {
-		chunk.Choices[0].FinishReason = "tool_calls"
+		return types.FinishToolCalls
 	}
+	return types.FinishStop
+}
 
-	return chunk
+// responseWarnings returns the warnings for a response: deprecation notices in its
+// headers, and the model substitution when it was served by a different model
+func responseWarnings(header http.Header, requestedModel, servedModel string) []types.Warning {
+	warnings := types.WarningsFromHeaders(header)
+	if warning := types.ModelSubstitutionWarning(requestedModel, servedModel); warning != nil {
+		warnings = append(warnings, *warning)
+	}
+	return warnings
+}
+
+// anthropicResponseMetadata returns the response extras carried to the response
+// chunk by GenerateChatCompletion, or nil if there are none
+func anthropicResponseMetadata(response *AnthropicResponse) map[string]interface{} {
+	metadata := make(map[string]interface{})
+	if uses := convertAnthropicContentToServerToolUses(response.Content); len(uses) > 0 {
+		metadata[metadataKeyServerToolUses] = uses
+	}
+	if response.StopReason != "" {
+		metadata[metadataKeyStopReason] = response.StopReason
+	}
+	if len(response.Warnings) > 0 {
+		metadata[metadataKeyWarnings] = response.Warnings
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
 }
 
 // convertContentPartToAnthropic converts a single ContentPart to Anthropic format
@@ -1310,6 +1466,20 @@ func (p *AnthropicProvider) SupportsToolCalling() bool {
 	return true
 }
 
+// Capabilities implements types.CapabilitiesReporter from the models.dev defaults.
+// JSON schema output is served through a forced tool call, so it follows tool support.
+func (p *AnthropicProvider) Capabilities(model string) types.Capabilities {
+	capabilities := common.ModelCapabilities(p, model)
+	capabilities.JSONSchema = capabilities.Tools
+	return capabilities
+}
+
+// SupportedParameters implements types.ParameterSupportReporter. The Messages API returns one
+// message per request, and parallel tool use, storage and reasoning effort are not sent.
+func (p *AnthropicProvider) SupportedParameters(model string) types.ParameterSupport {
+	return common.SingleChoiceParameters(p, model)
+}
+
 func (p *AnthropicProvider) SupportsStreaming() bool {
 	return true
 }
@@ -1322,6 +1492,18 @@ func (p *AnthropicProvider) GetToolFormat() types.ToolFormat {
 	return types.ToolFormatAnthropic
 }
 
+// Warmup refreshes OAuth tokens and loads the model list so the first request does not
+// pay for them
+func (p *AnthropicProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
+// RateLimitStatus returns the rate limits reported by the most recent response, see
+// types.RateLimitStatusProvider
+func (p *AnthropicProvider) RateLimitStatus() types.RateLimitStatus {
+	return p.rateLimitHelper.Status()
+}
+
 // TestConnectivity performs a lightweight connectivity test using the /v1/models endpoint
 // Results are cached for 30 seconds by default to prevent hammering the API during rapid health checks
 // To bypass the cache and force a fresh check, use TestConnectivityWithOptions with bypassCache=true
@@ -1407,9 +1589,8 @@ func (p *AnthropicProvider) testConnectivityWithModelsEndpoint(ctx context.Conte
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeAnthropic, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeAnthropic, "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -1499,9 +1680,8 @@ func (p *AnthropicProvider) testConnectivityWithMessagesAPI(ctx context.Context,
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeAnthropic, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeAnthropic, "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -1581,12 +1761,12 @@ func (p *AnthropicProvider) makeStreamingAPICallWithKey(ctx context.Context, req
 	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
 	p.authHelper.SetProviderSpecificHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
+	p.applyHeaders(req, &requestData)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeAnthropic, "request failed").
-			WithOperation("makeStreamingAPICallWithKey").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeAnthropic, "request failed", err).
+			WithOperation("makeStreamingAPICallWithKey")
 	}
 
 	// Parse rate limit headers from streaming response
@@ -1598,13 +1778,12 @@ func (p *AnthropicProvider) makeStreamingAPICallWithKey(ctx context.Context, req
 			//nolint:staticcheck // Empty branch is intentional - we ignore close errors
 			_ = resp.Body.Close()
 		}()
-		return nil, types.NewServerError(types.ProviderTypeAnthropic, resp.StatusCode, fmt.Sprintf("anthropic API error: %s", string(body))).
-			WithOperation("makeStreamingAPICallWithKey")
+		return nil, newAPIError(resp, body, "makeStreamingAPICallWithKey")
 	}
 
 	// Use the shared streaming utility
 	stream := streaming.CreateAnthropicStream(resp)
-	return streaming.StreamFromContext(ctx, stream), nil
+	return types.StreamWithWarnings(streaming.StreamFromContext(ctx, stream), types.WarningsFromHeaders(resp.Header)), nil
 }
 
 // makeStreamingAPICallWithOAuth makes a streaming API call with OAuth
@@ -1648,12 +1827,12 @@ func (p *AnthropicProvider) makeStreamingAPICallWithOAuth(ctx context.Context, r
 	p.authHelper.SetAuthHeaders(req, accessToken, "oauth")
 	p.authHelper.SetProviderSpecificHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
+	p.applyHeaders(req, &requestData)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeAnthropic, "request failed").
-			WithOperation("makeStreamingAPICallWithOAuth").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeAnthropic, "request failed", err).
+			WithOperation("makeStreamingAPICallWithOAuth")
 	}
 
 	// Parse rate limit headers from streaming response
@@ -1665,11 +1844,59 @@ func (p *AnthropicProvider) makeStreamingAPICallWithOAuth(ctx context.Context, r
 			//nolint:staticcheck // Empty branch is intentional - we ignore close errors
 			_ = resp.Body.Close()
 		}()
-		return nil, types.NewServerError(types.ProviderTypeAnthropic, resp.StatusCode, fmt.Sprintf("anthropic API error: %s", string(body))).
-			WithOperation("makeStreamingAPICallWithOAuth")
+		return nil, newAPIError(resp, body, "makeStreamingAPICallWithOAuth")
 	}
 
 	// Use the shared streaming utility
 	stream := streaming.CreateAnthropicStream(resp)
-	return streaming.StreamFromContext(ctx, stream), nil
+	return types.StreamWithWarnings(streaming.StreamFromContext(ctx, stream), types.WarningsFromHeaders(resp.Header)), nil
+}
+
+// newAPIError converts an error response of the Anthropic API to a ProviderError, its
+// code chosen by the status and, for other statuses, the error type of the body. Rate
+// limit errors carry the response's retry hint.
+func newAPIError(resp *http.Response, body []byte, operation string) *types.ProviderError {
+	statusCode := resp.StatusCode
+	message := string(body)
+	var errorResponse AnthropicErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil && errorResponse.Error.Message != "" {
+		message = errorResponse.Error.Message
+	}
+
+	providerErr := types.NewServerError(types.ProviderTypeAnthropic, statusCode, fmt.Sprintf("anthropic API error: %s", message)).
+		WithOperation(operation)
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		providerErr.Code = types.ErrCodeAuthentication
+	case http.StatusTooManyRequests:
+		providerErr.Code = types.ErrCodeRateLimit
+		providerErr.WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
+	case http.StatusNotFound:
+		providerErr.Code = types.ErrCodeNotFound
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		providerErr.Code = types.ErrCodeInvalidRequest
+	default:
+		if code := errorTypeCode(errorResponse.Error.Type); code != types.ErrCodeUnknown {
+			providerErr.Code = code
+		}
+	}
+	return providerErr
+}
+
+// errorTypeCode maps an Anthropic error type to an error code
+func errorTypeCode(errorType string) types.ErrorCode {
+	switch errorType {
+	case "invalid_request_error", "request_too_large":
+		return types.ErrCodeInvalidRequest
+	case "authentication_error", "permission_error":
+		return types.ErrCodeAuthentication
+	case "not_found_error":
+		return types.ErrCodeNotFound
+	case "rate_limit_error":
+		return types.ErrCodeRateLimit
+	case "api_error", "overloaded_error":
+		return types.ErrCodeServerError
+	default:
+		return types.ErrCodeUnknown
+	}
 }
@@ -2,8 +2,12 @@ package anthropic
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/auth"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
@@ -432,3 +436,146 @@ func TestMockStream(t *testing.T) {
 		t.Errorf("Expected 'Hello' after close, got '%s'", chunk.Content)
 	}
 }
+
+func TestAnthropicUsage_PromptCaching(t *testing.T) {
+	usage := anthropicUsage(AnthropicUsage{
+		InputTokens:              50,
+		OutputTokens:             20,
+		CacheCreationInputTokens: 200,
+		CacheReadInputTokens:     750,
+	})
+
+	if usage.PromptTokens != 1000 || usage.TotalTokens != 1020 {
+		t.Errorf("expected cached tokens counted in the prompt, got %+v", usage)
+	}
+	if usage.CacheReadTokens != 750 || usage.CacheCreationTokens != 200 {
+		t.Errorf("expected cache read 750 and creation 200, got %+v", usage)
+	}
+	if rate := usage.CacheHitRate(); rate != 0.75 {
+		t.Errorf("expected a 0.75 hit rate, got %v", rate)
+	}
+}
+
+func TestConvertAnthropicCitations(t *testing.T) {
+	content := []AnthropicContentBlock{
+		{Type: "text", Text: "According to the docs, "},
+		{Type: "text", Text: "Go is compiled.", Citations: []AnthropicCitation{{
+			Type:      "web_search_result_location",
+			URL:       "https://go.dev",
+			Title:     "The Go Programming Language",
+			CitedText: "Go is a compiled language",
+		}}},
+		{Type: "text", Text: " Modules", Citations: []AnthropicCitation{{
+			Type:          "char_location",
+			DocumentTitle: "Modules Reference",
+			CitedText:     "A module is a collection of packages",
+		}}},
+	}
+
+	citations := convertAnthropicCitations(content)
+	if len(citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", citations)
+	}
+	if c := citations[0]; c.URL != "https://go.dev" || c.Text != "Go is compiled." || c.StartIndex != 23 || c.EndIndex != 38 {
+		t.Errorf("unexpected web citation %+v", c)
+	}
+	if c := citations[1]; c.Title != "Modules Reference" || c.Snippet != "A module is a collection of packages" || c.StartIndex != 38 {
+		t.Errorf("unexpected document citation %+v", c)
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		code       types.ErrorCode
+		message    string
+	}{
+		{"authentication", 401, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, types.ErrCodeAuthentication, "invalid x-api-key"},
+		{"rate limit", 429, `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`, types.ErrCodeRateLimit, "Number of requests"},
+		{"invalid request", 400, `{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: field required"}}`, types.ErrCodeInvalidRequest, "max_tokens"},
+		{"overloaded", 529, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, types.ErrCodeServerError, "Overloaded"},
+		{"unparseable body", 502, `Bad Gateway`, types.ErrCodeServerError, "Bad Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(&http.Response{StatusCode: tt.statusCode, Header: http.Header{}}, []byte(tt.body), "makeAPICallWithKey")
+
+			var providerErr *types.ProviderError
+			if !errors.As(fmt.Errorf("wrapped: %w", err), &providerErr) {
+				t.Fatalf("expected a ProviderError, got %T", err)
+			}
+			if providerErr.Code != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, providerErr.Code)
+			}
+			if providerErr.StatusCode != tt.statusCode {
+				t.Errorf("expected status %d, got %d", tt.statusCode, providerErr.StatusCode)
+			}
+			if !strings.Contains(providerErr.Message, tt.message) {
+				t.Errorf("expected message containing %q, got %q", tt.message, providerErr.Message)
+			}
+		})
+	}
+
+	if !types.IsAuthenticationError(newAPIError(&http.Response{StatusCode: 401}, nil, "makeAPICallWithKey")) {
+		t.Error("expected a 401 to match AuthenticationError")
+	}
+
+	resp := &http.Response{StatusCode: 429, Header: http.Header{}}
+	resp.Header.Set("retry-after", "20")
+	var rateLimitErr *types.RateLimitError
+	if !errors.As(newAPIError(resp, nil, "makeAPICallWithKey"), &rateLimitErr) {
+		t.Fatal("expected a 429 to match RateLimitError")
+	}
+	if rateLimitErr.RetryAfter != 20*time.Second {
+		t.Errorf("expected RetryAfter 20s, got %v", rateLimitErr.RetryAfter)
+	}
+}
+
+func TestAnthropicProviderCapabilities(t *testing.T) {
+	supportsTools := false
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeAnthropic,
+		APIKey: "test-key",
+		ModelCapabilities: map[string]types.ModelCapabilityOverride{
+			"claude-no-tools": {SupportsTools: &supportsTools},
+		},
+	})
+
+	capabilities := types.ProviderCapabilities(provider, "claude-sonnet-4-20250514")
+	if !capabilities.Tools || !capabilities.Vision || !capabilities.JSONSchema || !capabilities.Streaming {
+		t.Errorf("Expected full capabilities for claude-sonnet-4, got %+v", capabilities)
+	}
+
+	// JSON schema output is a forced tool call, so it follows tool support
+	capabilities = provider.Capabilities("claude-no-tools")
+	if capabilities.JSONSchema != capabilities.Tools {
+		t.Errorf("Expected JSONSchema to follow Tools, got %+v", capabilities)
+	}
+}
+
+func TestAnthropicProviderDegradeOptions(t *testing.T) {
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key"})
+	store := true
+
+	degraded, warnings := types.DegradeOptions(provider, types.GenerateOptions{
+		Model:           "claude-sonnet-4-20250514",
+		Prompt:          "hi",
+		Temperature:     0.5,
+		Store:           &store,
+		ReasoningEffort: types.ReasoningEffortHigh,
+		N:               2,
+	})
+
+	if len(warnings) != 3 {
+		t.Fatalf("Expected 3 warnings, got %+v", warnings)
+	}
+	if degraded.Store != nil || degraded.ReasoningEffort != "" || degraded.N != 1 {
+		t.Errorf("Expected store, reasoning_effort and n to be dropped, got %+v", degraded)
+	}
+	if degraded.Temperature != 0.5 {
+		t.Errorf("Expected temperature to be kept, got %v", degraded.Temperature)
+	}
+}
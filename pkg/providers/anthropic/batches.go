@@ -0,0 +1,329 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Message batch processing statuses
+const (
+	BatchStatusInProgress = "in_progress"
+	BatchStatusCanceling  = "canceling"
+	BatchStatusEnded      = "ended"
+)
+
+// Message batch result types
+const (
+	BatchResultSucceeded = "succeeded"
+	BatchResultErrored   = "errored"
+	BatchResultCanceled  = "canceled"
+	BatchResultExpired   = "expired"
+)
+
+// MessageBatchRequest is one request of a message batch. CustomID correlates the
+// request with its result and must be unique within the batch.
+type MessageBatchRequest struct {
+	CustomID string
+	Options  types.GenerateOptions
+}
+
+// MessageBatchResult is the result of one request of a message batch
+type MessageBatchResult struct {
+	CustomID string
+
+	// Type is one of the BatchResult constants
+	Type string
+
+	// Response is set for succeeded requests
+	Response *types.ChatCompletionChunk
+
+	// Error is set for requests that did not succeed
+	Error error
+}
+
+// AnthropicMessageBatch is a message batch as returned by the Message Batches API
+type AnthropicMessageBatch struct {
+	ID               string                     `json:"id"`
+	Type             string                     `json:"type"`
+	ProcessingStatus string                     `json:"processing_status"`
+	RequestCounts    AnthropicBatchRequestCount `json:"request_counts"`
+	CreatedAt        string                     `json:"created_at"`
+	EndedAt          string                     `json:"ended_at,omitempty"`
+	ExpiresAt        string                     `json:"expires_at"`
+	ResultsURL       string                     `json:"results_url,omitempty"`
+}
+
+// AnthropicBatchRequestCount counts a batch's requests by status
+type AnthropicBatchRequestCount struct {
+	Processing int `json:"processing"`
+	Succeeded  int `json:"succeeded"`
+	Errored    int `json:"errored"`
+	Canceled   int `json:"canceled"`
+	Expired    int `json:"expired"`
+}
+
+// AnthropicBatchCreateRequest is the request payload creating a message batch
+type AnthropicBatchCreateRequest struct {
+	Requests []AnthropicBatchRequestItem `json:"requests"`
+}
+
+// AnthropicBatchRequestItem is one request of a batch creation payload
+type AnthropicBatchRequestItem struct {
+	CustomID string           `json:"custom_id"`
+	Params   AnthropicRequest `json:"params"`
+}
+
+// AnthropicBatchResultLine is one line of a batch's JSONL results
+type AnthropicBatchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string                  `json:"type"`
+		Message *AnthropicResponse      `json:"message,omitempty"`
+		Error   *AnthropicErrorResponse `json:"error,omitempty"`
+	} `json:"result"`
+}
+
+// SubmitMessageBatch creates a message batch from the requests, each validated and
+// converted as GenerateChatCompletion would. Batches are processed asynchronously at
+// a discount; poll GetBatchStatus until the batch has ended, then fetch its results
+// with GetBatchResults. Batches require API key authentication.
+func (p *AnthropicProvider) SubmitMessageBatch(ctx context.Context, requests []MessageBatchRequest) (*AnthropicMessageBatch, error) {
+	if len(requests) == 0 {
+		return nil, types.NewValidationError("a message batch needs at least one request")
+	}
+
+	payload := AnthropicBatchCreateRequest{Requests: make([]AnthropicBatchRequestItem, 0, len(requests))}
+	seen := make(map[string]bool, len(requests))
+	for i, request := range requests {
+		if request.CustomID == "" {
+			return nil, types.NewValidationError(fmt.Sprintf("batch request %d has no custom_id", i))
+		}
+		if seen[request.CustomID] {
+			return nil, types.NewValidationError(fmt.Sprintf("batch request %d reuses custom_id %q", i, request.CustomID))
+		}
+		seen[request.CustomID] = true
+
+		params, err := p.prepareBatchRequest(request.Options)
+		if err != nil {
+			return nil, fmt.Errorf("batch request %q: %w", request.CustomID, err)
+		}
+		payload.Requests = append(payload.Requests, AnthropicBatchRequestItem{CustomID: request.CustomID, Params: params})
+	}
+
+	var batch AnthropicMessageBatch
+	if err := p.doBatchCall(ctx, http.MethodPost, p.batchesURL(""), payload, "SubmitMessageBatch", func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&batch)
+	}); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// GetBatchStatus returns the current state of a message batch
+func (p *AnthropicProvider) GetBatchStatus(ctx context.Context, batchID string) (*AnthropicMessageBatch, error) {
+	if batchID == "" {
+		return nil, types.NewValidationError("batchID is required")
+	}
+
+	var batch AnthropicMessageBatch
+	if err := p.doBatchCall(ctx, http.MethodGet, p.batchesURL(url.PathEscape(batchID)), nil, "GetBatchStatus", func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&batch)
+	}); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// GetBatchResults returns the results of an ended message batch keyed by custom_id.
+// Succeeded requests carry their response as a chunk, converted as the non-streaming
+// GenerateChatCompletion would; errored, canceled and expired requests carry an error.
+func (p *AnthropicProvider) GetBatchResults(ctx context.Context, batchID string) (map[string]MessageBatchResult, error) {
+	batch, err := p.GetBatchStatus(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ProcessingStatus != BatchStatusEnded {
+		return nil, types.NewInvalidRequestError(types.ProviderTypeAnthropic, fmt.Sprintf("batch %s has not ended (status %s)", batchID, batch.ProcessingStatus)).
+			WithOperation("GetBatchResults")
+	}
+
+	resultsURL := batch.ResultsURL
+	if resultsURL == "" {
+		resultsURL = p.batchesURL(url.PathEscape(batchID) + "/results")
+	}
+
+	results := make(map[string]MessageBatchResult)
+	err = p.doBatchCall(ctx, http.MethodGet, resultsURL, nil, "GetBatchResults", func(body io.Reader) error {
+		decoder := json.NewDecoder(body)
+		for decoder.More() {
+			var line AnthropicBatchResultLine
+			if err := decoder.Decode(&line); err != nil {
+				return err
+			}
+			results[line.CustomID] = convertBatchResult(line)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// prepareBatchRequest validates options and converts them to the params of a batch
+// request
+func (p *AnthropicProvider) prepareBatchRequest(options types.GenerateOptions) (AnthropicRequest, error) {
+	if options.Stream {
+		return AnthropicRequest{}, types.NewValidationError("batch requests cannot stream")
+	}
+	model := common.ResolveModel(options.Model, p.GetConfig().DefaultModel, p.GetDefaultModel())
+	if model == "" {
+		return AnthropicRequest{}, types.NewInvalidRequestError(types.ProviderTypeAnthropic, "no model specified and no default model available").
+			WithOperation("SubmitMessageBatch")
+	}
+	if err := common.ValidateGenerateOptions(model, options); err != nil {
+		return AnthropicRequest{}, err
+	}
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return AnthropicRequest{}, err
+	}
+	if err := validateSystemMessagePlacement(options.Messages); err != nil {
+		return AnthropicRequest{}, err
+	}
+	if err := validateAnthropicOptions(options); err != nil {
+		return AnthropicRequest{}, err
+	}
+	return p.prepareRequest(options, model, common.ResolveMaxTokens(p.Type(), model, options.MaxTokens)), nil
+}
+
+// batchesURL returns the URL of the Message Batches API, or of a path below it
+func (p *AnthropicProvider) batchesURL(path string) string {
+	baseURL := p.GetConfig().BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+	if path == "" {
+		return baseURL + "/v1/messages/batches"
+	}
+	return baseURL + "/v1/messages/batches/" + path
+}
+
+// doBatchCall sends a Message Batches API request, trying each API key in turn, and
+// passes the body of the first successful response to decode
+func (p *AnthropicProvider) doBatchCall(ctx context.Context, method, endpoint string, payload interface{}, operation string, decode func(io.Reader) error) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return types.NewInvalidRequestError(types.ProviderTypeAnthropic, "failed to marshal request").
+				WithOperation(operation).
+				WithOriginalErr(err)
+		}
+	}
+
+	if p.authHelper.KeyManager == nil || len(p.authHelper.KeyManager.GetKeys()) == 0 {
+		return types.NewAuthError(types.ProviderTypeAnthropic, "message batches require an API key").
+			WithOperation(operation)
+	}
+
+	var lastErr error
+	for _, apiKey := range p.authHelper.KeyManager.GetKeys() {
+		// Failing over to another key is a retry of the request
+		if lastErr != nil && !types.AcquireRetry(ctx) {
+			break
+		}
+
+		lastErr = p.doBatchCallWithKey(ctx, method, endpoint, body, apiKey, operation, decode)
+		if lastErr == nil {
+			return nil
+		}
+		var providerErr *types.ProviderError
+		if errors.As(lastErr, &providerErr) && providerErr.Code != types.ErrCodeAuthentication && providerErr.Code != types.ErrCodeRateLimit {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// doBatchCallWithKey sends a Message Batches API request with one API key
+func (p *AnthropicProvider) doBatchCallWithKey(ctx context.Context, method, endpoint string, body []byte, apiKey, operation string, decode func(io.Reader) error) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return types.NewNetworkError(types.ProviderTypeAnthropic, "failed to create request").
+			WithOperation(operation).
+			WithOriginalErr(err)
+	}
+
+	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
+	p.authHelper.SetProviderSpecificHeaders(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	p.applyHeaders(req, nil)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return types.NewRequestError(types.ProviderTypeAnthropic, "request failed", err).
+			WithOperation(operation)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp, respBody, operation)
+	}
+
+	if err := decode(resp.Body); err != nil {
+		return types.NewServerError(types.ProviderTypeAnthropic, resp.StatusCode, "failed to parse API response").
+			WithOperation(operation).
+			WithOriginalErr(err)
+	}
+	return nil
+}
+
+// convertBatchResult converts one line of a batch's results
+func convertBatchResult(line AnthropicBatchResultLine) MessageBatchResult {
+	result := MessageBatchResult{CustomID: line.CustomID, Type: line.Result.Type}
+	switch line.Result.Type {
+	case BatchResultSucceeded:
+		if line.Result.Message == nil {
+			result.Error = types.NewServerError(types.ProviderTypeAnthropic, 0, "batch result has no message").
+				WithOperation("GetBatchResults")
+			break
+		}
+		chunk := convertAnthropicResponseToChunk(line.Result.Message)
+		result.Response = &chunk
+
+	case BatchResultErrored:
+		message := "batch request failed"
+		code := types.ErrCodeUnknown
+		if line.Result.Error != nil {
+			message = line.Result.Error.Error.Message
+			code = errorTypeCode(line.Result.Error.Error.Type)
+		}
+		result.Error = types.NewProviderError(types.ProviderTypeAnthropic, code, message).
+			WithOperation("GetBatchResults")
+
+	case BatchResultCanceled, BatchResultExpired:
+		result.Error = types.NewProviderError(types.ProviderTypeAnthropic, types.ErrCodeUnknown, "batch request "+line.Result.Type).
+			WithOperation("GetBatchResults")
+
+	default:
+		result.Error = types.NewProviderError(types.ProviderTypeAnthropic, types.ErrCodeUnknown, fmt.Sprintf("unknown batch result type %q", line.Result.Type)).
+			WithOperation("GetBatchResults")
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitMessageBatch(t *testing.T) {
+	var requestBody AnthropicBatchCreateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/messages/batches", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		assert.NotEmpty(t, r.Header.Get("anthropic-version"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":                "msgbatch_1",
+			"type":              "message_batch",
+			"processing_status": "in_progress",
+			"request_counts":    map[string]int{"processing": 2},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key", BaseURL: server.URL})
+	batch, err := provider.SubmitMessageBatch(context.Background(), []MessageBatchRequest{
+		{CustomID: "first", Options: types.GenerateOptions{Prompt: "Hello", Model: "claude-sonnet-4"}},
+		{CustomID: "second", Options: types.GenerateOptions{Messages: []types.ChatMessage{{Role: "user", Content: "Hi"}}, MaxTokens: 100}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "msgbatch_1", batch.ID)
+	assert.Equal(t, BatchStatusInProgress, batch.ProcessingStatus)
+	assert.Equal(t, 2, batch.RequestCounts.Processing)
+
+	require.Len(t, requestBody.Requests, 2)
+	assert.Equal(t, "first", requestBody.Requests[0].CustomID)
+	assert.Equal(t, "claude-sonnet-4", requestBody.Requests[0].Params.Model)
+	assert.False(t, requestBody.Requests[0].Params.Stream)
+	assert.Equal(t, "second", requestBody.Requests[1].CustomID)
+	assert.Equal(t, 100, requestBody.Requests[1].Params.MaxTokens)
+}
+
+func TestSubmitMessageBatch_Validation(t *testing.T) {
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key", BaseURL: "http://127.0.0.1:0"})
+	ctx := context.Background()
+
+	_, err := provider.SubmitMessageBatch(ctx, nil)
+	assert.Error(t, err)
+
+	_, err = provider.SubmitMessageBatch(ctx, []MessageBatchRequest{{Options: types.GenerateOptions{Prompt: "Hi"}}})
+	assert.ErrorContains(t, err, "custom_id")
+
+	_, err = provider.SubmitMessageBatch(ctx, []MessageBatchRequest{
+		{CustomID: "a", Options: types.GenerateOptions{Prompt: "Hi"}},
+		{CustomID: "a", Options: types.GenerateOptions{Prompt: "Hi"}},
+	})
+	assert.ErrorContains(t, err, "reuses custom_id")
+
+	_, err = provider.SubmitMessageBatch(ctx, []MessageBatchRequest{{CustomID: "a", Options: types.GenerateOptions{Prompt: "Hi", Stream: true}}})
+	assert.ErrorContains(t, err, "cannot stream")
+}
+
+func TestGetBatchResults(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/messages/batches/msgbatch_1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":                "msgbatch_1",
+				"processing_status": "ended",
+				"request_counts":    map[string]int{"succeeded": 1, "errored": 1, "expired": 1},
+				"results_url":       server.URL + "/results/msgbatch_1",
+			})
+		case "/results/msgbatch_1":
+			w.Header().Set("Content-Type", "application/x-jsonl")
+			_, _ = fmt.Fprintln(w, `{"custom_id":"first","result":{"type":"succeeded","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4","content":[{"type":"text","text":"Hello!"},{"type":"tool_use","id":"toolu_1","name":"lookup","input":{"q":"x"}}],"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":5}}}}`)
+			_, _ = fmt.Fprintln(w, `{"custom_id":"second","result":{"type":"errored","error":{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens too large"}}}}`)
+			_, _ = fmt.Fprintln(w, `{"custom_id":"third","result":{"type":"expired"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key", BaseURL: server.URL})
+	results, err := provider.GetBatchResults(context.Background(), "msgbatch_1")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	first := results["first"]
+	assert.Equal(t, BatchResultSucceeded, first.Type)
+	require.NoError(t, first.Error)
+	require.NotNil(t, first.Response)
+	assert.Equal(t, "msg_1", first.Response.ID)
+	assert.Equal(t, "Hello!", first.Response.Content)
+	assert.Equal(t, 15, first.Response.Usage.TotalTokens)
+	require.Len(t, first.Response.Choices, 1)
+	assert.Equal(t, types.FinishToolCalls, first.Response.Choices[0].FinishReason)
+	require.Len(t, first.Response.Choices[0].Message.ToolCalls, 1)
+	assert.Equal(t, "lookup", first.Response.Choices[0].Message.ToolCalls[0].Function.Name)
+
+	second := results["second"]
+	assert.Equal(t, BatchResultErrored, second.Type)
+	assert.Nil(t, second.Response)
+	var providerErr *types.ProviderError
+	require.True(t, errors.As(second.Error, &providerErr))
+	assert.Equal(t, types.ErrCodeInvalidRequest, providerErr.Code)
+	assert.Contains(t, providerErr.Message, "max_tokens too large")
+
+	third := results["third"]
+	assert.Equal(t, BatchResultExpired, third.Type)
+	assert.Error(t, third.Error)
+}
+
+func TestGetBatchResults_NotEnded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "msgbatch_1", "processing_status": "in_progress"})
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key", BaseURL: server.URL})
+	_, err := provider.GetBatchResults(context.Background(), "msgbatch_1")
+	assert.ErrorContains(t, err, "has not ended")
+}
+
+func TestGetBatchStatus_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"not_found_error","message":"batch not found"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key", BaseURL: server.URL})
+	_, err := provider.GetBatchStatus(context.Background(), "missing")
+	var providerErr *types.ProviderError
+	require.True(t, errors.As(err, &providerErr))
+	assert.Equal(t, types.ErrCodeNotFound, providerErr.Code)
+	assert.Contains(t, providerErr.Message, "batch not found")
+}
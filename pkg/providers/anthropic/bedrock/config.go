@@ -30,6 +30,10 @@ type BedrockConfig struct {
 
 	// Enable debug logging for AWS requests
 	Debug bool
+
+	// Gzip request bodies before signing, so the signed payload hash and the
+	// Content-Encoding header cover the compressed bytes that are sent
+	CompressRequests bool
 }
 
 // Validate checks if the configuration is valid
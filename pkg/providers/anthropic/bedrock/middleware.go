@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 
+	pkghttp "github.com/cecil-the-coder/ai-provider-kit/internal/http"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 )
 
@@ -90,6 +91,13 @@ func (m *BedrockMiddleware) ProcessRequest(ctx context.Context, req *http.Reques
 	// Transform headers for Bedrock
 	m.transformHeaders(req)
 
+	// Compress before signing; the signature must cover the bytes on the wire
+	if m.config.CompressRequests {
+		if err := pkghttp.GzipRequestBody(req); err != nil {
+			return ctx, req, fmt.Errorf("bedrock: %w", err)
+		}
+	}
+
 	// Sign the request with AWS Signature V4
 	if err := m.signer.SignRequest(req); err != nil {
 		return ctx, req, fmt.Errorf("bedrock: failed to sign request: %w", err)
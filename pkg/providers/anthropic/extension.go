@@ -146,24 +146,15 @@ func (e *AnthropicExtension) ProviderToStandard(response interface{}) (*types.St
 		ToolCalls: toolCalls,
 	}
 
-	// Determine finish reason
-	finishReason := "stop"
-	if len(toolCalls) > 0 {
-		finishReason = "tool_calls"
-	}
-
 	choices[0] = types.StandardChoice{
-		Index:        0,
-		Message:      message,
-		FinishReason: finishReason,
+		Index:           0,
+		Message:         message,
+		FinishReason:    anthropicFinishReason(anthropicResp.StopReason, toolCalls),
+		RawFinishReason: anthropicResp.StopReason,
 	}
 
 	// Convert usage
-	usage := types.Usage{
-		PromptTokens:     anthropicResp.Usage.InputTokens,
-		CompletionTokens: anthropicResp.Usage.OutputTokens,
-		TotalTokens:      anthropicResp.Usage.InputTokens + anthropicResp.Usage.OutputTokens,
-	}
+	usage := anthropicUsage(anthropicResp.Usage)
 
 	// Create provider metadata
 	providerMetadata := map[string]interface{}{
@@ -210,26 +201,18 @@ func (e *AnthropicExtension) ProviderToStandardChunk(chunk interface{}) (*types.
 		ToolCalls: toolCalls,
 	}
 
-	// Determine finish reason
-	finishReason := ""
-	if anthropicChunk.StopReason != "" {
-		finishReason = anthropicChunk.StopReason
-	}
-
 	choices[0] = types.StandardStreamChoice{
-		Index:        0,
-		Delta:        delta,
-		FinishReason: finishReason,
+		Index:           0,
+		Delta:           delta,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeAnthropic, anthropicChunk.StopReason),
+		RawFinishReason: anthropicChunk.StopReason,
 	}
 
 	// Convert usage if present
 	var usage *types.Usage
 	if anthropicChunk.Usage != nil {
-		usage = &types.Usage{
-			PromptTokens:     anthropicChunk.Usage.InputTokens,
-			CompletionTokens: anthropicChunk.Usage.OutputTokens,
-			TotalTokens:      anthropicChunk.Usage.InputTokens + anthropicChunk.Usage.OutputTokens,
-		}
+		converted := anthropicUsage(*anthropicChunk.Usage)
+		usage = &converted
 	}
 
 	// Create provider metadata
@@ -266,7 +266,8 @@ func TestProviderToStandardChunk(t *testing.T) {
 	assert.Equal(t, 0, result.Choices[0].Index)
 	assert.Equal(t, "assistant", result.Choices[0].Delta.Role)
 	assert.Equal(t, "Hello", result.Choices[0].Delta.Content)
-	assert.Equal(t, "end_turn", result.Choices[0].FinishReason)
+	assert.Equal(t, types.FinishStop, result.Choices[0].FinishReason)
+	assert.Equal(t, "end_turn", result.Choices[0].RawFinishReason)
 
 	// Verify usage
 	require.NotNil(t, result.Usage)
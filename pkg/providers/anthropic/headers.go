@@ -0,0 +1,151 @@
+package anthropic
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultAnthropicVersion is the anthropic-version header sent when none is configured
+const DefaultAnthropicVersion = "2023-06-01"
+
+// Beta feature flags sent in the anthropic-beta header
+const (
+	BetaPromptCaching       = "prompt-caching-2024-07-31"
+	BetaOutput128K          = "output-128k-2025-02-19"
+	BetaContext1M           = "context-1m-2025-08-07"
+	BetaStructuredOutputs   = "structured-outputs-2025-11-13"
+	BetaInterleavedThinking = "interleaved-thinking-2025-05-14"
+	BetaTokenEfficientTools = "token-efficient-tools-2025-02-19"
+)
+
+// output128KMinTokens is the max_tokens above which Claude 3.7 Sonnet needs the
+// extended output beta
+const output128KMinTokens = 64000
+
+// HeaderConfig configures the anthropic-version and anthropic-beta headers
+type HeaderConfig struct {
+	// Version overrides the anthropic-version header (default DefaultAnthropicVersion)
+	Version string
+
+	// Betas are sent with every request, in addition to the betas detected from the
+	// request body
+	Betas []string
+
+	// Context1M enables the 1M token context window on models that offer it
+	Context1M bool
+}
+
+// SetHeaders sets the anthropic-version header and the anthropic-beta flags a
+// Messages API request needs, so betas do not have to be set by hand. request is the
+// body about to be sent, from which betas are detected by DetectBetas; it is nil for
+// requests without a Messages body, which get the configured betas only.
+//
+// Detected betas are merged with configured ones and with any anthropic-beta header
+// already on the request (such as the OAuth betas), without duplicates.
+func (c HeaderConfig) SetHeaders(req *http.Request, request *AnthropicRequest) {
+	switch {
+	case c.Version != "":
+		req.Header.Set("anthropic-version", c.Version)
+	case req.Header.Get("anthropic-version") == "":
+		req.Header.Set("anthropic-version", DefaultAnthropicVersion)
+	}
+
+	betas := splitBetas(req.Header.Get("anthropic-beta"))
+	betas = append(betas, c.Betas...)
+	if c.Context1M {
+		betas = append(betas, BetaContext1M)
+	}
+	if request != nil {
+		betas = append(betas, DetectBetas(*request)...)
+	}
+	if betas = dedupeBetas(betas); len(betas) > 0 {
+		req.Header.Set("anthropic-beta", strings.Join(betas, ","))
+	}
+}
+
+// DetectBetas returns the beta flags a Messages API request uses:
+//
+//   - cache_control anywhere in the system prompt, messages or tool choice: BetaPromptCaching
+//   - max_tokens above 64000 on Claude 3.7 Sonnet: BetaOutput128K
+//   - response_format: BetaStructuredOutputs
+func DetectBetas(request AnthropicRequest) []string {
+	var betas []string
+	if containsKey(request.System, "cache_control") || containsKey(request.ToolChoice, "cache_control") ||
+		messagesContainKey(request.Messages, "cache_control") {
+		betas = append(betas, BetaPromptCaching)
+	}
+	if strings.Contains(request.Model, "claude-3-7-sonnet") && request.MaxTokens > output128KMinTokens {
+		betas = append(betas, BetaOutput128K)
+	}
+	if request.ResponseFormat != nil {
+		betas = append(betas, BetaStructuredOutputs)
+	}
+	return betas
+}
+
+// messagesContainKey reports whether key appears in the content of any message
+func messagesContainKey(messages []AnthropicMessage, key string) bool {
+	for _, message := range messages {
+		if containsKey(message.Content, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsKey reports whether key appears at any depth of a JSON-like value built from
+// maps and slices
+func containsKey(value interface{}, key string) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v[key]; ok {
+			return true
+		}
+		for _, child := range v {
+			if containsKey(child, key) {
+				return true
+			}
+		}
+	case map[string]string:
+		_, ok := v[key]
+		return ok
+	case []map[string]interface{}:
+		for _, child := range v {
+			if containsKey(child, key) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if containsKey(child, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitBetas parses a comma-separated anthropic-beta header
+func splitBetas(header string) []string {
+	var betas []string
+	for _, beta := range strings.Split(header, ",") {
+		if beta = strings.TrimSpace(beta); beta != "" {
+			betas = append(betas, beta)
+		}
+	}
+	return betas
+}
+
+// dedupeBetas removes empty and repeated betas, keeping the first occurrence
+func dedupeBetas(betas []string) []string {
+	seen := make(map[string]bool, len(betas))
+	result := betas[:0]
+	for _, beta := range betas {
+		if beta == "" || seen[beta] {
+			continue
+		}
+		seen[beta] = true
+		result = append(result, beta)
+	}
+	return result
+}
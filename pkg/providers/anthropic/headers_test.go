@@ -0,0 +1,115 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestDetectBetas(t *testing.T) {
+	cachedText := []interface{}{map[string]interface{}{
+		"type": "text", "text": "hi", "cache_control": map[string]string{"type": "ephemeral"},
+	}}
+
+	tests := []struct {
+		name     string
+		request  AnthropicRequest
+		expected []string
+	}{
+		{"plain request", AnthropicRequest{Model: "claude-sonnet-4", MaxTokens: 1024}, nil},
+		{"prompt caching in system", AnthropicRequest{Model: "claude-sonnet-4", System: cachedText}, []string{BetaPromptCaching}},
+		{"prompt caching in messages", AnthropicRequest{Model: "claude-sonnet-4", Messages: []AnthropicMessage{{Role: "user", Content: cachedText}}}, []string{BetaPromptCaching}},
+		{"extended output", AnthropicRequest{Model: "claude-3-7-sonnet-20250219", MaxTokens: 128000}, []string{BetaOutput128K}},
+		{"large max_tokens on other models", AnthropicRequest{Model: "claude-sonnet-4", MaxTokens: 128000}, nil},
+		{"structured outputs", AnthropicRequest{Model: "claude-sonnet-4", ResponseFormat: "json"}, []string{BetaStructuredOutputs}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectBetas(tt.request))
+		})
+	}
+}
+
+func TestHeaderConfig_SetHeaders(t *testing.T) {
+	request := &AnthropicRequest{
+		Model: "claude-sonnet-4",
+		Messages: []AnthropicMessage{{Role: "user", Content: []interface{}{map[string]interface{}{
+			"type": "text", "text": "hi", "cache_control": map[string]interface{}{"type": "ephemeral"},
+		}}}},
+	}
+
+	t.Run("merges detected, configured and existing betas", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "https://api.anthropic.com/v1/messages", nil)
+		req.Header.Set("anthropic-beta", "oauth-2025-04-20, "+BetaPromptCaching)
+
+		HeaderConfig{Betas: []string{BetaTokenEfficientTools}, Context1M: true}.SetHeaders(req, request)
+
+		expected := strings.Join([]string{"oauth-2025-04-20", BetaPromptCaching, BetaTokenEfficientTools, BetaContext1M}, ",")
+		assert.Equal(t, expected, req.Header.Get("anthropic-beta"))
+		assert.Equal(t, DefaultAnthropicVersion, req.Header.Get("anthropic-version"))
+	})
+
+	t.Run("configured version overrides the default", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "https://api.anthropic.com/v1/messages", nil)
+		req.Header.Set("anthropic-version", DefaultAnthropicVersion)
+
+		HeaderConfig{Version: "2025-01-01"}.SetHeaders(req, &AnthropicRequest{})
+		assert.Equal(t, "2025-01-01", req.Header.Get("anthropic-version"))
+		assert.Empty(t, req.Header.Get("anthropic-beta"))
+	})
+
+	t.Run("requests without a body get the configured betas", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "https://api.anthropic.com/v1/messages/batches", nil)
+
+		HeaderConfig{Context1M: true}.SetHeaders(req, nil)
+		assert.Equal(t, BetaContext1M, req.Header.Get("anthropic-beta"))
+		assert.Equal(t, DefaultAnthropicVersion, req.Header.Get("anthropic-version"))
+	})
+}
+
+func TestGenerateChatCompletion_BetaHeaders(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		response := map[string]interface{}{
+			"id":      "msg_test",
+			"type":    "message",
+			"role":    "assistant",
+			"model":   "claude-sonnet-4",
+			"content": []interface{}{map[string]interface{}{"type": "text", "text": "ok"}},
+			"usage":   map[string]interface{}{"input_tokens": 10, "output_tokens": 5},
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeAnthropic,
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+		ProviderConfig: map[string]interface{}{
+			"context_1m": true,
+			"betas":      []string{BetaTokenEfficientTools},
+		},
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt:         "hi",
+		Model:          "claude-sonnet-4",
+		ResponseFormat: "json",
+	})
+	require.NoError(t, err)
+	_ = stream.Close()
+
+	assert.Equal(t, DefaultAnthropicVersion, headers.Get("anthropic-version"))
+	assert.Equal(t, BetaTokenEfficientTools+","+BetaContext1M+","+BetaStructuredOutputs, headers.Get("anthropic-beta"))
+}
@@ -0,0 +1,174 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Versioned type discriminators of Anthropic-defined tools
+const (
+	AnthropicToolTypeWebSearch  = "web_search_20250305"
+	AnthropicToolTypeBash       = "bash_20250124"
+	AnthropicToolTypeTextEditor = "text_editor_20250124"
+)
+
+// metadataKeyServerToolUses carries parsed server tool uses from the response parser
+// to the response chunk
+const metadataKeyServerToolUses = "server_tool_uses"
+
+// metadataKeyStopReason carries the response's stop_reason to the response chunk
+const metadataKeyStopReason = "stop_reason"
+
+// metadataKeyWarnings carries the response's warnings to the response chunk
+const metadataKeyWarnings = "warnings"
+
+// AnthropicOptions configures Anthropic-specific request features.
+// Pass it through GenerateOptions.ProviderOptions under the "anthropic" key:
+//
+//	options := types.GenerateOptions{
+//	    Prompt: "What changed in the latest Go release?",
+//	    ProviderOptions: map[string]interface{}{
+//	        "anthropic": anthropic.AnthropicOptions{
+//	            ServerTools: []anthropic.AnthropicServerTool{anthropic.WebSearchTool(3)},
+//	        },
+//	    },
+//	}
+type AnthropicOptions struct {
+	// ServerTools are Anthropic-defined tools sent alongside GenerateOptions.Tools
+	ServerTools []AnthropicServerTool `json:"server_tools,omitempty"`
+}
+
+// AnthropicServerTool declares an Anthropic-defined tool. Unlike types.Tool these have
+// no input schema; the versioned Type selects the tool.
+//
+// Web search runs on Anthropic's servers. Its calls are returned in
+// ChatCompletionChunk.ServerToolUses for observation only: the application must not
+// execute them or send tool results for them. Each search is billed by Anthropic in
+// addition to tokens, and the search results count as input tokens; use MaxUses to
+// bound the cost of a request.
+//
+// Bash and the text editor are defined by Anthropic but executed by the application:
+// the model calls them with regular tool_use blocks, returned as ToolCalls like any
+// other tool. Their definitions add a fixed number of input tokens to every request.
+//
+// See https://docs.anthropic.com/en/docs/agents-and-tools/tool-use/overview
+type AnthropicServerTool struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// Web search settings
+	MaxUses        int                    `json:"max_uses,omitempty"`
+	AllowedDomains []string               `json:"allowed_domains,omitempty"`
+	BlockedDomains []string               `json:"blocked_domains,omitempty"`
+	UserLocation   *AnthropicUserLocation `json:"user_location,omitempty"`
+}
+
+// AnthropicUserLocation localizes web search results
+type AnthropicUserLocation struct {
+	Type     string `json:"type"` // "approximate"
+	City     string `json:"city,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// WebSearchTool returns the web search server tool, limited to maxUses searches per
+// request (0 for no limit)
+func WebSearchTool(maxUses int) AnthropicServerTool {
+	return AnthropicServerTool{Type: AnthropicToolTypeWebSearch, Name: "web_search", MaxUses: maxUses}
+}
+
+// BashTool returns the Anthropic-defined bash tool, which the application executes
+func BashTool() AnthropicServerTool {
+	return AnthropicServerTool{Type: AnthropicToolTypeBash, Name: "bash"}
+}
+
+// TextEditorTool returns the Anthropic-defined text editor tool, which the application executes
+func TextEditorTool() AnthropicServerTool {
+	return AnthropicServerTool{Type: AnthropicToolTypeTextEditor, Name: "str_replace_editor"}
+}
+
+// anthropicOptionsFrom extracts AnthropicOptions from GenerateOptions.ProviderOptions.
+// Accepts the struct, a pointer to it, or a generic map (e.g. decoded from JSON config).
+func anthropicOptionsFrom(options types.GenerateOptions) (*AnthropicOptions, error) {
+	raw, ok := options.ProviderOptions[string(types.ProviderTypeAnthropic)]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case AnthropicOptions:
+		return &v, nil
+	case *AnthropicOptions:
+		return v, nil
+	default:
+		// Round-trip through JSON to support map[string]interface{} and similar
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anthropic provider options: %w", err)
+		}
+		var opts AnthropicOptions
+		if err := json.Unmarshal(data, &opts); err != nil {
+			return nil, fmt.Errorf("invalid anthropic provider options: %w", err)
+		}
+		return &opts, nil
+	}
+}
+
+// validateAnthropicOptions rejects invalid provider options before any network I/O
+func validateAnthropicOptions(options types.GenerateOptions) error {
+	opts, err := anthropicOptionsFrom(options)
+	if err != nil || opts == nil {
+		return err
+	}
+	for i, tool := range opts.ServerTools {
+		if tool.Type == "" || tool.Name == "" {
+			return types.NewInvalidRequestError(types.ProviderTypeAnthropic, fmt.Sprintf("server tool %d requires a type and a name", i)).
+				WithOperation("GenerateChatCompletion")
+		}
+	}
+	return nil
+}
+
+// isServerToolResult reports whether a content block type is the result of a server
+// tool, such as "web_search_tool_result". "tool_result" is sent by the application.
+func isServerToolResult(blockType string) bool {
+	return blockType != "tool_result" && strings.HasSuffix(blockType, "_tool_result")
+}
+
+// convertAnthropicContentToServerToolUses collects the server_tool_use blocks of a
+// response, each paired with its result block
+func convertAnthropicContentToServerToolUses(content []AnthropicContentBlock) []types.ServerToolUse {
+	var uses []types.ServerToolUse
+	for _, block := range content {
+		switch {
+		case block.Type == "server_tool_use":
+			uses = append(uses, types.ServerToolUse{
+				ID:    block.ID,
+				Name:  block.Name,
+				Input: block.Input,
+			})
+		case isServerToolResult(block.Type):
+			paired := false
+			for i := range uses {
+				if uses[i].ID == block.ToolUseID {
+					uses[i].ResultType = block.Type
+					uses[i].Result = block.Content
+					paired = true
+					break
+				}
+			}
+			if !paired {
+				uses = append(uses, types.ServerToolUse{
+					ID:         block.ToolUseID,
+					ResultType: block.Type,
+					Result:     block.Content,
+				})
+			}
+		}
+	}
+	return uses
+}
@@ -0,0 +1,206 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropicOptionsFrom(t *testing.T) {
+	opts, err := anthropicOptionsFrom(types.GenerateOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, opts)
+
+	opts, err = anthropicOptionsFrom(types.GenerateOptions{
+		ProviderOptions: map[string]interface{}{
+			"anthropic": map[string]interface{}{
+				"server_tools": []interface{}{
+					map[string]interface{}{"type": AnthropicToolTypeWebSearch, "name": "web_search", "max_uses": 2},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, opts.ServerTools, 1)
+	assert.Equal(t, WebSearchTool(2), opts.ServerTools[0])
+
+	_, err = anthropicOptionsFrom(types.GenerateOptions{
+		ProviderOptions: map[string]interface{}{"anthropic": map[string]interface{}{"server_tools": "web_search"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestAnthropicRequest_MarshalServerTools(t *testing.T) {
+	request := AnthropicRequest{
+		Model:     "claude-sonnet-4",
+		MaxTokens: 1024,
+		Tools: []AnthropicTool{
+			{Name: "get_weather", Description: "Get the weather", InputSchema: map[string]interface{}{"type": "object"}},
+		},
+		ServerTools: []AnthropicServerTool{WebSearchTool(5), BashTool(), TextEditorTool()},
+	}
+
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Model string                   `json:"model"`
+		Tools []map[string]interface{} `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "claude-sonnet-4", decoded.Model)
+	require.Len(t, decoded.Tools, 4)
+
+	assert.Equal(t, "get_weather", decoded.Tools[0]["name"])
+	assert.NotContains(t, decoded.Tools[0], "type")
+
+	assert.Equal(t, map[string]interface{}{"type": "web_search_20250305", "name": "web_search", "max_uses": float64(5)}, decoded.Tools[1])
+	assert.Equal(t, map[string]interface{}{"type": "bash_20250124", "name": "bash"}, decoded.Tools[2])
+	assert.Equal(t, map[string]interface{}{"type": "text_editor_20250124", "name": "str_replace_editor"}, decoded.Tools[3])
+
+	// Without server tools the request is serialized as before
+	request.ServerTools = nil
+	request.Tools = nil
+	data, err = json.Marshal(request)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "tools")
+}
+
+func TestConvertAnthropicContentToServerToolUses(t *testing.T) {
+	content := []AnthropicContentBlock{
+		{Type: "text", Text: "Let me search for that."},
+		{Type: "server_tool_use", ID: "srvtoolu_1", Name: "web_search", Input: map[string]interface{}{"query": "go 1.24 release"}},
+		{Type: "web_search_tool_result", ToolUseID: "srvtoolu_1", Content: []interface{}{
+			map[string]interface{}{"type": "web_search_result", "url": "https://go.dev/doc/go1.24"},
+		}},
+		{Type: "tool_use", ID: "toolu_1", Name: "get_weather"},
+		{Type: "text", Text: "Go 1.24 was released in February 2025."},
+	}
+
+	uses := convertAnthropicContentToServerToolUses(content)
+	require.Len(t, uses, 1)
+	assert.Equal(t, "srvtoolu_1", uses[0].ID)
+	assert.Equal(t, "web_search", uses[0].Name)
+	assert.Equal(t, "go 1.24 release", uses[0].Input["query"])
+	assert.Equal(t, "web_search_tool_result", uses[0].ResultType)
+	assert.NotNil(t, uses[0].Result)
+
+	// Server tool uses are not returned as tool calls for the application to execute
+	toolCalls := convertAnthropicContentToToolCalls(content)
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, "toolu_1", toolCalls[0].ID)
+
+	assert.Equal(t, "Let me search for that.Go 1.24 was released in February 2025.", anthropicTextContent(content))
+}
+
+func TestGenerateChatCompletion_ServerTools(t *testing.T) {
+	var requestBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &requestBody)
+
+		response := map[string]interface{}{
+			"id":    "msg_test",
+			"type":  "message",
+			"role":  "assistant",
+			"model": "claude-sonnet-4",
+			"content": []interface{}{
+				map[string]interface{}{"type": "server_tool_use", "id": "srvtoolu_1", "name": "web_search", "input": map[string]interface{}{"query": "weather"}},
+				map[string]interface{}{"type": "web_search_tool_result", "tool_use_id": "srvtoolu_1", "content": []interface{}{}},
+				map[string]interface{}{"type": "text", "text": "It is sunny."},
+			},
+			"usage": map[string]interface{}{"input_tokens": 10, "output_tokens": 5},
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeAnthropic,
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "What is the weather?",
+		Model:  "claude-sonnet-4",
+		ProviderOptions: map[string]interface{}{
+			"anthropic": AnthropicOptions{ServerTools: []AnthropicServerTool{WebSearchTool(1)}},
+		},
+	})
+	require.NoError(t, err)
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny.", chunk.Content)
+	require.Len(t, chunk.ServerToolUses, 1)
+	assert.Equal(t, "web_search", chunk.ServerToolUses[0].Name)
+	assert.Equal(t, "web_search_tool_result", chunk.ServerToolUses[0].ResultType)
+	require.Len(t, chunk.Choices, 1)
+	assert.Empty(t, chunk.Choices[0].Message.ToolCalls, "server tool uses must not be reported as tool calls")
+	assert.Empty(t, chunk.Choices[0].Message.Metadata)
+
+	tools, ok := requestBody["tools"].([]interface{})
+	require.True(t, ok, "request should include tools")
+	require.Len(t, tools, 1)
+	assert.Equal(t, AnthropicToolTypeWebSearch, tools[0].(map[string]interface{})["type"])
+}
+
+func TestGenerateChatCompletion_InvalidServerTool(t *testing.T) {
+	provider := NewAnthropicProvider(types.ProviderConfig{Type: types.ProviderTypeAnthropic, APIKey: "test-key"})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "Hello",
+		Model:  "claude-sonnet-4",
+		ProviderOptions: map[string]interface{}{
+			"anthropic": AnthropicOptions{ServerTools: []AnthropicServerTool{{Name: "web_search"}}},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a type and a name")
+}
+
+func TestGenerateChatCompletion_Warnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":          "msg_test",
+			"type":        "message",
+			"role":        "assistant",
+			"model":       "claude-3-5-sonnet-20241022",
+			"content":     []interface{}{map[string]interface{}{"type": "text", "text": "Hello"}},
+			"stop_reason": "end_turn",
+			"usage":       map[string]interface{}{"input_tokens": 10, "output_tokens": 5},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeAnthropic,
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "Hello",
+		Model:  "claude-3-opus-latest",
+	})
+	require.NoError(t, err)
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	require.Len(t, chunk.Warnings, 2)
+	assert.Equal(t, types.WarningDeprecated, chunk.Warnings[0].Code)
+	assert.Contains(t, chunk.Warnings[0].Message, "Wed, 01 Jul 2026")
+	assert.Equal(t, types.WarningModelSubstituted, chunk.Warnings[1].Code)
+	assert.Empty(t, chunk.Choices[0].Message.Metadata)
+}
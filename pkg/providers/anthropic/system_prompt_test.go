@@ -0,0 +1,85 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareRequest_HoistsLeadingSystemMessages(t *testing.T) {
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeAnthropic,
+		APIKey: "sk-ant-api-test",
+	})
+
+	request := provider.prepareRequest(types.GenerateOptions{
+		Messages: []types.ChatMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "system", Parts: []types.ContentPart{types.NewTextPart("Answer in French.")}},
+			{Role: "user", Content: "Hello"},
+		},
+	}, "claude-3-5-sonnet-20241022", 1024)
+
+	system, ok := request.System.(string)
+	require.True(t, ok, "expected string system field for API key auth, got %T", request.System)
+	assert.Contains(t, system, "You are terse.\n\nAnswer in French.")
+
+	require.Len(t, request.Messages, 1)
+	assert.Equal(t, "user", request.Messages[0].Role)
+}
+
+func TestGenerateChatCompletion_RejectsMidConversationSystemMessage(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeAnthropic,
+		APIKey:  "sk-ant-api-test",
+		BaseURL: server.URL,
+	})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Model: "claude-3-5-sonnet-20241022",
+		Messages: []types.ChatMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "user", Content: "Hello"},
+			{Role: "assistant", Content: "Hi"},
+			{Role: "system", Content: "Now answer in French."},
+		},
+	})
+
+	require.Error(t, err)
+	assert.True(t, types.IsValidationError(err))
+	assert.Contains(t, err.Error(), "index 3")
+	assert.Zero(t, atomic.LoadInt32(&requests))
+}
+
+func TestPrepareRequest_TrailingAssistantPrefill(t *testing.T) {
+	provider := NewAnthropicProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeAnthropic,
+		APIKey: "sk-ant-api-test",
+	})
+
+	options := types.GenerateOptions{Prompt: "List three colors as JSON"}.WithPrefill("{\n")
+	request := provider.prepareRequest(options, "claude-3-5-sonnet-20241022", 1024)
+
+	require.Len(t, request.Messages, 2)
+	assert.Equal(t, "user", request.Messages[0].Role)
+	assert.Equal(t, "assistant", request.Messages[1].Role)
+	assert.Equal(t, "{", request.Messages[1].Content, "trailing whitespace must be trimmed from the prefill")
+
+	// A whitespace-only prefill is dropped rather than sent empty
+	request = provider.prepareRequest(options.WithoutPrefill().WithPrefill(" "), "claude-3-5-sonnet-20241022", 1024)
+	require.Len(t, request.Messages, 1)
+	assert.Equal(t, "user", request.Messages[0].Role)
+}
@@ -2,6 +2,12 @@
 // It includes request/response structures, streaming types, and model definitions.
 package anthropic
 
+import (
+	"encoding/json"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
 // AnthropicRequest represents the request payload for Anthropic API
 type AnthropicRequest struct {
 	Model          string             `json:"model"`
@@ -15,6 +21,29 @@ type AnthropicRequest struct {
 	StopSequences  []string           `json:"stop_sequences,omitempty"`
 	TopP           *float64           `json:"top_p,omitempty"`
 	TopK           *int               `json:"top_k,omitempty"`
+
+	// ServerTools are serialized into the tools array after Tools
+	ServerTools []AnthropicServerTool `json:"-"`
+}
+
+// MarshalJSON merges ServerTools into the tools array
+func (r AnthropicRequest) MarshalJSON() ([]byte, error) {
+	type request AnthropicRequest
+	if len(r.ServerTools) == 0 {
+		return json.Marshal(request(r))
+	}
+
+	tools := make([]interface{}, 0, len(r.Tools)+len(r.ServerTools))
+	for _, tool := range r.Tools {
+		tools = append(tools, tool)
+	}
+	for _, tool := range r.ServerTools {
+		tools = append(tools, tool)
+	}
+	return json.Marshal(struct {
+		request
+		Tools []interface{} `json:"tools"`
+	}{request(r), tools})
 }
 
 // AnthropicTool represents a tool definition in the Anthropic API
@@ -40,6 +69,9 @@ type AnthropicResponse struct {
 	Usage        AnthropicUsage          `json:"usage"`
 	StopReason   string                  `json:"stop_reason,omitempty"`
 	StopSequence string                  `json:"stop_sequence,omitempty"`
+
+	// Warnings detected from the response headers and model, not part of the API payload
+	Warnings []types.Warning `json:"-"`
 }
 
 // AnthropicContentBlock represents a content block in the response
@@ -51,12 +83,29 @@ type AnthropicContentBlock struct {
 	Input     map[string]interface{} `json:"input,omitempty"`       // for tool_use
 	ToolUseID string                 `json:"tool_use_id,omitempty"` // for tool_result
 	Content   interface{}            `json:"content,omitempty"`     // for tool_result, can be string or array
+
+	// Sources of a text block, from web search or documents with citations enabled
+	Citations []AnthropicCitation `json:"citations,omitempty"`
+}
+
+// AnthropicCitation is a source cited by a text block. Web search results have URL
+// and Title; document citations have DocumentTitle.
+type AnthropicCitation struct {
+	Type          string `json:"type"` // e.g. "web_search_result_location", "char_location"
+	CitedText     string `json:"cited_text,omitempty"`
+	URL           string `json:"url,omitempty"`
+	Title         string `json:"title,omitempty"`
+	DocumentTitle string `json:"document_title,omitempty"`
 }
 
 // AnthropicUsage represents token usage information
 type AnthropicUsage struct {
 	InputTokens  int `json:"input_tokens"`
 	OutputTokens int `json:"output_tokens"`
+
+	// Prompt caching; input_tokens counts only the tokens after the last cache breakpoint
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
 }
 
 // AnthropicErrorResponse represents an error response
@@ -11,9 +11,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
+const (
+	// latencyEWMAAlpha is the weight of the newest request in ProviderMetrics.EWMALatency
+	latencyEWMAAlpha = 0.2
+
+	// latencySampleSize is the number of recent requests ProviderMetrics latency
+	// percentiles are taken over
+	latencySampleSize = 256
+)
+
 // BaseProvider provides common functionality for all providers
 type BaseProvider struct {
 	name                 string
@@ -22,6 +32,7 @@ type BaseProvider struct {
 	logger               *log.Logger
 	mutex                sync.RWMutex
 	metrics              types.ProviderMetrics
+	latencies            *metrics.Histogram
 	metricsCollector     types.MetricsCollector
 	enableVerboseLogging bool
 }
@@ -34,6 +45,7 @@ func NewBaseProvider(name string, config types.ProviderConfig, client *http.Clie
 		client:               client,
 		logger:               logger,
 		enableVerboseLogging: config.EnableVerboseLogging,
+		latencies:            metrics.NewHistogram(latencySampleSize),
 		metrics: types.ProviderMetrics{
 			RequestCount: 0,
 			SuccessCount: 0,
@@ -101,6 +113,13 @@ func (p *BaseProvider) SetMetricsCollector(collector types.MetricsCollector) {
 	p.metricsCollector = collector
 }
 
+// GetMetricsCollector returns the metrics collector set for this provider, or nil
+func (p *BaseProvider) GetMetricsCollector() types.MetricsCollector {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.metricsCollector
+}
+
 func (p *BaseProvider) GetModels(ctx context.Context) ([]types.Model, error) {
 	return []types.Model{}, nil
 }
@@ -179,7 +198,35 @@ func (p *BaseProvider) HealthCheck(ctx context.Context) error {
 func (p *BaseProvider) GetMetrics() types.ProviderMetrics {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
-	return p.metrics
+	return p.withLatencyPercentiles(p.metrics)
+}
+
+// withLatencyPercentiles fills in the latency percentiles of m from the recent samples
+func (p *BaseProvider) withLatencyPercentiles(m types.ProviderMetrics) types.ProviderMetrics {
+	if p.latencies == nil {
+		return m
+	}
+	latency := p.latencies.GetLatencyMetrics()
+	m.P50Latency = latency.P50Latency
+	m.P95Latency = latency.P95Latency
+	m.P99Latency = latency.P99Latency
+	return m
+}
+
+// ResetMetrics zeroes the metric counters, see types.MetricsResetter
+func (p *BaseProvider) ResetMetrics() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.metrics.ResetCounters()
+}
+
+// SnapshotMetrics returns the current metrics and resets the counters atomically
+func (p *BaseProvider) SnapshotMetrics() types.ProviderMetrics {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	snapshot := p.withLatencyPercentiles(p.metrics)
+	p.metrics.ResetCounters()
+	return snapshot
 }
 
 // IncrementRequestCount increments the request counter
@@ -228,6 +275,16 @@ func (p *BaseProvider) RecordSuccessWithModel(ctx context.Context, latency time.
 		p.metrics.AverageLatency = p.metrics.TotalLatency / time.Duration(p.metrics.SuccessCount)
 	}
 
+	// Track recent latency
+	if p.metrics.EWMALatency == 0 {
+		p.metrics.EWMALatency = latency
+	} else {
+		p.metrics.EWMALatency = time.Duration(latencyEWMAAlpha*float64(latency) + (1-latencyEWMAAlpha)*float64(p.metrics.EWMALatency))
+	}
+	if p.latencies != nil {
+		p.latencies.Add(latency)
+	}
+
 	collector := p.metricsCollector
 	p.mutex.Unlock()
 
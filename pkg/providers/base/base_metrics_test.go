@@ -511,3 +511,116 @@ func TestBaseProvider_ConcurrentMetricsWithCollector(t *testing.T) {
 		t.Errorf("Expected SuccessfulRequests to be %d, got %d", numGoroutines, snapshot.SuccessfulRequests)
 	}
 }
+
+// TestBaseProvider_SnapshotMetrics tests that SnapshotMetrics returns the counters and resets them
+func TestBaseProvider_SnapshotMetrics(t *testing.T) {
+	provider := NewBaseProvider("test-provider", types.ProviderConfig{Type: types.ProviderTypeOpenAI}, &http.Client{}, nil)
+	ctx := context.Background()
+
+	provider.RecordRequest(ctx, "gpt-4")
+	provider.RecordSuccessWithModel(ctx, 200*time.Millisecond, 30, "gpt-4")
+	provider.RecordRequest(ctx, "gpt-4")
+	provider.RecordErrorWithModel(ctx, errors.New("boom"), "gpt-4", "server_error")
+
+	snapshot := provider.SnapshotMetrics()
+	if snapshot.RequestCount != 2 || snapshot.SuccessCount != 1 || snapshot.ErrorCount != 1 {
+		t.Errorf("Unexpected snapshot counters: %+v", snapshot)
+	}
+	if snapshot.TokensUsed != 30 || snapshot.AverageLatency != 200*time.Millisecond {
+		t.Errorf("Unexpected snapshot tokens or latency: %+v", snapshot)
+	}
+
+	after := provider.GetMetrics()
+	if after.RequestCount != 0 || after.SuccessCount != 0 || after.ErrorCount != 0 || after.TokensUsed != 0 || after.TotalLatency != 0 {
+		t.Errorf("Expected counters to be reset, got %+v", after)
+	}
+	if after.LastError != "boom" || after.LastRequestTime.IsZero() {
+		t.Errorf("Expected last error and times to be kept, got %+v", after)
+	}
+
+	provider.RecordRequest(ctx, "gpt-4")
+	provider.ResetMetrics()
+	if count := provider.GetMetrics().RequestCount; count != 0 {
+		t.Errorf("Expected RequestCount 0 after ResetMetrics, got %d", count)
+	}
+}
+
+// TestBaseProvider_SnapshotMetrics_Concurrent tests that periodic snapshots neither lose
+// nor double-count requests recorded concurrently
+func TestBaseProvider_SnapshotMetrics_Concurrent(t *testing.T) {
+	provider := NewBaseProvider("test-provider", types.ProviderConfig{Type: types.ProviderTypeOpenAI}, &http.Client{}, nil)
+	ctx := context.Background()
+
+	const numGoroutines = 20
+	const requestsPerGoroutine = 100
+
+	done := make(chan struct{})
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < requestsPerGoroutine; j++ {
+				provider.RecordRequest(ctx, "gpt-4")
+			}
+		}()
+	}
+
+	var total int64
+	for finished := 0; finished < numGoroutines; {
+		select {
+		case <-done:
+			finished++
+		default:
+			total += provider.SnapshotMetrics().RequestCount
+		}
+	}
+	total += provider.SnapshotMetrics().RequestCount
+
+	if total != numGoroutines*requestsPerGoroutine {
+		t.Errorf("Expected snapshots to add up to %d requests, got %d", numGoroutines*requestsPerGoroutine, total)
+	}
+}
+
+// TestBaseProvider_RecentLatency tests that EWMA latency and percentiles follow recent requests
+func TestBaseProvider_RecentLatency(t *testing.T) {
+	provider := NewBaseProvider("test-provider", types.ProviderConfig{Type: types.ProviderTypeOpenAI}, &http.Client{}, nil)
+
+	// A long slow period followed by a fast recent period
+	for i := 0; i < 1000; i++ {
+		provider.RecordSuccess(time.Second, 0)
+	}
+	for i := 0; i < latencySampleSize; i++ {
+		provider.RecordSuccess(100*time.Millisecond, 0)
+	}
+
+	m := provider.GetMetrics()
+	if m.AverageLatency < 800*time.Millisecond {
+		t.Errorf("Expected lifetime average to stay skewed by old requests, got %v", m.AverageLatency)
+	}
+	if m.EWMALatency < 100*time.Millisecond || m.EWMALatency > 110*time.Millisecond {
+		t.Errorf("Expected EWMA latency near 100ms, got %v", m.EWMALatency)
+	}
+	if m.P50Latency != 100*time.Millisecond || m.P95Latency != 100*time.Millisecond || m.P99Latency != 100*time.Millisecond {
+		t.Errorf("Expected percentiles over recent requests only, got p50=%v p95=%v p99=%v", m.P50Latency, m.P95Latency, m.P99Latency)
+	}
+
+	// A tail of slow requests shows in p99 but not p50
+	for i := 0; i < 10; i++ {
+		provider.RecordSuccess(2*time.Second, 0)
+	}
+	m = provider.GetMetrics()
+	if m.P50Latency != 100*time.Millisecond {
+		t.Errorf("Expected p50 of 100ms, got %v", m.P50Latency)
+	}
+	if m.P99Latency != 2*time.Second {
+		t.Errorf("Expected p99 of 2s, got %v", m.P99Latency)
+	}
+	if m.EWMALatency < time.Second {
+		t.Errorf("Expected EWMA latency to react to the slow requests, got %v", m.EWMALatency)
+	}
+
+	// Recent latency statistics survive a counter reset
+	provider.ResetMetrics()
+	if m := provider.GetMetrics(); m.EWMALatency == 0 || m.P50Latency == 0 || m.AverageLatency != 0 {
+		t.Errorf("Expected recent latency to be kept and the average reset, got %+v", m)
+	}
+}
@@ -339,7 +339,7 @@ func (f *ProviderFactory) parseProviderType(providerName string) (types.Provider
 		return types.ProviderTypeQwen, nil
 	case "openrouter":
 		return types.ProviderTypeOpenRouter, nil
-	case "xai", "x.ai":
+	case "xai", "x.ai", "grok":
 		return types.ProviderTypexAI, nil
 	case "fireworks":
 		return types.ProviderTypeFireworks, nil
@@ -10,15 +10,18 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
+	pkghttp "github.com/cecil-the-coder/ai-provider-kit/internal/http"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/base"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -48,9 +51,11 @@ func NewCerebrasProvider(config types.ProviderConfig) *CerebrasProvider {
 	// Merge with defaults and extract configuration
 	mergedConfig := configHelper.MergeWithDefaults(config)
 
-	client := &http.Client{
+	client := pkghttp.WithTimeouts(&http.Client{
 		Timeout: configHelper.ExtractTimeout(mergedConfig),
-	}
+	}, mergedConfig.ConnectTimeout, mergedConfig.RequestTimeout)
+	client = pkghttp.WithHeaders(pkghttp.WithCompression(client, mergedConfig.CompressRequests), mergedConfig.Headers, mergedConfig.UserAgent)
+	client = pkghttp.WithTransportWrapper(client, mergedConfig.WrapTransport)
 
 	// Create auth helper
 	authHelper := auth.NewAuthHelper("cerebras", mergedConfig, client)
@@ -142,9 +147,8 @@ func (p *CerebrasProvider) fetchModelsFromAPI(ctx context.Context) ([]types.Mode
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeCerebras, "failed to fetch models").
-			WithOperation("list_models").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeCerebras, "failed to fetch models", err).
+			WithOperation("list_models")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -279,6 +283,10 @@ func (p *CerebrasProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+
 	// Initialize request tracking
 	p.IncrementRequestCount()
 	startTime := time.Now()
@@ -291,6 +299,13 @@ func (p *CerebrasProvider) GenerateChatCompletion(
 
 	// Prepare request components
 	model := p.resolveModel(options.Model)
+	if err := common.ValidateGenerateOptions(model, options); err != nil {
+		return nil, err
+	}
+	ctx = middleware.WithModel(ctx, model)
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return nil, err
+	}
 	p.rateLimitHelper.CheckRateLimitAndWait(model, options.MaxTokens)
 	baseURL := p.getBaseURL()
 	temperature := p.resolveTemperature(options.Temperature)
@@ -475,6 +490,11 @@ func (p *CerebrasProvider) handleStreamingRequest(ctx context.Context, baseURL s
 	if p.authHelper.KeyManager != nil {
 		keys := p.authHelper.KeyManager.GetKeys()
 		for _, apiKey := range keys {
+			// Failing over to another key is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICall(ctx, url, request, apiKey)
 			if err != nil {
 				lastErr = err
@@ -615,9 +635,8 @@ func (p *CerebrasProvider) makeAPICall(ctx context.Context, url string, request
 	startTime := time.Now()
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeCerebras, "request failed").
-			WithOperation("chat_completion").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeCerebras, "request failed", err).
+			WithOperation("chat_completion")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -640,10 +659,7 @@ func (p *CerebrasProvider) makeAPICall(ctx context.Context, url string, request
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		errCode := types.ClassifyHTTPError(resp.StatusCode)
-		return nil, types.NewProviderError(types.ProviderTypeCerebras, errCode, string(body)).
-			WithOperation("chat_completion").
-			WithStatusCode(resp.StatusCode)
+		return nil, newAPIError(resp, body, "chat_completion")
 	}
 
 	var response CerebrasResponse
@@ -730,6 +746,12 @@ func (p *CerebrasProvider) SupportsToolCalling() bool {
 }
 
 // SupportsStreaming returns whether the provider supports streaming
+// SupportedParameters implements types.ParameterSupportReporter. Cerebras requests are sent
+// with a single choice and only the sampling parameters.
+func (p *CerebrasProvider) SupportedParameters(model string) types.ParameterSupport {
+	return common.SingleChoiceParameters(p, model)
+}
+
 func (p *CerebrasProvider) SupportsStreaming() bool {
 	return true
 }
@@ -788,9 +810,8 @@ func (p *CerebrasProvider) HealthCheck(ctx context.Context) error {
 	startTime := time.Now()
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		providerErr := types.NewNetworkError(types.ProviderTypeCerebras, "health check request failed").
-			WithOperation("health_check").
-			WithOriginalErr(err)
+		providerErr := types.NewRequestError(types.ProviderTypeCerebras, "health check request failed", err).
+			WithOperation("health_check")
 		p.UpdateHealthStatus(false, providerErr.Error())
 		return providerErr
 	}
@@ -826,6 +847,18 @@ func (p *CerebrasProvider) GetMetrics() types.ProviderMetrics {
 	return p.BaseProvider.GetMetrics()
 }
 
+// Warmup fetches the model list from the Cerebras API into the model cache, so the
+// first request does not wait for it
+func (p *CerebrasProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
+// RateLimitStatus returns the rate limits reported by the most recent response, see
+// types.RateLimitStatusProvider
+func (p *CerebrasProvider) RateLimitStatus() types.RateLimitStatus {
+	return p.rateLimitHelper.Status()
+}
+
 // TestConnectivity performs a lightweight connectivity test using the /v1/models endpoint
 // Results are cached for 30 seconds by default to prevent hammering the API during rapid health checks
 // To bypass the cache and force a fresh check, use TestConnectivityWithOptions with bypassCache=true
@@ -882,9 +915,8 @@ func (p *CerebrasProvider) performConnectivityTest(ctx context.Context) error {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeCerebras, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeCerebras, "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -948,9 +980,8 @@ func (p *CerebrasProvider) makeStreamingAPICall(ctx context.Context, url string,
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeCerebras, "request failed").
-			WithOperation("chat_completion_stream").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeCerebras, "request failed", err).
+			WithOperation("chat_completion_stream")
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -959,31 +990,31 @@ func (p *CerebrasProvider) makeStreamingAPICall(ctx context.Context, url string,
 			//nolint:staticcheck // Empty branch is intentional - we ignore close errors
 			_ = resp.Body.Close()
 		}()
-		errCode := types.ClassifyHTTPError(resp.StatusCode)
-		return nil, types.NewProviderError(types.ProviderTypeCerebras, errCode, string(body)).
-			WithOperation("chat_completion_stream").
-			WithStatusCode(resp.StatusCode)
+		return nil, newAPIError(resp, body, "chat_completion_stream")
 	}
 
 	// Parse rate limit headers for streaming responses
 	p.rateLimitHelper.ParseAndUpdateRateLimits(resp.Header, request.Model)
 
-	return &CerebrasRealStream{
+	return streaming.StreamFromContext(ctx, &CerebrasRealStream{
 		response: resp,
 		reader:   bufio.NewReader(resp.Body),
 		done:     false,
-	}, nil
+	}), nil
 }
 
 // CerebrasRealStream implements ChatCompletionStream for real streaming responses
 type CerebrasRealStream struct {
 	response *http.Response
 	reader   *bufio.Reader
+	events   *streaming.SSEScanner
 	done     bool
 	mutex    sync.Mutex
 }
 
-func (s *CerebrasRealStream) Next() (types.ChatCompletionChunk, error) {
+func (s *CerebrasRealStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer streaming.RecoverStreamPanic(types.ProviderTypeCerebras, &chunk, &err)
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -991,8 +1022,12 @@ func (s *CerebrasRealStream) Next() (types.ChatCompletionChunk, error) {
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
 
+	if s.events == nil {
+		s.events = streaming.NewSSEScanner(s.reader, streaming.SSEScannerOptions{DoneSentinel: streaming.SSEDone})
+	}
+
 	for {
-		line, err := s.reader.ReadString('\n')
+		event, err := s.events.Next()
 		if err != nil {
 			if err == io.EOF {
 				s.done = true
@@ -1000,23 +1035,7 @@ func (s *CerebrasRealStream) Next() (types.ChatCompletionChunk, error) {
 			}
 			return types.ChatCompletionChunk{}, err
 		}
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		if !strings.HasPrefix(line, "data: ") {
-			continue // Skip non-data lines
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
-
-		// Check for stream end
-		if data == "[DONE]" {
-			s.done = true
-			return types.ChatCompletionChunk{Done: true}, io.EOF
-		}
+		data := event.Data
 
 		var streamResp CerebrasResponse
 		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
@@ -1060,7 +1079,8 @@ func (s *CerebrasRealStream) Next() (types.ChatCompletionChunk, error) {
 							Content:   choice.Delta.Content,
 							ToolCalls: convertCerebrasToolCallsToUniversal(choice.Delta.ToolCalls),
 						},
-						FinishReason: choice.FinishReason,
+						FinishReason:    types.NormalizeFinishReason(types.ProviderTypeCerebras, choice.FinishReason),
+						RawFinishReason: choice.FinishReason,
 					},
 				}
 			}
@@ -1069,6 +1089,9 @@ func (s *CerebrasRealStream) Next() (types.ChatCompletionChunk, error) {
 				s.done = true
 				return chunk, io.EOF
 			}
+			if streaming.IsHeartbeat(chunk) {
+				continue
+			}
 
 			return chunk, nil
 		}
@@ -1158,3 +1181,15 @@ func convertToCerebrasToolChoice(toolChoice *types.ToolChoice) interface{} {
 		return "auto" // Default to auto if mode is unknown
 	}
 }
+
+// newAPIError converts an error response to a ProviderError classified by its status.
+// Rate limit errors carry the response's retry hint.
+func newAPIError(resp *http.Response, body []byte, operation string) *types.ProviderError {
+	providerErr := types.NewProviderError(types.ProviderTypeCerebras, types.ClassifyHTTPError(resp.StatusCode), string(body)).
+		WithOperation(operation).
+		WithStatusCode(resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		providerErr.WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
+	}
+	return providerErr
+}
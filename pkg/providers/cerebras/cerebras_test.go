@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -1119,3 +1120,14 @@ func TestCerebrasProvider_TestConnectivity(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid API key")
 	})
 }
+
+func TestNewAPIError_RateLimit(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "12")
+
+	var rateLimitErr *types.RateLimitError
+	if assert.ErrorAs(t, newAPIError(resp, []byte(`{"error":"rate limited"}`), "chat_completion"), &rateLimitErr) {
+		assert.Equal(t, 12*time.Second, rateLimitErr.RetryAfter)
+		assert.Equal(t, http.StatusTooManyRequests, rateLimitErr.StatusCode)
+	}
+}
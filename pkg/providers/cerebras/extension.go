@@ -224,9 +224,10 @@ func (e *CerebrasExtension) ProviderToStandard(response interface{}) (*types.Sta
 	}
 
 	standardChoice := types.StandardChoice{
-		Index:        choice.Index,
-		Message:      message,
-		FinishReason: choice.FinishReason,
+		Index:           choice.Index,
+		Message:         message,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeCerebras, choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
 
 	// Convert usage
@@ -280,9 +281,10 @@ func (e *CerebrasExtension) ProviderToStandardChunk(chunk interface{}) (*types.S
 	}
 
 	standardChoice := types.StandardStreamChoice{
-		Index:        choice.Index,
-		Delta:        delta,
-		FinishReason: choice.FinishReason,
+		Index:           choice.Index,
+		Delta:           delta,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeCerebras, choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
 
 	// Convert usage if present
@@ -164,10 +164,10 @@ func TestToolCalling_StreamingToolCalls(t *testing.T) {
 
 		// Send streaming chunks
 		chunks := []string{
-			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"message":{"role":"assistant","content":"","tool_calls":[{"id":"call_xyz","type":"function","function":{"name":"get_weather","arguments":""}}]},"finish_reason":""}]}`,
-			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"message":{"tool_calls":[{"function":{"arguments":"{\"location\""}}]},"finish_reason":""}]}`,
-			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"message":{"tool_calls":[{"function":{"arguments":":\"Boston\"}"}}]},"finish_reason":""}]}`,
-			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"message":{},"finish_reason":"tool_calls"}]}`,
+			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"delta":{"role":"assistant","content":"","tool_calls":[{"id":"call_xyz","type":"function","function":{"name":"get_weather","arguments":""}}]},"finish_reason":""}]}`,
+			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"delta":{"tool_calls":[{"function":{"arguments":"{\"location\""}}]},"finish_reason":""}]}`,
+			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"delta":{"tool_calls":[{"function":{"arguments":":\"Boston\"}"}}]},"finish_reason":""}]}`,
+			`data: {"id":"chatcmpl-123","object":"chat.completion.chunk","created":1234567890,"model":"zai-glm-4.6","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
 			`data: [DONE]`,
 		}
 
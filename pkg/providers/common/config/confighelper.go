@@ -46,12 +46,22 @@ func (h *ConfigHelper) ValidateProviderConfig(config types.ProviderConfig) Valid
 	if config.Timeout < 0 {
 		errors = append(errors, "timeout cannot be negative")
 	}
+	if config.ConnectTimeout < 0 {
+		errors = append(errors, "connect_timeout cannot be negative")
+	}
+	if config.RequestTimeout < 0 {
+		errors = append(errors, "request_timeout cannot be negative")
+	}
 
 	// Validate max tokens if specified
 	if config.MaxTokens < 0 {
 		errors = append(errors, "max_tokens cannot be negative")
 	}
 
+	if err := ValidatePathTemplate(config.PathTemplate); err != nil {
+		errors = append(errors, err.Error())
+	}
+
 	return ValidationResult{
 		Valid:  len(errors) == 0,
 		Errors: errors,
@@ -97,6 +107,10 @@ func (h *ConfigHelper) ExtractBaseURL(config types.ProviderConfig) string {
 		return "https://portal.qwen.ai/v1"
 	case types.ProviderTypeOpenRouter:
 		return "https://openrouter.ai/api/v1"
+	case types.ProviderTypexAI:
+		return "https://api.x.ai/v1"
+	case types.ProviderTypeDeepseek:
+		return "https://api.deepseek.com"
 	default:
 		return ""
 	}
@@ -249,6 +263,10 @@ func (h *ConfigHelper) GetProviderCapabilities() (supportsToolCalling, supportsS
 		return true, true, false
 	case types.ProviderTypeOpenRouter:
 		return true, true, false
+	case types.ProviderTypexAI:
+		return true, true, false
+	case types.ProviderTypeDeepseek:
+		return true, true, false
 	default:
 		return false, false, false
 	}
@@ -55,6 +55,22 @@ func TestConfigHelper_ValidateProviderConfig(t *testing.T) {
 			},
 			expectValid: false,
 		},
+		{
+			name: "negative connect timeout",
+			config: types.ProviderConfig{
+				Type:           types.ProviderTypeOpenAI,
+				ConnectTimeout: -time.Second,
+			},
+			expectValid: false,
+		},
+		{
+			name: "negative request timeout",
+			config: types.ProviderConfig{
+				Type:           types.ProviderTypeOpenAI,
+				RequestTimeout: -time.Second,
+			},
+			expectValid: false,
+		},
 		{
 			name: "negative max tokens",
 			config: types.ProviderConfig{
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Placeholders recognized in ProviderConfig.PathTemplate
+const (
+	PathTemplateBaseURL = "{base_url}"
+	PathTemplatePath    = "{path}"
+)
+
+// ValidatePathTemplate checks that a path template contains the {path} placeholder, which
+// receives the operation path (e.g. "chat/completions"), and that it produces a valid
+// URL. An empty template is valid and means BaseURL + "/" + path.
+func ValidatePathTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if !strings.Contains(template, PathTemplatePath) {
+		return fmt.Errorf("path_template %q must contain the %s placeholder", template, PathTemplatePath)
+	}
+	if strings.Count(template, PathTemplatePath) > 1 {
+		return fmt.Errorf("path_template %q must contain the %s placeholder only once", template, PathTemplatePath)
+	}
+	if !strings.HasPrefix(template, PathTemplateBaseURL) {
+		if _, err := parseEndpointURL(strings.Replace(template, PathTemplatePath, "path", 1)); err != nil {
+			return fmt.Errorf("path_template %q: %w", template, err)
+		}
+	}
+	return nil
+}
+
+// BuildEndpointURL returns the URL of an operation. Without a template the operation
+// path is appended to baseURL. With a template, {base_url} is replaced by baseURL and
+// {path} by the operation path:
+//
+//	BuildEndpointURL("https://api.openai.com/v1", "", "chat/completions")
+//	    // https://api.openai.com/v1/chat/completions
+//	BuildEndpointURL("", "https://gw.internal/ai/openai/v1/{path}", "chat/completions")
+//	    // https://gw.internal/ai/openai/v1/chat/completions
+//	BuildEndpointURL("https://gw.internal", "{base_url}/api/{path}", "models")
+//	    // https://gw.internal/api/models
+//
+// It returns an error when the template lacks {path} or the result is not an absolute
+// http(s) URL.
+func BuildEndpointURL(baseURL, template, path string) (string, error) {
+	path = strings.TrimPrefix(path, "/")
+
+	var endpoint string
+	if template == "" {
+		endpoint = strings.TrimSuffix(baseURL, "/") + "/" + path
+	} else {
+		if err := ValidatePathTemplate(template); err != nil {
+			return "", err
+		}
+		endpoint = strings.Replace(template, PathTemplateBaseURL, strings.TrimSuffix(baseURL, "/"), 1)
+		endpoint = strings.Replace(endpoint, PathTemplatePath, path, 1)
+	}
+
+	if _, err := parseEndpointURL(endpoint); err != nil {
+		return "", fmt.Errorf("invalid endpoint URL for %q: %w", path, err)
+	}
+	return endpoint, nil
+}
+
+// parseEndpointURL parses an endpoint URL and requires it to be absolute http(s)
+func parseEndpointURL(endpoint string) (*url.URL, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%q is not an http or https URL", endpoint)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("%q has no host", endpoint)
+	}
+	return parsed, nil
+}
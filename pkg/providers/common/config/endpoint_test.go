@@ -0,0 +1,61 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildEndpointURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		template string
+		path     string
+		want     string
+		wantErr  string
+	}{
+		{name: "no template", baseURL: "https://api.openai.com/v1", path: "chat/completions", want: "https://api.openai.com/v1/chat/completions"},
+		{name: "no template trailing slash", baseURL: "https://api.openai.com/v1/", path: "/models", want: "https://api.openai.com/v1/models"},
+		{name: "absolute template", template: "https://gw.internal/ai/openai/v1/{path}", path: "chat/completions", want: "https://gw.internal/ai/openai/v1/chat/completions"},
+		{name: "base url template", baseURL: "https://gw.internal/", template: "{base_url}/api/{path}", path: "models", want: "https://gw.internal/api/models"},
+		{name: "unversioned gateway", baseURL: "http://localhost:8080", template: "{base_url}/{path}", path: "chat/completions", want: "http://localhost:8080/chat/completions"},
+		{name: "missing path placeholder", template: "https://gw.internal/v1/chat/completions", path: "chat/completions", wantErr: "must contain the {path} placeholder"},
+		{name: "repeated path placeholder", template: "https://gw.internal/{path}/{path}", path: "models", wantErr: "only once"},
+		{name: "relative template", template: "gw.internal/{path}", path: "models", wantErr: "not an http or https URL"},
+		{name: "empty base url", template: "{base_url}/{path}", path: "models", wantErr: "not an http or https URL"},
+		{name: "no host", baseURL: "https:///v1", path: "models", wantErr: "has no host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildEndpointURL(tt.baseURL, tt.template, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, expected it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePathTemplate(t *testing.T) {
+	if err := ValidatePathTemplate(""); err != nil {
+		t.Errorf("empty template should be valid, got %v", err)
+	}
+	if err := ValidatePathTemplate("{base_url}/api/{path}"); err != nil {
+		t.Errorf("base url template should be valid, got %v", err)
+	}
+	if err := ValidatePathTemplate("https://gw.internal/{path}"); err != nil {
+		t.Errorf("absolute template should be valid, got %v", err)
+	}
+	if err := ValidatePathTemplate("https://gw.internal/v1"); err == nil {
+		t.Error("expected an error for a template without {path}")
+	}
+}
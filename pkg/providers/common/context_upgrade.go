@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
+)
+
+// EstimateRequestTokens estimates the context window a request needs: its prompt and
+// messages, counted with the provider's tokenizer (see utils.TokenizerFor), plus
+// MaxTokens for the response
+func EstimateRequestTokens(config types.ProviderConfig, options types.GenerateOptions) int {
+	tokenizer := utils.TokenizerFor(config)
+	return tokenizer.CountTokens(options.Prompt) + tokenizer.CountMessages(options.Messages) + options.MaxTokens
+}
+
+// ModelContextWindow returns model's context window: its ModelCapabilities override,
+// then the embedded models.dev defaults for the provider type, or 0 when unknown
+func ModelContextWindow(config types.ProviderConfig, model string) int {
+	if window := types.ModelContextWindow(config, model); window > 0 {
+		return window
+	}
+	return models.GetContextWindow(config.Type, model)
+}
+
+// NeedsContextUpgrade reports whether a request's model is opted into context upgrades
+// (ModelCapabilityOverride.ContextUpgrades) and the request's estimated tokens exceed
+// the model's context window (see ModelContextWindow). An empty model uses the
+// configured default model.
+func NeedsContextUpgrade(config types.ProviderConfig, options types.GenerateOptions) bool {
+	model := options.Model
+	if model == "" {
+		model = config.DefaultModel
+	}
+	window := ModelContextWindow(config, model)
+	if window == 0 || len(config.ModelCapabilities[model].ContextUpgrades) == 0 {
+		return false
+	}
+	return EstimateRequestTokens(config, options) > window
+}
+
+// SelectContextUpgrade returns the first of model's ContextUpgrades whose context
+// window fits tokens. An upgrade whose context window is neither configured nor in the
+// models.dev defaults is assumed to fit.
+// It returns a context length error when none fits.
+func SelectContextUpgrade(config types.ProviderConfig, model string, tokens int) (string, error) {
+	for _, candidate := range config.ModelCapabilities[model].ContextUpgrades {
+		window := ModelContextWindow(config, candidate)
+		if window == 0 || tokens <= window {
+			return candidate, nil
+		}
+	}
+	return "", types.NewContextLengthError(config.Type, fmt.Sprintf(
+		"request needs an estimated %d tokens, more than the %d-token context window of %s and of each of its context upgrades %v",
+		tokens, ModelContextWindow(config, model), model, config.ModelCapabilities[model].ContextUpgrades)).
+		WithOperation("context_upgrade")
+}
+
+// GenerateWithContextUpgrade sends a request that is too large for its model (see
+// NeedsContextUpgrade) to the first larger-context model configured for it, rather than
+// letting the provider reject or truncate it. It returns a context length error when
+// no configured model fits.
+//
+// Every chunk of the returned stream carries the original model in its metadata under
+// "context_upgraded_from" and the model used under "context_upgraded_to".
+//
+// Providers reach it through GenerateWithStrategies.
+func GenerateWithContextUpgrade(ctx context.Context, options types.GenerateOptions, config types.ProviderConfig, generate GenerateFunc) (types.ChatCompletionStream, error) {
+	model := options.Model
+	if model == "" {
+		model = config.DefaultModel
+	}
+
+	upgrade, err := SelectContextUpgrade(config, model, EstimateRequestTokens(config, options))
+	if err != nil {
+		return nil, err
+	}
+
+	upgraded := options
+	upgraded.Model = upgrade
+	stream, err := generate(ctx, upgraded)
+	if err != nil {
+		return nil, err
+	}
+	return &contextUpgradeStream{inner: stream, from: model, to: upgrade}, nil
+}
+
+// contextUpgradeStream tags each chunk with the model substitution
+type contextUpgradeStream struct {
+	inner types.ChatCompletionStream
+	from  string
+	to    string
+}
+
+func (s *contextUpgradeStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]interface{})
+	}
+	chunk.Metadata["context_upgraded_from"] = s.from
+	chunk.Metadata["context_upgraded_to"] = s.to
+	return chunk, err
+}
+
+func (s *contextUpgradeStream) Close() error {
+	return s.inner.Close()
+}
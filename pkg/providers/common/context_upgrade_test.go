@@ -0,0 +1,87 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func intPtr(n int) *int { return &n }
+
+func contextUpgradeConfig() types.ProviderConfig {
+	return types.ProviderConfig{
+		Type:         types.ProviderTypeAnthropic,
+		DefaultModel: "small",
+		ModelCapabilities: map[string]types.ModelCapabilityOverride{
+			"small":  {ContextWindow: intPtr(100), ContextUpgrades: []string{"medium", "large"}},
+			"medium": {ContextWindow: intPtr(1000)},
+			"large":  {ContextWindow: intPtr(10000)},
+			"plain":  {ContextWindow: intPtr(100)},
+		},
+	}
+}
+
+// promptOfTokens returns a prompt the heuristic tokenizer counts as about n tokens
+func promptOfTokens(n int) string {
+	return strings.Repeat("a", n*47/10+1)
+}
+
+func TestNeedsContextUpgrade(t *testing.T) {
+	config := contextUpgradeConfig()
+
+	assert.False(t, NeedsContextUpgrade(config, types.GenerateOptions{Model: "small", Prompt: "hi"}))
+	assert.True(t, NeedsContextUpgrade(config, types.GenerateOptions{Model: "small", Prompt: promptOfTokens(200)}))
+	assert.True(t, NeedsContextUpgrade(config, types.GenerateOptions{Prompt: "hi", MaxTokens: 200}), "MaxTokens and the default model count")
+	assert.False(t, NeedsContextUpgrade(config, types.GenerateOptions{Model: "plain", Prompt: promptOfTokens(200)}), "upgrades are opt-in")
+}
+
+func TestSelectContextUpgrade_KnownModels(t *testing.T) {
+	config := types.ProviderConfig{
+		Type: types.ProviderTypeOpenAI,
+		ModelCapabilities: map[string]types.ModelCapabilityOverride{
+			"gpt-3.5-turbo": {ContextUpgrades: []string{"gpt-4", "gpt-4o"}},
+		},
+	}
+
+	// gpt-4's 8192-token window comes from the models.dev defaults
+	upgrade, err := SelectContextUpgrade(config, "gpt-3.5-turbo", 50000)
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", upgrade)
+
+	_, err = SelectContextUpgrade(config, "gpt-3.5-turbo", 200000)
+	assert.Error(t, err, "no known window fits")
+
+	assert.True(t, NeedsContextUpgrade(config, types.GenerateOptions{Model: "gpt-3.5-turbo", Prompt: promptOfTokens(20000)}))
+}
+
+func TestGenerateWithContextUpgrade(t *testing.T) {
+	config := contextUpgradeConfig()
+
+	var used string
+	generate := func(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+		used = options.Model
+		return streaming.NewMockStream([]types.ChatCompletionChunk{{Content: "ok"}}), nil
+	}
+
+	stream, err := GenerateWithContextUpgrade(context.Background(), types.GenerateOptions{Model: "small", Prompt: promptOfTokens(2000)}, config, generate)
+	require.NoError(t, err)
+	assert.Equal(t, "large", used, "medium is too small")
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "small", chunk.Metadata["context_upgraded_from"])
+	assert.Equal(t, "large", chunk.Metadata["context_upgraded_to"])
+
+	_, err = GenerateWithContextUpgrade(context.Background(), types.GenerateOptions{Model: "small", Prompt: promptOfTokens(20000)}, config, generate)
+	require.Error(t, err)
+	var providerErr *types.ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, types.ErrCodeContextLength, providerErr.Code)
+	assert.Contains(t, err.Error(), "small")
+}
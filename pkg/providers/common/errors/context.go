@@ -40,6 +40,9 @@ type ErrorContext struct {
 
 	// Response is a snapshot of the HTTP response (if available)
 	Response *ResponseSnapshot
+
+	// Stack is the goroutine stack trace, for errors recovered from a panic
+	Stack string
 }
 
 // RequestSnapshot captures key information from an HTTP request for debugging
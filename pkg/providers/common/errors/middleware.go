@@ -20,6 +20,9 @@ const (
 	ContextKeyCorrelationID ContextKey = "errors:correlation_id"
 )
 
+// DefaultMasker can be passed to middleware.NewSamplingLogMiddleware
+var _ middleware.LogMasker = (*DefaultMasker)(nil)
+
 // ErrorContextMiddleware captures error context automatically for requests
 type ErrorContextMiddleware struct {
 	// provider is the provider name
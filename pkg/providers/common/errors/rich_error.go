@@ -99,6 +99,12 @@ func (e *RichError) WithTimingStart(startTime time.Time) *RichError {
 	return e
 }
 
+// WithStack sets the stack trace and returns the error for chaining
+func (e *RichError) WithStack(stack []byte) *RichError {
+	e.context.Stack = string(stack)
+	return e
+}
+
 // WithRequestSnapshot creates and attaches a request snapshot
 func (e *RichError) WithRequestSnapshot(req *http.Request) *RichError {
 	if req != nil {
@@ -135,6 +141,10 @@ func (e *RichError) Format() string {
 		e.formatContext(&b)
 		e.formatRequestSnapshot(&b)
 		e.formatResponseSnapshot(&b)
+		if e.context.Stack != "" {
+			b.WriteString("\nStack:\n")
+			b.WriteString(e.context.Stack)
+		}
 	}
 
 	return b.String()
@@ -133,6 +133,17 @@ func TestRichError_WithTiming(t *testing.T) {
 	}
 }
 
+func TestRichError_WithStack(t *testing.T) {
+	richErr := NewRichError(errors.New("test error")).WithStack([]byte("goroutine 1 [running]:\nmain.main()"))
+
+	if richErr.context.Stack != "goroutine 1 [running]:\nmain.main()" {
+		t.Errorf("Expected stack to be set, got: %s", richErr.context.Stack)
+	}
+	if !strings.Contains(richErr.Format(), "Stack:\ngoroutine 1 [running]:") {
+		t.Errorf("Expected formatted error to include the stack, got: %s", richErr.Format())
+	}
+}
+
 func TestRichError_Format(t *testing.T) {
 	baseErr := errors.New("test error message")
 	req := httptest.NewRequest("GET", "https://api.anthropic.com/v1/messages", nil)
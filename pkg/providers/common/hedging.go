@@ -0,0 +1,120 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// hedgeAttemptKey marks the context of a hedged attempt, so the provider serves it
+// directly instead of hedging it again
+type hedgeAttemptKey struct{}
+
+// ShouldHedge reports whether a provider should hedge a request: hedgeAfter is set,
+// the request is not streaming, and ctx is not already one of the hedged attempts.
+// Providers check it through GenerateWithStrategies.
+func ShouldHedge(ctx context.Context, options types.GenerateOptions, hedgeAfter time.Duration) bool {
+	return hedgeAfter > 0 && !options.Stream && ctx.Value(hedgeAttemptKey{}) == nil
+}
+
+// hedgeResult is the outcome of one hedged attempt
+type hedgeResult struct {
+	index  int
+	stream types.ChatCompletionStream
+	err    error
+}
+
+// GenerateWithHedging calls generate and, if it has not returned within hedgeAfter,
+// calls it a second time with the same options. The first successful attempt is used
+// and the other is cancelled. An attempt that fails does not end the request while the
+// other is still running; the first error is returned when both fail, or when the
+// first attempt fails before the second was started.
+//
+// generate must return only once the response is complete, as providers do for
+// non-streaming requests, since the winner's context is cancelled on return.
+//
+// Every chunk of the returned stream carries "hedged" (whether a second request was
+// sent) and "hedge_winner" (0 for the original request, 1 for the hedge) metadata.
+func GenerateWithHedging(ctx context.Context, options types.GenerateOptions, hedgeAfter time.Duration, generate GenerateFunc) (types.ChatCompletionStream, error) {
+	ctx = context.WithValue(ctx, hedgeAttemptKey{}, true)
+
+	results := make(chan hedgeResult, 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	start := func(index int) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func() {
+			stream, err := generate(attemptCtx, options)
+			results <- hedgeResult{index: index, stream: stream, err: err}
+		}()
+	}
+
+	start(0)
+	timer := time.NewTimer(hedgeAfter)
+	defer timer.Stop()
+
+	pending := 1
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			start(1)
+			pending++
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				// The deferred cancels stop the losing attempt
+				go drainHedgeResults(results, pending)
+				return &hedgedStream{inner: result.stream, hedged: len(cancels) > 1, winner: result.index}, nil
+			}
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			if pending == 0 {
+				// Both attempts failed, or the original failed before the hedge was due
+				return nil, firstErr
+			}
+		case <-ctx.Done():
+			go drainHedgeResults(results, pending)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// drainHedgeResults closes the streams of attempts that complete after the request
+// has been decided
+func drainHedgeResults(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		if result := <-results; result.stream != nil {
+			_ = result.stream.Close()
+		}
+	}
+}
+
+// hedgedStream tags each chunk with the outcome of hedging
+type hedgedStream struct {
+	inner  types.ChatCompletionStream
+	hedged bool
+	winner int
+}
+
+func (s *hedgedStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]interface{})
+	}
+	chunk.Metadata["hedged"] = s.hedged
+	chunk.Metadata["hedge_winner"] = s.winner
+	return chunk, err
+}
+
+func (s *hedgedStream) Close() error {
+	return s.inner.Close()
+}
@@ -0,0 +1,152 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// hedgeAttempts returns a GenerateFunc whose n-th call waits delays[n] (or until its
+// context is cancelled) and then returns errs[n], recording cancelled attempts
+type hedgeAttempts struct {
+	delays    []time.Duration
+	errs      []error
+	calls     atomic.Int32
+	cancelled atomic.Int32
+	wg        sync.WaitGroup
+}
+
+func (h *hedgeAttempts) generate(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+	h.wg.Add(1)
+	defer h.wg.Done()
+
+	n := int(h.calls.Add(1)) - 1
+	select {
+	case <-time.After(h.delays[n]):
+	case <-ctx.Done():
+		h.cancelled.Add(1)
+		return nil, ctx.Err()
+	}
+	if n < len(h.errs) && h.errs[n] != nil {
+		return nil, h.errs[n]
+	}
+	return streaming.NewMockStream([]types.ChatCompletionChunk{{Content: options.Prompt, Done: true}}), nil
+}
+
+func TestShouldHedge(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, ShouldHedge(ctx, types.GenerateOptions{}, time.Second))
+	assert.False(t, ShouldHedge(ctx, types.GenerateOptions{}, 0))
+	assert.False(t, ShouldHedge(ctx, types.GenerateOptions{Stream: true}, time.Second))
+
+	var hedgedCtx context.Context
+	attempts := func(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+		hedgedCtx = ctx
+		return streaming.NewMockStream(nil), nil
+	}
+	_, err := GenerateWithHedging(ctx, types.GenerateOptions{}, time.Second, attempts)
+	require.NoError(t, err)
+	assert.False(t, ShouldHedge(hedgedCtx, types.GenerateOptions{}, time.Second), "attempts must not hedge again")
+}
+
+func TestGenerateWithHedging(t *testing.T) {
+	t.Run("fast response is not hedged", func(t *testing.T) {
+		h := &hedgeAttempts{delays: []time.Duration{5 * time.Millisecond}}
+
+		stream, err := GenerateWithHedging(context.Background(), types.GenerateOptions{Prompt: "hi"}, 200*time.Millisecond, h.generate)
+		require.NoError(t, err)
+
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "hi", chunk.Content)
+		assert.Equal(t, false, chunk.Metadata["hedged"])
+		assert.Equal(t, 0, chunk.Metadata["hedge_winner"])
+		assert.Equal(t, int32(1), h.calls.Load())
+	})
+
+	t.Run("hedge wins and the slow request is cancelled", func(t *testing.T) {
+		h := &hedgeAttempts{delays: []time.Duration{10 * time.Second, 5 * time.Millisecond}}
+
+		start := time.Now()
+		stream, err := GenerateWithHedging(context.Background(), types.GenerateOptions{Prompt: "hi"}, 20*time.Millisecond, h.generate)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		assert.Equal(t, true, chunk.Metadata["hedged"])
+		assert.Equal(t, 1, chunk.Metadata["hedge_winner"])
+
+		h.wg.Wait()
+		assert.Equal(t, int32(2), h.calls.Load())
+		assert.Equal(t, int32(1), h.cancelled.Load())
+	})
+
+	t.Run("original wins after hedging", func(t *testing.T) {
+		h := &hedgeAttempts{delays: []time.Duration{40 * time.Millisecond, 10 * time.Second}}
+
+		stream, err := GenerateWithHedging(context.Background(), types.GenerateOptions{}, 10*time.Millisecond, h.generate)
+		require.NoError(t, err)
+
+		chunk, _ := stream.Next()
+		assert.Equal(t, true, chunk.Metadata["hedged"])
+		assert.Equal(t, 0, chunk.Metadata["hedge_winner"])
+
+		h.wg.Wait()
+		assert.Equal(t, int32(1), h.cancelled.Load())
+	})
+
+	t.Run("failed attempt waits for the other", func(t *testing.T) {
+		failure := errors.New("overloaded")
+		h := &hedgeAttempts{
+			delays: []time.Duration{30 * time.Millisecond, 50 * time.Millisecond},
+			errs:   []error{failure},
+		}
+
+		stream, err := GenerateWithHedging(context.Background(), types.GenerateOptions{}, 10*time.Millisecond, h.generate)
+		require.NoError(t, err)
+		chunk, _ := stream.Next()
+		assert.Equal(t, 1, chunk.Metadata["hedge_winner"])
+	})
+
+	t.Run("both attempts fail", func(t *testing.T) {
+		first, second := errors.New("first"), errors.New("second")
+		h := &hedgeAttempts{
+			delays: []time.Duration{20 * time.Millisecond, 60 * time.Millisecond},
+			errs:   []error{first, second},
+		}
+
+		_, err := GenerateWithHedging(context.Background(), types.GenerateOptions{}, 10*time.Millisecond, h.generate)
+		assert.ErrorIs(t, err, first)
+	})
+
+	t.Run("failure before the hedge is due is returned", func(t *testing.T) {
+		failure := errors.New("bad request")
+		h := &hedgeAttempts{delays: []time.Duration{time.Millisecond}, errs: []error{failure}}
+
+		_, err := GenerateWithHedging(context.Background(), types.GenerateOptions{}, time.Second, h.generate)
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, int32(1), h.calls.Load())
+	})
+
+	t.Run("caller cancellation stops both attempts", func(t *testing.T) {
+		h := &hedgeAttempts{delays: []time.Duration{10 * time.Second, 10 * time.Second}}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := GenerateWithHedging(ctx, types.GenerateOptions{}, 10*time.Millisecond, h.generate)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		h.wg.Wait()
+		assert.Equal(t, int32(2), h.cancelled.Load())
+	})
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/google/uuid"
+)
+
+// Translation between the unified tool types and OpenAI's legacy function calling,
+// types.ToolFormatOpenAIFunctions. The legacy format sends tools as "functions",
+// tool_choice as "function_call", and reports at most one call per response in the
+// assistant message's "function_call", without an ID. Tool results are sent as
+// messages with role "function" and the function's name instead of a tool_call_id.
+
+// LegacyFunction is a function definition in the legacy "functions" request field
+type LegacyFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"` // JSON Schema
+}
+
+// LegacyFunctionCall is a call in the legacy "function_call" message field
+type LegacyFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"` // JSON string
+}
+
+// LegacyFunctionRole is the message role of tool results in the legacy format
+const LegacyFunctionRole = "function"
+
+// ToolsToLegacyFunctions converts tools to legacy function definitions
+func ToolsToLegacyFunctions(tools []types.Tool) []LegacyFunction {
+	if len(tools) == 0 {
+		return nil
+	}
+	functions := make([]LegacyFunction, len(tools))
+	for i, tool := range tools {
+		parameters := tool.InputSchema
+		if parameters == nil {
+			parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		functions[i] = LegacyFunction{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  parameters,
+		}
+	}
+	return functions
+}
+
+// ToolChoiceToLegacyFunctionCall converts a tool choice to the legacy "function_call"
+// request value: "auto", "none", or {"name": ...}. The legacy format cannot require a
+// call to any function, so ToolChoiceRequired forces the only tool when there is
+// exactly one, and falls back to "auto" otherwise. A nil choice returns nil, leaving
+// the provider's default.
+func ToolChoiceToLegacyFunctionCall(choice *types.ToolChoice, tools []types.Tool) interface{} {
+	if choice == nil {
+		return nil
+	}
+	switch choice.Mode {
+	case types.ToolChoiceNone:
+		return "none"
+	case types.ToolChoiceSpecific:
+		return map[string]interface{}{"name": choice.FunctionName}
+	case types.ToolChoiceRequired:
+		if len(tools) == 1 {
+			return map[string]interface{}{"name": tools[0].Name}
+		}
+		return "auto"
+	default:
+		return "auto"
+	}
+}
+
+// ToolCallsToLegacyFunctionCall converts an assistant message's tool calls to its
+// legacy function call. The legacy format has one call per message, so only the
+// first is kept; it returns nil when there are none.
+func ToolCallsToLegacyFunctionCall(toolCalls []types.ToolCall) *LegacyFunctionCall {
+	if len(toolCalls) == 0 {
+		return nil
+	}
+	return &LegacyFunctionCall{
+		Name:      toolCalls[0].Function.Name,
+		Arguments: toolCalls[0].Function.Arguments,
+	}
+}
+
+// LegacyFunctionCallToToolCall converts a legacy function call from a response to a
+// tool call. Legacy calls have no ID, so one is generated for matching the result.
+func LegacyFunctionCallToToolCall(call LegacyFunctionCall) types.ToolCall {
+	return types.ToolCall{
+		ID:   NewLegacyFunctionCallID(),
+		Type: "function",
+		Function: types.ToolCallFunction{
+			Name:      call.Name,
+			Arguments: call.Arguments,
+		},
+	}
+}
+
+// NewLegacyFunctionCallID returns a tool call ID for a legacy function call
+func NewLegacyFunctionCallID() string {
+	return "call_" + uuid.New().String()
+}
+
+// LegacyFunctionNames maps the IDs of the tool calls in messages to their function
+// names, for sending tool results as legacy "function" messages, which are matched
+// to their call by name
+func LegacyFunctionNames(messages []types.ChatMessage) map[string]string {
+	names := make(map[string]string)
+	for _, message := range messages {
+		for _, call := range message.ToolCalls {
+			if call.ID != "" {
+				names[call.ID] = call.Function.Name
+			}
+		}
+	}
+	return names
+}
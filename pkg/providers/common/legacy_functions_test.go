@@ -0,0 +1,55 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolsToLegacyFunctions(t *testing.T) {
+	schema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}}}
+	functions := ToolsToLegacyFunctions([]types.Tool{
+		{Name: "get_weather", Description: "Get the weather", InputSchema: schema},
+		{Name: "now"},
+	})
+
+	require.Len(t, functions, 2)
+	assert.Equal(t, LegacyFunction{Name: "get_weather", Description: "Get the weather", Parameters: schema}, functions[0])
+	assert.Equal(t, "object", functions[1].Parameters["type"], "a missing schema becomes an empty object schema")
+	assert.Nil(t, ToolsToLegacyFunctions(nil))
+}
+
+func TestToolChoiceToLegacyFunctionCall(t *testing.T) {
+	one := []types.Tool{{Name: "get_weather"}}
+	two := []types.Tool{{Name: "get_weather"}, {Name: "now"}}
+
+	assert.Nil(t, ToolChoiceToLegacyFunctionCall(nil, two))
+	assert.Equal(t, "auto", ToolChoiceToLegacyFunctionCall(&types.ToolChoice{Mode: types.ToolChoiceAuto}, two))
+	assert.Equal(t, "none", ToolChoiceToLegacyFunctionCall(&types.ToolChoice{Mode: types.ToolChoiceNone}, two))
+	assert.Equal(t, map[string]interface{}{"name": "now"},
+		ToolChoiceToLegacyFunctionCall(&types.ToolChoice{Mode: types.ToolChoiceSpecific, FunctionName: "now"}, two))
+	assert.Equal(t, map[string]interface{}{"name": "get_weather"},
+		ToolChoiceToLegacyFunctionCall(&types.ToolChoice{Mode: types.ToolChoiceRequired}, one), "required with one tool forces it")
+	assert.Equal(t, "auto", ToolChoiceToLegacyFunctionCall(&types.ToolChoice{Mode: types.ToolChoiceRequired}, two))
+}
+
+func TestLegacyFunctionCallConversions(t *testing.T) {
+	call := LegacyFunctionCallToToolCall(LegacyFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`})
+	assert.True(t, strings.HasPrefix(call.ID, "call_"))
+	assert.Equal(t, "function", call.Type)
+	assert.Equal(t, "get_weather", call.Function.Name)
+	assert.Equal(t, `{"city":"Paris"}`, call.Function.Arguments)
+
+	assert.Nil(t, ToolCallsToLegacyFunctionCall(nil))
+	assert.Equal(t, &LegacyFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		ToolCallsToLegacyFunctionCall([]types.ToolCall{call, {Function: types.ToolCallFunction{Name: "dropped"}}}))
+
+	names := LegacyFunctionNames([]types.ChatMessage{
+		{Role: "user", Content: "Weather?"},
+		{Role: "assistant", ToolCalls: []types.ToolCall{call}},
+	})
+	assert.Equal(t, map[string]string{call.ID: "get_weather"}, names)
+}
@@ -111,6 +111,27 @@
 //
 //	chain.Add(headerMiddleware).Add(statusMiddleware)
 //
+// # Streaming Responses
+//
+// ResponseMiddleware receives the whole *http.Response, and reading its body would
+// consume an SSE stream before the provider can parse it. For streaming responses,
+// implement StreamingResponseMiddleware instead. The chain wraps the response body so
+// each chunk is passed to the middleware as the provider reads it, without buffering:
+//
+//	type ByteCounter struct{ total int64 }
+//
+//	func (c *ByteCounter) OnResponseChunk(ctx context.Context, req *http.Request, chunk []byte) {
+//	    atomic.AddInt64(&c.total, int64(len(chunk)))
+//	}
+//
+//	func (c *ByteCounter) OnResponseComplete(ctx context.Context, req *http.Request, bytesRead int64, err error) {
+//	    log.Printf("stream finished: %d bytes, err=%v", bytesRead, err)
+//	}
+//
+// OnResponseComplete is called exactly once: on EOF (err == nil), on a read error, or
+// with ErrBodyClosedEarly when the body is closed before EOF (e.g. a cancelled stream).
+// A middleware may implement both ResponseMiddleware and StreamingResponseMiddleware.
+//
 // # Advanced Chain Operations
 //
 // Inserting middleware at specific positions:
@@ -138,12 +159,15 @@
 //   - ContextKeyMetadata: Arbitrary metadata map
 //   - ContextKeyError: Error information
 //   - ContextKeyRetryCount: Retry attempt count
+//   - ContextKeyIdempotencyKey: Idempotency key reused across retries (see IdempotencyMiddleware)
+//   - ContextKeyMiddlewareTimings: Per-request middleware timings (see EnableTiming)
+//   - ContextKeySampled: Whether the request is logged (see SamplingLogMiddleware)
 //
 // Using context keys:
 //
 //	// Store data in context
 //	ctx = context.WithValue(ctx, middleware.ContextKeyProvider, "openai")
-//	ctx = context.WithValue(ctx, middleware.ContextKeyModel, "gpt-4")
+//	ctx = middleware.WithModel(ctx, "gpt-4")
 //	ctx = context.WithValue(ctx, middleware.ContextKeyRetryCount, 0)
 //
 //	// Retrieve data from context
@@ -194,6 +218,48 @@
 //   - Lock-free execution after chain is built
 //   - Benchmarks show ~900ns per request with 10 middleware
 //
+// # Timing Individual Middleware
+//
+// To find a middleware that adds latency, enable timing on the chain. Each
+// ProcessRequest and ProcessResponse call is then timed, aggregated per middleware
+// name (from a Name() method if present, otherwise the Go type), and also recorded
+// per request in the context:
+//
+//	chain := middleware.NewMiddlewareChain().EnableTiming(true)
+//
+//	ctx, req, _ := chain.ProcessRequest(ctx, req)
+//	// ... HTTP call ...
+//	ctx, resp, _ = chain.ProcessResponse(ctx, req, resp)
+//
+//	for _, t := range middleware.TimingsFromContext(ctx).Entries() {
+//	    log.Printf("%s %s: %v", t.Name, t.Phase, t.Duration)
+//	}
+//	for _, s := range chain.TimingStats() {
+//	    log.Printf("%s: avg request %v, avg response %v", s.Name, s.AverageRequest(), s.AverageResponse())
+//	}
+//
+// # Provider Transports
+//
+// Transport runs a chain around every request of an http.Client, so middleware applies
+// to providers without them calling the chain. Providers created by the factory use an
+// InstrumentedTransport by default, which also sets ContextKeyRequestID,
+// ContextKeyProvider and ContextKeyStartTime, records HTTPMetrics and masks credentials
+// in transport errors. ContextKeyModel comes from the request's context, where
+// providers set it with WithModel:
+//
+//	f := factory.NewProviderFactory()
+//	factory.RegisterDefaultProviders(f)
+//	f.SetMiddlewareChain(chain)
+//	provider, _ := f.CreateProvider(types.ProviderTypeOpenAI, config)
+//
+//	metrics, _ := f.TransportMetrics(types.ProviderTypeOpenAI)
+//
+// To use another transport, set ProviderConfig.WrapTransport:
+//
+//	config.WrapTransport = func(base http.RoundTripper) http.RoundTripper {
+//	    return middleware.NewTransport(base, chain)
+//	}
+//
 // # Complete Example
 //
 //	package main
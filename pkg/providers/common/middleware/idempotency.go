@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader is the header used to deduplicate retried requests server-side
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ContextKeyIdempotencyKey stores the idempotency key for the logical request
+const ContextKeyIdempotencyKey ContextKey = "middleware:idempotency_key"
+
+// NewIdempotencyKey generates a new random idempotency key
+func NewIdempotencyKey() string {
+	return uuid.New().String()
+}
+
+// WithIdempotencyKey returns a context carrying the given idempotency key.
+// Every attempt processed with this context reuses the same key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, ContextKeyIdempotencyKey, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key stored in the context, if any
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(ContextKeyIdempotencyKey).(string)
+	return key, ok && key != ""
+}
+
+// IdempotencyMiddleware sets an Idempotency-Key header on POST requests so that a
+// retried request is not generated (and billed) twice if the first attempt succeeded.
+//
+// The key is resolved in this order: an Idempotency-Key header already on the request,
+// the key stored in the context, then a newly generated key. The resolved key is stored
+// in the returned context, so retries that reuse that context send the same key.
+// The header lives on the request itself, so it survives req.Clone and body rewinds
+// via req.GetBody.
+type IdempotencyMiddleware struct{}
+
+// NewIdempotencyMiddleware creates a new idempotency middleware
+func NewIdempotencyMiddleware() *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{}
+}
+
+// ProcessRequest implements RequestMiddleware
+func (m *IdempotencyMiddleware) ProcessRequest(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+	if req.Method != http.MethodPost {
+		return ctx, req, nil
+	}
+
+	key := req.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		var ok bool
+		if key, ok = IdempotencyKeyFromContext(ctx); !ok {
+			key = NewIdempotencyKey()
+		}
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	return WithIdempotencyKey(ctx, key), req, nil
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware_GeneratesAndStoresKey(t *testing.T) {
+	mw := NewIdempotencyMiddleware()
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/chat/completions", strings.NewReader("{}"))
+
+	ctx, req, err := mw.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	key := req.Header.Get(IdempotencyKeyHeader)
+	assert.NotEmpty(t, key)
+
+	ctxKey, ok := IdempotencyKeyFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, key, ctxKey)
+}
+
+func TestIdempotencyMiddleware_ReusesKeyAcrossRetries(t *testing.T) {
+	chain := NewMiddlewareChain()
+	chain.Add(NewIdempotencyMiddleware())
+
+	ctx, first, err := chain.ProcessRequest(context.Background(), httptest.NewRequest("POST", "https://api.example.com", strings.NewReader("{}")))
+	require.NoError(t, err)
+
+	// A retry of the same logical request reuses the context from the first attempt
+	_, second, err := chain.ProcessRequest(ctx, httptest.NewRequest("POST", "https://api.example.com", strings.NewReader("{}")))
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Header.Get(IdempotencyKeyHeader), second.Header.Get(IdempotencyKeyHeader))
+
+	// A new logical request gets a new key
+	_, third, err := chain.ProcessRequest(context.Background(), httptest.NewRequest("POST", "https://api.example.com", strings.NewReader("{}")))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Header.Get(IdempotencyKeyHeader), third.Header.Get(IdempotencyKeyHeader))
+}
+
+func TestIdempotencyMiddleware_ExplicitKeys(t *testing.T) {
+	mw := NewIdempotencyMiddleware()
+
+	t.Run("FromContext", func(t *testing.T) {
+		ctx := WithIdempotencyKey(context.Background(), "ctx-key")
+		_, req, err := mw.ProcessRequest(ctx, httptest.NewRequest("POST", "https://api.example.com", nil))
+		require.NoError(t, err)
+		assert.Equal(t, "ctx-key", req.Header.Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("HeaderTakesPrecedence", func(t *testing.T) {
+		ctx := WithIdempotencyKey(context.Background(), "ctx-key")
+		req := httptest.NewRequest("POST", "https://api.example.com", nil)
+		req.Header.Set(IdempotencyKeyHeader, "header-key")
+
+		ctx, req, err := mw.ProcessRequest(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, "header-key", req.Header.Get(IdempotencyKeyHeader))
+
+		key, _ := IdempotencyKeyFromContext(ctx)
+		assert.Equal(t, "header-key", key)
+	})
+}
+
+func TestIdempotencyMiddleware_IgnoresNonPost(t *testing.T) {
+	mw := NewIdempotencyMiddleware()
+	ctx, req, err := mw.ProcessRequest(context.Background(), httptest.NewRequest("GET", "https://api.example.com/v1/models", nil))
+	require.NoError(t, err)
+
+	assert.Empty(t, req.Header.Get(IdempotencyKeyHeader))
+	_, ok := IdempotencyKeyFromContext(ctx)
+	assert.False(t, ok)
+}
+
+func TestIdempotencyMiddleware_PreservedAcrossBodyRewind(t *testing.T) {
+	mw := NewIdempotencyMiddleware()
+	req, err := http.NewRequest("POST", "https://api.example.com", strings.NewReader(`{"model":"gpt-4"}`))
+	require.NoError(t, err)
+
+	ctx, req, err := mw.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+	key := req.Header.Get(IdempotencyKeyHeader)
+
+	// Consume the body as the first attempt would, then rewind for a retry
+	_, err = io.ReadAll(req.Body)
+	require.NoError(t, err)
+
+	retry := req.Clone(ctx)
+	require.NotNil(t, req.GetBody)
+	retry.Body, err = req.GetBody()
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(retry.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"model":"gpt-4"}`, string(body))
+	assert.Equal(t, key, retry.Header.Get(IdempotencyKeyHeader))
+
+	// Re-running the middleware on the retry keeps the same key
+	_, retry, err = mw.ProcessRequest(ctx, retry)
+	require.NoError(t, err)
+	assert.Equal(t, key, retry.Header.Get(IdempotencyKeyHeader))
+}
@@ -6,6 +6,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
 )
 
@@ -30,6 +31,15 @@ const (
 	ContextKeyRetryCount ContextKey = "middleware:retry_count"
 )
 
+// WithModel returns a context carrying the model a request is for. Providers set it
+// before sending, so middleware sees the model without reading the request body.
+func WithModel(ctx context.Context, model string) context.Context {
+	if model == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ContextKeyModel, model)
+}
+
 // RequestMiddleware transforms requests before they are sent to the provider
 type RequestMiddleware interface {
 	// ProcessRequest processes an HTTP request before sending
@@ -81,6 +91,7 @@ type MiddlewareChain interface {
 // DefaultMiddlewareChain is the default implementation of MiddlewareChain
 type DefaultMiddlewareChain struct {
 	middleware []Middleware
+	timing     *timingRecorder // nil unless EnableTiming(true)
 	mu         sync.RWMutex
 }
 
@@ -159,13 +170,29 @@ func (c *DefaultMiddlewareChain) ProcessRequest(ctx context.Context, req *http.R
 	// Create a copy of the middleware slice to avoid holding the lock during execution
 	middlewareCopy := make([]Middleware, len(c.middleware))
 	copy(middlewareCopy, c.middleware)
+	recorder := c.timing
 	c.mu.RUnlock()
 
+	var timings *MiddlewareTimings
+	if recorder != nil {
+		timings = TimingsFromContext(ctx)
+		if timings == nil {
+			timings = &MiddlewareTimings{}
+			ctx = context.WithValue(ctx, ContextKeyMiddlewareTimings, timings)
+		}
+	}
+
 	var err error
 	for _, mw := range middlewareCopy {
 		// Check if this middleware implements RequestMiddleware
 		if reqMw, ok := mw.(RequestMiddleware); ok {
-			ctx, req, err = reqMw.ProcessRequest(ctx, req)
+			if recorder != nil {
+				recorder.timed(timings, MiddlewareName(mw), PhaseRequest, func() {
+					ctx, req, err = reqMw.ProcessRequest(ctx, req)
+				})
+			} else {
+				ctx, req, err = reqMw.ProcessRequest(ctx, req)
+			}
 			if err != nil {
 				return ctx, req, err
 			}
@@ -175,21 +202,39 @@ func (c *DefaultMiddlewareChain) ProcessRequest(ctx context.Context, req *http.R
 	return ctx, req, nil
 }
 
-// ProcessResponse executes all response middleware in reverse order
+// ProcessResponse executes all response middleware in reverse order.
+// Any StreamingResponseMiddleware in the chain is attached to the response body
+// first, so it observes the body as it is read rather than consuming it.
 func (c *DefaultMiddlewareChain) ProcessResponse(ctx context.Context, req *http.Request, resp *http.Response) (context.Context, *http.Response, error) {
 	c.mu.RLock()
 	// Create a copy of the middleware slice to avoid holding the lock during execution
 	middlewareCopy := make([]Middleware, len(c.middleware))
 	copy(middlewareCopy, c.middleware)
+	recorder := c.timing
 	c.mu.RUnlock()
 
+	// Attach streaming observers in the same reverse order as response middleware
+	var observers []StreamingResponseMiddleware
+	for i := len(middlewareCopy) - 1; i >= 0; i-- {
+		if streamMw, ok := middlewareCopy[i].(StreamingResponseMiddleware); ok {
+			observers = append(observers, streamMw)
+		}
+	}
+	resp = WrapResponseBody(ctx, req, resp, observers...)
+
 	var err error
 	// Execute in reverse order
 	for i := len(middlewareCopy) - 1; i >= 0; i-- {
 		mw := middlewareCopy[i]
 		// Check if this middleware implements ResponseMiddleware
 		if respMw, ok := mw.(ResponseMiddleware); ok {
-			ctx, resp, err = respMw.ProcessResponse(ctx, req, resp)
+			if recorder != nil {
+				recorder.timed(TimingsFromContext(ctx), MiddlewareName(mw), PhaseResponse, func() {
+					ctx, resp, err = respMw.ProcessResponse(ctx, req, resp)
+				})
+			} else {
+				ctx, resp, err = respMw.ProcessResponse(ctx, req, resp)
+			}
 			if err != nil {
 				return ctx, resp, err
 			}
@@ -199,6 +244,29 @@ func (c *DefaultMiddlewareChain) ProcessResponse(ctx context.Context, req *http.
 	return ctx, resp, nil
 }
 
+// Clone returns an independent copy of the chain. The clone has its own middleware
+// slice, so adding or removing middleware on either chain does not affect the other,
+// while the middleware instances themselves are shared. If timing is enabled, the clone
+// starts with timing enabled and empty statistics.
+//
+// This allows per-request variations on a shared base chain:
+//
+//	chain := baseChain.Clone()
+//	chain.Add(debugLogging)
+func (c *DefaultMiddlewareChain) Clone() *DefaultMiddlewareChain {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := &DefaultMiddlewareChain{
+		middleware: make([]Middleware, len(c.middleware)),
+	}
+	copy(clone.middleware, c.middleware)
+	if c.timing != nil {
+		clone.timing = newTimingRecorder()
+	}
+	return clone
+}
+
 // Clear removes all middleware from the chain
 func (c *DefaultMiddlewareChain) Clear() {
 	c.mu.Lock()
@@ -215,6 +283,40 @@ func (c *DefaultMiddlewareChain) Len() int {
 	return len(c.middleware)
 }
 
+// Describe returns the names of the chain's middleware in order, see MiddlewareName
+func (c *DefaultMiddlewareChain) Describe() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, len(c.middleware))
+	for i, mw := range c.middleware {
+		names[i] = MiddlewareName(mw)
+	}
+	return names
+}
+
+// String shows the chain's execution order: request middleware in order, then the
+// provider, then response middleware in reverse, e.g. "auth -> logging -> provider ->
+// logging"
+func (c *DefaultMiddlewareChain) String() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var steps []string
+	for _, mw := range c.middleware {
+		if _, ok := mw.(RequestMiddleware); ok {
+			steps = append(steps, MiddlewareName(mw))
+		}
+	}
+	steps = append(steps, "provider")
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		if _, ok := c.middleware[i].(ResponseMiddleware); ok {
+			steps = append(steps, MiddlewareName(c.middleware[i]))
+		}
+	}
+	return strings.Join(steps, " -> ")
+}
+
 // RequestMiddlewareFunc is a function adapter for RequestMiddleware
 type RequestMiddlewareFunc func(ctx context.Context, req *http.Request) (context.Context, *http.Request, error)
 
@@ -200,6 +200,49 @@ func TestMiddlewareChain_Clear(t *testing.T) {
 	assert.Equal(t, 0, chain.Len())
 }
 
+func TestMiddlewareChain_Clone(t *testing.T) {
+	mw1 := &mockRequestMiddleware{}
+	mw2 := &mockResponseMiddleware{}
+	base := NewMiddlewareChain()
+	base.Add(mw1).Add(mw2)
+
+	clone := base.Clone()
+	assert.Equal(t, 2, clone.Len())
+
+	// Changes to the clone don't affect the original
+	debug := &mockBothMiddleware{}
+	clone.Add(debug)
+	assert.True(t, clone.Remove(mw1))
+	assert.Equal(t, 2, clone.Len())
+	assert.Equal(t, 2, base.Len())
+	assert.False(t, base.Remove(debug))
+
+	// Changes to the original don't affect the clone
+	base.Clear()
+	assert.Equal(t, 0, base.Len())
+	assert.Equal(t, 2, clone.Len())
+
+	// Middleware instances are shared, not copied
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	_, _, err := clone.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, debug.requestCalled)
+}
+
+func TestMiddlewareChain_CloneKeepsTimingEnabled(t *testing.T) {
+	base := NewMiddlewareChain().EnableTiming(true)
+	base.Add(&mockRequestMiddleware{})
+
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	_, _, err := base.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	clone := base.Clone()
+	assert.NotNil(t, clone.TimingStats())
+	assert.Empty(t, clone.TimingStats())
+	assert.Len(t, base.TimingStats(), 1)
+}
+
 // verifyMiddlewareOrder is a helper that verifies middleware execution order
 func verifyMiddlewareOrder(t *testing.T, setupChain func(chain *DefaultMiddlewareChain, mw1, mw2, mw3 *orderTrackingMiddleware), expectedOrder []string) {
 	t.Helper()
@@ -283,6 +326,23 @@ func TestMiddlewareChain_AddAfterNotFound(t *testing.T) {
 	assert.Equal(t, 1, chain.Len())
 }
 
+func TestMiddlewareChain_Describe(t *testing.T) {
+	chain := NewMiddlewareChain()
+	assert.Equal(t, "provider", chain.String())
+
+	chain.Add(&mockRequestMiddleware{})
+	chain.Add(&mockResponseMiddleware{})
+	chain.Add(&slowMiddleware{name: "logging"})
+
+	assert.Equal(t, []string{
+		"*middleware.mockRequestMiddleware",
+		"*middleware.mockResponseMiddleware",
+		"logging",
+	}, chain.Describe())
+	assert.Equal(t, "*middleware.mockRequestMiddleware -> logging -> provider -> "+
+		"logging -> *middleware.mockResponseMiddleware", chain.String())
+}
+
 func TestMiddlewareChain_ProcessRequest(t *testing.T) {
 	chain := NewMiddlewareChain()
 	reqMw := &mockRequestMiddleware{}
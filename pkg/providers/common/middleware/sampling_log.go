@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"hash/fnv"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxLoggedBodyBytes bounds how much of a body is read and buffered for logging
+const maxLoggedBodyBytes = 64 << 10
+
+// ContextKeySampled stores whether the request was selected for logging by a
+// SamplingLogMiddleware
+const ContextKeySampled ContextKey = "middleware:sampled"
+
+// LogMasker masks credentials before request and response data is logged.
+// errors.CredentialMasker satisfies it, e.g. errors.DefaultCredentialMasker().
+type LogMasker interface {
+	// MaskString masks sensitive information in a string
+	MaskString(s string) string
+
+	// MaskHeaders masks sensitive information in HTTP headers
+	MaskHeaders(headers http.Header) map[string][]string
+}
+
+// SamplingLogMiddleware logs full request and response bodies for a sampled fraction
+// of traffic, with credentials masked.
+//
+// Sampling is deterministic per request ID, so a request is either fully logged or not
+// at all, including across retries that reuse its context. The request ID is taken from
+// the context (ContextKeyRequestID), the X-Request-ID header or the idempotency key,
+// and generated if none is set.
+//
+// Streaming (SSE) response bodies are only logged when enabled with
+// WithStreamingBodies, since the stream has to be buffered until it completes. At most
+// 64 KiB of each body is read for logging; the rest is passed through unread.
+type SamplingLogMiddleware struct {
+	rate            float64
+	logger          *log.Logger
+	masker          LogMasker
+	streamingBodies bool
+}
+
+// NewSamplingLogMiddleware creates a middleware logging the given fraction (0 to 1) of
+// requests. A nil logger uses the standard logger. The masker is applied to URLs,
+// headers and bodies; with a nil masker only the request line and status are logged.
+func NewSamplingLogMiddleware(rate float64, logger *log.Logger, masker LogMasker) *SamplingLogMiddleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &SamplingLogMiddleware{
+		rate:   rate,
+		logger: logger,
+		masker: masker,
+	}
+}
+
+// WithStreamingBodies enables buffering and logging streaming response bodies
+func (m *SamplingLogMiddleware) WithStreamingBodies(enabled bool) *SamplingLogMiddleware {
+	m.streamingBodies = enabled
+	return m
+}
+
+// ShouldSample reports whether the request with the given ID is logged
+func (m *SamplingLogMiddleware) ShouldSample(requestID string) bool {
+	if m.rate <= 0 {
+		return false
+	}
+	if m.rate >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(requestID))
+	return float64(h.Sum64()) < m.rate*math.MaxUint64
+}
+
+// ProcessRequest implements RequestMiddleware
+func (m *SamplingLogMiddleware) ProcessRequest(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+	requestID := requestIDFor(ctx, req)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	ctx = context.WithValue(ctx, ContextKeyRequestID, requestID)
+
+	sampled := m.ShouldSample(requestID)
+	ctx = context.WithValue(ctx, ContextKeySampled, sampled)
+	if !sampled {
+		return ctx, req, nil
+	}
+
+	if m.masker == nil {
+		m.logger.Printf("[SamplingLog] request %s: %s %s", requestID, req.Method, req.URL.Redacted())
+		return ctx, req, nil
+	}
+
+	body, err := readAndRestoreRequestBody(req)
+	if err != nil {
+		return ctx, req, err
+	}
+	m.logger.Printf("[SamplingLog] request %s: %s %s headers=%v body=%s",
+		requestID, req.Method, m.masker.MaskString(req.URL.String()), m.masker.MaskHeaders(req.Header), m.masker.MaskString(string(body)))
+	return ctx, req, nil
+}
+
+// ProcessResponse implements ResponseMiddleware
+func (m *SamplingLogMiddleware) ProcessResponse(ctx context.Context, req *http.Request, resp *http.Response) (context.Context, *http.Response, error) {
+	sampled, ok := ctx.Value(ContextKeySampled).(bool)
+	if !ok {
+		sampled = m.ShouldSample(requestIDFor(ctx, req))
+	}
+	if !sampled || resp == nil {
+		return ctx, resp, nil
+	}
+
+	requestID := requestIDFor(ctx, req)
+	if m.masker == nil {
+		m.logger.Printf("[SamplingLog] response %s: status=%d", requestID, resp.StatusCode)
+		return ctx, resp, nil
+	}
+
+	headers := m.masker.MaskHeaders(resp.Header)
+	if IsStreamingResponse(resp) {
+		if !m.streamingBodies {
+			m.logger.Printf("[SamplingLog] response %s: status=%d headers=%v body=<streaming, not logged>", requestID, resp.StatusCode, headers)
+			return ctx, resp, nil
+		}
+		var buf bytes.Buffer
+		resp = WrapResponseBody(ctx, req, resp, &StreamingResponseMiddlewareFuncs{
+			OnChunk: func(_ context.Context, _ *http.Request, chunk []byte) {
+				if room := maxLoggedBodyBytes - buf.Len(); room > 0 {
+					buf.Write(chunk[:min(len(chunk), room)])
+				}
+			},
+			OnComplete: func(_ context.Context, _ *http.Request, bytesRead int64, err error) {
+				m.logger.Printf("[SamplingLog] response %s: status=%d headers=%v bytes=%d err=%v body=%s",
+					requestID, resp.StatusCode, headers, bytesRead, err, m.masker.MaskString(buf.String()))
+			},
+		})
+		return ctx, resp, nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		var err error
+		if body, resp.Body, _, err = peekBody(resp.Body); err != nil {
+			return ctx, resp, err
+		}
+	}
+	m.logger.Printf("[SamplingLog] response %s: status=%d headers=%v body=%s",
+		requestID, resp.StatusCode, headers, m.masker.MaskString(string(body)))
+	return ctx, resp, nil
+}
+
+// requestIDFor returns the ID identifying a request for sampling
+func requestIDFor(ctx context.Context, req *http.Request) string {
+	if id, ok := ctx.Value(ContextKeyRequestID).(string); ok && id != "" {
+		return id
+	}
+	if id := strings.TrimSpace(req.Header.Get("X-Request-ID")); id != "" {
+		return id
+	}
+	if key, ok := IdempotencyKeyFromContext(ctx); ok {
+		return key
+	}
+	return req.Header.Get(IdempotencyKeyHeader)
+}
+
+// readAndRestoreRequestBody reads up to maxLoggedBodyBytes of the request body and
+// replaces the body with one still sending all of it. GetBody is replaced with a
+// rewindable copy when the whole body was read.
+func readAndRestoreRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	prefix, body, complete, err := peekBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = body
+	if complete {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(prefix)), nil
+		}
+	}
+	return prefix, nil
+}
+
+// peekBody reads up to maxLoggedBodyBytes of body and returns them with a body yielding
+// the whole content, unread bytes included. complete reports whether body was read to
+// its end, in which case it is closed. On error body is closed.
+func peekBody(body io.ReadCloser) (prefix []byte, restored io.ReadCloser, complete bool, err error) {
+	data, err := io.ReadAll(io.LimitReader(body, maxLoggedBodyBytes+1))
+	if err != nil {
+		_ = body.Close()
+		return nil, io.NopCloser(bytes.NewReader(data)), false, err
+	}
+	if len(data) <= maxLoggedBodyBytes {
+		_ = body.Close()
+		return data, io.NopCloser(bytes.NewReader(data)), true, nil
+	}
+	restored = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), body), body}
+	return data[:maxLoggedBodyBytes], restored, false, nil
+}
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMasker masks a fixed secret and the Authorization header
+type testMasker struct{}
+
+func (testMasker) MaskString(s string) string {
+	return strings.ReplaceAll(s, "sk-secret", "***MASKED***")
+}
+
+func (testMasker) MaskHeaders(headers http.Header) map[string][]string {
+	masked := make(map[string][]string, len(headers))
+	for key, values := range headers {
+		if strings.EqualFold(key, "Authorization") {
+			masked[key] = []string{"***MASKED***"}
+			continue
+		}
+		masked[key] = values
+	}
+	return masked
+}
+
+func newSamplingLogTest(rate float64) (*SamplingLogMiddleware, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return NewSamplingLogMiddleware(rate, log.New(&buf, "", 0), testMasker{}), &buf
+}
+
+func TestSamplingLogMiddleware_LogsMaskedRequestAndResponse(t *testing.T) {
+	mw, buf := newSamplingLogTest(1)
+
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/chat/completions", strings.NewReader(`{"api":"sk-secret","model":"m"}`))
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	ctx, req, err := mw.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"api":"sk-secret","model":"m"}`, string(body), "request body must be restored")
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"resp-1"}`)),
+	}
+	_, resp, err = mw.ProcessResponse(ctx, req, resp)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"resp-1"}`, string(respBody), "response body must be restored")
+
+	output := buf.String()
+	assert.NotContains(t, output, "sk-secret")
+	assert.Contains(t, output, `"model":"m"`)
+	assert.Contains(t, output, `{"id":"resp-1"}`)
+	assert.Contains(t, output, "status=200")
+}
+
+func TestSamplingLogMiddleware_BoundsLoggedBodies(t *testing.T) {
+	mw, buf := newSamplingLogTest(1)
+
+	// An unknown length must not make the whole body be read
+	large := strings.Repeat("a", 2*maxLoggedBodyBytes)
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/chat/completions", strings.NewReader(large))
+	req.ContentLength = -1
+	ctx, req, err := mw.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body), "the whole request body must still be sent")
+
+	resp := &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(large))}
+	_, resp, err = mw.ProcessResponse(ctx, req, resp)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(respBody), "the whole response body must still be read")
+
+	assert.NotContains(t, buf.String(), strings.Repeat("a", maxLoggedBodyBytes+1))
+	assert.Contains(t, buf.String(), strings.Repeat("a", maxLoggedBodyBytes))
+}
+
+func TestSamplingLogMiddleware_DeterministicPerRequestID(t *testing.T) {
+	mw, buf := newSamplingLogTest(0.5)
+
+	sampled := 0
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("req-%d", i)
+		first := mw.ShouldSample(id)
+		assert.Equal(t, first, mw.ShouldSample(id), "sampling must be stable for %s", id)
+		if first {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 100, sampled, 30)
+
+	// A request is either logged on both sides or not at all
+	for i := 0; i < 20; i++ {
+		buf.Reset()
+		req := httptest.NewRequest("POST", "https://api.example.com/v1/messages", strings.NewReader("{}"))
+		req.Header.Set("X-Request-ID", fmt.Sprintf("req-%d", i))
+		ctx, req, err := mw.ProcessRequest(context.Background(), req)
+		require.NoError(t, err)
+		_, _, err = mw.ProcessResponse(ctx, req, &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("{}"))})
+		require.NoError(t, err)
+
+		lines := strings.Count(buf.String(), "[SamplingLog]")
+		if mw.ShouldSample(req.Header.Get("X-Request-ID")) {
+			assert.Equal(t, 2, lines)
+		} else {
+			assert.Equal(t, 0, lines)
+		}
+	}
+}
+
+func TestSamplingLogMiddleware_ZeroRateLogsNothing(t *testing.T) {
+	mw, buf := newSamplingLogTest(0)
+
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/messages", strings.NewReader("{}"))
+	ctx, req, err := mw.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+	_, _, err = mw.ProcessResponse(ctx, req, &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("{}"))})
+	require.NoError(t, err)
+
+	assert.Empty(t, buf.String())
+	id, ok := ctx.Value(ContextKeyRequestID).(string)
+	assert.True(t, ok && id != "", "a request ID is assigned")
+}
+
+func TestSamplingLogMiddleware_StreamingBodies(t *testing.T) {
+	sse := "data: {\"token\":\"sk-secret\"}\n\ndata: [DONE]\n\n"
+	newStreamResponse := func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(strings.NewReader(sse)),
+		}
+	}
+
+	t.Run("not buffered by default", func(t *testing.T) {
+		mw, buf := newSamplingLogTest(1)
+		req := httptest.NewRequest("POST", "https://api.example.com/v1/messages", nil)
+		ctx, req, err := mw.ProcessRequest(context.Background(), req)
+		require.NoError(t, err)
+
+		_, resp, err := mw.ProcessResponse(ctx, req, newStreamResponse())
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, sse, string(body))
+		assert.Contains(t, buf.String(), "<streaming, not logged>")
+	})
+
+	t.Run("buffered when enabled", func(t *testing.T) {
+		mw, buf := newSamplingLogTest(1)
+		mw.WithStreamingBodies(true)
+		req := httptest.NewRequest("POST", "https://api.example.com/v1/messages", nil)
+		ctx, req, err := mw.ProcessRequest(context.Background(), req)
+		require.NoError(t, err)
+
+		_, resp, err := mw.ProcessResponse(ctx, req, newStreamResponse())
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "response", "streamed body is logged once complete")
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, sse, string(body))
+		assert.Contains(t, buf.String(), "data: [DONE]")
+		assert.NotContains(t, buf.String(), "sk-secret")
+	})
+}
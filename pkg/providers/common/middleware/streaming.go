@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrBodyClosedEarly is reported to StreamingResponseMiddleware when the consumer
+// closes the response body before reaching EOF (e.g. a cancelled stream)
+var ErrBodyClosedEarly = errors.New("response body closed before EOF")
+
+// StreamingResponseMiddleware observes response bodies chunk-by-chunk as they are read.
+// Unlike ResponseMiddleware, it never reads the body itself, so it is safe to use with
+// streaming (SSE) responses: the provider keeps consuming the body incrementally and
+// each chunk is handed to the middleware as it passes through.
+type StreamingResponseMiddleware interface {
+	// OnResponseChunk is called with each chunk of the response body as it is read.
+	// The chunk slice is only valid for the duration of the call and must not be retained.
+	OnResponseChunk(ctx context.Context, req *http.Request, chunk []byte)
+
+	// OnResponseComplete is called exactly once when the body reaches EOF, fails, or is closed.
+	// err is nil on a clean EOF, ErrBodyClosedEarly if closed before EOF, or the read error.
+	OnResponseComplete(ctx context.Context, req *http.Request, bytesRead int64, err error)
+}
+
+// IsStreamingResponse reports whether the response is a server-sent event stream
+func IsStreamingResponse(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// WrapResponseBody wraps resp.Body so that each observer sees the body as it is read.
+// Observers are notified in the order given. The response is modified in place and returned.
+func WrapResponseBody(ctx context.Context, req *http.Request, resp *http.Response, observers ...StreamingResponseMiddleware) *http.Response {
+	if resp == nil || resp.Body == nil || len(observers) == 0 {
+		return resp
+	}
+
+	resp.Body = &observedBody{
+		ctx:       ctx,
+		req:       req,
+		body:      resp.Body,
+		observers: observers,
+	}
+	return resp
+}
+
+// observedBody is an io.ReadCloser that forwards reads to the underlying body
+// while notifying streaming middleware of each chunk
+type observedBody struct {
+	ctx       context.Context
+	req       *http.Request
+	body      io.ReadCloser
+	observers []StreamingResponseMiddleware
+	bytesRead int64
+	once      sync.Once
+}
+
+// Read implements io.Reader
+func (b *observedBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.bytesRead += int64(n)
+		for _, observer := range b.observers {
+			observer.OnResponseChunk(b.ctx, b.req, p[:n])
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			b.complete(nil)
+		} else {
+			b.complete(err)
+		}
+	}
+
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *observedBody) Close() error {
+	b.complete(ErrBodyClosedEarly)
+	return b.body.Close()
+}
+
+// complete notifies observers that the body is finished, at most once
+func (b *observedBody) complete(err error) {
+	b.once.Do(func() {
+		for _, observer := range b.observers {
+			observer.OnResponseComplete(b.ctx, b.req, b.bytesRead, err)
+		}
+	})
+}
+
+// StreamingResponseMiddlewareFuncs adapts a pair of functions to StreamingResponseMiddleware.
+// Either function may be nil.
+type StreamingResponseMiddlewareFuncs struct {
+	OnChunk    func(ctx context.Context, req *http.Request, chunk []byte)
+	OnComplete func(ctx context.Context, req *http.Request, bytesRead int64, err error)
+}
+
+// OnResponseChunk implements StreamingResponseMiddleware
+func (f *StreamingResponseMiddlewareFuncs) OnResponseChunk(ctx context.Context, req *http.Request, chunk []byte) {
+	if f.OnChunk != nil {
+		f.OnChunk(ctx, req, chunk)
+	}
+}
+
+// OnResponseComplete implements StreamingResponseMiddleware
+func (f *StreamingResponseMiddlewareFuncs) OnResponseComplete(ctx context.Context, req *http.Request, bytesRead int64, err error) {
+	if f.OnComplete != nil {
+		f.OnComplete(ctx, req, bytesRead, err)
+	}
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingStreamMiddleware records chunks and completion events
+type recordingStreamMiddleware struct {
+	mu          sync.Mutex
+	name        string
+	order       *[]string
+	chunks      []string
+	completions int
+	bytesRead   int64
+	err         error
+}
+
+func (m *recordingStreamMiddleware) OnResponseChunk(ctx context.Context, req *http.Request, chunk []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chunks = append(m.chunks, string(chunk))
+	if m.order != nil {
+		*m.order = append(*m.order, m.name)
+	}
+}
+
+func (m *recordingStreamMiddleware) OnResponseComplete(ctx context.Context, req *http.Request, bytesRead int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.completions++
+	m.bytesRead = bytesRead
+	m.err = err
+}
+
+// chunkedReader returns data in fixed-size pieces to simulate a streamed body
+type chunkedReader struct {
+	data   []string
+	index  int
+	closed bool
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if r.index >= len(r.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.index])
+	r.index++
+	return n, nil
+}
+
+func (r *chunkedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newStreamingResponse(body io.ReadCloser) *http.Response {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       body,
+	}
+	resp.Header.Set("Content-Type", "text/event-stream")
+	return resp
+}
+
+func TestStreamingResponseMiddleware_ObservesChunksWithoutConsuming(t *testing.T) {
+	chain := NewMiddlewareChain()
+	observer := &recordingStreamMiddleware{}
+	chain.Add(observer)
+
+	body := &chunkedReader{data: []string{"data: {\"a\":1}\n\n", "data: {\"b\":2}\n\n", "data: [DONE]\n\n"}}
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/chat/completions", nil)
+
+	_, resp, err := chain.ProcessResponse(context.Background(), req, newStreamingResponse(body))
+	require.NoError(t, err)
+
+	// Nothing has been read yet - the middleware must not consume the body
+	assert.Empty(t, observer.chunks)
+	assert.Equal(t, 0, observer.completions)
+
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "data: {\"a\":1}\n\ndata: {\"b\":2}\n\ndata: [DONE]\n\n", string(content))
+
+	assert.Equal(t, []string{"data: {\"a\":1}\n\n", "data: {\"b\":2}\n\n", "data: [DONE]\n\n"}, observer.chunks)
+	assert.Equal(t, 1, observer.completions)
+	assert.Equal(t, int64(len(content)), observer.bytesRead)
+	assert.NoError(t, observer.err)
+
+	// Closing after EOF must not report a second completion
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, 1, observer.completions)
+	assert.True(t, body.closed)
+}
+
+func TestStreamingResponseMiddleware_ClosedEarly(t *testing.T) {
+	chain := NewMiddlewareChain()
+	observer := &recordingStreamMiddleware{}
+	chain.Add(observer)
+
+	body := &chunkedReader{data: []string{"data: one\n\n", "data: two\n\n"}}
+	req := httptest.NewRequest("POST", "https://api.example.com", nil)
+
+	_, resp, err := chain.ProcessResponse(context.Background(), req, newStreamingResponse(body))
+	require.NoError(t, err)
+
+	buf := make([]byte, 64)
+	_, err = resp.Body.Read(buf)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 1, observer.completions)
+	assert.ErrorIs(t, observer.err, ErrBodyClosedEarly)
+	assert.Equal(t, int64(len("data: one\n\n")), observer.bytesRead)
+}
+
+func TestStreamingResponseMiddleware_ReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	observer := &recordingStreamMiddleware{}
+	req := httptest.NewRequest("POST", "https://api.example.com", nil)
+
+	resp := WrapResponseBody(context.Background(), req, newStreamingResponse(io.NopCloser(&failingReader{err: readErr})), observer)
+
+	_, err := io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, readErr)
+	assert.Equal(t, 1, observer.completions)
+	assert.ErrorIs(t, observer.err, readErr)
+}
+
+func TestStreamingResponseMiddleware_OrderAndCoexistence(t *testing.T) {
+	var order []string
+	first := &recordingStreamMiddleware{name: "first", order: &order}
+	second := &recordingStreamMiddleware{name: "second", order: &order}
+	regular := &mockResponseMiddleware{}
+
+	chain := NewMiddlewareChain()
+	chain.Add(first).Add(regular).Add(second)
+
+	req := httptest.NewRequest("POST", "https://api.example.com", nil)
+	_, resp, err := chain.ProcessResponse(context.Background(), req, newStreamingResponse(io.NopCloser(strings.NewReader("chunk"))))
+	require.NoError(t, err)
+	assert.True(t, regular.wasCalled())
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// Streaming observers follow the same reverse order as response middleware
+	assert.Equal(t, []string{"second", "first"}, order)
+}
+
+func TestStreamingResponseMiddleware_NoObserversLeavesBodyUntouched(t *testing.T) {
+	chain := NewMiddlewareChain()
+	chain.Add(&mockResponseMiddleware{})
+
+	body := io.NopCloser(strings.NewReader("plain"))
+	req := httptest.NewRequest("GET", "https://api.example.com", nil)
+	resp := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: body}
+
+	_, result, err := chain.ProcessResponse(context.Background(), req, resp)
+	require.NoError(t, err)
+	assert.Equal(t, body, result.Body)
+}
+
+func TestStreamingResponseMiddlewareFuncs(t *testing.T) {
+	var chunks int
+	var completed bool
+	mw := &StreamingResponseMiddlewareFuncs{
+		OnChunk: func(ctx context.Context, req *http.Request, chunk []byte) {
+			chunks++
+		},
+		OnComplete: func(ctx context.Context, req *http.Request, bytesRead int64, err error) {
+			completed = true
+			assert.Equal(t, int64(5), bytesRead)
+		},
+	}
+
+	req := httptest.NewRequest("GET", "https://api.example.com", nil)
+	resp := WrapResponseBody(context.Background(), req, newStreamingResponse(io.NopCloser(strings.NewReader("hello"))), mw)
+	_, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, chunks)
+	assert.True(t, completed)
+
+	// Nil functions are safe
+	empty := &StreamingResponseMiddlewareFuncs{}
+	empty.OnResponseChunk(context.Background(), req, []byte("x"))
+	empty.OnResponseComplete(context.Background(), req, 1, nil)
+}
+
+func TestIsStreamingResponse(t *testing.T) {
+	assert.False(t, IsStreamingResponse(nil))
+
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("Content-Type", "application/json")
+	assert.False(t, IsStreamingResponse(resp))
+
+	resp.Header.Set("Content-Type", "text/event-stream; charset=utf-8")
+	assert.True(t, IsStreamingResponse(resp))
+}
+
+// failingReader always returns the configured error
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
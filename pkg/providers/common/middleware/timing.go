@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ContextKeyMiddlewareTimings stores the *MiddlewareTimings recorded for the current request
+const ContextKeyMiddlewareTimings ContextKey = "middleware:timings"
+
+// MiddlewarePhase identifies which half of the chain a timing was recorded in
+type MiddlewarePhase string
+
+const (
+	// PhaseRequest is the ProcessRequest pass
+	PhaseRequest MiddlewarePhase = "request"
+	// PhaseResponse is the ProcessResponse pass
+	PhaseResponse MiddlewarePhase = "response"
+)
+
+// MiddlewareTiming is the duration of a single middleware invocation
+type MiddlewareTiming struct {
+	Name     string
+	Phase    MiddlewarePhase
+	Duration time.Duration
+}
+
+// MiddlewareTimings collects the timings of one request as it passes through the chain.
+// It is stored in the context returned by ProcessRequest when timing is enabled.
+type MiddlewareTimings struct {
+	mu      sync.Mutex
+	entries []MiddlewareTiming
+}
+
+func (t *MiddlewareTimings) add(timing MiddlewareTiming) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, timing)
+}
+
+// Entries returns the recorded timings in execution order
+func (t *MiddlewareTimings) Entries() []MiddlewareTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entries := make([]MiddlewareTiming, len(t.entries))
+	copy(entries, t.entries)
+	return entries
+}
+
+// Total returns the combined duration of all recorded invocations
+func (t *MiddlewareTimings) Total() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total time.Duration
+	for _, entry := range t.entries {
+		total += entry.Duration
+	}
+	return total
+}
+
+// TimingsFromContext returns the per-request middleware timings, or nil if timing
+// was not enabled on the chain that processed the request
+func TimingsFromContext(ctx context.Context) *MiddlewareTimings {
+	timings, _ := ctx.Value(ContextKeyMiddlewareTimings).(*MiddlewareTimings)
+	return timings
+}
+
+// MiddlewareStats aggregates timings for one middleware across all requests
+type MiddlewareStats struct {
+	Name string
+
+	RequestCount int64
+	RequestTotal time.Duration
+	RequestMax   time.Duration
+
+	ResponseCount int64
+	ResponseTotal time.Duration
+	ResponseMax   time.Duration
+}
+
+// AverageRequest returns the mean ProcessRequest duration
+func (s MiddlewareStats) AverageRequest() time.Duration {
+	if s.RequestCount == 0 {
+		return 0
+	}
+	return s.RequestTotal / time.Duration(s.RequestCount)
+}
+
+// AverageResponse returns the mean ProcessResponse duration
+func (s MiddlewareStats) AverageResponse() time.Duration {
+	if s.ResponseCount == 0 {
+		return 0
+	}
+	return s.ResponseTotal / time.Duration(s.ResponseCount)
+}
+
+func (s *MiddlewareStats) record(phase MiddlewarePhase, d time.Duration) {
+	switch phase {
+	case PhaseRequest:
+		s.RequestCount++
+		s.RequestTotal += d
+		if d > s.RequestMax {
+			s.RequestMax = d
+		}
+	case PhaseResponse:
+		s.ResponseCount++
+		s.ResponseTotal += d
+		if d > s.ResponseMax {
+			s.ResponseMax = d
+		}
+	}
+}
+
+// MiddlewareName returns the display name of a middleware: the result of its
+// Name() method if it has one, otherwise its Go type
+func MiddlewareName(mw Middleware) string {
+	if named, ok := mw.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", mw)
+}
+
+// timingRecorder holds the aggregate statistics of a chain with timing enabled
+type timingRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*MiddlewareStats
+}
+
+func newTimingRecorder() *timingRecorder {
+	return &timingRecorder{stats: make(map[string]*MiddlewareStats)}
+}
+
+func (r *timingRecorder) record(timing MiddlewareTiming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[timing.Name]
+	if !ok {
+		stats = &MiddlewareStats{Name: timing.Name}
+		r.stats[timing.Name] = stats
+	}
+	stats.record(timing.Phase, timing.Duration)
+}
+
+// timed runs fn and records its duration against the named middleware, both in the
+// chain's aggregate statistics and in the per-request timings
+func (r *timingRecorder) timed(timings *MiddlewareTimings, name string, phase MiddlewarePhase, fn func()) {
+	start := time.Now()
+	fn()
+	timing := MiddlewareTiming{Name: name, Phase: phase, Duration: time.Since(start)}
+
+	r.record(timing)
+	if timings != nil {
+		timings.add(timing)
+	}
+}
+
+// EnableTiming turns per-middleware timing on or off. While enabled, the chain records
+// how long each middleware spends in ProcessRequest and ProcessResponse; aggregates are
+// available from TimingStats and per-request timings from TimingsFromContext on the
+// context returned by ProcessRequest. Timing is off by default.
+func (c *DefaultMiddlewareChain) EnableTiming(enabled bool) *DefaultMiddlewareChain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if enabled && c.timing == nil {
+		c.timing = newTimingRecorder()
+	} else if !enabled {
+		c.timing = nil
+	}
+	return c
+}
+
+// TimingStats returns aggregate timing statistics per middleware, in chain order.
+// Middleware sharing a name are aggregated together. Returns nil if timing is disabled.
+func (c *DefaultMiddlewareChain) TimingStats() []MiddlewareStats {
+	c.mu.RLock()
+	recorder := c.timing
+	names := make([]string, 0, len(c.middleware))
+	for _, mw := range c.middleware {
+		names = append(names, MiddlewareName(mw))
+	}
+	c.mu.RUnlock()
+
+	if recorder == nil {
+		return nil
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	seen := make(map[string]bool, len(names))
+	result := make([]MiddlewareStats, 0, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if stats, ok := recorder.stats[name]; ok {
+			result = append(result, *stats)
+		}
+	}
+	return result
+}
+
+// ResetTimingStats clears the aggregate timing statistics
+func (c *DefaultMiddlewareChain) ResetTimingStats() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.timing != nil {
+		c.timing = newTimingRecorder()
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowMiddleware sleeps in both phases and reports a fixed name
+type slowMiddleware struct {
+	name  string
+	delay time.Duration
+}
+
+func (m *slowMiddleware) Name() string { return m.name }
+
+func (m *slowMiddleware) ProcessRequest(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+	time.Sleep(m.delay)
+	return ctx, req, nil
+}
+
+func (m *slowMiddleware) ProcessResponse(ctx context.Context, req *http.Request, resp *http.Response) (context.Context, *http.Response, error) {
+	time.Sleep(m.delay)
+	return ctx, resp, nil
+}
+
+func TestMiddlewareChain_TimingDisabledByDefault(t *testing.T) {
+	chain := NewMiddlewareChain()
+	chain.Add(&slowMiddleware{name: "slow"})
+
+	req := httptest.NewRequest("POST", "/v1/chat/completions", nil)
+	ctx, _, err := chain.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Nil(t, TimingsFromContext(ctx))
+	assert.Nil(t, chain.TimingStats())
+}
+
+func TestMiddlewareChain_Timing(t *testing.T) {
+	chain := NewMiddlewareChain().EnableTiming(true)
+	chain.Add(&slowMiddleware{name: "fast"})
+	chain.Add(&slowMiddleware{name: "slow", delay: 5 * time.Millisecond})
+	chain.Add(RequestMiddlewareFunc(func(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+		return ctx, req, nil
+	}))
+
+	req := httptest.NewRequest("POST", "/v1/chat/completions", nil)
+	ctx, req, err := chain.ProcessRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}
+	ctx, _, err = chain.ProcessResponse(ctx, req, resp)
+	require.NoError(t, err)
+
+	timings := TimingsFromContext(ctx)
+	require.NotNil(t, timings)
+	entries := timings.Entries()
+	require.Len(t, entries, 5)
+
+	// Request phase runs in order, response phase in reverse
+	assert.Equal(t, PhaseRequest, entries[0].Phase)
+	assert.Equal(t, "fast", entries[0].Name)
+	assert.Equal(t, "slow", entries[1].Name)
+	assert.Equal(t, "middleware.RequestMiddlewareFunc", entries[2].Name)
+	assert.Equal(t, PhaseResponse, entries[3].Phase)
+	assert.Equal(t, "slow", entries[3].Name)
+	assert.Equal(t, "fast", entries[4].Name)
+
+	assert.GreaterOrEqual(t, entries[1].Duration, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, timings.Total(), 10*time.Millisecond)
+
+	stats := chain.TimingStats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, "slow", stats[1].Name)
+	assert.Equal(t, int64(1), stats[1].RequestCount)
+	assert.Equal(t, int64(1), stats[1].ResponseCount)
+	assert.GreaterOrEqual(t, stats[1].AverageRequest(), 5*time.Millisecond)
+	assert.GreaterOrEqual(t, stats[1].ResponseMax, 5*time.Millisecond)
+	assert.Zero(t, stats[2].ResponseCount)
+
+	chain.ResetTimingStats()
+	assert.Empty(t, chain.TimingStats())
+
+	chain.EnableTiming(false)
+	assert.Nil(t, chain.TimingStats())
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/google/uuid"
+)
+
+// Transport is an http.RoundTripper that runs a middleware chain around every request
+// it sends, so the chain applies to a provider's HTTP client without the provider
+// calling it. Install it through types.ProviderConfig.WrapTransport.
+type Transport struct {
+	base  http.RoundTripper
+	chain MiddlewareChain
+}
+
+// NewTransport creates a transport that runs chain around base. A nil base uses
+// http.DefaultTransport and a nil chain runs no middleware.
+func NewTransport(base http.RoundTripper, chain MiddlewareChain) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, chain: chain}
+}
+
+// RoundTrip implements http.RoundTripper. Request middleware runs in order before the
+// request is sent and response middleware in reverse order after, with the context
+// returned by the request middleware.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.chain == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, req, err := t.chain.ProcessRequest(req.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, resp, err = t.chain.ProcessResponse(ctx, req, resp)
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// InstrumentedTransportConfig configures NewInstrumentedTransport
+type InstrumentedTransportConfig struct {
+	// Provider is stored under ContextKeyProvider for every request
+	Provider string
+
+	// Chain runs around every request once the context keys are set; may be nil
+	Chain MiddlewareChain
+
+	// Masker masks credentials in the errors the transport returns. A nil masker
+	// returns errors unchanged.
+	Masker LogMasker
+
+	// Metrics records every round trip; transports may share one. Nil creates one,
+	// available from the transport's Metrics method.
+	Metrics *HTTPMetrics
+}
+
+// InstrumentedTransport is the transport providers use by default. Before running its
+// chain it sets the standard context keys: ContextKeyRequestID (the X-Request-ID header
+// or a new UUID), ContextKeyProvider, ContextKeyModel (set by the provider with WithModel;
+// the body is never read) and ContextKeyStartTime. It records every round trip in its
+// HTTPMetrics and masks credentials in the errors it returns.
+type InstrumentedTransport struct {
+	transport *Transport
+	provider  string
+	masker    LogMasker
+	metrics   *HTTPMetrics
+}
+
+// NewInstrumentedTransport creates an instrumented transport around base; a nil base
+// uses http.DefaultTransport
+func NewInstrumentedTransport(base http.RoundTripper, config InstrumentedTransportConfig) *InstrumentedTransport {
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = &HTTPMetrics{}
+	}
+	return &InstrumentedTransport{
+		transport: NewTransport(base, config.Chain),
+		provider:  config.Provider,
+		masker:    config.Masker,
+		metrics:   metrics,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	req = req.WithContext(t.instrument(req))
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		err = t.maskError(err)
+	}
+	t.metrics.record(time.Since(start), resp, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Metrics returns the metrics the transport records into
+func (t *InstrumentedTransport) Metrics() *HTTPMetrics {
+	return t.metrics
+}
+
+// instrument returns the request's context with the standard keys set; keys already
+// present in the context are kept
+func (t *InstrumentedTransport) instrument(req *http.Request) context.Context {
+	ctx := req.Context()
+	if id, _ := ctx.Value(ContextKeyRequestID).(string); id == "" {
+		id = req.Header.Get("X-Request-ID")
+		if id == "" {
+			id = uuid.New().String()
+		}
+		ctx = context.WithValue(ctx, ContextKeyRequestID, id)
+	}
+	if t.provider != "" && ctx.Value(ContextKeyProvider) == nil {
+		ctx = context.WithValue(ctx, ContextKeyProvider, t.provider)
+	}
+	if ctx.Value(ContextKeyStartTime) == nil {
+		ctx = context.WithValue(ctx, ContextKeyStartTime, time.Now())
+	}
+	return ctx
+}
+
+// maskError masks credentials in err's message. A *url.Error keeps its type, with its
+// URL masked; other errors are wrapped, so errors.Is and errors.As still see them.
+func (t *InstrumentedTransport) maskError(err error) error {
+	if t.masker == nil {
+		return err
+	}
+	if urlErr, ok := err.(*url.Error); ok {
+		masked := *urlErr
+		masked.URL = t.masker.MaskString(urlErr.URL)
+		masked.Err = t.maskError(urlErr.Err)
+		return &masked
+	}
+	if err == nil {
+		return nil
+	}
+	message := t.masker.MaskString(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return &maskedError{message: message, err: err}
+}
+
+// HTTPMetrics records HTTP round trips as ProviderMetrics. They count round trips,
+// including retries and model listing, so they are kept apart from a provider's own
+// metrics, which count calls to the provider. Responses below 400 succeed; other
+// responses and transport errors fail.
+type HTTPMetrics struct {
+	mu      sync.RWMutex
+	metrics types.ProviderMetrics
+}
+
+// Snapshot returns a copy of the metrics
+func (m *HTTPMetrics) Snapshot() types.ProviderMetrics {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.metrics
+}
+
+// record adds one round trip to the metrics
+func (m *HTTPMetrics) record(latency time.Duration, resp *http.Response, err error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.metrics.RequestCount++
+	m.metrics.LastRequestTime = now
+	m.metrics.TotalLatency += latency
+	m.metrics.AverageLatency = m.metrics.TotalLatency / time.Duration(m.metrics.RequestCount)
+
+	switch {
+	case err != nil:
+		m.metrics.ErrorCount++
+		m.metrics.LastErrorTime = now
+		m.metrics.LastError = err.Error()
+	case resp.StatusCode >= http.StatusBadRequest:
+		m.metrics.ErrorCount++
+		m.metrics.LastErrorTime = now
+		m.metrics.LastError = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	default:
+		m.metrics.SuccessCount++
+		m.metrics.LastSuccessTime = now
+	}
+}
+
+// maskedError is an error whose message has had credentials masked
+type maskedError struct {
+	message string
+	err     error
+}
+
+func (e *maskedError) Error() string { return e.message }
+func (e *maskedError) Unwrap() error { return e.err }
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestTransport_RunsChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen", r.Header.Get("X-Added"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var order []string
+	chain := NewMiddlewareChain()
+	chain.Add(NewCombinedMiddleware(
+		RequestMiddlewareFunc(func(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+			order = append(order, "request")
+			req.Header.Set("X-Added", "yes")
+			return context.WithValue(ctx, ContextKeyMetadata, "from request"), req, nil
+		}),
+		ResponseMiddlewareFunc(func(ctx context.Context, req *http.Request, resp *http.Response) (context.Context, *http.Response, error) {
+			order = append(order, "response")
+			assert.Equal(t, "from request", ctx.Value(ContextKeyMetadata))
+			return ctx, resp, nil
+		}),
+	))
+
+	client := &http.Client{Transport: NewTransport(nil, chain)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "yes", resp.Header.Get("X-Seen"))
+	assert.Equal(t, []string{"request", "response"}, order)
+}
+
+func TestTransport_RequestMiddlewareErrorAborts(t *testing.T) {
+	sent := false
+	base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		sent = true
+		return nil, errors.New("unexpected")
+	})
+	abort := errors.New("rejected")
+	chain := NewMiddlewareChain()
+	chain.Add(RequestMiddlewareFunc(func(ctx context.Context, req *http.Request) (context.Context, *http.Request, error) {
+		return ctx, req, abort
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	_, err := NewTransport(base, chain).RoundTrip(req)
+	assert.ErrorIs(t, err, abort)
+	assert.False(t, sent)
+}
+
+func TestInstrumentedTransport_SetsContextKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var ctx context.Context
+	chain := NewMiddlewareChain()
+	chain.Add(RequestMiddlewareFunc(func(c context.Context, req *http.Request) (context.Context, *http.Request, error) {
+		ctx = c
+		return c, req, nil
+	}))
+	client := &http.Client{Transport: NewInstrumentedTransport(nil, InstrumentedTransportConfig{Provider: "openai", Chain: chain})}
+
+	req, err := http.NewRequestWithContext(WithModel(context.Background(), "gpt-4o"), http.MethodPost, server.URL,
+		strings.NewReader(`{"model":"ignored","messages":[]}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-123")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.NotNil(t, ctx)
+	assert.Equal(t, "req-123", ctx.Value(ContextKeyRequestID))
+	assert.Equal(t, "openai", ctx.Value(ContextKeyProvider))
+	assert.Equal(t, "gpt-4o", ctx.Value(ContextKeyModel))
+	assert.NotNil(t, ctx.Value(ContextKeyStartTime))
+
+	// Without a header or a model in the context, a request ID is generated and no
+	// model is set, even if the body names one
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{"model":"gpt-4o"}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.NotEmpty(t, ctx.Value(ContextKeyRequestID))
+	assert.NotEqual(t, "req-123", ctx.Value(ContextKeyRequestID))
+	assert.Nil(t, ctx.Value(ContextKeyModel))
+}
+
+func TestInstrumentedTransport_RecordsMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := &HTTPMetrics{}
+	transport := NewInstrumentedTransport(nil, InstrumentedTransportConfig{Metrics: metrics})
+	client := &http.Client{Transport: transport}
+	for _, path := range []string{"/ok", "/ok", "/fail"} {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Same(t, metrics, transport.Metrics())
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, int64(3), snapshot.RequestCount)
+	assert.Equal(t, int64(2), snapshot.SuccessCount)
+	assert.Equal(t, int64(1), snapshot.ErrorCount)
+	assert.Equal(t, "HTTP 429", snapshot.LastError)
+	assert.False(t, snapshot.LastSuccessTime.IsZero())
+	assert.False(t, snapshot.LastErrorTime.IsZero())
+}
+
+func TestInstrumentedTransport_MasksErrors(t *testing.T) {
+	cause := errors.New("dial failed for sk-secret")
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, &url.Error{Op: "Post", URL: req.URL.String(), Err: cause}
+	})
+	transport := NewInstrumentedTransport(base, InstrumentedTransportConfig{Masker: testMasker{}})
+
+	req := httptest.NewRequest(http.MethodPost, "https://api.example.com/v1?key=sk-secret", nil)
+	_, err := transport.RoundTrip(req)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "sk-secret")
+	assert.ErrorIs(t, err, cause)
+
+	var urlErr *url.Error
+	require.ErrorAs(t, err, &urlErr)
+	assert.Equal(t, "https://api.example.com/v1?key=***MASKED***", urlErr.URL)
+	assert.NotContains(t, transport.Metrics().Snapshot().LastError, "sk-secret")
+
+	// Errors without credentials are returned as they are
+	plain := errors.New("connection refused")
+	transport = NewInstrumentedTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, plain
+	}), InstrumentedTransportConfig{Masker: testMasker{}})
+	_, err = transport.RoundTrip(req)
+	assert.Same(t, plain, err)
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// GenerateFunc is a provider's GenerateChatCompletion method
+type GenerateFunc func(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error)
+
+// GenerateWithModelFallbacks calls generate with options.Model and then, for as long as
+// the call fails with a model-specific error (see IsModelFallbackError), with each model
+// in options.ModelFallbacks in turn. Other errors are returned immediately.
+//
+// Every chunk of the returned stream carries the model that served the request in its
+// metadata under "served_model", and its position in the list under
+// "model_fallback_index" (0 for options.Model).
+//
+// Providers reach it through GenerateWithStrategies when ModelFallbacks is set.
+func GenerateWithModelFallbacks(ctx context.Context, options types.GenerateOptions, generate GenerateFunc) (types.ChatCompletionStream, error) {
+	models := append([]string{options.Model}, options.ModelFallbacks...)
+
+	var lastErr error
+	for i, model := range models {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Falling back to another model is a retry of the request
+		if i > 0 && !types.AcquireRetry(ctx) {
+			return nil, fmt.Errorf("retry budget exhausted after %d models, last error: %w", i, lastErr)
+		}
+
+		attempt := options
+		attempt.Model = model
+		attempt.ModelFallbacks = nil
+
+		attemptCtx := ctx
+		if i < len(models)-1 {
+			attemptCtx = types.WithModelFallback(ctx)
+		}
+		stream, err := generate(attemptCtx, attempt)
+		if err == nil {
+			return &modelFallbackStream{inner: stream, model: model, index: i}, nil
+		}
+		if !IsModelFallbackError(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("all models failed, last error: %w", lastErr)
+}
+
+// IsModelFallbackError reports whether err means the requested model is unavailable, so
+// that the same request may succeed with a different model. It extends
+// types.IsModelUnavailable to also recognize APIError.
+func IsModelFallbackError(err error) bool {
+	if types.IsModelUnavailable(err) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound, http.StatusServiceUnavailable, types.StatusOverloaded:
+			return true
+		}
+	}
+	return false
+}
+
+// modelFallbackStream tags each chunk with the model that served the request
+type modelFallbackStream struct {
+	inner types.ChatCompletionStream
+	model string
+	index int
+}
+
+func (s *modelFallbackStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+
+	served := s.model
+	if served == "" {
+		// The provider's default model was used
+		served = chunk.Model
+	}
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]interface{})
+	}
+	if served != "" {
+		chunk.Metadata["served_model"] = served
+	}
+	chunk.Metadata["model_fallback_index"] = s.index
+	return chunk, err
+}
+
+func (s *modelFallbackStream) Close() error {
+	return s.inner.Close()
+}
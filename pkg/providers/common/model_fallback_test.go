@@ -0,0 +1,130 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestIsModelFallbackError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "provider error", err: types.NewServerError(types.ProviderTypeOpenAI, 503, "unavailable"), want: true},
+		{name: "api error 503", err: &APIError{StatusCode: 503, Type: APIErrorTypeServer}, want: true},
+		{name: "api error 529", err: fmt.Errorf("stream: %w", &APIError{StatusCode: 529, Type: APIErrorTypeServer}), want: true},
+		{name: "api error 500", err: &APIError{StatusCode: 500, Type: APIErrorTypeServer}, want: false},
+		{name: "plain error", err: errors.New("connection reset"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsModelFallbackError(tt.err))
+		})
+	}
+}
+
+func TestGenerateWithModelFallbacks(t *testing.T) {
+	overloaded := types.NewServerError(types.ProviderTypeOpenAI, 503, "model overloaded")
+
+	// generateFor returns a generate function that fails with the mapped error per model
+	generateFor := func(errs map[string]error, attempts *[]string) GenerateFunc {
+		return func(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+			*attempts = append(*attempts, options.Model)
+			assert.Empty(t, options.ModelFallbacks, "fallbacks must not be passed to individual attempts")
+			if err := errs[options.Model]; err != nil {
+				return nil, err
+			}
+			return streaming.NewMockStream([]types.ChatCompletionChunk{{Content: "from " + options.Model, Done: true}}), nil
+		}
+	}
+
+	t.Run("primary model succeeds", func(t *testing.T) {
+		var attempts []string
+		stream, err := GenerateWithModelFallbacks(context.Background(), types.GenerateOptions{
+			Model:          "gpt-4o",
+			ModelFallbacks: []string{"gpt-4o-mini"},
+		}, generateFor(nil, &attempts))
+		require.NoError(t, err)
+
+		chunk, _ := stream.Next()
+		assert.Equal(t, "from gpt-4o", chunk.Content)
+		assert.Equal(t, "gpt-4o", chunk.Metadata["served_model"])
+		assert.Equal(t, 0, chunk.Metadata["model_fallback_index"])
+		assert.Equal(t, []string{"gpt-4o"}, attempts)
+	})
+
+	t.Run("marks every attempt but the last", func(t *testing.T) {
+		marked := map[string]bool{}
+		_, err := GenerateWithModelFallbacks(context.Background(), types.GenerateOptions{
+			Model:          "gpt-4o",
+			ModelFallbacks: []string{"gpt-4o-mini"},
+		}, func(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+			marked[options.Model] = types.HasModelFallback(ctx)
+			return nil, overloaded
+		})
+		require.Error(t, err)
+		assert.Equal(t, map[string]bool{"gpt-4o": true, "gpt-4o-mini": false}, marked)
+	})
+
+	t.Run("falls back on model-specific error", func(t *testing.T) {
+		var attempts []string
+		stream, err := GenerateWithModelFallbacks(context.Background(), types.GenerateOptions{
+			Model:          "gpt-4o",
+			ModelFallbacks: []string{"gpt-4o-mini", "gpt-3.5-turbo"},
+		}, generateFor(map[string]error{"gpt-4o": overloaded}, &attempts))
+		require.NoError(t, err)
+
+		chunk, _ := stream.Next()
+		assert.Equal(t, "from gpt-4o-mini", chunk.Content)
+		assert.Equal(t, "gpt-4o-mini", chunk.Metadata["served_model"])
+		assert.Equal(t, 1, chunk.Metadata["model_fallback_index"])
+		assert.Equal(t, []string{"gpt-4o", "gpt-4o-mini"}, attempts)
+	})
+
+	t.Run("other errors are returned without fallback", func(t *testing.T) {
+		authErr := types.NewAuthError(types.ProviderTypeOpenAI, "bad key")
+		var attempts []string
+		_, err := GenerateWithModelFallbacks(context.Background(), types.GenerateOptions{
+			Model:          "gpt-4o",
+			ModelFallbacks: []string{"gpt-4o-mini"},
+		}, generateFor(map[string]error{"gpt-4o": authErr}, &attempts))
+
+		assert.ErrorIs(t, err, authErr)
+		assert.Equal(t, []string{"gpt-4o"}, attempts)
+	})
+
+	t.Run("all models fail", func(t *testing.T) {
+		notFound := types.NewNotFoundError(types.ProviderTypeOpenAI, "model not found")
+		var attempts []string
+		_, err := GenerateWithModelFallbacks(context.Background(), types.GenerateOptions{
+			Model:          "gpt-4o",
+			ModelFallbacks: []string{"gpt-4o-mini"},
+		}, generateFor(map[string]error{"gpt-4o": overloaded, "gpt-4o-mini": notFound}, &attempts))
+
+		assert.ErrorIs(t, err, notFound)
+		assert.Contains(t, err.Error(), "all models failed")
+		assert.Equal(t, []string{"gpt-4o", "gpt-4o-mini"}, attempts)
+	})
+	t.Run("fallbacks take retries from the budget", func(t *testing.T) {
+		var attempts []string
+		ctx := types.WithRetryBudget(context.Background(), types.NewRetryBudget(1))
+		_, err := GenerateWithModelFallbacks(ctx, types.GenerateOptions{
+			Model:          "gpt-4o",
+			ModelFallbacks: []string{"gpt-4o-mini", "gpt-3.5-turbo"},
+		}, generateFor(map[string]error{"gpt-4o": overloaded, "gpt-4o-mini": overloaded}, &attempts))
+
+		assert.ErrorIs(t, err, overloaded)
+		assert.Contains(t, err.Error(), "retry budget exhausted")
+		assert.Equal(t, []string{"gpt-4o", "gpt-4o-mini"}, attempts)
+	})
+}
@@ -1,5 +1,12 @@
 package common
 
+import (
+	"log"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
 // ResolveModel returns the model to use, with fallback priority:
 // 1. Model specified in options
 // 2. Default model from provider config
@@ -13,3 +20,34 @@ func ResolveModel(optionsModel, configDefaultModel, providerDefaultModel string)
 	}
 	return providerDefaultModel
 }
+
+// ResolveMaxTokens returns the max_tokens value to send for a model, logging a warning
+// when the requested value exceeds the model's output limit and is reduced to it.
+// See models.ResolveMaxTokens for how defaults are chosen.
+func ResolveMaxTokens(providerType types.ProviderType, model string, requested int) int {
+	resolution := models.ResolveMaxTokens(providerType, model, requested)
+	if resolution.Warning != "" {
+		log.Printf("⚠️ [%s] %s", providerType, resolution.Warning)
+	}
+	return resolution.MaxTokens
+}
+
+// ModelCapabilities returns the request features provider supports for model, for
+// providers implementing types.CapabilitiesReporter. Tools and streaming start from
+// SupportsToolCalling and SupportsStreaming; models known to the embedded models.dev
+// defaults are further limited by their tool, vision and structured output support.
+// Unknown models are assumed to support vision and JSON schema.
+func ModelCapabilities(provider types.Provider, model string) types.Capabilities {
+	capabilities := types.Capabilities{
+		Tools:      provider.SupportsToolCalling(),
+		Vision:     true,
+		JSONSchema: true,
+		Streaming:  provider.SupportsStreaming(),
+	}
+	if known, ok := models.GetCapabilities(provider.Type(), model); ok {
+		capabilities.Tools = capabilities.Tools && known.Tools
+		capabilities.Vision = known.Vision
+		capabilities.JSONSchema = known.JSONSchema
+	}
+	return capabilities
+}
@@ -22,19 +22,21 @@ type ModelsDevProvider struct {
 
 // ModelsDevModel represents a model in the models.dev dataset
 type ModelsDevModel struct {
-	ID          string              `json:"id"`
-	Name        string              `json:"name"`
-	Attachment  bool                `json:"attachment"`
-	Reasoning   bool                `json:"reasoning"`
-	ToolCall    bool                `json:"tool_call"`
-	Temperature bool                `json:"temperature"`
-	Knowledge   string              `json:"knowledge"`
-	ReleaseDate string              `json:"release_date"`
-	LastUpdated string              `json:"last_updated"`
-	Modalities  ModelsDevModalities `json:"modalities"`
-	OpenWeights bool                `json:"open_weights"`
-	Cost        *ModelsDevCost      `json:"cost,omitempty"`
-	Limit       ModelsDevLimit      `json:"limit"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Attachment bool   `json:"attachment"`
+	Reasoning  bool   `json:"reasoning"`
+	ToolCall   bool   `json:"tool_call"`
+	// StructuredOutput is JSON schema output support, nil when the dataset doesn't say
+	StructuredOutput *bool               `json:"structured_output,omitempty"`
+	Temperature      bool                `json:"temperature"`
+	Knowledge        string              `json:"knowledge"`
+	ReleaseDate      string              `json:"release_date"`
+	LastUpdated      string              `json:"last_updated"`
+	Modalities       ModelsDevModalities `json:"modalities"`
+	OpenWeights      bool                `json:"open_weights"`
+	Cost             *ModelsDevCost      `json:"cost,omitempty"`
+	Limit            ModelsDevLimit      `json:"limit"`
 }
 
 // ModelsDevModalities represents input/output modalities
@@ -123,6 +125,40 @@ func (r *DefaultsRegistry) GetModelDefaults(modelID string) *ModelMetadata {
 	return nil
 }
 
+// GetProviderModelDefaults returns the default capabilities for a model of a specific
+// provider ID, preferring an exact match and then the longest model ID that is a prefix
+// of modelID (e.g. "claude-sonnet-4-5" for a dated version). When the provider does
+// not list the model, an exact match from any provider is used.
+func (r *DefaultsRegistry) GetProviderModelDefaults(providerID, modelID string) *ModelMetadata {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var match *ModelsDevModel
+	if provider, exists := r.providers[providerID]; exists {
+		if model, ok := provider.Models[modelID]; ok {
+			match = &model
+		} else {
+			matchID := ""
+			for id, model := range provider.Models {
+				if strings.HasPrefix(modelID, id) && len(id) > len(matchID) {
+					model := model
+					match, matchID = &model, id
+				}
+			}
+		}
+	}
+	if match != nil {
+		return r.convertToMetadata(match)
+	}
+
+	for _, provider := range r.providers {
+		if model, exists := provider.Models[modelID]; exists {
+			return r.convertToMetadata(&model)
+		}
+	}
+	return nil
+}
+
 // GetProviderModels returns all models for a specific provider ID
 func (r *DefaultsRegistry) GetProviderModels(providerID string) map[string]*ModelMetadata {
 	r.mu.RLock()
@@ -148,13 +184,17 @@ func (r *DefaultsRegistry) convertToMetadata(model *ModelsDevModel) *ModelMetada
 	}
 
 	metadata := &ModelMetadata{
-		DisplayName: model.Name,
-		MaxTokens:   model.Limit.Context,
-		Description: "",
+		DisplayName:     model.Name,
+		MaxTokens:       model.Limit.Context,
+		MaxOutputTokens: model.Limit.Output,
+		Description:     "",
 		Capabilities: ModelCapabilities{
 			SupportsTools:     model.ToolCall,
+			SupportsReasoning: model.Reasoning,
 			SupportsStreaming: true, // Assume streaming is supported by default
 			SupportsVision:    r.hasVisionSupport(model),
+			// Models the dataset doesn't mark are assumed to support it
+			SupportsStructuredOutput: model.StructuredOutput == nil || *model.StructuredOutput,
 		},
 	}
 
@@ -220,5 +260,9 @@ func ApplyUserOverride(metadata *ModelMetadata, override types.ModelCapabilityOv
 		result.Capabilities.SupportsVision = *override.SupportsVision
 	}
 
+	if override.SupportsJSONSchema != nil {
+		result.Capabilities.SupportsStructuredOutput = *override.SupportsJSONSchema
+	}
+
 	return &result
 }
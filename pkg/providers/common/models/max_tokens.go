@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Default max_tokens values used when a request leaves MaxTokens at zero
+const (
+	// DefaultMaxOutputTokens is the default for standard models of providers without
+	// a default of their own in providerMaxOutputTokens
+	DefaultMaxOutputTokens = 4096
+
+	// DefaultReasoningMaxOutputTokens is the default for reasoning models, whose
+	// thinking counts against max_tokens and is truncated by the standard default
+	DefaultReasoningMaxOutputTokens = 16384
+)
+
+// providerMaxOutputTokens holds the standard model defaults of providers that have
+// always sent more than DefaultMaxOutputTokens
+var providerMaxOutputTokens = map[types.ProviderType]int{
+	types.ProviderTypeGemini: 8192,
+}
+
+// datasetProviderIDs maps provider types to their provider IDs in the models.dev
+// snapshot, where they differ
+var datasetProviderIDs = map[types.ProviderType]string{
+	types.ProviderTypeGemini:    "google",
+	types.ProviderTypeQwen:      "alibaba",
+	types.ProviderTypeFireworks: "fireworks-ai",
+}
+
+// MaxTokensResolution is the result of ResolveMaxTokens
+type MaxTokensResolution struct {
+	// MaxTokens is the value to send to the provider
+	MaxTokens int
+
+	// Limit is the model's maximum output tokens, 0 when unknown
+	Limit int
+
+	// Warning is set when the requested value exceeded Limit and was reduced to it
+	Warning string
+}
+
+// ResolveMaxTokens returns the max_tokens value to send for a model. A positive
+// requested value is used as is, reduced to the model's output limit if it exceeds it.
+// When requested is zero, the default is DefaultReasoningMaxOutputTokens for reasoning
+// models and otherwise the provider's standard default (DefaultMaxOutputTokens, or
+// 8192 for Gemini), capped at the model's output limit.
+// Limits and reasoning support come from the embedded models.dev defaults; unknown
+// models are not capped.
+func ResolveMaxTokens(providerType types.ProviderType, modelID string, requested int) MaxTokensResolution {
+	var limit int
+	if metadata := GetDefaultsRegistry().GetProviderModelDefaults(datasetProviderID(providerType), modelID); metadata != nil {
+		limit = metadata.MaxOutputTokens
+	}
+	reasoning := IsReasoningModel(providerType, modelID)
+
+	resolution := MaxTokensResolution{MaxTokens: requested, Limit: limit}
+	if requested > 0 {
+		if limit > 0 && requested > limit {
+			resolution.MaxTokens = limit
+			resolution.Warning = fmt.Sprintf("max_tokens %d exceeds the %d output token limit of %s, using %d", requested, limit, modelID, limit)
+		}
+		return resolution
+	}
+
+	resolution.MaxTokens = DefaultMaxOutputTokens
+	if providerDefault, ok := providerMaxOutputTokens[providerType]; ok {
+		resolution.MaxTokens = providerDefault
+	}
+	if reasoning {
+		resolution.MaxTokens = DefaultReasoningMaxOutputTokens
+	}
+	if limit > 0 && resolution.MaxTokens > limit {
+		resolution.MaxTokens = limit
+	}
+	return resolution
+}
+
+// GetContextWindow returns modelID's context window in tokens, covering input and
+// output, from the embedded models.dev defaults, or 0 when the model is unknown
+func GetContextWindow(providerType types.ProviderType, modelID string) int {
+	if metadata := GetDefaultsRegistry().GetProviderModelDefaults(datasetProviderID(providerType), modelID); metadata != nil {
+		return metadata.MaxTokens
+	}
+	return 0
+}
+
+// GetCapabilities returns the request features modelID supports according to the
+// embedded models.dev defaults, reporting false when the model is unknown. Streaming is
+// assumed for every model.
+func GetCapabilities(providerType types.ProviderType, modelID string) (types.Capabilities, bool) {
+	metadata := GetDefaultsRegistry().GetProviderModelDefaults(datasetProviderID(providerType), modelID)
+	if metadata == nil {
+		return types.Capabilities{}, false
+	}
+	return types.Capabilities{
+		Tools:      metadata.Capabilities.SupportsTools,
+		Vision:     metadata.Capabilities.SupportsVision,
+		JSONSchema: metadata.Capabilities.SupportsStructuredOutput,
+		Streaming:  metadata.Capabilities.SupportsStreaming,
+	}, true
+}
+
+// IsReasoningModel reports whether modelID is a reasoning model, which thinks before
+// answering, according to the embedded models.dev defaults or, for models missing from
+// them, the model's name
+func IsReasoningModel(providerType types.ProviderType, modelID string) bool {
+	if isReasoningModelID(modelID) {
+		return true
+	}
+	metadata := GetDefaultsRegistry().GetProviderModelDefaults(datasetProviderID(providerType), modelID)
+	return metadata != nil && metadata.Capabilities.SupportsReasoning
+}
+
+func datasetProviderID(providerType types.ProviderType) string {
+	if id, ok := datasetProviderIDs[providerType]; ok {
+		return id
+	}
+	return string(providerType)
+}
+
+// isReasoningModelID recognizes reasoning models by name, for models missing from
+// the defaults
+func isReasoningModelID(modelID string) bool {
+	id := strings.ToLower(modelID)
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	for _, prefix := range []string{"o1", "o3", "o4", "gpt-5", "deepseek-r1"} {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(id, "thinking") || strings.Contains(id, "reasoner")
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveMaxTokens(t *testing.T) {
+	t.Run("default for standard model", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeOpenAI, "gpt-4o", 0)
+		assert.Equal(t, DefaultMaxOutputTokens, resolution.MaxTokens)
+		assert.Equal(t, 16384, resolution.Limit)
+		assert.Empty(t, resolution.Warning)
+	})
+
+	t.Run("higher default for reasoning model", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeAnthropic, "claude-sonnet-4-5-20250929", 0)
+		assert.Equal(t, DefaultReasoningMaxOutputTokens, resolution.MaxTokens)
+		assert.Equal(t, 64000, resolution.Limit)
+	})
+
+	t.Run("default capped at model limit", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeAnthropic, "claude-3-haiku-20240307", 0)
+		assert.Equal(t, 4096, resolution.MaxTokens)
+	})
+
+	t.Run("provider default for standard model", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeGemini, "gemini-2.0-flash", 0)
+		assert.Equal(t, 8192, resolution.MaxTokens, "Gemini keeps its higher default")
+		assert.Equal(t, 8192, resolution.Limit)
+
+		resolution = ResolveMaxTokens(types.ProviderTypeGemini, "my-tuned-gemini", 0)
+		assert.Equal(t, 8192, resolution.MaxTokens)
+	})
+
+	t.Run("requested value within limit", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeGemini, "gemini-2.5-pro", 32000)
+		assert.Equal(t, 32000, resolution.MaxTokens)
+		assert.Empty(t, resolution.Warning)
+	})
+
+	t.Run("requested value over limit", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeAnthropic, "claude-3-5-haiku-20241022", 20000)
+		assert.Equal(t, 8192, resolution.MaxTokens)
+		assert.Contains(t, resolution.Warning, "exceeds the 8192 output token limit")
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		resolution := ResolveMaxTokens(types.ProviderTypeOllama, "my-local-model", 0)
+		assert.Equal(t, DefaultMaxOutputTokens, resolution.MaxTokens)
+		assert.Zero(t, resolution.Limit)
+
+		resolution = ResolveMaxTokens(types.ProviderTypeOllama, "my-local-model", 500000)
+		assert.Equal(t, 500000, resolution.MaxTokens, "unknown models are not capped")
+
+		resolution = ResolveMaxTokens(types.ProviderTypeOpenRouter, "acme/acme-thinking-v2", 0)
+		assert.Equal(t, DefaultReasoningMaxOutputTokens, resolution.MaxTokens, "reasoning recognized by name")
+	})
+}
+
+func TestGetContextWindow(t *testing.T) {
+	assert.Equal(t, 128000, GetContextWindow(types.ProviderTypeOpenAI, "gpt-4o"))
+	assert.Equal(t, 0, GetContextWindow(types.ProviderTypeOpenAI, "unknown-model"))
+}
+
+func TestGetCapabilities(t *testing.T) {
+	capabilities, ok := GetCapabilities(types.ProviderTypeOpenAI, "gpt-4o")
+	assert.True(t, ok)
+	assert.Equal(t, types.Capabilities{Tools: true, Vision: true, JSONSchema: true, Streaming: true}, capabilities)
+
+	capabilities, ok = GetCapabilities(types.ProviderTypeOpenAI, "gpt-4")
+	assert.True(t, ok)
+	assert.False(t, capabilities.Vision)
+	assert.False(t, capabilities.JSONSchema, "structured_output is false in the defaults")
+
+	capabilities, ok = GetCapabilities(types.ProviderTypeAnthropic, "claude-sonnet-4-20250514")
+	assert.True(t, ok)
+	assert.True(t, capabilities.JSONSchema, "unmarked models are assumed to support it")
+
+	_, ok = GetCapabilities(types.ProviderTypeOpenAI, "unknown-model")
+	assert.False(t, ok)
+}
+
+func TestIsReasoningModel(t *testing.T) {
+	assert.True(t, IsReasoningModel(types.ProviderTypeOpenAI, "o3-mini"))
+	assert.True(t, IsReasoningModel(types.ProviderTypeOpenAI, "gpt-5"))
+	assert.True(t, IsReasoningModel(types.ProviderTypeAnthropic, "claude-sonnet-4-5-20250929"), "reasoning from the defaults")
+	assert.False(t, IsReasoningModel(types.ProviderTypeOpenAI, "gpt-4o"))
+	assert.False(t, IsReasoningModel(types.ProviderTypeOllama, "my-local-model"))
+}
+
+func TestGetProviderModelDefaults(t *testing.T) {
+	registry := GetDefaultsRegistry()
+
+	metadata := registry.GetProviderModelDefaults("anthropic", "claude-opus-4-5-20251101")
+	if assert.NotNil(t, metadata) {
+		assert.Equal(t, 64000, metadata.MaxOutputTokens)
+		assert.True(t, metadata.Capabilities.SupportsReasoning)
+	}
+
+	// Longest prefix match for versions missing from the snapshot
+	metadata = registry.GetProviderModelDefaults("openai", "gpt-4o-mini-2099-01-01")
+	if assert.NotNil(t, metadata) {
+		assert.Equal(t, "GPT-4o mini", metadata.DisplayName)
+	}
+
+	assert.Nil(t, registry.GetProviderModelDefaults("ollama", "my-local-model"))
+}
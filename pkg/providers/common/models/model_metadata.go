@@ -26,6 +26,10 @@ func GetStaticFallback(providerType types.ProviderType) []types.Model {
 		return AnthropicFallbackModels
 	case types.ProviderTypeGemini:
 		return GeminiFallbackModels
+	case types.ProviderTypexAI:
+		return XAIFallbackModels
+	case types.ProviderTypeDeepseek:
+		return DeepSeekFallbackModels
 	default:
 		return []types.Model{}
 	}
@@ -120,6 +124,19 @@ var GeminiFallbackModels = []types.Model{
 	{ID: "gemini-2.0-flash-lite-001", Name: "Gemini 2.0 Flash Lite 001", Provider: types.ProviderTypeGemini, MaxTokens: 524288, SupportsStreaming: true, SupportsToolCalling: true, Description: "Gemini 2.0 Flash Lite version 001"},
 }
 
+// XAIFallbackModels contains static fallback models for xAI Grok
+var XAIFallbackModels = []types.Model{
+	{ID: "grok-4", Name: "Grok 4", Provider: types.ProviderTypexAI, MaxTokens: 256000, SupportsStreaming: true, SupportsToolCalling: true, Description: "xAI's flagship reasoning model"},
+	{ID: "grok-3", Name: "Grok 3", Provider: types.ProviderTypexAI, MaxTokens: 131072, SupportsStreaming: true, SupportsToolCalling: true, Description: "xAI's general-purpose model"},
+	{ID: "grok-3-mini", Name: "Grok 3 Mini", Provider: types.ProviderTypexAI, MaxTokens: 131072, SupportsStreaming: true, SupportsToolCalling: true, Description: "Lightweight reasoning model with adjustable reasoning effort"},
+}
+
+// DeepSeekFallbackModels contains static fallback models for DeepSeek
+var DeepSeekFallbackModels = []types.Model{
+	{ID: "deepseek-chat", Name: "DeepSeek Chat", Provider: types.ProviderTypeDeepseek, MaxTokens: 128000, SupportsStreaming: true, SupportsToolCalling: true, Description: "DeepSeek's general-purpose model"},
+	{ID: "deepseek-reasoner", Name: "DeepSeek Reasoner", Provider: types.ProviderTypeDeepseek, MaxTokens: 128000, SupportsStreaming: true, SupportsToolCalling: true, Description: "DeepSeek's reasoning model, returning its reasoning content"},
+}
+
 // GetOpenAIMetadataRegistry returns a pre-populated registry for OpenAI models
 func GetOpenAIMetadataRegistry() *ModelMetadataRegistry {
 	registry := NewModelMetadataRegistry()
@@ -17,18 +17,21 @@ type ModelMetadataRegistry struct {
 
 // ModelMetadata contains comprehensive metadata for a model
 type ModelMetadata struct {
-	DisplayName   string
-	MaxTokens     int
-	Description   string
-	Capabilities  ModelCapabilities
-	CostPerMToken CostInfo
+	DisplayName     string
+	MaxTokens       int
+	MaxOutputTokens int // Maximum tokens the model can generate in one response, 0 if unknown
+	Description     string
+	Capabilities    ModelCapabilities
+	CostPerMToken   CostInfo
 }
 
 // ModelCapabilities defines what a model can do
 type ModelCapabilities struct {
-	SupportsTools     bool
-	SupportsStreaming bool
-	SupportsVision    bool
+	SupportsTools            bool
+	SupportsStreaming        bool
+	SupportsVision           bool
+	SupportsReasoning        bool
+	SupportsStructuredOutput bool // JSON schema output
 }
 
 // CostInfo contains pricing information per million tokens
@@ -132,6 +135,9 @@ func (r *ModelMetadataRegistry) EnrichModel(model *types.Model) *types.Model {
 	if metadata.MaxTokens > 0 {
 		enriched.MaxTokens = metadata.MaxTokens
 	}
+	if metadata.MaxOutputTokens > 0 {
+		enriched.OutputTokens = metadata.MaxOutputTokens
+	}
 	if metadata.Description != "" {
 		enriched.Description = metadata.Description
 	}
@@ -200,6 +206,9 @@ func (r *ModelMetadataRegistry) EnrichModelWithOverrides(model *types.Model, ove
 		if metadata.MaxTokens > 0 {
 			enriched.MaxTokens = metadata.MaxTokens
 		}
+		if metadata.MaxOutputTokens > 0 {
+			enriched.OutputTokens = metadata.MaxOutputTokens
+		}
 		if metadata.Description != "" {
 			enriched.Description = metadata.Description
 		}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// GenerateWithoutPrefill serves a request with a prefill (see
+// types.GenerateOptions.Prefill) on a provider that cannot continue one: the prefill
+// message is dropped and the first chunk of the response carries a
+// types.WarningParameterIgnored warning saying so.
+//
+// Providers call it at the top of GenerateChatCompletion:
+//
+//	if _, ok := options.Prefill(); ok {
+//	    return common.GenerateWithoutPrefill(ctx, options, p.Type(), p.GenerateChatCompletion)
+//	}
+func GenerateWithoutPrefill(ctx context.Context, options types.GenerateOptions, providerType types.ProviderType, generate GenerateFunc) (types.ChatCompletionStream, error) {
+	stream, err := generate(ctx, options.WithoutPrefill())
+	if err != nil {
+		return nil, err
+	}
+	return types.StreamWithWarnings(stream, []types.Warning{{
+		Code:    types.WarningParameterIgnored,
+		Message: fmt.Sprintf("provider %s does not support assistant prefill; the trailing assistant message was dropped", providerType),
+		Param:   "messages",
+	}}), nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
 // RateLimitHelper provides shared rate limiting functionality for all AI providers.
@@ -16,6 +17,7 @@ import (
 type RateLimitHelper struct {
 	tracker *ratelimit.Tracker
 	parser  ratelimit.Parser
+	last    *ratelimit.Info // Most recent info that reported any limit, for Status
 	mutex   sync.RWMutex
 }
 
@@ -33,6 +35,7 @@ func (h *RateLimitHelper) ParseAndUpdateRateLimits(headers http.Header, model st
 	if info, err := h.parser.Parse(headers, model); err == nil {
 		h.mutex.Lock()
 		h.tracker.Update(info)
+		h.recordLast(info)
 		h.mutex.Unlock()
 
 		// Log rate limit information using provider-specific formatting
@@ -193,11 +196,60 @@ func (h *RateLimitHelper) UpdateRateLimitInfo(info *ratelimit.Info) {
 
 	h.mutex.Lock()
 	h.tracker.Update(info)
+	h.recordLast(info)
 	h.mutex.Unlock()
 
 	h.logRateLimitInfo(info)
 }
 
+// recordLast keeps info for Status if it reports any limit; responses without
+// rate-limit headers leave the last status in place. Callers must hold the write lock.
+func (h *RateLimitHelper) recordLast(info *ratelimit.Info) {
+	if info == nil {
+		return
+	}
+	if info.RequestsLimit == 0 && info.RequestsRemaining == 0 && info.RequestsReset.IsZero() &&
+		info.TokensLimit == 0 && info.TokensRemaining == 0 && info.TokensReset.IsZero() &&
+		info.RetryAfter == 0 {
+		return
+	}
+	if info.Timestamp.IsZero() {
+		// Not every parser stamps its info; RetryAt is relative to when it was received
+		stamped := *info
+		stamped.Timestamp = time.Now()
+		info = &stamped
+	}
+	h.last = info
+}
+
+// Status returns the rate-limit state from the most recent response that reported it,
+// across all models, or types.UnknownRateLimitStatus if none has
+func (h *RateLimitHelper) Status() types.RateLimitStatus {
+	h.mutex.RLock()
+	info := h.last
+	h.mutex.RUnlock()
+
+	if info == nil {
+		return types.UnknownRateLimitStatus
+	}
+
+	status := types.RateLimitStatus{
+		Known:             true,
+		Model:             info.Model,
+		RequestsLimit:     info.RequestsLimit,
+		RequestsRemaining: info.RequestsRemaining,
+		RequestsReset:     info.RequestsReset,
+		TokensLimit:       info.TokensLimit,
+		TokensRemaining:   info.TokensRemaining,
+		TokensReset:       info.TokensReset,
+		ObservedAt:        info.Timestamp,
+	}
+	if info.RetryAfter > 0 {
+		status.RetryAt = info.Timestamp.Add(info.RetryAfter)
+	}
+	return status
+}
+
 // GetTracker returns the underlying rate limit tracker for advanced operations.
 // This should be used sparingly when the helper methods don't provide sufficient functionality.
 func (h *RateLimitHelper) GetTracker() *ratelimit.Tracker {
@@ -340,3 +340,52 @@ func TestRateLimitHelper_ThreadSafety(t *testing.T) {
 		<-done
 	}
 }
+
+func TestRateLimitHelper_Status(t *testing.T) {
+	parser := &MockParser{providerName: "openai", info: &ratelimit.Info{Model: "gpt-4"}}
+	helper := NewRateLimitHelper(parser)
+
+	if status := helper.Status(); status.Known {
+		t.Fatalf("expected an unknown status before any response, got %+v", status)
+	}
+
+	// A response without rate-limit headers keeps the status unknown
+	helper.ParseAndUpdateRateLimits(http.Header{}, "gpt-4")
+	if status := helper.Status(); status.Known {
+		t.Fatalf("expected an unknown status without rate-limit data, got %+v", status)
+	}
+
+	reset := time.Now().Add(time.Minute)
+	parser.info = &ratelimit.Info{
+		Model:             "gpt-4",
+		RequestsLimit:     100,
+		RequestsRemaining: 0,
+		RequestsReset:     reset,
+		RetryAfter:        20 * time.Second,
+	}
+	before := time.Now()
+	helper.ParseAndUpdateRateLimits(http.Header{}, "gpt-4")
+
+	status := helper.Status()
+	if !status.Known || status.Model != "gpt-4" {
+		t.Fatalf("expected a known status for gpt-4, got %+v", status)
+	}
+	if status.RequestsLimit != 100 || status.RequestsRemaining != 0 || !status.RequestsReset.Equal(reset) {
+		t.Errorf("unexpected request limits: %+v", status)
+	}
+	if status.ObservedAt.Before(before) {
+		t.Errorf("expected the observation time to be stamped, got %v", status.ObservedAt)
+	}
+	if want := status.ObservedAt.Add(20 * time.Second); !status.RetryAt.Equal(want) {
+		t.Errorf("got RetryAt %v, expected %v", status.RetryAt, want)
+	}
+	if !status.Limited(time.Now()) {
+		t.Error("expected the status to be limited")
+	}
+
+	// The status is stable between calls
+	time.Sleep(5 * time.Millisecond)
+	if again := helper.Status(); !again.RetryAt.Equal(status.RetryAt) {
+		t.Errorf("RetryAt moved from %v to %v", status.RetryAt, again.RetryAt)
+	}
+}
@@ -2,7 +2,6 @@ package retry
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -122,36 +121,6 @@ func (p *RetryPolicy) isRetryableStatusCode(statusCode int) bool {
 	return IsRetryableStatusCode(statusCode)
 }
 
-// ParseRetryAfter parses the Retry-After header from an HTTP response
-// It supports both delay-seconds (integer) and HTTP-date formats
-// Returns the duration to wait before retrying, or 0 if not present/invalid
-func ParseRetryAfter(headers http.Header) time.Duration {
-	retryAfter := headers.Get("Retry-After")
-	if retryAfter == "" {
-		return 0
-	}
-
-	// Try to parse as delay-seconds (integer)
-	if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
-		if seconds > 0 {
-			return time.Duration(seconds) * time.Second
-		}
-		return 0
-	}
-
-	// Try to parse as HTTP-date
-	if t, err := http.ParseTime(retryAfter); err == nil {
-		duration := time.Until(t)
-		if duration > 0 {
-			return duration
-		}
-		return 0
-	}
-
-	// Unable to parse, return 0
-	return 0
-}
-
 // GetRetryDelay calculates the retry delay for a given attempt
 // It respects the Retry-After header if present, otherwise uses backoff strategy
 func (p *RetryPolicy) GetRetryDelay(attempt int, headers http.Header) time.Duration {
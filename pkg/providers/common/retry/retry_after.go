@@ -0,0 +1,166 @@
+package retry
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRetryAfter extracts how long to wait before retrying from the headers of a
+// rate-limited response. Returns 0 if the headers carry no usable retry hint; use
+// LookupRetryAfter to tell that apart from a hint of zero.
+func ParseRetryAfter(headers http.Header) time.Duration {
+	d, _ := LookupRetryAfter(headers)
+	return d
+}
+
+// LookupRetryAfter extracts how long to wait before retrying from the headers of a
+// rate-limited response, reporting false if the headers carry no usable retry hint.
+//
+// Headers are checked in order:
+//   - retry-after-ms: milliseconds, sent by OpenAI and Azure alongside Retry-After
+//   - Retry-After: delay-seconds or HTTP-date (RFC 9110)
+//   - x-ratelimit-reset-requests / x-ratelimit-reset-tokens: Go-style durations ("6m0s", "20ms") used by OpenAI and Cerebras
+//   - anthropic-ratelimit-*-reset: RFC 3339 timestamps
+//   - x-ratelimit-reset: OpenRouter's epoch milliseconds (epoch seconds and delta seconds are also accepted)
+//
+// When several reset headers are present, the exhausted limit (remaining == 0) wins;
+// otherwise the longest reset is used. Durations in the past are reported as zero.
+func LookupRetryAfter(headers http.Header) (time.Duration, bool) {
+	if d, ok := parseRetryAfterMs(headers.Get("retry-after-ms")); ok {
+		return d, true
+	}
+
+	if d, ok := parseRetryAfterHeader(headers.Get("Retry-After")); ok {
+		return d, true
+	}
+
+	if d, ok := parseResetPair(headers, "x-ratelimit", parseDurationHeader); ok {
+		return d, true
+	}
+
+	if d, ok := parseResetPair(headers, "anthropic-ratelimit", parseTimestampHeader); ok {
+		return d, true
+	}
+
+	if d, ok := parseEpochOrDelta(headers.Get("x-ratelimit-reset")); ok {
+		return d, true
+	}
+
+	return 0, false
+}
+
+// parseRetryAfterMs parses a retry-after-ms value, a (possibly fractional) number of milliseconds
+func parseRetryAfterMs(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	ms, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return nonNegative(time.Duration(ms * float64(time.Millisecond))), true
+}
+
+// parseRetryAfterHeader parses a Retry-After value as delay-seconds or an HTTP-date
+func parseRetryAfterHeader(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return nonNegative(time.Duration(seconds * float64(time.Second))), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return nonNegative(time.Until(t)), true
+	}
+	return 0, false
+}
+
+// parseResetPair parses the <prefix>-reset-requests and <prefix>-reset-tokens headers
+// (or the <prefix>-requests-reset / <prefix>-tokens-reset form used by Anthropic)
+func parseResetPair(headers http.Header, prefix string, parse func(string) (time.Duration, bool)) (time.Duration, bool) {
+	var longest time.Duration
+	found := false
+
+	for _, limit := range []string{"requests", "tokens", "input-tokens", "output-tokens"} {
+		reset := headers.Get(prefix + "-reset-" + limit)
+		if reset == "" {
+			reset = headers.Get(prefix + "-" + limit + "-reset")
+		}
+		d, ok := parse(reset)
+		if !ok {
+			continue
+		}
+
+		remaining := headers.Get(prefix + "-remaining-" + limit)
+		if remaining == "" {
+			remaining = headers.Get(prefix + "-" + limit + "-remaining")
+		}
+		if remaining == "0" {
+			return d, true
+		}
+
+		if !found || d > longest {
+			longest = d
+			found = true
+		}
+	}
+
+	return longest, found
+}
+
+// parseDurationHeader parses a Go-style duration such as "1s" or "6m0s"
+func parseDurationHeader(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return nonNegative(d), true
+}
+
+// parseTimestampHeader parses an RFC 3339 timestamp into a duration from now
+func parseTimestampHeader(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return nonNegative(time.Until(t)), true
+}
+
+// parseEpochOrDelta parses a numeric reset value. Large values are treated as
+// epoch milliseconds or epoch seconds, small values as seconds from now.
+func parseEpochOrDelta(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	switch {
+	case n >= 1e12:
+		return nonNegative(time.Until(time.UnixMilli(n))), true
+	case n >= 1e9:
+		return nonNegative(time.Until(time.Unix(n, 0))), true
+	default:
+		return nonNegative(time.Duration(n) * time.Second), true
+	}
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
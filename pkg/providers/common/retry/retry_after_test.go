@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func headersOf(values map[string]string) http.Header {
+	headers := make(http.Header)
+	for k, v := range values {
+		headers.Set(k, v)
+	}
+	return headers
+}
+
+func TestParseRetryAfter_ResetHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{
+			name:    "retry-after fractional seconds",
+			headers: map[string]string{"Retry-After": "1.5"},
+			want:    1500 * time.Millisecond,
+		},
+		{
+			name:    "retry-after-ms takes precedence over retry-after",
+			headers: map[string]string{"retry-after-ms": "20", "Retry-After": "1"},
+			want:    20 * time.Millisecond,
+		},
+		{
+			name:    "retry-after takes precedence over reset headers",
+			headers: map[string]string{"Retry-After": "5", "x-ratelimit-reset-requests": "1m0s"},
+			want:    5 * time.Second,
+		},
+		{
+			name:    "openai duration reset uses longest",
+			headers: map[string]string{"x-ratelimit-reset-requests": "20ms", "x-ratelimit-reset-tokens": "6m0s"},
+			want:    6 * time.Minute,
+		},
+		{
+			name: "openai duration reset prefers exhausted limit",
+			headers: map[string]string{
+				"x-ratelimit-reset-requests":     "2s",
+				"x-ratelimit-remaining-requests": "0",
+				"x-ratelimit-reset-tokens":       "6m0s",
+				"x-ratelimit-remaining-tokens":   "1000",
+			},
+			want: 2 * time.Second,
+		},
+		{
+			name:    "openrouter delta seconds",
+			headers: map[string]string{"x-ratelimit-reset": "12"},
+			want:    12 * time.Second,
+		},
+		{
+			name:    "past epoch milliseconds clamps to zero",
+			headers: map[string]string{"x-ratelimit-reset": "1600000000000"},
+			want:    0,
+		},
+		{
+			name:    "unparseable values",
+			headers: map[string]string{"Retry-After": "soon", "x-ratelimit-reset": "later"},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseRetryAfter(headersOf(tt.headers)))
+		})
+	}
+}
+
+func TestParseRetryAfter_ResetTimestamps(t *testing.T) {
+	// Allow for the time elapsed between building the header and parsing it
+	within := func(t *testing.T, got, want time.Duration) {
+		t.Helper()
+		assert.True(t, got <= want && got > want-3*time.Second, "expected about %v, got %v", want, got)
+	}
+
+	t.Run("anthropic rfc3339", func(t *testing.T) {
+		reset := time.Now().Add(45 * time.Second).UTC().Format(time.RFC3339)
+		within(t, ParseRetryAfter(headersOf(map[string]string{
+			"anthropic-ratelimit-requests-reset":     reset,
+			"anthropic-ratelimit-requests-remaining": "0",
+		})), 45*time.Second)
+	})
+
+	t.Run("openrouter epoch milliseconds", func(t *testing.T) {
+		reset := strconv.FormatInt(time.Now().Add(30*time.Second).UnixMilli(), 10)
+		within(t, ParseRetryAfter(headersOf(map[string]string{"x-ratelimit-reset": reset})), 30*time.Second)
+	})
+}
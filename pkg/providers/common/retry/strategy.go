@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
 // RetryStrategy defines the interface for retry execution
@@ -75,6 +77,9 @@ func (r *RetryExecutor) Execute(ctx context.Context, operation func() error) err
 			}
 			return fmt.Errorf("non-retryable error: %w", err)
 		}
+		if !types.AcquireRetry(ctx) {
+			return fmt.Errorf("retry budget exhausted after %d attempts: %w", attempt+1, err)
+		}
 
 		// Calculate delay before next retry
 		delay := r.strategy.NextDelay(attempt, err)
@@ -133,6 +138,9 @@ func (r *RetryExecutor) ExecuteWithResult(ctx context.Context, operation func()
 			}
 			return result, fmt.Errorf("non-retryable error: %w", err)
 		}
+		if !types.AcquireRetry(ctx) {
+			return result, fmt.Errorf("retry budget exhausted after %d attempts: %w", attempt+1, err)
+		}
 
 		// Calculate delay before next retry
 		delay := r.strategy.NextDelay(attempt, err)
@@ -221,6 +229,9 @@ func (r *RetryExecutor) ExecuteWithCallback(
 			}
 			return fmt.Errorf("non-retryable error: %w", err)
 		}
+		if !types.AcquireRetry(ctx) {
+			return fmt.Errorf("retry budget exhausted after %d attempts: %w", attempt+1, err)
+		}
 
 		// Calculate delay before next retry
 		delay := r.strategy.NextDelay(attempt, err)
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -100,6 +101,33 @@ func TestRetryExecutor_Execute_MaxRetriesExceeded(t *testing.T) {
 	assert.Equal(t, 3, callCount, "Should attempt initial + 2 retries")
 }
 
+func TestRetryExecutor_Execute_RetryBudget(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxRetries:   3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Multiplier:   1.0,
+	}
+	executor := NewRetryExecutor(policy, NewExponentialBackoffStrategy(policy).WithJitterType(NoJitter))
+	budget := types.NewRetryBudget(4)
+	ctx := types.WithRetryBudget(context.Background(), budget)
+
+	callCount := 0
+	operation := func() error {
+		callCount++
+		return MarkRetryable(errors.New("persistent error"), 503)
+	}
+
+	// Nested layers share the budget: the outer retry gets what the inner one left
+	err := executor.Execute(ctx, func() error { return MarkRetryable(executor.Execute(ctx, operation), 503) })
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry budget exhausted")
+	assert.Contains(t, err.Error(), "persistent error")
+	assert.Equal(t, 0, budget.Remaining())
+	assert.Equal(t, 5, callCount, "two inner first attempts and three inner retries; the outer layer used the fourth retry")
+}
+
 func TestRetryExecutor_Execute_NonRetryableError(t *testing.T) {
 	executor := NewDefaultRetryExecutor()
 	ctx := context.Background()
@@ -0,0 +1,148 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// StreamOpener starts a streaming request, e.g. by calling a provider's
+// GenerateChatCompletion with Stream set
+type StreamOpener func(ctx context.Context) (types.ChatCompletionStream, error)
+
+// ProviderStreamOpener returns a StreamOpener that starts a streaming chat completion
+func ProviderStreamOpener(provider types.Provider, options types.GenerateOptions) StreamOpener {
+	options.Stream = true
+	return func(ctx context.Context) (types.ChatCompletionStream, error) {
+		return provider.GenerateChatCompletion(ctx, options)
+	}
+}
+
+// OpenStream starts a stream and reconnects when the connection fails before the first
+// chunk arrives, by calling open again after the executor's backoff delay. Failures when
+// opening the stream and from its first Next call are retried if the executor's policy
+// considers them retryable or they are connection errors (reset, refused, unexpected
+// EOF). Once a chunk has been received the request cannot be replayed without
+// duplicating output, so later errors are returned as is.
+//
+// This operates at the streaming layer and complements retries of individual HTTP
+// requests: a provider may return a stream whose connection drops before any data.
+func OpenStream(ctx context.Context, executor *RetryExecutor, open StreamOpener) (types.ChatCompletionStream, error) {
+	stream := &reconnectingStream{ctx: ctx, executor: executor, open: open}
+	executor.strategy.Reset()
+	if err := stream.connect(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// reconnectingStream reopens its stream on failures before the first chunk
+type reconnectingStream struct {
+	ctx      context.Context
+	executor *RetryExecutor
+	open     StreamOpener
+	current  types.ChatCompletionStream
+	attempt  int
+	started  bool
+}
+
+// Next returns the next chunk, reconnecting if the stream fails before its first chunk
+func (s *reconnectingStream) Next() (types.ChatCompletionChunk, error) {
+	for {
+		chunk, err := s.current.Next()
+		if err == nil {
+			s.started = true
+			return chunk, nil
+		}
+		if s.started || errors.Is(err, io.EOF) {
+			return chunk, err
+		}
+
+		_ = s.current.Close()
+		if err := s.backoff(err); err != nil {
+			return types.ChatCompletionChunk{}, err
+		}
+		if err := s.connect(); err != nil {
+			return types.ChatCompletionChunk{}, err
+		}
+	}
+}
+
+// Close closes the current underlying stream
+func (s *reconnectingStream) Close() error {
+	return s.current.Close()
+}
+
+// connect opens the stream, retrying failures allowed by the policy
+func (s *reconnectingStream) connect() error {
+	for {
+		stream, err := s.open(s.ctx)
+		if err == nil {
+			s.current = stream
+			return nil
+		}
+		if err := s.backoff(err); err != nil {
+			return err
+		}
+	}
+}
+
+// backoff waits before the next connection attempt, or returns the error to surface
+// when err should not be retried
+func (s *reconnectingStream) backoff(err error) error {
+	if !s.shouldReconnect(err) {
+		if s.attempt > 0 {
+			return fmt.Errorf("stream failed after %d reconnect attempts: %w", s.attempt, err)
+		}
+		return err
+	}
+	if !types.AcquireRetry(s.ctx) {
+		return fmt.Errorf("retry budget exhausted after %d connection attempts: %w", s.attempt+1, err)
+	}
+
+	delay := s.executor.strategy.NextDelay(s.attempt, err)
+	log.Printf("[ReconnectingStream] Connection attempt %d failed before the first chunk: %v. Reconnecting in %v", s.attempt+1, err, delay)
+
+	select {
+	case <-s.ctx.Done():
+		return fmt.Errorf("context cancelled during reconnect wait after %d attempts: %w", s.attempt+1, err)
+	case <-time.After(delay):
+	}
+
+	s.attempt++
+	return nil
+}
+
+func (s *reconnectingStream) shouldReconnect(err error) bool {
+	if s.attempt >= s.executor.policy.MaxRetries || s.ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return s.executor.policy.ShouldRetry(err, s.attempt) || IsConnectionError(err)
+}
+
+// IsConnectionError reports whether err is a transient transport failure: a reset,
+// refused or broken connection, a network error, or a connection closed mid-response
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
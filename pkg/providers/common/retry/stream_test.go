@@ -0,0 +1,178 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// scriptedStream returns its chunks, then err (io.EOF if nil)
+type scriptedStream struct {
+	chunks []types.ChatCompletionChunk
+	err    error
+	closed bool
+}
+
+func (s *scriptedStream) Next() (types.ChatCompletionChunk, error) {
+	if len(s.chunks) == 0 {
+		if s.err != nil {
+			return types.ChatCompletionChunk{}, s.err
+		}
+		return types.ChatCompletionChunk{}, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *scriptedStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func testStreamExecutor(maxRetries int) *RetryExecutor {
+	policy := NoRetryPolicy().WithMaxRetries(maxRetries)
+	return NewRetryExecutor(policy, NewConstantBackoffStrategy(time.Millisecond))
+}
+
+// collect reads a stream to the end and returns its content and final error
+func collect(stream types.ChatCompletionStream) (string, error) {
+	var content string
+	for {
+		chunk, err := stream.Next()
+		content += chunk.Content
+		if err != nil {
+			return content, err
+		}
+	}
+}
+
+func TestOpenStream_ReconnectsBeforeFirstChunk(t *testing.T) {
+	var opened []*scriptedStream
+	results := []func() (*scriptedStream, error){
+		func() (*scriptedStream, error) { return nil, fmt.Errorf("dial: %w", syscall.ECONNREFUSED) },
+		func() (*scriptedStream, error) { return &scriptedStream{err: io.ErrUnexpectedEOF}, nil },
+		func() (*scriptedStream, error) {
+			return &scriptedStream{chunks: []types.ChatCompletionChunk{{Content: "Hello"}, {Content: " world", Done: true}}}, nil
+		},
+	}
+
+	open := func(ctx context.Context) (types.ChatCompletionStream, error) {
+		stream, err := results[len(opened)]()
+		opened = append(opened, stream)
+		if err != nil {
+			return nil, err
+		}
+		return stream, nil
+	}
+
+	stream, err := OpenStream(context.Background(), testStreamExecutor(3), open)
+	require.NoError(t, err)
+
+	content, err := collect(stream)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "Hello world", content)
+	assert.Len(t, opened, 3)
+	assert.True(t, opened[1].closed, "the failed stream should be closed before reconnecting")
+
+	require.NoError(t, stream.Close())
+	assert.True(t, opened[2].closed)
+}
+
+func TestOpenStream_MidStreamErrorsSurface(t *testing.T) {
+	calls := 0
+	open := func(ctx context.Context) (types.ChatCompletionStream, error) {
+		calls++
+		return &scriptedStream{
+			chunks: []types.ChatCompletionChunk{{Content: "partial"}},
+			err:    fmt.Errorf("read: %w", syscall.ECONNRESET),
+		}, nil
+	}
+
+	stream, err := OpenStream(context.Background(), testStreamExecutor(3), open)
+	require.NoError(t, err)
+
+	content, err := collect(stream)
+	assert.Equal(t, "partial", content)
+	assert.ErrorIs(t, err, syscall.ECONNRESET)
+	assert.Equal(t, 1, calls, "a stream that produced output must not be replayed")
+}
+
+func TestOpenStream_NonRetryableErrors(t *testing.T) {
+	calls := 0
+	authErr := types.NewProviderError(types.ProviderTypeOpenAI, types.ErrCodeAuthentication, "invalid API key")
+	open := func(ctx context.Context) (types.ChatCompletionStream, error) {
+		calls++
+		return nil, authErr
+	}
+
+	_, err := OpenStream(context.Background(), testStreamExecutor(3), open)
+	assert.ErrorIs(t, err, authErr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestOpenStream_RetryableProviderErrors(t *testing.T) {
+	calls := 0
+	open := func(ctx context.Context) (types.ChatCompletionStream, error) {
+		calls++
+		if calls == 1 {
+			return nil, types.NewProviderError(types.ProviderTypeOpenAI, types.ErrCodeServerError, "bad gateway").WithStatusCode(502)
+		}
+		return &scriptedStream{chunks: []types.ChatCompletionChunk{{Content: "ok"}}}, nil
+	}
+
+	stream, err := OpenStream(context.Background(), testStreamExecutor(1), open)
+	require.NoError(t, err)
+	content, _ := collect(stream)
+	assert.Equal(t, "ok", content)
+	assert.Equal(t, 2, calls)
+}
+
+func TestOpenStream_MaxRetries(t *testing.T) {
+	calls := 0
+	open := func(ctx context.Context) (types.ChatCompletionStream, error) {
+		calls++
+		return &scriptedStream{err: io.ErrUnexpectedEOF}, nil
+	}
+
+	stream, err := OpenStream(context.Background(), testStreamExecutor(2), open)
+	require.NoError(t, err)
+
+	_, err = stream.Next()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Contains(t, err.Error(), "after 2 reconnect attempts")
+	assert.Equal(t, 3, calls)
+}
+
+func TestOpenStream_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	open := func(ctx context.Context) (types.ChatCompletionStream, error) {
+		calls++
+		return nil, fmt.Errorf("dial: %w", syscall.ECONNREFUSED)
+	}
+
+	_, err := OpenStream(ctx, testStreamExecutor(3), open)
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, IsConnectionError(fmt.Errorf("read: %w", syscall.ECONNRESET)))
+	assert.True(t, IsConnectionError(io.ErrUnexpectedEOF))
+	assert.False(t, IsConnectionError(io.EOF))
+	assert.False(t, IsConnectionError(errors.New("invalid request")))
+	assert.False(t, IsConnectionError(nil))
+}
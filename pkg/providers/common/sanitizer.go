@@ -0,0 +1,166 @@
+package common
+
+import (
+	"context"
+	"log"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// SanitizeMode selects how SanitizeRequest handles unsupported request features
+type SanitizeMode int
+
+const (
+	// SanitizeStrict rejects a request that uses an unsupported feature with a
+	// *types.UnsupportedFeatureError
+	SanitizeStrict SanitizeMode = iota
+
+	// SanitizeLenient removes unsupported features from the request and logs a warning
+	SanitizeLenient
+)
+
+// SanitizeRequest checks each feature used by options (tools, image content, JSON
+// schema output, streaming) against types.ProviderCapabilities, and each parameter
+// set (temperature, stop, n, parallel tool calls, reasoning effort, store) against
+// types.SupportedParameters, so a request the provider cannot serve fails with a
+// precise error instead of a provider 400.
+//
+// In SanitizeStrict mode the first unsupported feature is returned as a
+// *types.UnsupportedFeatureError naming the feature and provider, and the first
+// unsupported parameter as a *types.UnsupportedParameterError. In SanitizeLenient
+// mode unsupported features are stripped: tools and tool choice are dropped, image
+// parts are removed from messages, ResponseFormat is cleared and Stream is turned
+// off. Unsupported parameters are reset to their zero values, leaving the provider
+// default. The caller's options are never modified.
+func SanitizeRequest(provider types.Provider, options types.GenerateOptions, mode SanitizeMode) (types.GenerateOptions, error) {
+	capabilities := types.ProviderCapabilities(provider, options.Model)
+
+	for _, feature := range types.RequestFeatures(options) {
+		if capabilities.Supports(feature) {
+			continue
+		}
+		if mode == SanitizeStrict {
+			return options, &types.UnsupportedFeatureError{
+				Provider: provider.Type(),
+				Model:    options.Model,
+				Feature:  feature,
+			}
+		}
+
+		log.Printf("[Sanitizer] Provider %s does not support %s, removing it from the request", provider.Name(), feature)
+		switch feature {
+		case types.FeatureTools:
+			options.Tools = nil
+			options.ToolChoice = nil
+		case types.FeatureVision:
+			options.Messages = stripImageParts(options.Messages)
+		case types.FeatureJSONSchema:
+			options.ResponseFormat = ""
+		case types.FeatureStreaming:
+			options.Stream = false
+		}
+	}
+
+	support := types.SupportedParameters(provider, options.Model)
+	for _, param := range requestParameters {
+		if !param.isSet(options) || param.supported(support) {
+			continue
+		}
+		if mode == SanitizeStrict {
+			return options, &types.UnsupportedParameterError{
+				Provider:  provider.Type(),
+				Model:     options.Model,
+				Parameter: param.name,
+			}
+		}
+
+		log.Printf("[Sanitizer] Provider %s does not accept %s for model %s, removing it from the request", provider.Name(), param.name, options.Model)
+		param.clear(&options)
+	}
+	return options, nil
+}
+
+// requestParameters are the GenerateOptions parameters SanitizeRequest checks against
+// types.SupportedParameters
+var requestParameters = []struct {
+	name      string
+	isSet     func(types.GenerateOptions) bool
+	supported func(types.ParameterSupport) bool
+	clear     func(*types.GenerateOptions)
+}{
+	{
+		name:      "temperature",
+		isSet:     func(o types.GenerateOptions) bool { return o.Temperature != 0 },
+		supported: func(s types.ParameterSupport) bool { return s.Temperature },
+		clear:     func(o *types.GenerateOptions) { o.Temperature = 0 },
+	},
+	{
+		name:      "stop",
+		isSet:     func(o types.GenerateOptions) bool { return len(o.Stop) > 0 },
+		supported: func(s types.ParameterSupport) bool { return s.Stop },
+		clear:     func(o *types.GenerateOptions) { o.Stop = nil },
+	},
+	{
+		name:      "n",
+		isSet:     func(o types.GenerateOptions) bool { return o.N > 1 },
+		supported: func(s types.ParameterSupport) bool { return s.N },
+		clear:     func(o *types.GenerateOptions) { o.N = 0 },
+	},
+	{
+		name:      "parallel_tool_calls",
+		isSet:     func(o types.GenerateOptions) bool { return o.ParallelToolCalls != nil },
+		supported: func(s types.ParameterSupport) bool { return s.ParallelToolCalls },
+		clear:     func(o *types.GenerateOptions) { o.ParallelToolCalls = nil },
+	},
+	{
+		name:      "reasoning_effort",
+		isSet:     func(o types.GenerateOptions) bool { return o.ReasoningEffort != "" },
+		supported: func(s types.ParameterSupport) bool { return s.ReasoningEffort },
+		clear:     func(o *types.GenerateOptions) { o.ReasoningEffort = "" },
+	},
+	{
+		name:      "store",
+		isSet:     func(o types.GenerateOptions) bool { return o.Store != nil },
+		supported: func(s types.ParameterSupport) bool { return s.Store },
+		clear:     func(o *types.GenerateOptions) { o.Store = nil },
+	},
+}
+
+// stripImageParts returns a copy of messages without image content parts
+func stripImageParts(messages []types.ChatMessage) []types.ChatMessage {
+	result := make([]types.ChatMessage, len(messages))
+	for i, msg := range messages {
+		if msg.HasImages() {
+			parts := make([]types.ContentPart, 0, len(msg.Parts))
+			for _, part := range msg.Parts {
+				if part.Type != types.ContentTypeImage {
+					parts = append(parts, part)
+				}
+			}
+			msg.Parts = parts
+		}
+		result[i] = msg
+	}
+	return result
+}
+
+// SanitizingProvider wraps a provider so every chat completion request passes
+// through SanitizeRequest before it is sent
+type SanitizingProvider struct {
+	types.Provider
+	mode SanitizeMode
+}
+
+// NewSanitizingProvider wraps provider with a SanitizeRequest step in the given mode
+func NewSanitizingProvider(provider types.Provider, mode SanitizeMode) *SanitizingProvider {
+	return &SanitizingProvider{Provider: provider, mode: mode}
+}
+
+// GenerateChatCompletion sanitizes options and forwards them to the wrapped provider
+func (p *SanitizingProvider) GenerateChatCompletion(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+	sanitized, err := SanitizeRequest(p.Provider, options, p.mode)
+	if err != nil {
+		return nil, err
+	}
+	return p.Provider.GenerateChatCompletion(ctx, sanitized)
+}
@@ -0,0 +1,186 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// capabilityProvider reports fixed capabilities and records the last request;
+// unused Provider methods panic
+type capabilityProvider struct {
+	types.Provider
+	config       types.ProviderConfig
+	capabilities *types.Capabilities
+	tools        bool
+	lastOptions  *types.GenerateOptions
+}
+
+func (p *capabilityProvider) Name() string                    { return "capability" }
+func (p *capabilityProvider) Type() types.ProviderType        { return types.ProviderTypeOllama }
+func (p *capabilityProvider) GetConfig() types.ProviderConfig { return p.config }
+func (p *capabilityProvider) SupportsToolCalling() bool       { return p.tools }
+func (p *capabilityProvider) SupportsStreaming() bool         { return true }
+func (p *capabilityProvider) GenerateChatCompletion(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+	p.lastOptions = &options
+	return nil, nil
+}
+
+// reportingProvider adds types.CapabilitiesReporter to capabilityProvider
+type reportingProvider struct {
+	*capabilityProvider
+}
+
+func (p *reportingProvider) Capabilities(string) types.Capabilities { return *p.capabilities }
+
+// parameterProvider adds types.ParameterSupportReporter to capabilityProvider
+type parameterProvider struct {
+	*capabilityProvider
+	support types.ParameterSupport
+}
+
+func (p *parameterProvider) SupportedParameters(model string) types.ParameterSupport {
+	return p.support
+}
+
+func fullRequest() types.GenerateOptions {
+	return types.GenerateOptions{
+		Model: "llama3",
+		Messages: []types.ChatMessage{{
+			Role:  "user",
+			Parts: []types.ContentPart{types.NewTextPart("what is this?"), types.NewImagePart("image/png", "aGVsbG8=")},
+		}},
+		Tools:          []types.Tool{{Name: "lookup", Description: "Look something up"}},
+		ToolChoice:     &types.ToolChoice{Mode: types.ToolChoiceAuto},
+		ResponseFormat: `{"type":"object"}`,
+		Stream:         true,
+	}
+}
+
+func TestSanitizeRequest_Strict(t *testing.T) {
+	provider := &capabilityProvider{tools: false}
+
+	_, err := SanitizeRequest(provider, fullRequest(), SanitizeStrict)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, types.ErrUnsupportedFeature))
+
+	var unsupported *types.UnsupportedFeatureError
+	require.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, types.FeatureTools, unsupported.Feature)
+	assert.Equal(t, types.ProviderTypeOllama, unsupported.Provider)
+	assert.Equal(t, "provider ollama does not support tools with model llama3", err.Error())
+
+	// Supported features pass through untouched
+	provider.tools = true
+	options, err := SanitizeRequest(provider, fullRequest(), SanitizeStrict)
+	require.NoError(t, err)
+	assert.Equal(t, fullRequest(), options)
+}
+
+func TestSanitizeRequest_CapabilitiesReporter(t *testing.T) {
+	provider := &reportingProvider{&capabilityProvider{
+		tools:        true,
+		capabilities: &types.Capabilities{Tools: true, Vision: false, JSONSchema: true, Streaming: true},
+	}}
+
+	_, err := SanitizeRequest(provider, fullRequest(), SanitizeStrict)
+	var unsupported *types.UnsupportedFeatureError
+	require.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, types.FeatureVision, unsupported.Feature)
+}
+
+func TestSanitizeRequest_ModelOverride(t *testing.T) {
+	noVision := false
+	provider := &capabilityProvider{tools: true, config: types.ProviderConfig{
+		DefaultModel: "llama3",
+		ModelCapabilities: map[string]types.ModelCapabilityOverride{
+			"llama3": {SupportsVision: &noVision},
+		},
+	}}
+
+	_, err := SanitizeRequest(provider, fullRequest(), SanitizeStrict)
+	assert.ErrorIs(t, err, types.ErrUnsupportedFeature)
+
+	// The default model's overrides apply when the request names no model
+	options := fullRequest()
+	options.Model = ""
+	_, err = SanitizeRequest(provider, options, SanitizeStrict)
+	assert.ErrorIs(t, err, types.ErrUnsupportedFeature)
+
+	options.Model = "llava"
+	_, err = SanitizeRequest(provider, options, SanitizeStrict)
+	assert.NoError(t, err)
+}
+
+func TestSanitizeRequest_Lenient(t *testing.T) {
+	provider := &reportingProvider{&capabilityProvider{
+		capabilities: &types.Capabilities{},
+	}}
+
+	original := fullRequest()
+	options, err := SanitizeRequest(provider, original, SanitizeLenient)
+	require.NoError(t, err)
+
+	assert.Nil(t, options.Tools)
+	assert.Nil(t, options.ToolChoice)
+	assert.Empty(t, options.ResponseFormat)
+	assert.False(t, options.Stream)
+	require.Len(t, options.Messages, 1)
+	require.Len(t, options.Messages[0].Parts, 1)
+	assert.Equal(t, types.ContentTypeText, options.Messages[0].Parts[0].Type)
+
+	// The caller's request is not modified
+	assert.Len(t, original.Messages[0].Parts, 2)
+	assert.Empty(t, types.RequestFeatures(options))
+}
+
+func TestSanitizeRequest_Parameters(t *testing.T) {
+	support := types.AllParametersSupported
+	support.Temperature = false
+	support.ParallelToolCalls = false
+	provider := &parameterProvider{capabilityProvider: &capabilityProvider{tools: true}, support: support}
+
+	parallel := false
+	original := types.GenerateOptions{
+		Model:             "o1",
+		Prompt:            "hi",
+		Temperature:       0.7,
+		Stop:              []string{"END"},
+		ParallelToolCalls: &parallel,
+		ReasoningEffort:   types.ReasoningEffortHigh,
+	}
+
+	_, err := SanitizeRequest(provider, original, SanitizeStrict)
+	assert.ErrorIs(t, err, types.ErrUnsupportedParameter)
+	var unsupported *types.UnsupportedParameterError
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, "temperature", unsupported.Parameter)
+	assert.Equal(t, "model o1 of provider ollama does not accept temperature", err.Error())
+
+	options, err := SanitizeRequest(provider, original, SanitizeLenient)
+	require.NoError(t, err)
+	assert.Zero(t, options.Temperature)
+	assert.Nil(t, options.ParallelToolCalls)
+	assert.Equal(t, []string{"END"}, options.Stop)
+	assert.Equal(t, types.ReasoningEffortHigh, options.ReasoningEffort)
+	assert.Equal(t, 0.7, original.Temperature)
+}
+
+func TestSanitizingProvider(t *testing.T) {
+	inner := &capabilityProvider{tools: false}
+
+	_, err := NewSanitizingProvider(inner, SanitizeStrict).GenerateChatCompletion(context.Background(), fullRequest())
+	assert.ErrorIs(t, err, types.ErrUnsupportedFeature)
+	assert.Nil(t, inner.lastOptions, "request must not be sent")
+
+	_, err = NewSanitizingProvider(inner, SanitizeLenient).GenerateChatCompletion(context.Background(), fullRequest())
+	require.NoError(t, err)
+	require.NotNil(t, inner.lastOptions)
+	assert.Empty(t, inner.lastOptions.Tools)
+	assert.True(t, inner.lastOptions.Stream)
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"context"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// GenerateWithStrategies applies the request strategies a provider delegates to before
+// serving a request itself, in order: model fallbacks (GenerateWithModelFallbacks), a
+// context window upgrade (GenerateWithContextUpgrade) and hedging (GenerateWithHedging).
+// Each calls generate again with the strategy resolved. handled is false when none
+// applies, and the provider serves the request.
+//
+// Providers call it at the top of GenerateChatCompletion:
+//
+//	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+//	    return stream, err
+//	}
+func GenerateWithStrategies(ctx context.Context, options types.GenerateOptions, config types.ProviderConfig, generate GenerateFunc) (stream types.ChatCompletionStream, handled bool, err error) {
+	switch {
+	case len(options.ModelFallbacks) > 0:
+		stream, err = GenerateWithModelFallbacks(ctx, options, generate)
+	case NeedsContextUpgrade(config, options):
+		stream, err = GenerateWithContextUpgrade(ctx, options, config, generate)
+	case ShouldHedge(ctx, options, config.HedgeAfter):
+		stream, err = GenerateWithHedging(ctx, options, config.HedgeAfter, generate)
+	default:
+		return nil, false, nil
+	}
+	return stream, true, err
+}
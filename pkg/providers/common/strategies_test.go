@@ -0,0 +1,69 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestGenerateWithStrategies(t *testing.T) {
+	// serve is a provider's GenerateChatCompletion: it applies the strategies, then
+	// serves the request itself, recording the model of each request it serves
+	var served []string
+	var serve GenerateFunc
+	newServe := func(config types.ProviderConfig, errs map[string]error) {
+		served = nil
+		serve = func(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+			if stream, handled, err := GenerateWithStrategies(ctx, options, config, serve); handled {
+				return stream, err
+			}
+			served = append(served, options.Model)
+			if err := errs[options.Model]; err != nil {
+				return nil, err
+			}
+			return streaming.NewMockStream([]types.ChatCompletionChunk{{Content: "from " + options.Model, Done: true}}), nil
+		}
+	}
+
+	t.Run("serves the request when no strategy applies", func(t *testing.T) {
+		newServe(contextUpgradeConfig(), nil)
+		stream, handled, err := GenerateWithStrategies(context.Background(), types.GenerateOptions{Model: "small", Prompt: "hi"}, contextUpgradeConfig(), serve)
+		assert.False(t, handled)
+		assert.Nil(t, stream)
+		assert.NoError(t, err)
+		assert.Empty(t, served)
+	})
+
+	t.Run("model fallbacks", func(t *testing.T) {
+		newServe(types.ProviderConfig{}, map[string]error{"primary": types.NewServerError(types.ProviderTypeOpenAI, 503, "model overloaded")})
+		stream, err := serve(context.Background(), types.GenerateOptions{Model: "primary", ModelFallbacks: []string{"backup"}})
+		require.NoError(t, err)
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "from backup", chunk.Content)
+		assert.Equal(t, []string{"primary", "backup"}, served)
+	})
+
+	t.Run("context upgrade", func(t *testing.T) {
+		newServe(contextUpgradeConfig(), nil)
+		_, err := serve(context.Background(), types.GenerateOptions{Model: "small", Prompt: promptOfTokens(200)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"medium"}, served)
+	})
+
+	t.Run("hedging", func(t *testing.T) {
+		newServe(types.ProviderConfig{HedgeAfter: time.Minute}, nil)
+		stream, err := serve(context.Background(), types.GenerateOptions{Model: "model"})
+		require.NoError(t, err)
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		assert.Equal(t, false, chunk.Metadata["hedged"])
+		assert.Equal(t, []string{"model"}, served)
+	})
+}
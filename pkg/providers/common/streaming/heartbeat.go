@@ -0,0 +1,31 @@
+package streaming
+
+import "github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+
+// IsHeartbeat reports whether a parsed chunk carries nothing for the caller: no
+// content, reasoning, tool calls, finish reason, usage, error or other payload.
+// Providers and proxies send such empty deltas to keep long generations alive, and
+// streams skip them rather than return them, as the SSEScanner skips comment lines.
+// Identifying fields (ID, model) and a delta's role alone are not payload. Content
+// that is only whitespace is output, not a heartbeat.
+func IsHeartbeat(chunk types.ChatCompletionChunk) bool {
+	if chunk.Done || chunk.Content != "" || chunk.Reasoning != "" || chunk.ReasoningContent != "" ||
+		chunk.Error != "" || chunk.Usage != (types.Usage{}) || chunk.TokenCount != nil ||
+		len(chunk.Metadata) > 0 || len(chunk.CodeExecutions) > 0 || len(chunk.ServerToolUses) > 0 ||
+		len(chunk.Citations) > 0 || len(chunk.Warnings) > 0 {
+		return false
+	}
+	for _, choice := range chunk.Choices {
+		if choice.FinishReason != "" || choice.RawFinishReason != "" || !isEmptyDelta(choice.Delta) || !isEmptyDelta(choice.Message) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyDelta reports whether a message has nothing besides, perhaps, its role
+func isEmptyDelta(message types.ChatMessage) bool {
+	return message.Content == "" && len(message.Parts) == 0 && message.Reasoning == "" &&
+		message.ReasoningContent == "" && len(message.ToolCalls) == 0 && message.ToolCallID == "" &&
+		len(message.Citations) == 0 && len(message.Metadata) == 0
+}
@@ -0,0 +1,32 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestIsHeartbeat(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunk     types.ChatCompletionChunk
+		heartbeat bool
+	}{
+		{"empty", types.ChatCompletionChunk{}, true},
+		{"identifiers only", types.ChatCompletionChunk{ID: "1", Object: "chat.completion.chunk", Model: "m"}, true},
+		{"role only", types.ChatCompletionChunk{Choices: []types.ChatChoice{{Delta: types.ChatMessage{Role: "assistant"}}}}, true},
+		{"space", types.ChatCompletionChunk{Content: " "}, false},
+		{"newline", types.ChatCompletionChunk{Content: "\n"}, false},
+		{"reasoning", types.ChatCompletionChunk{Reasoning: "thinking"}, false},
+		{"done", types.ChatCompletionChunk{Done: true}, false},
+		{"usage", types.ChatCompletionChunk{Usage: types.Usage{TotalTokens: 3}}, false},
+		{"finish reason", types.ChatCompletionChunk{Choices: []types.ChatChoice{{FinishReason: "stop"}}}, false},
+		{"tool call", types.ChatCompletionChunk{Choices: []types.ChatChoice{{Delta: types.ChatMessage{ToolCalls: []types.ToolCall{{ID: "call_1"}}}}}}, false},
+		{"warning", types.ChatCompletionChunk{Warnings: []types.Warning{{Code: types.WarningDegraded}}}, false},
+	}
+	for _, tt := range tests {
+		if got := IsHeartbeat(tt.chunk); got != tt.heartbeat {
+			t.Errorf("%s: expected IsHeartbeat %v, got %v", tt.name, tt.heartbeat, got)
+		}
+	}
+}
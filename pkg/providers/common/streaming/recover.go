@@ -0,0 +1,42 @@
+package streaming
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	commonerrors "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/errors"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ErrStreamPanic is matched (with errors.Is) by the error RecoverStreamPanic returns
+// from a stream's Next when reading the stream panicked
+var ErrStreamPanic = errors.New("stream panicked")
+
+// RecoverStreamPanic turns a panic in a stream's Next, e.g. in a parser fed malformed
+// data, into an error returned from Next instead of a crash of the application. Streams
+// defer it at the top of Next, passing their named results:
+//
+//	func (s *MyStream) Next() (chunk types.ChatCompletionChunk, err error) {
+//	    defer streaming.RecoverStreamPanic(types.ProviderTypeOpenAI, &chunk, &err)
+//	    ...
+//	}
+//
+// The error is a *commonerrors.RichError carrying the provider and the stack of the
+// panic, and wrapping ErrStreamPanic. The chunk is marked Done, so callers stop reading.
+// provider may be empty for streams shared between providers.
+func RecoverStreamPanic(provider types.ProviderType, chunk *types.ChatCompletionChunk, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	richErr := commonerrors.NewRichError(fmt.Errorf("%w: %v", ErrStreamPanic, r)).
+		WithOperation("stream").
+		WithStack(debug.Stack())
+	if provider != "" {
+		richErr = richErr.WithProvider(provider)
+	}
+	*chunk = types.ChatCompletionChunk{Done: true}
+	*err = richErr
+}
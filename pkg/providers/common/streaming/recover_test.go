@@ -0,0 +1,92 @@
+package streaming
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	commonerrors "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/errors"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// naiveParser decodes each event with unchecked type assertions, as a careless parser
+// might, so that malformed data panics
+type naiveParser struct{}
+
+func (naiveParser) ParseLine(data string) (types.ChatCompletionChunk, bool, error) {
+	if data == "[DONE]" {
+		return types.ChatCompletionChunk{Done: true}, true, nil
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return types.ChatCompletionChunk{}, false, err
+	}
+	choice := event["choices"].([]interface{})[0].(map[string]interface{})
+	content := choice["delta"].(map[string]interface{})["content"].(string)
+	return types.ChatCompletionChunk{Content: content}, false, nil
+}
+
+func TestRecoverStreamPanic_MalformedSSE(t *testing.T) {
+	sseData := `data: {"choices":[{"delta":{"content":"Hello"}}]}
+
+data: {"choices":"not-an-array"}
+
+data: [DONE]
+
+`
+	resp := &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(sseData)),
+	}
+	stream := CreateCustomStream(resp, naiveParser{})
+	defer func() { _ = stream.Close() }()
+
+	chunk, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Expected no error on first chunk, got: %v", err)
+	}
+	if chunk.Content != "Hello" {
+		t.Errorf("Expected content 'Hello', got: %s", chunk.Content)
+	}
+
+	chunk, err = stream.Next()
+	if !errors.Is(err, ErrStreamPanic) {
+		t.Fatalf("Expected ErrStreamPanic, got: %v", err)
+	}
+	if !chunk.Done {
+		t.Error("Expected Done to be true after a panic")
+	}
+
+	var richErr *commonerrors.RichError
+	if !errors.As(err, &richErr) {
+		t.Fatalf("Expected a RichError, got: %T", err)
+	}
+	if !strings.Contains(richErr.Context().Stack, "naiveParser.ParseLine") {
+		t.Errorf("Expected the stack to include the panicking parser, got: %s", richErr.Context().Stack)
+	}
+	if richErr.Context().Operation != "stream" {
+		t.Errorf("Expected operation 'stream', got: %s", richErr.Context().Operation)
+	}
+}
+
+func TestRecoverStreamPanic_SetsProvider(t *testing.T) {
+	next := func() (chunk types.ChatCompletionChunk, err error) {
+		defer RecoverStreamPanic(types.ProviderTypeGemini, &chunk, &err)
+		panic("boom")
+	}
+
+	_, err := next()
+	var richErr *commonerrors.RichError
+	if !errors.As(err, &richErr) {
+		t.Fatalf("Expected a RichError, got: %v", err)
+	}
+	if richErr.Context().Provider != types.ProviderTypeGemini {
+		t.Errorf("Expected provider gemini, got: %s", richErr.Context().Provider)
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected the panic value in the error, got: %v", err)
+	}
+}
@@ -26,12 +26,13 @@ type SSELineParser interface {
 }
 
 // GenericSSEStream wraps SSE parsing for any provider that implements SSELineParser.
-// It handles the low-level SSE protocol details (reading lines, handling "data:" prefix)
+// It reads events with an SSEScanner, which handles the low-level SSE protocol details,
 // while delegating provider-specific parsing to the SSELineParser implementation.
 type GenericSSEStream struct {
 	response *http.Response
 	reader   *bufio.Reader
 	parser   SSELineParser
+	events   *SSEScanner
 	done     bool
 	mu       sync.Mutex
 }
@@ -50,7 +51,9 @@ func NewGenericSSEStream(resp *http.Response, parser SSELineParser) *GenericSSES
 // Next returns the next chunk from the SSE stream.
 // It reads lines from the stream, extracts SSE data, and uses the parser to convert them to chunks.
 // Returns io.EOF when the stream is complete or an error if parsing fails.
-func (s *GenericSSEStream) Next() (types.ChatCompletionChunk, error) {
+func (s *GenericSSEStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer RecoverStreamPanic("", &chunk, &err)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -58,39 +61,21 @@ func (s *GenericSSEStream) Next() (types.ChatCompletionChunk, error) {
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
 
+	if s.events == nil {
+		s.events = NewSSEScanner(s.reader, SSEScannerOptions{})
+	}
+
 	for {
-		line, err := s.reader.ReadString('\n')
+		event, err := s.events.Next()
 		if err != nil {
 			if err == io.EOF {
+				// Without a done line or a final chunk the server closed the stream early
 				s.done = true
-				return types.ChatCompletionChunk{Done: true}, io.EOF
+				return types.ChatCompletionChunk{}, errClosedByServer()
 			}
-			return types.ChatCompletionChunk{}, fmt.Errorf("error reading stream: %w", err)
-		}
-
-		line = strings.TrimSpace(line)
-
-		// Skip empty lines (SSE uses empty lines as delimiters)
-		if line == "" {
-			continue
-		}
-
-		// Check for SSE comment lines (start with ':')
-		if strings.HasPrefix(line, ":") {
-			continue
-		}
-
-		// Extract SSE data
-		var data string
-		switch {
-		case strings.HasPrefix(line, "data: "):
-			data = strings.TrimPrefix(line, "data: ")
-		case strings.HasPrefix(line, "data:"):
-			data = strings.TrimPrefix(line, "data:")
-		default:
-			// Skip non-data SSE fields (event:, id:, retry:)
-			continue
+			return types.ChatCompletionChunk{}, err
 		}
+		data := event.Data
 
 		// Check if parser indicates this is a completion signal
 		if s.parser.IsDone(data) {
@@ -110,6 +95,9 @@ func (s *GenericSSEStream) Next() (types.ChatCompletionChunk, error) {
 			s.done = true
 			return chunk, io.EOF
 		}
+		if IsHeartbeat(chunk) {
+			continue
+		}
 
 		// Return the parsed chunk
 		return chunk, nil
@@ -212,8 +200,9 @@ func (p *OpenAICompatibleParser) ParseLine(line string) (types.ChatCompletionChu
 
 		// Build ChatChoice for the chunk
 		chatChoice := types.ChatChoice{
-			Index:        choice.Index,
-			FinishReason: choice.FinishReason,
+			Index:           choice.Index,
+			FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, choice.FinishReason),
+			RawFinishReason: choice.FinishReason,
 			Delta: types.ChatMessage{
 				Role:    choice.Delta.Role,
 				Content: choice.Delta.Content,
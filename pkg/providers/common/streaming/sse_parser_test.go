@@ -2,6 +2,7 @@ package streaming
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -566,3 +567,17 @@ data: [DONE]
 		t.Errorf("Expected content 'test', got: %s", chunk.Content)
 	}
 }
+
+func TestGenericSSEStream_ClosedBeforeDone(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("data: {\"choices\":[{\"delta\":{\"content\":\"partial\"}}]}\n\n"))}
+	stream := NewGenericSSEStream(resp, NewOpenAICompatibleParser())
+
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error on first chunk: %v", err)
+	}
+	_, err := stream.Next()
+	var cancelled *types.StreamCancelledError
+	if !errors.As(err, &cancelled) || cancelled.Reason != types.StreamCancelledByServer {
+		t.Errorf("expected a StreamCancelledError closed by server, got %v", err)
+	}
+}
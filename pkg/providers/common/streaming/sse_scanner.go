@@ -0,0 +1,184 @@
+package streaming
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SSEDone is the data OpenAI-compatible APIs send as their last event
+const SSEDone = "[DONE]"
+
+// SSEEvent is one server-sent event
+type SSEEvent struct {
+	Event   string // The "event:" name, or "" for unnamed events
+	Data    string // The "data:" payload
+	ID      string // The last "id:" seen, which carries over to later events
+	Comment bool   // Set for comment lines (": keep-alive"), whose text is in Data
+}
+
+// SSEScannerOptions configures an SSEScanner for a provider's dialect of SSE
+type SSEScannerOptions struct {
+	// DoneSentinel is event data that ends the stream, such as SSEDone. The sentinel
+	// event itself is not returned. Empty means the stream ends only at EOF or IsDone.
+	DoneSentinel string
+
+	// IsDone reports whether an event is the last of the stream, for APIs that signal
+	// the end with a named event or a flag in the payload rather than a sentinel. The
+	// event is returned; the following Next returns io.EOF.
+	IsDone func(event SSEEvent) bool
+
+	// EmitComments returns comment lines (": keep-alive") as events with Comment set,
+	// rather than skipping them
+	EmitComments bool
+
+	// JoinDataLines follows the SSE specification for multi-line data: consecutive
+	// "data:" lines are joined with newlines into one event, dispatched at the blank
+	// line that ends it. By default every "data:" line is an event of its own, which
+	// suits the one-JSON-object-per-line APIs and tolerates servers that omit the
+	// blank lines.
+	JoinDataLines bool
+
+	// HandleEvent is called with each event before it is returned. Returning false
+	// skips the event, e.g. pings; returning an error ends the stream with it, e.g. for
+	// an "error" event.
+	HandleEvent func(event SSEEvent) (bool, error)
+}
+
+// SSEScanner reads server-sent events from a response body, so providers share one SSE
+// implementation rather than each parsing lines. It accepts "data:" with or without a
+// space, "event:" names (which apply until the blank line ending the event), "id:",
+// CRLF line endings and comments; a final line without a newline is not lost. Provider
+// differences are configured with SSEScannerOptions.
+//
+//	events := streaming.NewSSEScanner(resp.Body, streaming.SSEScannerOptions{DoneSentinel: streaming.SSEDone})
+//	for {
+//	    event, err := events.Next()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    ...
+//	}
+//
+// An SSEScanner is not safe for concurrent use.
+type SSEScanner struct {
+	reader   *bufio.Reader
+	options  SSEScannerOptions
+	done     bool
+	finished bool // Ended by the DoneSentinel or an IsDone event
+
+	event string   // Name of the event being read
+	id    string   // Last event ID
+	data  []string // Data lines of the event being read, with JoinDataLines
+}
+
+// NewSSEScanner creates a scanner reading events from r
+func NewSSEScanner(r io.Reader, options SSEScannerOptions) *SSEScanner {
+	reader, ok := r.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(r)
+	}
+	return &SSEScanner{reader: reader, options: options}
+}
+
+// Next returns the next event, or io.EOF once the stream has ended
+func (s *SSEScanner) Next() (SSEEvent, error) {
+	for !s.done {
+		event, err := s.readEvent()
+		if err != nil {
+			s.done = true
+			return SSEEvent{}, err
+		}
+
+		if !event.Comment && s.options.DoneSentinel != "" && strings.TrimSpace(event.Data) == s.options.DoneSentinel {
+			s.done = true
+			s.finished = true
+			break
+		}
+
+		if s.options.HandleEvent != nil {
+			emit, err := s.options.HandleEvent(event)
+			if err != nil {
+				s.done = true
+				return SSEEvent{}, err
+			}
+			if !emit {
+				continue
+			}
+		}
+
+		if !event.Comment && s.options.IsDone != nil && s.options.IsDone(event) {
+			s.done = true
+			s.finished = true
+		}
+		return event, nil
+	}
+	return SSEEvent{}, io.EOF
+}
+
+// Finished reports whether the stream ended with the DoneSentinel or an IsDone event,
+// rather than with the end of the body
+func (s *SSEScanner) Finished() bool {
+	return s.finished
+}
+
+// readEvent reads lines until an event is complete
+func (s *SSEScanner) readEvent() (SSEEvent, error) {
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return SSEEvent{}, fmt.Errorf("error reading stream: %w", err)
+		}
+		eof := err == io.EOF
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if event, ok := s.dispatch(); ok {
+				return event, nil
+			}
+			s.event = ""
+			if eof {
+				return SSEEvent{}, io.EOF
+			}
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "":
+			if s.options.EmitComments {
+				return SSEEvent{Data: value, ID: s.id, Comment: true}, nil
+			}
+		case "event":
+			s.event = value
+		case "id":
+			s.id = value
+		case "data":
+			if !s.options.JoinDataLines {
+				return SSEEvent{Event: s.event, Data: value, ID: s.id}, nil
+			}
+			s.data = append(s.data, value)
+		}
+
+		if eof {
+			// The body ended without a final blank line; dispatch what was read
+			if event, ok := s.dispatch(); ok {
+				return event, nil
+			}
+			return SSEEvent{}, io.EOF
+		}
+	}
+}
+
+// dispatch returns the event whose data lines have been read, if any, with
+// JoinDataLines, and starts the next event
+func (s *SSEScanner) dispatch() (SSEEvent, bool) {
+	if len(s.data) == 0 {
+		return SSEEvent{}, false
+	}
+	event := SSEEvent{Event: s.event, Data: strings.Join(s.data, "\n"), ID: s.id}
+	s.event, s.data = "", nil
+	return event, true
+}
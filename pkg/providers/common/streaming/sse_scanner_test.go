@@ -0,0 +1,247 @@
+package streaming
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// scanAll reads every event from body, returning the events and the error that ended
+// the stream (nil for io.EOF)
+func scanAll(t *testing.T, body string, options SSEScannerOptions) ([]SSEEvent, error) {
+	t.Helper()
+	scanner := NewSSEScanner(strings.NewReader(body), options)
+	var events []SSEEvent
+	for i := 0; i < 100; i++ {
+		event, err := scanner.Next()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+	t.Fatal("scanner did not end")
+	return nil, nil
+}
+
+func eventData(events []SSEEvent) []string {
+	data := make([]string, len(events))
+	for i, event := range events {
+		data[i] = event.Data
+	}
+	return data
+}
+
+func assertData(t *testing.T, events []SSEEvent, want ...string) {
+	t.Helper()
+	got := eventData(events)
+	if len(got) != len(want) {
+		t.Fatalf("got %d events %q, want %d %q", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSSEScanner_OpenAIFormat(t *testing.T) {
+	body := "data: {\"id\":\"1\",\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+		"data: {\"id\":\"1\",\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+		"data: [DONE]\n\n" +
+		"data: {\"ignored\":true}\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{DoneSentinel: SSEDone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events,
+		`{"id":"1","choices":[{"delta":{"content":"Hel"}}]}`,
+		`{"id":"1","choices":[{"delta":{"content":"lo"}}]}`,
+	)
+}
+
+func TestSSEScanner_AnthropicFormat(t *testing.T) {
+	body := "event: message_start\ndata: {\"type\":\"message_start\"}\n\n" +
+		"event: ping\ndata: {\"type\": \"ping\"}\n\n" +
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"text\":\"Hi\"}}\n\n" +
+		"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{
+		HandleEvent: func(event SSEEvent) (bool, error) {
+			return event.Event != "ping", nil
+		},
+		IsDone: func(event SSEEvent) bool {
+			return event.Event == "message_stop"
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	names := []string{"message_start", "content_block_delta", "message_stop"}
+	for i, name := range names {
+		if events[i].Event != name {
+			t.Errorf("event %d: got name %q, want %q", i, events[i].Event, name)
+		}
+	}
+}
+
+func TestSSEScanner_AnthropicErrorEvent(t *testing.T) {
+	body := "event: content_block_delta\ndata: {\"delta\":{\"text\":\"Hi\"}}\n\n" +
+		"event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\"}}\n\n" +
+		"event: content_block_delta\ndata: {\"delta\":{\"text\":\"never read\"}}\n\n"
+	errOverloaded := errors.New("overloaded")
+
+	events, err := scanAll(t, body, SSEScannerOptions{
+		HandleEvent: func(event SSEEvent) (bool, error) {
+			if event.Event == "error" {
+				return false, errOverloaded
+			}
+			return true, nil
+		},
+	})
+	if !errors.Is(err, errOverloaded) {
+		t.Fatalf("got error %v, want %v", err, errOverloaded)
+	}
+	assertData(t, events, `{"delta":{"text":"Hi"}}`)
+}
+
+func TestSSEScanner_GeminiFormat(t *testing.T) {
+	// Gemini separates events with CRLF and ends the stream without a sentinel
+	body := "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hel\"}]}}]}\r\n\r\n" +
+		"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"lo\"}]},\"finishReason\":\"STOP\"}]}\r\n\r\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events,
+		`{"candidates":[{"content":{"parts":[{"text":"Hel"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"lo"}]},"finishReason":"STOP"}]}`,
+	)
+}
+
+func TestSSEScanner_OpenRouterComments(t *testing.T) {
+	body := ": OPENROUTER PROCESSING\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+		": OPENROUTER PROCESSING\n\n" +
+		"data: [DONE]\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{DoneSentinel: SSEDone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, `{"choices":[{"delta":{"content":"Hi"}}]}`)
+
+	events, err = scanAll(t, body, SSEScannerOptions{DoneSentinel: SSEDone, EmitComments: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, "OPENROUTER PROCESSING", `{"choices":[{"delta":{"content":"Hi"}}]}`, "OPENROUTER PROCESSING")
+	if !events[0].Comment || events[1].Comment || !events[2].Comment {
+		t.Errorf("Comment flags = %v, %v, %v; want true, false, true", events[0].Comment, events[1].Comment, events[2].Comment)
+	}
+}
+
+func TestSSEScanner_QwenFormat(t *testing.T) {
+	// DashScope sends id and event fields, and "data:" without a space
+	body := "id:1\nevent:result\n:HTTP_STATUS/200\ndata:{\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+		"id:2\nevent:result\n:HTTP_STATUS/200\ndata:[DONE]\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{DoneSentinel: SSEDone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, `{"choices":[{"delta":{"content":"Hi"}}]}`)
+	if events[0].ID != "1" || events[0].Event != "result" {
+		t.Errorf("got id %q and event %q, want \"1\" and \"result\"", events[0].ID, events[0].Event)
+	}
+}
+
+func TestSSEScanner_CerebrasFormat(t *testing.T) {
+	// Cerebras reports usage in a final chunk before the sentinel
+	body := "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+		"data: {\"choices\":[],\"usage\":{\"total_tokens\":5}}\n\n" +
+		"data: [DONE]\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{DoneSentinel: SSEDone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events,
+		`{"choices":[{"delta":{"content":"Hi"}}]}`,
+		`{"choices":[],"usage":{"total_tokens":5}}`,
+	)
+}
+
+func TestSSEScanner_OllamaOpenAIFormat(t *testing.T) {
+	// Ollama's OpenAI endpoint sends events without blank lines between them
+	body := "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n" +
+		"data: [DONE]\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{DoneSentinel: SSEDone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events,
+		`{"choices":[{"delta":{"content":"Hel"}}]}`,
+		`{"choices":[{"delta":{"content":"lo"}}]}`,
+	)
+}
+
+func TestSSEScanner_JoinDataLines(t *testing.T) {
+	body := "event: message\ndata: first line\ndata: second line\n\n" +
+		"data: alone\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{JoinDataLines: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, "first line\nsecond line", "alone")
+	if events[0].Event != "message" || events[1].Event != "" {
+		t.Errorf("got event names %q and %q, want \"message\" and \"\"", events[0].Event, events[1].Event)
+	}
+}
+
+func TestSSEScanner_FinalLineWithoutNewline(t *testing.T) {
+	events, err := scanAll(t, "data: one\n\ndata: two", SSEScannerOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, "one", "two")
+
+	events, err = scanAll(t, "data: one\ndata: two", SSEScannerOptions{JoinDataLines: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, "one\ntwo")
+}
+
+func TestSSEScanner_EventNameResetsAtBlankLine(t *testing.T) {
+	body := "event: custom\ndata: a\ndata: b\n\ndata: c\n\n"
+
+	events, err := scanAll(t, body, SSEScannerOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertData(t, events, "a", "b", "c")
+	if events[0].Event != "custom" || events[1].Event != "custom" || events[2].Event != "" {
+		t.Errorf("got event names %q, %q, %q", events[0].Event, events[1].Event, events[2].Event)
+	}
+}
+
+func TestSSEScanner_EOFAfterDone(t *testing.T) {
+	scanner := NewSSEScanner(strings.NewReader("data: [DONE]\n\n"), SSEScannerOptions{DoneSentinel: SSEDone})
+	for i := 0; i < 2; i++ {
+		if _, err := scanner.Next(); err != io.EOF {
+			t.Fatalf("call %d: got error %v, want io.EOF", i, err)
+		}
+	}
+}
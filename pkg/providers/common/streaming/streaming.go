@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,12 +12,14 @@ import (
 	"sync"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/google/uuid"
 )
 
 // StreamProcessor provides common streaming functionality for all providers
 type StreamProcessor struct {
 	response *http.Response
 	reader   *bufio.Reader
+	events   *SSEScanner
 	done     bool
 	mutex    sync.Mutex
 }
@@ -42,36 +45,25 @@ func (sp *StreamProcessor) NextChunk(processLine ProcessLineFunc) (types.ChatCom
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
 
+	if sp.events == nil {
+		sp.events = NewSSEScanner(sp.reader, SSEScannerOptions{DoneSentinel: SSEDone})
+	}
+
 	for {
-		line, err := sp.reader.ReadString('\n')
+		event, err := sp.events.Next()
 		if err != nil {
 			if err == io.EOF {
 				sp.done = true
+				if !sp.events.Finished() {
+					return types.ChatCompletionChunk{}, errClosedByServer()
+				}
 				return types.ChatCompletionChunk{Done: true}, io.EOF
 			}
 			return types.ChatCompletionChunk{}, err
 		}
 
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		// Check for standard SSE data format
-		if !strings.HasPrefix(line, "data: ") {
-			continue // Skip non-data lines
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
-
-		// Check for stream end
-		if data == "[DONE]" {
-			sp.done = true
-			return types.ChatCompletionChunk{Done: true}, io.EOF
-		}
-
-		// Process the line using the provided function
-		chunk, err, isDone := processLine(data)
+		// Process the event's data using the provided function
+		chunk, err, isDone := processLine(event.Data)
 		if err != nil {
 			continue // Skip malformed chunks
 		}
@@ -80,11 +72,20 @@ func (sp *StreamProcessor) NextChunk(processLine ProcessLineFunc) (types.ChatCom
 			sp.done = true
 			return chunk, io.EOF
 		}
+		if IsHeartbeat(chunk) {
+			continue
+		}
 
 		return chunk, nil
 	}
 }
 
+// errClosedByServer is returned when a response body ends before the stream's last
+// chunk or done signal, so a dropped connection is not mistaken for a complete answer
+func errClosedByServer() error {
+	return &types.StreamCancelledError{Reason: types.StreamCancelledByServer, Err: io.ErrUnexpectedEOF}
+}
+
 // Close closes the stream and cleans up resources
 func (sp *StreamProcessor) Close() error {
 	sp.mutex.Lock()
@@ -146,14 +147,29 @@ func NewBaseStream(processor *StreamProcessor, parser StreamParser) *BaseStream
 }
 
 // Next returns the next chunk from the stream
-func (bs *BaseStream) Next() (types.ChatCompletionChunk, error) {
-	return bs.processor.NextChunk(func(line string) (types.ChatCompletionChunk, error, bool) {
+func (bs *BaseStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer RecoverStreamPanic("", &chunk, &err)
+
+	chunk, err = bs.processor.NextChunk(func(line string) (types.ChatCompletionChunk, error, bool) {
 		chunk, isDone, err := bs.parser.ParseLine(line)
 		if err != nil {
 			return types.ChatCompletionChunk{}, err, false
 		}
 		return chunk, nil, isDone
 	})
+
+	// A parser holding back the final chunk returns it when the stream ends first,
+	// which also makes a body closed after it a complete stream
+	if err == io.EOF || errors.Is(err, types.ErrStreamCancelled) {
+		if flusher, ok := bs.parser.(interface {
+			Flush() (types.ChatCompletionChunk, bool)
+		}); ok {
+			if final, ok := flusher.Flush(); ok {
+				return final, io.EOF
+			}
+		}
+	}
+	return chunk, err
 }
 
 // Close closes the stream
@@ -170,7 +186,19 @@ type StandardStreamParser struct {
 	DoneField             string
 	UsageField            string
 	ToolCallsField        string
+	FunctionCallField     string // Legacy function calling, reported as a tool call
 	FinishReason          string
+
+	// SeparateReasoning leaves reasoning out of Content, for APIs whose reasoning
+	// models stream the answer in the content field after the reasoning. Otherwise
+	// reasoning stands in for empty content.
+	SeparateReasoning bool
+
+	// AwaitUsage is set when the request asked for stream_options.include_usage, which
+	// makes OpenAI send usage in a chunk of its own after the one with the finish
+	// reason. The stream then ends with that usage chunk rather than the finish reason.
+	AwaitUsage bool
+	final      *types.ChatCompletionChunk // Held back until the usage chunk, if AwaitUsage
 }
 
 // NewStandardStreamParser creates a new standard stream parser with default OpenAI mappings
@@ -182,6 +210,7 @@ func NewStandardStreamParser() *StandardStreamParser {
 		DoneField:             "choices.0.finish_reason",
 		UsageField:            "usage",
 		ToolCallsField:        "choices.0.delta.tool_calls",
+		FunctionCallField:     "choices.0.delta.function_call",
 		FinishReason:          "",
 	}
 }
@@ -222,7 +251,9 @@ func (p *StandardStreamParser) ParseLine(data string) (types.ChatCompletionChunk
 	chunk.ReasoningContent = extractStringField(streamResp, p.ReasoningContentField)
 
 	// Apply reasoning fallback if content is empty
-	applyReasoningFallback(&chunk)
+	if !p.SeparateReasoning {
+		applyReasoningFallback(&chunk)
+	}
 
 	// Check if done
 	if finishReason, ok := getNestedValue(streamResp, p.DoneField); ok {
@@ -245,6 +276,11 @@ func (p *StandardStreamParser) ParseLine(data string) (types.ChatCompletionChunk
 			if totalTokens, ok := usageMap["total_tokens"].(float64); ok {
 				chunk.Usage.TotalTokens = int(totalTokens)
 			}
+			if details, ok := usageMap["prompt_tokens_details"].(map[string]interface{}); ok {
+				if cachedTokens, ok := details["cached_tokens"].(float64); ok {
+					chunk.Usage.CacheReadTokens = int(cachedTokens)
+				}
+			}
 		}
 	}
 
@@ -256,15 +292,99 @@ func (p *StandardStreamParser) ParseLine(data string) (types.ChatCompletionChunk
 					Delta: types.ChatMessage{
 						ToolCalls: convertToolCalls(toolCallsArray),
 					},
-					FinishReason: p.FinishReason,
+					FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, p.FinishReason),
+					RawFinishReason: p.FinishReason,
 				},
 			}
 		}
 	}
 
+	// Legacy function calls stream as one call without an ID or index
+	if len(chunk.Choices) == 0 && p.FunctionCallField != "" {
+		if functionCall, ok := getNestedValue(streamResp, p.FunctionCallField); ok {
+			if functionCallMap, ok := functionCall.(map[string]interface{}); ok {
+				chunk.Choices = []types.ChatChoice{
+					{
+						Delta: types.ChatMessage{
+							ToolCalls: []types.ToolCall{legacyFunctionCallDelta(functionCallMap)},
+						},
+						FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, p.FinishReason),
+						RawFinishReason: p.FinishReason,
+					},
+				}
+			}
+		}
+	}
+
+	// Report the finish reason on the final chunk even without tool calls
+	if chunk.Done && len(chunk.Choices) == 0 {
+		chunk.Choices = []types.ChatChoice{
+			{
+				FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, p.FinishReason),
+				RawFinishReason: p.FinishReason,
+			},
+		}
+	}
+
+	if p.AwaitUsage {
+		return p.awaitUsage(chunk)
+	}
 	return chunk, chunk.Done, nil
 }
 
+// awaitUsage passes on the chunk with the finish reason as an ordinary chunk, and
+// ends the stream with the usage chunk that follows it
+func (p *StandardStreamParser) awaitUsage(chunk types.ChatCompletionChunk) (types.ChatCompletionChunk, bool, error) {
+	if chunk.Done && chunk.Usage.TotalTokens > 0 {
+		return chunk, true, nil // Usage came with the finish reason
+	}
+	if p.final != nil {
+		final := *p.final
+		p.final = nil
+		final.Usage = chunk.Usage
+		return final, true, nil
+	}
+	if chunk.Done {
+		p.final = &types.ChatCompletionChunk{
+			Done: true,
+			Choices: []types.ChatChoice{
+				{
+					FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, p.FinishReason),
+					RawFinishReason: p.FinishReason,
+				},
+			},
+		}
+		chunk.Done = false
+	}
+	return chunk, false, nil
+}
+
+// Flush returns the final chunk when the stream ended without the usage chunk
+// AwaitUsage was waiting for, e.g. from a server that ignores stream_options
+func (p *StandardStreamParser) Flush() (types.ChatCompletionChunk, bool) {
+	if p.final == nil {
+		return types.ChatCompletionChunk{}, false
+	}
+	final := *p.final
+	p.final = nil
+	return final, true
+}
+
+// legacyFunctionCallDelta converts a legacy function_call delta to a tool call delta.
+// The first delta, which carries the name, gets a generated ID.
+func legacyFunctionCallDelta(functionCall map[string]interface{}) types.ToolCall {
+	index := 0
+	toolCall := types.ToolCall{Type: "function", Index: &index}
+	if name, ok := functionCall["name"].(string); ok && name != "" {
+		toolCall.ID = "call_" + uuid.New().String()
+		toolCall.Function.Name = name
+	}
+	if args, ok := functionCall["arguments"].(string); ok {
+		toolCall.Function.Arguments = args
+	}
+	return toolCall
+}
+
 // getNestedValue extracts a nested value from a map using dot notation
 // Supports both map keys and array indices (e.g., "choices.0.delta.tool_calls")
 func getNestedValue(data map[string]interface{}, path string) (interface{}, bool) {
@@ -363,6 +483,10 @@ func convertToolCalls(toolCallsArray []interface{}) []types.ToolCall {
 			if typ, ok := toolCallMap["type"].(string); ok {
 				toolCall.Type = typ
 			}
+			if index, ok := toolCallMap["index"].(float64); ok {
+				i := int(index)
+				toolCall.Index = &i
+			}
 			if functionMap, ok := toolCallMap["function"].(map[string]interface{}); ok {
 				toolCall.Function = types.ToolCallFunction{}
 				if name, ok := functionMap["name"].(string); ok {
@@ -384,6 +508,10 @@ type AnthropicStreamParser struct {
 	currentToolCallID   string
 	currentToolName     string
 	currentContentIndex int
+
+	// Server tool use being streamed; its input is reported once complete
+	serverToolUse   *types.ServerToolUse
+	serverToolInput strings.Builder
 }
 
 // NewAnthropicStreamParser creates a new Anthropic stream parser
@@ -391,20 +519,6 @@ func NewAnthropicStreamParser() *AnthropicStreamParser {
 	return &AnthropicStreamParser{}
 }
 
-// mapAnthropicStopReason maps Anthropic stop reasons to OpenAI finish reasons
-func mapAnthropicStopReason(stopReason string) string {
-	switch stopReason {
-	case "tool_use":
-		return "tool_calls"
-	case "end_turn":
-		return "stop"
-	case "max_tokens":
-		return "length"
-	default:
-		return stopReason
-	}
-}
-
 // parseAnthropicUsage extracts usage information from an Anthropic stream response
 func parseAnthropicUsage(streamResp map[string]interface{}) types.Usage {
 	usage := types.Usage{}
@@ -415,11 +529,31 @@ func parseAnthropicUsage(streamResp map[string]interface{}) types.Usage {
 		if outputTokens, ok := usageMap["output_tokens"].(float64); ok {
 			usage.CompletionTokens = int(outputTokens)
 		}
+		// Cached prompt tokens are counted apart from input_tokens
+		if cacheRead, ok := usageMap["cache_read_input_tokens"].(float64); ok {
+			usage.CacheReadTokens = int(cacheRead)
+		}
+		if cacheCreation, ok := usageMap["cache_creation_input_tokens"].(float64); ok {
+			usage.CacheCreationTokens = int(cacheCreation)
+		}
+		usage.PromptTokens += usage.CacheReadTokens + usage.CacheCreationTokens
 		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
 	}
 	return usage
 }
 
+// anthropicCitation converts a citation of an Anthropic citations_delta. Web search
+// results carry a URL and title, document citations a document title.
+func anthropicCitation(citation map[string]interface{}) types.Citation {
+	title, _ := citation["title"].(string)
+	if title == "" {
+		title, _ = citation["document_title"].(string)
+	}
+	url, _ := citation["url"].(string)
+	citedText, _ := citation["cited_text"].(string)
+	return types.Citation{Title: title, URL: url, Snippet: citedText}
+}
+
 // ParseLine parses a line from an Anthropic stream
 func (p *AnthropicStreamParser) ParseLine(data string) (types.ChatCompletionChunk, bool, error) {
 	var streamResp map[string]interface{}
@@ -436,6 +570,25 @@ func (p *AnthropicStreamParser) ParseLine(data string) (types.ChatCompletionChun
 		if contentBlock, ok := streamResp["content_block"].(map[string]interface{}); ok {
 			blockType, _ := contentBlock["type"].(string)
 
+			if blockType == "server_tool_use" {
+				// Executed by Anthropic; reported in full at content_block_stop
+				toolID, _ := contentBlock["id"].(string)
+				toolName, _ := contentBlock["name"].(string)
+				p.serverToolUse = &types.ServerToolUse{ID: toolID, Name: toolName}
+				p.serverToolInput.Reset()
+				return types.ChatCompletionChunk{}, false, nil
+			}
+
+			if blockType != "tool_result" && strings.HasSuffix(blockType, "_tool_result") {
+				// Result of a server tool use, e.g. web_search_tool_result
+				toolUseID, _ := contentBlock["tool_use_id"].(string)
+				return types.ChatCompletionChunk{
+					ServerToolUses: []types.ServerToolUse{
+						{ID: toolUseID, ResultType: blockType, Result: contentBlock["content"]},
+					},
+				}, false, nil
+			}
+
 			if blockType == "tool_use" {
 				// Extract tool call metadata
 				toolID, _ := contentBlock["id"].(string)
@@ -488,9 +641,22 @@ func (p *AnthropicStreamParser) ParseLine(data string) (types.ChatCompletionChun
 					}, false, nil
 				}
 
+			case "citations_delta":
+				// A source for the text block being streamed
+				if citation, ok := delta["citation"].(map[string]interface{}); ok {
+					return types.ChatCompletionChunk{
+						Citations: []types.Citation{anthropicCitation(citation)},
+						Done:      false,
+					}, false, nil
+				}
+
 			case "input_json_delta":
 				// Extract tool call arguments
 				if partialJSON, ok := delta["partial_json"].(string); ok {
+					if p.serverToolUse != nil {
+						p.serverToolInput.WriteString(partialJSON)
+						return types.ChatCompletionChunk{}, false, nil
+					}
 					return types.ChatCompletionChunk{
 						Choices: []types.ChatChoice{
 							{
@@ -515,18 +681,28 @@ func (p *AnthropicStreamParser) ParseLine(data string) (types.ChatCompletionChun
 			}
 		}
 
+	case "content_block_stop":
+		if p.serverToolUse != nil {
+			use := *p.serverToolUse
+			p.serverToolUse = nil
+			if p.serverToolInput.Len() > 0 {
+				_ = json.Unmarshal([]byte(p.serverToolInput.String()), &use.Input)
+			}
+			return types.ChatCompletionChunk{
+				ServerToolUses: []types.ServerToolUse{use},
+			}, false, nil
+		}
+
 	case "message_delta":
 		// Handle message-level deltas (e.g., stop_reason)
 		if delta, ok := streamResp["delta"].(map[string]interface{}); ok {
 			if stopReason, ok := delta["stop_reason"].(string); ok {
-				// Map Anthropic stop reasons to OpenAI finish reasons
-				finishReason := mapAnthropicStopReason(stopReason)
-
 				return types.ChatCompletionChunk{
 					Choices: []types.ChatChoice{
 						{
-							Index:        0,
-							FinishReason: finishReason,
+							Index:           0,
+							FinishReason:    types.NormalizeFinishReason(types.ProviderTypeAnthropic, stopReason),
+							RawFinishReason: stopReason,
 						},
 					},
 					Done: false,
@@ -601,13 +777,16 @@ type ContextAwareStream struct {
 	ctx        context.Context
 }
 
-// Next returns the next chunk, respecting context cancellation
+// Next returns the next chunk, respecting context cancellation. A stream aborted by
+// the context or the connection returns a *types.StreamCancelledError, see
+// types.StreamCancelError.
 func (cas *ContextAwareStream) Next() (types.ChatCompletionChunk, error) {
 	select {
 	case <-cas.ctx.Done():
-		return types.ChatCompletionChunk{Done: true}, cas.ctx.Err()
+		return types.ChatCompletionChunk{Done: true}, types.StreamCancelError(cas.ctx, cas.ctx.Err())
 	default:
-		return cas.baseStream.Next()
+		chunk, err := cas.baseStream.Next()
+		return chunk, types.StreamCancelError(cas.ctx, err)
 	}
 }
 
@@ -625,6 +804,15 @@ func CreateOpenAIStream(response *http.Response) types.ChatCompletionStream {
 	return NewBaseStream(processor, parser)
 }
 
+// CreateOpenAIUsageStream creates a stream for OpenAI-compatible responses to requests
+// with stream_options.include_usage, whose final chunk carries the usage
+func CreateOpenAIUsageStream(response *http.Response) types.ChatCompletionStream {
+	processor := NewStreamProcessor(response)
+	parser := NewStandardStreamParser()
+	parser.AwaitUsage = true
+	return NewBaseStream(processor, parser)
+}
+
 // CreateAnthropicStream creates a stream for Anthropic responses
 func CreateAnthropicStream(response *http.Response) types.ChatCompletionStream {
 	processor := NewStreamProcessor(response)
@@ -3,6 +3,7 @@ package streaming
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -583,8 +584,29 @@ func TestContextAwareStream(t *testing.T) {
 
 	stream = StreamFromContext(ctx, baseStream)
 	chunk, err = stream.Next()
-	if err != context.Canceled {
-		t.Errorf("expected context.Canceled error, got %v", err)
+	var cancelled *types.StreamCancelledError
+	if !errors.As(err, &cancelled) || cancelled.Reason != types.StreamCancelledByUser {
+		t.Errorf("expected a StreamCancelledError stopped by user, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to match context.Canceled, got %v", err)
+	}
+}
+
+func TestContextAwareStream_ServerClosed(t *testing.T) {
+	stream := StreamFromContext(context.Background(), CreateErrorStream(io.ErrUnexpectedEOF))
+
+	_, err := stream.Next()
+	var cancelled *types.StreamCancelledError
+	if !errors.As(err, &cancelled) || cancelled.Reason != types.StreamCancelledByServer {
+		t.Errorf("expected a StreamCancelledError closed by server, got %v", err)
+	}
+
+	// Other errors are returned unchanged
+	parseErr := errors.New("bad chunk")
+	_, err = StreamFromContext(context.Background(), CreateErrorStream(parseErr)).Next()
+	if err != parseErr {
+		t.Errorf("expected the stream's error unchanged, got %v", err)
 	}
 }
 
@@ -627,6 +649,144 @@ func TestCreateOpenAIStream(t *testing.T) {
 	_ = stream.Close()
 }
 
+func TestCreateOpenAIUsageStream(t *testing.T) {
+	// readAll returns the stream's chunks, including the final one returned with io.EOF
+	readAll := func(body string) []types.ChatCompletionChunk {
+		stream := CreateOpenAIUsageStream(&http.Response{Body: io.NopCloser(strings.NewReader(body))})
+		defer func() { _ = stream.Close() }()
+		var chunks []types.ChatCompletionChunk
+		for {
+			chunk, err := stream.Next()
+			if err == nil || chunk.Done {
+				chunks = append(chunks, chunk)
+			}
+			if err != nil {
+				return chunks
+			}
+		}
+	}
+
+	t.Run("ends with the usage chunk", func(t *testing.T) {
+		chunks := readAll(`data: {"choices":[{"delta":{"content":"Hi"}}]}
+
+data: {"choices":[{"delta":{},"finish_reason":"stop"}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}
+
+data: [DONE]
+
+`)
+		if len(chunks) != 3 {
+			t.Fatalf("got %d chunks, want 3", len(chunks))
+		}
+		if chunks[1].Done {
+			t.Error("the finish reason chunk should not end the stream")
+		}
+		final := chunks[2]
+		if !final.Done || final.Usage.TotalTokens != 6 {
+			t.Errorf("final chunk = %+v, want done with 6 total tokens", final)
+		}
+		if len(final.Choices) != 1 || final.Choices[0].FinishReason != "stop" {
+			t.Errorf("final chunk choices = %+v, want finish reason stop", final.Choices)
+		}
+	})
+
+	t.Run("ends with the finish reason without a usage chunk", func(t *testing.T) {
+		chunks := readAll(`data: {"choices":[{"delta":{"content":"Hi"}}]}
+
+data: {"choices":[{"delta":{},"finish_reason":"length"}]}
+
+data: [DONE]
+
+`)
+		final := chunks[len(chunks)-1]
+		if !final.Done || len(final.Choices) != 1 || final.Choices[0].FinishReason != "length" {
+			t.Errorf("final chunk = %+v, want done with finish reason length", final)
+		}
+	})
+
+	t.Run("ends at a finish reason that carries usage", func(t *testing.T) {
+		chunks := readAll(`data: {"choices":[{"delta":{"content":"Hi"},"finish_reason":"stop"}],"usage":{"total_tokens":3}}
+
+data: [DONE]
+
+`)
+		if len(chunks) != 1 || !chunks[0].Done || chunks[0].Usage.TotalTokens != 3 {
+			t.Errorf("chunks = %+v, want one final chunk with usage", chunks)
+		}
+	})
+}
+
+func TestAnthropicStreamParser_ServerToolUse(t *testing.T) {
+	parser := NewAnthropicStreamParser()
+
+	lines := []string{
+		`{"type": "content_block_start", "index": 0, "content_block": {"type": "server_tool_use", "id": "srvtoolu_1", "name": "web_search", "input": {}}}`,
+		`{"type": "content_block_delta", "index": 0, "delta": {"type": "input_json_delta", "partial_json": "{\"query\": "}}`,
+		`{"type": "content_block_delta", "index": 0, "delta": {"type": "input_json_delta", "partial_json": "\"golang\"}"}}`,
+		`{"type": "content_block_stop", "index": 0}`,
+		`{"type": "content_block_start", "index": 1, "content_block": {"type": "web_search_tool_result", "tool_use_id": "srvtoolu_1", "content": [{"type": "web_search_result", "url": "https://go.dev"}]}}`,
+		`{"type": "content_block_stop", "index": 1}`,
+	}
+
+	var uses []types.ServerToolUse
+	for _, line := range lines {
+		chunk, _, err := parser.ParseLine(line)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(chunk.Choices) > 0 {
+			t.Errorf("server tool use must not be reported as a tool call: %+v", chunk.Choices)
+		}
+		uses = append(uses, chunk.ServerToolUses...)
+	}
+
+	if len(uses) != 2 {
+		t.Fatalf("got %d server tool uses, expected 2", len(uses))
+	}
+	if uses[0].ID != "srvtoolu_1" || uses[0].Name != "web_search" || uses[0].Input["query"] != "golang" {
+		t.Errorf("unexpected server tool use %+v", uses[0])
+	}
+	if uses[1].ID != "srvtoolu_1" || uses[1].ResultType != "web_search_tool_result" || uses[1].Result == nil {
+		t.Errorf("unexpected server tool result %+v", uses[1])
+	}
+}
+
+func TestAnthropicStreamParser_CacheUsage(t *testing.T) {
+	parser := NewAnthropicStreamParser()
+
+	chunk, _, err := parser.ParseLine(`{"type": "message_stop", "usage": {"input_tokens": 50, "output_tokens": 20, "cache_read_input_tokens": 750, "cache_creation_input_tokens": 200}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chunk.Usage.PromptTokens != 1000 || chunk.Usage.TotalTokens != 1020 {
+		t.Errorf("expected cached tokens counted in the prompt, got %+v", chunk.Usage)
+	}
+	if chunk.Usage.CacheReadTokens != 750 || chunk.Usage.CacheCreationTokens != 200 {
+		t.Errorf("expected cache read 750 and creation 200, got %+v", chunk.Usage)
+	}
+}
+
+func TestAnthropicStreamParser_Citations(t *testing.T) {
+	parser := NewAnthropicStreamParser()
+
+	chunk, _, err := parser.ParseLine(`{"type": "content_block_delta", "index": 1, "delta": {"type": "citations_delta", "citation": {"type": "web_search_result_location", "url": "https://go.dev", "title": "go.dev", "cited_text": "Go is compiled"}}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunk.Citations) != 1 {
+		t.Fatalf("got %d citations, expected 1", len(chunk.Citations))
+	}
+	if c := chunk.Citations[0]; c.URL != "https://go.dev" || c.Title != "go.dev" || c.Snippet != "Go is compiled" {
+		t.Errorf("unexpected citation %+v", c)
+	}
+
+	chunk, _, _ = parser.ParseLine(`{"type": "content_block_delta", "index": 1, "delta": {"type": "citations_delta", "citation": {"type": "char_location", "document_title": "Handbook", "cited_text": "Be kind"}}}`)
+	if len(chunk.Citations) != 1 || chunk.Citations[0].Title != "Handbook" {
+		t.Errorf("expected the document title, got %+v", chunk.Citations)
+	}
+}
+
 func TestCreateAnthropicStream(t *testing.T) {
 	body := bytes.NewBufferString("data: {\"type\": \"content_block_delta\", \"delta\": {\"text\": \"test\"}}\n")
 	resp := &http.Response{
@@ -805,3 +965,65 @@ func TestJSONLineProcessor(t *testing.T) {
 		t.Errorf("got content %q, expected %q", chunk.Content, "hello")
 	}
 }
+
+func TestStandardStreamParser_LegacyFunctionCall(t *testing.T) {
+	parser := NewStandardStreamParser()
+
+	chunk, _, err := parser.ParseLine(`{"choices": [{"delta": {"role": "assistant", "function_call": {"name": "get_weather", "arguments": ""}}}]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunk.Choices) != 1 || len(chunk.Choices[0].Delta.ToolCalls) != 1 {
+		t.Fatalf("expected one tool call delta, got %+v", chunk.Choices)
+	}
+	first := chunk.Choices[0].Delta.ToolCalls[0]
+	if first.ID == "" || first.Function.Name != "get_weather" || first.Index == nil || *first.Index != 0 {
+		t.Errorf("expected the first delta to carry an ID, the name and index 0, got %+v", first)
+	}
+
+	chunk, _, err = parser.ParseLine(`{"choices": [{"delta": {"function_call": {"arguments": "{\"city\":"}}}]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next := chunk.Choices[0].Delta.ToolCalls[0]
+	if next.ID != "" || next.Function.Arguments != `{"city":` || *next.Index != 0 {
+		t.Errorf("expected an arguments fragment for the same call, got %+v", next)
+	}
+
+	chunk, done, err := parser.ParseLine(`{"choices": [{"delta": {}, "finish_reason": "function_call"}]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done || chunk.Choices[0].FinishReason != types.FinishToolCalls {
+		t.Errorf("expected function_call to finish as tool calls, got %+v", chunk.Choices)
+	}
+}
+
+func TestStreamProcessor_ClosedBeforeDone(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("data: {\"choices\":[{\"delta\":{\"content\":\"partial\"}}]}\n\n"))}
+	stream := CreateOpenAIStream(resp)
+
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error on first chunk: %v", err)
+	}
+	_, err := stream.Next()
+	var cancelled *types.StreamCancelledError
+	if !errors.As(err, &cancelled) || cancelled.Reason != types.StreamCancelledByServer {
+		t.Errorf("expected a StreamCancelledError closed by server, got %v", err)
+	}
+
+	// A stream ended by [DONE] or a finish reason ends with io.EOF
+	for _, body := range []string{
+		"data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n",
+		"data: {\"choices\":[{\"delta\":{\"content\":\"hi\"},\"finish_reason\":\"stop\"}]}\n\n",
+	} {
+		stream := CreateOpenAIStream(&http.Response{Body: io.NopCloser(strings.NewReader(body))})
+		var err error
+		for err == nil {
+			_, err = stream.Next()
+		}
+		if err != io.EOF {
+			t.Errorf("expected io.EOF for %q, got %v", body, err)
+		}
+	}
+}
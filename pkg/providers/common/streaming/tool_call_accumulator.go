@@ -0,0 +1,103 @@
+package streaming
+
+import "github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+
+// ToolCallAccumulator merges streamed tool call deltas into complete tool calls.
+//
+// OpenAI-compatible streams send a tool call's ID and name with its first delta and
+// identify the following argument fragments only by Index, so parallel tool calls are
+// merged by index. Anthropic streams repeat the tool use ID on every delta and carry no
+// index, so they are merged by ID. A delta with neither continues the last tool call.
+type ToolCallAccumulator struct {
+	calls   []*types.ToolCall
+	byID    map[string]*types.ToolCall
+	byIndex map[int]*types.ToolCall
+}
+
+// NewToolCallAccumulator creates an empty tool call accumulator
+func NewToolCallAccumulator() *ToolCallAccumulator {
+	return &ToolCallAccumulator{
+		byID:    make(map[string]*types.ToolCall),
+		byIndex: make(map[int]*types.ToolCall),
+	}
+}
+
+// AddChunk merges the tool call deltas of a chunk's first choice
+func (a *ToolCallAccumulator) AddChunk(chunk types.ChatCompletionChunk) {
+	if len(chunk.Choices) == 0 {
+		return
+	}
+	for _, delta := range chunk.Choices[0].Delta.ToolCalls {
+		a.Add(delta)
+	}
+}
+
+// Add merges a single tool call delta
+func (a *ToolCallAccumulator) Add(delta types.ToolCall) {
+	call := a.find(delta)
+	if call == nil {
+		call = &types.ToolCall{}
+		a.calls = append(a.calls, call)
+	}
+
+	if delta.ID != "" && call.ID == "" {
+		call.ID = delta.ID
+		a.byID[delta.ID] = call
+	}
+	if delta.Index != nil && call.Index == nil {
+		index := *delta.Index
+		call.Index = &index
+		a.byIndex[index] = call
+	}
+	if call.Type == "" {
+		call.Type = delta.Type
+	}
+	// Anthropic repeats the name on every delta, OpenAI sends it once
+	if call.Function.Name == "" {
+		call.Function.Name = delta.Function.Name
+	}
+	call.Function.Arguments += delta.Function.Arguments
+	for k, v := range delta.Metadata {
+		if call.Metadata == nil {
+			call.Metadata = make(map[string]interface{})
+		}
+		call.Metadata[k] = v
+	}
+}
+
+// find returns the tool call a delta continues, or nil if it starts a new one
+func (a *ToolCallAccumulator) find(delta types.ToolCall) *types.ToolCall {
+	if delta.ID != "" {
+		if call, ok := a.byID[delta.ID]; ok {
+			return call
+		}
+	}
+	if delta.Index != nil {
+		call, ok := a.byIndex[*delta.Index]
+		if !ok || (delta.ID != "" && call.ID != "") {
+			return nil
+		}
+		return call
+	}
+	if delta.ID == "" && len(a.calls) > 0 {
+		return a.calls[len(a.calls)-1]
+	}
+	return nil
+}
+
+// ToolCalls returns the accumulated tool calls in the order they started
+func (a *ToolCallAccumulator) ToolCalls() []types.ToolCall {
+	if len(a.calls) == 0 {
+		return nil
+	}
+	result := make([]types.ToolCall, len(a.calls))
+	for i, call := range a.calls {
+		result[i] = *call
+	}
+	return result
+}
+
+// Len returns the number of tool calls accumulated so far
+func (a *ToolCallAccumulator) Len() int {
+	return len(a.calls)
+}
@@ -0,0 +1,226 @@
+package streaming
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// openAIParallelToolCallsFixture is a chat completions stream with two parallel tool
+// calls. Only the first delta of each call carries its ID and name; argument fragments
+// are identified by index and interleave between the calls.
+const openAIParallelToolCallsFixture = `data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"role":"assistant","content":null},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_weather_sf","type":"function","function":{"name":"get_weather","arguments":""}}]},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"loc"}}]},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_weather_ny","type":"function","function":{"name":"get_weather","arguments":""}}]},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":"{\"location\": \"New"}}]},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"ation\": \"San Francisco\"}"}}]},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":" York\"}"}}]},"logprobs":null,"finish_reason":null}]}
+
+data: {"id":"chatcmpl-AB1","object":"chat.completion.chunk","created":1727000000,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{},"logprobs":null,"finish_reason":"tool_calls"}]}
+
+data: [DONE]
+
+`
+
+// anthropicParallelToolCallsFixture is a Messages API stream with text followed by two
+// tool_use blocks. Deltas identify their block by content block index, which the
+// parser resolves to the tool use ID.
+const anthropicParallelToolCallsFixture = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_01A","type":"message","role":"assistant","model":"claude-sonnet-4-5-20250929","content":[],"stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":412,"output_tokens":2}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"I'll check both cities."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_01SF","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"location\": \"San"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":" Francisco\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_01NY","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"location\": "}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"New York\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":2}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":89}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+
+// accumulateToolCalls drains a stream into an accumulator
+func accumulateToolCalls(t *testing.T, stream types.ChatCompletionStream) []types.ToolCall {
+	t.Helper()
+	defer func() { _ = stream.Close() }()
+
+	acc := NewToolCallAccumulator()
+	for {
+		chunk, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		acc.AddChunk(chunk)
+		if chunk.Done {
+			break
+		}
+	}
+	return acc.ToolCalls()
+}
+
+func fixtureResponse(fixture string) *http.Response {
+	return &http.Response{Body: io.NopCloser(bytes.NewBufferString(fixture))}
+}
+
+func assertWeatherToolCalls(t *testing.T, calls []types.ToolCall, sfID, nyID string) {
+	t.Helper()
+	expected := []types.ToolCall{
+		{ID: sfID, Type: "function", Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"location": "San Francisco"}`}},
+		{ID: nyID, Type: "function", Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"location": "New York"}`}},
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected %d tool calls, got %d: %+v", len(expected), len(calls), calls)
+	}
+	for i, want := range expected {
+		got := calls[i]
+		if got.ID != want.ID || got.Type != want.Type || got.Function != want.Function {
+			t.Errorf("tool call %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestToolCallAccumulator_OpenAIParallelToolCalls(t *testing.T) {
+	calls := accumulateToolCalls(t, CreateOpenAIStream(fixtureResponse(openAIParallelToolCallsFixture)))
+
+	assertWeatherToolCalls(t, calls, "call_weather_sf", "call_weather_ny")
+	for i, call := range calls {
+		if call.Index == nil || *call.Index != i {
+			t.Errorf("tool call %d: expected index %d, got %v", i, i, call.Index)
+		}
+	}
+}
+
+func TestToolCallAccumulator_AnthropicParallelToolCalls(t *testing.T) {
+	calls := accumulateToolCalls(t, CreateAnthropicStream(fixtureResponse(anthropicParallelToolCallsFixture)))
+
+	assertWeatherToolCalls(t, calls, "toolu_01SF", "toolu_01NY")
+}
+
+func TestConvertToolCalls_Index(t *testing.T) {
+	calls := convertToolCalls([]interface{}{
+		map[string]interface{}{"index": float64(1), "function": map[string]interface{}{"arguments": "{}"}},
+		map[string]interface{}{"id": "call_1", "function": map[string]interface{}{"name": "f"}},
+	})
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(calls))
+	}
+	if calls[0].Index == nil || *calls[0].Index != 1 {
+		t.Errorf("expected index 1, got %v", calls[0].Index)
+	}
+	if calls[1].Index != nil {
+		t.Errorf("expected no index, got %d", *calls[1].Index)
+	}
+}
+
+func TestToolCallAccumulator_Add(t *testing.T) {
+	index := func(i int) *int { return &i }
+
+	tests := []struct {
+		name     string
+		deltas   []types.ToolCall
+		expected []types.ToolCall
+	}{
+		{
+			name: "deltas without id or index continue the last call",
+			deltas: []types.ToolCall{
+				{ID: "call_1", Type: "function", Function: types.ToolCallFunction{Name: "search", Arguments: `{"q":`}},
+				{Function: types.ToolCallFunction{Arguments: `"go"}`}},
+			},
+			expected: []types.ToolCall{
+				{ID: "call_1", Type: "function", Function: types.ToolCallFunction{Name: "search", Arguments: `{"q":"go"}`}},
+			},
+		},
+		{
+			name: "argument delta before the id is attached when the id arrives",
+			deltas: []types.ToolCall{
+				{Index: index(0), Function: types.ToolCallFunction{Arguments: `{"a":`}},
+				{Index: index(0), ID: "call_1", Function: types.ToolCallFunction{Name: "f", Arguments: `1}`}},
+			},
+			expected: []types.ToolCall{
+				{ID: "call_1", Index: index(0), Function: types.ToolCallFunction{Name: "f", Arguments: `{"a":1}`}},
+			},
+		},
+		{
+			name: "repeated name is not duplicated",
+			deltas: []types.ToolCall{
+				{ID: "toolu_1", Function: types.ToolCallFunction{Name: "f"}},
+				{ID: "toolu_1", Function: types.ToolCallFunction{Name: "f", Arguments: `{}`}},
+			},
+			expected: []types.ToolCall{
+				{ID: "toolu_1", Function: types.ToolCallFunction{Name: "f", Arguments: `{}`}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := NewToolCallAccumulator()
+			for _, delta := range tt.deltas {
+				acc.Add(delta)
+			}
+			calls := acc.ToolCalls()
+			if len(calls) != len(tt.expected) {
+				t.Fatalf("expected %d tool calls, got %d: %+v", len(tt.expected), len(calls), calls)
+			}
+			for i, want := range tt.expected {
+				got := calls[i]
+				if got.ID != want.ID || got.Function != want.Function {
+					t.Errorf("tool call %d: expected %+v, got %+v", i, want, got)
+				}
+				if (want.Index == nil) != (got.Index == nil) || (want.Index != nil && *want.Index != *got.Index) {
+					t.Errorf("tool call %d: expected index %v, got %v", i, want.Index, got.Index)
+				}
+			}
+		})
+	}
+}
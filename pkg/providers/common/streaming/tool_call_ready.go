@@ -0,0 +1,159 @@
+package streaming
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ToolCallReadyFunc is called with a tool call once its name and arguments are complete
+type ToolCallReadyFunc func(call types.ToolCall)
+
+// ToolCallReadyStream passes a stream's chunks through unchanged while accumulating
+// their tool call deltas, and calls its ToolCallReadyFunc as soon as each tool call is
+// complete, so callers can show or start executing a tool while parallel tool calls are
+// still streaming.
+//
+// Arguments are scanned incrementally as they arrive. A tool call is ready early once
+// it has a name and its arguments are a JSON object or array whose closing bracket has
+// arrived and which parses; JSON that is merely valid so far, such as a number that may
+// gain more digits, is never reported early, since no delta can extend a closed object
+// or array into valid JSON. Tool calls not ready by the end of the stream, such as those
+// with empty arguments, are reported when the stream ends: on a done chunk, a finish
+// reason or io.EOF. Each tool call is reported once, in the goroutine calling Next.
+type ToolCallReadyStream struct {
+	stream   types.ChatCompletionStream
+	onReady  ToolCallReadyFunc
+	acc      *ToolCallAccumulator
+	scanners []*jsonCompletionScanner
+	reported []bool
+	finished bool
+}
+
+// NewToolCallReadyStream wraps stream, calling onReady as each tool call completes
+func NewToolCallReadyStream(stream types.ChatCompletionStream, onReady ToolCallReadyFunc) *ToolCallReadyStream {
+	return &ToolCallReadyStream{
+		stream:  stream,
+		onReady: onReady,
+		acc:     NewToolCallAccumulator(),
+	}
+}
+
+// Next implements types.ChatCompletionStream
+func (s *ToolCallReadyStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.stream.Next()
+	if err != nil {
+		if err == io.EOF {
+			s.finish()
+		}
+		return chunk, err
+	}
+
+	s.acc.AddChunk(chunk)
+	s.reportReady()
+	if chunk.Done || (len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != "") {
+		s.finish()
+	}
+	return chunk, nil
+}
+
+// Close implements types.ChatCompletionStream
+func (s *ToolCallReadyStream) Close() error {
+	return s.stream.Close()
+}
+
+// ToolCalls returns the tool calls accumulated so far
+func (s *ToolCallReadyStream) ToolCalls() []types.ToolCall {
+	return s.acc.ToolCalls()
+}
+
+// reportReady scans the arguments that arrived since the last chunk and reports the
+// tool calls they complete
+func (s *ToolCallReadyStream) reportReady() {
+	calls := s.acc.ToolCalls()
+	for len(s.scanners) < len(calls) {
+		s.scanners = append(s.scanners, &jsonCompletionScanner{})
+		s.reported = append(s.reported, false)
+	}
+	for i, call := range calls {
+		if s.reported[i] {
+			continue
+		}
+		if s.scanners[i].scan(call.Function.Arguments) && call.Function.Name != "" {
+			s.report(i, call)
+		}
+	}
+}
+
+// finish reports every tool call not yet reported, once the stream has ended
+func (s *ToolCallReadyStream) finish() {
+	if s.finished {
+		return
+	}
+	s.finished = true
+	s.reportReady()
+	for i, call := range s.acc.ToolCalls() {
+		if !s.reported[i] {
+			s.report(i, call)
+		}
+	}
+}
+
+func (s *ToolCallReadyStream) report(i int, call types.ToolCall) {
+	s.reported[i] = true
+	if s.onReady != nil {
+		s.onReady(call)
+	}
+}
+
+// jsonCompletionScanner tracks the nesting of JSON text as it grows, to detect when its
+// top-level object or array closes without rescanning what it has seen
+type jsonCompletionScanner struct {
+	offset   int
+	started  bool
+	scalar   bool
+	depth    int
+	inString bool
+	escaped  bool
+	closedAt int
+}
+
+// scan scans the part of text not yet seen, which must extend the text of the previous
+// call, and reports whether the text is a complete JSON object or array
+func (sc *jsonCompletionScanner) scan(text string) bool {
+	for ; sc.offset < len(text) && sc.closedAt == 0 && !sc.scalar; sc.offset++ {
+		c := text[sc.offset]
+		switch {
+		case !sc.started:
+			switch c {
+			case ' ', '\t', '\r', '\n':
+			case '{', '[':
+				sc.started = true
+				sc.depth = 1
+			default:
+				// Scalars cannot be told complete until the stream ends
+				sc.scalar = true
+			}
+		case sc.inString:
+			switch {
+			case sc.escaped:
+				sc.escaped = false
+			case c == '\\':
+				sc.escaped = true
+			case c == '"':
+				sc.inString = false
+			}
+		case c == '"':
+			sc.inString = true
+		case c == '{' || c == '[':
+			sc.depth++
+		case c == '}' || c == ']':
+			sc.depth--
+			if sc.depth == 0 {
+				sc.closedAt = sc.offset + 1
+			}
+		}
+	}
+	return sc.closedAt > 0 && json.Valid([]byte(text[:sc.closedAt]))
+}
@@ -0,0 +1,149 @@
+package streaming
+
+import (
+	"io"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// readyEvent records a reported tool call and how many chunks had been read
+type readyEvent struct {
+	call   types.ToolCall
+	chunks int
+}
+
+// drainReadyStream reads a ToolCallReadyStream to the end, recording its reports
+func drainReadyStream(t *testing.T, stream types.ChatCompletionStream) []readyEvent {
+	t.Helper()
+
+	var events []readyEvent
+	chunks := 0
+	ready := NewToolCallReadyStream(stream, func(call types.ToolCall) {
+		events = append(events, readyEvent{call: call, chunks: chunks})
+	})
+	defer func() { _ = ready.Close() }()
+
+	for {
+		_, err := ready.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		chunks++
+	}
+	return events
+}
+
+func TestToolCallReadyStream_OpenAIParallelToolCalls(t *testing.T) {
+	events := drainReadyStream(t, CreateOpenAIStream(fixtureResponse(openAIParallelToolCallsFixture)))
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 ready tool calls, got %d", len(events))
+	}
+	assertWeatherToolCalls(t, []types.ToolCall{events[0].call, events[1].call}, "call_weather_sf", "call_weather_ny")
+	// San Francisco closes one chunk before New York's last argument delta
+	if events[1].chunks-events[0].chunks != 1 {
+		t.Errorf("expected the first call ready one chunk before the second, got after %d and %d chunks", events[0].chunks, events[1].chunks)
+	}
+}
+
+func TestToolCallReadyStream_AnthropicParallelToolCalls(t *testing.T) {
+	events := drainReadyStream(t, CreateAnthropicStream(fixtureResponse(anthropicParallelToolCallsFixture)))
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 ready tool calls, got %d", len(events))
+	}
+	assertWeatherToolCalls(t, []types.ToolCall{events[0].call, events[1].call}, "toolu_01SF", "toolu_01NY")
+	if events[0].chunks >= events[1].chunks {
+		t.Errorf("expected the first call ready before the second, got after %d and %d chunks", events[0].chunks, events[1].chunks)
+	}
+}
+
+func TestToolCallReadyStream_ReportsIncompleteCallsAtEnd(t *testing.T) {
+	index := func(i int) *int { return &i }
+	toolCallChunk := func(delta types.ToolCall) types.ChatCompletionChunk {
+		return types.ChatCompletionChunk{Choices: []types.ChatChoice{{Delta: types.ChatMessage{ToolCalls: []types.ToolCall{delta}}}}}
+	}
+
+	var reported []string
+	stream := NewToolCallReadyStream(NewMockStream([]types.ChatCompletionChunk{
+		toolCallChunk(types.ToolCall{Index: index(0), ID: "call_1", Function: types.ToolCallFunction{Name: "now"}}),
+		toolCallChunk(types.ToolCall{Index: index(1), ID: "call_2", Function: types.ToolCallFunction{Name: "count", Arguments: "12"}}),
+		toolCallChunk(types.ToolCall{Index: index(1), Function: types.ToolCallFunction{Arguments: "3"}}),
+		{Choices: []types.ChatChoice{{FinishReason: types.FinishToolCalls}}},
+	}), func(call types.ToolCall) {
+		reported = append(reported, call.Function.Name+"("+call.Function.Arguments+")")
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := stream.Next(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reported) != 0 {
+			t.Fatalf("expected no ready calls before the stream ends, got %v", reported)
+		}
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reported) != 2 || reported[0] != "now()" || reported[1] != "count(123)" {
+		t.Errorf("expected [now() count(123)] at the finish reason, got %v", reported)
+	}
+
+	if _, err := stream.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if len(reported) != 2 {
+		t.Errorf("expected each call reported once, got %v", reported)
+	}
+}
+
+func TestJSONCompletionScanner(t *testing.T) {
+	tests := []struct {
+		name      string
+		fragments []string
+		complete  []bool
+	}{
+		{
+			name:      "object completes when it closes",
+			fragments: []string{`{"a":`, `1`, `}`},
+			complete:  []bool{false, false, true},
+		},
+		{
+			name:      "brackets in strings are ignored",
+			fragments: []string{`{"q":"}`, `{\"]`, `"}`},
+			complete:  []bool{false, false, true},
+		},
+		{
+			name:      "nested values complete with the outer object",
+			fragments: []string{` {"a":{"b":[1,`, `2]}`, `}`},
+			complete:  []bool{false, false, true},
+		},
+		{
+			name:      "scalars are never complete early",
+			fragments: []string{`1`, `23`},
+			complete:  []bool{false, false},
+		},
+		{
+			name:      "invalid JSON is not complete",
+			fragments: []string{`{"a" 1}`},
+			complete:  []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := &jsonCompletionScanner{}
+			text := ""
+			for i, fragment := range tt.fragments {
+				text += fragment
+				if got := scanner.scan(text); got != tt.complete[i] {
+					t.Errorf("after %q: expected complete=%v, got %v", text, tt.complete[i], got)
+				}
+			}
+		})
+	}
+}
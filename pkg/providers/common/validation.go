@@ -0,0 +1,43 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ValidateGenerateOptions performs pre-flight validation of a chat completion request
+// so invalid requests fail with a *types.ValidationError before any network I/O.
+// It requires a model and applies types.GenerateOptions.Validate, which mirrors the
+// rules of types.CoreRequestBuilder, so the legacy and standardized APIs report the
+// same errors. model is the resolved model name, after config and provider defaults
+// have been applied.
+func ValidateGenerateOptions(model string, options types.GenerateOptions) error {
+	if model == "" {
+		return types.ErrNoModel
+	}
+	return options.Validate()
+}
+
+// ValidateSingleChoice rejects a request for more than one completion (GenerateOptions.N
+// greater than 1) with a *types.ValidationError, for providers whose APIs return a
+// single choice, so the request does not silently yield fewer choices than asked for
+func ValidateSingleChoice(provider types.ProviderType, options types.GenerateOptions) error {
+	if options.N > 1 {
+		return types.NewValidationError(fmt.Sprintf("provider %s does not support n > 1 (requested %d choices)", provider, options.N))
+	}
+	return nil
+}
+
+// SingleChoiceParameters returns the types.ParameterSupport of providers whose APIs
+// return a single choice and take none of OpenAI's parallel_tool_calls, store and
+// reasoning_effort, with tools as types.ProviderCapabilities reports for model
+func SingleChoiceParameters(provider types.Provider, model string) types.ParameterSupport {
+	support := types.AllParametersSupported
+	support.Tools = types.ProviderCapabilities(provider, model).Tools
+	support.N = false
+	support.ParallelToolCalls = false
+	support.ReasoningEffort = false
+	support.Store = false
+	return support
+}
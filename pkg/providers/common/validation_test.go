@@ -0,0 +1,128 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestValidateGenerateOptions(t *testing.T) {
+	tools := []types.Tool{{Name: "get_weather"}}
+	messages := []types.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	tests := []struct {
+		name    string
+		model   string
+		options types.GenerateOptions
+		wantErr string
+	}{
+		{
+			name:    "valid messages",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{Messages: messages, Temperature: 0.7, MaxTokens: 100},
+		},
+		{
+			name:    "valid prompt",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{Prompt: "Hello"},
+		},
+		{
+			name:    "missing model",
+			options: types.GenerateOptions{Messages: messages},
+			wantErr: "model is required",
+		},
+		{
+			name:    "no messages or prompt",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{},
+			wantErr: "at least one message is required",
+		},
+		{
+			name:    "temperature too high",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{Messages: messages, Temperature: 2.5},
+			wantErr: "temperature must be between 0 and 2",
+		},
+		{
+			name:    "negative max tokens",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{Messages: messages, MaxTokens: -1},
+			wantErr: "max_tokens must be non-negative",
+		},
+		{
+			name:    "negative n",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{Messages: messages, N: -1},
+			wantErr: "n must be non-negative",
+		},
+		{
+			name:    "tool choice without tools",
+			model:   "gpt-4o",
+			options: types.GenerateOptions{Messages: messages, ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceAuto}},
+			wantErr: "tool_choice specified but no tools provided",
+		},
+		{
+			name:  "specific tool choice",
+			model: "gpt-4o",
+			options: types.GenerateOptions{
+				Messages:   messages,
+				Tools:      tools,
+				ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceSpecific, FunctionName: "get_weather"},
+			},
+		},
+		{
+			name:  "specific tool choice without function name",
+			model: "gpt-4o",
+			options: types.GenerateOptions{
+				Messages:   messages,
+				Tools:      tools,
+				ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceSpecific},
+			},
+			wantErr: "requires a function name",
+		},
+		{
+			name:  "specific tool choice with unknown function",
+			model: "gpt-4o",
+			options: types.GenerateOptions{
+				Messages:   messages,
+				Tools:      tools,
+				ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceSpecific, FunctionName: "get_time"},
+			},
+			wantErr: `"get_time" is not among the provided tools`,
+		},
+		{
+			name:  "invalid tool choice mode",
+			model: "gpt-4o",
+			options: types.GenerateOptions{
+				Messages:   messages,
+				Tools:      tools,
+				ToolChoice: &types.ToolChoice{Mode: "sometimes"},
+			},
+			wantErr: "invalid tool_choice mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGenerateOptions(tt.model, tt.options)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.True(t, types.IsValidationError(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateSingleChoice(t *testing.T) {
+	assert.NoError(t, ValidateSingleChoice(types.ProviderTypeAnthropic, types.GenerateOptions{}))
+	assert.NoError(t, ValidateSingleChoice(types.ProviderTypeAnthropic, types.GenerateOptions{N: 1}))
+
+	err := ValidateSingleChoice(types.ProviderTypeAnthropic, types.GenerateOptions{N: 2})
+	assert.True(t, types.IsValidationError(err))
+	assert.Contains(t, err.Error(), "anthropic does not support n > 1")
+}
@@ -0,0 +1,102 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// warmupPrompt is the prompt of the optional warm-up completion
+const warmupPrompt = "Hi"
+
+// WarmupOptions controls what Warmup does beyond authentication and model loading
+type WarmupOptions struct {
+	// Completion sends a one-token completion to the default model, which also
+	// warms connection pools and proxies. It costs a few tokens per provider.
+	Completion bool
+}
+
+// oauthRefresher is implemented by providers that can refresh all their OAuth tokens
+type oauthRefresher interface {
+	RefreshAllOAuthTokens(ctx context.Context) error
+}
+
+// Warmup prepares a provider for its first request so that request does not pay for
+// cold-start work:
+//   - OAuth tokens are refreshed, for providers with OAuth configured
+//   - the model list is fetched, which populates the provider's model cache
+//   - with options.Completion, a one-token completion is sent to the default model
+//
+// Providers implement types.WarmableProvider by calling Warmup with default options.
+func Warmup(ctx context.Context, provider types.Provider, options WarmupOptions) error {
+	if refresher, ok := provider.(oauthRefresher); ok && oauthConfigured(provider) {
+		if err := refresher.RefreshAllOAuthTokens(ctx); err != nil {
+			return fmt.Errorf("warmup %s: refresh OAuth tokens: %w", provider.Name(), err)
+		}
+	}
+
+	if _, err := provider.GetModels(ctx); err != nil {
+		return fmt.Errorf("warmup %s: load models: %w", provider.Name(), err)
+	}
+
+	if options.Completion {
+		stream, err := provider.GenerateChatCompletion(ctx, types.GenerateOptions{
+			Model:     provider.GetDefaultModel(),
+			Messages:  []types.ChatMessage{{Role: "user", Content: warmupPrompt}},
+			MaxTokens: 1,
+		})
+		if err != nil {
+			return fmt.Errorf("warmup %s: completion: %w", provider.Name(), err)
+		}
+		if err := types.StreamToCallback(stream, func(types.ChatCompletionChunk) error { return nil }); err != nil {
+			return fmt.Errorf("warmup %s: completion: %w", provider.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// WarmupAll warms providers concurrently and returns the errors keyed by provider
+// name; the map is empty when every provider is ready. Providers implementing
+// types.WarmableProvider use their own Warmup unless options.Completion is set.
+func WarmupAll(ctx context.Context, providers []types.Provider, options WarmupOptions) map[string]error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+	)
+
+	for _, provider := range providers {
+		wg.Add(1)
+		go func(provider types.Provider) {
+			defer wg.Done()
+
+			var err error
+			if warmable, ok := provider.(types.WarmableProvider); ok && !options.Completion {
+				err = warmable.Warmup(ctx)
+			} else {
+				err = Warmup(ctx, provider, options)
+			}
+
+			if err != nil {
+				mu.Lock()
+				errs[provider.Name()] = err
+				mu.Unlock()
+			}
+		}(provider)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// oauthConfigured reports whether a provider has OAuth credentials; providers that do
+// not say are assumed to have them
+func oauthConfigured(provider types.Provider) bool {
+	if detector, ok := provider.(types.AuthMethodDetector); ok {
+		return detector.IsOAuthConfigured()
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// warmupProvider records the calls made by Warmup; unused Provider methods panic
+type warmupProvider struct {
+	types.Provider
+	name          string
+	oauth         bool
+	modelsErr     error
+	refreshes     atomic.Int32
+	modelLoads    atomic.Int32
+	completions   atomic.Int32
+	lastMaxTokens atomic.Int32
+}
+
+func (p *warmupProvider) Name() string            { return p.name }
+func (p *warmupProvider) GetDefaultModel() string { return "default-model" }
+func (p *warmupProvider) IsOAuthConfigured() bool { return p.oauth }
+func (p *warmupProvider) IsAPIKeyConfigured() bool {
+	return !p.oauth
+}
+
+func (p *warmupProvider) RefreshAllOAuthTokens(ctx context.Context) error {
+	p.refreshes.Add(1)
+	return nil
+}
+
+func (p *warmupProvider) GetModels(ctx context.Context) ([]types.Model, error) {
+	p.modelLoads.Add(1)
+	return nil, p.modelsErr
+}
+
+func (p *warmupProvider) GenerateChatCompletion(ctx context.Context, options types.GenerateOptions) (types.ChatCompletionStream, error) {
+	p.completions.Add(1)
+	p.lastMaxTokens.Store(int32(options.MaxTokens))
+	return streaming.NewMockStream([]types.ChatCompletionChunk{{Content: "H", Done: true}}), nil
+}
+
+func TestWarmup(t *testing.T) {
+	t.Run("loads models without a completion", func(t *testing.T) {
+		provider := &warmupProvider{name: "p"}
+		require.NoError(t, Warmup(context.Background(), provider, WarmupOptions{}))
+
+		assert.Equal(t, int32(1), provider.modelLoads.Load())
+		assert.Equal(t, int32(0), provider.refreshes.Load(), "no OAuth configured")
+		assert.Equal(t, int32(0), provider.completions.Load())
+	})
+
+	t.Run("refreshes OAuth tokens", func(t *testing.T) {
+		provider := &warmupProvider{name: "p", oauth: true}
+		require.NoError(t, Warmup(context.Background(), provider, WarmupOptions{}))
+		assert.Equal(t, int32(1), provider.refreshes.Load())
+	})
+
+	t.Run("sends a one-token completion", func(t *testing.T) {
+		provider := &warmupProvider{name: "p"}
+		require.NoError(t, Warmup(context.Background(), provider, WarmupOptions{Completion: true}))
+		assert.Equal(t, int32(1), provider.completions.Load())
+		assert.Equal(t, int32(1), provider.lastMaxTokens.Load())
+	})
+
+	t.Run("reports model errors", func(t *testing.T) {
+		provider := &warmupProvider{name: "p", modelsErr: errors.New("unauthorized")}
+		err := Warmup(context.Background(), provider, WarmupOptions{Completion: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "warmup p: load models")
+		assert.Equal(t, int32(0), provider.completions.Load())
+	})
+}
+
+func TestWarmupAll(t *testing.T) {
+	ok := &warmupProvider{name: "ok"}
+	broken := &warmupProvider{name: "broken", modelsErr: errors.New("unreachable")}
+
+	errs := WarmupAll(context.Background(), []types.Provider{ok, broken}, WarmupOptions{})
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs["broken"], "unreachable")
+	assert.Equal(t, int32(1), ok.modelLoads.Load())
+}
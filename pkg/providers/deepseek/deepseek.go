@@ -0,0 +1,89 @@
+// Package deepseek provides integration with DeepSeek's models. The DeepSeek API is
+// OpenAI-compatible, so the provider is the OpenAI provider with DeepSeek's defaults.
+// Its reasoning models stream their thinking in a reasoning_content field ahead of the
+// answer, which the provider reports in the ReasoningContent field of chunks and
+// messages, apart from the answer's Content.
+package deepseek
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/openai"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// DeepSeek models
+const (
+	ModelChat     = "deepseek-chat"     // General-purpose model, the default
+	ModelReasoner = "deepseek-reasoner" // Reasoning model, reports its reasoning_content
+)
+
+// DeepSeekProvider implements the Provider interface for DeepSeek
+type DeepSeekProvider struct {
+	*openai.OpenAIProvider
+}
+
+// NewDeepSeekProvider creates a new DeepSeek provider. The base URL defaults to
+// https://api.deepseek.com and the model to deepseek-chat.
+func NewDeepSeekProvider(config types.ProviderConfig) *DeepSeekProvider {
+	return &DeepSeekProvider{
+		OpenAIProvider: openai.NewCompatibleProvider(config, openai.CompatibleAPI{
+			Name:              "DeepSeek",
+			Type:              types.ProviderTypeDeepseek,
+			Description:       "DeepSeek - chat and reasoning models with OpenAI-compatible API access",
+			DefaultModel:      ModelChat,
+			PrepareRequest:    prepareRequest,
+			SeparateReasoning: true,
+		}),
+	}
+}
+
+// SupportedParameters implements types.ParameterSupportReporter. No DeepSeek model
+// takes reasoning_effort or store, and deepseek-reasoner ignores temperature, top_p and the
+// penalties.
+func (p *DeepSeekProvider) SupportedParameters(model string) types.ParameterSupport {
+	if model == "" {
+		model = p.GetDefaultModel()
+	}
+	model = normalizeModel(model)
+	support := types.AllParametersSupported
+	support.Tools = types.ProviderCapabilities(p, model).Tools
+	support.ReasoningEffort = false
+	support.Store = false
+
+	if model == ModelReasoner {
+		support.Temperature = false
+		support.TopP = false
+		support.FrequencyPenalty = false
+		support.PresencePenalty = false
+	}
+	return support
+}
+
+// prepareRequest adjusts an OpenAI request for the DeepSeek API: model names may carry
+// a "deepseek/" prefix, as in router catalogs, and reasoning_effort is not accepted, as
+// deepseek-reasoner always reasons and deepseek-chat never does.
+func prepareRequest(request *openai.OpenAIRequest, _ types.GenerateOptions) ([]types.Warning, error) {
+	request.Model = normalizeModel(request.Model)
+
+	var warnings []types.Warning
+	if request.ReasoningEffort != "" {
+		warnings = append(warnings, types.Warning{
+			Code:    types.WarningParameterIgnored,
+			Message: fmt.Sprintf("model %s does not accept reasoning_effort, it was not sent", request.Model),
+			Param:   "reasoning_effort",
+		})
+		request.ReasoningEffort = ""
+	}
+	return warnings, nil
+}
+
+// normalizeModel strips a router-style provider prefix from a DeepSeek model name
+func normalizeModel(model string) string {
+	const prefix = "deepseek/"
+	if strings.HasPrefix(strings.ToLower(model), prefix) {
+		return model[len(prefix):]
+	}
+	return model
+}
@@ -0,0 +1,152 @@
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeepSeekProvider_Defaults(t *testing.T) {
+	provider := NewDeepSeekProvider(types.ProviderConfig{Type: types.ProviderTypeDeepseek, APIKey: "ds-key"})
+
+	assert.Equal(t, "DeepSeek", provider.Name())
+	assert.Equal(t, types.ProviderTypeDeepseek, provider.Type())
+	assert.Equal(t, ModelChat, provider.GetDefaultModel())
+	assert.Equal(t, "https://api.deepseek.com", provider.GetConfig().BaseURL)
+	assert.True(t, provider.SupportsToolCalling())
+	assert.False(t, provider.SupportsResponsesAPI())
+}
+
+func TestDeepSeekProvider_ReasoningContent(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer ds-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     "chatcmpl-ds",
+			"object": "chat.completion",
+			"model":  ModelReasoner,
+			"choices": []interface{}{map[string]interface{}{
+				"index": 0,
+				"message": map[string]interface{}{
+					"role":              "assistant",
+					"reasoning_content": "9.11 has fewer tenths than 9.9.",
+					"content":           "9.9 is larger.",
+				},
+				"finish_reason": "stop",
+			}},
+			"usage": map[string]interface{}{"prompt_tokens": 10, "completion_tokens": 20, "total_tokens": 30},
+		})
+	}))
+	defer server.Close()
+	provider := NewDeepSeekProvider(types.ProviderConfig{Type: types.ProviderTypeDeepseek, APIKey: "ds-key", BaseURL: server.URL})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt:          "Which is larger, 9.11 or 9.9?",
+		Model:           "deepseek/deepseek-reasoner",
+		ReasoningEffort: types.ReasoningEffortHigh,
+	})
+	require.NoError(t, err)
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+
+	assert.Equal(t, "9.9 is larger.", chunk.Content)
+	assert.Equal(t, "9.11 has fewer tenths than 9.9.", chunk.ReasoningContent)
+	require.Len(t, chunk.Choices, 1)
+	assert.Equal(t, "9.9 is larger.", chunk.Choices[0].Message.Content)
+	assert.Equal(t, "9.11 has fewer tenths than 9.9.", chunk.Choices[0].Message.ReasoningContent)
+	require.Len(t, chunk.Warnings, 1)
+	assert.Equal(t, "reasoning_effort", chunk.Warnings[0].Param)
+
+	assert.Equal(t, ModelReasoner, body["model"])
+	assert.NotContains(t, body, "reasoning_effort")
+}
+
+func TestDeepSeekProvider_StreamingReasoningContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{
+			`{"role":"assistant","content":null,"reasoning_content":""}`,
+			`{"content":null,"reasoning_content":"Compare tenths."}`,
+			`{"content":null,"reasoning_content":" 9 > 1."}`,
+			`{"content":"9.9","reasoning_content":null}`,
+		} {
+			_, _ = fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":%s,\"finish_reason\":null}]}\n\n", delta)
+		}
+		_, _ = io.WriteString(w, "data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		_, _ = io.WriteString(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":7,\"total_tokens\":12}}\n\n")
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+	provider := NewDeepSeekProvider(types.ProviderConfig{Type: types.ProviderTypeDeepseek, APIKey: "ds-key", BaseURL: server.URL})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "Which is larger, 9.11 or 9.9?",
+		Model:  ModelReasoner,
+		Stream: true,
+	})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	var content, reasoning string
+	var usage types.Usage
+	for {
+		chunk, err := stream.Next()
+		content += chunk.Content
+		reasoning += chunk.ReasoningContent
+		if chunk.Usage.TotalTokens > 0 {
+			usage = chunk.Usage
+		}
+		if err == io.EOF || chunk.Done {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "9.9", content)
+	assert.Equal(t, "Compare tenths. 9 > 1.", reasoning)
+	assert.Equal(t, 12, usage.TotalTokens)
+}
+
+func TestNormalizeModel(t *testing.T) {
+	assert.Equal(t, ModelChat, normalizeModel("deepseek/deepseek-chat"))
+	assert.Equal(t, ModelChat, normalizeModel(ModelChat))
+}
+
+func TestDeepSeekProvider_SupportedParameters(t *testing.T) {
+	provider := NewDeepSeekProvider(types.ProviderConfig{Type: types.ProviderTypeDeepseek, APIKey: "ds-key"})
+
+	reasoner := provider.SupportedParameters(ModelReasoner)
+	assert.False(t, reasoner.Temperature)
+	assert.False(t, reasoner.ReasoningEffort)
+	assert.True(t, reasoner.Tools)
+
+	chat := provider.SupportedParameters("")
+	assert.True(t, chat.Temperature)
+	assert.False(t, chat.ReasoningEffort)
+}
+
+func TestDeepSeekProvider_ErrorsNameDeepSeek(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad messages","type":"invalid_request_error"}}`))
+	}))
+	t.Cleanup(server.Close)
+	provider := NewDeepSeekProvider(types.ProviderConfig{Type: types.ProviderTypeDeepseek, APIKey: "ds-key", BaseURL: server.URL})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "Hello"})
+	var providerErr *types.ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, types.ProviderTypeDeepseek, providerErr.Provider)
+	assert.Equal(t, "invalid DeepSeek request: bad messages", providerErr.Message)
+}
@@ -0,0 +1,81 @@
+package gemini
+
+import (
+	"io"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestConvertGeminiCitations(t *testing.T) {
+	candidate := Candidate{
+		GroundingMetadata: &GroundingMetadata{
+			GroundingChunks: []GroundingChunk{
+				{Web: &GroundingSource{URI: "https://go.dev", Title: "go.dev"}},
+				{Web: &GroundingSource{URI: "https://pkg.go.dev", Title: "pkg.go.dev"}},
+			},
+			GroundingSupports: []GroundingSupport{{
+				Segment:               GroundingSegment{StartIndex: 0, EndIndex: 15, Text: "Go is compiled."},
+				GroundingChunkIndices: []int{0, 5},
+			}},
+		},
+		CitationMetadata: &CitationMetadata{
+			CitationSources: []CitationSource{{URI: "https://example.com", StartIndex: 3, EndIndex: 9}},
+		},
+	}
+
+	citations := convertGeminiCitations(candidate)
+	if len(citations) != 3 {
+		t.Fatalf("expected 3 citations, got %+v", citations)
+	}
+	if c := citations[0]; c.URL != "https://go.dev" || c.Text != "Go is compiled." || c.EndIndex != 15 {
+		t.Errorf("unexpected supported citation %+v", c)
+	}
+	if c := citations[1]; c.URL != "https://pkg.go.dev" || c.Text != "" || c.EndIndex != 0 {
+		t.Errorf("expected the unsupported chunk without a location, got %+v", c)
+	}
+	if c := citations[2]; c.URL != "https://example.com" || c.StartIndex != 3 || c.EndIndex != 9 {
+		t.Errorf("unexpected recitation source %+v", c)
+	}
+
+	if citations := convertGeminiCitations(Candidate{}); citations != nil {
+		t.Errorf("expected no citations, got %+v", citations)
+	}
+}
+
+func TestPrepareStandardRequest_GoogleSearch(t *testing.T) {
+	provider := NewGeminiProvider(types.ProviderConfig{Type: types.ProviderTypeGemini})
+
+	req := provider.prepareStandardRequest(types.GenerateOptions{
+		Prompt: "Who won the match?",
+		ProviderOptions: map[string]interface{}{
+			"gemini": GeminiOptions{GoogleSearch: true},
+		},
+	})
+	if len(req.Tools) != 1 || req.Tools[0].GoogleSearch == nil {
+		t.Errorf("expected the google_search tool, got %+v", req.Tools)
+	}
+}
+
+func TestGeminiStream_Citations(t *testing.T) {
+	stream := newTestGeminiStream(
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"Go is compiled."}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[]},"finishReason":"STOP","groundingMetadata":{"groundingChunks":[{"web":{"uri":"https://go.dev","title":"go.dev"}}],"groundingSupports":[{"segment":{"endIndex":15,"text":"Go is compiled."},"groundingChunkIndices":[0]}]}}]}`,
+	)
+
+	var citations []types.Citation
+	for {
+		chunk, err := stream.Next()
+		citations = append(citations, chunk.Citations...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+	}
+
+	if len(citations) != 1 || citations[0].URL != "https://go.dev" || citations[0].Text != "Go is compiled." {
+		t.Errorf("unexpected citations %+v", citations)
+	}
+}
@@ -136,47 +136,33 @@ func (e *GeminiExtension) ProviderToStandard(response interface{}) (*types.Stand
 
 	candidate := geminiResp.Candidates[0]
 
-	// Extract text content
-	var content string
-	for _, part := range candidate.Content.Parts {
-		if part.Text != "" {
-			content += part.Text
-		}
-	}
+	// Extract text content and thoughts
+	content, thoughts := splitGeminiThoughts(candidate.Content.Parts)
 
 	// Convert tool calls if present
 	toolCalls := convertGeminiFunctionCallsToUniversal(candidate.Content.Parts)
 
 	message := types.ChatMessage{
-		Role:      "assistant",
-		Content:   content,
-		ToolCalls: toolCalls,
-	}
-
-	// Determine finish reason
-	finishReason := "stop"
-	if candidate.FinishReason == "SAFETY" {
-		finishReason = "content_filtered"
-	} else if len(toolCalls) > 0 {
-		finishReason = "tool_calls"
+		Role:             "assistant",
+		Content:          content,
+		ReasoningContent: thoughts,
+		ToolCalls:        toolCalls,
+		Citations:        convertGeminiCitations(candidate),
 	}
 
 	choices := []types.StandardChoice{
 		{
-			Index:        0,
-			Message:      message,
-			FinishReason: finishReason,
+			Index:           0,
+			Message:         message,
+			FinishReason:    geminiFinishReason(candidate.FinishReason, toolCalls),
+			RawFinishReason: candidate.FinishReason,
 		},
 	}
 
 	// Convert usage
 	usage := types.Usage{}
 	if geminiResp.UsageMetadata != nil {
-		usage = types.Usage{
-			PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      geminiResp.UsageMetadata.TotalTokenCount,
-		}
+		usage = geminiUsage(geminiResp.UsageMetadata)
 	}
 
 	// Create provider metadata
@@ -212,54 +198,40 @@ func (e *GeminiExtension) ProviderToStandardChunk(chunk interface{}) (*types.Sta
 
 	candidate := geminiChunk.Candidates[0]
 
-	// Extract text content
-	var content string
-	for _, part := range candidate.Content.Parts {
-		if part.Text != "" {
-			content += part.Text
-		}
-	}
+	// Extract text content and thoughts
+	content, thoughts := splitGeminiThoughts(candidate.Content.Parts)
 
 	// Convert tool calls if present
 	toolCalls := convertGeminiFunctionCallsToUniversal(candidate.Content.Parts)
 
 	delta := types.ChatMessage{
-		Role:      "assistant",
-		Content:   content,
-		ToolCalls: toolCalls,
+		Role:             "assistant",
+		Content:          content,
+		ReasoningContent: thoughts,
+		ToolCalls:        toolCalls,
+		Citations:        convertGeminiCitations(candidate),
 	}
 
-	// Determine finish reason
+	// Intermediate chunks have no finish reason
 	finishReason := ""
 	if candidate.FinishReason != "" {
-		switch candidate.FinishReason {
-		case "SAFETY":
-			finishReason = "content_filtered"
-		default:
-			if len(toolCalls) > 0 {
-				finishReason = "tool_calls"
-			} else {
-				finishReason = candidate.FinishReason
-			}
-		}
+		finishReason = geminiFinishReason(candidate.FinishReason, toolCalls)
 	}
 
 	choices := []types.StandardStreamChoice{
 		{
-			Index:        0,
-			Delta:        delta,
-			FinishReason: finishReason,
+			Index:           0,
+			Delta:           delta,
+			FinishReason:    finishReason,
+			RawFinishReason: candidate.FinishReason,
 		},
 	}
 
 	// Convert usage if present
 	var usage *types.Usage
 	if geminiChunk.UsageMetadata != nil {
-		usage = &types.Usage{
-			PromptTokens:     geminiChunk.UsageMetadata.PromptTokenCount,
-			CompletionTokens: geminiChunk.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      geminiChunk.UsageMetadata.TotalTokenCount,
-		}
+		converted := geminiUsage(geminiChunk.UsageMetadata)
+		usage = &converted
 	}
 
 	// Create provider metadata
@@ -22,6 +22,9 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 	"golang.org/x/time/rate"
@@ -78,7 +81,13 @@ func NewGeminiProvider(config types.ProviderConfig) *GeminiProvider {
 
 	// Create HTTP client using internal/http package
 	httpClient := pkghttp.NewHTTPClient(pkghttp.HTTPClientConfig{
-		Timeout: configHelper.ExtractTimeout(mergedConfig),
+		Timeout:          configHelper.ExtractTimeout(mergedConfig),
+		ConnectTimeout:   mergedConfig.ConnectTimeout,
+		RequestTimeout:   mergedConfig.RequestTimeout,
+		Headers:          mergedConfig.Headers,
+		UserAgent:        mergedConfig.UserAgent,
+		CompressRequests: mergedConfig.CompressRequests,
+		WrapTransport:    mergedConfig.WrapTransport,
 	})
 
 	// Extract the underlying http.Client for compatibility with existing code
@@ -171,9 +180,25 @@ func (p *GeminiProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+
 	p.IncrementRequestCount()
 	startTime := time.Now()
 
+	// Validate before any network I/O
+	if err := common.ValidateGenerateOptions(p.resolveModel("", options), options); err != nil {
+		return nil, err
+	}
+	ctx = middleware.WithModel(ctx, p.resolveModel("", options))
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return nil, err
+	}
+	if _, err := geminiOptionsFrom(options); err != nil {
+		return nil, err
+	}
+
 	// Check if streaming is requested
 	if options.Stream {
 		// Determine model for streaming with fallback priority
@@ -237,18 +262,29 @@ func (p *GeminiProvider) GenerateChatCompletion(
 	}
 
 	chunk := types.ChatCompletionChunk{
-		Content: responseMessage.Content,
-		Done:    true,
-		Usage:   usageValue,
+		Content:          responseMessage.Content,
+		ReasoningContent: responseMessage.ReasoningContent,
+		Citations:        responseMessage.Citations,
+		Done:             true,
+		Usage:            usageValue,
 	}
 
-	// Include tool calls if present
-	if len(responseMessage.ToolCalls) > 0 {
-		chunk.Choices = []types.ChatChoice{
-			{
-				Message: responseMessage,
-			},
-		}
+	if executions, ok := responseMessage.Metadata[metadataKeyCodeExecutions].([]types.CodeExecution); ok {
+		chunk.CodeExecutions = executions
+	}
+	finishReason, _ := responseMessage.Metadata[metadataKeyFinishReason].(string)
+	delete(responseMessage.Metadata, metadataKeyCodeExecutions)
+	delete(responseMessage.Metadata, metadataKeyFinishReason)
+	if len(responseMessage.Metadata) == 0 {
+		responseMessage.Metadata = nil
+	}
+
+	chunk.Choices = []types.ChatChoice{
+		{
+			Message:         responseMessage,
+			FinishReason:    geminiFinishReason(finishReason, responseMessage.ToolCalls),
+			RawFinishReason: finishReason,
+		},
 	}
 
 	return &MockStream{
@@ -374,6 +410,18 @@ func (p *GeminiProvider) Logout(ctx context.Context) error {
 	return p.Configure(newConfig)
 }
 
+// Warmup refreshes OAuth tokens and loads the model list so the first request does not
+// pay for them
+func (p *GeminiProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
+// RateLimitStatus returns the rate limits reported by the most recent response, see
+// types.RateLimitStatusProvider
+func (p *GeminiProvider) RateLimitStatus() types.RateLimitStatus {
+	return p.rateLimitHelper.Status()
+}
+
 // TestConnectivity performs a lightweight connectivity test to verify the provider can reach its service
 func (p *GeminiProvider) TestConnectivity(ctx context.Context) error {
 	// Check for OAuth token in context first (injected by caller)
@@ -456,9 +504,8 @@ func (p *GeminiProvider) testConnectivityWithAPIKey(ctx context.Context, apiKey
 
 	resp, err := testClient.Client().Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeGemini, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeGemini, "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -532,9 +579,8 @@ func (p *GeminiProvider) testConnectivityWithOAuth(ctx context.Context, accessTo
 
 	resp, err := testClient.Client().Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeGemini, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeGemini, "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -614,6 +660,18 @@ func (p *GeminiProvider) SupportsToolCalling() bool {
 	return true
 }
 
+// Capabilities implements types.CapabilitiesReporter from the models.dev defaults,
+// so vision and structured output are reported per model
+func (p *GeminiProvider) Capabilities(model string) types.Capabilities {
+	return common.ModelCapabilities(p, model)
+}
+
+// SupportedParameters implements types.ParameterSupportReporter. Requests ask for a single
+// candidate and carry no parallel_tool_calls, store or reasoning_effort.
+func (p *GeminiProvider) SupportedParameters(model string) types.ParameterSupport {
+	return common.SingleChoiceParameters(p, model)
+}
+
 func (p *GeminiProvider) SupportsStreaming() bool {
 	return true
 }
@@ -636,6 +694,28 @@ func (p *GeminiProvider) InvokeServerTool(
 
 // Tool Calling Conversion Functions
 
+// convertMessageToGeminiParts converts a message's content and tool calls to Gemini parts
+func convertMessageToGeminiParts(msg types.ChatMessage) []Part {
+	// Use GetContentParts() helper for unified access
+	contentParts := msg.GetContentParts()
+	var parts []Part
+
+	if len(contentParts) > 0 {
+		// Convert multimodal content parts
+		parts = convertContentPartsToGeminiParts(contentParts)
+	} else if len(msg.ToolCalls) == 0 {
+		// Fallback to string content (should not happen with GetContentParts)
+		parts = []Part{{Text: msg.Content}}
+	}
+
+	// Tool calls of an assistant turn, which carry the thought signatures a thinking
+	// model needs to continue
+	if len(msg.Parts) == 0 && len(msg.ToolCalls) > 0 {
+		parts = append(parts, convertUniversalToolCallsToGeminiParts(msg.ToolCalls)...)
+	}
+	return parts
+}
+
 // convertContentPartsToGeminiParts converts types.ContentPart to Gemini Part format
 func convertContentPartsToGeminiParts(parts []types.ContentPart) []Part {
 	if len(parts) == 0 {
@@ -679,6 +759,7 @@ func convertContentPartsToGeminiParts(parts []types.ContentPart) []Part {
 					Name: part.Name,
 					Args: part.Input,
 				},
+				ThoughtSignature: thoughtSignatureFrom(part.Extra),
 			})
 
 		case types.ContentTypeToolResult:
@@ -710,10 +791,12 @@ func convertContentPartsToGeminiParts(parts []types.ContentPart) []Part {
 			})
 
 		case types.ContentTypeThinking:
-			// Gemini doesn't have native thinking support, convert to text
+			// Sent back as a thought, with its signature if the model gave one
 			if part.Thinking != "" {
 				geminiParts = append(geminiParts, Part{
-					Text: fmt.Sprintf("[Thinking]: %s", part.Thinking),
+					Text:             part.Thinking,
+					Thought:          true,
+					ThoughtSignature: thoughtSignatureFrom(part.Extra),
 				})
 			}
 		}
@@ -823,6 +906,10 @@ func convertGeminiFunctionCallsToUniversal(parts []Part) []types.ToolCall {
 					Arguments: string(argsJSON),
 				},
 			}
+			if part.ThoughtSignature != "" {
+				// Must be sent back with the call, see convertUniversalToolCallsToGeminiParts
+				toolCall.Metadata = map[string]interface{}{metadataKeyThoughtSignature: part.ThoughtSignature}
+			}
 			toolCalls = append(toolCalls, toolCall)
 			callIndex++
 		}
@@ -847,6 +934,7 @@ func convertUniversalToolCallsToGeminiParts(toolCalls []types.ToolCall) []Part {
 				Name: tc.Function.Name,
 				Args: args,
 			},
+			ThoughtSignature: thoughtSignatureFrom(tc.Metadata),
 		}
 	}
 	return parts
@@ -871,6 +959,11 @@ func (p *GeminiProvider) executeStreamWithAuth(ctx context.Context, options type
 		creds := p.authHelper.OAuthManager.GetCredentials()
 		var lastErr error
 		for _, cred := range creds {
+			// Failing over to another credential is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICallWithToken(ctx, options, model, cred.AccessToken)
 			if err == nil {
 				return stream, nil
@@ -890,6 +983,11 @@ func (p *GeminiProvider) executeStreamWithAuth(ctx context.Context, options type
 		keys := p.authHelper.KeyManager.GetKeys()
 		var lastErr error
 		for _, apiKey := range keys {
+			// Failing over to another key is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICallWithAPIKey(ctx, options, model, apiKey)
 			if err == nil {
 				p.authHelper.KeyManager.ReportSuccess(apiKey)
@@ -951,19 +1049,14 @@ func (p *GeminiProvider) makeStreamingStandardAPICallWithOAuth(ctx context.Conte
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, types.NewRequestError(types.ProviderTypeGemini, "request failed", err)
 	}
 
 	// Check for 429 status and parse retry-after
 	if resp.StatusCode == 429 {
 		body, _ := io.ReadAll(resp.Body)
 		func() { _ = resp.Body.Close() }() //nolint:staticcheck // Empty branch is intentional - we ignore close errors
-		if info, err := p.rateLimitHelper.GetParser().Parse(resp.Header, model); err == nil && info.RetryAfter > 0 {
-			// Update tracker with retry info
-			p.rateLimitHelper.UpdateRateLimitInfo(info)
-			return nil, fmt.Errorf("rate limited, retry after %v", info.RetryAfter)
-		}
-		return nil, fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(body))
+		return nil, p.newRateLimitError(resp, model, body)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -972,11 +1065,11 @@ func (p *GeminiProvider) makeStreamingStandardAPICallWithOAuth(ctx context.Conte
 		return nil, fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(body))
 	}
 
-	return &GeminiStream{
+	return streaming.StreamFromContext(ctx, &GeminiStream{
 		response: resp,
 		reader:   bufio.NewReader(resp.Body),
 		done:     false,
-	}, nil
+	}), nil
 }
 
 // makeStreamingAPICallWithAPIKey makes a streaming API call with API key
@@ -1000,21 +1093,9 @@ func (p *GeminiProvider) makeStreamingAPICallWithAPIKey(ctx context.Context, opt
 	if len(options.Messages) > 0 {
 		contents = make([]Content, len(options.Messages))
 		for i, msg := range options.Messages {
-			// Use GetContentParts() helper for unified access
-			contentParts := msg.GetContentParts()
-			var parts []Part
-
-			if len(contentParts) > 0 {
-				// Convert multimodal content parts
-				parts = convertContentPartsToGeminiParts(contentParts)
-			} else {
-				// Fallback to string content (should not happen with GetContentParts)
-				parts = []Part{{Text: msg.Content}}
-			}
-
 			contents[i] = Content{
 				Role:  msg.Role,
-				Parts: parts,
+				Parts: convertMessageToGeminiParts(msg),
 			}
 		}
 	} else if options.Prompt != "" {
@@ -1033,7 +1114,7 @@ func (p *GeminiProvider) makeStreamingAPICallWithAPIKey(ctx context.Context, opt
 			Temperature:     0.7,
 			TopP:            0.95,
 			TopK:            40,
-			MaxOutputTokens: 8192,
+			MaxOutputTokens: common.ResolveMaxTokens(p.Type(), model, options.MaxTokens),
 		},
 	}
 
@@ -1042,6 +1123,8 @@ func (p *GeminiProvider) makeStreamingAPICallWithAPIKey(ctx context.Context, opt
 		requestBody.Tools = convertToGeminiTools(options.Tools)
 	}
 
+	applyGeminiOptions(&requestBody, options)
+
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -1061,19 +1144,14 @@ func (p *GeminiProvider) makeStreamingAPICallWithAPIKey(ctx context.Context, opt
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, types.NewRequestError(types.ProviderTypeGemini, "request failed", err)
 	}
 
 	// Check for 429 status and parse retry-after
 	if resp.StatusCode == 429 {
 		body, _ := io.ReadAll(resp.Body)
 		func() { _ = resp.Body.Close() }() //nolint:staticcheck // Empty branch is intentional - we ignore close errors
-		if info, err := p.rateLimitHelper.GetParser().Parse(resp.Header, model); err == nil && info.RetryAfter > 0 {
-			// Update tracker with retry info
-			p.rateLimitHelper.UpdateRateLimitInfo(info)
-			return nil, fmt.Errorf("rate limited, retry after %v", info.RetryAfter)
-		}
-		return nil, fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(body))
+		return nil, p.newRateLimitError(resp, model, body)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -1082,22 +1160,50 @@ func (p *GeminiProvider) makeStreamingAPICallWithAPIKey(ctx context.Context, opt
 		return nil, fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(body))
 	}
 
-	return &GeminiStream{
+	return streaming.StreamFromContext(ctx, &GeminiStream{
 		response: resp,
 		reader:   bufio.NewReader(resp.Body),
 		done:     false,
-	}, nil
+	}), nil
 }
 
 // GeminiStream implements ChatCompletionStream for real streaming responses
 type GeminiStream struct {
 	response *http.Response
 	reader   *bufio.Reader
+	events   *streaming.SSEScanner
 	done     bool
 	mutex    sync.Mutex
+
+	// Text of the previous payload, to turn cumulative payloads into incremental deltas
+	text       string
+	modeKnown  bool
+	cumulative bool
+
+	// While the mode is undecided: the first payload's text, sent on, and the payloads
+	// after it that repeat it, held back until the mode is known
+	first   string
+	held    []string
+	growths int
+
+	// Tool calls sent so far. Gemini sends each function call whole, in one payload.
+	toolCalls []types.ToolCall
 }
 
-func (s *GeminiStream) Next() (types.ChatCompletionChunk, error) {
+const (
+	// minCumulativePrefix is the shortest repeated text taken as a sign of cumulative
+	// payloads; an incremental stream often starts a payload with the same newline
+	// or list marker as the previous one
+	minCumulativePrefix = 8
+
+	// cumulativeEvidence is how many payloads must each extend the previous one
+	// before a stream is treated as cumulative
+	cumulativeEvidence = 2
+)
+
+func (s *GeminiStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer streaming.RecoverStreamPanic(types.ProviderTypeGemini, &chunk, &err)
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -1105,26 +1211,20 @@ func (s *GeminiStream) Next() (types.ChatCompletionChunk, error) {
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
 
+	if s.events == nil {
+		s.events = streaming.NewSSEScanner(s.reader, streaming.SSEScannerOptions{})
+	}
+
 	for {
-		line, err := s.reader.ReadString('\n')
+		event, err := s.events.Next()
 		if err != nil {
 			if err == io.EOF {
 				s.done = true
-				return types.ChatCompletionChunk{Done: true}, io.EOF
+				return types.ChatCompletionChunk{Content: s.flushHeld(), Done: true}, io.EOF
 			}
 			return types.ChatCompletionChunk{}, err
 		}
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		if !strings.HasPrefix(line, "data: ") {
-			continue // Skip non-data lines
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
+		data := event.Data
 
 		var streamResp GeminiStreamResponse
 		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
@@ -1133,32 +1233,48 @@ func (s *GeminiStream) Next() (types.ChatCompletionChunk, error) {
 
 		if len(streamResp.Candidates) > 0 {
 			candidate := streamResp.Candidates[0]
-			if len(candidate.Content.Parts) > 0 {
-				var fullText strings.Builder
-				for _, part := range candidate.Content.Parts {
-					if part.Text != "" {
-						fullText.WriteString(part.Text)
-					}
+			// Grounding metadata may come in a payload of its own
+			if len(candidate.Content.Parts) > 0 || candidate.GroundingMetadata != nil || candidate.CitationMetadata != nil {
+				fullText, thoughts := splitGeminiThoughts(candidate.Content.Parts)
+				toolCalls := s.newToolCalls(candidate.Content.Parts)
+
+				content := s.delta(fullText)
+				if candidate.FinishReason != "" {
+					content += s.flushHeld()
 				}
-
-				content := fullText.String()
 				chunk := types.ChatCompletionChunk{
-					Content: content,
-					Done:    candidate.FinishReason != "",
+					Content:          content,
+					ReasoningContent: thoughts,
+					Done:             candidate.FinishReason != "",
+					CodeExecutions:   convertGeminiCodeExecutions(candidate.Content.Parts),
+					Citations:        convertGeminiCitations(candidate),
+				}
+				if content == "" && fullText != "" && thoughts == "" && !chunk.Done && len(chunk.CodeExecutions) == 0 && len(chunk.Citations) == 0 && len(toolCalls) == 0 && streamResp.UsageMetadata == nil {
+					continue // A cumulative payload that adds nothing
+				}
+				if len(toolCalls) > 0 || candidate.FinishReason != "" {
+					choice := types.ChatChoice{}
+					if len(toolCalls) > 0 {
+						choice.Delta = types.ChatMessage{Role: "assistant", ToolCalls: toolCalls}
+					}
+					if candidate.FinishReason != "" {
+						choice.FinishReason = geminiFinishReason(candidate.FinishReason, s.toolCalls)
+						choice.RawFinishReason = candidate.FinishReason
+					}
+					chunk.Choices = []types.ChatChoice{choice}
 				}
 
 				if streamResp.UsageMetadata != nil {
-					chunk.Usage = types.Usage{
-						PromptTokens:     streamResp.UsageMetadata.PromptTokenCount,
-						CompletionTokens: streamResp.UsageMetadata.CandidatesTokenCount,
-						TotalTokens:      streamResp.UsageMetadata.TotalTokenCount,
-					}
+					chunk.Usage = geminiUsage(streamResp.UsageMetadata)
 				}
 
 				if chunk.Done {
 					s.done = true
 					return chunk, io.EOF
 				}
+				if streaming.IsHeartbeat(chunk) {
+					continue
+				}
 
 				return chunk, nil
 			}
@@ -1166,6 +1282,89 @@ func (s *GeminiStream) Next() (types.ChatCompletionChunk, error) {
 	}
 }
 
+// newToolCalls converts a payload's function calls, with their thought signatures,
+// numbering them after the calls of earlier payloads so their IDs and indexes are
+// unique within the stream
+func (s *GeminiStream) newToolCalls(parts []Part) []types.ToolCall {
+	toolCalls := convertGeminiFunctionCallsToUniversal(parts)
+	for i := range toolCalls {
+		index := len(s.toolCalls)
+		toolCalls[i].ID = fmt.Sprintf("call_%d", index)
+		toolCalls[i].Index = &index
+		s.toolCalls = append(s.toolCalls, toolCalls[i])
+	}
+	return toolCalls
+}
+
+// delta returns the new text in a payload's content. Gemini normally streams
+// incremental text, but in some modes each payload repeats everything sent so far.
+// The stream is taken to be cumulative once cumulativeEvidence payloads in a row have
+// each started with all of the previous one, at least minCumulativePrefix long, and
+// added to it; payloads that could be either are held back until then. Any other
+// payload decides the stream is incremental, and the held payloads are sent with it.
+// Once cumulative, the repeated prefix is dropped from every payload, and a payload
+// that rewrites earlier text is passed on whole.
+func (s *GeminiStream) delta(content string) string {
+	if content == "" {
+		return ""
+	}
+
+	if !s.modeKnown {
+		if s.text == "" {
+			s.first = content
+			s.text = content
+			return content
+		}
+		if len(s.text) < minCumulativePrefix || !strings.HasPrefix(content, s.text) {
+			s.modeKnown = true
+			held := strings.Join(s.held, "")
+			s.held = nil
+			return held + content
+		}
+		if len(content) > len(s.text) {
+			s.growths++
+		}
+		s.held = append(s.held, content)
+		s.text = content
+		if s.growths < cumulativeEvidence {
+			return ""
+		}
+		s.modeKnown = true
+		s.cumulative = true
+		s.held = nil
+		return content[len(s.first):]
+	}
+	if !s.cumulative {
+		return content
+	}
+
+	seen := s.text
+	s.text = content
+	if strings.HasPrefix(content, seen) {
+		return content[len(seen):]
+	}
+	return content
+}
+
+// flushHeld decides the mode of a stream that ends while payloads are held back, and
+// returns their text: what they add to the first payload if any of them extended it,
+// as a cumulative stream, or all of them as incremental text
+func (s *GeminiStream) flushHeld() string {
+	if len(s.held) == 0 {
+		return ""
+	}
+	s.modeKnown = true
+	var text string
+	if s.growths > 0 {
+		s.cumulative = true
+		text = s.text[len(s.first):]
+	} else {
+		text = strings.Join(s.held, "")
+	}
+	s.held = nil
+	return text
+}
+
 func (s *GeminiStream) Close() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -1245,21 +1444,9 @@ func (p *GeminiProvider) prepareStandardRequest(options types.GenerateOptions) G
 	if len(options.Messages) > 0 {
 		contents = make([]Content, len(options.Messages))
 		for i, msg := range options.Messages {
-			// Use GetContentParts() helper for unified access
-			contentParts := msg.GetContentParts()
-			var parts []Part
-
-			if len(contentParts) > 0 {
-				// Convert multimodal content parts
-				parts = convertContentPartsToGeminiParts(contentParts)
-			} else {
-				// Fallback to string content (should not happen with GetContentParts)
-				parts = []Part{{Text: msg.Content}}
-			}
-
 			contents[i] = Content{
 				Role:  msg.Role,
-				Parts: parts,
+				Parts: convertMessageToGeminiParts(msg),
 			}
 		}
 	} else if options.Prompt != "" {
@@ -1276,7 +1463,7 @@ func (p *GeminiProvider) prepareStandardRequest(options types.GenerateOptions) G
 		Temperature:     0.7,
 		TopP:            0.95,
 		TopK:            40,
-		MaxOutputTokens: 8192,
+		MaxOutputTokens: common.ResolveMaxTokens(p.Type(), p.resolveModel("", options), options.MaxTokens),
 	}
 
 	// Handle structured outputs via ResponseFormat
@@ -1304,6 +1491,8 @@ func (p *GeminiProvider) prepareStandardRequest(options types.GenerateOptions) G
 		requestBody.Tools = convertToGeminiTools(options.Tools)
 	}
 
+	applyGeminiOptions(&requestBody, options)
+
 	return requestBody
 }
 
@@ -1336,7 +1525,7 @@ func (p *GeminiProvider) executeStandardAPIRequest(ctx context.Context, model st
 	// Make the request
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, types.NewRequestError(types.ProviderTypeGemini, "request failed", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -1348,12 +1537,7 @@ func (p *GeminiProvider) executeStandardAPIRequest(ctx context.Context, model st
 
 	// Handle rate limiting
 	if resp.StatusCode == 429 {
-		if info, err := p.rateLimitHelper.GetParser().Parse(resp.Header, model); err == nil && info.RetryAfter > 0 {
-			// Update tracker with retry info
-			p.rateLimitHelper.UpdateRateLimitInfo(info)
-			return nil, fmt.Errorf("rate limited, retry after %v", info.RetryAfter)
-		}
-		return nil, fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(responseBody))
+		return nil, p.newRateLimitError(resp, model, responseBody)
 	}
 
 	// Check status code
@@ -1398,7 +1582,7 @@ func (p *GeminiProvider) makeStandardAPICallWithOAuth(ctx context.Context, model
 	// Make the request
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, types.NewRequestError(types.ProviderTypeGemini, "request failed", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -1410,12 +1594,7 @@ func (p *GeminiProvider) makeStandardAPICallWithOAuth(ctx context.Context, model
 
 	// Handle rate limiting
 	if resp.StatusCode == 429 {
-		if info, err := p.rateLimitHelper.GetParser().Parse(resp.Header, model); err == nil && info.RetryAfter > 0 {
-			// Update tracker with retry info
-			p.rateLimitHelper.UpdateRateLimitInfo(info)
-			return nil, fmt.Errorf("rate limited, retry after %v", info.RetryAfter)
-		}
-		return nil, fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(responseBody))
+		return nil, p.newRateLimitError(resp, model, responseBody)
 	}
 
 	// Check status code
@@ -1448,14 +1627,7 @@ func (p *GeminiProvider) parseStandardGeminiResponse(responseBody []byte, _ stri
 		return "", nil, fmt.Errorf("no parts in candidate content")
 	}
 
-	var fullText strings.Builder
-	for _, part := range candidate.Content.Parts {
-		if part.Text != "" {
-			fullText.WriteString(part.Text)
-		}
-	}
-
-	result := fullText.String()
+	result, _ := splitGeminiThoughts(candidate.Content.Parts)
 	if result == "" {
 		return "", nil, fmt.Errorf("empty response from Gemini API")
 	}
@@ -1463,16 +1635,37 @@ func (p *GeminiProvider) parseStandardGeminiResponse(responseBody []byte, _ stri
 	// Extract usage information
 	var usage *types.Usage
 	if apiResp.UsageMetadata != nil {
-		usage = &types.Usage{
-			PromptTokens:     apiResp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: apiResp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      apiResp.UsageMetadata.TotalTokenCount,
-		}
+		converted := geminiUsage(apiResp.UsageMetadata)
+		usage = &converted
 	}
 
 	return result, usage, nil
 }
 
+// geminiFinishReason normalizes a candidate's finishReason. Gemini reports STOP for
+// candidates that call functions, so those are reported as tool calls.
+func geminiFinishReason(finishReason string, toolCalls []types.ToolCall) string {
+	reason := types.NormalizeFinishReason(types.ProviderTypeGemini, finishReason)
+	if (reason == "" || reason == types.FinishStop) && len(toolCalls) > 0 {
+		return types.FinishToolCalls
+	}
+	if reason == "" {
+		return types.FinishStop
+	}
+	return reason
+}
+
+// geminiUsage converts usage metadata. Gemini counts thinking apart from the
+// candidates, and both are billed as output, so thoughts are completion tokens.
+func geminiUsage(metadata *UsageMetadata) types.Usage {
+	return types.Usage{
+		PromptTokens:     metadata.PromptTokenCount,
+		CompletionTokens: metadata.CandidatesTokenCount + metadata.ThoughtsTokenCount,
+		TotalTokens:      metadata.TotalTokenCount,
+		CacheReadTokens:  metadata.CachedContentTokenCount,
+	}
+}
+
 // parseStandardGeminiResponseMessage parses standard Gemini response and returns ChatMessage
 func (p *GeminiProvider) parseStandardGeminiResponseMessage(responseBody []byte, _ string) (types.ChatMessage, *types.Usage, error) {
 	// Parse response (standard Gemini API returns direct response)
@@ -1495,28 +1688,33 @@ func (p *GeminiProvider) parseStandardGeminiResponseMessage(responseBody []byte,
 		return types.ChatMessage{}, nil, fmt.Errorf("no parts in candidate content")
 	}
 
-	// Extract text content and tool calls
-	var fullText strings.Builder
-	for _, part := range candidate.Content.Parts {
-		if part.Text != "" {
-			fullText.WriteString(part.Text)
-		}
-	}
+	// Extract text content, thoughts and tool calls
+	text, thoughts := splitGeminiThoughts(candidate.Content.Parts)
 
 	message := types.ChatMessage{
-		Role:      candidate.Content.Role,
-		Content:   fullText.String(),
-		ToolCalls: convertGeminiFunctionCallsToUniversal(candidate.Content.Parts),
+		Role:             candidate.Content.Role,
+		Content:          text,
+		ReasoningContent: thoughts,
+		ToolCalls:        convertGeminiFunctionCallsToUniversal(candidate.Content.Parts),
+		Citations:        convertGeminiCitations(candidate),
+	}
+
+	// Carried to the response chunk by GenerateChatCompletion
+	if executions := convertGeminiCodeExecutions(candidate.Content.Parts); len(executions) > 0 {
+		message.Metadata = map[string]interface{}{metadataKeyCodeExecutions: executions}
+	}
+	if candidate.FinishReason != "" {
+		if message.Metadata == nil {
+			message.Metadata = make(map[string]interface{})
+		}
+		message.Metadata[metadataKeyFinishReason] = candidate.FinishReason
 	}
 
 	// Extract usage information
 	var usage *types.Usage
 	if apiResp.UsageMetadata != nil {
-		usage = &types.Usage{
-			PromptTokens:     apiResp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: apiResp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      apiResp.UsageMetadata.TotalTokenCount,
-		}
+		converted := geminiUsage(apiResp.UsageMetadata)
+		usage = &converted
 	}
 
 	return message, usage, nil
@@ -1649,3 +1847,21 @@ func (p *GeminiProvider) GetAuthURL(redirectURI string, state string) string {
 
 	return authURL
 }
+
+// newRateLimitError builds a rate limit error carrying the response's retry hint
+// and updates the rate limit tracker
+func (p *GeminiProvider) newRateLimitError(resp *http.Response, model string, body []byte) *types.ProviderError {
+	retryAfter := retry.ParseRetryAfter(resp.Header)
+	if info, err := p.rateLimitHelper.GetParser().Parse(resp.Header, model); err == nil && info.RetryAfter > 0 {
+		// Update tracker with retry info
+		p.rateLimitHelper.UpdateRateLimitInfo(info)
+		if retryAfter == 0 {
+			retryAfter = info.RetryAfter
+		}
+	}
+
+	return types.NewRateLimitError(types.ProviderTypeGemini, 0).
+		WithStatusCode(resp.StatusCode).
+		WithRetryAfterDuration(retryAfter).
+		WithOriginalErr(fmt.Errorf("gemini API error: %d - %s", resp.StatusCode, string(body)))
+}
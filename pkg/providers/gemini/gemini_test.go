@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -1599,3 +1600,125 @@ func TestGeminiProvider_OAuthBackwardCompatibility(t *testing.T) {
 		t.Errorf("Expected auth method 'api_key', got '%v'", authStatus["method"])
 	}
 }
+
+func TestPrepareStandardRequest_MaxTokens(t *testing.T) {
+	provider := NewGeminiProvider(types.ProviderConfig{Type: types.ProviderTypeGemini})
+
+	req := provider.prepareStandardRequest(types.GenerateOptions{Prompt: "Hi", Model: "gemini-2.0-flash", MaxTokens: 1000})
+	if req.GenerationConfig.MaxOutputTokens != 1000 {
+		t.Errorf("Expected requested max tokens 1000, got %d", req.GenerationConfig.MaxOutputTokens)
+	}
+
+	req = provider.prepareStandardRequest(types.GenerateOptions{Prompt: "Hi", Model: "gemini-2.0-flash", MaxTokens: 100000})
+	if req.GenerationConfig.MaxOutputTokens != 8192 {
+		t.Errorf("Expected max tokens capped at the model limit 8192, got %d", req.GenerationConfig.MaxOutputTokens)
+	}
+
+	req = provider.prepareStandardRequest(types.GenerateOptions{Prompt: "Hi", Model: "gemini-2.5-pro"})
+	if req.GenerationConfig.MaxOutputTokens != models.DefaultReasoningMaxOutputTokens {
+		t.Errorf("Expected reasoning default %d, got %d", models.DefaultReasoningMaxOutputTokens, req.GenerationConfig.MaxOutputTokens)
+	}
+}
+
+// newTestGeminiStream returns a GeminiStream reading the given SSE payloads
+func newTestGeminiStream(payloads ...string) *GeminiStream {
+	var sse strings.Builder
+	for _, payload := range payloads {
+		sse.WriteString("data: " + payload + "\n\n")
+	}
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(sse.String()))}
+	return &GeminiStream{response: resp, reader: bufio.NewReader(resp.Body)}
+}
+
+// collectGeminiStream concatenates chunk contents the way callers accumulate a response
+func collectGeminiStream(t *testing.T, stream *GeminiStream) (string, []string) {
+	t.Helper()
+	var response string
+	var deltas []string
+	for {
+		chunk, err := stream.Next()
+		if chunk.Content != "" {
+			response += chunk.Content
+			deltas = append(deltas, chunk.Content)
+		}
+		if err == io.EOF {
+			return response, deltas
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func TestGeminiStream_CumulativePayloads(t *testing.T) {
+	stream := newTestGeminiStream(
+		`{"candidates":[{"content":{"parts":[{"text":"The quick"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"The quick brown fox"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"The quick brown fox"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"The quick brown fox jumps."}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":5,"totalTokenCount":8}}`,
+	)
+
+	response, deltas := collectGeminiStream(t, stream)
+	if response != "The quick brown fox jumps." {
+		t.Errorf("Expected the response without duplication, got %q", response)
+	}
+	// The second payload is held until the fourth confirms the stream is cumulative
+	expected := []string{"The quick", " brown fox jumps."}
+	if strings.Join(deltas, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected deltas %q, got %q", expected, deltas)
+	}
+}
+
+func TestGeminiStream_IncrementalPayloads(t *testing.T) {
+	stream := newTestGeminiStream(
+		`{"candidates":[{"content":{"parts":[{"text":"Hello"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":", world"}]}}]}`,
+		// Starts with the first payload's text, but the stream is already known to be incremental
+		`{"candidates":[{"content":{"parts":[{"text":"Hello again"}]},"finishReason":"STOP"}]}`,
+	)
+
+	response, _ := collectGeminiStream(t, stream)
+	if response != "Hello, worldHello again" {
+		t.Errorf("Expected incremental payloads to be passed through, got %q", response)
+	}
+}
+
+func TestGeminiStream_IncrementalRepeatedPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads []string
+		expected string
+	}{
+		{"newline", []string{"\n", "\nfoo", " bar"}, "\n\nfoo bar"},
+		{"list marker", []string{"-", "- item", "\n- other"}, "-- item\n- other"},
+		{"single extension", []string{"Here is a list", "Here is a list of things", " to do."}, "Here is a listHere is a list of things to do."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payloads []string
+			for _, text := range tt.payloads {
+				data, _ := json.Marshal(text)
+				payloads = append(payloads, `{"candidates":[{"content":{"parts":[{"text":`+string(data)+`}]}}]}`)
+			}
+			response, _ := collectGeminiStream(t, newTestGeminiStream(payloads...))
+			if response != tt.expected {
+				t.Errorf("Expected incremental text to be kept, got %q", response)
+			}
+		})
+	}
+}
+
+func TestGeminiStream_CumulativeRewrite(t *testing.T) {
+	stream := newTestGeminiStream(
+		`{"candidates":[{"content":{"parts":[{"text":"First draft"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"First draft, one"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"First draft, one two"}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"Final text"}]},"finishReason":"STOP"}]}`,
+	)
+
+	_, deltas := collectGeminiStream(t, stream)
+	if len(deltas) != 3 || deltas[1] != ", one two" || deltas[2] != "Final text" {
+		t.Errorf("Expected a rewritten payload to be passed on whole, got %q", deltas)
+	}
+}
@@ -226,20 +226,24 @@ func TestConvertContentPartsToGeminiParts(t *testing.T) {
 			},
 		},
 		{
-			name: "thinking part converts to text with prefix",
+			name: "thinking part converts to thought",
 			input: []types.ContentPart{
 				{
 					Type:     types.ContentTypeThinking,
 					Thinking: "Let me analyze this problem...",
+					Extra:    map[string]interface{}{"thought_signature": "sig-1"},
 				},
 			},
 			validate: func(t *testing.T, result []Part) {
 				if len(result) != 1 {
 					t.Fatalf("Expected 1 part, got %d", len(result))
 				}
-				expected := "[Thinking]: Let me analyze this problem..."
-				if result[0].Text != expected {
-					t.Errorf("Expected text '%s', got '%s'", expected, result[0].Text)
+				expected := "Let me analyze this problem..."
+				if result[0].Text != expected || !result[0].Thought {
+					t.Errorf("Expected thought '%s', got '%s' (thought=%v)", expected, result[0].Text, result[0].Thought)
+				}
+				if result[0].ThoughtSignature != "sig-1" {
+					t.Errorf("Expected thought signature 'sig-1', got '%s'", result[0].ThoughtSignature)
 				}
 			},
 		},
@@ -0,0 +1,202 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// metadataKeyCodeExecutions carries parsed code executions from the response parser
+// to the response chunk
+const metadataKeyCodeExecutions = "code_executions"
+
+// metadataKeyFinishReason carries the candidate's finishReason to the response chunk
+const metadataKeyFinishReason = "finish_reason"
+
+// metadataKeyThoughtSignature holds a part's thought signature in ToolCall.Metadata
+// and ContentPart.Extra, from which it is sent back to the model
+const metadataKeyThoughtSignature = "thought_signature"
+
+// GeminiOptions configures Gemini-specific request features.
+// Pass it through GenerateOptions.ProviderOptions under the "gemini" key:
+//
+//	options := types.GenerateOptions{
+//	    Prompt: "What is the sum of the first 50 prime numbers?",
+//	    ProviderOptions: map[string]interface{}{
+//	        "gemini": gemini.GeminiOptions{CodeExecution: true},
+//	    },
+//	}
+//
+// The code the model ran and its output are returned in
+// ChatCompletionChunk.CodeExecutions.
+//
+// See https://ai.google.dev/gemini-api/docs/code-execution
+// and https://ai.google.dev/gemini-api/docs/thinking
+type GeminiOptions struct {
+	// CodeExecution enables the built-in code_execution tool, which lets the model
+	// write and run Python code in a sandbox
+	CodeExecution bool `json:"code_execution,omitempty"`
+
+	// GoogleSearch grounds responses with Google Search. The pages used are returned
+	// in ChatCompletionChunk.Citations.
+	GoogleSearch bool `json:"google_search,omitempty"`
+
+	// Thinking configures the thinking of Gemini 2.5 and later models. Nil leaves the
+	// model's default, under which thoughts are not returned.
+	Thinking *GeminiThinking `json:"thinking,omitempty"`
+}
+
+// GeminiThinking configures a model's thinking. Thought summaries are returned in
+// ChatCompletionChunk.ReasoningContent and ChatMessage.ReasoningContent.
+type GeminiThinking struct {
+	// Budget is the number of tokens the model may think for: 0 turns thinking off
+	// (where the model allows it) and -1 lets the model decide. Nil keeps the
+	// model's default budget.
+	Budget *int `json:"budget,omitempty"`
+
+	// IncludeThoughts returns summaries of the model's thoughts
+	IncludeThoughts bool `json:"include_thoughts,omitempty"`
+}
+
+// WithThinking returns o with thinking enabled for up to budget tokens (-1 lets the
+// model decide) and thought summaries included in the response:
+//
+//	"gemini": gemini.GeminiOptions{}.WithThinking(2048)
+//
+// A budget of 0 turns thinking off.
+func (o GeminiOptions) WithThinking(budget int) GeminiOptions {
+	o.Thinking = &GeminiThinking{Budget: &budget, IncludeThoughts: budget != 0}
+	return o
+}
+
+// geminiOptionsFrom extracts GeminiOptions from GenerateOptions.ProviderOptions.
+// Accepts the struct, a pointer to it, or a generic map (e.g. decoded from JSON config).
+func geminiOptionsFrom(options types.GenerateOptions) (*GeminiOptions, error) {
+	raw, ok := options.ProviderOptions[string(types.ProviderTypeGemini)]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case GeminiOptions:
+		return &v, nil
+	case *GeminiOptions:
+		return v, nil
+	default:
+		// Round-trip through JSON to support map[string]interface{} and similar
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gemini provider options: %w", err)
+		}
+		var opts GeminiOptions
+		if err := json.Unmarshal(data, &opts); err != nil {
+			return nil, fmt.Errorf("invalid gemini provider options: %w", err)
+		}
+		return &opts, nil
+	}
+}
+
+// applyGeminiOptions adds the request features enabled in options.ProviderOptions.
+// Invalid provider options are rejected earlier, by GenerateChatCompletion.
+func applyGeminiOptions(requestBody *GenerateContentRequest, options types.GenerateOptions) {
+	geminiOpts, _ := geminiOptionsFrom(options)
+	if geminiOpts == nil {
+		return
+	}
+	if geminiOpts.CodeExecution {
+		requestBody.Tools = append(requestBody.Tools, GeminiTool{CodeExecution: &GeminiCodeExecution{}})
+	}
+	if geminiOpts.GoogleSearch {
+		requestBody.Tools = append(requestBody.Tools, GeminiTool{GoogleSearch: &GeminiGoogleSearch{}})
+	}
+	if geminiOpts.Thinking != nil {
+		if requestBody.GenerationConfig == nil {
+			requestBody.GenerationConfig = &GenerationConfig{}
+		}
+		requestBody.GenerationConfig.ThinkingConfig = &ThinkingConfig{
+			ThinkingBudget:  geminiOpts.Thinking.Budget,
+			IncludeThoughts: geminiOpts.Thinking.IncludeThoughts,
+		}
+	}
+}
+
+// convertGeminiCodeExecutions collects the executableCode and codeExecutionResult parts
+// of a response. A result is paired with the code part preceding it.
+func convertGeminiCodeExecutions(parts []Part) []types.CodeExecution {
+	var executions []types.CodeExecution
+	for _, part := range parts {
+		switch {
+		case part.ExecutableCode != nil:
+			executions = append(executions, types.CodeExecution{
+				Language: part.ExecutableCode.Language,
+				Code:     part.ExecutableCode.Code,
+			})
+		case part.CodeExecutionResult != nil:
+			if n := len(executions); n > 0 && executions[n-1].Outcome == "" {
+				executions[n-1].Outcome = part.CodeExecutionResult.Outcome
+				executions[n-1].Output = part.CodeExecutionResult.Output
+				continue
+			}
+			// The code was sent in an earlier stream chunk
+			executions = append(executions, types.CodeExecution{
+				Outcome: part.CodeExecutionResult.Outcome,
+				Output:  part.CodeExecutionResult.Output,
+			})
+		}
+	}
+	return executions
+}
+
+// convertGeminiCitations returns the sources of a candidate. Each grounding support
+// gives a citation per supporting chunk, located at the support's segment; chunks no
+// support refers to are listed without a location. Recitation sources follow.
+func convertGeminiCitations(candidate Candidate) []types.Citation {
+	var citations []types.Citation
+
+	if grounding := candidate.GroundingMetadata; grounding != nil {
+		cited := make([]bool, len(grounding.GroundingChunks))
+		for _, support := range grounding.GroundingSupports {
+			for _, index := range support.GroundingChunkIndices {
+				if index < 0 || index >= len(grounding.GroundingChunks) {
+					continue
+				}
+				cited[index] = true
+				citation := groundingChunkCitation(grounding.GroundingChunks[index])
+				citation.Text = support.Segment.Text
+				citation.StartIndex = support.Segment.StartIndex
+				citation.EndIndex = support.Segment.EndIndex
+				citations = append(citations, citation)
+			}
+		}
+		for i, chunk := range grounding.GroundingChunks {
+			if !cited[i] {
+				citations = append(citations, groundingChunkCitation(chunk))
+			}
+		}
+	}
+
+	if candidate.CitationMetadata != nil {
+		for _, source := range candidate.CitationMetadata.CitationSources {
+			citations = append(citations, types.Citation{
+				Title:      source.Title,
+				URL:        source.URI,
+				StartIndex: source.StartIndex,
+				EndIndex:   source.EndIndex,
+			})
+		}
+	}
+	return citations
+}
+
+// groundingChunkCitation returns the citation of a grounding source, without a location
+func groundingChunkCitation(chunk GroundingChunk) types.Citation {
+	source := chunk.Web
+	if source == nil {
+		source = chunk.RetrievedContext
+	}
+	if source == nil {
+		return types.Citation{}
+	}
+	return types.Citation{Title: source.Title, URL: source.URI, Snippet: source.Text}
+}
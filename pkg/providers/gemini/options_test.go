@@ -0,0 +1,242 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestGeminiOptionsFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     interface{}
+		want    bool
+		wantErr bool
+	}{
+		{name: "struct", raw: GeminiOptions{CodeExecution: true}, want: true},
+		{name: "pointer", raw: &GeminiOptions{CodeExecution: true}, want: true},
+		{name: "map", raw: map[string]interface{}{"code_execution": true}, want: true},
+		{name: "invalid", raw: map[string]interface{}{"code_execution": "yes"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := geminiOptionsFrom(types.GenerateOptions{
+				ProviderOptions: map[string]interface{}{"gemini": tt.raw},
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts == nil || opts.CodeExecution != tt.want {
+				t.Errorf("expected CodeExecution %v, got %+v", tt.want, opts)
+			}
+		})
+	}
+
+	opts, err := geminiOptionsFrom(types.GenerateOptions{})
+	if err != nil || opts != nil {
+		t.Errorf("expected no options, got %+v, %v", opts, err)
+	}
+}
+
+func TestPrepareStandardRequest_CodeExecution(t *testing.T) {
+	provider := NewGeminiProvider(types.ProviderConfig{Type: types.ProviderTypeGemini})
+
+	options := types.GenerateOptions{
+		Prompt: "Compute the 20th Fibonacci number",
+		ProviderOptions: map[string]interface{}{
+			"gemini": GeminiOptions{CodeExecution: true},
+		},
+	}
+	req := provider.prepareStandardRequest(options)
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	if !strings.Contains(string(data), `"tools":[{"code_execution":{}}]`) {
+		t.Errorf("expected a code_execution tool, got %s", data)
+	}
+
+	// Without the option no tools are sent
+	req = provider.prepareStandardRequest(types.GenerateOptions{Prompt: "Hello"})
+	if len(req.Tools) != 0 {
+		t.Errorf("expected no tools, got %+v", req.Tools)
+	}
+}
+
+func TestConvertGeminiCodeExecutions(t *testing.T) {
+	parts := []Part{
+		{Text: "Let me compute that."},
+		{ExecutableCode: &ExecutableCode{Language: "PYTHON", Code: "print(1 + 1)"}},
+		{CodeExecutionResult: &CodeExecutionResult{Outcome: "OUTCOME_OK", Output: "2\n"}},
+		{ExecutableCode: &ExecutableCode{Language: "PYTHON", Code: "1 / 0"}},
+	}
+
+	executions := convertGeminiCodeExecutions(parts)
+	if len(executions) != 2 {
+		t.Fatalf("expected 2 executions, got %d", len(executions))
+	}
+	want := types.CodeExecution{Language: "PYTHON", Code: "print(1 + 1)", Outcome: "OUTCOME_OK", Output: "2\n"}
+	if executions[0] != want {
+		t.Errorf("expected %+v, got %+v", want, executions[0])
+	}
+	if executions[1].Code != "1 / 0" || executions[1].Outcome != "" {
+		t.Errorf("expected pending execution, got %+v", executions[1])
+	}
+
+	// A result whose code arrived in an earlier chunk
+	executions = convertGeminiCodeExecutions([]Part{
+		{CodeExecutionResult: &CodeExecutionResult{Outcome: "OUTCOME_FAILED", Output: "ZeroDivisionError"}},
+	})
+	if len(executions) != 1 || executions[0].Code != "" || executions[0].Outcome != "OUTCOME_FAILED" {
+		t.Errorf("expected a result-only execution, got %+v", executions)
+	}
+
+	if executions := convertGeminiCodeExecutions([]Part{{Text: "hi"}}); executions != nil {
+		t.Errorf("expected no executions, got %+v", executions)
+	}
+}
+
+func TestGenerateChatCompletion_CodeExecution(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateContentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].CodeExecution == nil {
+			t.Errorf("expected a code_execution tool, got %+v", req.Tools)
+		}
+
+		response := GenerateContentResponse{
+			Candidates: []Candidate{{
+				Content: Content{
+					Role: "model",
+					Parts: []Part{
+						{ExecutableCode: &ExecutableCode{Language: "PYTHON", Code: "print(6 * 7)"}},
+						{CodeExecutionResult: &CodeExecutionResult{Outcome: "OUTCOME_OK", Output: "42\n"}},
+						{Text: "The answer is 42."},
+					},
+				},
+				FinishReason: "STOP",
+			}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer mockServer.Close()
+
+	provider := NewGeminiProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeGemini,
+		APIKey:  "test-api-key",
+		BaseURL: mockServer.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "What is 6 * 7?",
+		ProviderOptions: map[string]interface{}{
+			"gemini": map[string]interface{}{"code_execution": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateChatCompletion failed: %v", err)
+	}
+
+	chunk, err := stream.Next()
+	if err != nil {
+		t.Fatalf("failed to read chunk: %v", err)
+	}
+	if chunk.Content != "The answer is 42." {
+		t.Errorf("unexpected content %q", chunk.Content)
+	}
+	if len(chunk.CodeExecutions) != 1 {
+		t.Fatalf("expected 1 code execution, got %+v", chunk.CodeExecutions)
+	}
+	if chunk.CodeExecutions[0].Code != "print(6 * 7)" || chunk.CodeExecutions[0].Output != "42\n" {
+		t.Errorf("unexpected code execution %+v", chunk.CodeExecutions[0])
+	}
+}
+
+func TestGenerateChatCompletion_InvalidGeminiOptions(t *testing.T) {
+	provider := NewGeminiProvider(types.ProviderConfig{Type: types.ProviderTypeGemini, APIKey: "test-api-key"})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "Hello",
+		ProviderOptions: map[string]interface{}{
+			"gemini": map[string]interface{}{"code_execution": "yes"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid gemini provider options") {
+		t.Errorf("expected invalid options error, got %v", err)
+	}
+}
+
+func TestGeminiStream_CodeExecution(t *testing.T) {
+	events := []string{
+		`{"candidates":[{"content":{"role":"model","parts":[{"executableCode":{"language":"PYTHON","code":"print(6 * 7)"}}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"codeExecutionResult":{"outcome":"OUTCOME_OK","output":"42\n"}}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"The answer is 42."}]},"finishReason":"STOP"}]}`,
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+	}))
+	defer mockServer.Close()
+
+	provider := NewGeminiProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeGemini,
+		APIKey:  "test-api-key",
+		BaseURL: mockServer.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "What is 6 * 7?",
+		Stream: true,
+		ProviderOptions: map[string]interface{}{
+			"gemini": GeminiOptions{CodeExecution: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateChatCompletion failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	var executions []types.CodeExecution
+	var content strings.Builder
+	for {
+		chunk, err := stream.Next()
+		executions = append(executions, chunk.CodeExecutions...)
+		content.WriteString(chunk.Content)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+	}
+
+	if content.String() != "The answer is 42." {
+		t.Errorf("unexpected content %q", content.String())
+	}
+	if len(executions) != 2 {
+		t.Fatalf("expected code and result entries, got %+v", executions)
+	}
+	if executions[0].Code != "print(6 * 7)" || executions[1].Outcome != "OUTCOME_OK" || executions[1].Output != "42\n" {
+		t.Errorf("unexpected code executions %+v", executions)
+	}
+}
@@ -0,0 +1,27 @@
+package gemini
+
+import "strings"
+
+// splitGeminiThoughts returns the text of a response's parts, with the thought
+// summaries apart from the answer
+func splitGeminiThoughts(parts []Part) (text, thoughts string) {
+	var textBuilder, thoughtBuilder strings.Builder
+	for _, part := range parts {
+		if part.Text == "" {
+			continue
+		}
+		if part.Thought {
+			thoughtBuilder.WriteString(part.Text)
+		} else {
+			textBuilder.WriteString(part.Text)
+		}
+	}
+	return textBuilder.String(), thoughtBuilder.String()
+}
+
+// thoughtSignatureFrom returns the thought signature kept in a ToolCall's Metadata or
+// a ContentPart's Extra
+func thoughtSignatureFrom(metadata map[string]interface{}) string {
+	signature, _ := metadata[metadataKeyThoughtSignature].(string)
+	return signature
+}
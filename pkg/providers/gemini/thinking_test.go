@@ -0,0 +1,206 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestPrepareStandardRequest_Thinking(t *testing.T) {
+	provider := NewGeminiProvider(types.ProviderConfig{Type: types.ProviderTypeGemini})
+
+	req := provider.prepareStandardRequest(types.GenerateOptions{
+		Prompt: "Hello",
+		ProviderOptions: map[string]interface{}{
+			"gemini": GeminiOptions{}.WithThinking(2048),
+		},
+	})
+	thinking := req.GenerationConfig.ThinkingConfig
+	if thinking == nil || thinking.ThinkingBudget == nil || *thinking.ThinkingBudget != 2048 || !thinking.IncludeThoughts {
+		t.Fatalf("expected a 2048 token budget with thoughts, got %+v", thinking)
+	}
+
+	// A zero budget is sent, to turn thinking off
+	req = provider.prepareStandardRequest(types.GenerateOptions{
+		Prompt: "Hello",
+		ProviderOptions: map[string]interface{}{
+			"gemini": map[string]interface{}{"thinking": map[string]interface{}{"budget": 0}},
+		},
+	})
+	data, err := json.Marshal(req.GenerationConfig)
+	if err != nil {
+		t.Fatalf("failed to marshal generation config: %v", err)
+	}
+	if want := `"thinkingConfig":{"thinkingBudget":0}`; !strings.Contains(string(data), want) {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	req = provider.prepareStandardRequest(types.GenerateOptions{Prompt: "Hello"})
+	if req.GenerationConfig.ThinkingConfig != nil {
+		t.Errorf("expected no thinking config, got %+v", req.GenerationConfig.ThinkingConfig)
+	}
+}
+
+func TestParseStandardGeminiResponseMessage_Thoughts(t *testing.T) {
+	provider := NewGeminiProvider(types.ProviderConfig{Type: types.ProviderTypeGemini})
+
+	responseBody := []byte(`{
+		"candidates": [{
+			"content": {"role": "model", "parts": [
+				{"text": "The user wants the weather.", "thought": true},
+				{"functionCall": {"name": "get_weather", "args": {"city": "Paris"}}, "thoughtSignature": "sig-abc"}
+			]},
+			"finishReason": "STOP"
+		}],
+		"usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 5, "thoughtsTokenCount": 20, "totalTokenCount": 35}
+	}`)
+
+	message, usage, err := provider.parseStandardGeminiResponseMessage(responseBody, "gemini-2.5-flash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if message.Content != "" {
+		t.Errorf("expected no content, got %q", message.Content)
+	}
+	if message.ReasoningContent != "The user wants the weather." {
+		t.Errorf("unexpected reasoning content %q", message.ReasoningContent)
+	}
+	if len(message.ToolCalls) != 1 || message.ToolCalls[0].Metadata["thought_signature"] != "sig-abc" {
+		t.Fatalf("expected the thought signature on the tool call, got %+v", message.ToolCalls)
+	}
+	if usage.CompletionTokens != 25 || usage.TotalTokens != 35 {
+		t.Errorf("expected thoughts counted as completion tokens, got %+v", usage)
+	}
+
+	// The signature goes back with the call on the next turn
+	parts := convertMessageToGeminiParts(message)
+	if len(parts) != 1 || parts[0].FunctionCall == nil || parts[0].ThoughtSignature != "sig-abc" {
+		t.Errorf("expected a function call with its signature, got %+v", parts)
+	}
+}
+
+func TestGenerateChatCompletion_Thoughts(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := GenerateContentResponse{
+			Candidates: []Candidate{{
+				Content: Content{
+					Role: "model",
+					Parts: []Part{
+						{Text: "Six sevens are forty-two.", Thought: true},
+						{Text: "42"},
+					},
+				},
+				FinishReason: "STOP",
+			}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer mockServer.Close()
+
+	provider := NewGeminiProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeGemini,
+		APIKey:  "test-api-key",
+		BaseURL: mockServer.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "What is 6 * 7?",
+		ProviderOptions: map[string]interface{}{
+			"gemini": GeminiOptions{}.WithThinking(-1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateChatCompletion failed: %v", err)
+	}
+
+	chunk, err := stream.Next()
+	if err != nil {
+		t.Fatalf("failed to read chunk: %v", err)
+	}
+	if chunk.Content != "42" || chunk.ReasoningContent != "Six sevens are forty-two." {
+		t.Errorf("unexpected content %q and reasoning %q", chunk.Content, chunk.ReasoningContent)
+	}
+}
+
+func TestGeminiStream_Thoughts(t *testing.T) {
+	stream := newTestGeminiStream(
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"Thinking it over.","thought":true}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"Hello"}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":" there"}]},"finishReason":"STOP"}]}`,
+	)
+
+	var content, reasoning string
+	for {
+		chunk, err := stream.Next()
+		content += chunk.Content
+		reasoning += chunk.ReasoningContent
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+	}
+
+	if content != "Hello there" {
+		t.Errorf("unexpected content %q", content)
+	}
+	if reasoning != "Thinking it over." {
+		t.Errorf("unexpected reasoning %q", reasoning)
+	}
+}
+
+func TestGeminiStream_ToolCallsWithThoughtSignatures(t *testing.T) {
+	stream := newTestGeminiStream(
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"Checking the weather.","thought":true}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather","args":{"city":"Paris"}},"thoughtSignature":"sig-1"}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_time","args":{"city":"Paris"}}}]},"finishReason":"STOP"}]}`,
+	)
+
+	var toolCalls []types.ToolCall
+	var finishReason string
+	for {
+		chunk, err := stream.Next()
+		for _, choice := range chunk.Choices {
+			toolCalls = append(toolCalls, choice.Delta.ToolCalls...)
+			if choice.FinishReason != "" {
+				finishReason = choice.FinishReason
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+	}
+
+	if len(toolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", toolCalls)
+	}
+	if toolCalls[0].ID == toolCalls[1].ID {
+		t.Errorf("expected distinct tool call IDs, got %q twice", toolCalls[0].ID)
+	}
+	if toolCalls[0].Function.Name != "get_weather" || toolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected first tool call %+v", toolCalls[0])
+	}
+	if toolCalls[0].Metadata[metadataKeyThoughtSignature] != "sig-1" {
+		t.Errorf("expected the thought signature in metadata, got %v", toolCalls[0].Metadata)
+	}
+	if finishReason != types.FinishToolCalls {
+		t.Errorf("expected finish reason %q, got %q", types.FinishToolCalls, finishReason)
+	}
+
+	// The signature is sent back with the call on the next turn
+	parts := convertUniversalToolCallsToGeminiParts(toolCalls[:1])
+	if len(parts) != 1 || parts[0].ThoughtSignature != "sig-1" {
+		t.Errorf("expected the signature on the replayed call, got %+v", parts)
+	}
+}
@@ -24,6 +24,29 @@ type Part struct {
 	FileData         *FileData           `json:"fileData,omitempty"`
 	FunctionCall     *GeminiFunctionCall `json:"functionCall,omitempty"`
 	FunctionResponse *FunctionResponse   `json:"functionResponse,omitempty"`
+
+	// Returned by the model when the code_execution tool is enabled
+	ExecutableCode      *ExecutableCode      `json:"executableCode,omitempty"`
+	CodeExecutionResult *CodeExecutionResult `json:"codeExecutionResult,omitempty"`
+
+	// Thought marks a summary of the model's thinking, returned when thoughts are
+	// included. ThoughtSignature is an opaque record of that thinking, which must be
+	// sent back with the part it came on for the model to keep its reasoning across
+	// turns of tool use.
+	Thought          bool   `json:"thought,omitempty"`
+	ThoughtSignature string `json:"thoughtSignature,omitempty"`
+}
+
+// ExecutableCode is code generated by the model for the code_execution tool
+type ExecutableCode struct {
+	Language string `json:"language"`
+	Code     string `json:"code"`
+}
+
+// CodeExecutionResult is the result of running an ExecutableCode part
+type CodeExecutionResult struct {
+	Outcome string `json:"outcome"`
+	Output  string `json:"output,omitempty"`
 }
 
 // InlineData represents inline media data (base64)
@@ -52,6 +75,13 @@ type GenerationConfig struct {
 	MaxOutputTokens  int                    `json:"maxOutputTokens,omitempty"`
 	ResponseMimeType string                 `json:"responseMimeType,omitempty"` // For structured outputs
 	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`   // For structured outputs JSON schema
+	ThinkingConfig   *ThinkingConfig        `json:"thinkingConfig,omitempty"`
+}
+
+// ThinkingConfig controls the thinking of Gemini 2.5 and later models
+type ThinkingConfig struct {
+	ThinkingBudget  *int `json:"thinkingBudget,omitempty"` // Tokens; 0 turns thinking off, -1 lets the model decide
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
 }
 
 // GenerateContentResponse represents a response from generate content
@@ -64,6 +94,58 @@ type GenerateContentResponse struct {
 type Candidate struct {
 	Content      Content `json:"content"`
 	FinishReason string  `json:"finishReason,omitempty"`
+
+	// Sources of the candidate, see convertGeminiCitations
+	GroundingMetadata *GroundingMetadata `json:"groundingMetadata,omitempty"`
+	CitationMetadata  *CitationMetadata  `json:"citationMetadata,omitempty"`
+}
+
+// GroundingMetadata lists the sources of a response grounded with Google Search or
+// retrieval, and which segments of the response each supports
+type GroundingMetadata struct {
+	GroundingChunks   []GroundingChunk   `json:"groundingChunks,omitempty"`
+	GroundingSupports []GroundingSupport `json:"groundingSupports,omitempty"`
+	WebSearchQueries  []string           `json:"webSearchQueries,omitempty"`
+}
+
+// GroundingChunk is one source: a web page or a retrieved document
+type GroundingChunk struct {
+	Web              *GroundingSource `json:"web,omitempty"`
+	RetrievedContext *GroundingSource `json:"retrievedContext,omitempty"`
+}
+
+// GroundingSource identifies a grounding source
+type GroundingSource struct {
+	URI   string `json:"uri,omitempty"`
+	Title string `json:"title,omitempty"`
+	Text  string `json:"text,omitempty"` // Retrieved passage, for retrievedContext
+}
+
+// GroundingSupport links a segment of the response to the chunks supporting it
+type GroundingSupport struct {
+	Segment               GroundingSegment `json:"segment"`
+	GroundingChunkIndices []int            `json:"groundingChunkIndices,omitempty"`
+}
+
+// GroundingSegment is a span of the response; indexes are byte offsets
+type GroundingSegment struct {
+	StartIndex int    `json:"startIndex,omitempty"`
+	EndIndex   int    `json:"endIndex,omitempty"`
+	Text       string `json:"text,omitempty"`
+}
+
+// CitationMetadata lists sources the model recited from
+type CitationMetadata struct {
+	CitationSources []CitationSource `json:"citationSources,omitempty"`
+}
+
+// CitationSource is a source recited in a span of the response
+type CitationSource struct {
+	StartIndex int    `json:"startIndex,omitempty"`
+	EndIndex   int    `json:"endIndex,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	Title      string `json:"title,omitempty"`
+	License    string `json:"license,omitempty"`
 }
 
 // UsageMetadata represents usage metadata
@@ -71,6 +153,10 @@ type UsageMetadata struct {
 	PromptTokenCount     int `json:"promptTokenCount"`
 	CandidatesTokenCount int `json:"candidatesTokenCount"`
 	TotalTokenCount      int `json:"totalTokenCount"`
+	ThoughtsTokenCount   int `json:"thoughtsTokenCount,omitempty"`
+
+	// Prompt tokens read from cached content, included in PromptTokenCount
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
 }
 
 // CloudCode API types
@@ -225,9 +311,17 @@ type GeminiModelData struct {
 
 // GeminiTool represents a tool available to the model
 type GeminiTool struct {
-	FunctionDeclarations []GeminiFunctionDeclaration `json:"function_declarations"`
+	FunctionDeclarations []GeminiFunctionDeclaration `json:"function_declarations,omitempty"`
+	CodeExecution        *GeminiCodeExecution        `json:"code_execution,omitempty"`
+	GoogleSearch         *GeminiGoogleSearch         `json:"google_search,omitempty"`
 }
 
+// GeminiCodeExecution enables the built-in code execution tool. It has no settings.
+type GeminiCodeExecution struct{}
+
+// GeminiGoogleSearch enables grounding with Google Search. It has no settings.
+type GeminiGoogleSearch struct{}
+
 // GeminiFunctionDeclaration represents a function declaration in Gemini format
 type GeminiFunctionDeclaration struct {
 	Name        string       `json:"name"`
@@ -19,7 +19,9 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -62,7 +64,13 @@ func NewOllamaProvider(config types.ProviderConfig) *OllamaProvider {
 
 	// Create HTTP client using internal/http package
 	httpClient := pkghttp.NewHTTPClient(pkghttp.HTTPClientConfig{
-		Timeout: timeout,
+		Timeout:          timeout,
+		ConnectTimeout:   mergedConfig.ConnectTimeout,
+		RequestTimeout:   mergedConfig.RequestTimeout,
+		Headers:          mergedConfig.Headers,
+		UserAgent:        mergedConfig.UserAgent,
+		CompressRequests: mergedConfig.CompressRequests,
+		WrapTransport:    mergedConfig.WrapTransport,
 	})
 
 	// Extract the underlying http.Client for compatibility with existing code
@@ -211,6 +219,12 @@ func (p *OllamaProvider) SupportsToolCalling() bool {
 }
 
 // SupportsStreaming returns whether the provider supports streaming
+// SupportedParameters implements types.ParameterSupportReporter. Ollama generates one response
+// per request and has no parallel_tool_calls, store or reasoning_effort.
+func (p *OllamaProvider) SupportedParameters(model string) types.ParameterSupport {
+	return common.SingleChoiceParameters(p, model)
+}
+
 func (p *OllamaProvider) SupportsStreaming() bool {
 	return true
 }
@@ -291,9 +305,8 @@ func (p *OllamaProvider) fetchModelsFromAPI(ctx context.Context) ([]types.Model,
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOllama, "failed to fetch models").
-			WithOperation("fetch_models").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeOllama, "failed to fetch models", err).
+			WithOperation("fetch_models")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -478,9 +491,8 @@ func (p *OllamaProvider) GetRunningModels(ctx context.Context) ([]types.RunningM
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOllama, "failed to fetch running models").
-			WithOperation("get_running_models").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeOllama, "failed to fetch running models", err).
+			WithOperation("get_running_models")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -543,6 +555,9 @@ type ollamaChatRequest struct {
 	Tools    []ollamaTool           `json:"tools,omitempty"`
 	Format   interface{}            `json:"format,omitempty"` // Can be "json" string or JSON schema object
 	Options  map[string]interface{} `json:"options,omitempty"`
+
+	// KeepAlive is how long the model stays loaded after the request (e.g. "5m")
+	KeepAlive string `json:"keep_alive,omitempty"`
 }
 
 // ollamaChatMessage represents a message in the Ollama chat API
@@ -600,6 +615,10 @@ func (p *OllamaProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+
 	// Initialize request tracking
 	p.IncrementRequestCount()
 	startTime := time.Now()
@@ -614,6 +633,18 @@ func (p *OllamaProvider) GenerateChatCompletion(
 	// Build the request
 	request := p.buildOllamaChatRequest(options)
 
+	// Validate before any network I/O
+	if err := common.ValidateGenerateOptions(request.Model, options); err != nil {
+		return nil, err
+	}
+	ctx = middleware.WithModel(ctx, request.Model)
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return nil, err
+	}
+	if err := validateOllamaOptions(options); err != nil {
+		return nil, err
+	}
+
 	// Determine the base URL
 	baseURL := p.config.BaseURL
 	if baseURL == "" {
@@ -628,6 +659,15 @@ func (p *OllamaProvider) GenerateChatCompletion(
 
 	// Make the API call
 	stream, err := p.makeStreamingAPICall(ctx, url, request)
+	if err != nil && isModelNotFound(err) && !p.isCloudEndpoint() {
+		if opts, _ := ollamaOptionsFrom(options); opts != nil && opts.AutoPull {
+			if pullErr := p.autoPull(ctx, request.Model, opts); pullErr != nil {
+				p.RecordError(pullErr)
+				return nil, pullErr
+			}
+			stream, err = p.makeStreamingAPICall(ctx, url, request)
+		}
+	}
 	if err != nil {
 		p.RecordError(err)
 		return nil, err
@@ -662,10 +702,11 @@ func (p *OllamaProvider) buildOllamaChatRequest(options types.GenerateOptions) o
 
 	// Build request
 	request := ollamaChatRequest{
-		Model:    model,
-		Messages: messages,
-		Stream:   true, // Always stream for real-time responses
-		Options:  optionsMap,
+		Model:     model,
+		Messages:  messages,
+		Stream:    true, // Always stream for real-time responses
+		Options:   optionsMap,
+		KeepAlive: p.keepAliveFor(options),
 	}
 
 	// Convert tools if provided
@@ -822,9 +863,8 @@ func (p *OllamaProvider) makeHTTPStreamRequest(ctx context.Context, url string,
 	// Make the request
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOllama, "request failed").
-			WithOperation("chat_completion").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeOllama, "request failed", err).
+			WithOperation("chat_completion")
 	}
 
 	// Check status code
@@ -845,7 +885,8 @@ func (p *OllamaProvider) makeHTTPStreamRequest(ctx context.Context, url string,
 		case http.StatusTooManyRequests:
 			return nil, types.NewRateLimitError(types.ProviderTypeOllama, 0).
 				WithOperation("chat_completion").
-				WithStatusCode(resp.StatusCode)
+				WithStatusCode(resp.StatusCode).
+				WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
 		default:
 			if resp.StatusCode >= 500 {
 				return nil, types.NewServerError(types.ProviderTypeOllama, resp.StatusCode, string(body)).
@@ -956,9 +997,8 @@ func (p *OllamaProvider) GenerateEmbeddings(ctx context.Context, model string, t
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		p.RecordError(types.NewNetworkError(types.ProviderTypeOllama, "request failed"))
-		return nil, types.NewNetworkError(types.ProviderTypeOllama, "request failed").
-			WithOperation("generate_embeddings").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(types.ProviderTypeOllama, "request failed", err).
+			WithOperation("generate_embeddings")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -985,7 +1025,8 @@ func (p *OllamaProvider) GenerateEmbeddings(ctx context.Context, model string, t
 		case http.StatusTooManyRequests:
 			err := types.NewRateLimitError(types.ProviderTypeOllama, 0).
 				WithOperation("generate_embeddings").
-				WithStatusCode(resp.StatusCode)
+				WithStatusCode(resp.StatusCode).
+				WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
 			p.RecordError(err)
 			return nil, err
 		default:
@@ -1041,6 +1082,12 @@ func (p *OllamaProvider) GetMetrics() types.ProviderMetrics {
 	return p.BaseProvider.GetMetrics()
 }
 
+// Warmup lists the models of the Ollama server into the model cache, which also checks
+// the server is reachable before the first request
+func (p *OllamaProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
 // TestConnectivity performs a lightweight connectivity test
 // Results are cached for 30 seconds by default to prevent hammering the API during rapid health checks
 func (p *OllamaProvider) TestConnectivity(ctx context.Context) error {
@@ -1157,14 +1204,13 @@ type ollamaModelRequest struct {
 
 // ollamaProgressResponse represents a streaming progress response from pull/push operations
 type ollamaProgressResponse struct {
-	Status    string `json:"status"`
-	Digest    string `json:"digest,omitempty"`
-	Total     int64  `json:"total,omitempty"`
-	Completed int64  `json:"completed,omitempty"`
+	PullProgress
+	Error string `json:"error,omitempty"`
 }
 
-// executeStreamingModelOperation executes a streaming model operation (pull/push)
-func (p *OllamaProvider) executeStreamingModelOperation(ctx context.Context, model, endpoint, operation string) error {
+// executeStreamingModelOperation executes a streaming model operation (pull/push),
+// passing each progress update to progress when it is non-nil
+func (p *OllamaProvider) executeStreamingModelOperation(ctx context.Context, model, endpoint, operation string, progress func(PullProgress)) error {
 	// Check if this is a cloud endpoint
 	if p.isCloudEndpoint() {
 		return types.NewInvalidRequestError(types.ProviderTypeOllama, "model management operations are not supported on cloud endpoints").
@@ -1203,12 +1249,16 @@ func (p *OllamaProvider) executeStreamingModelOperation(ctx context.Context, mod
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 
+	// Downloads take far longer than the client timeout allows for a request, so
+	// model operations are bounded by ctx instead
+	client := *p.httpClient
+	client.Timeout = 0
+
 	// Make the request
-	resp, err := p.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOllama, fmt.Sprintf("%s request failed", endpoint)).
-			WithOperation(operation).
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeOllama, fmt.Sprintf("%s request failed", endpoint), err).
+			WithOperation(operation)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -1236,10 +1286,19 @@ func (p *OllamaProvider) executeStreamingModelOperation(ctx context.Context, mod
 			continue
 		}
 
+		// Ollama reports failures mid-stream, after the 200 status
+		if progressResp.Error != "" {
+			return types.NewServerError(types.ProviderTypeOllama, resp.StatusCode, progressResp.Error).
+				WithOperation(operation)
+		}
+
 		// Log progress
 		if p.BaseProvider != nil {
 			p.BaseProvider.LogRequest(logPrefix, "progress", nil, progressResp)
 		}
+		if progress != nil {
+			progress(progressResp.PullProgress)
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -1254,13 +1313,20 @@ func (p *OllamaProvider) executeStreamingModelOperation(ctx context.Context, mod
 // PullModel pulls a model from the Ollama registry
 // This operation is only supported on local Ollama instances
 func (p *OllamaProvider) PullModel(ctx context.Context, model string) error {
-	return p.executeStreamingModelOperation(ctx, model, "pull", "pull_model")
+	return p.executeStreamingModelOperation(ctx, model, "pull", "pull_model", nil)
+}
+
+// PullModelWithProgress pulls a model from the Ollama registry, calling progress for
+// each update Ollama streams, and returns once the pull has completed or failed.
+// This operation is only supported on local Ollama instances
+func (p *OllamaProvider) PullModelWithProgress(ctx context.Context, model string, progress func(PullProgress)) error {
+	return p.executeStreamingModelOperation(ctx, model, "pull", "pull_model", progress)
 }
 
 // PushModel pushes a model to the Ollama registry
 // This operation is only supported on local Ollama instances
 func (p *OllamaProvider) PushModel(ctx context.Context, model string) error {
-	return p.executeStreamingModelOperation(ctx, model, "push", "push_model")
+	return p.executeStreamingModelOperation(ctx, model, "push", "push_model", nil)
 }
 
 // ollamaDeleteRequest represents a request to delete a model
@@ -1311,9 +1377,8 @@ func (p *OllamaProvider) DeleteModel(ctx context.Context, model string) error {
 	// Make the request
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOllama, "delete request failed").
-			WithOperation("delete_model").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeOllama, "delete request failed", err).
+			WithOperation("delete_model")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -1384,9 +1449,8 @@ func (p *OllamaProvider) CopyModel(ctx context.Context, source, destination stri
 	// Make the request
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOllama, "copy request failed").
-			WithOperation("copy_model").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeOllama, "copy request failed", err).
+			WithOperation("copy_model")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -1464,9 +1528,8 @@ func (p *OllamaProvider) CreateModel(ctx context.Context, name string, modelfile
 	// Make the request
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOllama, "create request failed").
-			WithOperation("create_model").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeOllama, "create request failed", err).
+			WithOperation("create_model")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -0,0 +1,183 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// OllamaOptions configures Ollama-specific request features.
+// Pass it through GenerateOptions.ProviderOptions under the "ollama" key:
+//
+//	options := types.GenerateOptions{
+//	    Prompt: "Hello",
+//	    ProviderOptions: map[string]interface{}{
+//	        "ollama": ollama.OllamaOptions{KeepAlive: "30m"},
+//	    },
+//	}
+type OllamaOptions struct {
+	// KeepAlive is how long the model stays loaded after the request, as a Go
+	// duration: "30m", "0s" to unload it right away, or a negative duration such as
+	// "-1s" to keep it loaded indefinitely. It overrides the provider's "keep_alive"
+	// config; when neither is set, Ollama's default (5 minutes) applies.
+	KeepAlive string `json:"keep_alive,omitempty"`
+
+	// AutoPull pulls the requested model when Ollama reports it is not downloaded,
+	// then sends the request again. Pulls are bounded by MaxPullSize and PullTimeout,
+	// and are not available on cloud endpoints.
+	AutoPull bool `json:"auto_pull,omitempty"`
+
+	// MaxPullSize stops an automatic pull once the model's layers add up to more
+	// than this many bytes. 0 means DefaultMaxPullSize; negative means no limit.
+	MaxPullSize int64 `json:"max_pull_size,omitempty"`
+
+	// PullTimeout bounds how long an automatic pull may take. 0 means
+	// DefaultPullTimeout; negative means no limit.
+	PullTimeout time.Duration `json:"pull_timeout,omitempty"`
+
+	// OnPullProgress is called with each progress update of an automatic pull
+	OnPullProgress func(PullProgress) `json:"-"`
+}
+
+// Defaults bounding automatic pulls, so a large model is not downloaded unnoticed
+const (
+	DefaultMaxPullSize = 8 << 30 // 8 GiB
+	DefaultPullTimeout = 30 * time.Minute
+)
+
+// ollamaOptionsFrom extracts OllamaOptions from GenerateOptions.ProviderOptions.
+// Accepts the struct, a pointer to it, or a generic map (e.g. decoded from JSON config).
+func ollamaOptionsFrom(options types.GenerateOptions) (*OllamaOptions, error) {
+	raw, ok := options.ProviderOptions[string(types.ProviderTypeOllama)]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case OllamaOptions:
+		return &v, nil
+	case *OllamaOptions:
+		return v, nil
+	default:
+		// Round-trip through JSON to support map[string]interface{} and similar
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ollama provider options: %w", err)
+		}
+		var opts OllamaOptions
+		if err := json.Unmarshal(data, &opts); err != nil {
+			return nil, fmt.Errorf("invalid ollama provider options: %w", err)
+		}
+		return &opts, nil
+	}
+}
+
+// validateOllamaOptions rejects invalid provider options before any network I/O
+func validateOllamaOptions(options types.GenerateOptions) error {
+	opts, err := ollamaOptionsFrom(options)
+	if err != nil {
+		return types.NewInvalidRequestError(types.ProviderTypeOllama, err.Error()).
+			WithOperation("chat_completion")
+	}
+	if opts == nil || opts.KeepAlive == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(opts.KeepAlive); err != nil {
+		return types.NewInvalidRequestError(types.ProviderTypeOllama, fmt.Sprintf("invalid keep_alive %q: %v", opts.KeepAlive, err)).
+			WithOperation("chat_completion")
+	}
+	return nil
+}
+
+// keepAliveFor returns the keep_alive to send for a request: the per-request option,
+// then the provider's "keep_alive" config
+func (p *OllamaProvider) keepAliveFor(options types.GenerateOptions) string {
+	if opts, err := ollamaOptionsFrom(options); err == nil && opts != nil && opts.KeepAlive != "" {
+		return opts.KeepAlive
+	}
+	if p.config.ProviderConfig != nil {
+		if keepAlive, ok := p.config.ProviderConfig["keep_alive"].(string); ok {
+			return keepAlive
+		}
+	}
+	return ""
+}
+
+// PullProgress reports the progress of a model pull or push, as streamed by Ollama.
+// Total and Completed are byte counts of the layer identified by Digest while it is
+// downloading; they are zero for other statuses such as "pulling manifest".
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+}
+
+// isModelNotFound reports whether err is Ollama's 404 for a model that is not downloaded
+func isModelNotFound(err error) bool {
+	var providerErr *types.ProviderError
+	return errors.As(err, &providerErr) && providerErr.Code == types.ErrCodeNotFound
+}
+
+// autoPull pulls model within the size and time limits of opts, reporting progress to
+// opts.OnPullProgress. A pull that fails or exceeds a limit returns an error naming
+// the model, rather than the completion's 404.
+func (p *OllamaProvider) autoPull(ctx context.Context, model string, opts *OllamaOptions) error {
+	maxSize := opts.MaxPullSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxPullSize
+	}
+	timeout := opts.PullTimeout
+	if timeout == 0 {
+		timeout = DefaultPullTimeout
+	}
+
+	pullCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if timeout > 0 {
+		pullCtx, cancel = context.WithTimeout(pullCtx, timeout)
+		defer cancel()
+	}
+
+	// Ollama reports each layer's size separately as it starts downloading
+	layerSizes := make(map[string]int64)
+	var total int64
+	var guardErr error
+
+	log.Printf("[Ollama] Model %s not found locally, pulling it", model)
+	err := p.PullModelWithProgress(pullCtx, model, func(progress PullProgress) {
+		if guardErr != nil {
+			return
+		}
+		if progress.Digest != "" && progress.Total > layerSizes[progress.Digest] {
+			total += progress.Total - layerSizes[progress.Digest]
+			layerSizes[progress.Digest] = progress.Total
+		}
+		if maxSize > 0 && total > maxSize {
+			guardErr = fmt.Errorf("model is larger than the %d byte limit", maxSize)
+			cancel()
+			return
+		}
+		if opts.OnPullProgress != nil {
+			opts.OnPullProgress(progress)
+		}
+	})
+
+	switch {
+	case guardErr != nil:
+		err = guardErr
+	case err != nil && errors.Is(pullCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil:
+		err = fmt.Errorf("pull did not finish within %v", timeout)
+	}
+	if err != nil {
+		return types.NewNotFoundError(types.ProviderTypeOllama, fmt.Sprintf("model %q is not available locally and auto-pull failed: %v", model, err)).
+			WithOperation("auto_pull").
+			WithOriginalErr(err)
+	}
+	return nil
+}
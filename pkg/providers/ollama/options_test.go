@@ -0,0 +1,246 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOllamaProvider_KeepAlive(t *testing.T) {
+	var keepAlive interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		keepAlive = req["keep_alive"]
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"llama3.1:8b","message":{"role":"assistant","content":"ok"},"done":true}` + "\n"))
+	}))
+	defer server.Close()
+
+	generate := func(t *testing.T, provider *OllamaProvider, providerOptions map[string]interface{}) error {
+		t.Helper()
+		stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+			Model:           "llama3.1:8b",
+			Messages:        []types.ChatMessage{{Role: "user", Content: "hi"}},
+			ProviderOptions: providerOptions,
+		})
+		if err != nil {
+			return err
+		}
+		for {
+			if chunk, err := stream.Next(); err != nil || chunk.Done {
+				break
+			}
+		}
+		return stream.Close()
+	}
+
+	configured := NewOllamaProvider(types.ProviderConfig{
+		Type:           types.ProviderTypeOllama,
+		BaseURL:        server.URL,
+		ProviderConfig: map[string]interface{}{"keep_alive": "10m"},
+	})
+
+	t.Run("config default", func(t *testing.T) {
+		require.NoError(t, generate(t, configured, nil))
+		assert.Equal(t, "10m", keepAlive)
+	})
+
+	t.Run("per-request option overrides config", func(t *testing.T) {
+		require.NoError(t, generate(t, configured, map[string]interface{}{
+			"ollama": OllamaOptions{KeepAlive: "-1s"},
+		}))
+		assert.Equal(t, "-1s", keepAlive)
+	})
+
+	t.Run("map options", func(t *testing.T) {
+		require.NoError(t, generate(t, configured, map[string]interface{}{
+			"ollama": map[string]interface{}{"keep_alive": "0s"},
+		}))
+		assert.Equal(t, "0s", keepAlive)
+	})
+
+	t.Run("omitted when unset", func(t *testing.T) {
+		keepAlive = "unchanged"
+		provider := NewOllamaProvider(types.ProviderConfig{Type: types.ProviderTypeOllama, BaseURL: server.URL})
+		require.NoError(t, generate(t, provider, nil))
+		assert.Nil(t, keepAlive)
+	})
+
+	t.Run("invalid duration is rejected", func(t *testing.T) {
+		err := generate(t, configured, map[string]interface{}{
+			"ollama": OllamaOptions{KeepAlive: "forever"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "keep_alive")
+	})
+}
+
+func TestOllamaProvider_PullModelWithProgress(t *testing.T) {
+	t.Run("reports progress", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/pull", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			for _, line := range []string{
+				`{"status":"pulling manifest"}`,
+				`{"status":"downloading","digest":"sha256:abcd","total":1000,"completed":500}`,
+				`{"status":"success"}`,
+			} {
+				_, _ = w.Write([]byte(line + "\n"))
+			}
+		}))
+		defer server.Close()
+
+		provider := NewOllamaProvider(types.ProviderConfig{Type: types.ProviderTypeOllama, BaseURL: server.URL})
+
+		var updates []PullProgress
+		err := provider.PullModelWithProgress(context.Background(), "llama3.1:8b", func(progress PullProgress) {
+			updates = append(updates, progress)
+		})
+		require.NoError(t, err)
+
+		require.Len(t, updates, 3)
+		assert.Equal(t, "pulling manifest", updates[0].Status)
+		assert.Equal(t, PullProgress{Status: "downloading", Digest: "sha256:abcd", Total: 1000, Completed: 500}, updates[1])
+		assert.Equal(t, "success", updates[2].Status)
+	})
+
+	t.Run("returns errors reported mid-stream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"pulling manifest"}` + "\n"))
+			_, _ = w.Write([]byte(`{"error":"pull model manifest: file does not exist"}` + "\n"))
+		}))
+		defer server.Close()
+
+		provider := NewOllamaProvider(types.ProviderConfig{Type: types.ProviderTypeOllama, BaseURL: server.URL})
+
+		err := provider.PullModelWithProgress(context.Background(), "missing-model", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "file does not exist")
+	})
+}
+
+// autoPullServer serves /api/chat with a 404 until the model has been pulled, and
+// /api/pull with the given progress lines
+type autoPullServer struct {
+	pullLines []string
+	hangPull  bool
+	pulled    bool
+	chats     int
+}
+
+func (s *autoPullServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/api/chat":
+		s.chats++
+		if !s.pulled {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model \"llama3.1:8b\" not found, try pulling it first"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"model":"llama3.1:8b","message":{"role":"assistant","content":"ok"},"done":true}` + "\n"))
+	case "/api/pull":
+		if s.hangPull {
+			// The server only notices the client going away once the body is read
+			_, _ = io.ReadAll(r.Body)
+			<-r.Context().Done()
+			return
+		}
+		for _, line := range s.pullLines {
+			_, _ = w.Write([]byte(line + "\n"))
+		}
+		s.pulled = true
+	}
+}
+
+func TestOllamaProvider_AutoPull(t *testing.T) {
+	successfulPull := []string{
+		`{"status":"pulling manifest"}`,
+		`{"status":"downloading","digest":"sha256:a","total":600,"completed":600}`,
+		`{"status":"downloading","digest":"sha256:b","total":600,"completed":600}`,
+		`{"status":"success"}`,
+	}
+
+	generate := func(serverHandler http.Handler, opts OllamaOptions) (types.ChatCompletionStream, error) {
+		server := httptest.NewServer(serverHandler)
+		t.Cleanup(server.Close)
+
+		provider := NewOllamaProvider(types.ProviderConfig{Type: types.ProviderTypeOllama, BaseURL: server.URL})
+		return provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+			Model:           "llama3.1:8b",
+			Messages:        []types.ChatMessage{{Role: "user", Content: "hi"}},
+			ProviderOptions: map[string]interface{}{"ollama": opts},
+		})
+	}
+
+	t.Run("pulls the missing model and retries", func(t *testing.T) {
+		server := &autoPullServer{pullLines: successfulPull}
+		var statuses []string
+
+		stream, err := generate(server, OllamaOptions{
+			AutoPull:       true,
+			OnPullProgress: func(progress PullProgress) { statuses = append(statuses, progress.Status) },
+		})
+		require.NoError(t, err)
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "ok", chunk.Content)
+		_ = stream.Close()
+
+		assert.Equal(t, 2, server.chats)
+		assert.Equal(t, []string{"pulling manifest", "downloading", "downloading", "success"}, statuses)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := &autoPullServer{pullLines: successfulPull}
+
+		_, err := generate(server, OllamaOptions{})
+		require.Error(t, err)
+		assert.False(t, server.pulled)
+		assert.Equal(t, 1, server.chats)
+	})
+
+	t.Run("size limit stops the pull", func(t *testing.T) {
+		server := &autoPullServer{pullLines: successfulPull}
+		var updates int
+
+		_, err := generate(server, OllamaOptions{
+			AutoPull:       true,
+			MaxPullSize:    1000,
+			OnPullProgress: func(PullProgress) { updates++ },
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `model "llama3.1:8b" is not available locally and auto-pull failed`)
+		assert.Contains(t, err.Error(), "1000 byte limit")
+		assert.Equal(t, 2, updates, "no progress is reported past the limit")
+		assert.Equal(t, 1, server.chats)
+	})
+
+	t.Run("time limit stops the pull", func(t *testing.T) {
+		server := &autoPullServer{hangPull: true}
+
+		_, err := generate(server, OllamaOptions{AutoPull: true, PullTimeout: 50 * time.Millisecond})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pull did not finish within 50ms")
+	})
+
+	t.Run("pull failure is reported clearly", func(t *testing.T) {
+		server := &autoPullServer{pullLines: []string{`{"error":"pull model manifest: file does not exist"}`}}
+
+		_, err := generate(server, OllamaOptions{AutoPull: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "auto-pull failed")
+		assert.Contains(t, err.Error(), "file does not exist")
+	})
+}
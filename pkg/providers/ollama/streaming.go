@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -27,6 +28,7 @@ const (
 // It supports both native Ollama newline-delimited JSON format and OpenAI-compatible SSE format.
 type OllamaStream struct {
 	reader   *bufio.Reader
+	events   *streaming.SSEScanner // Reads the OpenAI endpoint's SSE
 	body     io.ReadCloser
 	done     bool
 	model    string
@@ -67,7 +69,9 @@ func (s *OllamaStream) GetMetadata() StreamMetadata {
 
 // Next returns the next chunk from the stream.
 // Returns io.EOF when the stream is complete.
-func (s *OllamaStream) Next() (types.ChatCompletionChunk, error) {
+func (s *OllamaStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer streaming.RecoverStreamPanic(types.ProviderTypeOllama, &chunk, &err)
+
 	if s.done {
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
@@ -76,17 +80,18 @@ func (s *OllamaStream) Next() (types.ChatCompletionChunk, error) {
 	select {
 	case <-s.ctx.Done():
 		s.done = true
-		return types.ChatCompletionChunk{Done: true}, s.ctx.Err()
+		return types.ChatCompletionChunk{Done: true}, types.StreamCancelError(s.ctx, s.ctx.Err())
 	default:
 	}
 
 	// Read based on endpoint format
 	switch s.endpoint {
 	case StreamEndpointOpenAI:
-		return s.nextOpenAI()
+		chunk, err = s.nextOpenAI()
 	default:
-		return s.nextOllama()
+		chunk, err = s.nextOllama()
 	}
+	return chunk, types.StreamCancelError(s.ctx, err)
 }
 
 // nextOllama reads from native Ollama endpoint (newline-delimited JSON)
@@ -162,15 +167,21 @@ func (s *OllamaStream) nextOllama() (types.ChatCompletionChunk, error) {
 			},
 		}
 	}
+	if streaming.IsHeartbeat(chunk) {
+		return s.nextOllama()
+	}
 
 	return chunk, nil
 }
 
 // nextOpenAI reads from OpenAI-compatible endpoint (SSE format)
 func (s *OllamaStream) nextOpenAI() (types.ChatCompletionChunk, error) {
+	if s.events == nil {
+		s.events = streaming.NewSSEScanner(s.reader, streaming.SSEScannerOptions{DoneSentinel: streaming.SSEDone})
+	}
+
 	for {
-		// Read next line
-		line, err := s.reader.ReadBytes('\n')
+		event, err := s.events.Next()
 		if err != nil {
 			if err == io.EOF {
 				s.done = true
@@ -179,30 +190,9 @@ func (s *OllamaStream) nextOpenAI() (types.ChatCompletionChunk, error) {
 			return types.ChatCompletionChunk{}, fmt.Errorf("failed to read stream: %w", err)
 		}
 
-		// Handle SSE format: "data: {...}"
-		line = bytes.TrimSpace(line)
-		if len(line) == 0 {
-			continue // Skip empty lines
-		}
-
-		// Check for SSE data prefix
-		if !bytes.HasPrefix(line, []byte("data: ")) {
-			continue // Skip non-data lines
-		}
-
-		// Extract JSON data
-		data := bytes.TrimPrefix(line, []byte("data: "))
-		data = bytes.TrimSpace(data)
-
-		// Check for [DONE] marker
-		if bytes.Equal(data, []byte("[DONE]")) {
-			s.done = true
-			return types.ChatCompletionChunk{Done: true}, io.EOF
-		}
-
 		// Parse OpenAI-compatible response
 		var resp openAIStreamResponse
-		if err := json.Unmarshal(data, &resp); err != nil {
+		if err := json.Unmarshal([]byte(event.Data), &resp); err != nil {
 			// Skip malformed chunks
 			continue
 		}
@@ -269,9 +259,10 @@ func (s *OllamaStream) nextOpenAI() (types.ChatCompletionChunk, error) {
 
 			chunk.Choices = []types.ChatChoice{
 				{
-					Index:        choice.Index,
-					Delta:        deltaMsg,
-					FinishReason: choice.FinishReason,
+					Index:           choice.Index,
+					Delta:           deltaMsg,
+					FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOllama, choice.FinishReason),
+					RawFinishReason: choice.FinishReason,
 				},
 			}
 
@@ -293,6 +284,9 @@ func (s *OllamaStream) nextOpenAI() (types.ChatCompletionChunk, error) {
 				}
 			}
 		}
+		if streaming.IsHeartbeat(chunk) {
+			continue
+		}
 
 		return chunk, nil
 	}
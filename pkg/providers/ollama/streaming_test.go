@@ -340,8 +340,10 @@ func TestOllamaStream_ContextCancellation(t *testing.T) {
 	// Next read should fail with context error
 	_, err = stream.Next()
 	assert.Error(t, err)
-	// Should be either context.DeadlineExceeded or context.Canceled
-	assert.True(t, err == context.DeadlineExceeded || err == context.Canceled)
+	var cancelled *types.StreamCancelledError
+	require.ErrorAs(t, err, &cancelled)
+	assert.Equal(t, types.StreamCancelledByTimeout, cancelled.Reason)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
 
 	// Close stream
 	err = stream.Close()
@@ -0,0 +1,12 @@
+package openai
+
+import (
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Capabilities implements types.CapabilitiesReporter from the models.dev defaults of
+// the provider type, so vision and structured output are reported per model.
+func (p *OpenAIProvider) Capabilities(model string) types.Capabilities {
+	return common.ModelCapabilities(p, model)
+}
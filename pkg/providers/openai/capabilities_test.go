@@ -0,0 +1,32 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAIProvider_Capabilities(t *testing.T) {
+	supportsJSONSchema := true
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeOpenAI,
+		APIKey: "sk-test",
+		ModelCapabilities: map[string]types.ModelCapabilityOverride{
+			"gpt-4": {SupportsJSONSchema: &supportsJSONSchema},
+		},
+	})
+
+	assert.Equal(t, types.Capabilities{Tools: true, Vision: true, JSONSchema: true, Streaming: true},
+		types.ProviderCapabilities(provider, "gpt-4o"))
+
+	// gpt-4 takes no images and, per the defaults, no JSON schema unless overridden
+	legacy := provider.Capabilities("gpt-4")
+	assert.False(t, legacy.Vision)
+	assert.False(t, legacy.JSONSchema)
+	assert.True(t, types.ProviderCapabilities(provider, "gpt-4").JSONSchema)
+
+	// Models missing from the defaults keep the provider-wide assumptions
+	assert.Equal(t, types.Capabilities{Tools: true, Vision: true, JSONSchema: true, Streaming: true},
+		provider.Capabilities("my-fine-tune"))
+}
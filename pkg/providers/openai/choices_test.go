@@ -0,0 +1,74 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_MultipleChoices(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"gpt-4o","choices":[
+			{"index":0,"message":{"role":"assistant","content":"Red"},"finish_reason":"stop"},
+			{"index":1,"message":{"role":"assistant","content":"Blue"},"finish_reason":"length"},
+			{"index":2,"message":{"role":"assistant","content":"Green"},"finish_reason":"stop"}
+		],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	options, err := types.NewOptionsBuilder().WithModel("gpt-4o").WithPrompt("Name a color").WithN(3).Build()
+	require.NoError(t, err)
+	stream, err := provider.GenerateChatCompletion(context.Background(), options)
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), sent["n"])
+
+	assert.Equal(t, "Red", chunk.Content)
+	require.Len(t, chunk.Choices, 3)
+	for i, want := range []string{"Red", "Blue", "Green"} {
+		assert.Equal(t, i, chunk.Choices[i].Index)
+		assert.Equal(t, want, chunk.Choices[i].Message.Content)
+		assert.Nil(t, chunk.Choices[i].Message.Metadata)
+	}
+	assert.Equal(t, types.FinishLength, chunk.Choices[1].FinishReason)
+	assert.Equal(t, 8, chunk.Usage.TotalTokens)
+}
+
+func TestOpenAIProvider_SingleChoiceOmitsN(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI, APIKey: "sk-test-key"})
+
+	request := provider.buildOpenAIRequest(types.GenerateOptions{Prompt: "Hi", N: 1})
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), `"n"`)
+}
+
+func TestOpenAIProvider_MultipleChoicesRejectedWhenStreaming(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI, APIKey: "sk-test-key"})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Model:  "gpt-4o",
+		Prompt: "Hi",
+		N:      2,
+		Stream: true,
+	})
+	assert.True(t, types.IsValidationError(err))
+}
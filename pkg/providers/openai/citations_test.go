@@ -0,0 +1,44 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertOpenAIResponseMessage_URLCitations(t *testing.T) {
+	// The indexes count characters, so the accented word shifts the byte offsets
+	content := "Café news: Go 1.24 is out."
+	msg := convertOpenAIResponseMessage(OpenAIMessage{
+		Role:    "assistant",
+		Content: content,
+		Annotations: []OpenAIAnnotation{
+			{Type: "url_citation", URLCitation: &OpenAIURLCitation{
+				URL:        "https://go.dev/blog/go1.24",
+				Title:      "Go 1.24 is released",
+				StartIndex: 11,
+				EndIndex:   26,
+			}},
+			{Type: "file_citation"},
+		},
+	}, false)
+
+	require.Len(t, msg.Citations, 1)
+	citation := msg.Citations[0]
+	assert.Equal(t, "https://go.dev/blog/go1.24", citation.URL)
+	assert.Equal(t, "Go 1.24 is released", citation.Title)
+	assert.Equal(t, "Go 1.24 is out.", citation.Text)
+	assert.Equal(t, content[citation.StartIndex:citation.EndIndex], citation.Text)
+}
+
+func TestConvertOpenAIAnnotations_OutOfRange(t *testing.T) {
+	citations := convertOpenAIAnnotations([]OpenAIAnnotation{
+		{Type: "url_citation", URLCitation: &OpenAIURLCitation{URL: "https://go.dev", StartIndex: 2, EndIndex: 40}},
+	}, "short")
+
+	require.Len(t, citations, 1)
+	assert.Equal(t, "https://go.dev", citations[0].URL)
+	assert.Empty(t, citations[0].Text)
+	assert.Zero(t, citations[0].EndIndex)
+}
@@ -0,0 +1,109 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_PathTemplate(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"role": "assistant", "content": "ok"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:         types.ProviderTypeOpenAI,
+		APIKey:       "sk-test-key",
+		PathTemplate: server.URL + "/ai/openai/v1/{path}",
+	})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+	require.NoError(t, err)
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi", Stream: true})
+	require.NoError(t, err)
+	_ = stream.Close()
+
+	assert.Equal(t, []string{"/ai/openai/v1/chat/completions", "/ai/openai/v1/chat/completions"}, paths)
+}
+
+func TestOpenAIProvider_PathTemplateWithBaseURL(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"role": "assistant", "content": "ok"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:         types.ProviderTypeOpenAI,
+		APIKey:       "sk-test-key",
+		BaseURL:      server.URL,
+		PathTemplate: "{base_url}/api/{path}",
+	})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/chat/completions", path)
+}
+
+func TestOpenAIProvider_PathTemplateForImages(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": 1700000000,
+			"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:         types.ProviderTypeOpenAI,
+		APIKey:       "sk-test-key",
+		PathTemplate: server.URL + "/ai/openai/v1/{path}",
+	})
+
+	_, err := provider.GenerateImage(context.Background(), types.ImageRequest{Prompt: "a fox"})
+	require.NoError(t, err)
+	assert.Equal(t, "/ai/openai/v1/images/generations", path)
+}
+
+func TestOpenAIProvider_InvalidPathTemplate(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:         types.ProviderTypeOpenAI,
+		APIKey:       "sk-test-key",
+		PathTemplate: "https://gw.internal/ai/openai/v1/chat/completions",
+	})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must contain the {path} placeholder")
+
+	err = provider.Configure(types.ProviderConfig{
+		Type:         types.ProviderTypeOpenAI,
+		PathTemplate: "gw.internal/{path}",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not an http or https URL")
+}
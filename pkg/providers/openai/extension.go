@@ -80,6 +80,13 @@ func (e *OpenAIExtension) StandardToProvider(request types.StandardRequest) (int
 		if request.ToolChoice != nil {
 			openAIReq.ToolChoice = convertToOpenAIToolChoice(request.ToolChoice)
 		}
+		openAIReq.ParallelToolCalls = request.ParallelToolCalls
+	}
+
+	openAIReq.ReasoningEffort, _ = reasoningEffort(types.ProviderTypeOpenAI, openAIReq.Model, request.ReasoningEffort)
+
+	if err := applyStore(&openAIReq, request.Store, request.Metadata); err != nil {
+		return nil, err
 	}
 
 	// Handle OpenAI-specific parameters from metadata
@@ -104,6 +111,7 @@ func (e *OpenAIExtension) StandardToProvider(request types.StandardRequest) (int
 			openAIReq.ParallelToolCalls = &parallelToolCalls
 		}
 	}
+	prepareReasoningRequest(&openAIReq)
 
 	return openAIReq, nil
 }
@@ -135,18 +143,15 @@ func (e *OpenAIExtension) ProviderToStandard(response interface{}) (*types.Stand
 		}
 
 		choices[i] = types.StandardChoice{
-			Index:        choice.Index,
-			Message:      message,
-			FinishReason: choice.FinishReason,
+			Index:           choice.Index,
+			Message:         message,
+			FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, choice.FinishReason),
+			RawFinishReason: choice.FinishReason,
 		}
 	}
 
 	// Convert usage
-	usage := types.Usage{
-		PromptTokens:     openAIResp.Usage.PromptTokens,
-		CompletionTokens: openAIResp.Usage.CompletionTokens,
-		TotalTokens:      openAIResp.Usage.TotalTokens,
-	}
+	usage := openAIResp.Usage.toUsage()
 
 	// Create provider metadata
 	providerMetadata := map[string]interface{}{
@@ -186,20 +191,18 @@ func (e *OpenAIExtension) ProviderToStandardChunk(chunk interface{}) (*types.Sta
 		}
 
 		choices[i] = types.StandardStreamChoice{
-			Index:        choice.Index,
-			Delta:        delta,
-			FinishReason: choice.FinishReason,
+			Index:           choice.Index,
+			Delta:           delta,
+			FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, choice.FinishReason),
+			RawFinishReason: choice.FinishReason,
 		}
 	}
 
 	// Convert usage if present
 	var usage *types.Usage
 	if openAIChunk.Usage != nil {
-		usage = &types.Usage{
-			PromptTokens:     openAIChunk.Usage.PromptTokens,
-			CompletionTokens: openAIChunk.Usage.CompletionTokens,
-			TotalTokens:      openAIChunk.Usage.TotalTokens,
-		}
+		converted := openAIChunk.Usage.toUsage()
+		usage = &converted
 	}
 
 	// Create provider metadata
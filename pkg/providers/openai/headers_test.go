@@ -0,0 +1,75 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_ConfiguredHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"role": "assistant", "content": "ok"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:      types.ProviderTypeOpenAI,
+		APIKey:    "sk-test-key",
+		BaseURL:   server.URL,
+		UserAgent: "my-app/2.1",
+		Headers: map[string]string{
+			"X-Team": "search",
+		},
+	})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-app/2.1", received.Get("User-Agent"))
+	assert.Equal(t, "search", received.Get("X-Team"))
+	assert.Equal(t, "Bearer sk-test-key", received.Get("Authorization"))
+}
+
+func TestOpenAIProvider_Warnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Deprecation", "true")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "chatcmpl-1",
+			"model": "gpt-4o-2024-08-06",
+			"choices": []map[string]interface{}{
+				{"index": 0, "message": map[string]interface{}{"role": "assistant", "content": "ok"}, "finish_reason": "stop"},
+			},
+			"usage": map[string]interface{}{"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test",
+		BaseURL: server.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi", Model: "gpt-4o"})
+	require.NoError(t, err)
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	require.Len(t, chunk.Warnings, 1, "a dated snapshot of the requested model is not a substitution")
+	assert.Equal(t, types.WarningDeprecated, chunk.Warnings[0].Code)
+	assert.Empty(t, chunk.Choices[0].Message.Metadata)
+}
@@ -0,0 +1,107 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdempotencyTestServer fails the first request and succeeds afterwards,
+// recording the Idempotency-Key header of every attempt
+func newIdempotencyTestServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(middleware.IdempotencyKeyHeader))
+		attempt := len(keys)
+		mu.Unlock()
+
+		if attempt == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":{"message":"upstream timeout"}}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "chatcmpl-1",
+			"model": "gpt-4o",
+			"choices": []map[string]interface{}{
+				{"index": 0, "message": map[string]interface{}{"role": "assistant", "content": "ok"}, "finish_reason": "stop"},
+			},
+			"usage": map[string]interface{}{"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2},
+		})
+	}))
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+}
+
+func TestOpenAIProvider_IdempotencyKey(t *testing.T) {
+	t.Run("GeneratedKeyReusedAcrossFailover", func(t *testing.T) {
+		server, keys := newIdempotencyTestServer(t)
+		defer server.Close()
+
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:           types.ProviderTypeOpenAI,
+			BaseURL:        server.URL,
+			ProviderConfig: map[string]interface{}{"api_keys": []string{"sk-key-1", "sk-key-2"}},
+		})
+
+		_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+		require.NoError(t, err)
+
+		sent := keys()
+		require.Len(t, sent, 2)
+		assert.NotEmpty(t, sent[0])
+		assert.Equal(t, sent[0], sent[1])
+
+		// A second logical request gets a fresh key
+		_, err = provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+		require.NoError(t, err)
+		sent = keys()
+		require.Len(t, sent, 3)
+		assert.NotEqual(t, sent[0], sent[2])
+	})
+
+	t.Run("ExplicitKey", func(t *testing.T) {
+		server, keys := newIdempotencyTestServer(t)
+		defer server.Close()
+
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:           types.ProviderTypeOpenAI,
+			BaseURL:        server.URL,
+			ProviderConfig: map[string]interface{}{"api_keys": []string{"sk-key-1", "sk-key-2"}},
+		})
+
+		_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+			Prompt:         "hi",
+			IdempotencyKey: "order-42",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order-42", "order-42"}, keys())
+	})
+
+	t.Run("NotSerializedInBody", func(t *testing.T) {
+		provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI, APIKey: "sk-test"})
+		request := provider.buildOpenAIRequest(types.GenerateOptions{Prompt: "hi", IdempotencyKey: "order-42"})
+		assert.Equal(t, "order-42", request.IdempotencyKey)
+
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "order-42")
+	})
+}
@@ -0,0 +1,218 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Constants for OpenAI image generation
+const (
+	openAIDefaultImageModel = "dall-e-3"
+	openAIDefaultImageSize  = "1024x1024"
+)
+
+// OpenAIImageRequest represents a request to the OpenAI images API
+type OpenAIImageRequest struct {
+	Model          string `json:"model"`
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n,omitempty"`
+	Size           string `json:"size,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	Style          string `json:"style,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+// OpenAIImageResponse represents a response from the OpenAI images API
+type OpenAIImageResponse struct {
+	Created int64             `json:"created"`
+	Data    []OpenAIImageData `json:"data"`
+	Usage   *OpenAIImageUsage `json:"usage,omitempty"`
+}
+
+// OpenAIImageData represents a single generated image in the OpenAI API
+type OpenAIImageData struct {
+	URL           string `json:"url,omitempty"`
+	B64JSON       string `json:"b64_json,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// OpenAIImageUsage represents token usage reported by gpt-image models
+type OpenAIImageUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// GenerateImage generates images using the OpenAI images API (DALL·E / gpt-image)
+func (p *OpenAIProvider) GenerateImage(ctx context.Context, req types.ImageRequest) (*types.ImageResponse, error) {
+	if req.Prompt == "" {
+		return nil, types.NewInvalidRequestError(p.Type(), "image prompt is required").
+			WithOperation("GenerateImage")
+	}
+
+	p.IncrementRequestCount()
+	startTime := time.Now()
+
+	requestData := p.buildOpenAIImageRequest(req)
+
+	var response *types.ImageResponse
+	apiKeyOperation := func(ctx context.Context, apiKey string) (string, *types.Usage, error) {
+		resp, err := p.makeImageAPICall(ctx, requestData, apiKey)
+		if err != nil {
+			return "", nil, err
+		}
+		response = resp
+		return "", resp.Usage, nil
+	}
+
+	var usage *types.Usage
+	var callErr error
+	if p.authHelper.KeyManager != nil {
+		_, usage, callErr = p.authHelper.KeyManager.ExecuteWithFailover(ctx, apiKeyOperation)
+	} else {
+		callErr = types.NewAuthError(p.Type(), fmt.Sprintf("no API keys configured for %s", p.Name())).
+			WithOperation("GenerateImage")
+	}
+
+	if callErr != nil {
+		p.RecordError(callErr)
+		return nil, callErr
+	}
+
+	tokensUsed := int64(0)
+	if usage != nil {
+		tokensUsed = int64(usage.TotalTokens)
+	}
+	p.RecordSuccess(time.Since(startTime), tokensUsed)
+
+	return response, nil
+}
+
+// buildOpenAIImageRequest builds the OpenAI images API request from a universal image request
+func (p *OpenAIProvider) buildOpenAIImageRequest(req types.ImageRequest) OpenAIImageRequest {
+	model := req.Model
+	if model == "" {
+		model = openAIDefaultImageModel
+	}
+
+	size := req.Size
+	if size == "" {
+		size = openAIDefaultImageSize
+	}
+
+	return OpenAIImageRequest{
+		Model:          model,
+		Prompt:         req.Prompt,
+		N:              req.N,
+		Size:           size,
+		ResponseFormat: string(req.ResponseFormat),
+		Quality:        req.Quality,
+		Style:          req.Style,
+		User:           req.User,
+	}
+}
+
+// makeImageAPICall makes a single call to the OpenAI images API
+func (p *OpenAIProvider) makeImageAPICall(ctx context.Context, requestData OpenAIImageRequest, apiKey string) (*types.ImageResponse, error) {
+	ctx = middleware.WithModel(ctx, requestData.Model)
+	jsonBody, err := json.Marshal(requestData)
+	if err != nil {
+		return nil, types.NewInvalidRequestError(p.Type(), "failed to marshal image request").
+			WithOperation("makeImageAPICall").
+			WithOriginalErr(err)
+	}
+
+	url, err := p.endpointURL("images/generations", "makeImageAPICall")
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, types.NewNetworkError(p.Type(), "failed to create request").
+			WithOperation("makeImageAPICall").
+			WithOriginalErr(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
+	p.authHelper.SetProviderSpecificHeaders(req)
+
+	p.LogRequest("POST", url, map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer ***",
+	}, requestData)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, types.NewRequestError(p.Type(), "request failed", err).
+			WithOperation("makeImageAPICall")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, types.NewNetworkError(p.Type(), "failed to read response body").
+			WithOperation("makeImageAPICall").
+			WithOriginalErr(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse OpenAIErrorResponse
+		message := string(body)
+		if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil && errorResponse.Error.Message != "" {
+			message = errorResponse.Error.Message
+		}
+		return nil, &types.ProviderError{
+			Code:       types.ClassifyHTTPError(resp.StatusCode),
+			Message:    fmt.Sprintf("%s images API error: %s", p.Name(), message),
+			Provider:   p.Type(),
+			StatusCode: resp.StatusCode,
+			Operation:  "makeImageAPICall",
+		}
+	}
+
+	var imageResponse OpenAIImageResponse
+	if err := json.Unmarshal(body, &imageResponse); err != nil {
+		return nil, types.NewInvalidRequestError(p.Type(), "failed to parse image response").
+			WithOperation("makeImageAPICall").
+			WithOriginalErr(err)
+	}
+
+	return convertOpenAIImageResponse(imageResponse, requestData.Model), nil
+}
+
+// convertOpenAIImageResponse converts an OpenAI images API response to universal format
+func convertOpenAIImageResponse(response OpenAIImageResponse, model string) *types.ImageResponse {
+	result := &types.ImageResponse{
+		Created: response.Created,
+		Model:   model,
+		Data:    make([]types.ImageData, len(response.Data)),
+	}
+
+	for i, image := range response.Data {
+		result.Data[i] = types.ImageData{
+			URL:           image.URL,
+			B64JSON:       image.B64JSON,
+			RevisedPrompt: image.RevisedPrompt,
+		}
+	}
+
+	if response.Usage != nil {
+		result.Usage = &types.Usage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		}
+	}
+
+	return result
+}
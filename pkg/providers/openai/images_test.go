@@ -0,0 +1,153 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAIProvider_GenerateImage tests image generation against a mock images API
+func TestOpenAIProvider_GenerateImage(t *testing.T) {
+	t.Run("URLResponse", func(t *testing.T) {
+		var received OpenAIImageRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/images/generations", r.URL.Path)
+			assert.Equal(t, "Bearer sk-test-key", r.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"created": 1700000000,
+				"data": []map[string]interface{}{
+					{"url": "https://example.com/image.png", "revised_prompt": "a red fox"},
+				},
+			})
+		}))
+		defer server.Close()
+
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:    types.ProviderTypeOpenAI,
+			APIKey:  "sk-test-key",
+			BaseURL: server.URL,
+		})
+
+		resp, err := provider.GenerateImage(context.Background(), types.ImageRequest{
+			Prompt: "a fox",
+			N:      1,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "dall-e-3", received.Model)
+		assert.Equal(t, "1024x1024", received.Size)
+		assert.Equal(t, "a fox", received.Prompt)
+
+		assert.Equal(t, int64(1700000000), resp.Created)
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "https://example.com/image.png", resp.Data[0].URL)
+		assert.Equal(t, "a red fox", resp.Data[0].RevisedPrompt)
+		assert.Nil(t, resp.Usage)
+	})
+
+	t.Run("Base64ResponseWithUsage", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req OpenAIImageRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "gpt-image-1", req.Model)
+			assert.Equal(t, "b64_json", req.ResponseFormat)
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"created": 1700000001,
+				"data": []map[string]interface{}{
+					{"b64_json": "aGVsbG8="},
+					{"b64_json": "d29ybGQ="},
+				},
+				"usage": map[string]interface{}{
+					"input_tokens":  12,
+					"output_tokens": 4160,
+					"total_tokens":  4172,
+				},
+			})
+		}))
+		defer server.Close()
+
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:    types.ProviderTypeOpenAI,
+			APIKey:  "sk-test-key",
+			BaseURL: server.URL,
+		})
+
+		resp, err := provider.GenerateImage(context.Background(), types.ImageRequest{
+			Prompt:         "two images",
+			Model:          "gpt-image-1",
+			N:              2,
+			ResponseFormat: types.ImageResponseFormatB64JSON,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, resp.Data, 2)
+		assert.Equal(t, "aGVsbG8=", resp.Data[0].B64JSON)
+		assert.Equal(t, "gpt-image-1", resp.Model)
+		require.NotNil(t, resp.Usage)
+		assert.Equal(t, 12, resp.Usage.PromptTokens)
+		assert.Equal(t, 4160, resp.Usage.CompletionTokens)
+		assert.Equal(t, 4172, resp.Usage.TotalTokens)
+
+		metrics := provider.GetMetrics()
+		assert.Equal(t, int64(1), metrics.SuccessCount)
+		assert.Equal(t, int64(4172), metrics.TokensUsed)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{
+					"message": "Your request was rejected by the safety system",
+					"type":    "invalid_request_error",
+				},
+			})
+		}))
+		defer server.Close()
+
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:    types.ProviderTypeOpenAI,
+			APIKey:  "sk-test-key",
+			BaseURL: server.URL,
+		})
+
+		_, err := provider.GenerateImage(context.Background(), types.ImageRequest{Prompt: "bad"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "safety system")
+		assert.Equal(t, int64(1), provider.GetMetrics().ErrorCount)
+	})
+
+	t.Run("EmptyPrompt", func(t *testing.T) {
+		provider := createTestProvider(t)
+
+		_, err := provider.GenerateImage(context.Background(), types.ImageRequest{})
+		require.Error(t, err)
+
+		var providerErr *types.ProviderError
+		require.ErrorAs(t, err, &providerErr)
+		assert.Equal(t, types.ErrCodeInvalidRequest, providerErr.Code)
+	})
+
+	t.Run("NoAPIKey", func(t *testing.T) {
+		provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI})
+
+		_, err := provider.GenerateImage(context.Background(), types.ImageRequest{Prompt: "a fox"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no API keys configured")
+	})
+}
+
+// TestOpenAIProvider_ImplementsImageProvider verifies the OpenAI provider satisfies ImageProvider
+func TestOpenAIProvider_ImplementsImageProvider(t *testing.T) {
+	var _ types.ImageProvider = (*OpenAIProvider)(nil)
+}
@@ -0,0 +1,46 @@
+package openai
+
+import (
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// toolFormat returns the tool calling format for model: types.ToolFormatOpenAIFunctions
+// when configured for the model or provider, types.ToolFormatOpenAI otherwise. An empty
+// model uses the configured default model.
+func (p *OpenAIProvider) toolFormat(model string) types.ToolFormat {
+	config := p.GetConfig()
+	if model == "" {
+		model = config.DefaultModel
+	}
+	if types.ModelToolFormat(config, model, types.ToolFormatOpenAI) == types.ToolFormatOpenAIFunctions {
+		return types.ToolFormatOpenAIFunctions
+	}
+	return types.ToolFormatOpenAI
+}
+
+// useLegacyFunctionCalling rewrites a request built with tools into the legacy
+// function calling format, for endpoints that only accept "functions"
+func useLegacyFunctionCalling(request *OpenAIRequest, options types.GenerateOptions) {
+	request.Functions = common.ToolsToLegacyFunctions(options.Tools)
+	if len(options.Tools) > 0 {
+		request.FunctionCall = common.ToolChoiceToLegacyFunctionCall(options.ToolChoice, options.Tools)
+	}
+	request.Tools = nil
+	request.ToolChoice = nil
+	request.ParallelToolCalls = nil
+
+	names := common.LegacyFunctionNames(options.Messages)
+	for i := range request.Messages {
+		message := &request.Messages[i]
+		if len(message.ToolCalls) > 0 {
+			message.FunctionCall = common.ToolCallsToLegacyFunctionCall(convertOpenAIToolCallsToUniversal(message.ToolCalls))
+			message.ToolCalls = nil
+		}
+		if message.Role == "tool" {
+			message.Role = common.LegacyFunctionRole
+			message.Name = names[message.ToolCallID]
+			message.ToolCallID = ""
+		}
+	}
+}
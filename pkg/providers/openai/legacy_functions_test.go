@@ -0,0 +1,114 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_LegacyFunctionCalling(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request = nil
+		_ = json.Unmarshal(body, &request)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "chatcmpl-1",
+			"model": "legacy-model",
+			"choices": []map[string]interface{}{{
+				"index": 0,
+				"message": map[string]interface{}{
+					"role":          "assistant",
+					"content":       nil,
+					"function_call": map[string]interface{}{"name": "get_weather", "arguments": `{"city":"Paris"}`},
+				},
+				"finish_reason": "function_call",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	tools := []types.Tool{{Name: "get_weather", Description: "Get the weather", InputSchema: map[string]interface{}{"type": "object"}}}
+	messages := []types.ChatMessage{
+		{Role: "user", Content: "Weather in Paris?"},
+		{Role: "assistant", ToolCalls: []types.ToolCall{{ID: "call_1", Type: "function", Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}}},
+		{Role: "tool", ToolCallID: "call_1", Content: "Sunny"},
+	}
+
+	generate := func(t *testing.T, config types.ProviderConfig) types.ChatCompletionChunk {
+		t.Helper()
+		config.Type = types.ProviderTypeOpenAI
+		config.APIKey = "sk-test"
+		config.BaseURL = server.URL
+		provider := NewOpenAIProvider(config)
+
+		stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+			Model:      "legacy-model",
+			Messages:   messages,
+			Tools:      tools,
+			ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceSpecific, FunctionName: "get_weather"},
+		})
+		require.NoError(t, err)
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		return chunk
+	}
+
+	t.Run("converts tools to functions", func(t *testing.T) {
+		chunk := generate(t, types.ProviderConfig{ToolFormat: types.ToolFormatOpenAIFunctions})
+
+		assert.NotContains(t, request, "tools")
+		assert.NotContains(t, request, "tool_choice")
+		functions := request["functions"].([]interface{})
+		require.Len(t, functions, 1)
+		assert.Equal(t, "get_weather", functions[0].(map[string]interface{})["name"])
+		assert.Equal(t, map[string]interface{}{"name": "get_weather"}, request["function_call"])
+
+		sent := request["messages"].([]interface{})
+		assistant := sent[1].(map[string]interface{})
+		assert.NotContains(t, assistant, "tool_calls")
+		assert.Equal(t, map[string]interface{}{"name": "get_weather", "arguments": `{"city":"Paris"}`}, assistant["function_call"])
+		result := sent[2].(map[string]interface{})
+		assert.Equal(t, "function", result["role"])
+		assert.Equal(t, "get_weather", result["name"])
+		assert.NotContains(t, result, "tool_call_id")
+
+		require.Len(t, chunk.Choices, 1)
+		toolCalls := chunk.Choices[0].Message.ToolCalls
+		require.Len(t, toolCalls, 1)
+		assert.NotEmpty(t, toolCalls[0].ID)
+		assert.Equal(t, "get_weather", toolCalls[0].Function.Name)
+		assert.Equal(t, `{"city":"Paris"}`, toolCalls[0].Function.Arguments)
+		assert.Equal(t, types.FinishToolCalls, chunk.Choices[0].FinishReason)
+	})
+
+	t.Run("per-model capability", func(t *testing.T) {
+		generate(t, types.ProviderConfig{
+			ModelCapabilities: map[string]types.ModelCapabilityOverride{
+				"legacy-model": {ToolFormat: types.ToolFormatOpenAIFunctions},
+			},
+		})
+		assert.Contains(t, request, "functions")
+		assert.NotContains(t, request, "tools")
+	})
+
+	t.Run("tools by default", func(t *testing.T) {
+		generate(t, types.ProviderConfig{})
+		assert.Contains(t, request, "tools")
+		assert.NotContains(t, request, "functions")
+	})
+}
+
+func TestOpenAIProvider_GetToolFormat_LegacyFunctions(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI, ToolFormat: types.ToolFormatOpenAIFunctions})
+	assert.Equal(t, types.ToolFormatOpenAIFunctions, provider.GetToolFormat())
+}
@@ -0,0 +1,126 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_ModelFallbacks(t *testing.T) {
+	var mu sync.Mutex
+	var models []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OpenAIRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		models = append(models, req.Model)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Model == "gpt-4o" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"message":"The model is overloaded","type":"server_error"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"` + req.Model + `","choices":[{"index":0,"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt:         "hi",
+		Model:          "gpt-4o",
+		ModelFallbacks: []string{"gpt-4o-mini"},
+	})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	chunk, _ := stream.Next()
+	assert.Equal(t, "hello", chunk.Content)
+	assert.Equal(t, "gpt-4o-mini", chunk.Metadata["served_model"])
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "gpt-4o", models[0])
+	assert.Equal(t, "gpt-4o-mini", models[len(models)-1])
+}
+
+func TestOpenAIProvider_ContextUpgrade(t *testing.T) {
+	var model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OpenAIRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		model = req.Model
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"` + req.Model + `","choices":[{"index":0,"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	smallWindow, largeWindow := 1000, 128000
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+		ModelCapabilities: map[string]types.ModelCapabilityOverride{
+			"small-model": {ContextWindow: &smallWindow, ContextUpgrades: []string{"large-model"}},
+			"large-model": {ContextWindow: &largeWindow},
+		},
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Model:     "small-model",
+		Prompt:    "hi",
+		MaxTokens: 4096,
+	})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	chunk, _ := stream.Next()
+	assert.Equal(t, "large-model", model)
+	assert.Equal(t, "small-model", chunk.Metadata["context_upgraded_from"])
+	assert.Equal(t, "large-model", chunk.Metadata["context_upgraded_to"])
+}
+
+func TestOpenAIProvider_StreamingKeyFailoverRetryBudget(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":{"message":"internal error","type":"server_error"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:           types.ProviderTypeOpenAI,
+		APIKey:         "sk-key-1",
+		BaseURL:        server.URL,
+		ProviderConfig: map[string]interface{}{"api_keys": []string{"sk-key-2", "sk-key-3"}},
+	})
+
+	ctx := types.WithRetryBudget(context.Background(), types.NewRetryBudget(1))
+	_, err := provider.GenerateChatCompletion(ctx, types.GenerateOptions{Prompt: "hi", Stream: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "internal error", "the last real error is returned")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, keys, 2, "the budget allows one failover to another key")
+}
@@ -17,7 +17,9 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
@@ -38,13 +40,64 @@ type OpenAIRequest struct {
 	MaxTokens         int                    `json:"max_tokens,omitempty"`
 	Temperature       float64                `json:"temperature,omitempty"`
 	Stream            bool                   `json:"stream,omitempty"`
+	StreamOptions     *OpenAIStreamOptions   `json:"stream_options,omitempty"`
 	TopP              float64                `json:"top_p,omitempty"`
 	Tools             []OpenAITool           `json:"tools,omitempty"`
 	ToolChoice        interface{}            `json:"tool_choice,omitempty"`
 	Stop              []string               `json:"stop,omitempty"`
+	N                 int                    `json:"n,omitempty"`
 	Seed              *int                   `json:"seed,omitempty"`
 	ResponseFormat    map[string]interface{} `json:"response_format,omitempty"`
 	ParallelToolCalls *bool                  `json:"parallel_tool_calls,omitempty"`
+	ReasoningEffort   string                 `json:"reasoning_effort,omitempty"` // Reasoning models only
+	Store             *bool                  `json:"store,omitempty"`
+	Metadata          map[string]string      `json:"metadata,omitempty"` // Only sent with Store true
+
+	// MaxCompletionTokens replaces MaxTokens for reasoning models, see
+	// prepareReasoningRequest
+	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"`
+
+	// Legacy function calling, sent instead of Tools and ToolChoice for
+	// types.ToolFormatOpenAIFunctions
+	Functions    []common.LegacyFunction `json:"functions,omitempty"`
+	FunctionCall interface{}             `json:"function_call,omitempty"`
+
+	// IdempotencyKey is sent as the Idempotency-Key header, not in the body
+	IdempotencyKey string `json:"-"`
+
+	// Extra holds fields of OpenAI-compatible APIs that OpenAI lacks, merged into the
+	// body by MarshalJSON. Fields of the request take precedence.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON merges Extra into the request body
+func (r OpenAIRequest) MarshalJSON() ([]byte, error) {
+	type request OpenAIRequest
+	data, err := json.Marshal(request(r))
+	if err != nil || len(r.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range r.Extra {
+		if _, ok := fields[key]; ok {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extra field %q: %w", key, err)
+		}
+		fields[key] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// OpenAIStreamOptions configures a streaming response
+type OpenAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Send usage in a final chunk
 }
 
 // OpenAITool represents a tool in the OpenAI API
@@ -68,6 +121,29 @@ type OpenAIMessage struct {
 	ReasoningContent string           `json:"reasoning_content,omitempty"` // Alternative reasoning field (vLLM/Synthetic)
 	ToolCalls        []OpenAIToolCall `json:"tool_calls,omitempty"`
 	ToolCallID       string           `json:"tool_call_id,omitempty"`
+
+	// Legacy function calling: the assistant's call, and the function name of a
+	// "function" role result
+	FunctionCall *common.LegacyFunctionCall `json:"function_call,omitempty"`
+	Name         string                     `json:"name,omitempty"`
+
+	// Sources of a response from a search model
+	Annotations []OpenAIAnnotation `json:"annotations,omitempty"`
+}
+
+// OpenAIAnnotation is an annotation of a response message's content
+type OpenAIAnnotation struct {
+	Type        string             `json:"type"` // "url_citation"
+	URLCitation *OpenAIURLCitation `json:"url_citation,omitempty"`
+}
+
+// OpenAIURLCitation is a web source cited by a span of the content. The indexes count
+// characters, not bytes.
+type OpenAIURLCitation struct {
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
 }
 
 // OpenAIContentPart represents a content part in OpenAI's multimodal format
@@ -85,6 +161,7 @@ type OpenAIImageURL struct {
 
 // OpenAIToolCall represents a tool call in the OpenAI API
 type OpenAIToolCall struct {
+	Index    *int                   `json:"index,omitempty"` // Set on streaming deltas only
 	ID       string                 `json:"id"`
 	Type     string                 `json:"type"` // "function"
 	Function OpenAIToolCallFunction `json:"function"`
@@ -116,9 +193,28 @@ type OpenAIChoice struct {
 
 // OpenAIUsage represents token usage information from OpenAI
 type OpenAIUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens        int                        `json:"prompt_tokens"`
+	CompletionTokens    int                        `json:"completion_tokens"`
+	TotalTokens         int                        `json:"total_tokens"`
+	PromptTokensDetails *OpenAIPromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+// OpenAIPromptTokensDetails breaks down the prompt tokens of a response
+type OpenAIPromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"` // Read from the prompt cache, included in prompt_tokens
+}
+
+// toUsage converts the usage to the standard format
+func (u OpenAIUsage) toUsage() types.Usage {
+	usage := types.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		usage.CacheReadTokens = u.PromptTokensDetails.CachedTokens
+	}
+	return usage
 }
 
 // OpenAIStreamResponse represents a streaming response chunk
@@ -181,25 +277,105 @@ type OpenAIProvider struct {
 	httpClient        *pkghttp.HTTPClient
 	client            *http.Client
 	baseURL           string
+	pathTemplate      string
 	useResponsesAPI   bool
 	rateLimitHelper   *common.RateLimitHelper
 	modelCache        *models.ModelCache
 	modelRegistry     *models.ModelMetadataRegistry
 	organizationID    string
 	connectivityCache *common.ConnectivityCache
+
+	// streamIncludeUsage requests usage in the final chunk of streamed responses, see
+	// configStreamIncludeUsage
+	streamIncludeUsage bool
+
+	// streamResponsesAPI streams chat completions through the Responses API, see
+	// configStreamResponsesAPI
+	streamResponsesAPI bool
+
+	// api identifies the OpenAI-compatible API served, see NewCompatibleProvider
+	api CompatibleAPI
+}
+
+// CompatibleAPI describes an OpenAI-compatible API served by the OpenAI provider
+type CompatibleAPI struct {
+	Name        string
+	Type        types.ProviderType
+	Description string
+
+	// DefaultModel is used when neither the request nor the config names a model
+	DefaultModel string
+
+	// PrepareRequest adjusts each chat completion request for the API's quirks once it
+	// is built from options. It may return warnings for the response, or an error to
+	// reject the request before it is sent.
+	PrepareRequest func(request *OpenAIRequest, options types.GenerateOptions) ([]types.Warning, error)
+
+	// SeparateReasoning reports reasoning only in the ReasoningContent and Reasoning
+	// fields, for APIs that send the answer after the reasoning in the content field.
+	// Otherwise reasoning stands in for empty content, as some servers send the answer
+	// of reasoning models in the reasoning fields.
+	SeparateReasoning bool
+}
+
+// openAIAPI describes OpenAI's own API
+var openAIAPI = CompatibleAPI{
+	Name:         "OpenAI",
+	Type:         types.ProviderTypeOpenAI,
+	Description:  "OpenAI - GPT models with native API access",
+	DefaultModel: openAIDefaultModel,
+	PrepareRequest: func(request *OpenAIRequest, _ types.GenerateOptions) ([]types.Warning, error) {
+		return prepareReasoningRequest(request), nil
+	},
+}
+
+// configKeyStreamIncludeUsage is the ProviderConfig.ProviderConfig key that, set to
+// false, stops the provider from sending stream_options.include_usage, for proxies and
+// OpenAI-compatible servers that reject the field
+const configKeyStreamIncludeUsage = "stream_include_usage"
+
+// configKeyStreamResponsesAPI is the ProviderConfig key that streams chat completions
+// through the Responses API, so their tool outputs can be submitted to the stored
+// response. It takes effect with SupportsResponsesAPI.
+const configKeyStreamResponsesAPI = "stream_responses_api"
+
+// configStreamResponsesAPI reports whether streamed requests go through the Responses
+// API, which they only do when enabled in config
+func configStreamResponsesAPI(config types.ProviderConfig) bool {
+	enabled, _ := config.ProviderConfig[configKeyStreamResponsesAPI].(bool)
+	return enabled
+}
+
+// configStreamIncludeUsage reports whether streamed requests should ask for usage,
+// which they do unless disabled in config
+func configStreamIncludeUsage(config types.ProviderConfig) bool {
+	includeUsage, ok := config.ProviderConfig[configKeyStreamIncludeUsage].(bool)
+	return includeUsage || !ok
 }
 
 // NewOpenAIProvider creates a new OpenAI provider
 func NewOpenAIProvider(config types.ProviderConfig) *OpenAIProvider {
+	return NewCompatibleProvider(config, openAIAPI)
+}
+
+// NewCompatibleProvider creates a provider for an OpenAI-compatible API. The config
+// helper's defaults for api.Type, such as the base URL, apply to config.
+func NewCompatibleProvider(config types.ProviderConfig, api CompatibleAPI) *OpenAIProvider {
 	// Use the shared config helper
-	configHelper := commonconfig.NewConfigHelper("OpenAI", types.ProviderTypeOpenAI)
+	configHelper := commonconfig.NewConfigHelper(api.Name, api.Type)
 
 	// Merge with defaults and extract configuration
 	mergedConfig := configHelper.MergeWithDefaults(config)
 
 	// Create HTTP client using internal/http package
 	httpClient := pkghttp.NewHTTPClient(pkghttp.HTTPClientConfig{
-		Timeout: configHelper.ExtractTimeout(mergedConfig),
+		Timeout:          configHelper.ExtractTimeout(mergedConfig),
+		ConnectTimeout:   mergedConfig.ConnectTimeout,
+		RequestTimeout:   mergedConfig.RequestTimeout,
+		Headers:          mergedConfig.Headers,
+		UserAgent:        mergedConfig.UserAgent,
+		CompressRequests: mergedConfig.CompressRequests,
+		WrapTransport:    mergedConfig.WrapTransport,
 	})
 
 	// Extract configuration using helper
@@ -207,7 +383,7 @@ func NewOpenAIProvider(config types.ProviderConfig) *OpenAIProvider {
 	organizationID := configHelper.ExtractStringField(mergedConfig, "organization_id", "")
 
 	// Create auth helper with the underlying http.Client
-	authHelper := auth.NewAuthHelper("openai", mergedConfig, httpClient.Client())
+	authHelper := auth.NewAuthHelper(string(api.Type), mergedConfig, httpClient.Client())
 
 	// Setup API keys using shared helper
 	authHelper.SetupAPIKeys()
@@ -223,32 +399,37 @@ func NewOpenAIProvider(config types.ProviderConfig) *OpenAIProvider {
 	}
 
 	provider := &OpenAIProvider{
-		BaseProvider:      base.NewBaseProvider("openai", mergedConfig, httpClient.Client(), log.Default()),
+		BaseProvider:      base.NewBaseProvider(string(api.Type), mergedConfig, httpClient.Client(), log.Default()),
 		authHelper:        authHelper,
 		httpClient:        httpClient,
 		client:            httpClient.Client(),
 		baseURL:           baseURL,
+		pathTemplate:      mergedConfig.PathTemplate,
 		useResponsesAPI:   useResponsesAPI,
 		rateLimitHelper:   common.NewRateLimitHelper(ratelimit.NewOpenAIParser()),
 		organizationID:    organizationID,
 		modelCache:        models.NewModelCache(24 * time.Hour), // 24 hour cache for OpenAI
 		modelRegistry:     models.GetOpenAIMetadataRegistry(),
 		connectivityCache: common.NewDefaultConnectivityCache(),
+
+		streamIncludeUsage: configStreamIncludeUsage(mergedConfig),
+		streamResponsesAPI: configStreamResponsesAPI(mergedConfig),
+		api:                api,
 	}
 
 	return provider
 }
 
 func (p *OpenAIProvider) Name() string {
-	return "OpenAI"
+	return p.api.Name
 }
 
 func (p *OpenAIProvider) Type() types.ProviderType {
-	return types.ProviderTypeOpenAI
+	return p.api.Type
 }
 
 func (p *OpenAIProvider) Description() string {
-	return "OpenAI - GPT models with native API access"
+	return p.api.Description
 }
 
 func (p *OpenAIProvider) GetModels(ctx context.Context) ([]types.Model, error) {
@@ -258,7 +439,7 @@ func (p *OpenAIProvider) GetModels(ctx context.Context) ([]types.Model, error) {
 			// Fetch from API
 			models, err := p.fetchModelsFromAPI(ctx)
 			if err != nil {
-				log.Printf("OpenAI: Failed to fetch models from API: %v", err)
+				log.Printf("%s: Failed to fetch models from API: %v", p.Name(), err)
 				return nil, err
 			}
 			// Enrich with provider-specific metadata
@@ -274,22 +455,25 @@ func (p *OpenAIProvider) GetModels(ctx context.Context) ([]types.Model, error) {
 // fetchModelsFromAPI fetches models from OpenAI API
 func (p *OpenAIProvider) fetchModelsFromAPI(ctx context.Context) ([]types.Model, error) {
 	if p.authHelper.KeyManager == nil || len(p.authHelper.KeyManager.GetKeys()) == 0 {
-		return nil, types.NewAuthError(types.ProviderTypeOpenAI, "no OpenAI API key configured").
+		return nil, types.NewAuthError(p.Type(), fmt.Sprintf("no %s API key configured", p.Name())).
 			WithOperation("fetchModelsFromAPI")
 	}
 
-	url := p.baseURL + "/models"
+	url, err := p.endpointURL("models", "fetchModelsFromAPI")
+	if err != nil {
+		return nil, err
+	}
 
 	// Use first available API key
 	keys := p.authHelper.KeyManager.GetKeys()
 	if len(keys) == 0 {
-		return nil, types.NewAuthError(types.ProviderTypeOpenAI, "no API keys available").
+		return nil, types.NewAuthError(p.Type(), "no API keys available").
 			WithOperation("fetchModelsFromAPI")
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOpenAI, "failed to create request").
+		return nil, types.NewNetworkError(p.Type(), "failed to create request").
 			WithOperation("fetchModelsFromAPI").
 			WithOriginalErr(err)
 	}
@@ -299,28 +483,27 @@ func (p *OpenAIProvider) fetchModelsFromAPI(ctx context.Context) ([]types.Model,
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOpenAI, "failed to fetch models").
-			WithOperation("fetchModelsFromAPI").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(p.Type(), "failed to fetch models", err).
+			WithOperation("fetchModelsFromAPI")
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, types.NewServerError(types.ProviderTypeOpenAI, resp.StatusCode, fmt.Sprintf("failed to fetch models: %s", string(body))).
+		return nil, types.NewServerError(p.Type(), resp.StatusCode, fmt.Sprintf("failed to fetch models: %s", string(body))).
 			WithOperation("fetchModelsFromAPI")
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOpenAI, "failed to read response").
+		return nil, types.NewNetworkError(p.Type(), "failed to read response").
 			WithOperation("fetchModelsFromAPI").
 			WithOriginalErr(err)
 	}
 
 	var modelsResp OpenAIModelsResponse
 	if err := json.Unmarshal(body, &modelsResp); err != nil {
-		return nil, types.NewInvalidRequestError(types.ProviderTypeOpenAI, "failed to parse models response").
+		return nil, types.NewInvalidRequestError(p.Type(), "failed to parse models response").
 			WithOperation("fetchModelsFromAPI").
 			WithOriginalErr(err)
 	}
@@ -352,7 +535,7 @@ func (p *OpenAIProvider) GetDefaultModel() string {
 	if config.DefaultModel != "" {
 		return config.DefaultModel
 	}
-	return openAIDefaultModel
+	return p.api.DefaultModel
 }
 
 // GenerateChatCompletion generates a chat completion
@@ -360,6 +543,13 @@ func (p *OpenAIProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+	if _, ok := options.Prefill(); ok {
+		return common.GenerateWithoutPrefill(ctx, options, p.Type(), p.GenerateChatCompletion)
+	}
+
 	// Increment request count at the start
 	p.IncrementRequestCount()
 
@@ -369,20 +559,59 @@ func (p *OpenAIProvider) GenerateChatCompletion(
 	// Build OpenAI request
 	requestData := p.buildOpenAIRequest(options)
 
-	// Check rate limits before making request
+	// Validate before any network I/O
 	model := requestData.Model
+	if err := common.ValidateGenerateOptions(model, options); err != nil {
+		return nil, err
+	}
+	ctx = middleware.WithModel(ctx, model)
+	if options.N > 1 && options.Stream {
+		// Streamed chunks carry a single choice
+		return nil, types.NewValidationError("n > 1 is not supported with streaming")
+	}
+	if err := applyStore(&requestData, options.Store, options.Metadata); err != nil {
+		return nil, err
+	}
+
+	var requestWarnings []types.Warning
+	effort, warning := reasoningEffort(p.Type(), model, options.ReasoningEffort)
+	requestData.ReasoningEffort = effort
+	if warning != nil {
+		requestWarnings = append(requestWarnings, *warning)
+	}
+	if p.api.PrepareRequest != nil {
+		warnings, err := p.api.PrepareRequest(&requestData, options)
+		if err != nil {
+			return nil, err
+		}
+		requestWarnings = append(requestWarnings, warnings...)
+	}
+
+	// Check rate limits before making request
 	p.rateLimitHelper.CheckRateLimitAndWait(model, options.MaxTokens)
 
 	// Check if streaming is requested
 	if options.Stream {
-		stream, err := p.executeStreamWithAuth(ctx, requestData)
+		var stream types.ChatCompletionStream
+		var err error
+		if p.streamsResponses() {
+			// Streamed through the Responses API, so tool outputs can be submitted to the
+			// stored response
+			var responsesRequest OpenAIResponsesRequest
+			if responsesRequest, err = newResponsesRequest(requestData, options); err != nil {
+				return nil, err
+			}
+			stream, err = p.executeResponsesStream(ctx, responsesRequest, "GenerateChatCompletion")
+		} else {
+			stream, err = p.executeStreamWithAuth(ctx, requestData)
+		}
 		if err != nil {
 			p.RecordError(err)
 			return nil, err
 		}
 		latency := time.Since(startTime)
 		p.RecordSuccess(latency, 0) // Tokens will be counted as stream is consumed
-		return stream, nil
+		return types.StreamWithWarnings(stream, requestWarnings), nil
 	}
 
 	// Non-streaming path - use auth helper
@@ -428,39 +657,78 @@ func (p *OpenAIProvider) GenerateChatCompletion(
 	}
 
 	chunk := types.ChatCompletionChunk{
-		Content: responseContent,
-		Done:    true,
-		Usage:   usageValue,
+		Content:          responseContent,
+		Reasoning:        responseMessage.Reasoning,
+		ReasoningContent: responseMessage.ReasoningContent,
+		Citations:        responseMessage.Citations,
+		Done:             true,
+		Usage:            usageValue,
 	}
 
-	// Include tool calls if present
-	if len(responseMessage.ToolCalls) > 0 {
-		chunk.Choices = []types.ChatChoice{
-			{
-				Message: responseMessage,
-			},
-		}
+	finishReason, _ := responseMessage.Metadata[metadataKeyFinishReason].(string)
+	delete(responseMessage.Metadata, metadataKeyFinishReason)
+	responseWarnings, _ := responseMessage.Metadata[metadataKeyWarnings].([]types.Warning)
+	chunk.Warnings = append(requestWarnings, responseWarnings...)
+	delete(responseMessage.Metadata, metadataKeyWarnings)
+
+	extraChoices, _ := responseMessage.Metadata[metadataKeyExtraChoices].([]types.ChatChoice)
+	delete(responseMessage.Metadata, metadataKeyExtraChoices)
+	if len(responseMessage.Metadata) == 0 {
+		responseMessage.Metadata = nil
 	}
 
+	chunk.Choices = append([]types.ChatChoice{
+		{
+			Message:         responseMessage,
+			FinishReason:    types.NormalizeFinishReason(p.Type(), finishReason),
+			RawFinishReason: finishReason,
+		},
+	}, extraChoices...)
+
 	return streaming.NewMockStream([]types.ChatCompletionChunk{chunk}), nil
 }
 
+// Metadata keys carrying response details from makeAPICall to the response chunk
+const (
+	metadataKeyFinishReason = "finish_reason"
+	metadataKeyWarnings     = "warnings"
+	metadataKeyExtraChoices = "extra_choices" // Choices after the first, when n > 1
+)
+
 // executeStreamWithAuth handles streaming requests with authentication
 func (p *OpenAIProvider) executeStreamWithAuth(ctx context.Context, requestData OpenAIRequest) (types.ChatCompletionStream, error) {
 	requestData.Stream = true
+	if p.streamIncludeUsage {
+		// Without it OpenAI reports no usage for streamed responses
+		requestData.StreamOptions = &OpenAIStreamOptions{IncludeUsage: true}
+	}
 
 	// Try API keys (OpenAI doesn't use OAuth)
+	var lastErr error
 	if p.authHelper.KeyManager != nil {
 		keys := p.authHelper.KeyManager.GetKeys()
 		for _, apiKey := range keys {
+			// Failing over to another key is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICall(ctx, requestData, apiKey)
 			if err == nil {
 				return stream, nil
 			}
+			// Another key won't help if the model itself is unavailable
+			if common.IsModelFallbackError(err) {
+				return nil, err
+			}
+			lastErr = err
 		}
 	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
 
-	return nil, types.NewAuthError(types.ProviderTypeOpenAI, "no valid API key available for streaming").
+	return nil, types.NewAuthError(p.Type(), "no valid API key available for streaming").
 		WithOperation("executeStreamWithAuth")
 }
 
@@ -468,7 +736,11 @@ func (p *OpenAIProvider) executeStreamWithAuth(ctx context.Context, requestData
 func (p *OpenAIProvider) buildOpenAIRequest(options types.GenerateOptions) OpenAIRequest {
 	// Determine which model to use with fallback priority
 	config := p.GetConfig()
-	model := common.ResolveModel(options.Model, config.DefaultModel, openAIFallbackModel)
+	fallbackModel := openAIFallbackModel
+	if p.api.Type != types.ProviderTypeOpenAI {
+		fallbackModel = p.api.DefaultModel
+	}
+	model := common.ResolveModel(options.Model, config.DefaultModel, fallbackModel)
 
 	// Convert messages to OpenAI format
 	var messages []OpenAIMessage
@@ -511,13 +783,29 @@ func (p *OpenAIProvider) buildOpenAIRequest(options types.GenerateOptions) OpenA
 		})
 	}
 
+	// Zero is omitted so OpenAI allows up to the model's output limit; requested values
+	// are capped at that limit
+	maxTokens := options.MaxTokens
+	if maxTokens > 0 {
+		maxTokens = common.ResolveMaxTokens(p.Type(), model, maxTokens)
+	}
+
 	request := OpenAIRequest{
 		Model:       model,
 		Messages:    messages,
-		MaxTokens:   options.MaxTokens,
+		MaxTokens:   maxTokens,
 		Temperature: options.Temperature,
 		Stream:      options.Stream,
 	}
+	if options.N > 1 {
+		request.N = options.N
+	}
+
+	// One key per logical request, so key failover retries are deduplicated server-side
+	request.IdempotencyKey = options.IdempotencyKey
+	if request.IdempotencyKey == "" {
+		request.IdempotencyKey = middleware.NewIdempotencyKey()
+	}
 
 	// Convert tools if provided
 	if len(options.Tools) > 0 {
@@ -528,6 +816,13 @@ func (p *OpenAIProvider) buildOpenAIRequest(options types.GenerateOptions) OpenA
 			request.ToolChoice = convertToOpenAIToolChoice(options.ToolChoice)
 		}
 		// Otherwise, ToolChoice defaults to "auto" (OpenAI's default behavior)
+
+		// OpenAI rejects parallel_tool_calls without tools
+		request.ParallelToolCalls = options.ParallelToolCalls
+	}
+
+	if p.toolFormat(model) == types.ToolFormatOpenAIFunctions {
+		useLegacyFunctionCalling(&request, options)
 	}
 
 	// Handle structured outputs via ResponseFormat
@@ -552,21 +847,35 @@ func (p *OpenAIProvider) buildOpenAIRequest(options types.GenerateOptions) OpenA
 	return request
 }
 
+// endpointURL builds the URL of an API operation from the base URL and path template
+func (p *OpenAIProvider) endpointURL(path, operation string) (string, error) {
+	url, err := commonconfig.BuildEndpointURL(p.baseURL, p.pathTemplate, path)
+	if err != nil {
+		return "", types.NewInvalidRequestError(p.Type(), "invalid endpoint configuration: "+err.Error()).
+			WithOperation(operation).
+			WithOriginalErr(err)
+	}
+	return url, nil
+}
+
 // makeAPICall makes a single API call to OpenAI
 func (p *OpenAIProvider) makeAPICall(ctx context.Context, requestData OpenAIRequest, apiKey string) (types.ChatMessage, *types.Usage, error) {
 	// Serialize request
 	jsonBody, err := json.Marshal(requestData)
 	if err != nil {
-		return types.ChatMessage{}, nil, types.NewInvalidRequestError(types.ProviderTypeOpenAI, "failed to marshal request").
+		return types.ChatMessage{}, nil, types.NewInvalidRequestError(p.Type(), "failed to marshal request").
 			WithOperation("makeAPICall").
 			WithOriginalErr(err)
 	}
 
 	// Create HTTP request
-	url := p.baseURL + "/chat/completions"
+	url, err := p.endpointURL("chat/completions", "makeAPICall")
+	if err != nil {
+		return types.ChatMessage{}, nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return types.ChatMessage{}, nil, types.NewNetworkError(types.ProviderTypeOpenAI, "failed to create request").
+		return types.ChatMessage{}, nil, types.NewNetworkError(p.Type(), "failed to create request").
 			WithOperation("makeAPICall").
 			WithOriginalErr(err)
 	}
@@ -575,6 +884,9 @@ func (p *OpenAIProvider) makeAPICall(ctx context.Context, requestData OpenAIRequ
 	req.Header.Set("Content-Type", "application/json")
 	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
 	p.authHelper.SetProviderSpecificHeaders(req)
+	if requestData.IdempotencyKey != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, requestData.IdempotencyKey)
+	}
 
 	// Log request (for debugging)
 	p.LogRequest("POST", url, map[string]string{
@@ -585,16 +897,15 @@ func (p *OpenAIProvider) makeAPICall(ctx context.Context, requestData OpenAIRequ
 	// Make the request
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return types.ChatMessage{}, nil, types.NewNetworkError(types.ProviderTypeOpenAI, "request failed").
-			WithOperation("makeAPICall").
-			WithOriginalErr(err)
+		return types.ChatMessage{}, nil, types.NewRequestError(p.Type(), "request failed", err).
+			WithOperation("makeAPICall")
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Read response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return types.ChatMessage{}, nil, types.NewNetworkError(types.ProviderTypeOpenAI, "failed to read response body").
+		return types.ChatMessage{}, nil, types.NewNetworkError(p.Type(), "failed to read response body").
 			WithOperation("makeAPICall").
 			WithOriginalErr(err)
 	}
@@ -609,36 +920,41 @@ func (p *OpenAIProvider) makeAPICall(ctx context.Context, requestData OpenAIRequ
 			// Handle specific error types
 			switch errorResponse.Error.Type {
 			case "invalid_api_key":
-				return types.ChatMessage{}, nil, types.NewAuthError(types.ProviderTypeOpenAI, "invalid OpenAI API key").
+				return types.ChatMessage{}, nil, types.NewAuthError(p.Type(), fmt.Sprintf("invalid %s API key", p.Name())).
 					WithOperation("makeAPICall").
 					WithStatusCode(resp.StatusCode)
 			case "insufficient_quota":
 				return types.ChatMessage{}, nil, &types.ProviderError{
 					Code:       types.ErrCodeRateLimit,
-					Message:    "OpenAI quota exceeded",
-					Provider:   types.ProviderTypeOpenAI,
+					Message:    p.Name() + " quota exceeded",
+					Provider:   p.Type(),
 					StatusCode: resp.StatusCode,
 					Operation:  "makeAPICall",
 				}
 			case "rate_limit_exceeded":
-				return types.ChatMessage{}, nil, types.NewRateLimitError(types.ProviderTypeOpenAI, 0).
-					WithOperation("makeAPICall").
-					WithStatusCode(resp.StatusCode).
-					WithOriginalErr(fmt.Errorf("OpenAI rate limit exceeded"))
+				return types.ChatMessage{}, nil, p.newRateLimitError(resp, "makeAPICall").
+					WithOriginalErr(fmt.Errorf("%s rate limit exceeded", p.Name()))
 			case "model_not_found":
-				return types.ChatMessage{}, nil, types.NewNotFoundError(types.ProviderTypeOpenAI, fmt.Sprintf("OpenAI model not found: %s", errorResponse.Error.Message)).
+				return types.ChatMessage{}, nil, types.NewNotFoundError(p.Type(), fmt.Sprintf("%s model not found: %s", p.Name(), errorResponse.Error.Message)).
 					WithOperation("makeAPICall").
 					WithStatusCode(resp.StatusCode)
 			case "invalid_request_error":
-				return types.ChatMessage{}, nil, types.NewInvalidRequestError(types.ProviderTypeOpenAI, fmt.Sprintf("invalid OpenAI request: %s", errorResponse.Error.Message)).
+				return types.ChatMessage{}, nil, types.NewInvalidRequestError(p.Type(), fmt.Sprintf("invalid %s request: %s", p.Name(), errorResponse.Error.Message)).
 					WithOperation("makeAPICall").
 					WithStatusCode(resp.StatusCode)
 			default:
-				return types.ChatMessage{}, nil, types.NewServerError(types.ProviderTypeOpenAI, resp.StatusCode, fmt.Sprintf("OpenAI API error (%s): %s", errorResponse.Error.Type, errorResponse.Error.Message)).
+				if resp.StatusCode == http.StatusTooManyRequests {
+					return types.ChatMessage{}, nil, p.newRateLimitError(resp, "makeAPICall").
+						WithOriginalErr(fmt.Errorf("%s API error (%s): %s", p.Name(), errorResponse.Error.Type, errorResponse.Error.Message))
+				}
+				return types.ChatMessage{}, nil, types.NewServerError(p.Type(), resp.StatusCode, fmt.Sprintf("%s API error (%s): %s", p.Name(), errorResponse.Error.Type, errorResponse.Error.Message)).
 					WithOperation("makeAPICall")
 			}
 		}
-		return types.ChatMessage{}, nil, types.NewServerError(types.ProviderTypeOpenAI, resp.StatusCode, fmt.Sprintf("OpenAI API error: %s", string(body))).
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return types.ChatMessage{}, nil, p.newRateLimitError(resp, "makeAPICall")
+		}
+		return types.ChatMessage{}, nil, types.NewServerError(p.Type(), resp.StatusCode, fmt.Sprintf("%s API error: %s", p.Name(), string(body))).
 			WithOperation("makeAPICall")
 	}
 
@@ -652,15 +968,51 @@ func (p *OpenAIProvider) makeAPICall(ctx context.Context, requestData OpenAIRequ
 		return types.ChatMessage{}, nil, fmt.Errorf("no choices in API response")
 	}
 
-	// Extract message from response
-	openaiMsg := response.Choices[0].Message
+	message := convertOpenAIResponseMessage(response.Choices[0].Message, p.api.SeparateReasoning)
+
+	// Carried to the response chunk by GenerateChatCompletion
+	message.Metadata = map[string]interface{}{}
+	if finishReason := response.Choices[0].FinishReason; finishReason != "" {
+		message.Metadata[metadataKeyFinishReason] = finishReason
+	}
+	if len(response.Choices) > 1 {
+		extraChoices := make([]types.ChatChoice, 0, len(response.Choices)-1)
+		for _, choice := range response.Choices[1:] {
+			extraChoices = append(extraChoices, types.ChatChoice{
+				Index:           choice.Index,
+				Message:         convertOpenAIResponseMessage(choice.Message, p.api.SeparateReasoning),
+				FinishReason:    types.NormalizeFinishReason(p.Type(), choice.FinishReason),
+				RawFinishReason: choice.FinishReason,
+			})
+		}
+		message.Metadata[metadataKeyExtraChoices] = extraChoices
+	}
+	warnings := types.WarningsFromHeaders(resp.Header)
+	if warning := types.ModelSubstitutionWarning(requestData.Model, response.Model); warning != nil {
+		warnings = append(warnings, *warning)
+	}
+	if len(warnings) > 0 {
+		message.Metadata[metadataKeyWarnings] = warnings
+	}
+	if len(message.Metadata) == 0 {
+		message.Metadata = nil
+	}
 
+	// Convert usage
+	usage := response.Usage.toUsage()
+
+	return message, &usage, nil
+}
+
+// convertOpenAIResponseMessage converts a response choice's message to the universal
+// format. Unless separateReasoning is set, reasoning stands in for empty content.
+func convertOpenAIResponseMessage(openaiMsg OpenAIMessage, separateReasoning bool) types.ChatMessage {
 	// Determine effective content - fallback to reasoning fields if content is empty
 	effectiveContent := ""
 	if contentStr, ok := openaiMsg.Content.(string); ok {
 		effectiveContent = contentStr
 	}
-	if effectiveContent == "" || effectiveContent == "\n" {
+	if !separateReasoning && (effectiveContent == "" || effectiveContent == "\n") {
 		// Try reasoning_content first (vLLM/Synthetic style)
 		if openaiMsg.ReasoningContent != "" {
 			effectiveContent = openaiMsg.ReasoningContent
@@ -682,15 +1034,49 @@ func (p *OpenAIProvider) makeAPICall(ctx context.Context, requestData OpenAIRequ
 	if len(openaiMsg.ToolCalls) > 0 {
 		message.ToolCalls = convertOpenAIToolCallsToUniversal(openaiMsg.ToolCalls)
 	}
+	if len(openaiMsg.ToolCalls) == 0 && openaiMsg.FunctionCall != nil {
+		message.ToolCalls = []types.ToolCall{common.LegacyFunctionCallToToolCall(*openaiMsg.FunctionCall)}
+	}
+	message.Citations = convertOpenAIAnnotations(openaiMsg.Annotations, effectiveContent)
+	return message
+}
 
-	// Convert usage
-	usage := &types.Usage{
-		PromptTokens:     response.Usage.PromptTokens,
-		CompletionTokens: response.Usage.CompletionTokens,
-		TotalTokens:      response.Usage.TotalTokens,
+// convertOpenAIAnnotations returns the url_citation annotations of a message as
+// citations, with their character indexes into content converted to byte offsets
+func convertOpenAIAnnotations(annotations []OpenAIAnnotation, content string) []types.Citation {
+	var citations []types.Citation
+	for _, annotation := range annotations {
+		if annotation.Type != "url_citation" || annotation.URLCitation == nil {
+			continue
+		}
+		source := annotation.URLCitation
+		citation := types.Citation{Title: source.Title, URL: source.URL}
+		start, startOK := byteOffset(content, source.StartIndex)
+		end, endOK := byteOffset(content, source.EndIndex)
+		if startOK && endOK && start < end {
+			citation.Text = content[start:end]
+			citation.StartIndex = start
+			citation.EndIndex = end
+		}
+		citations = append(citations, citation)
 	}
+	return citations
+}
 
-	return message, usage, nil
+// byteOffset returns the byte offset of the character at index chars of s, reporting
+// false when s is shorter
+func byteOffset(s string, chars int) (int, bool) {
+	if chars < 0 {
+		return 0, false
+	}
+	count := 0
+	for offset := range s {
+		if count == chars {
+			return offset, true
+		}
+		count++
+	}
+	return len(s), count == chars
 }
 
 // makeStreamingAPICall makes a streaming API call to OpenAI
@@ -698,15 +1084,18 @@ func (p *OpenAIProvider) makeStreamingAPICall(ctx context.Context, requestData O
 	requestData.Stream = true
 	jsonBody, err := json.Marshal(requestData)
 	if err != nil {
-		return nil, types.NewInvalidRequestError(types.ProviderTypeOpenAI, "failed to marshal request").
+		return nil, types.NewInvalidRequestError(p.Type(), "failed to marshal request").
 			WithOperation("makeStreamingAPICall").
 			WithOriginalErr(err)
 	}
 
-	url := p.baseURL + "/chat/completions"
+	url, err := p.endpointURL("chat/completions", "makeStreamingAPICall")
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOpenAI, "failed to create request").
+		return nil, types.NewNetworkError(p.Type(), "failed to create request").
 			WithOperation("makeStreamingAPICall").
 			WithOriginalErr(err)
 	}
@@ -714,12 +1103,14 @@ func (p *OpenAIProvider) makeStreamingAPICall(ctx context.Context, requestData O
 	req.Header.Set("Content-Type", "application/json")
 	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
 	p.authHelper.SetProviderSpecificHeaders(req)
+	if requestData.IdempotencyKey != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, requestData.IdempotencyKey)
+	}
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, types.NewNetworkError(types.ProviderTypeOpenAI, "request failed").
-			WithOperation("makeStreamingAPICall").
-			WithOriginalErr(err)
+		return nil, types.NewRequestError(p.Type(), "request failed", err).
+			WithOperation("makeStreamingAPICall")
 	}
 
 	// Parse and update rate limit info from response headers
@@ -728,13 +1119,36 @@ func (p *OpenAIProvider) makeStreamingAPICall(ctx context.Context, requestData O
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		func() { _ = resp.Body.Close() }()
-		return nil, types.NewServerError(types.ProviderTypeOpenAI, resp.StatusCode, fmt.Sprintf("OpenAI API error: %s", string(body))).
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, p.newRateLimitError(resp, "makeStreamingAPICall").
+				WithOriginalErr(fmt.Errorf("%s API error: %s", p.Name(), string(body)))
+		}
+		return nil, types.NewServerError(p.Type(), resp.StatusCode, fmt.Sprintf("%s API error: %s", p.Name(), string(body))).
 			WithOperation("makeStreamingAPICall")
 	}
 
 	// Use the shared streaming utility
-	stream := streaming.CreateOpenAIStream(resp)
-	return streaming.StreamFromContext(ctx, stream), nil
+	var stream types.ChatCompletionStream
+	switch {
+	case p.api.SeparateReasoning:
+		parser := streaming.NewStandardStreamParser()
+		parser.AwaitUsage = requestData.StreamOptions != nil && requestData.StreamOptions.IncludeUsage
+		parser.SeparateReasoning = true
+		stream = streaming.CreateCustomStream(resp, parser)
+	case requestData.StreamOptions != nil && requestData.StreamOptions.IncludeUsage:
+		stream = streaming.CreateOpenAIUsageStream(resp)
+	default:
+		stream = streaming.CreateOpenAIStream(resp)
+	}
+	return types.StreamWithWarnings(streaming.StreamFromContext(ctx, stream), types.WarningsFromHeaders(resp.Header)), nil
+}
+
+// newRateLimitError builds a rate limit error carrying the response's retry hint
+func (p *OpenAIProvider) newRateLimitError(resp *http.Response, operation string) *types.ProviderError {
+	err := types.NewRateLimitError(p.Type(), 0).
+		WithOperation(operation).
+		WithStatusCode(resp.StatusCode)
+	return err.WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
 }
 
 // InvokeServerTool invokes a server tool (not yet implemented)
@@ -743,14 +1157,14 @@ func (p *OpenAIProvider) InvokeServerTool(
 	toolName string,
 	params interface{},
 ) (interface{}, error) {
-	return nil, types.NewInvalidRequestError(types.ProviderTypeOpenAI, "tool invocation not yet implemented for OpenAI provider").
+	return nil, types.NewInvalidRequestError(p.Type(), fmt.Sprintf("tool invocation not yet implemented for %s provider", p.Name())).
 		WithOperation("InvokeTool")
 }
 
 func (p *OpenAIProvider) Authenticate(ctx context.Context, authConfig types.AuthConfig) error {
 	// OpenAI only supports API key authentication
 	if authConfig.Method != types.AuthMethodAPIKey {
-		return fmt.Errorf("OpenAI only supports API key authentication")
+		return fmt.Errorf("%s only supports API key authentication", p.Name())
 	}
 
 	// Update config with new authentication, preserving capability flags
@@ -790,7 +1204,7 @@ func (p *OpenAIProvider) Logout(ctx context.Context) error {
 
 func (p *OpenAIProvider) Configure(config types.ProviderConfig) error {
 	// Use the shared config helper for validation and extraction
-	configHelper := commonconfig.NewConfigHelper("OpenAI", types.ProviderTypeOpenAI)
+	configHelper := commonconfig.NewConfigHelper(p.api.Name, p.api.Type)
 
 	// Validate configuration
 	validation := configHelper.ValidateProviderConfig(config)
@@ -803,11 +1217,14 @@ func (p *OpenAIProvider) Configure(config types.ProviderConfig) error {
 
 	// Extract configuration using helper
 	p.baseURL = configHelper.ExtractBaseURL(mergedConfig)
+	p.pathTemplate = mergedConfig.PathTemplate
 	p.organizationID = configHelper.ExtractStringField(mergedConfig, "organization_id", "")
+	p.streamIncludeUsage = configStreamIncludeUsage(mergedConfig)
+	p.streamResponsesAPI = configStreamResponsesAPI(mergedConfig)
 
 	// Handle capability flags properly - preserve existing values for minimal configs
 	// If this appears to be a minimal config (only auth changes), preserve existing flags
-	isMinimalConfig := config.Type == types.ProviderTypeOpenAI &&
+	isMinimalConfig := config.Type == p.api.Type &&
 		config.BaseURL == "" &&
 		config.DefaultModel == "" &&
 		!config.SupportsToolCalling &&
@@ -851,7 +1268,19 @@ func (p *OpenAIProvider) SupportsResponsesAPI() bool {
 }
 
 func (p *OpenAIProvider) GetToolFormat() types.ToolFormat {
-	return types.ToolFormatOpenAI
+	return p.toolFormat("")
+}
+
+// Warmup fetches the model list from the models endpoint into the model cache, so the
+// first request does not wait for it
+func (p *OpenAIProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
+// RateLimitStatus returns the rate limits reported by the most recent response, see
+// types.RateLimitStatusProvider
+func (p *OpenAIProvider) RateLimitStatus() types.RateLimitStatus {
+	return p.rateLimitHelper.Status()
 }
 
 // TestConnectivity performs a lightweight connectivity test using the /v1/models endpoint
@@ -866,7 +1295,7 @@ func (p *OpenAIProvider) TestConnectivity(ctx context.Context) error {
 func (p *OpenAIProvider) TestConnectivityWithOptions(ctx context.Context, bypassCache bool) error {
 	return p.connectivityCache.TestConnectivity(
 		ctx,
-		types.ProviderTypeOpenAI,
+		p.Type(),
 		p.performConnectivityTest,
 		bypassCache,
 	)
@@ -876,7 +1305,7 @@ func (p *OpenAIProvider) TestConnectivityWithOptions(ctx context.Context, bypass
 func (p *OpenAIProvider) performConnectivityTest(ctx context.Context) error {
 	// Check if we have API keys configured
 	if p.authHelper.KeyManager == nil || len(p.authHelper.KeyManager.GetKeys()) == 0 {
-		return types.NewAuthError(types.ProviderTypeOpenAI, "no API keys configured").
+		return types.NewAuthError(p.Type(), "no API keys configured").
 			WithOperation("test_connectivity")
 	}
 
@@ -885,10 +1314,13 @@ func (p *OpenAIProvider) performConnectivityTest(ctx context.Context) error {
 	apiKey := keys[0]
 
 	// Create a request to the /v1/models endpoint
-	url := p.baseURL + "/models"
+	url, err := p.endpointURL("models", "test_connectivity")
+	if err != nil {
+		return err
+	}
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOpenAI, "failed to create connectivity test request").
+		return types.NewNetworkError(p.Type(), "failed to create connectivity test request").
 			WithOperation("test_connectivity").
 			WithOriginalErr(err)
 	}
@@ -903,28 +1335,27 @@ func (p *OpenAIProvider) performConnectivityTest(ctx context.Context) error {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOpenAI, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(p.Type(), "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Check response status
 	if resp.StatusCode == http.StatusUnauthorized {
-		return types.NewAuthError(types.ProviderTypeOpenAI, "invalid API key").
+		return types.NewAuthError(p.Type(), "invalid API key").
 			WithOperation("test_connectivity").
 			WithStatusCode(resp.StatusCode)
 	}
 
 	if resp.StatusCode == http.StatusForbidden {
-		return types.NewAuthError(types.ProviderTypeOpenAI, "API key does not have access to models endpoint").
+		return types.NewAuthError(p.Type(), "API key does not have access to models endpoint").
 			WithOperation("test_connectivity").
 			WithStatusCode(resp.StatusCode)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return types.NewServerError(types.ProviderTypeOpenAI, resp.StatusCode,
+		return types.NewServerError(p.Type(), resp.StatusCode,
 			fmt.Sprintf("connectivity test failed: %s", string(body))).
 			WithOperation("test_connectivity")
 	}
@@ -932,7 +1363,7 @@ func (p *OpenAIProvider) performConnectivityTest(ctx context.Context) error {
 	// Read entire response to support providers with many models (e.g., Groq with 20+ models)
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeOpenAI, "failed to read response body").
+		return types.NewNetworkError(p.Type(), "failed to read response body").
 			WithOperation("test_connectivity").
 			WithOriginalErr(err)
 	}
@@ -941,7 +1372,7 @@ func (p *OpenAIProvider) performConnectivityTest(ctx context.Context) error {
 		Data []interface{} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &testResponse); err != nil {
-		return types.NewInvalidRequestError(types.ProviderTypeOpenAI, "invalid response from models endpoint").
+		return types.NewInvalidRequestError(p.Type(), "invalid response from models endpoint").
 			WithOperation("test_connectivity").
 			WithOriginalErr(err)
 	}
@@ -988,8 +1419,9 @@ func convertOpenAIToolCallsToUniversal(toolCalls []OpenAIToolCall) []types.ToolC
 	universal := make([]types.ToolCall, len(toolCalls))
 	for i, tc := range toolCalls {
 		universal[i] = types.ToolCall{
-			ID:   tc.ID,
-			Type: tc.Type,
+			ID:    tc.ID,
+			Type:  tc.Type,
+			Index: tc.Index,
 			Function: types.ToolCallFunction{
 				Name:      tc.Function.Name,
 				Arguments: tc.Function.Arguments,
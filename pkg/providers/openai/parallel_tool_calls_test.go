@@ -0,0 +1,51 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_ParallelToolCalls(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = nil
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"gpt-4o","choices":[
+			{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+	tools := []types.Tool{{Name: "get_weather", Description: "Get the weather", InputSchema: map[string]interface{}{"type": "object"}}}
+
+	generate := func(builder *types.OptionsBuilder) {
+		t.Helper()
+		options, err := builder.WithModel("gpt-4o").WithPrompt("Weather?").Build()
+		require.NoError(t, err)
+		stream, err := provider.GenerateChatCompletion(context.Background(), options)
+		require.NoError(t, err)
+		_ = stream.Close()
+	}
+
+	generate(types.NewOptionsBuilder().WithTools(tools).WithParallelToolCalls(false))
+	assert.Equal(t, false, sent["parallel_tool_calls"])
+
+	generate(types.NewOptionsBuilder().WithTools(tools))
+	assert.NotContains(t, sent, "parallel_tool_calls", "unset leaves the provider default")
+
+	generate(types.NewOptionsBuilder().WithParallelToolCalls(false))
+	assert.NotContains(t, sent, "parallel_tool_calls", "not sent without tools")
+}
@@ -0,0 +1,30 @@
+package openai
+
+import (
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// SupportedParameters implements types.ParameterSupportReporter. Reasoning models,
+// such as the o-series and GPT-5, reject temperature, top_p, the penalties and
+// parallel_tool_calls, and only they take reasoning_effort. Only OpenAI itself keeps
+// stored completions.
+func (p *OpenAIProvider) SupportedParameters(model string) types.ParameterSupport {
+	if model == "" {
+		model = p.GetDefaultModel()
+	}
+	support := types.AllParametersSupported
+	support.Tools = types.ProviderCapabilities(p, model).Tools
+	support.Store = p.api.Type == types.ProviderTypeOpenAI
+
+	if models.IsReasoningModel(p.api.Type, model) {
+		support.Temperature = false
+		support.TopP = false
+		support.FrequencyPenalty = false
+		support.PresencePenalty = false
+		support.ParallelToolCalls = false
+	} else {
+		support.ReasoningEffort = false
+	}
+	return support
+}
@@ -0,0 +1,29 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAIProvider_SupportedParameters(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI, APIKey: "sk-test"})
+
+	reasoning := provider.SupportedParameters("o1")
+	assert.False(t, reasoning.Temperature)
+	assert.False(t, reasoning.TopP)
+	assert.False(t, reasoning.FrequencyPenalty)
+	assert.False(t, reasoning.PresencePenalty)
+	assert.False(t, reasoning.ParallelToolCalls)
+	assert.True(t, reasoning.ReasoningEffort)
+	assert.True(t, reasoning.Tools)
+
+	standard := provider.SupportedParameters("gpt-4o")
+	assert.True(t, standard.Temperature)
+	assert.True(t, standard.ParallelToolCalls)
+	assert.False(t, standard.ReasoningEffort)
+
+	// Reported through the provider-agnostic lookup, for the default model when none is given
+	assert.Equal(t, standard, types.SupportedParameters(provider, ""))
+}
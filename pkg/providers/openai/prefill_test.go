@@ -0,0 +1,43 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_PrefillDroppedWithWarning(t *testing.T) {
+	var sent OpenAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"{\"a\":1}"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	options := types.GenerateOptions{Model: "gpt-4o", Prompt: "Reply in JSON"}.WithPrefill("{")
+	stream, err := provider.GenerateChatCompletion(context.Background(), options)
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	require.Len(t, sent.Messages, 1)
+	assert.Equal(t, "user", sent.Messages[0].Role)
+
+	require.Len(t, chunk.Warnings, 1)
+	assert.Equal(t, types.WarningParameterIgnored, chunk.Warnings[0].Code)
+	assert.Equal(t, "messages", chunk.Warnings[0].Param)
+}
@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_RateLimitRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-reset-requests", "7s")
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi"})
+	require.Error(t, err)
+
+	var rateLimitErr *types.RateLimitError
+	require.True(t, errors.As(err, &rateLimitErr), "expected RateLimitError, got %v", err)
+	assert.Equal(t, 7*time.Second, rateLimitErr.RetryAfter)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitErr.StatusCode)
+}
+
+func TestOpenAIProvider_RateLimitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.Header().Set("x-ratelimit-reset-requests", "7s")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+	assert.False(t, provider.RateLimitStatus().Known, "no headers seen yet")
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "hi", Model: "gpt-4o"})
+	require.Error(t, err)
+
+	status := types.ProviderRateLimitStatus(provider)
+	require.True(t, status.Known)
+	assert.Equal(t, "gpt-4o", status.Model)
+	assert.Equal(t, 500, status.RequestsLimit)
+	assert.Equal(t, 0, status.RequestsRemaining)
+	assert.True(t, status.Limited(time.Now()))
+	assert.InDelta(t, 7*time.Second, status.WaitTime(time.Now()), float64(time.Second))
+}
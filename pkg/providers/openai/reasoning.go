@@ -0,0 +1,59 @@
+package openai
+
+import (
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// reasoningEffort returns the reasoning_effort to send for model. Models that do not
+// reason reject the parameter, so for them the effort is dropped and a warning returned.
+func reasoningEffort(providerType types.ProviderType, model string, effort types.ReasoningEffort) (string, *types.Warning) {
+	if effort == "" {
+		return "", nil
+	}
+	if !models.IsReasoningModel(providerType, model) {
+		return "", &types.Warning{
+			Code:    types.WarningParameterIgnored,
+			Message: fmt.Sprintf("model %s is not a reasoning model, reasoning_effort was not sent", model),
+			Param:   "reasoning_effort",
+		}
+	}
+	return string(effort), nil
+}
+
+// prepareReasoningRequest adapts a request for OpenAI's reasoning models, which take
+// max_completion_tokens instead of max_tokens and reject temperature other than 1,
+// top_p and parallel_tool_calls. Those parameters are dropped, with a warning for each.
+// Requests for other models are unchanged.
+func prepareReasoningRequest(request *OpenAIRequest) []types.Warning {
+	if !models.IsReasoningModel(types.ProviderTypeOpenAI, request.Model) {
+		return nil
+	}
+	request.MaxCompletionTokens, request.MaxTokens = request.MaxTokens, 0
+
+	var warnings []types.Warning
+	ignore := func(param string) {
+		warnings = append(warnings, types.Warning{
+			Code:    types.WarningParameterIgnored,
+			Message: fmt.Sprintf("reasoning model %s does not accept %s, it was not sent", request.Model, param),
+			Param:   param,
+		})
+	}
+	if request.Temperature != 0 {
+		if request.Temperature != 1 { // The only value accepted, and the default
+			ignore("temperature")
+		}
+		request.Temperature = 0
+	}
+	if request.TopP != 0 {
+		ignore("top_p")
+		request.TopP = 0
+	}
+	if request.ParallelToolCalls != nil {
+		ignore("parallel_tool_calls")
+		request.ParallelToolCalls = nil
+	}
+	return warnings
+}
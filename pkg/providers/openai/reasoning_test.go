@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_ReasoningEffort(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = nil
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"` + sent["model"].(string) + `","choices":[
+			{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	generate := func(model string, effort types.ReasoningEffort) (types.ChatCompletionChunk, error) {
+		t.Helper()
+		stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+			Model:           model,
+			Prompt:          "Solve this",
+			ReasoningEffort: effort,
+		})
+		if err != nil {
+			return types.ChatCompletionChunk{}, err
+		}
+		defer func() { _ = stream.Close() }()
+		return stream.Next()
+	}
+
+	chunk, err := generate("o3-mini", types.ReasoningEffortHigh)
+	require.NoError(t, err)
+	assert.Equal(t, "high", sent["reasoning_effort"])
+	assert.Empty(t, chunk.Warnings)
+
+	chunk, err = generate("gpt-4o", types.ReasoningEffortHigh)
+	require.NoError(t, err)
+	assert.NotContains(t, sent, "reasoning_effort", "non-reasoning models reject the parameter")
+	require.Len(t, chunk.Warnings, 1)
+	assert.Equal(t, types.WarningParameterIgnored, chunk.Warnings[0].Code)
+	assert.Equal(t, "reasoning_effort", chunk.Warnings[0].Param)
+
+	_, err = generate("o3-mini", "")
+	require.NoError(t, err)
+	assert.NotContains(t, sent, "reasoning_effort", "unset leaves the model default")
+
+	_, err = generate("o3-mini", "maximum")
+	assert.True(t, types.IsValidationError(err))
+}
+
+func TestOpenAIProvider_ReasoningModelParameters(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = nil
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"` + sent["model"].(string) + `","choices":[
+			{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	parallel := false
+	generate := func(model string, temperature float64) types.ChatCompletionChunk {
+		t.Helper()
+		stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+			Model:             model,
+			Prompt:            "Solve this",
+			MaxTokens:         1000,
+			Temperature:       temperature,
+			Tools:             []types.Tool{{Name: "calculator", InputSchema: map[string]interface{}{"type": "object"}}},
+			ParallelToolCalls: &parallel,
+		})
+		require.NoError(t, err)
+		defer func() { _ = stream.Close() }()
+		chunk, err := stream.Next()
+		require.NoError(t, err)
+		return chunk
+	}
+
+	chunk := generate("o3-mini", 0.7)
+	assert.NotContains(t, sent, "temperature")
+	assert.NotContains(t, sent, "parallel_tool_calls")
+	assert.NotContains(t, sent, "max_tokens")
+	assert.Equal(t, float64(1000), sent["max_completion_tokens"])
+	require.Len(t, chunk.Warnings, 2)
+	assert.Equal(t, "temperature", chunk.Warnings[0].Param)
+	assert.Equal(t, "parallel_tool_calls", chunk.Warnings[1].Param)
+	assert.Equal(t, types.WarningParameterIgnored, chunk.Warnings[0].Code)
+
+	// Temperature 1 is the reasoning models' default, so dropping it changes nothing
+	chunk = generate("o3-mini", 1)
+	assert.NotContains(t, sent, "temperature")
+	require.Len(t, chunk.Warnings, 1)
+	assert.Equal(t, "parallel_tool_calls", chunk.Warnings[0].Param)
+
+	chunk = generate("gpt-4o", 0.7)
+	assert.Equal(t, 0.7, sent["temperature"])
+	assert.Equal(t, false, sent["parallel_tool_calls"])
+	assert.Equal(t, float64(1000), sent["max_tokens"])
+	assert.NotContains(t, sent, "max_completion_tokens")
+	assert.Empty(t, chunk.Warnings)
+}
+
+func TestPrepareReasoningRequest_TopP(t *testing.T) {
+	request := OpenAIRequest{Model: "o1", TopP: 0.9}
+	warnings := prepareReasoningRequest(&request)
+	assert.Zero(t, request.TopP)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "top_p", warnings[0].Param)
+
+	request = OpenAIRequest{Model: "gpt-4o", TopP: 0.9}
+	assert.Empty(t, prepareReasoningRequest(&request))
+	assert.Equal(t, 0.9, request.TopP)
+}
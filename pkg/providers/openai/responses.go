@@ -0,0 +1,451 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// responseIDPrefix starts the IDs of responses stored by the Responses API. Chat
+// completion IDs ("chatcmpl-...") cannot be continued.
+const responseIDPrefix = "resp_"
+
+// OpenAIResponsesRequest is a Responses API request, starting a response or continuing
+// a stored one
+type OpenAIResponsesRequest struct {
+	Model              string                    `json:"model"`
+	PreviousResponseID string                    `json:"previous_response_id,omitempty"`
+	Input              []interface{}             `json:"input"` // Of OpenAIResponsesMessage, OpenAIResponsesFunctionCall and OpenAIResponsesInput
+	Tools              []OpenAIResponsesTool     `json:"tools,omitempty"`
+	ToolChoice         interface{}               `json:"tool_choice,omitempty"`
+	ParallelToolCalls  *bool                     `json:"parallel_tool_calls,omitempty"`
+	MaxOutputTokens    int                       `json:"max_output_tokens,omitempty"`
+	Temperature        float64                   `json:"temperature,omitempty"`
+	TopP               float64                   `json:"top_p,omitempty"`
+	Reasoning          *OpenAIResponsesReasoning `json:"reasoning,omitempty"`
+	Text               *OpenAIResponsesText      `json:"text,omitempty"`
+	Store              *bool                     `json:"store,omitempty"`
+	Metadata           map[string]string         `json:"metadata,omitempty"`
+	Stream             bool                      `json:"stream"`
+}
+
+// OpenAIResponsesMessage is a message item of a Responses API request
+type OpenAIResponsesMessage struct {
+	Type    string      `json:"type"` // Always "message"
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"` // A string, or []OpenAIResponsesContentPart with images
+}
+
+// OpenAIResponsesContentPart is a part of a multimodal message of a Responses API request
+type OpenAIResponsesContentPart struct {
+	Type     string `json:"type"` // "input_text" or "input_image"
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"` // URL or data URL
+}
+
+// OpenAIResponsesText configures the text output of a response
+type OpenAIResponsesText struct {
+	// Format is the chat completions response_format, with the json_schema object
+	// flattened into it
+	Format map[string]interface{} `json:"format"`
+}
+
+// OpenAIResponsesFunctionCall is a function call item of a Responses API request, an
+// earlier tool call of the assistant
+type OpenAIResponsesFunctionCall struct {
+	Type      string `json:"type"` // Always "function_call"
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// OpenAIResponsesInput is a function call output item of a Responses API request
+type OpenAIResponsesInput struct {
+	Type   string `json:"type"`
+	CallID string `json:"call_id"`
+	Output string `json:"output"`
+}
+
+// OpenAIResponsesTool is a function tool of a Responses API request
+type OpenAIResponsesTool struct {
+	Type        string                 `json:"type"` // Always "function"
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// OpenAIResponsesReasoning configures the reasoning of a reasoning model
+type OpenAIResponsesReasoning struct {
+	Effort string `json:"effort"`
+}
+
+// OpenAIResponsesEvent is a Responses API streaming event
+type OpenAIResponsesEvent struct {
+	Type     string                    `json:"type"`
+	Delta    string                    `json:"delta"`
+	Item     *OpenAIResponsesItem      `json:"item"`
+	Response *OpenAIResponsesResponse  `json:"response"`
+	Message  string                    `json:"message"` // Set on "error" events
+	Error    *OpenAIResponsesErrorBody `json:"error"`
+}
+
+// OpenAIResponsesItem is an output item of a response
+type OpenAIResponsesItem struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// OpenAIResponsesResponse is the response object carried by lifecycle events
+type OpenAIResponsesResponse struct {
+	ID     string                    `json:"id"`
+	Model  string                    `json:"model"`
+	Status string                    `json:"status"`
+	Error  *OpenAIResponsesErrorBody `json:"error"`
+	Usage  *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// OpenAIResponsesErrorBody is the error of a failed response
+type OpenAIResponsesErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// newResponsesRequest converts a chat completions request, built from options by
+// buildOpenAIRequest, to a streaming Responses API request. Messages are sent as text,
+// with images as input_image parts; assistant tool calls and tool results become
+// function call and output items. Settings the Responses API has no equivalent for
+// (stop sequences, seed, legacy function calling and fields of OpenAI-compatible APIs)
+// are rejected with a validation error rather than dropped.
+func newResponsesRequest(requestData OpenAIRequest, options types.GenerateOptions) (OpenAIResponsesRequest, error) {
+	switch {
+	case len(requestData.Stop) > 0:
+		return OpenAIResponsesRequest{}, types.NewValidationError("stop sequences are not supported by the Responses API")
+	case requestData.Seed != nil:
+		return OpenAIResponsesRequest{}, types.NewValidationError("seed is not supported by the Responses API")
+	case len(requestData.Functions) > 0:
+		return OpenAIResponsesRequest{}, types.NewValidationError("legacy function calling is not supported by the Responses API")
+	case len(requestData.Extra) > 0:
+		return OpenAIResponsesRequest{}, types.NewValidationError("OpenAI-compatible request fields are not supported by the Responses API")
+	}
+
+	request := OpenAIResponsesRequest{
+		Model:             requestData.Model,
+		Input:             responsesInput(options),
+		ToolChoice:        responsesToolChoice(options.ToolChoice),
+		ParallelToolCalls: requestData.ParallelToolCalls,
+		MaxOutputTokens:   requestData.MaxTokens,
+		Temperature:       requestData.Temperature,
+		TopP:              requestData.TopP,
+		Store:             requestData.Store,
+		Metadata:          requestData.Metadata,
+		Stream:            true,
+	}
+	if requestData.MaxCompletionTokens > 0 {
+		request.MaxOutputTokens = requestData.MaxCompletionTokens
+	}
+	if requestData.ReasoningEffort != "" {
+		request.Reasoning = &OpenAIResponsesReasoning{Effort: requestData.ReasoningEffort}
+	}
+	for _, tool := range requestData.Tools {
+		request.Tools = append(request.Tools, OpenAIResponsesTool{
+			Type:        "function",
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			Parameters:  tool.Function.Parameters,
+		})
+	}
+	if len(request.Tools) == 0 {
+		request.ToolChoice = nil
+	}
+	if len(requestData.ResponseFormat) > 0 {
+		format := map[string]interface{}{"type": requestData.ResponseFormat["type"]}
+		if schema, ok := requestData.ResponseFormat["json_schema"].(map[string]interface{}); ok {
+			for key, value := range schema {
+				format[key] = value
+			}
+		}
+		request.Text = &OpenAIResponsesText{Format: format}
+	}
+	return request, nil
+}
+
+// responsesInput converts the messages or prompt of options to Responses API input items
+func responsesInput(options types.GenerateOptions) []interface{} {
+	if len(options.Messages) == 0 {
+		return []interface{}{OpenAIResponsesMessage{Type: "message", Role: "user", Content: options.Prompt}}
+	}
+
+	var input []interface{}
+	for _, msg := range options.Messages {
+		switch {
+		case msg.Role == "tool":
+			input = append(input, OpenAIResponsesInput{Type: "function_call_output", CallID: msg.ToolCallID, Output: msg.GetTextContent()})
+		default:
+			if content := responsesContent(msg); content != "" || len(msg.ToolCalls) == 0 {
+				input = append(input, OpenAIResponsesMessage{Type: "message", Role: msg.Role, Content: content})
+			}
+			for _, call := range msg.ToolCalls {
+				input = append(input, OpenAIResponsesFunctionCall{
+					Type:      "function_call",
+					CallID:    call.ID,
+					Name:      call.Function.Name,
+					Arguments: call.Function.Arguments,
+				})
+			}
+		}
+	}
+	return input
+}
+
+// responsesContent returns the content of a message item: its text, or input parts
+// when it has images
+func responsesContent(msg types.ChatMessage) interface{} {
+	parts := msg.GetContentParts()
+	hasImage := false
+	for _, part := range parts {
+		hasImage = hasImage || part.Type == types.ContentTypeImage
+	}
+	if !hasImage {
+		return msg.GetTextContent()
+	}
+
+	var content []OpenAIResponsesContentPart
+	for _, part := range convertContentPartsToOpenAI(parts).([]OpenAIContentPart) {
+		if part.ImageURL != nil {
+			content = append(content, OpenAIResponsesContentPart{Type: "input_image", ImageURL: part.ImageURL.URL})
+		} else {
+			content = append(content, OpenAIResponsesContentPart{Type: "input_text", Text: part.Text})
+		}
+	}
+	return content
+}
+
+// responsesToolChoice converts a tool choice to the Responses API format, which names
+// a specific function at the top level
+func responsesToolChoice(toolChoice *types.ToolChoice) interface{} {
+	if toolChoice != nil && toolChoice.Mode == types.ToolChoiceSpecific {
+		return map[string]interface{}{"type": "function", "name": toolChoice.FunctionName}
+	}
+	return convertToOpenAIToolChoice(toolChoice)
+}
+
+// streamsResponses reports whether streamed chat completions go through the Responses
+// API, which requires both SupportsResponsesAPI and the stream_responses_api opt-in
+func (p *OpenAIProvider) streamsResponses() bool {
+	return p.useResponsesAPI && p.streamResponsesAPI
+}
+
+// IsStoredResponse implements types.StatefulToolProvider. Only the IDs of responses
+// streamed through the Responses API (see streamsResponses) can be continued; chat
+// completion IDs cannot.
+func (p *OpenAIProvider) IsStoredResponse(responseID string) bool {
+	return p.streamsResponses() && strings.HasPrefix(responseID, responseIDPrefix)
+}
+
+// SubmitToolOutputs implements types.StatefulToolProvider. It continues a stored
+// response through the Responses API with previous_response_id, so the conversation is
+// not resent, and streams the rest of it. options is the request that made the tool
+// calls; its model, tools and settings are sent again, as the API does not carry them
+// over. It requires SupportsResponsesAPI with the stream_responses_api provider config
+// set, and a response ID from a stream of the Responses API; use
+// types.SubmitToolOutputs to fall back to resending the conversation otherwise.
+func (p *OpenAIProvider) SubmitToolOutputs(ctx context.Context, responseID string, options types.GenerateOptions, outputs []types.ToolOutput) (types.ChatCompletionStream, error) {
+	if !p.streamsResponses() {
+		return nil, types.NewInvalidRequestError(p.Type(), "submitting tool outputs requires streaming through the Responses API; enable SupportsResponsesAPI and stream_responses_api").
+			WithOperation("SubmitToolOutputs")
+	}
+	if !p.IsStoredResponse(responseID) {
+		return nil, types.NewValidationError(fmt.Sprintf("%q is not the ID of a stored response; tool outputs can only be submitted to Responses API responses", responseID))
+	}
+
+	requestData := p.buildOpenAIRequest(options)
+	requestData.ReasoningEffort, _ = reasoningEffort(p.Type(), requestData.Model, options.ReasoningEffort)
+	responsesRequest, err := newResponsesRequest(requestData, options)
+	if err != nil {
+		return nil, err
+	}
+	responsesRequest.PreviousResponseID = responseID
+	responsesRequest.Input = make([]interface{}, len(outputs))
+	for i, output := range outputs {
+		responsesRequest.Input[i] = OpenAIResponsesInput{Type: "function_call_output", CallID: output.ToolCallID, Output: output.Output}
+	}
+
+	return p.executeResponsesStream(ctx, responsesRequest, "SubmitToolOutputs")
+}
+
+// executeResponsesStream sends a streaming Responses API request, failing over between
+// API keys. The last error is returned when every key fails.
+func (p *OpenAIProvider) executeResponsesStream(ctx context.Context, requestData OpenAIResponsesRequest, operation string) (types.ChatCompletionStream, error) {
+	ctx = middleware.WithModel(ctx, requestData.Model)
+	var lastErr error
+	if p.authHelper.KeyManager != nil {
+		for _, apiKey := range p.authHelper.KeyManager.GetKeys() {
+			// Failing over to another key is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
+			stream, err := p.makeResponsesStreamingCall(ctx, requestData, apiKey)
+			if err == nil {
+				return stream, nil
+			}
+			lastErr = err
+			// Another key won't help if the model itself is unavailable
+			if common.IsModelFallbackError(err) {
+				return nil, err
+			}
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, types.NewAuthError(p.Type(), "no valid API key available for streaming").
+		WithOperation(operation)
+}
+
+// makeResponsesStreamingCall sends a streaming Responses API request
+func (p *OpenAIProvider) makeResponsesStreamingCall(ctx context.Context, requestData OpenAIResponsesRequest, apiKey string) (types.ChatCompletionStream, error) {
+	jsonBody, err := json.Marshal(requestData)
+	if err != nil {
+		return nil, types.NewInvalidRequestError(p.Type(), "failed to marshal request").
+			WithOperation("makeResponsesStreamingCall").
+			WithOriginalErr(err)
+	}
+
+	url, err := p.endpointURL("responses", "makeResponsesStreamingCall")
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, types.NewNetworkError(p.Type(), "failed to create request").
+			WithOperation("makeResponsesStreamingCall").
+			WithOriginalErr(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	p.authHelper.SetAuthHeaders(req, apiKey, "api_key")
+	p.authHelper.SetProviderSpecificHeaders(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, types.NewRequestError(p.Type(), "request failed", err).
+			WithOperation("makeResponsesStreamingCall")
+	}
+
+	p.rateLimitHelper.ParseAndUpdateRateLimits(resp.Header, requestData.Model)
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		func() { _ = resp.Body.Close() }()
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, p.newRateLimitError(resp, "makeResponsesStreamingCall").
+				WithOriginalErr(fmt.Errorf("%s API error: %s", p.Name(), string(body)))
+		}
+		return nil, types.NewServerError(p.Type(), resp.StatusCode, fmt.Sprintf("%s API error: %s", p.Name(), string(body))).
+			WithOperation("makeResponsesStreamingCall")
+	}
+
+	stream := streaming.NewGenericSSEStream(resp, &responsesStreamParser{})
+	return types.StreamWithWarnings(streaming.StreamFromContext(ctx, stream), types.WarningsFromHeaders(resp.Header)), nil
+}
+
+// responsesStreamParser converts Responses API events to chunks. Text arrives as
+// deltas and each function call once complete; every chunk carries the response ID,
+// for submitting the outputs of its tool calls. Events with nothing for the caller
+// parse to empty chunks, which the stream skips.
+type responsesStreamParser struct {
+	responseID   string
+	model        string
+	hasToolCalls bool
+}
+
+// ParseLine implements streaming.SSELineParser
+func (p *responsesStreamParser) ParseLine(line string) (types.ChatCompletionChunk, error) {
+	var event OpenAIResponsesEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return types.ChatCompletionChunk{}, fmt.Errorf("failed to parse OpenAI responses event: %w", err)
+	}
+	if event.Response != nil {
+		if event.Response.ID != "" {
+			p.responseID = event.Response.ID
+		}
+		if event.Response.Model != "" {
+			p.model = event.Response.Model
+		}
+	}
+
+	chunk := types.ChatCompletionChunk{ID: p.responseID, Model: p.model}
+	switch event.Type {
+	case "response.output_text.delta":
+		chunk.Content = event.Delta
+		chunk.Choices = []types.ChatChoice{{Delta: types.ChatMessage{Role: "assistant", Content: event.Delta}}}
+
+	case "response.output_item.done":
+		if event.Item == nil || event.Item.Type != "function_call" {
+			break
+		}
+		p.hasToolCalls = true
+		chunk.Choices = []types.ChatChoice{{Delta: types.ChatMessage{Role: "assistant", ToolCalls: []types.ToolCall{{
+			ID:       event.Item.CallID,
+			Type:     "function",
+			Function: types.ToolCallFunction{Name: event.Item.Name, Arguments: event.Item.Arguments},
+		}}}}}
+
+	case "response.completed", "response.incomplete":
+		finishReason := "stop"
+		if event.Type == "response.incomplete" {
+			finishReason = "length"
+		} else if p.hasToolCalls {
+			finishReason = "tool_calls"
+		}
+		chunk.Done = true
+		chunk.Choices = []types.ChatChoice{{
+			FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenAI, finishReason),
+			RawFinishReason: finishReason,
+		}}
+		if event.Response != nil && event.Response.Usage != nil {
+			usage := event.Response.Usage
+			chunk.Usage = types.Usage{
+				PromptTokens:     usage.InputTokens,
+				CompletionTokens: usage.OutputTokens,
+				TotalTokens:      usage.TotalTokens,
+			}
+		}
+
+	case "response.failed", "error":
+		chunk.Done = true
+		chunk.Error = event.Message
+		if event.Error != nil {
+			chunk.Error = event.Error.Message
+		} else if event.Response != nil && event.Response.Error != nil {
+			chunk.Error = event.Response.Error.Message
+		}
+		if chunk.Error == "" {
+			chunk.Error = "response failed"
+		}
+	}
+	return chunk, nil
+}
+
+// IsDone implements streaming.SSELineParser. The Responses API ends streams with a
+// lifecycle event rather than a sentinel line.
+func (p *responsesStreamParser) IsDone(line string) bool {
+	return false
+}
@@ -0,0 +1,262 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const responsesContinuationEvents = `event: response.created
+data: {"type":"response.created","response":{"id":"resp_2","model":"gpt-4o","status":"in_progress"}}
+
+event: response.output_item.added
+data: {"type":"response.output_item.added","output_index":0,"item":{"type":"message","id":"msg_1"}}
+
+event: response.output_text.delta
+data: {"type":"response.output_text.delta","output_index":0,"delta":"It is "}
+
+event: response.output_text.delta
+data: {"type":"response.output_text.delta","output_index":0,"delta":"21°C."}
+
+event: response.output_item.done
+data: {"type":"response.output_item.done","output_index":1,"item":{"type":"function_call","id":"fc_1","call_id":"call_2","name":"get_forecast","arguments":"{\"city\":\"Paris\"}"}}
+
+event: response.completed
+data: {"type":"response.completed","response":{"id":"resp_2","model":"gpt-4o","status":"completed","usage":{"input_tokens":40,"output_tokens":12,"total_tokens":52}}}
+
+`
+
+func TestOpenAIProvider_SubmitToolOutputs(t *testing.T) {
+	var path string
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, responsesContinuationEvents)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:                 types.ProviderTypeOpenAI,
+		APIKey:               "sk-test-key",
+		BaseURL:              server.URL,
+		DefaultModel:         "gpt-4o",
+		SupportsResponsesAPI: true,
+		ProviderConfig:       map[string]interface{}{"stream_responses_api": true},
+	})
+
+	options := types.GenerateOptions{
+		Model:           "gpt-4o-mini",
+		Tools:           []types.Tool{{Name: "get_forecast", Description: "Get the forecast", InputSchema: map[string]interface{}{"type": "object"}}},
+		ReasoningEffort: "high",
+	}
+	stream, err := types.SubmitToolOutputs(context.Background(), provider, "resp_1", options,
+		[]types.ToolOutput{{ToolCallID: "call_1", Output: `{"temp":21}`}})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	assert.Equal(t, "/responses", path)
+	assert.Equal(t, "resp_1", sent["previous_response_id"])
+	assert.Equal(t, "gpt-4o-mini", sent["model"], "the request's model is carried forward")
+	assert.Equal(t, true, sent["stream"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"type": "function", "name": "get_forecast", "description": "Get the forecast", "parameters": map[string]interface{}{"type": "object"},
+	}}, sent["tools"], "the request's tools are carried forward")
+	assert.Equal(t, []interface{}{map[string]interface{}{"type": "function_call_output", "call_id": "call_1", "output": `{"temp":21}`}}, sent["input"])
+
+	var content string
+	var toolCalls []types.ToolCall
+	var last types.ChatCompletionChunk
+	for {
+		chunk, err := stream.Next()
+		if chunk.Done || err == io.EOF {
+			last = chunk
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "resp_2", chunk.ID, "chunks carry the ID to submit further outputs to")
+		content += chunk.Content
+		toolCalls = append(toolCalls, chunk.Choices[0].Delta.ToolCalls...)
+	}
+
+	assert.Equal(t, "It is 21°C.", content)
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, "call_2", toolCalls[0].ID)
+	assert.Equal(t, "get_forecast", toolCalls[0].Function.Name)
+	assert.Equal(t, "resp_2", last.ID)
+	assert.Equal(t, types.Usage{PromptTokens: 40, CompletionTokens: 12, TotalTokens: 52}, last.Usage)
+	require.Len(t, last.Choices, 1)
+	assert.Equal(t, "tool_calls", last.Choices[0].RawFinishReason)
+}
+
+func TestOpenAIProvider_SubmitToolOutputsRequiresResponsesAPI(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{Type: types.ProviderTypeOpenAI, APIKey: "sk-test-key"})
+
+	_, err := provider.SubmitToolOutputs(context.Background(), "resp_1", types.GenerateOptions{}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Responses API")
+}
+
+func TestOpenAIProvider_SubmitToolOutputsRequiresStoredResponse(t *testing.T) {
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:                 types.ProviderTypeOpenAI,
+		APIKey:               "sk-test-key",
+		SupportsResponsesAPI: true,
+		ProviderConfig:       map[string]interface{}{"stream_responses_api": true},
+	})
+
+	assert.True(t, provider.IsStoredResponse("resp_1"))
+	assert.False(t, provider.IsStoredResponse("chatcmpl-1"))
+
+	_, err := provider.SubmitToolOutputs(context.Background(), "chatcmpl-1", types.GenerateOptions{}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not the ID of a stored response")
+}
+
+func TestOpenAIProvider_SubmitToolOutputsReturnsLastError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"error":{"message":"Previous response not found","type":"invalid_request_error"}}`)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:                 types.ProviderTypeOpenAI,
+		APIKey:               "sk-test-key",
+		BaseURL:              server.URL,
+		SupportsResponsesAPI: true,
+		ProviderConfig:       map[string]interface{}{"stream_responses_api": true},
+	})
+
+	_, err := provider.SubmitToolOutputs(context.Background(), "resp_1", types.GenerateOptions{}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Previous response not found")
+}
+
+func TestOpenAIProvider_GenerateChatCompletionResponsesAPI(t *testing.T) {
+	var path string
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, responsesContinuationEvents)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:                 types.ProviderTypeOpenAI,
+		APIKey:               "sk-test-key",
+		BaseURL:              server.URL,
+		DefaultModel:         "o3-mini",
+		SupportsResponsesAPI: true,
+		ProviderConfig:       map[string]interface{}{"stream_responses_api": true},
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Stream:          true,
+		ReasoningEffort: "low",
+		Messages: []types.ChatMessage{
+			{Role: "user", Content: "Weather in Paris?"},
+			{Role: "assistant", ToolCalls: []types.ToolCall{{ID: "call_1", Type: "function", Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}}},
+			{Role: "tool", Content: `{"temp":21}`, ToolCallID: "call_1"},
+		},
+	})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	assert.Equal(t, "/responses", path)
+	assert.NotContains(t, sent, "previous_response_id")
+	assert.Equal(t, map[string]interface{}{"effort": "low"}, sent["reasoning"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "message", "role": "user", "content": "Weather in Paris?"},
+		map[string]interface{}{"type": "function_call", "call_id": "call_1", "name": "get_weather", "arguments": `{"city":"Paris"}`},
+		map[string]interface{}{"type": "function_call_output", "call_id": "call_1", "output": `{"temp":21}`},
+	}, sent["input"])
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	assert.True(t, provider.IsStoredResponse(chunk.ID), "the stream's ID can be passed to SubmitToolOutputs")
+}
+
+func TestOpenAIProvider_ResponsesAPIRequiresOptIn(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:                 types.ProviderTypeOpenAI,
+		APIKey:               "sk-test-key",
+		BaseURL:              server.URL,
+		DefaultModel:         "gpt-4o",
+		SupportsResponsesAPI: true,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "Hi", Stream: true})
+	require.NoError(t, err)
+	_ = stream.Close()
+
+	assert.Equal(t, "/chat/completions", path, "the capability flag alone keeps chat completions")
+	assert.False(t, provider.IsStoredResponse("resp_1"))
+}
+
+func TestNewResponsesRequest(t *testing.T) {
+	store := true
+	request, err := newResponsesRequest(OpenAIRequest{
+		Model:    "gpt-4o",
+		TopP:     0.9,
+		Store:    &store,
+		Metadata: map[string]string{"user": "u1"},
+		ResponseFormat: map[string]interface{}{
+			"type":        "json_schema",
+			"json_schema": map[string]interface{}{"name": "weather", "schema": map[string]interface{}{"type": "object"}},
+		},
+	}, types.GenerateOptions{Messages: []types.ChatMessage{{Role: "user", Parts: []types.ContentPart{
+		types.NewTextPart("What is this?"),
+		{Type: types.ContentTypeImage, Source: &types.MediaSource{Type: types.MediaSourceURL, URL: "https://example.com/cat.png"}},
+	}}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.9, request.TopP)
+	assert.Equal(t, &store, request.Store)
+	assert.Equal(t, map[string]string{"user": "u1"}, request.Metadata)
+	require.NotNil(t, request.Text)
+	assert.Equal(t, map[string]interface{}{"type": "json_schema", "name": "weather", "schema": map[string]interface{}{"type": "object"}}, request.Text.Format)
+	assert.Equal(t, []interface{}{OpenAIResponsesMessage{Type: "message", Role: "user", Content: []OpenAIResponsesContentPart{
+		{Type: "input_text", Text: "What is this?"},
+		{Type: "input_image", ImageURL: "https://example.com/cat.png"},
+	}}}, request.Input)
+
+	seed := 1
+	for name, unsupported := range map[string]OpenAIRequest{
+		"stop":      {Stop: []string{"END"}},
+		"seed":      {Seed: &seed},
+		"functions": {Functions: []common.LegacyFunction{{Name: "f"}}},
+	} {
+		_, err := newResponsesRequest(unsupported, types.GenerateOptions{Prompt: "Hi"})
+		assert.True(t, types.IsValidationError(err), "%s must be rejected, got %v", name, err)
+	}
+}
+
+func TestResponsesStreamParser_Failed(t *testing.T) {
+	parser := &responsesStreamParser{}
+	chunk, err := parser.ParseLine(`{"type":"response.failed","response":{"id":"resp_3","status":"failed","error":{"code":"server_error","message":"The model failed"}}}`)
+	require.NoError(t, err)
+	assert.True(t, chunk.Done)
+	assert.Equal(t, "resp_3", chunk.ID)
+	assert.Equal(t, "The model failed", chunk.Error)
+}
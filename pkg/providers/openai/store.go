@@ -0,0 +1,86 @@
+package openai
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Limits OpenAI places on request metadata
+const (
+	openAIMaxMetadataPairs       = 16
+	openAIMaxMetadataKeyLength   = 64
+	openAIMaxMetadataValueLength = 512
+)
+
+// applyStore sets store on request and, when it is true, sends metadata as the stored
+// completion's metadata. OpenAI only accepts metadata on stored completions, so the
+// kit's metadata, which also carries options for the kit itself, is otherwise not sent.
+func applyStore(request *OpenAIRequest, store *bool, metadata map[string]interface{}) error {
+	request.Store = store
+	if store == nil || !*store || len(metadata) == 0 {
+		return nil
+	}
+	converted, err := openAIMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	request.Metadata = converted
+	return nil
+}
+
+// openAIMetadata converts the kit's metadata to OpenAI's string to string metadata.
+// Numbers and booleans are formatted as strings; any other non-string value, or
+// metadata over OpenAI's limits, is a *types.ValidationError.
+func openAIMetadata(metadata map[string]interface{}) (map[string]string, error) {
+	if len(metadata) > openAIMaxMetadataPairs {
+		return nil, types.NewValidationError(fmt.Sprintf(
+			"openai metadata allows at most %d pairs, got %d", openAIMaxMetadataPairs, len(metadata)))
+	}
+
+	// Sorted so the reported error does not depend on map order
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	converted := make(map[string]string, len(metadata))
+	for _, key := range keys {
+		if len(key) > openAIMaxMetadataKeyLength {
+			return nil, types.NewValidationError(fmt.Sprintf(
+				"openai metadata key %q is longer than %d characters", key, openAIMaxMetadataKeyLength))
+		}
+		value, ok := metadataString(metadata[key])
+		if !ok {
+			return nil, types.NewValidationError(fmt.Sprintf(
+				"openai metadata value for key %q must be a string, number or boolean, got %T", key, metadata[key]))
+		}
+		if len(value) > openAIMaxMetadataValueLength {
+			return nil, types.NewValidationError(fmt.Sprintf(
+				"openai metadata value for key %q is longer than %d characters", key, openAIMaxMetadataValueLength))
+		}
+		converted[key] = value
+	}
+	return converted, nil
+}
+
+// metadataString formats a scalar metadata value as a string
+func metadataString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
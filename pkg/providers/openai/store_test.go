@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_StoreAndMetadata(t *testing.T) {
+	var sent map[string]interface{}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		sent = nil
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"gpt-4o","choices":[
+			{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	generate := func(builder *types.OptionsBuilder) error {
+		t.Helper()
+		options, err := builder.WithModel("gpt-4o").WithPrompt("Hello").Build()
+		require.NoError(t, err)
+		stream, err := provider.GenerateChatCompletion(context.Background(), options)
+		if err != nil {
+			return err
+		}
+		return stream.Close()
+	}
+
+	require.NoError(t, generate(types.NewOptionsBuilder().
+		WithStore(true).
+		WithMetadata("user_tier", "pro").
+		WithMetadata("attempt", 2).
+		WithMetadata("beta", true)))
+	assert.Equal(t, true, sent["store"])
+	assert.Equal(t, map[string]interface{}{"user_tier": "pro", "attempt": "2", "beta": "true"}, sent["metadata"])
+
+	require.NoError(t, generate(types.NewOptionsBuilder().WithMetadata("user_tier", "pro")))
+	assert.NotContains(t, sent, "store", "unset leaves the provider default")
+	assert.NotContains(t, sent, "metadata", "metadata is only sent with stored completions")
+
+	require.NoError(t, generate(types.NewOptionsBuilder().WithStore(false).WithMetadata("user_tier", "pro")))
+	assert.Equal(t, false, sent["store"])
+	assert.NotContains(t, sent, "metadata")
+
+	sentRequests := requests
+	err := generate(types.NewOptionsBuilder().WithStore(true).WithMetadata("tags", []string{"a", "b"}))
+	var validationErr *types.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, err.Error(), `key "tags" must be a string`)
+	assert.Equal(t, sentRequests, requests, "invalid metadata must not be sent")
+}
+
+func TestOpenAIMetadata_Limits(t *testing.T) {
+	tooMany := make(map[string]interface{})
+	for i := 0; i <= openAIMaxMetadataPairs; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+	_, err := openAIMetadata(tooMany)
+	assert.ErrorContains(t, err, "at most 16 pairs")
+
+	_, err = openAIMetadata(map[string]interface{}{strings.Repeat("k", 65): "v"})
+	assert.ErrorContains(t, err, "longer than 64 characters")
+
+	_, err = openAIMetadata(map[string]interface{}{"k": strings.Repeat("v", 513)})
+	assert.ErrorContains(t, err, "longer than 512 characters")
+
+	converted, err := openAIMetadata(map[string]interface{}{"ratio": 0.5, "count": int64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ratio": "0.5", "count": "3"}, converted)
+}
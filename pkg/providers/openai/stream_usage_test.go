@@ -0,0 +1,85 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider_StreamIncludeUsage(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = nil
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":4,\"completion_tokens\":1,\"total_tokens\":5,\"prompt_tokens_details\":{\"cached_tokens\":2}}}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	options := types.GenerateOptions{Model: "gpt-4o", Prompt: "Hello", Stream: true}
+
+	t.Run("requested by default", func(t *testing.T) {
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:    types.ProviderTypeOpenAI,
+			APIKey:  "sk-test-key",
+			BaseURL: server.URL,
+		})
+
+		stream, err := provider.GenerateChatCompletion(context.Background(), options)
+		require.NoError(t, err)
+		defer func() { _ = stream.Close() }()
+
+		var final types.ChatCompletionChunk
+		for {
+			chunk, err := stream.Next()
+			if chunk.Done {
+				final = chunk
+			}
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, map[string]interface{}{"include_usage": true}, sent["stream_options"])
+		assert.Equal(t, 5, final.Usage.TotalTokens)
+		assert.Equal(t, 2, final.Usage.CacheReadTokens)
+	})
+
+	t.Run("disabled in config", func(t *testing.T) {
+		provider := NewOpenAIProvider(types.ProviderConfig{
+			Type:           types.ProviderTypeOpenAI,
+			APIKey:         "sk-test-key",
+			BaseURL:        server.URL,
+			ProviderConfig: map[string]interface{}{"stream_include_usage": false},
+		})
+
+		stream, err := provider.GenerateChatCompletion(context.Background(), options)
+		require.NoError(t, err)
+		_ = stream.Close()
+
+		assert.NotContains(t, sent, "stream_options")
+	})
+}
+
+func TestOpenAIUsage_CachedTokens(t *testing.T) {
+	var usage OpenAIUsage
+	require.NoError(t, json.Unmarshal([]byte(`{"prompt_tokens":2000,"completion_tokens":10,"total_tokens":2010,"prompt_tokens_details":{"cached_tokens":1920}}`), &usage))
+
+	converted := usage.toUsage()
+	assert.Equal(t, 2000, converted.PromptTokens)
+	assert.Equal(t, 1920, converted.CacheReadTokens)
+	assert.Equal(t, 0.96, converted.CacheHitRate())
+
+	assert.Zero(t, OpenAIUsage{PromptTokens: 10}.toUsage().CacheReadTokens)
+}
@@ -0,0 +1,49 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAIProvider_InvalidRequestFailsBeforeNetwork(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenAI,
+		APIKey:  "sk-test-key",
+		BaseURL: server.URL,
+	})
+
+	tests := []struct {
+		name    string
+		options types.GenerateOptions
+	}{
+		{name: "no messages", options: types.GenerateOptions{}},
+		{name: "temperature out of range", options: types.GenerateOptions{Prompt: "hi", Temperature: 3}},
+		{name: "tool choice without tools", options: types.GenerateOptions{Prompt: "hi", ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceRequired}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, stream := range []bool{false, true} {
+				tt.options.Stream = stream
+				_, err := provider.GenerateChatCompletion(context.Background(), tt.options)
+				assert.Error(t, err)
+				assert.True(t, types.IsValidationError(err), "expected validation error, got %v", err)
+			}
+		})
+	}
+
+	assert.Zero(t, atomic.LoadInt32(&requests), "invalid requests must not reach the network")
+}
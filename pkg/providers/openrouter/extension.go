@@ -258,9 +258,10 @@ func (e *OpenRouterExtension) ProviderToStandard(response interface{}) (*types.S
 	}
 
 	standardChoice := types.StandardChoice{
-		Index:        choice.Index,
-		Message:      message,
-		FinishReason: choice.FinishReason,
+		Index:           choice.Index,
+		Message:         message,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenRouter, choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
 
 	// Convert usage
@@ -313,9 +314,10 @@ func (e *OpenRouterExtension) ProviderToStandardChunk(chunk interface{}) (*types
 	}
 
 	standardChoice := types.StandardStreamChoice{
-		Index:        choice.Index,
-		Delta:        delta,
-		FinishReason: choice.FinishReason,
+		Index:           choice.Index,
+		Delta:           delta,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenRouter, choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
 
 	// Convert usage if present
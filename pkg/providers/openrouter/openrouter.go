@@ -17,11 +17,15 @@ import (
 	"sync"
 	"time"
 
+	pkghttp "github.com/cecil-the-coder/ai-provider-kit/internal/http"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/base"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -121,9 +125,11 @@ func NewOpenRouterProvider(config types.ProviderConfig) *OpenRouterProvider {
 		modelStrategy = "failover"
 	}
 
-	client := &http.Client{
+	client := pkghttp.WithTimeouts(&http.Client{
 		Timeout: 60 * time.Second,
-	}
+	}, config.ConnectTimeout, config.RequestTimeout)
+	client = pkghttp.WithHeaders(pkghttp.WithCompression(client, config.CompressRequests), config.Headers, config.UserAgent)
+	client = pkghttp.WithTransportWrapper(client, config.WrapTransport)
 
 	// Create auth helper
 	authHelper := auth.NewAuthHelper("openrouter", config, client)
@@ -284,6 +290,10 @@ func (p *OpenRouterProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+
 	if !p.authHelper.IsAuthenticated() {
 		return nil, fmt.Errorf("no OpenRouter API key configured")
 	}
@@ -294,6 +304,15 @@ func (p *OpenRouterProvider) GenerateChatCompletion(
 		return nil, err
 	}
 
+	// Validate before any network I/O
+	if err := common.ValidateGenerateOptions(requestData.Model, options); err != nil {
+		return nil, err
+	}
+	ctx = middleware.WithModel(ctx, requestData.Model)
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return nil, err
+	}
+
 	// Option 1: Check via /api/v1/key endpoint (existing approach)
 	rateLimits, err := p.GetRateLimits(ctx)
 	if err != nil {
@@ -494,6 +513,12 @@ func (p *OpenRouterProvider) SupportsToolCalling() bool {
 	return true
 }
 
+// SupportedParameters implements types.ParameterSupportReporter. OpenRouter requests are built
+// without n, parallel_tool_calls, store and reasoning_effort.
+func (p *OpenRouterProvider) SupportedParameters(model string) types.ParameterSupport {
+	return common.SingleChoiceParameters(p, model)
+}
+
 func (p *OpenRouterProvider) SupportsStreaming() bool {
 	return true
 }
@@ -506,6 +531,19 @@ func (p *OpenRouterProvider) GetToolFormat() types.ToolFormat {
 	return types.ToolFormatOpenAI
 }
 
+// Warmup fetches the OpenRouter model catalog into the model cache, so the first
+// request does not wait for it. OpenRouter's OAuth provisions API keys, which have no
+// tokens to refresh.
+func (p *OpenRouterProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
+// RateLimitStatus returns the rate limits reported by the most recent response, see
+// types.RateLimitStatusProvider
+func (p *OpenRouterProvider) RateLimitStatus() types.RateLimitStatus {
+	return p.rateLimitTracker.GetHelper().Status()
+}
+
 // TestConnectivity performs a lightweight connectivity test using the /v1/models endpoint
 // Results are cached for 30 seconds by default to prevent hammering the API during rapid health checks
 // To bypass the cache and force a fresh check, use TestConnectivityWithOptions with bypassCache=true
@@ -619,6 +657,29 @@ func (p *OpenRouterProvider) GetMetrics() types.ProviderMetrics {
 	return metrics
 }
 
+// ResetMetrics zeroes the metric counters, including the last call's usage
+func (p *OpenRouterProvider) ResetMetrics() {
+	p.mutex.Lock()
+	p.lastUsage = nil
+	p.mutex.Unlock()
+
+	p.BaseProvider.ResetMetrics()
+}
+
+// SnapshotMetrics returns the metrics as GetMetrics would and resets the counters
+func (p *OpenRouterProvider) SnapshotMetrics() types.ProviderMetrics {
+	p.mutex.Lock()
+	lastUsage := p.lastUsage
+	p.lastUsage = nil
+	p.mutex.Unlock()
+
+	metrics := p.BaseProvider.SnapshotMetrics()
+	if lastUsage != nil {
+		metrics.TokensUsed += int64(lastUsage.TotalTokens)
+	}
+	return metrics
+}
+
 // GetLastUsedModel returns the model name that was used in the last API call
 func (p *OpenRouterProvider) GetLastUsedModel() string {
 	p.mutex.RLock()
@@ -702,6 +763,17 @@ func (p *OpenRouterProvider) prepareRequest(options types.GenerateOptions) (Open
 		}
 	}
 
+	// Apply routing preferences from provider options
+	routing, err := openRouterOptionsFrom(options)
+	if err != nil {
+		return OpenRouterRequest{}, err
+	}
+	if routing != nil {
+		requestData.Models = routing.Models
+		requestData.Route = routing.Route
+		requestData.Provider = routing.Provider
+	}
+
 	return requestData, nil
 }
 
@@ -739,11 +811,7 @@ func (p *OpenRouterProvider) makeAPICallWithKey(ctx context.Context, requestData
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		var errorResponse OpenRouterErrorResponse
-		if parseErr := json.Unmarshal(body, &errorResponse); parseErr == nil {
-			return nil, fmt.Errorf("OpenRouter API error: %d - %s", resp.StatusCode, errorResponse.Error.Message)
-		}
-		return nil, fmt.Errorf("OpenRouter API error: %d - %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp, body, "makeAPICallWithKey")
 	}
 
 	var response OpenRouterResponse
@@ -876,25 +944,28 @@ func (p *OpenRouterProvider) makeStreamingAPICallWithKey(ctx context.Context, re
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		func() { _ = resp.Body.Close() }() //nolint:staticcheck // Empty branch is intentional - we ignore close errors
-		return nil, fmt.Errorf("OpenRouter API error: %d - %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp, body, "makeStreamingAPICallWithKey")
 	}
 
-	return &OpenRouterStream{
+	return streaming.StreamFromContext(ctx, &OpenRouterStream{
 		response: resp,
 		reader:   bufio.NewReader(resp.Body),
 		done:     false,
-	}, nil
+	}), nil
 }
 
 // OpenRouterStream implements ChatCompletionStream for real streaming responses
 type OpenRouterStream struct {
 	response *http.Response
 	reader   *bufio.Reader
+	events   *streaming.SSEScanner
 	done     bool
 	mutex    sync.Mutex
 }
 
-func (s *OpenRouterStream) Next() (types.ChatCompletionChunk, error) {
+func (s *OpenRouterStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer streaming.RecoverStreamPanic(types.ProviderTypeOpenRouter, &chunk, &err)
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -902,8 +973,12 @@ func (s *OpenRouterStream) Next() (types.ChatCompletionChunk, error) {
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
 
+	if s.events == nil {
+		s.events = streaming.NewSSEScanner(s.reader, streaming.SSEScannerOptions{DoneSentinel: streaming.SSEDone})
+	}
+
 	for {
-		line, err := s.reader.ReadString('\n')
+		event, err := s.events.Next()
 		if err != nil {
 			if err == io.EOF {
 				s.done = true
@@ -911,23 +986,7 @@ func (s *OpenRouterStream) Next() (types.ChatCompletionChunk, error) {
 			}
 			return types.ChatCompletionChunk{}, err
 		}
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		if !strings.HasPrefix(line, "data: ") {
-			continue // Skip non-data lines
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
-
-		// Check for stream end
-		if data == "[DONE]" {
-			s.done = true
-			return types.ChatCompletionChunk{Done: true}, io.EOF
-		}
+		data := event.Data
 
 		var streamResp OpenRouterResponse
 		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
@@ -936,8 +995,12 @@ func (s *OpenRouterStream) Next() (types.ChatCompletionChunk, error) {
 
 		if len(streamResp.Choices) > 0 {
 			choice := streamResp.Choices[0]
+			message := choice.Delta
+			if message.Content == "" && len(message.ToolCalls) == 0 {
+				message = choice.Message // Some upstreams stream whole messages
+			}
 			chunk := types.ChatCompletionChunk{
-				Content: choice.Message.Content,
+				Content: message.Content,
 				Done:    choice.FinishReason != "",
 			}
 
@@ -950,16 +1013,17 @@ func (s *OpenRouterStream) Next() (types.ChatCompletionChunk, error) {
 				}
 			}
 
-			// Add tool calls if present in the message
-			if len(choice.Message.ToolCalls) > 0 {
+			// Add tool calls if present
+			if len(message.ToolCalls) > 0 {
 				chunk.Choices = []types.ChatChoice{
 					{
 						Delta: types.ChatMessage{
-							Role:      choice.Message.Role,
-							Content:   choice.Message.Content,
-							ToolCalls: convertOpenRouterToolCallsToUniversal(choice.Message.ToolCalls),
+							Role:      message.Role,
+							Content:   message.Content,
+							ToolCalls: convertOpenRouterToolCallsToUniversal(message.ToolCalls),
 						},
-						FinishReason: choice.FinishReason,
+						FinishReason:    types.NormalizeFinishReason(types.ProviderTypeOpenRouter, choice.FinishReason),
+						RawFinishReason: choice.FinishReason,
 					},
 				}
 			}
@@ -968,6 +1032,9 @@ func (s *OpenRouterStream) Next() (types.ChatCompletionChunk, error) {
 				s.done = true
 				return chunk, io.EOF
 			}
+			if streaming.IsHeartbeat(chunk) {
+				continue
+			}
 
 			return chunk, nil
 		}
@@ -998,6 +1065,11 @@ type OpenRouterRequest struct {
 	Tools          []OpenRouterTool       `json:"tools,omitempty"`
 	ToolChoice     interface{}            `json:"tool_choice,omitempty"`
 	ResponseFormat map[string]interface{} `json:"response_format,omitempty"` // For structured outputs
+
+	// Routing preferences (see OpenRouterOptions)
+	Models   []string                       `json:"models,omitempty"`
+	Route    string                         `json:"route,omitempty"`
+	Provider *OpenRouterProviderPreferences `json:"provider,omitempty"`
 }
 
 // OpenRouterTool represents a tool in the OpenRouter API (OpenAI-compatible format)
@@ -1048,6 +1120,7 @@ type OpenRouterResponse struct {
 type OpenRouterChoice struct {
 	Index        int               `json:"index"`
 	Message      OpenRouterMessage `json:"message"`
+	Delta        OpenRouterMessage `json:"delta"` // Set instead of Message in stream chunks
 	FinishReason string            `json:"finish_reason"`
 }
 
@@ -1430,3 +1503,22 @@ func convertOpenRouterToolCallsToUniversal(toolCalls []OpenRouterToolCall) []typ
 	}
 	return universal
 }
+
+// newAPIError converts an error response to a ProviderError classified by its status.
+// Rate limit errors carry the response's retry hint.
+func newAPIError(resp *http.Response, body []byte, operation string) *types.ProviderError {
+	message := string(body)
+	var errorResponse OpenRouterErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil && errorResponse.Error.Message != "" {
+		message = errorResponse.Error.Message
+	}
+
+	providerErr := types.NewProviderError(types.ProviderTypeOpenRouter, types.ClassifyHTTPError(resp.StatusCode),
+		fmt.Sprintf("OpenRouter API error: %s", message)).
+		WithOperation(operation).
+		WithStatusCode(resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		providerErr.WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
+	}
+	return providerErr
+}
@@ -3,6 +3,7 @@ package openrouter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -744,3 +745,56 @@ func TestGetStaticFallback(t *testing.T) {
 		t.Error("Expected qwen/qwen3-coder in static fallback")
 	}
 }
+
+func TestOpenRouterProvider_ConfiguredHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"is_free_tier": false,
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenRouterProvider(types.ProviderConfig{
+		Type:      types.ProviderTypeOpenRouter,
+		APIKey:    "test-key",
+		BaseURL:   server.URL,
+		UserAgent: "my-app/2.1",
+		Headers: map[string]string{
+			"HTTP-Referer": "https://myapp.example.com",
+			"X-Title":      "My App",
+		},
+	})
+
+	if err := provider.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("Expected no error from health check, got %v", err)
+	}
+
+	if got := received.Get("HTTP-Referer"); got != "https://myapp.example.com" {
+		t.Errorf("Expected configured HTTP-Referer, got %q", got)
+	}
+	if got := received.Get("X-Title"); got != "My App" {
+		t.Errorf("Expected configured X-Title, got %q", got)
+	}
+	if got := received.Get("User-Agent"); got != "my-app/2.1" {
+		t.Errorf("Expected configured User-Agent, got %q", got)
+	}
+}
+
+func TestNewAPIError_RateLimit(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "12")
+
+	var rateLimitErr *types.RateLimitError
+	if !errors.As(newAPIError(resp, []byte(`{"error":{"message":"Rate limit exceeded","code":429}}`), "makeAPICallWithKey"), &rateLimitErr) {
+		t.Fatal("expected a 429 to match RateLimitError")
+	}
+	if rateLimitErr.RetryAfter != 12*time.Second {
+		t.Errorf("expected RetryAfter 12s, got %v", rateLimitErr.RetryAfter)
+	}
+	if rateLimitErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected status 429, got %d", rateLimitErr.StatusCode)
+	}
+}
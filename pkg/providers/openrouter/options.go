@@ -0,0 +1,110 @@
+package openrouter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// OpenRouterRouteFallback is the route value that enables model fallbacks
+const OpenRouterRouteFallback = "fallback"
+
+// OpenRouterOptions configures OpenRouter's request routing.
+// Pass it through GenerateOptions.ProviderOptions under the "openrouter" key:
+//
+//	options := types.GenerateOptions{
+//	    Model: "anthropic/claude-3.5-sonnet",
+//	    ProviderOptions: map[string]interface{}{
+//	        "openrouter": openrouter.OpenRouterOptions{
+//	            Models: []string{"openai/gpt-4o", "google/gemini-pro-1.5"},
+//	            Route:  openrouter.OpenRouterRouteFallback,
+//	            Provider: &openrouter.OpenRouterProviderPreferences{
+//	                Order:          []string{"Anthropic", "Together"},
+//	                AllowFallbacks: openrouter.Bool(false),
+//	            },
+//	        },
+//	    },
+//	}
+//
+// See https://openrouter.ai/docs/features/provider-routing
+type OpenRouterOptions struct {
+	// Models is the fallback model list tried in order if the primary model fails
+	Models []string `json:"models,omitempty"`
+
+	// Route selects the routing strategy ("fallback")
+	Route string `json:"route,omitempty"`
+
+	// Provider sets preferences for which upstream providers serve the request
+	Provider *OpenRouterProviderPreferences `json:"provider,omitempty"`
+}
+
+// OpenRouterProviderPreferences controls upstream provider selection
+type OpenRouterProviderPreferences struct {
+	// Order lists provider names to try in order (e.g. "Anthropic", "OpenAI")
+	Order []string `json:"order,omitempty"`
+
+	// AllowFallbacks permits providers outside Order when those are unavailable (default true)
+	AllowFallbacks *bool `json:"allow_fallbacks,omitempty"`
+
+	// RequireParameters only routes to providers that support every request parameter
+	RequireParameters *bool `json:"require_parameters,omitempty"`
+
+	// DataCollection is "allow" or "deny" for providers that may store data
+	DataCollection string `json:"data_collection,omitempty"`
+
+	// Only restricts routing to these providers
+	Only []string `json:"only,omitempty"`
+
+	// Ignore excludes these providers
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Quantizations restricts routing to these quantization levels (e.g. "fp8")
+	Quantizations []string `json:"quantizations,omitempty"`
+
+	// Sort orders providers by "price", "throughput" or "latency"
+	Sort string `json:"sort,omitempty"`
+
+	// MaxPrice caps the price (USD per million tokens) a provider may charge
+	MaxPrice *OpenRouterMaxPrice `json:"max_price,omitempty"`
+}
+
+// OpenRouterMaxPrice caps provider pricing
+type OpenRouterMaxPrice struct {
+	Prompt     float64 `json:"prompt,omitempty"`
+	Completion float64 `json:"completion,omitempty"`
+	Request    float64 `json:"request,omitempty"`
+	Image      float64 `json:"image,omitempty"`
+}
+
+// Bool returns a pointer to b, for optional preference fields
+func Bool(b bool) *bool {
+	return &b
+}
+
+// openRouterOptionsFrom extracts OpenRouterOptions from GenerateOptions.ProviderOptions.
+// Accepts the struct, a pointer to it, or a generic map (e.g. decoded from JSON config).
+func openRouterOptionsFrom(options types.GenerateOptions) (*OpenRouterOptions, error) {
+	raw, ok := options.ProviderOptions[string(types.ProviderTypeOpenRouter)]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case OpenRouterOptions:
+		return &v, nil
+	case *OpenRouterOptions:
+		return v, nil
+	default:
+		// Round-trip through JSON to support map[string]interface{} and similar
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid openrouter provider options: %w", err)
+		}
+		var opts OpenRouterOptions
+		if err := json.Unmarshal(data, &opts); err != nil {
+			return nil, fmt.Errorf("invalid openrouter provider options: %w", err)
+		}
+		return &opts, nil
+	}
+}
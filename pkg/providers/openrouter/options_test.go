@@ -0,0 +1,186 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestPrepareRequest_RoutingOptions(t *testing.T) {
+	provider := NewOpenRouterProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeOpenRouter,
+		APIKey: "test-key",
+	})
+
+	tests := []struct {
+		name    string
+		options interface{}
+	}{
+		{
+			name: "struct",
+			options: OpenRouterOptions{
+				Models: []string{"openai/gpt-4o", "google/gemini-pro-1.5"},
+				Route:  OpenRouterRouteFallback,
+				Provider: &OpenRouterProviderPreferences{
+					Order:          []string{"Anthropic", "Together"},
+					AllowFallbacks: Bool(false),
+					MaxPrice:       &OpenRouterMaxPrice{Prompt: 1, Completion: 2},
+				},
+			},
+		},
+		{
+			name: "pointer",
+			options: &OpenRouterOptions{
+				Models: []string{"openai/gpt-4o", "google/gemini-pro-1.5"},
+				Route:  OpenRouterRouteFallback,
+				Provider: &OpenRouterProviderPreferences{
+					Order:          []string{"Anthropic", "Together"},
+					AllowFallbacks: Bool(false),
+					MaxPrice:       &OpenRouterMaxPrice{Prompt: 1, Completion: 2},
+				},
+			},
+		},
+		{
+			name: "map",
+			options: map[string]interface{}{
+				"models": []string{"openai/gpt-4o", "google/gemini-pro-1.5"},
+				"route":  "fallback",
+				"provider": map[string]interface{}{
+					"order":           []string{"Anthropic", "Together"},
+					"allow_fallbacks": false,
+					"max_price":       map[string]interface{}{"prompt": 1, "completion": 2},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := provider.prepareRequest(types.GenerateOptions{
+				Model:           "anthropic/claude-3.5-sonnet",
+				Prompt:          "Hello",
+				ProviderOptions: map[string]interface{}{"openrouter": tt.options},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !reflect.DeepEqual(req.Models, []string{"openai/gpt-4o", "google/gemini-pro-1.5"}) {
+				t.Errorf("Unexpected models: %v", req.Models)
+			}
+			if req.Route != "fallback" {
+				t.Errorf("Expected route 'fallback', got %q", req.Route)
+			}
+			if req.Provider == nil {
+				t.Fatal("Expected provider preferences")
+			}
+			if !reflect.DeepEqual(req.Provider.Order, []string{"Anthropic", "Together"}) {
+				t.Errorf("Unexpected provider order: %v", req.Provider.Order)
+			}
+			if req.Provider.AllowFallbacks == nil || *req.Provider.AllowFallbacks {
+				t.Errorf("Expected allow_fallbacks=false, got %v", req.Provider.AllowFallbacks)
+			}
+			if req.Provider.MaxPrice == nil || req.Provider.MaxPrice.Prompt != 1 || req.Provider.MaxPrice.Completion != 2 {
+				t.Errorf("Unexpected max price: %+v", req.Provider.MaxPrice)
+			}
+		})
+	}
+}
+
+func TestPrepareRequest_NoRoutingOptions(t *testing.T) {
+	provider := NewOpenRouterProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeOpenRouter,
+		APIKey: "test-key",
+	})
+
+	req, err := provider.prepareRequest(types.GenerateOptions{
+		Model:  "openai/gpt-4o",
+		Prompt: "Hello",
+		// Options for other providers are ignored
+		ProviderOptions: map[string]interface{}{"anthropic": map[string]interface{}{"x": 1}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	var fields map[string]interface{}
+	_ = json.Unmarshal(body, &fields)
+	for _, key := range []string{"models", "route", "provider"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("Expected %q to be omitted when no routing options are set", key)
+		}
+	}
+}
+
+func TestPrepareRequest_InvalidRoutingOptions(t *testing.T) {
+	provider := NewOpenRouterProvider(types.ProviderConfig{
+		Type:   types.ProviderTypeOpenRouter,
+		APIKey: "test-key",
+	})
+
+	_, err := provider.prepareRequest(types.GenerateOptions{
+		Model:           "openai/gpt-4o",
+		Prompt:          "Hello",
+		ProviderOptions: map[string]interface{}{"openrouter": map[string]interface{}{"models": "not-a-list"}},
+	})
+	if err == nil {
+		t.Error("Expected error for invalid routing options")
+	}
+}
+
+func TestGenerateChatCompletion_RoutingOptionsInBody(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "gen-1",
+			"model": "openai/gpt-4o",
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"role": "assistant", "content": "Hi"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOpenRouterProvider(types.ProviderConfig{
+		Type:    types.ProviderTypeOpenRouter,
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Model:  "anthropic/claude-3.5-sonnet",
+		Prompt: "Hello",
+		ProviderOptions: map[string]interface{}{
+			"openrouter": OpenRouterOptions{
+				Models:   []string{"openai/gpt-4o"},
+				Route:    OpenRouterRouteFallback,
+				Provider: &OpenRouterProviderPreferences{Sort: "throughput"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_ = stream.Close()
+
+	if body["route"] != "fallback" {
+		t.Errorf("Expected route in body, got %v", body["route"])
+	}
+	if models, ok := body["models"].([]interface{}); !ok || len(models) != 1 || models[0] != "openai/gpt-4o" {
+		t.Errorf("Expected models in body, got %v", body["models"])
+	}
+	if prefs, ok := body["provider"].(map[string]interface{}); !ok || prefs["sort"] != "throughput" {
+		t.Errorf("Expected provider preferences in body, got %v", body["provider"])
+	}
+}
@@ -251,9 +251,10 @@ func (e *QwenExtension) ProviderToStandard(response interface{}) (*types.Standar
 	}
 
 	standardChoice := types.StandardChoice{
-		Index:        choice.Index,
-		Message:      message,
-		FinishReason: choice.FinishReason,
+		Index:           choice.Index,
+		Message:         message,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeQwen, choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
 
 	// Convert usage
@@ -312,9 +313,10 @@ func (e *QwenExtension) ProviderToStandardChunk(chunk interface{}) (*types.Stand
 	}
 
 	standardChoice := types.StandardStreamChoice{
-		Index:        choice.Index,
-		Delta:        delta,
-		FinishReason: choice.FinishReason,
+		Index:           choice.Index,
+		Delta:           delta,
+		FinishReason:    types.NormalizeFinishReason(types.ProviderTypeQwen, choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
 
 	// Convert usage if present
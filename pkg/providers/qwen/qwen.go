@@ -20,6 +20,8 @@ import (
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/auth"
 	commonconfig "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/config"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/middleware"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/ratelimit"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
@@ -62,7 +64,13 @@ func NewQwenProvider(config types.ProviderConfig) *QwenProvider {
 
 	// Create HTTP client using internal/http package
 	httpClient := pkghttp.NewHTTPClient(pkghttp.HTTPClientConfig{
-		Timeout: configHelper.ExtractTimeout(mergedConfig),
+		Timeout:          configHelper.ExtractTimeout(mergedConfig),
+		ConnectTimeout:   mergedConfig.ConnectTimeout,
+		RequestTimeout:   mergedConfig.RequestTimeout,
+		Headers:          mergedConfig.Headers,
+		UserAgent:        mergedConfig.UserAgent,
+		CompressRequests: mergedConfig.CompressRequests,
+		WrapTransport:    mergedConfig.WrapTransport,
 	})
 
 	// Create auth helper with the underlying http.Client
@@ -154,12 +162,26 @@ func (p *QwenProvider) GenerateChatCompletion(
 	ctx context.Context,
 	options types.GenerateOptions,
 ) (types.ChatCompletionStream, error) {
+	if stream, handled, err := common.GenerateWithStrategies(ctx, options, p.GetConfig(), p.GenerateChatCompletion); handled {
+		return stream, err
+	}
+
 	// Increment request count at the start
 	p.IncrementRequestCount()
 
 	// Track start time for latency measurement
 	startTime := time.Now()
 
+	// Validate before any network I/O or rate limit waits
+	model := common.ResolveModel(options.Model, p.GetConfig().DefaultModel, qwenDefaultModel)
+	if err := common.ValidateGenerateOptions(model, options); err != nil {
+		return nil, err
+	}
+	ctx = middleware.WithModel(ctx, model)
+	if err := common.ValidateSingleChoice(p.Type(), options); err != nil {
+		return nil, err
+	}
+
 	// Client-side rate limiting (Qwen doesn't provide rate limit headers)
 	// Use token bucket algorithm to enforce free tier limits: 60 RPM, 2000/day
 	waitCtx, cancel := context.WithTimeout(ctx, time.Second*10)
@@ -421,7 +443,7 @@ func (p *QwenProvider) makeAPICall(ctx context.Context, url string, request Qwen
 	startTime := time.Now()
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, types.NewRequestError(types.ProviderTypeQwen, "request failed", err)
 	}
 	defer func() { _ = resp.Body.Close() }() //nolint:staticcheck // Empty branch is intentional - we ignore close errors
 
@@ -437,7 +459,7 @@ func (p *QwenProvider) makeAPICall(ctx context.Context, url string, request Qwen
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp, body, "makeAPICall")
 	}
 
 	var response QwenResponse
@@ -564,6 +586,12 @@ func (p *QwenProvider) IsOAuthConfigured() bool {
 	return p.authHelper.IsOAuthConfigured()
 }
 
+// RefreshAllOAuthTokens refreshes the OAuth credentials that are expired or about to
+// expire, using the shared helper
+func (p *QwenProvider) RefreshAllOAuthTokens(ctx context.Context) error {
+	return p.authHelper.RefreshAllOAuthTokens(ctx)
+}
+
 // IsAPIKeyConfigured checks if API key authentication is properly configured
 func (p *QwenProvider) IsAPIKeyConfigured() bool {
 	return p.authHelper.IsAPIKeyConfigured()
@@ -591,6 +619,18 @@ func (p *QwenProvider) Logout(ctx context.Context) error {
 	return p.Configure(newConfig)
 }
 
+// Warmup refreshes OAuth tokens that are expired or about to expire, so the first
+// request does not wait for a token refresh
+func (p *QwenProvider) Warmup(ctx context.Context) error {
+	return common.Warmup(ctx, p, common.WarmupOptions{})
+}
+
+// RateLimitStatus returns the rate limits reported by the most recent response, see
+// types.RateLimitStatusProvider
+func (p *QwenProvider) RateLimitStatus() types.RateLimitStatus {
+	return p.rateLimitHelper.Status()
+}
+
 // TestConnectivity performs a lightweight connectivity test to verify the provider can reach its service
 func (p *QwenProvider) TestConnectivity(ctx context.Context) error {
 	// Check for OAuth token in context first (injected by caller)
@@ -667,9 +707,8 @@ func (p *QwenProvider) TestConnectivity(ctx context.Context) error {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return types.NewNetworkError(types.ProviderTypeQwen, "connectivity test failed").
-			WithOperation("test_connectivity").
-			WithOriginalErr(err)
+		return types.NewRequestError(types.ProviderTypeQwen, "connectivity test failed", err).
+			WithOperation("test_connectivity")
 	}
 	defer func() {
 		//nolint:staticcheck // Empty branch is intentional - we ignore close errors
@@ -720,6 +759,12 @@ func (p *QwenProvider) SupportsToolCalling() bool {
 }
 
 // SupportsStreaming returns whether the provider supports streaming
+// SupportedParameters implements types.ParameterSupportReporter. Qwen returns a single choice,
+// and parallel_tool_calls, store and reasoning_effort are not sent.
+func (p *QwenProvider) SupportedParameters(model string) types.ParameterSupport {
+	return common.SingleChoiceParameters(p, model)
+}
+
 func (p *QwenProvider) SupportsStreaming() bool {
 	return true
 }
@@ -768,6 +813,11 @@ func (p *QwenProvider) executeStreamWithAuth(ctx context.Context, options types.
 		creds := p.authHelper.OAuthManager.GetCredentials()
 		var lastErr error
 		for _, cred := range creds {
+			// Failing over to another credential is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICall(ctx, baseURL+"/chat/completions", request, cred.AccessToken)
 			if err == nil {
 				return stream, nil
@@ -787,6 +837,11 @@ func (p *QwenProvider) executeStreamWithAuth(ctx context.Context, options types.
 		keys := p.authHelper.KeyManager.GetKeys()
 		var lastErr error
 		for _, apiKey := range keys {
+			// Failing over to another key is a retry of the request
+			if lastErr != nil && !types.AcquireRetry(ctx) {
+				break
+			}
+
 			stream, err := p.makeStreamingAPICall(ctx, baseURL+"/chat/completions", request, apiKey)
 			if err == nil {
 				return stream, nil
@@ -794,7 +849,7 @@ func (p *QwenProvider) executeStreamWithAuth(ctx context.Context, options types.
 			lastErr = err
 		}
 		// Fall back to configured API key if available
-		if providerConfig.APIKey != "" {
+		if providerConfig.APIKey != "" && (lastErr == nil || types.AcquireRetry(ctx)) {
 			stream, err := p.makeStreamingAPICall(ctx, baseURL+"/chat/completions", request, providerConfig.APIKey)
 			if err == nil {
 				return stream, nil
@@ -834,7 +889,7 @@ func (p *QwenProvider) makeStreamingAPICall(ctx context.Context, url string, req
 	startTime := time.Now()
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, types.NewRequestError(types.ProviderTypeQwen, "request failed", err)
 	}
 
 	duration := time.Since(startTime)
@@ -846,25 +901,28 @@ func (p *QwenProvider) makeStreamingAPICall(ctx context.Context, url string, req
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		func() { _ = resp.Body.Close() }() //nolint:staticcheck // Empty branch is intentional - we ignore close errors
-		return nil, fmt.Errorf("qwen API error: %d - %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp, body, "makeStreamingAPICall")
 	}
 
-	return &QwenRealStream{
+	return streaming.StreamFromContext(ctx, &QwenRealStream{
 		response: resp,
 		reader:   bufio.NewReader(resp.Body),
 		done:     false,
-	}, nil
+	}), nil
 }
 
 // QwenRealStream implements ChatCompletionStream for real streaming responses
 type QwenRealStream struct {
 	response *http.Response
 	reader   *bufio.Reader
+	events   *streaming.SSEScanner
 	done     bool
 	mutex    sync.Mutex
 }
 
-func (s *QwenRealStream) Next() (types.ChatCompletionChunk, error) {
+func (s *QwenRealStream) Next() (chunk types.ChatCompletionChunk, err error) {
+	defer streaming.RecoverStreamPanic(types.ProviderTypeQwen, &chunk, &err)
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -872,8 +930,12 @@ func (s *QwenRealStream) Next() (types.ChatCompletionChunk, error) {
 		return types.ChatCompletionChunk{Done: true}, io.EOF
 	}
 
+	if s.events == nil {
+		s.events = streaming.NewSSEScanner(s.reader, streaming.SSEScannerOptions{DoneSentinel: streaming.SSEDone})
+	}
+
 	for {
-		line, err := s.reader.ReadString('\n')
+		event, err := s.events.Next()
 		if err != nil {
 			if err == io.EOF {
 				s.done = true
@@ -881,23 +943,7 @@ func (s *QwenRealStream) Next() (types.ChatCompletionChunk, error) {
 			}
 			return types.ChatCompletionChunk{}, err
 		}
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		if !strings.HasPrefix(line, "data: ") {
-			continue // Skip non-data lines
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
-
-		// Check for stream end
-		if data == "[DONE]" {
-			s.done = true
-			return types.ChatCompletionChunk{Done: true}, io.EOF
-		}
+		data := event.Data
 
 		var streamResp QwenResponse
 		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
@@ -933,6 +979,9 @@ func (s *QwenRealStream) Next() (types.ChatCompletionChunk, error) {
 				s.done = true
 				return chunk, io.EOF
 			}
+			if streaming.IsHeartbeat(chunk) {
+				continue
+			}
 
 			return chunk, nil
 		}
@@ -1071,3 +1120,16 @@ func convertContentPartsToQwen(parts []types.ContentPart) interface{} {
 
 	return qwenParts
 }
+
+// newAPIError converts an error response to a ProviderError classified by its status.
+// Rate limit errors carry the response's retry hint.
+func newAPIError(resp *http.Response, body []byte, operation string) *types.ProviderError {
+	providerErr := types.NewProviderError(types.ProviderTypeQwen, types.ClassifyHTTPError(resp.StatusCode),
+		fmt.Sprintf("qwen API error: %s", string(body))).
+		WithOperation(operation).
+		WithStatusCode(resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		providerErr.WithRetryAfterDuration(retry.ParseRetryAfter(resp.Header))
+	}
+	return providerErr
+}
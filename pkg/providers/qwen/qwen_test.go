@@ -2,9 +2,11 @@ package qwen
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -450,3 +452,58 @@ func TestMockStream(t *testing.T) {
 		t.Errorf("Expected content 'Chunk 1' after close, got '%s'", chunk.Content)
 	}
 }
+
+func TestNewAPIError_RateLimit(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "12")
+
+	var rateLimitErr *types.RateLimitError
+	if !errors.As(newAPIError(resp, []byte(`{"error":{"message":"Throttling"}}`), "makeAPICall"), &rateLimitErr) {
+		t.Fatal("expected a 429 to match RateLimitError")
+	}
+	if rateLimitErr.RetryAfter != 12*time.Second {
+		t.Errorf("expected RetryAfter 12s, got %v", rateLimitErr.RetryAfter)
+	}
+	if rateLimitErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected status 429, got %d", rateLimitErr.StatusCode)
+	}
+}
+
+func TestQwenProvider_WarmupRefreshesOAuthTokens(t *testing.T) {
+	provider := NewQwenProvider(types.ProviderConfig{
+		Type: types.ProviderTypeQwen,
+		OAuthCredentials: []*types.OAuthCredentialSet{{
+			ID:           "default",
+			AccessToken:  "expired-token",
+			RefreshToken: "refresh-token",
+			ExpiresAt:    time.Now().Add(-time.Hour),
+		}},
+	})
+
+	var refreshed []string
+	provider.authHelper.SetupOAuth(func(ctx context.Context, cred *types.OAuthCredentialSet) (*types.OAuthCredentialSet, error) {
+		refreshed = append(refreshed, cred.ID)
+		updated := *cred
+		updated.AccessToken = "fresh-token"
+		updated.ExpiresAt = time.Now().Add(time.Hour)
+		return &updated, nil
+	})
+
+	if err := provider.Warmup(context.Background()); err != nil {
+		t.Fatalf("Expected warmup to succeed, got %v", err)
+	}
+	if len(refreshed) != 1 || refreshed[0] != "default" {
+		t.Errorf("Expected the expired credential to be refreshed once, got %v", refreshed)
+	}
+	if token := provider.authHelper.OAuthManager.GetCredentials()[0].AccessToken; token != "fresh-token" {
+		t.Errorf("Expected the refreshed token to be stored, got %q", token)
+	}
+}
+
+func TestQwenProvider_RefreshAllOAuthTokens_NotConfigured(t *testing.T) {
+	provider := NewQwenProvider(types.ProviderConfig{Type: types.ProviderTypeQwen})
+
+	if err := provider.RefreshAllOAuthTokens(context.Background()); err == nil {
+		t.Error("Expected error when OAuth is not configured")
+	}
+}
@@ -0,0 +1,19 @@
+// Package deterministic provides a virtual provider that records another provider's
+// responses to disk and replays them, for repeatable integration tests.
+//
+// Each request is keyed by utils.HashRequest, whether it streams and the underlying
+// provider's type and name. On a cache hit the recorded response is
+// returned without calling the underlying provider; on a miss the underlying provider is
+// called and its response recorded once the stream completes. Responses are recorded as
+// the ordered list of stream chunks, so a replay yields the same chunk boundaries as the
+// original stream. Credentials are masked in recorded content before it is written.
+//
+// In strict replay mode a cache miss returns ErrCacheMiss instead of calling out, which
+// guarantees a test run makes no network requests:
+//
+//	provider := deterministic.New(openaiProvider, "testdata/llm-cache")
+//	provider.SetStrictReplay(os.Getenv("CI") != "")
+//
+// Only chat completions are recorded; the provider's other methods call the underlying
+// provider directly.
+package deterministic
@@ -0,0 +1,246 @@
+package deterministic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	commonerrors "github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/errors"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/utils"
+)
+
+// ErrCacheMiss is returned in strict replay mode for requests that have no recording
+var ErrCacheMiss = errors.New("no recorded response for request")
+
+// recording is the on-disk form of a recorded response
+type recording struct {
+	Key    string                      `json:"key"`
+	Model  string                      `json:"model,omitempty"`
+	Chunks []types.ChatCompletionChunk `json:"chunks"`
+}
+
+// DeterministicProvider wraps a provider, replaying recorded responses and recording
+// new ones. Methods other than GenerateChatCompletion go to the underlying provider.
+type DeterministicProvider struct {
+	types.Provider
+	cacheDir string
+	masker   commonerrors.CredentialMasker
+
+	mu           sync.RWMutex
+	strictReplay bool
+}
+
+// New creates a provider that records underlying's responses in cacheDir, which is
+// created on the first recording
+func New(underlying types.Provider, cacheDir string) *DeterministicProvider {
+	return &DeterministicProvider{
+		Provider: underlying,
+		cacheDir: cacheDir,
+		masker:   commonerrors.DefaultCredentialMasker(),
+	}
+}
+
+// SetStrictReplay enables or disables strict replay mode, in which requests without a
+// recording fail with ErrCacheMiss instead of calling the underlying provider
+func (d *DeterministicProvider) SetStrictReplay(strict bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.strictReplay = strict
+}
+
+// StrictReplay reports whether strict replay mode is enabled
+func (d *DeterministicProvider) StrictReplay() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.strictReplay
+}
+
+func (d *DeterministicProvider) Type() types.ProviderType { return "deterministic" }
+func (d *DeterministicProvider) Description() string {
+	return "Replays recorded responses of another provider"
+}
+
+// CachePath returns the file a request's response is recorded in
+func (d *DeterministicProvider) CachePath(opts types.GenerateOptions) string {
+	return filepath.Join(d.cacheDir, d.cacheKey(opts)+".json")
+}
+
+// cacheKey extends utils.HashRequest, which ignores streaming, with whether the request
+// streams and the underlying provider's type and name, so requests that differ only in
+// those don't share a recording when several providers use one cache directory
+func (d *DeterministicProvider) cacheKey(opts types.GenerateOptions) string {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%t", utils.HashRequest(opts), d.Provider.Type(), d.Provider.Name(), opts.Stream)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateChatCompletion replays the request's recorded response, or calls the
+// underlying provider and records its response once the stream completes
+func (d *DeterministicProvider) GenerateChatCompletion(ctx context.Context, opts types.GenerateOptions) (types.ChatCompletionStream, error) {
+	path := d.CachePath(opts)
+
+	rec, err := readRecording(path)
+	if err == nil {
+		return streaming.NewMockStream(rec.Chunks), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read recorded response %s: %w", path, err)
+	}
+
+	if d.StrictReplay() {
+		return nil, fmt.Errorf("%w: %s (model %q)", ErrCacheMiss, path, opts.Model)
+	}
+
+	stream, err := d.Provider.GenerateChatCompletion(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingStream{
+		inner:    stream,
+		provider: d,
+		path:     path,
+		rec:      recording{Key: d.cacheKey(opts), Model: opts.Model},
+	}, nil
+}
+
+// recordingStream passes chunks through and writes them to the cache once the stream
+// ends with io.EOF, or is closed after a Done chunk. Chunks following the Done chunk,
+// such as a final usage chunk, are recorded too. Streams that fail or are closed before
+// their Done chunk are not recorded.
+type recordingStream struct {
+	inner    types.ChatCompletionStream
+	provider *DeterministicProvider
+	path     string
+	rec      recording
+	done     bool // A Done chunk was read
+	failed   bool
+	saved    bool
+}
+
+func (s *recordingStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.failed = true
+		return chunk, err
+	}
+
+	if errors.Is(err, io.EOF) {
+		// Some streams return their last content together with io.EOF
+		if !s.saved && hasContent(chunk) {
+			s.rec.Chunks = append(s.rec.Chunks, s.provider.mask(chunk))
+		}
+		s.save()
+		return chunk, err
+	}
+
+	if !s.saved {
+		s.rec.Chunks = append(s.rec.Chunks, s.provider.mask(chunk))
+	}
+	if chunk.Done {
+		s.done = true
+	}
+	return chunk, nil
+}
+
+func hasContent(chunk types.ChatCompletionChunk) bool {
+	return chunk.Content != "" || len(chunk.Choices) > 0 || chunk.Usage.TotalTokens > 0
+}
+
+// save writes the recording once, unless the stream failed. Failing to record never
+// fails the stream; the request is simply recorded again next time.
+func (s *recordingStream) save() {
+	if s.saved || s.failed {
+		return
+	}
+	s.saved = true
+	_ = writeRecording(s.path, s.rec)
+}
+
+// Close records a stream that was read up to its Done chunk without reaching io.EOF
+func (s *recordingStream) Close() error {
+	if s.done {
+		s.save()
+	}
+	return s.inner.Close()
+}
+
+// mask returns a copy of chunk with credentials masked in its text
+func (d *DeterministicProvider) mask(chunk types.ChatCompletionChunk) types.ChatCompletionChunk {
+	chunk.Content = d.masker.MaskString(chunk.Content)
+	chunk.Reasoning = d.masker.MaskString(chunk.Reasoning)
+	chunk.ReasoningContent = d.masker.MaskString(chunk.ReasoningContent)
+	chunk.Error = d.masker.MaskString(chunk.Error)
+
+	if len(chunk.Choices) > 0 {
+		choices := make([]types.ChatChoice, len(chunk.Choices))
+		for i, choice := range chunk.Choices {
+			choice.Message = d.maskMessage(choice.Message)
+			choice.Delta = d.maskMessage(choice.Delta)
+			choices[i] = choice
+		}
+		chunk.Choices = choices
+	}
+	return chunk
+}
+
+func (d *DeterministicProvider) maskMessage(message types.ChatMessage) types.ChatMessage {
+	message.Content = d.masker.MaskString(message.Content)
+	message.Reasoning = d.masker.MaskString(message.Reasoning)
+	if len(message.ToolCalls) > 0 {
+		toolCalls := make([]types.ToolCall, len(message.ToolCalls))
+		for i, call := range message.ToolCalls {
+			call.Function.Arguments = d.masker.MaskString(call.Function.Arguments)
+			toolCalls[i] = call
+		}
+		message.ToolCalls = toolCalls
+	}
+	return message
+}
+
+func readRecording(path string) (recording, error) {
+	var rec recording
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is built from the cache dir and a hex hash
+	if err != nil {
+		return rec, err
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, err
+	}
+	return rec, nil
+}
+
+// writeRecording writes rec to path through a temporary file, so concurrent readers
+// never see a partial recording
+func writeRecording(path string, rec recording) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,187 @@
+package deterministic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/streaming"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider streams fixed chunks and counts its calls; its other methods are unused
+type countingProvider struct {
+	types.Provider
+	providerType types.ProviderType
+	name         string
+	chunks       []types.ChatCompletionChunk
+	err          error
+	calls        int
+}
+
+func (p *countingProvider) Type() types.ProviderType { return p.providerType }
+func (p *countingProvider) Name() string             { return p.name }
+
+func (p *countingProvider) GenerateChatCompletion(ctx context.Context, opts types.GenerateOptions) (types.ChatCompletionStream, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return streaming.NewMockStream(p.chunks), nil
+}
+
+// collect reads the stream up to its Done chunk and closes it, as most consumers do
+func collect(t *testing.T, stream types.ChatCompletionStream) []types.ChatCompletionChunk {
+	t.Helper()
+	defer func() { require.NoError(t, stream.Close()) }()
+	var chunks []types.ChatCompletionChunk
+	for {
+		chunk, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+		if chunk.Done {
+			return chunks
+		}
+	}
+}
+
+var weatherRequest = types.GenerateOptions{
+	Model:    "test-model",
+	Messages: []types.ChatMessage{{Role: "user", Content: "Weather?"}},
+}
+
+func TestDeterministicProvider_RecordsAndReplays(t *testing.T) {
+	underlying := &countingProvider{chunks: []types.ChatCompletionChunk{
+		{Content: "Sun"},
+		{Content: "ny"},
+		{Content: " today", Done: true, Usage: types.Usage{TotalTokens: 7}},
+	}}
+	provider := New(underlying, t.TempDir())
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	first := collect(t, stream)
+	assert.FileExists(t, provider.CachePath(weatherRequest))
+
+	stream, err = provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	replayed := collect(t, stream)
+
+	assert.Equal(t, 1, underlying.calls, "the replay must not call the underlying provider")
+	assert.Equal(t, first, replayed, "the replay keeps the chunk boundaries")
+}
+
+func TestDeterministicProvider_CachePathKeysProviderAndStreaming(t *testing.T) {
+	dir := t.TempDir()
+	openai := New(&countingProvider{providerType: types.ProviderTypeOpenAI, name: "openai"}, dir)
+	anthropic := New(&countingProvider{providerType: types.ProviderTypeAnthropic, name: "anthropic"}, dir)
+	openaiBackup := New(&countingProvider{providerType: types.ProviderTypeOpenAI, name: "openai-backup"}, dir)
+
+	streamed := weatherRequest
+	streamed.Stream = true
+
+	paths := map[string]string{
+		"openai":           openai.CachePath(weatherRequest),
+		"anthropic":        anthropic.CachePath(weatherRequest),
+		"openai-backup":    openaiBackup.CachePath(weatherRequest),
+		"openai streaming": openai.CachePath(streamed),
+	}
+	seen := make(map[string]string)
+	for name, path := range paths {
+		if other, ok := seen[path]; ok {
+			t.Errorf("%s and %s share the cache path %s", name, other, path)
+		}
+		seen[path] = name
+	}
+	assert.Equal(t, openai.CachePath(weatherRequest), openai.CachePath(weatherRequest), "the key must be stable")
+}
+
+func TestDeterministicProvider_RecordsChunksAfterDone(t *testing.T) {
+	underlying := &countingProvider{chunks: []types.ChatCompletionChunk{
+		{Content: "Sunny", Done: true},
+		{Usage: types.Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7}},
+	}}
+	provider := New(underlying, t.TempDir())
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Next(); err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+	require.NoError(t, stream.Close())
+
+	rec, err := readRecording(provider.CachePath(weatherRequest))
+	require.NoError(t, err)
+	require.Len(t, rec.Chunks, 2, "the usage chunk after the Done chunk is recorded")
+	assert.Equal(t, 7, rec.Chunks[1].Usage.TotalTokens)
+}
+
+func TestDeterministicProvider_DoesNotRecordStreamsClosedEarly(t *testing.T) {
+	underlying := &countingProvider{chunks: []types.ChatCompletionChunk{
+		{Content: "Sun"},
+		{Content: "ny", Done: true},
+	}}
+	provider := New(underlying, t.TempDir())
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	_, err = stream.Next()
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+
+	assert.NoFileExists(t, provider.CachePath(weatherRequest))
+}
+
+func TestDeterministicProvider_StrictReplay(t *testing.T) {
+	underlying := &countingProvider{chunks: []types.ChatCompletionChunk{{Content: "Hi", Done: true}}}
+	provider := New(underlying, t.TempDir())
+	provider.SetStrictReplay(true)
+
+	_, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	assert.Equal(t, 0, underlying.calls)
+
+	provider.SetStrictReplay(false)
+	stream, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	collect(t, stream)
+
+	provider.SetStrictReplay(true)
+	stream, err = provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", collect(t, stream)[0].Content)
+}
+
+func TestDeterministicProvider_MasksRecordings(t *testing.T) {
+	underlying := &countingProvider{chunks: []types.ChatCompletionChunk{
+		{Content: "Use Bearer abc123secret to connect", Done: true},
+	}}
+	provider := New(underlying, t.TempDir())
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.NoError(t, err)
+	assert.Contains(t, collect(t, stream)[0].Content, "abc123secret", "the live stream is not masked")
+
+	data, err := os.ReadFile(provider.CachePath(weatherRequest))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "abc123secret")
+}
+
+func TestDeterministicProvider_DoesNotRecordFailures(t *testing.T) {
+	underlying := &countingProvider{err: errors.New("provider down")}
+	provider := New(underlying, t.TempDir())
+
+	_, err := provider.GenerateChatCompletion(context.Background(), weatherRequest)
+	require.Error(t, err)
+	assert.NoFileExists(t, provider.CachePath(weatherRequest))
+}
@@ -6,7 +6,7 @@
 //
 // # Available Virtual Providers
 //
-// The package includes three virtual provider types, each in its own sub-package:
+// The package includes four virtual provider types, each in its own sub-package:
 //
 // # Racing Provider (racing/)
 //
@@ -35,6 +35,14 @@
 //   - Rate limit management across multiple API keys
 //   - Geographic distribution of requests
 //
+// # Deterministic Provider (deterministic/)
+//
+// The deterministic provider wraps a single provider, recording its responses to disk and
+// replaying them for identical requests. This is useful for:
+//
+//   - Repeatable integration tests of agents and prompts
+//   - Guaranteeing no network calls in CI with strict replay mode
+//
 // # Usage
 //
 // Virtual providers are configured through the factory package and can be nested:
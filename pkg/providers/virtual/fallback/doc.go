@@ -1,4 +1,15 @@
 // Package fallback provides a virtual provider that implements fallback logic.
 // It tries providers sequentially in order until one succeeds, providing automatic
 // failover for improved reliability when individual providers fail.
+//
+// By default a provider is chosen once its stream has started, and later stream
+// errors are returned to the caller. With Config.StreamFailover enabled, a stream that
+// fails before its commit point is closed and the request continues on the next
+// provider without the caller seeing the failure. The commit point is set by
+// Config.CommitAfterChunks: the stream commits once it has delivered its first chunk,
+// or holds back that many chunks first. After the commit point errors are surfaced
+// rather than restarting, so output is never duplicated or mixed between providers.
+//
+// With Config.PreferWarmCache, providers whose prompt cache is warm, judged from the
+// cache reads in the Usage of their recent responses, are tried before the others.
 package fallback
@@ -0,0 +1,211 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// scriptedStream returns its chunks in order, then err (io.EOF if nil)
+type scriptedStream struct {
+	chunks []string
+	err    error
+	index  int
+	closed bool
+}
+
+func (s *scriptedStream) Next() (types.ChatCompletionChunk, error) {
+	if s.index < len(s.chunks) {
+		s.index++
+		return types.ChatCompletionChunk{Content: s.chunks[s.index-1]}, nil
+	}
+	if s.err != nil {
+		return types.ChatCompletionChunk{}, s.err
+	}
+	return types.ChatCompletionChunk{Done: true}, io.EOF
+}
+
+func (s *scriptedStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+// scriptedProvider returns a scriptedStream with the given chunks and trailing error
+type scriptedProvider struct {
+	mockChatProvider
+	chunks []string
+	err    error
+	stream *scriptedStream
+}
+
+func (p *scriptedProvider) GenerateChatCompletion(ctx context.Context, opts types.GenerateOptions) (types.ChatCompletionStream, error) {
+	p.stream = &scriptedStream{chunks: p.chunks, err: p.err}
+	return p.stream, nil
+}
+
+func newScriptedProvider(name string, err error, chunks ...string) *scriptedProvider {
+	return &scriptedProvider{mockChatProvider: mockChatProvider{name: name}, chunks: chunks, err: err}
+}
+
+// drain reads a stream to completion, returning the content and the terminal error
+func drain(t *testing.T, stream types.ChatCompletionStream) (string, []string, error) {
+	t.Helper()
+	var content strings.Builder
+	var providers []string
+	for {
+		chunk, err := stream.Next()
+		content.WriteString(chunk.Content)
+		if chunk.Content != "" {
+			providers = append(providers, chunk.Metadata["fallback_provider"].(string))
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return content.String(), providers, nil
+			}
+			return content.String(), providers, err
+		}
+	}
+}
+
+func TestFailoverStream_ErrorBeforeFirstChunkFailsOver(t *testing.T) {
+	primary := newScriptedProvider("primary", errors.New("connection reset"))
+	secondary := newScriptedProvider("secondary", nil, "Hello", " world")
+
+	fallback := NewFallbackProvider("test", &Config{StreamFailover: true})
+	fallback.SetProviders([]types.Provider{primary, secondary})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	content, providers, err := drain(t, stream)
+	if err != nil {
+		t.Fatalf("expected no stream error, got %v", err)
+	}
+	if content != "Hello world" {
+		t.Errorf("expected content 'Hello world', got %q", content)
+	}
+	for _, p := range providers {
+		if p != "secondary" {
+			t.Errorf("expected all chunks from secondary, got %s", p)
+		}
+	}
+	if !primary.stream.closed {
+		t.Error("expected failed primary stream to be closed")
+	}
+}
+
+func TestFailoverStream_ErrorAfterCommitIsSurfaced(t *testing.T) {
+	streamErr := errors.New("connection reset")
+	primary := newScriptedProvider("primary", streamErr, "Hello")
+	secondary := newScriptedProvider("secondary", nil, "Different answer")
+
+	fallback := NewFallbackProvider("test", &Config{StreamFailover: true})
+	fallback.SetProviders([]types.Provider{primary, secondary})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	content, _, err := drain(t, stream)
+	if !errors.Is(err, streamErr) {
+		t.Errorf("expected stream error to be surfaced, got %v", err)
+	}
+	if content != "Hello" {
+		t.Errorf("expected only primary content, got %q", content)
+	}
+	if secondary.stream != nil {
+		t.Error("expected secondary not to be called after commit")
+	}
+}
+
+func TestFailoverStream_CommitAfterChunksBuffersEarlyOutput(t *testing.T) {
+	primary := newScriptedProvider("primary", errors.New("connection reset"), "Hel", "lo")
+	secondary := newScriptedProvider("secondary", nil, "Hi", " there", "!")
+
+	fallback := NewFallbackProvider("test", &Config{StreamFailover: true, CommitAfterChunks: 2})
+	fallback.SetProviders([]types.Provider{primary, secondary})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	content, providers, err := drain(t, stream)
+	if err != nil {
+		t.Fatalf("expected no stream error, got %v", err)
+	}
+	if content != "Hi there!" {
+		t.Errorf("expected buffered primary output to be discarded, got %q", content)
+	}
+	if len(providers) != 3 || providers[0] != "secondary" {
+		t.Errorf("expected chunks from secondary, got %v", providers)
+	}
+}
+
+func TestFailoverStream_ShortResponseWithinCommitPoint(t *testing.T) {
+	primary := newScriptedProvider("primary", nil, "Hi")
+
+	fallback := NewFallbackProvider("test", &Config{StreamFailover: true, CommitAfterChunks: 5})
+	fallback.SetProviders([]types.Provider{primary})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	content, _, err := drain(t, stream)
+	if err != nil {
+		t.Fatalf("expected no stream error, got %v", err)
+	}
+	if content != "Hi" {
+		t.Errorf("expected content 'Hi', got %q", content)
+	}
+}
+
+func TestFailoverStream_AllProvidersFailMidStream(t *testing.T) {
+	lastErr := errors.New("secondary reset")
+	primary := newScriptedProvider("primary", errors.New("primary reset"))
+	secondary := newScriptedProvider("secondary", lastErr)
+
+	fallback := NewFallbackProvider("test", &Config{StreamFailover: true})
+	fallback.SetProviders([]types.Provider{primary, secondary})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, _, err = drain(t, stream)
+	if !errors.Is(err, lastErr) {
+		t.Errorf("expected last stream error, got %v", err)
+	}
+}
+
+func TestFallbackStream_FailoverDisabledSurfacesEarlyError(t *testing.T) {
+	streamErr := errors.New("connection reset")
+	primary := newScriptedProvider("primary", streamErr)
+	secondary := newScriptedProvider("secondary", nil, "Hello")
+
+	fallback := NewFallbackProvider("test", &Config{})
+	fallback.SetProviders([]types.Provider{primary, secondary})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, _, err = drain(t, stream)
+	if !errors.Is(err, streamErr) {
+		t.Errorf("expected stream error without failover, got %v", err)
+	}
+	if secondary.stream != nil {
+		t.Error("expected secondary not to be called")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/metrics"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/base"
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
 
@@ -420,3 +421,26 @@ func (m *mockProviderWithMetrics) HealthCheck(ctx context.Context) error { retur
 func (m *mockProviderWithMetrics) GetMetrics() types.ProviderMetrics {
 	return m.metrics
 }
+
+func TestFallbackProvider_SnapshotMetrics(t *testing.T) {
+	fp := NewFallbackProvider("fallback-test", &Config{})
+
+	child1 := base.NewBaseProviderStub("provider-1", types.ProviderConfig{}, nil, nil)
+	child2 := base.NewBaseProviderStub("provider-2", types.ProviderConfig{}, nil, nil)
+	child1.RecordRequest(context.Background(), "test-model")
+	child1.RecordSuccess(time.Second, 100)
+	child2.RecordRequest(context.Background(), "test-model")
+	child2.RecordError(errors.New("unavailable"))
+
+	fp.SetProviders([]types.Provider{child1, child2})
+
+	snapshot := fp.SnapshotMetrics()
+	if snapshot.RequestCount != 2 || snapshot.SuccessCount != 1 || snapshot.ErrorCount != 1 || snapshot.TokensUsed != 100 {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+
+	metrics := fp.GetMetrics()
+	if metrics.RequestCount != 0 || metrics.SuccessCount != 0 || metrics.ErrorCount != 0 || metrics.TokensUsed != 0 {
+		t.Errorf("expected child counters to be reset, got %+v", metrics)
+	}
+}
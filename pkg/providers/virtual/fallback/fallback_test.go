@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -855,6 +857,23 @@ func TestFallbackProvider_Configure(t *testing.T) {
 	}
 }
 
+// TestFallbackProvider_ConfigureCommitAfterChunks tests that commit_after_chunks is
+// read both as an int and as a JSON-decoded float64
+func TestFallbackProvider_ConfigureCommitAfterChunks(t *testing.T) {
+	for _, value := range []interface{}{3, float64(3)} {
+		fallback := NewFallbackProvider("test-fallback", &Config{})
+		err := fallback.Configure(types.ProviderConfig{
+			ProviderConfig: map[string]interface{}{"commit_after_chunks": value},
+		})
+		if err != nil {
+			t.Errorf("expected no error from Configure, got %v", err)
+		}
+		if fallback.config.CommitAfterChunks != 3 {
+			t.Errorf("expected CommitAfterChunks 3 for %T, got %d", value, fallback.config.CommitAfterChunks)
+		}
+	}
+}
+
 // TestFallbackProvider_ConfigureEmpty tests Configure with empty config
 func TestFallbackProvider_ConfigureEmpty(t *testing.T) {
 	fallback := NewFallbackProvider("test-fallback", &Config{
@@ -1040,3 +1059,180 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// rateLimitedProvider is a mockChatProvider reporting a rate-limit status
+type rateLimitedProvider struct {
+	*mockChatProvider
+	status types.RateLimitStatus
+}
+
+func (p *rateLimitedProvider) RateLimitStatus() types.RateLimitStatus { return p.status }
+
+func TestFallbackProvider_SkipsRateLimitedProviders(t *testing.T) {
+	exhausted := types.RateLimitStatus{Known: true, RetryAt: time.Now().Add(time.Minute)}
+	expired := types.RateLimitStatus{Known: true, RetryAt: time.Now().Add(-time.Minute)}
+
+	t.Run("skips a limited provider", func(t *testing.T) {
+		fb := NewFallbackProvider("fb", &Config{})
+		fb.SetProviders([]types.Provider{
+			&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "limited"}, status: exhausted},
+			&mockChatProvider{name: "next"},
+		})
+
+		stream, err := fb.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		chunk, _ := stream.Next()
+		if got := chunk.Metadata["fallback_provider"]; got != "next" {
+			t.Errorf("expected the next provider to serve the request, got %v", got)
+		}
+	})
+
+	t.Run("uses a provider whose limit has reset", func(t *testing.T) {
+		fb := NewFallbackProvider("fb", &Config{})
+		fb.SetProviders([]types.Provider{
+			&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "recovered"}, status: expired},
+			&mockChatProvider{name: "next"},
+		})
+
+		stream, err := fb.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		chunk, _ := stream.Next()
+		if got := chunk.Metadata["fallback_provider"]; got != "recovered" {
+			t.Errorf("expected the recovered provider to serve the request, got %v", got)
+		}
+	})
+
+	t.Run("always tries the last provider", func(t *testing.T) {
+		fb := NewFallbackProvider("fb", &Config{})
+		fb.SetProviders([]types.Provider{
+			&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "only"}, status: exhausted},
+		})
+
+		if _, err := fb.GenerateChatCompletion(context.Background(), types.GenerateOptions{}); err != nil {
+			t.Fatalf("expected the last provider to be tried, got %v", err)
+		}
+	})
+}
+
+func TestFallbackProvider_TargetProvider(t *testing.T) {
+	fallback := NewFallbackProvider("test-fallback", &Config{
+		ProviderNames: []string{"provider1", "provider2"},
+	})
+	fallback.SetProviders([]types.Provider{
+		&mockChatProvider{name: "provider1", err: errors.New("provider1 failed")},
+		&mockChatProvider{name: "provider2"},
+	})
+
+	stream, err := fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "test", TargetProvider: "provider2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunk, _ := stream.Next()
+	_ = stream.Close()
+	if chunk.Content != "test response from provider2" {
+		t.Errorf("expected a response from provider2, got %q", chunk.Content)
+	}
+
+	_, err = fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "test", TargetProvider: "provider1"})
+	if err == nil {
+		t.Error("expected the pinned provider's error, without falling back")
+	}
+
+	_, err = fallback.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "test", TargetProvider: "provider3"})
+	if !types.IsValidationError(err) {
+		t.Errorf("expected a validation error for an unknown target, got %v", err)
+	}
+}
+
+func TestFallbackProvider_RetryBudget(t *testing.T) {
+	fallback := NewFallbackProvider("test-fallback", &Config{
+		ProviderNames: []string{"provider1", "provider2", "provider3"},
+	})
+	fallback.SetProviders([]types.Provider{
+		&mockChatProvider{name: "provider1", err: errors.New("provider1 failed")},
+		&mockChatProvider{name: "provider2", err: errors.New("provider2 failed")},
+		&mockChatProvider{name: "provider3"},
+	})
+
+	budget := types.NewRetryBudget(1)
+	ctx := types.WithRetryBudget(context.Background(), budget)
+	_, err := fallback.GenerateChatCompletion(ctx, types.GenerateOptions{Prompt: "test"})
+	if err == nil || !strings.Contains(err.Error(), "provider2 failed") {
+		t.Fatalf("expected the budget to stop after one fallback with its error, got %v", err)
+	}
+	if budget.Remaining() != 0 {
+		t.Errorf("expected the budget to be spent, %d left", budget.Remaining())
+	}
+
+	ctx = types.WithRetryBudget(context.Background(), types.NewRetryBudget(2))
+	stream, err := fallback.GenerateChatCompletion(ctx, types.GenerateOptions{Prompt: "test"})
+	if err != nil {
+		t.Fatalf("expected the third provider to be reached, got %v", err)
+	}
+	_ = stream.Close()
+}
+
+// cachedUsageProvider is a mockChatProvider whose response reports prompt cache usage
+type cachedUsageProvider struct {
+	*mockChatProvider
+	usage types.Usage
+}
+
+func (p *cachedUsageProvider) GenerateChatCompletion(ctx context.Context, opts types.GenerateOptions) (types.ChatCompletionStream, error) {
+	return &usageStream{usage: p.usage}, nil
+}
+
+// usageStream returns one final chunk carrying usage
+type usageStream struct {
+	usage types.Usage
+}
+
+func (s *usageStream) Next() (types.ChatCompletionChunk, error) {
+	return types.ChatCompletionChunk{Content: "cached", Done: true, Usage: s.usage}, io.EOF
+}
+
+func (s *usageStream) Close() error { return nil }
+
+func TestFallbackProvider_PreferWarmCache(t *testing.T) {
+	warm := types.Usage{PromptTokens: 1000, CacheReadTokens: 900}
+
+	fb := NewFallbackProvider("fb", &Config{PreferWarmCache: true})
+	fb.SetProviders([]types.Provider{
+		&mockChatProvider{name: "primary"},
+		&cachedUsageProvider{mockChatProvider: &mockChatProvider{name: "secondary"}, usage: warm},
+	})
+
+	// Pinned to the secondary, whose response shows a warm cache
+	stream, err := fb.GenerateChatCompletion(context.Background(), types.GenerateOptions{TargetProvider: "secondary"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = stream.Next()
+	if rate := fb.CacheHitRate("secondary"); rate != 0.9 {
+		t.Fatalf("expected a 0.9 hit rate for the secondary, got %v", rate)
+	}
+
+	stream, err = fb.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunk, _ := stream.Next()
+	if got := chunk.Metadata["fallback_provider"]; got != "secondary" {
+		t.Errorf("expected the cache-warm provider to be tried first, got %v", got)
+	}
+
+	// Without the option the configured order is kept
+	fb.config.PreferWarmCache = false
+	stream, err = fb.GenerateChatCompletion(context.Background(), types.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunk, _ = stream.Next()
+	if got := chunk.Metadata["fallback_provider"]; got != "primary" {
+		t.Errorf("expected the primary provider, got %v", got)
+	}
+}
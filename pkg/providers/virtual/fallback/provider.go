@@ -2,7 +2,9 @@ package fallback
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -15,21 +17,52 @@ type FallbackProvider struct {
 	providers        []types.Provider
 	config           *Config
 	metricsCollector types.MetricsCollector
+	cacheHits        *types.CacheHitTracker
 	mu               sync.RWMutex
 }
 
 type Config struct {
 	ProviderNames []string `yaml:"providers"`
 	MaxRetries    int      `yaml:"max_retries"`
+
+	// StreamFailover retries on the next provider when a stream fails mid-way, as
+	// long as the failure happens before the stream's commit point. Once committed,
+	// stream errors are returned to the caller instead, since restarting on another
+	// provider would duplicate or garble the output already delivered.
+	StreamFailover bool `yaml:"stream_failover"`
+
+	// CommitAfterChunks is the number of chunks held back before the stream commits
+	// to a provider. With 0 (the default) the stream commits as soon as the first
+	// chunk is delivered; a larger value buffers that many chunks so failures early
+	// in a response can still fail over invisibly, at the cost of added latency.
+	CommitAfterChunks int `yaml:"commit_after_chunks"`
+
+	// PreferWarmCache tries providers whose prompt cache is warm before the others, so
+	// a request does not move off a provider that would read most of its prompt from
+	// cache. A provider is warm when the recent share of its prompt tokens read from
+	// cache, reported in the Usage of its responses, is at least CacheWarmThreshold
+	// (0.5 when unset). Warm and cold providers each keep their configured order.
+	PreferWarmCache    bool    `yaml:"prefer_warm_cache"`
+	CacheWarmThreshold float64 `yaml:"cache_warm_threshold"`
 }
 
+// defaultCacheWarmThreshold is the cache hit rate at which a provider counts as warm
+const defaultCacheWarmThreshold = 0.5
+
 func NewFallbackProvider(name string, config *Config) *FallbackProvider {
 	return &FallbackProvider{
-		name:   name,
-		config: config,
+		name:      name,
+		config:    config,
+		cacheHits: types.NewCacheHitTracker(0),
 	}
 }
 
+// CacheHitRate returns the recent prompt cache hit rate of the named provider, from 0
+// to 1, as seen in the responses it served through this fallback provider
+func (f *FallbackProvider) CacheHitRate(providerName string) float64 {
+	return f.cacheHits.HitRate(providerName)
+}
+
 func (f *FallbackProvider) SetProviders(providers []types.Provider) {
 	f.providers = providers
 }
@@ -44,6 +77,18 @@ func (f *FallbackProvider) GenerateChatCompletion(ctx context.Context, opts type
 	providers := f.providers
 	f.mu.RUnlock()
 
+	// A pinned request goes to its target alone, without falling back
+	if opts.TargetProvider != "" {
+		target, err := types.FindTargetProvider(providers, opts.TargetProvider)
+		if err != nil {
+			return nil, err
+		}
+		providers = []types.Provider{target}
+		opts.TargetProvider = ""
+	} else if f.config != nil && f.config.PreferWarmCache {
+		providers = f.warmFirst(providers)
+	}
+
 	// Record request
 	if collector != nil {
 		_ = collector.RecordEvent(ctx, types.MetricEvent{
@@ -55,18 +100,84 @@ func (f *FallbackProvider) GenerateChatCompletion(ctx context.Context, opts type
 		})
 	}
 
+	stream, index, err := f.openStream(ctx, opts, providers, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.config != nil && f.config.StreamFailover {
+		return &failoverStream{
+			ctx:          ctx,
+			opts:         opts,
+			provider:     f,
+			providers:    providers,
+			commitAfter:  f.config.CommitAfterChunks,
+			inner:        stream,
+			providerName: providers[index].Name(),
+			index:        index,
+		}, nil
+	}
+
+	return &fallbackStream{
+		inner:         stream,
+		providerName:  providers[index].Name(),
+		providerIndex: index,
+		cacheHits:     f.cacheHits,
+	}, nil
+}
+
+// warmFirst returns providers with those whose cache is warm moved to the front
+func (f *FallbackProvider) warmFirst(providers []types.Provider) []types.Provider {
+	threshold := f.config.CacheWarmThreshold
+	if threshold <= 0 {
+		threshold = defaultCacheWarmThreshold
+	}
+
+	ordered := make([]types.Provider, 0, len(providers))
+	var cold []types.Provider
+	for _, provider := range providers {
+		if f.cacheHits.HitRate(provider.Name()) >= threshold {
+			ordered = append(ordered, provider)
+		} else {
+			cold = append(cold, provider)
+		}
+	}
+	return append(ordered, cold...)
+}
+
+// openStream starts a completion on the first provider at or after start that
+// succeeds, returning the stream and the index of the provider that produced it
+func (f *FallbackProvider) openStream(ctx context.Context, opts types.GenerateOptions, providers []types.Provider, start int) (types.ChatCompletionStream, int, error) {
+	f.mu.RLock()
+	collector := f.metricsCollector
+	f.mu.RUnlock()
+
 	var lastErr error
 	var previousProvider string
+	if start > 0 {
+		previousProvider = providers[start-1].Name()
+	}
 
-	for i, provider := range providers {
+	for i := start; i < len(providers); i++ {
+		provider := providers[i]
 		chatProvider, ok := provider.(types.ChatProvider)
 		if !ok {
 			continue
 		}
 
-		start := time.Now()
+		// Pass over a provider that reported its rate limits exhausted, unless it is the last one
+		if i < len(providers)-1 && types.ProviderRateLimitStatus(provider).Limited(time.Now()) {
+			continue
+		}
+
+		// Falling back after a failure is a retry of the request
+		if lastErr != nil && !types.AcquireRetry(ctx) {
+			break
+		}
+
+		attemptStart := time.Now()
 		stream, err := chatProvider.GenerateChatCompletion(ctx, opts)
-		latency := time.Since(start)
+		latency := time.Since(attemptStart)
 
 		if err == nil {
 			// Success - emit provider switch event if not the first provider
@@ -97,11 +208,7 @@ func (f *FallbackProvider) GenerateChatCompletion(ctx context.Context, opts type
 				})
 			}
 
-			return &fallbackStream{
-				inner:         stream,
-				providerName:  provider.Name(),
-				providerIndex: i,
-			}, nil
+			return stream, i, nil
 		}
 
 		// Record fallback attempt failure
@@ -145,19 +252,25 @@ func (f *FallbackProvider) GenerateChatCompletion(ctx context.Context, opts type
 	}
 
 	if lastErr != nil {
-		return nil, fmt.Errorf("all providers failed, last error: %w", lastErr)
+		return nil, -1, fmt.Errorf("all providers failed, last error: %w", lastErr)
 	}
-	return nil, fmt.Errorf("no providers available")
+	return nil, -1, fmt.Errorf("no providers available")
 }
 
 type fallbackStream struct {
 	inner         types.ChatCompletionStream
 	providerName  string
 	providerIndex int
+	cacheHits     *types.CacheHitTracker
+	usageRecorded bool
 }
 
 func (s *fallbackStream) Next() (types.ChatCompletionChunk, error) {
 	chunk, err := s.inner.Next()
+	if !s.usageRecorded && chunk.Usage.PromptTokens > 0 {
+		s.cacheHits.Record(s.providerName, chunk.Usage)
+		s.usageRecorded = true
+	}
 	if chunk.Metadata == nil {
 		chunk.Metadata = make(map[string]interface{})
 	}
@@ -169,3 +282,106 @@ func (s *fallbackStream) Next() (types.ChatCompletionChunk, error) {
 func (s *fallbackStream) Close() error {
 	return s.inner.Close()
 }
+
+// failoverStream is the stream returned when StreamFailover is enabled. Until the
+// commit point it holds chunks back and, if the current provider's stream fails,
+// closes it and continues on the next provider. After the commit point it behaves
+// like fallbackStream.
+type failoverStream struct {
+	ctx         context.Context
+	opts        types.GenerateOptions
+	provider    *FallbackProvider
+	providers   []types.Provider
+	commitAfter int
+
+	inner        types.ChatCompletionStream
+	providerName string
+	index        int
+
+	committed  bool
+	buffered   []types.ChatCompletionChunk
+	pending    []types.ChatCompletionChunk // committed chunks waiting to be returned
+	pendingErr error                       // returned once pending is drained
+
+	usageRecorded bool // The current provider's prompt usage has been seen
+}
+
+func (s *failoverStream) Next() (types.ChatCompletionChunk, error) {
+	for {
+		if len(s.pending) > 0 {
+			chunk := s.pending[0]
+			s.pending = s.pending[1:]
+			if len(s.pending) == 0 && s.pendingErr != nil {
+				return chunk, s.pendingErr
+			}
+			return chunk, nil
+		}
+		if s.pendingErr != nil {
+			return types.ChatCompletionChunk{Done: true}, s.pendingErr
+		}
+
+		chunk, err := s.inner.Next()
+		if s.committed {
+			return s.tag(chunk), err
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			if failoverErr := s.failover(err); failoverErr != nil {
+				return types.ChatCompletionChunk{}, failoverErr
+			}
+			continue
+		}
+
+		s.buffered = append(s.buffered, s.tag(chunk))
+		if err != nil || chunk.Done || len(s.buffered) > s.commitAfter {
+			s.commit(err)
+		}
+	}
+}
+
+// commit releases the buffered chunks; err, if any, is returned with the last of them
+func (s *failoverStream) commit(err error) {
+	s.committed = true
+	s.pending = s.buffered
+	s.pendingErr = err
+	s.buffered = nil
+}
+
+// failover discards the uncommitted output of the failed stream and switches to the
+// next provider that starts successfully. It returns an error when none is left.
+func (s *failoverStream) failover(streamErr error) error {
+	_ = s.inner.Close()
+	s.buffered = nil
+
+	if s.index+1 >= len(s.providers) {
+		return fmt.Errorf("stream from %s failed and no providers remain: %w", s.providerName, streamErr)
+	}
+
+	stream, index, err := s.provider.openStream(s.ctx, s.opts, s.providers, s.index+1)
+	if err != nil {
+		return fmt.Errorf("stream from %s failed: %v; %w", s.providerName, streamErr, err)
+	}
+
+	s.inner = stream
+	s.index = index
+	s.providerName = s.providers[index].Name()
+	s.usageRecorded = false
+	return nil
+}
+
+func (s *failoverStream) tag(chunk types.ChatCompletionChunk) types.ChatCompletionChunk {
+	if !s.usageRecorded && chunk.Usage.PromptTokens > 0 {
+		s.provider.cacheHits.Record(s.providerName, chunk.Usage)
+		s.usageRecorded = true
+	}
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]interface{})
+	}
+	chunk.Metadata["fallback_provider"] = s.providerName
+	chunk.Metadata["fallback_index"] = s.index
+	return chunk
+}
+
+func (s *failoverStream) Close() error {
+	return s.inner.Close()
+}
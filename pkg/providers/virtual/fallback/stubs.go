@@ -55,6 +55,21 @@ func (f *FallbackProvider) Configure(config types.ProviderConfig) error {
 		if providers, ok := config.ProviderConfig["providers"].([]string); ok {
 			f.config.ProviderNames = providers
 		}
+		if streamFailover, ok := config.ProviderConfig["stream_failover"].(bool); ok {
+			f.config.StreamFailover = streamFailover
+		}
+		switch commitAfter := config.ProviderConfig["commit_after_chunks"].(type) {
+		case int:
+			f.config.CommitAfterChunks = commitAfter
+		case float64: // Numbers decoded from JSON
+			f.config.CommitAfterChunks = int(commitAfter)
+		}
+		if preferWarmCache, ok := config.ProviderConfig["prefer_warm_cache"].(bool); ok {
+			f.config.PreferWarmCache = preferWarmCache
+		}
+		if threshold, ok := config.ProviderConfig["cache_warm_threshold"].(float64); ok {
+			f.config.CacheWarmThreshold = threshold
+		}
 	}
 	return nil
 }
@@ -64,8 +79,12 @@ func (f *FallbackProvider) GetConfig() types.ProviderConfig {
 		Type: "fallback",
 		Name: f.name,
 		ProviderConfig: map[string]interface{}{
-			"max_retries": f.config.MaxRetries,
-			"providers":   f.config.ProviderNames,
+			"max_retries":          f.config.MaxRetries,
+			"providers":            f.config.ProviderNames,
+			"stream_failover":      f.config.StreamFailover,
+			"commit_after_chunks":  f.config.CommitAfterChunks,
+			"prefer_warm_cache":    f.config.PreferWarmCache,
+			"cache_warm_threshold": f.config.CacheWarmThreshold,
 		},
 	}
 }
@@ -103,14 +122,46 @@ func (f *FallbackProvider) SetMetricsCollector(collector types.MetricsCollector)
 	f.metricsCollector = collector
 }
 
+// GetMetricsCollector returns the metrics collector set for this provider, or nil
+func (f *FallbackProvider) GetMetricsCollector() types.MetricsCollector {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.metricsCollector
+}
+
 func (f *FallbackProvider) GetMetrics() types.ProviderMetrics {
+	return f.aggregateMetrics(func(provider types.Provider) types.ProviderMetrics {
+		return provider.GetMetrics()
+	})
+}
+
+// ResetMetrics resets the metrics of the child providers that support it
+func (f *FallbackProvider) ResetMetrics() {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, provider := range f.providers {
+		if resetter, ok := provider.(types.MetricsResetter); ok {
+			resetter.ResetMetrics()
+		}
+	}
+}
+
+// SnapshotMetrics aggregates a snapshot of each child provider's metrics, resetting
+// the children that support it
+func (f *FallbackProvider) SnapshotMetrics() types.ProviderMetrics {
+	return f.aggregateMetrics(types.SnapshotProviderMetrics)
+}
+
+// aggregateMetrics combines the metrics of the child providers, read with childMetricsOf
+func (f *FallbackProvider) aggregateMetrics(childMetricsOf func(types.Provider) types.ProviderMetrics) types.ProviderMetrics {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
 	// Aggregate metrics from child providers
 	var metrics types.ProviderMetrics
 	for _, provider := range f.providers {
-		childMetrics := provider.GetMetrics()
+		childMetrics := childMetricsOf(provider)
 		metrics.RequestCount += childMetrics.RequestCount
 		metrics.SuccessCount += childMetrics.SuccessCount
 		metrics.ErrorCount += childMetrics.ErrorCount
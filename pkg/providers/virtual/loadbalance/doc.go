@@ -1,4 +1,5 @@
 // Package loadbalance provides a virtual provider that distributes requests across
 // multiple providers. It supports multiple strategies including round-robin, random,
-// and weighted distribution for load balancing and resource optimization.
+// and weighted distribution for load balancing and resource optimization, and cache
+// affinity, which keeps requests on the provider whose prompt cache is warm.
 package loadbalance
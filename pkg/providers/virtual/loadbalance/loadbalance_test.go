@@ -6,6 +6,7 @@ import (
 	"io"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
 )
@@ -1034,3 +1035,115 @@ func BenchmarkRandomSelection(b *testing.B) {
 		_ = stream.Close()
 	}
 }
+
+// rateLimitedProvider is a mockChatProvider reporting a rate-limit status
+type rateLimitedProvider struct {
+	*mockChatProvider
+	status types.RateLimitStatus
+}
+
+func (p *rateLimitedProvider) RateLimitStatus() types.RateLimitStatus { return p.status }
+
+func TestLoadBalanceProvider_SkipsRateLimitedProviders(t *testing.T) {
+	exhausted := types.RateLimitStatus{Known: true, RetryAt: time.Now().Add(time.Minute)}
+
+	lb := NewLoadBalanceProvider("lb", &Config{Strategy: StrategyRoundRobin})
+	lb.SetProviders([]types.Provider{
+		&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "limited", response: "a"}, status: exhausted},
+		&mockChatProvider{name: "available", response: "b"},
+	})
+
+	for i := 0; i < 4; i++ {
+		if got := lb.selectProvider().Name(); got != "available" {
+			t.Fatalf("request %d: expected the rate-limited provider to be skipped, got %s", i, got)
+		}
+	}
+
+	// With every provider limited, requests still go out
+	lb.SetProviders([]types.Provider{
+		&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "limited-1"}, status: exhausted},
+		&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "limited-2"}, status: exhausted},
+	})
+	if lb.selectProvider() == nil {
+		t.Fatal("expected a provider when all are rate limited")
+	}
+}
+
+func TestLoadBalanceProvider_TargetProvider(t *testing.T) {
+	lb := NewLoadBalanceProvider("test", &Config{Strategy: StrategyRoundRobin})
+	lb.SetProviders([]types.Provider{
+		&mockChatProvider{name: "provider1", response: "1"},
+		&mockChatProvider{name: "provider2", response: "2"},
+	})
+
+	opts := types.GenerateOptions{Prompt: "test", TargetProvider: "provider2"}
+	for i := 0; i < 3; i++ {
+		stream, err := lb.GenerateChatCompletion(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error on iteration %d: %v", i, err)
+		}
+		chunk, _ := stream.Next()
+		_ = stream.Close()
+		if chunk.Content != "2" {
+			t.Errorf("expected the pinned provider2 on iteration %d, got %q", i, chunk.Content)
+		}
+	}
+
+	opts.TargetProvider = "provider3"
+	if _, err := lb.GenerateChatCompletion(context.Background(), opts); !types.IsValidationError(err) {
+		t.Errorf("expected a validation error for an unknown target, got %v", err)
+	}
+}
+
+func TestLoadBalanceProvider_CacheAffinity(t *testing.T) {
+	lb := NewLoadBalanceProvider("lb", &Config{Strategy: StrategyCacheAffinity})
+	lb.SetProviders([]types.Provider{
+		&mockChatProvider{name: "cold", response: "a"},
+		&mockChatProvider{name: "warm", response: "b"},
+	})
+
+	// With no warm cache, requests are round robined
+	if first, second := lb.selectProvider().Name(), lb.selectProvider().Name(); first == second {
+		t.Errorf("expected round robin while no cache is warm, got %s twice", first)
+	}
+
+	lb.cacheHits.Record("warm", types.Usage{PromptTokens: 1000, CacheReadTokens: 800})
+	lb.cacheHits.Record("cold", types.Usage{PromptTokens: 1000, CacheReadTokens: 100})
+	for i := 0; i < 3; i++ {
+		if got := lb.selectProvider().Name(); got != "warm" {
+			t.Fatalf("request %d: expected the cache-warm provider, got %s", i, got)
+		}
+	}
+
+	// A hit rate below the threshold does not count as warm
+	lb.config.CacheWarmThreshold = 0.9
+	if first, second := lb.selectProvider().Name(), lb.selectProvider().Name(); first == second {
+		t.Errorf("expected round robin while no cache is above the threshold, got %s twice", first)
+	}
+	lb.config.CacheWarmThreshold = 0
+
+	// Load is spread over all the warm providers
+	lb.SetProviders([]types.Provider{
+		&mockChatProvider{name: "cold", response: "a"},
+		&mockChatProvider{name: "warm", response: "b"},
+		&mockChatProvider{name: "warm2", response: "c"},
+	})
+	lb.cacheHits.Record("warm2", types.Usage{PromptTokens: 1000, CacheReadTokens: 600})
+	counts := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		counts[lb.selectProvider().Name()]++
+	}
+	if counts["warm"] != 2 || counts["warm2"] != 2 || counts["cold"] != 0 {
+		t.Errorf("expected requests spread evenly over the warm providers, got %v", counts)
+	}
+
+	// A rate-limited warm provider is passed over
+	exhausted := types.RateLimitStatus{Known: true, RetryAt: time.Now().Add(time.Minute)}
+	lb.SetProviders([]types.Provider{
+		&mockChatProvider{name: "cold", response: "a"},
+		&rateLimitedProvider{mockChatProvider: &mockChatProvider{name: "warm", response: "b"}, status: exhausted},
+	})
+	if got := lb.selectProvider().Name(); got != "cold" {
+		t.Errorf("expected the rate-limited warm provider to be skipped, got %s", got)
+	}
+}
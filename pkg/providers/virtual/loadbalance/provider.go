@@ -17,29 +17,49 @@ type LoadBalanceProvider struct {
 	config           *Config
 	counter          uint64
 	metricsCollector types.MetricsCollector
+	cacheHits        *types.CacheHitTracker
 	mu               sync.RWMutex
 }
 
 type Config struct {
 	Strategy      Strategy `yaml:"strategy"`
 	ProviderNames []string `yaml:"providers"`
+
+	// CacheWarmThreshold is the recent cache hit rate at which a provider counts as
+	// warm under StrategyCacheAffinity (0.5 when unset)
+	CacheWarmThreshold float64 `yaml:"cache_warm_threshold"`
 }
 
+// defaultCacheWarmThreshold is the cache hit rate at which a provider counts as warm
+const defaultCacheWarmThreshold = 0.5
+
 type Strategy string
 
 const (
 	StrategyRoundRobin Strategy = "round_robin"
 	StrategyRandom     Strategy = "random"
 	StrategyWeighted   Strategy = "weighted"
+
+	// StrategyCacheAffinity round robins requests over the providers whose recent
+	// prompt cache hit rate is at least Config.CacheWarmThreshold, so conversations
+	// stay where their prompt is cached, and over all providers while none is warm
+	StrategyCacheAffinity Strategy = "cache_affinity"
 )
 
 func NewLoadBalanceProvider(name string, config *Config) *LoadBalanceProvider {
 	return &LoadBalanceProvider{
-		name:   name,
-		config: config,
+		name:      name,
+		config:    config,
+		cacheHits: types.NewCacheHitTracker(0),
 	}
 }
 
+// CacheHitRate returns the recent prompt cache hit rate of the named provider, from 0
+// to 1, as seen in the responses it served through this load balancer
+func (lb *LoadBalanceProvider) CacheHitRate(providerName string) float64 {
+	return lb.cacheHits.HitRate(providerName)
+}
+
 func (lb *LoadBalanceProvider) SetProviders(providers []types.Provider) {
 	lb.providers = providers
 }
@@ -58,6 +78,16 @@ func (lb *LoadBalanceProvider) GenerateChatCompletion(ctx context.Context, opts
 		return nil, fmt.Errorf("no providers configured")
 	}
 
+	// A pinned request bypasses the strategy
+	var target types.Provider
+	if opts.TargetProvider != "" {
+		var err error
+		if target, err = types.FindTargetProvider(providers, opts.TargetProvider); err != nil {
+			return nil, err
+		}
+		opts.TargetProvider = ""
+	}
+
 	// Record request
 	if collector != nil {
 		_ = collector.RecordEvent(ctx, types.MetricEvent{
@@ -69,7 +99,10 @@ func (lb *LoadBalanceProvider) GenerateChatCompletion(ctx context.Context, opts
 		})
 	}
 
-	provider := lb.selectProvider()
+	provider := target
+	if provider == nil {
+		provider = lb.selectProvider()
+	}
 
 	chatProvider, ok := provider.(types.ChatProvider)
 	if !ok {
@@ -126,16 +159,23 @@ func (lb *LoadBalanceProvider) GenerateChatCompletion(ctx context.Context, opts
 	return &loadBalanceStream{
 		inner:        stream,
 		providerName: provider.Name(),
+		cacheHits:    lb.cacheHits,
 	}, nil
 }
 
 type loadBalanceStream struct {
-	inner        types.ChatCompletionStream
-	providerName string
+	inner         types.ChatCompletionStream
+	providerName  string
+	cacheHits     *types.CacheHitTracker
+	usageRecorded bool
 }
 
 func (s *loadBalanceStream) Next() (types.ChatCompletionChunk, error) {
 	chunk, err := s.inner.Next()
+	if !s.usageRecorded && chunk.Usage.PromptTokens > 0 {
+		s.cacheHits.Record(s.providerName, chunk.Usage)
+		s.usageRecorded = true
+	}
 	if chunk.Metadata == nil {
 		chunk.Metadata = make(map[string]interface{})
 	}
@@ -147,7 +187,31 @@ func (s *loadBalanceStream) Close() error {
 	return s.inner.Close()
 }
 
+// selectProvider picks the next provider by strategy, passing over providers whose
+// reported rate limits are exhausted unless all of them are
 func (lb *LoadBalanceProvider) selectProvider() types.Provider {
+	now := time.Now()
+	if lb.config.Strategy == StrategyCacheAffinity {
+		if warm := lb.warmProviders(now); len(warm) > 0 {
+			idx := atomic.AddUint64(&lb.counter, 1) - 1
+			return warm[idx%uint64(len(warm))]
+		}
+	}
+
+	first := lb.nextProvider()
+	provider := first
+	for i := 0; i < len(lb.providers); i++ {
+		if i > 0 {
+			provider = lb.nextProvider()
+		}
+		if !types.ProviderRateLimitStatus(provider).Limited(now) {
+			return provider
+		}
+	}
+	return first
+}
+
+func (lb *LoadBalanceProvider) nextProvider() types.Provider {
 	switch lb.config.Strategy {
 	case StrategyRandom:
 		return lb.providers[randomInt(len(lb.providers))]
@@ -157,6 +221,23 @@ func (lb *LoadBalanceProvider) selectProvider() types.Provider {
 	}
 }
 
+// warmProviders returns the providers whose cache is warm and whose rate limits are
+// not exhausted
+func (lb *LoadBalanceProvider) warmProviders(now time.Time) []types.Provider {
+	threshold := lb.config.CacheWarmThreshold
+	if threshold <= 0 {
+		threshold = defaultCacheWarmThreshold
+	}
+
+	var warm []types.Provider
+	for _, provider := range lb.providers {
+		if lb.cacheHits.HitRate(provider.Name()) >= threshold && !types.ProviderRateLimitStatus(provider).Limited(now) {
+			warm = append(warm, provider)
+		}
+	}
+	return warm
+}
+
 func randomInt(max int) int {
 	return int(time.Now().UnixNano() % int64(max))
 }
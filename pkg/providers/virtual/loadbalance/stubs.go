@@ -55,6 +55,9 @@ func (lb *LoadBalanceProvider) Configure(config types.ProviderConfig) error {
 		if providers, ok := config.ProviderConfig["providers"].([]string); ok {
 			lb.config.ProviderNames = providers
 		}
+		if threshold, ok := config.ProviderConfig["cache_warm_threshold"].(float64); ok {
+			lb.config.CacheWarmThreshold = threshold
+		}
 	}
 	return nil
 }
@@ -64,8 +67,9 @@ func (lb *LoadBalanceProvider) GetConfig() types.ProviderConfig {
 		Type: "loadbalance",
 		Name: lb.name,
 		ProviderConfig: map[string]interface{}{
-			"strategy":  string(lb.config.Strategy),
-			"providers": lb.config.ProviderNames,
+			"strategy":             string(lb.config.Strategy),
+			"providers":            lb.config.ProviderNames,
+			"cache_warm_threshold": lb.config.CacheWarmThreshold,
 		},
 	}
 }
@@ -103,14 +107,46 @@ func (lb *LoadBalanceProvider) SetMetricsCollector(collector types.MetricsCollec
 	lb.metricsCollector = collector
 }
 
+// GetMetricsCollector returns the metrics collector set for this provider, or nil
+func (lb *LoadBalanceProvider) GetMetricsCollector() types.MetricsCollector {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return lb.metricsCollector
+}
+
 func (lb *LoadBalanceProvider) GetMetrics() types.ProviderMetrics {
+	return lb.aggregateMetrics(func(provider types.Provider) types.ProviderMetrics {
+		return provider.GetMetrics()
+	})
+}
+
+// ResetMetrics resets the metrics of the child providers that support it
+func (lb *LoadBalanceProvider) ResetMetrics() {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	for _, provider := range lb.providers {
+		if resetter, ok := provider.(types.MetricsResetter); ok {
+			resetter.ResetMetrics()
+		}
+	}
+}
+
+// SnapshotMetrics aggregates a snapshot of each child provider's metrics, resetting
+// the children that support it
+func (lb *LoadBalanceProvider) SnapshotMetrics() types.ProviderMetrics {
+	return lb.aggregateMetrics(types.SnapshotProviderMetrics)
+}
+
+// aggregateMetrics combines the metrics of the child providers, read with childMetricsOf
+func (lb *LoadBalanceProvider) aggregateMetrics(childMetricsOf func(types.Provider) types.ProviderMetrics) types.ProviderMetrics {
 	lb.mu.RLock()
 	defer lb.mu.RUnlock()
 
 	// Aggregate metrics from child providers
 	var metrics types.ProviderMetrics
 	for _, provider := range lb.providers {
-		childMetrics := provider.GetMetrics()
+		childMetrics := childMetricsOf(provider)
 		metrics.RequestCount += childMetrics.RequestCount
 		metrics.SuccessCount += childMetrics.SuccessCount
 		metrics.ErrorCount += childMetrics.ErrorCount
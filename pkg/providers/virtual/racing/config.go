@@ -16,6 +16,13 @@ type Config struct {
 	VirtualModels       map[string]VirtualModelConfig `yaml:"virtual_models"`
 	DefaultVirtualModel string                        `yaml:"default_virtual_model"`
 	PerformanceFile     string                        `yaml:"performance_file,omitempty"`
+
+	// MaxRacers limits each race to the providers with the lowest recent latency (p95,
+	// from their metrics). 0 races all providers. All providers are raced while some
+	// have no latency data yet, and whenever one of the fastest is slower than
+	// SlowThresholdMS.
+	MaxRacers       int `yaml:"max_racers,omitempty"`
+	SlowThresholdMS int `yaml:"slow_threshold_ms,omitempty"`
 }
 
 // VirtualModelConfig represents configuration for a single virtual model
@@ -170,6 +177,14 @@ func (c *Config) Validate() error {
 		return &ConfigError{Field: "grace_period_ms", Message: "must be non-negative"}
 	}
 
+	if c.MaxRacers < 0 {
+		return &ConfigError{Field: "max_racers", Message: "must be non-negative"}
+	}
+
+	if c.SlowThresholdMS < 0 {
+		return &ConfigError{Field: "slow_threshold_ms", Message: "must be non-negative"}
+	}
+
 	if c.DefaultVirtualModel == "" {
 		return &ConfigError{Field: "default_virtual_model", Message: "cannot be empty"}
 	}
@@ -1,5 +1,6 @@
 // Package racing provides a virtual provider that races multiple providers concurrently.
 // It returns the first successful response, with support for weighted and quality-based
 // selection strategies, along with performance tracking to optimize provider selection
-// over time.
+// over time. With Config.MaxRacers set, each race is limited to the providers with the
+// lowest recent latency; GetRankings exposes the current ranking.
 package racing
@@ -92,6 +92,19 @@ func (r *RacingProvider) GenerateChatCompletion(ctx context.Context, opts types.
 		raceProviders = providers
 	}
 
+	if opts.TargetProvider != "" {
+		// A pinned request goes to its target alone
+		target, err := types.FindTargetProvider(raceProviders, opts.TargetProvider)
+		if err != nil {
+			return nil, err
+		}
+		raceProviders = []types.Provider{target}
+		opts.TargetProvider = ""
+	} else {
+		// Race only the fastest providers when MaxRacers is set
+		raceProviders = r.selectRacers(raceProviders)
+	}
+
 	// Record race request
 	metadata := map[string]interface{}{}
 	if virtualModelConfig != nil {
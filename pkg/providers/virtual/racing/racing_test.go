@@ -2928,3 +2928,33 @@ func TestBackwardCompatibility_MixedUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestRacingProvider_TargetProvider(t *testing.T) {
+	rp := NewRacingProvider("test", &Config{
+		TimeoutMS: 5000,
+		Strategy:  StrategyFirstWins,
+	})
+	rp.SetProviders([]types.Provider{
+		&mockChatProvider{name: "slow-provider", delay: 50 * time.Millisecond, response: "slow response"},
+		&mockChatProvider{name: "fast-provider", delay: time.Millisecond, response: "fast response"},
+	})
+
+	stream, err := rp.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "test", TargetProvider: "slow-provider"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	chunk, err := stream.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chunk.Metadata["racing_winner"] != "slow-provider" {
+		t.Errorf("expected the pinned slow-provider to answer, got %v", chunk.Metadata["racing_winner"])
+	}
+
+	_, err = rp.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "test", TargetProvider: "other-provider"})
+	if !types.IsValidationError(err) {
+		t.Errorf("expected a validation error for an unknown target, got %v", err)
+	}
+}
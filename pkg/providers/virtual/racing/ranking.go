@@ -0,0 +1,113 @@
+package racing
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ProviderRanking is a provider's position in the latency ranking used to choose
+// which providers to race when Config.MaxRacers is set
+type ProviderRanking struct {
+	Provider string `json:"provider"`
+
+	// Rank is the provider's position by recent latency, 1 being the fastest. It is
+	// 0 for providers without latency data yet.
+	Rank int `json:"rank"`
+
+	P50Latency  time.Duration `json:"p50_latency"`
+	P95Latency  time.Duration `json:"p95_latency"`
+	EWMALatency time.Duration `json:"ewma_latency"`
+
+	// index is the provider's position in the ranked slice, since names may repeat
+	index int
+}
+
+// HasData reports whether the provider has served requests to rank it by
+func (pr ProviderRanking) HasData() bool {
+	return pr.Rank > 0
+}
+
+// latency is the value providers are ranked by: p95, or the EWMA when no
+// percentiles are available
+func (pr ProviderRanking) latency() time.Duration {
+	if pr.P95Latency > 0 {
+		return pr.P95Latency
+	}
+	return pr.EWMALatency
+}
+
+// GetRankings returns the current latency ranking of the racing providers, fastest
+// first, followed by the providers without latency data
+func (r *RacingProvider) GetRankings() []ProviderRanking {
+	r.mu.RLock()
+	providers := r.providers
+	r.mu.RUnlock()
+
+	return rankProviders(providers)
+}
+
+// rankProviders ranks providers by the recent latency in their metrics
+func rankProviders(providers []types.Provider) []ProviderRanking {
+	rankings := make([]ProviderRanking, len(providers))
+	for i, provider := range providers {
+		metrics := provider.GetMetrics()
+		rankings[i] = ProviderRanking{
+			Provider:    provider.Name(),
+			P50Latency:  metrics.P50Latency,
+			P95Latency:  metrics.P95Latency,
+			EWMALatency: metrics.EWMALatency,
+			index:       i,
+		}
+	}
+
+	sort.SliceStable(rankings, func(i, j int) bool {
+		li, lj := rankings[i].latency(), rankings[j].latency()
+		if li == 0 || lj == 0 {
+			return lj == 0 && li != 0
+		}
+		if li != lj {
+			return li < lj
+		}
+		return rankings[i].EWMALatency < rankings[j].EWMALatency
+	})
+
+	for i := range rankings {
+		if rankings[i].latency() > 0 {
+			rankings[i].Rank = i + 1
+		}
+	}
+	return rankings
+}
+
+// selectRacers returns the providers to race. With Config.MaxRacers set, only the
+// fastest MaxRacers providers are raced, unless some provider has no latency data yet
+// (so every provider gets measured) or one of the fastest is slower than
+// Config.SlowThresholdMS at p95, in which case all providers are raced.
+func (r *RacingProvider) selectRacers(providers []types.Provider) []types.Provider {
+	r.mu.RLock()
+	maxRacers := r.config.MaxRacers
+	slowThreshold := time.Duration(r.config.SlowThresholdMS) * time.Millisecond
+	r.mu.RUnlock()
+
+	if maxRacers <= 0 || maxRacers >= len(providers) {
+		return providers
+	}
+
+	rankings := rankProviders(providers)
+	for i, ranking := range rankings {
+		if !ranking.HasData() {
+			return providers
+		}
+		if i < maxRacers && slowThreshold > 0 && ranking.latency() > slowThreshold {
+			return providers
+		}
+	}
+
+	racers := make([]types.Provider, 0, maxRacers)
+	for _, ranking := range rankings[:maxRacers] {
+		racers = append(racers, providers[ranking.index])
+	}
+	return racers
+}
@@ -0,0 +1,173 @@
+package racing
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// rankedMockProvider reports fixed latency metrics and counts the races it joins
+type rankedMockProvider struct {
+	mockChatProvider
+	p95   time.Duration
+	ewma  time.Duration
+	calls atomic.Int32
+}
+
+func (m *rankedMockProvider) GenerateChatCompletion(ctx context.Context, opts types.GenerateOptions) (types.ChatCompletionStream, error) {
+	m.calls.Add(1)
+	return m.mockChatProvider.GenerateChatCompletion(ctx, opts)
+}
+
+func (m *rankedMockProvider) GetMetrics() types.ProviderMetrics {
+	return types.ProviderMetrics{P50Latency: m.p95 / 2, P95Latency: m.p95, EWMALatency: m.ewma}
+}
+
+func newRankedMock(name string, p95 time.Duration) *rankedMockProvider {
+	return &rankedMockProvider{
+		mockChatProvider: mockChatProvider{name: name, delay: time.Millisecond, response: name},
+		p95:              p95,
+		ewma:             p95 / 2,
+	}
+}
+
+func TestRacingProvider_GetRankings(t *testing.T) {
+	rp := NewRacingProvider("test", &Config{TimeoutMS: 1000})
+	rp.SetProviders([]types.Provider{
+		newRankedMock("slow", 3*time.Second),
+		newRankedMock("new", 0),
+		newRankedMock("fast", 200*time.Millisecond),
+		newRankedMock("medium", time.Second),
+	})
+
+	rankings := rp.GetRankings()
+
+	expected := []struct {
+		provider string
+		rank     int
+	}{
+		{"fast", 1},
+		{"medium", 2},
+		{"slow", 3},
+		{"new", 0},
+	}
+	if len(rankings) != len(expected) {
+		t.Fatalf("expected %d rankings, got %d", len(expected), len(rankings))
+	}
+	for i, want := range expected {
+		if rankings[i].Provider != want.provider || rankings[i].Rank != want.rank {
+			t.Errorf("ranking %d: expected %s at rank %d, got %s at rank %d",
+				i, want.provider, want.rank, rankings[i].Provider, rankings[i].Rank)
+		}
+	}
+	if rankings[0].P95Latency != 200*time.Millisecond || rankings[0].EWMALatency != 100*time.Millisecond {
+		t.Errorf("expected latency metrics in ranking, got %+v", rankings[0])
+	}
+	if rankings[3].HasData() {
+		t.Error("expected provider without metrics to have no data")
+	}
+}
+
+func TestRacingProvider_MaxRacers(t *testing.T) {
+	race := func(t *testing.T, config *Config, providers ...*rankedMockProvider) {
+		t.Helper()
+		rp := NewRacingProvider("test", config)
+		all := make([]types.Provider, len(providers))
+		for i, p := range providers {
+			all[i] = p
+		}
+		rp.SetProviders(all)
+
+		stream, err := rp.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "test"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = stream.Close()
+	}
+
+	t.Run("races only the fastest providers", func(t *testing.T) {
+		slow := newRankedMock("slow", 3*time.Second)
+		fast := newRankedMock("fast", 200*time.Millisecond)
+		medium := newRankedMock("medium", time.Second)
+
+		race(t, &Config{TimeoutMS: 1000, MaxRacers: 2, SlowThresholdMS: 2000}, slow, fast, medium)
+
+		if fast.calls.Load() != 1 || medium.calls.Load() != 1 {
+			t.Errorf("expected the two fastest providers to race, got fast=%d medium=%d", fast.calls.Load(), medium.calls.Load())
+		}
+		if slow.calls.Load() != 0 {
+			t.Errorf("expected the slowest provider to sit out, got %d calls", slow.calls.Load())
+		}
+	})
+
+	t.Run("tells apart providers with the same name", func(t *testing.T) {
+		fast := newRankedMock("openai", 200*time.Millisecond)
+		medium := newRankedMock("medium", time.Second)
+		slow := newRankedMock("openai", 3*time.Second)
+
+		race(t, &Config{TimeoutMS: 1000, MaxRacers: 2}, fast, medium, slow)
+
+		if fast.calls.Load() != 1 || slow.calls.Load() != 0 {
+			t.Errorf("expected the faster of the same-named providers to race, got fast=%d slow=%d", fast.calls.Load(), slow.calls.Load())
+		}
+	})
+
+	t.Run("races all when the fastest are slow", func(t *testing.T) {
+		slow := newRankedMock("slow", 5*time.Second)
+		fast := newRankedMock("fast", 3*time.Second)
+		medium := newRankedMock("medium", 4*time.Second)
+
+		race(t, &Config{TimeoutMS: 1000, MaxRacers: 2, SlowThresholdMS: 2000}, slow, fast, medium)
+
+		if slow.calls.Load() != 1 || fast.calls.Load() != 1 || medium.calls.Load() != 1 {
+			t.Errorf("expected all providers to race, got slow=%d fast=%d medium=%d", slow.calls.Load(), fast.calls.Load(), medium.calls.Load())
+		}
+	})
+
+	t.Run("races all until every provider has latency data", func(t *testing.T) {
+		fresh := newRankedMock("fresh", 0)
+		fast := newRankedMock("fast", 200*time.Millisecond)
+		medium := newRankedMock("medium", time.Second)
+
+		race(t, &Config{TimeoutMS: 1000, MaxRacers: 2}, fresh, fast, medium)
+
+		if fresh.calls.Load() != 1 {
+			t.Errorf("expected provider without latency data to race, got %d calls", fresh.calls.Load())
+		}
+	})
+
+	t.Run("races all by default", func(t *testing.T) {
+		slow := newRankedMock("slow", 3*time.Second)
+		fast := newRankedMock("fast", 200*time.Millisecond)
+
+		race(t, &Config{TimeoutMS: 1000}, slow, fast)
+
+		if slow.calls.Load() != 1 || fast.calls.Load() != 1 {
+			t.Errorf("expected all providers to race, got slow=%d fast=%d", slow.calls.Load(), fast.calls.Load())
+		}
+	})
+}
+
+func TestConfig_Validate_MaxRacers(t *testing.T) {
+	config := DefaultConfig()
+	config.VirtualModels["default"] = VirtualModelConfig{Providers: []ProviderReference{{Name: "a"}}}
+
+	config.MaxRacers = -1
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for negative max_racers")
+	}
+
+	config.MaxRacers = 2
+	config.SlowThresholdMS = -1
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for negative slow_threshold_ms")
+	}
+
+	config.SlowThresholdMS = 2000
+	if err := config.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
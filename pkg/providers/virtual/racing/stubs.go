@@ -62,6 +62,8 @@ func (r *RacingProvider) Configure(config types.ProviderConfig) error {
 		if performanceFile, ok := config.ProviderConfig["performance_file"].(string); ok {
 			r.config.PerformanceFile = performanceFile
 		}
+		r.config.MaxRacers = getIntOrDefault(config.ProviderConfig, "max_racers", r.config.MaxRacers)
+		r.config.SlowThresholdMS = getIntOrDefault(config.ProviderConfig, "slow_threshold_ms", r.config.SlowThresholdMS)
 
 		// Handle virtual models configuration
 		if virtualModels, ok := config.ProviderConfig["virtual_models"].(map[string]interface{}); ok {
@@ -132,6 +134,11 @@ func (r *RacingProvider) GetConfig() types.ProviderConfig {
 		providerConfig["performance_file"] = r.config.PerformanceFile
 	}
 
+	if r.config.MaxRacers > 0 {
+		providerConfig["max_racers"] = r.config.MaxRacers
+		providerConfig["slow_threshold_ms"] = r.config.SlowThresholdMS
+	}
+
 	return types.ProviderConfig{
 		Type:           "racing",
 		Name:           r.name,
@@ -176,18 +183,58 @@ func (r *RacingProvider) SetMetricsCollector(collector types.MetricsCollector) {
 	r.metricsCollector = collector
 }
 
+// GetMetricsCollector returns the metrics collector set for this provider, or nil
+func (r *RacingProvider) GetMetricsCollector() types.MetricsCollector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.metricsCollector
+}
+
 func (r *RacingProvider) GetMetrics() types.ProviderMetrics {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	return r.aggregateMetrics(r.requestCount, func(provider types.Provider) types.ProviderMetrics {
+		return provider.GetMetrics()
+	})
+}
+
+// ResetMetrics resets the racing request count and the metrics of the child providers
+// that support it
+func (r *RacingProvider) ResetMetrics() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requestCount = 0
+	for _, provider := range r.providers {
+		if resetter, ok := provider.(types.MetricsResetter); ok {
+			resetter.ResetMetrics()
+		}
+	}
+}
+
+// SnapshotMetrics aggregates a snapshot of the racing request count and each child
+// provider's metrics, resetting them
+func (r *RacingProvider) SnapshotMetrics() types.ProviderMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requestCount := r.requestCount
+	r.requestCount = 0
+	return r.aggregateMetrics(requestCount, types.SnapshotProviderMetrics)
+}
+
+// aggregateMetrics combines requestCount with the metrics of the child providers,
+// read with childMetricsOf. The caller holds r.mu.
+func (r *RacingProvider) aggregateMetrics(requestCount int64, childMetricsOf func(types.Provider) types.ProviderMetrics) types.ProviderMetrics {
 	// Start with racing provider's own request count
 	var metrics types.ProviderMetrics
-	metrics.RequestCount = r.requestCount
+	metrics.RequestCount = requestCount
 	metrics.LastRequestTime = time.Now()
 
 	// Aggregate metrics from child providers
 	for _, provider := range r.providers {
-		childMetrics := provider.GetMetrics()
+		childMetrics := childMetricsOf(provider)
 		metrics.RequestCount += childMetrics.RequestCount
 		metrics.SuccessCount += childMetrics.SuccessCount
 		metrics.ErrorCount += childMetrics.ErrorCount
@@ -0,0 +1,105 @@
+package xai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Live search modes
+const (
+	SearchModeAuto = "auto" // The model decides whether to search
+	SearchModeOn   = "on"
+	SearchModeOff  = "off"
+)
+
+// XAIOptions configures xAI-specific request features.
+// Pass it through GenerateOptions.ProviderOptions under the "xai" key:
+//
+//	options := types.GenerateOptions{
+//	    Prompt: "What happened in AI this week?",
+//	    ProviderOptions: map[string]interface{}{
+//	        "xai": xai.XAIOptions{
+//	            SearchParameters: &xai.XAISearchParameters{
+//	                Mode:            xai.SearchModeAuto,
+//	                ReturnCitations: xai.Bool(true),
+//	            },
+//	        },
+//	    },
+//	}
+type XAIOptions struct {
+	// SearchParameters enables live search of the web, X and news sources
+	SearchParameters *XAISearchParameters `json:"search_parameters,omitempty"`
+}
+
+// XAISearchParameters configures live search. Sources searched are billed per source
+// used, on top of tokens.
+type XAISearchParameters struct {
+	// Mode is one of the SearchMode constants; the API defaults to "auto"
+	Mode string `json:"mode,omitempty"`
+
+	// Sources restricts the sources searched, each with a "type" of "web", "x",
+	// "news" or "rss" and its type's filters
+	Sources []map[string]interface{} `json:"sources,omitempty"`
+
+	// ReturnCitations returns the URLs of the sources used (default true)
+	ReturnCitations *bool `json:"return_citations,omitempty"`
+
+	// MaxSearchResults caps the sources used
+	MaxSearchResults int `json:"max_search_results,omitempty"`
+
+	// FromDate and ToDate restrict results to a date range, as YYYY-MM-DD
+	FromDate string `json:"from_date,omitempty"`
+	ToDate   string `json:"to_date,omitempty"`
+}
+
+// Bool returns a pointer to b, for optional fields
+func Bool(b bool) *bool {
+	return &b
+}
+
+// validate rejects search parameters the API would reject
+func (s *XAISearchParameters) validate() error {
+	switch s.Mode {
+	case "", SearchModeAuto, SearchModeOn, SearchModeOff:
+	default:
+		return fmt.Errorf("invalid xai search mode %q", s.Mode)
+	}
+	for i, source := range s.Sources {
+		if _, ok := source["type"].(string); !ok {
+			return fmt.Errorf("xai search source %d requires a type", i)
+		}
+	}
+	if s.MaxSearchResults < 0 {
+		return fmt.Errorf("xai max_search_results must not be negative")
+	}
+	return nil
+}
+
+// xaiOptionsFrom extracts XAIOptions from GenerateOptions.ProviderOptions.
+// Accepts the struct, a pointer to it, or a generic map (e.g. decoded from JSON config).
+func xaiOptionsFrom(options types.GenerateOptions) (*XAIOptions, error) {
+	raw, ok := options.ProviderOptions[string(types.ProviderTypexAI)]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case XAIOptions:
+		return &v, nil
+	case *XAIOptions:
+		return v, nil
+	default:
+		// Round-trip through JSON to support map[string]interface{} and similar
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xai provider options: %w", err)
+		}
+		var opts XAIOptions
+		if err := json.Unmarshal(data, &opts); err != nil {
+			return nil, fmt.Errorf("invalid xai provider options: %w", err)
+		}
+		return &opts, nil
+	}
+}
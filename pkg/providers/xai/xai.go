@@ -0,0 +1,106 @@
+// Package xai provides integration with xAI's Grok models. The Grok API is
+// OpenAI-compatible, so the provider is the OpenAI provider with xAI's defaults and
+// request adjustments for Grok's differences.
+package xai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/openai"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// xaiDefaultModel is the default model for chat completions
+const xaiDefaultModel = "grok-4"
+
+// XAIProvider implements the Provider interface for xAI Grok
+type XAIProvider struct {
+	*openai.OpenAIProvider
+}
+
+// NewXAIProvider creates a new xAI provider. The base URL defaults to
+// https://api.x.ai/v1 and the model to grok-4.
+func NewXAIProvider(config types.ProviderConfig) *XAIProvider {
+	return &XAIProvider{
+		OpenAIProvider: openai.NewCompatibleProvider(config, openai.CompatibleAPI{
+			Name:           "xAI",
+			Type:           types.ProviderTypexAI,
+			Description:    "xAI - Grok models with OpenAI-compatible API access",
+			DefaultModel:   xaiDefaultModel,
+			PrepareRequest: prepareRequest,
+		}),
+	}
+}
+
+// SupportedParameters implements types.ParameterSupportReporter. Grok reasoning models
+// reject the penalties and stop, only the grok-3-mini models take reasoning_effort, and
+// completions are not stored.
+func (p *XAIProvider) SupportedParameters(model string) types.ParameterSupport {
+	if model == "" {
+		model = p.GetDefaultModel()
+	}
+	model = normalizeModel(model)
+	support := types.AllParametersSupported
+	support.Tools = types.ProviderCapabilities(p, model).Tools
+	support.ReasoningEffort = strings.HasPrefix(model, "grok-3-mini")
+	support.Store = false
+
+	if models.IsReasoningModel(types.ProviderTypexAI, model) {
+		support.FrequencyPenalty = false
+		support.PresencePenalty = false
+		support.Stop = false
+	}
+	return support
+}
+
+// prepareRequest adjusts an OpenAI request for the Grok API: model names may carry an
+// "xai/" or "x-ai/" prefix, as in router catalogs; only the grok-3-mini models take
+// reasoning_effort, and only "low" or "high"; and live search is sent from XAIOptions.
+func prepareRequest(request *openai.OpenAIRequest, options types.GenerateOptions) ([]types.Warning, error) {
+	request.Model = normalizeModel(request.Model)
+
+	var warnings []types.Warning
+	if request.ReasoningEffort != "" && !supportsReasoningEffort(request.Model, request.ReasoningEffort) {
+		warnings = append(warnings, types.Warning{
+			Code:    types.WarningParameterIgnored,
+			Message: fmt.Sprintf("model %s does not accept reasoning_effort %q, it was not sent", request.Model, request.ReasoningEffort),
+			Param:   "reasoning_effort",
+		})
+		request.ReasoningEffort = ""
+	}
+
+	opts, err := xaiOptionsFrom(options)
+	if err != nil {
+		return nil, types.NewInvalidRequestError(types.ProviderTypexAI, err.Error()).
+			WithOperation("GenerateChatCompletion")
+	}
+	if opts != nil && opts.SearchParameters != nil {
+		if err := opts.SearchParameters.validate(); err != nil {
+			return nil, types.NewInvalidRequestError(types.ProviderTypexAI, err.Error()).
+				WithOperation("GenerateChatCompletion")
+		}
+		if request.Extra == nil {
+			request.Extra = make(map[string]interface{})
+		}
+		request.Extra["search_parameters"] = opts.SearchParameters
+	}
+	return warnings, nil
+}
+
+// normalizeModel strips a router-style provider prefix from a Grok model name
+func normalizeModel(model string) string {
+	for _, prefix := range []string{"xai/", "x-ai/"} {
+		if strings.HasPrefix(strings.ToLower(model), prefix) {
+			return model[len(prefix):]
+		}
+	}
+	return model
+}
+
+// supportsReasoningEffort reports whether model accepts the reasoning effort. Other
+// Grok reasoning models always reason and reject the parameter.
+func supportsReasoningEffort(model, effort string) bool {
+	return strings.HasPrefix(model, "grok-3-mini") && (effort == "low" || effort == "high")
+}
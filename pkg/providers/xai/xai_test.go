@@ -0,0 +1,144 @@
+package xai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewXAIProvider_Defaults(t *testing.T) {
+	provider := NewXAIProvider(types.ProviderConfig{Type: types.ProviderTypeGrok, APIKey: "xai-key"})
+
+	assert.Equal(t, "xAI", provider.Name())
+	assert.Equal(t, types.ProviderTypexAI, provider.Type())
+	assert.Equal(t, "grok-4", provider.GetDefaultModel())
+	assert.Equal(t, "https://api.x.ai/v1", provider.GetConfig().BaseURL)
+	assert.True(t, provider.SupportsToolCalling())
+	assert.False(t, provider.SupportsResponsesAPI())
+}
+
+// newGrokServer returns a server answering chat completions, recording the request body
+func newGrokServer(t *testing.T, body *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer xai-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(body))
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      "chatcmpl-grok",
+			"object":  "chat.completion",
+			"model":   (*body)["model"],
+			"choices": []interface{}{map[string]interface{}{"index": 0, "message": map[string]interface{}{"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}},
+			"usage":   map[string]interface{}{"prompt_tokens": 3, "completion_tokens": 1, "total_tokens": 4},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestXAIProvider_GenerateChatCompletion(t *testing.T) {
+	var body map[string]interface{}
+	server := newGrokServer(t, &body)
+	provider := NewXAIProvider(types.ProviderConfig{Type: types.ProviderTypexAI, APIKey: "xai-key", BaseURL: server.URL})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt:          "Hello",
+		Model:           "xai/grok-3-mini",
+		ReasoningEffort: types.ReasoningEffortHigh,
+		ProviderOptions: map[string]interface{}{
+			"xai": map[string]interface{}{
+				"search_parameters": map[string]interface{}{"mode": "on", "max_search_results": 5},
+			},
+		},
+	})
+	require.NoError(t, err)
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", chunk.Content)
+	assert.Empty(t, chunk.Warnings)
+
+	assert.Equal(t, "grok-3-mini", body["model"])
+	assert.Equal(t, "high", body["reasoning_effort"])
+	assert.Equal(t, map[string]interface{}{"mode": "on", "max_search_results": float64(5)}, body["search_parameters"])
+}
+
+func TestXAIProvider_DropsUnsupportedReasoningEffort(t *testing.T) {
+	var body map[string]interface{}
+	server := newGrokServer(t, &body)
+	provider := NewXAIProvider(types.ProviderConfig{Type: types.ProviderTypexAI, APIKey: "xai-key", BaseURL: server.URL})
+
+	stream, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt:          "Hello",
+		ReasoningEffort: types.ReasoningEffortHigh,
+	})
+	require.NoError(t, err)
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+
+	assert.Equal(t, "grok-4", body["model"])
+	assert.NotContains(t, body, "reasoning_effort")
+	assert.NotContains(t, body, "search_parameters")
+	require.Len(t, chunk.Warnings, 1)
+	assert.Equal(t, "reasoning_effort", chunk.Warnings[0].Param)
+}
+
+func TestXAIProvider_InvalidSearchParameters(t *testing.T) {
+	provider := NewXAIProvider(types.ProviderConfig{Type: types.ProviderTypexAI, APIKey: "xai-key", BaseURL: "http://127.0.0.1:0"})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{
+		Prompt: "Hello",
+		ProviderOptions: map[string]interface{}{
+			"xai": XAIOptions{SearchParameters: &XAISearchParameters{Mode: "always"}},
+		},
+	})
+	var providerErr *types.ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, types.ErrCodeInvalidRequest, providerErr.Code)
+	assert.Contains(t, providerErr.Message, "search mode")
+}
+
+func TestNormalizeModel(t *testing.T) {
+	assert.Equal(t, "grok-4", normalizeModel("xai/grok-4"))
+	assert.Equal(t, "grok-4", normalizeModel("x-ai/grok-4"))
+	assert.Equal(t, "grok-4", normalizeModel("grok-4"))
+}
+
+func TestXAIProvider_SupportedParameters(t *testing.T) {
+	provider := NewXAIProvider(types.ProviderConfig{Type: types.ProviderTypexAI, APIKey: "xai-key"})
+
+	mini := provider.SupportedParameters("xai/grok-3-mini")
+	assert.True(t, mini.ReasoningEffort)
+	assert.True(t, mini.Temperature)
+	assert.False(t, mini.PresencePenalty)
+	assert.False(t, mini.Stop)
+
+	grok3 := provider.SupportedParameters("grok-3")
+	assert.False(t, grok3.ReasoningEffort)
+	assert.True(t, grok3.Stop)
+}
+
+func TestXAIProvider_ErrorsNameXAI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad key","type":"invalid_request_error"}}`))
+	}))
+	t.Cleanup(server.Close)
+	provider := NewXAIProvider(types.ProviderConfig{Type: types.ProviderTypexAI, APIKey: "xai-key", BaseURL: server.URL})
+
+	_, err := provider.GenerateChatCompletion(context.Background(), types.GenerateOptions{Prompt: "Hello"})
+	var providerErr *types.ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, types.ProviderTypexAI, providerErr.Provider)
+	assert.Contains(t, providerErr.Message, "xAI")
+	assert.NotContains(t, err.Error(), "OpenAI")
+
+	// Reconfiguring validates against the xAI type rather than OpenAI's
+	assert.NoError(t, provider.Configure(types.ProviderConfig{Type: types.ProviderTypexAI, APIKey: "xai-key", BaseURL: server.URL}))
+}
@@ -0,0 +1,56 @@
+package toolvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// MergeTools concatenates tool sets, such as those contributed by several plugins, into
+// one set in order of first appearance. A tool defined identically in more than one set
+// is kept once; two different definitions under the same name are an error. Every
+// merged tool must pass ValidateToolDefinition.
+func MergeTools(sets ...[]types.Tool) ([]types.Tool, error) {
+	var merged []types.Tool
+	seen := make(map[string]int)
+	validator := New(false)
+
+	for _, set := range sets {
+		for _, tool := range set {
+			if i, ok := seen[tool.Name]; ok {
+				same, err := sameTool(merged[i], tool)
+				if err != nil {
+					return nil, err
+				}
+				if !same {
+					return nil, fmt.Errorf("conflicting definitions for tool %s", tool.Name)
+				}
+				continue
+			}
+
+			if err := validator.ValidateToolDefinition(tool); err != nil {
+				return nil, fmt.Errorf("invalid tool %q: %w", tool.Name, err)
+			}
+			seen[tool.Name] = len(merged)
+			merged = append(merged, tool)
+		}
+	}
+
+	return merged, nil
+}
+
+// sameTool reports whether a and b are the same definition. Tools are compared by their
+// JSON encoding, so schemas built in code match those decoded from JSON.
+func sameTool(a, b types.Tool) (bool, error) {
+	encodedA, err := json.Marshal(a)
+	if err != nil {
+		return false, fmt.Errorf("invalid input schema for tool %s: %w", a.Name, err)
+	}
+	encodedB, err := json.Marshal(b)
+	if err != nil {
+		return false, fmt.Errorf("invalid input schema for tool %s: %w", b.Name, err)
+	}
+	return bytes.Equal(encodedA, encodedB), nil
+}
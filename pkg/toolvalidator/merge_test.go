@@ -0,0 +1,56 @@
+package toolvalidator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeTools(t *testing.T) {
+	weather := types.Tool{
+		Name:        "get_weather",
+		Description: "Get the current weather",
+		InputSchema: map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"location"},
+		},
+	}
+	search := types.Tool{
+		Name:        "search",
+		Description: "Search the web",
+		InputSchema: map[string]interface{}{"type": "object"},
+	}
+
+	t.Run("ConcatenatesAndDeduplicates", func(t *testing.T) {
+		var decoded types.Tool
+		encoded, err := json.Marshal(weather)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+		merged, err := MergeTools([]types.Tool{weather}, []types.Tool{search, decoded}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []types.Tool{weather, search}, merged)
+	})
+
+	t.Run("ConflictingDefinitions", func(t *testing.T) {
+		other := weather
+		other.Description = "Get the forecast"
+
+		_, err := MergeTools([]types.Tool{weather}, []types.Tool{other})
+		assert.EqualError(t, err, "conflicting definitions for tool get_weather")
+	})
+
+	t.Run("InvalidTool", func(t *testing.T) {
+		_, err := MergeTools([]types.Tool{search}, []types.Tool{{Name: "broken", Description: "No schema"}})
+		assert.EqualError(t, err, `invalid tool "broken": tool input schema is required`)
+	})
+
+	t.Run("NoTools", func(t *testing.T) {
+		merged, err := MergeTools()
+		require.NoError(t, err)
+		assert.Empty(t, merged)
+	})
+}
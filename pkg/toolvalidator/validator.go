@@ -81,6 +81,22 @@ func (v *Validator) ValidateToolCall(tool types.Tool, call types.ToolCall) error
 	return nil
 }
 
+// ValidateValue validates a decoded JSON value, such as a structured model output,
+// against a JSON schema: the top-level type, required fields, and the types and enums
+// of the top-level properties
+func (v *Validator) ValidateValue(value interface{}, schema map[string]interface{}) error {
+	if schemaType, ok := schema["type"].(string); ok {
+		if err := v.validateType("root", value, schemaType); err != nil {
+			return err
+		}
+	}
+
+	if data, ok := value.(map[string]interface{}); ok {
+		return v.validateAgainstSchema(data, schema)
+	}
+	return nil
+}
+
 // validateAgainstSchema validates data against a JSON schema
 func (v *Validator) validateAgainstSchema(data map[string]interface{}, schema map[string]interface{}) error {
 	if err := v.validateRequiredFields(data, schema); err != nil {
@@ -333,3 +333,24 @@ func TestTypeValidation(t *testing.T) {
 		assert.Contains(t, err.Error(), "must be a boolean")
 	})
 }
+
+func TestValidateValue(t *testing.T) {
+	validator := New(false)
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"age":  map[string]interface{}{"type": "number"},
+		},
+		"required": []interface{}{"name"},
+	}
+
+	assert.NoError(t, validator.ValidateValue(map[string]interface{}{"name": "Ada", "age": float64(36)}, schema))
+
+	err := validator.ValidateValue(map[string]interface{}{"age": float64(36)}, schema)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "required field name is missing")
+
+	assert.Error(t, validator.ValidateValue([]interface{}{"Ada"}, schema))
+	assert.Error(t, validator.ValidateValue(map[string]interface{}{"name": float64(1)}, schema))
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCacheWarmTTL is how long a provider's prompt cache is taken to stay warm after
+// its last response, matching the five minute lifetime of Anthropic and OpenAI caches
+const DefaultCacheWarmTTL = 5 * time.Minute
+
+// cacheHitSmoothing is the weight of the newest response in a provider's hit rate
+const cacheHitSmoothing = 0.3
+
+// CacheHitTracker keeps the recent prompt cache hit rate of each provider, from the
+// Usage of their responses, so routing providers can stay on a provider whose cache is
+// warm instead of moving to one where the prompt would be processed in full.
+//
+// The rate is a moving average that favours recent responses, and it is forgotten once
+// a provider has had no response for the TTL, since its cache will have expired.
+// A CacheHitTracker is safe for concurrent use.
+type CacheHitTracker struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	providers map[string]cacheHitState
+}
+
+type cacheHitState struct {
+	rate       float64
+	observedAt time.Time
+}
+
+// NewCacheHitTracker returns a tracker that forgets a provider's hit rate ttl after its
+// last response. A ttl of 0 uses DefaultCacheWarmTTL.
+func NewCacheHitTracker(ttl time.Duration) *CacheHitTracker {
+	if ttl <= 0 {
+		ttl = DefaultCacheWarmTTL
+	}
+	return &CacheHitTracker{ttl: ttl, providers: make(map[string]cacheHitState)}
+}
+
+// Record adds the usage of a response from provider. Usage without prompt tokens is
+// ignored.
+func (t *CacheHitTracker) Record(provider string, usage Usage) {
+	t.record(provider, usage, time.Now())
+}
+
+func (t *CacheHitTracker) record(provider string, usage Usage, now time.Time) {
+	if usage.PromptTokens <= 0 {
+		return
+	}
+	rate := usage.CacheHitRate()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state, ok := t.providers[provider]; ok && now.Sub(state.observedAt) < t.ttl {
+		rate = cacheHitSmoothing*rate + (1-cacheHitSmoothing)*state.rate
+	}
+	t.providers[provider] = cacheHitState{rate: rate, observedAt: now}
+}
+
+// HitRate returns provider's recent cache hit rate, from 0 to 1, or 0 when it has had
+// no response within the TTL
+func (t *CacheHitTracker) HitRate(provider string) float64 {
+	return t.hitRate(provider, time.Now())
+}
+
+func (t *CacheHitTracker) hitRate(provider string, now time.Time) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.providers[provider]
+	if !ok || now.Sub(state.observedAt) >= t.ttl {
+		return 0
+	}
+	return state.rate
+}
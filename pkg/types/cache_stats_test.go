@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage_CacheHitRate(t *testing.T) {
+	assert.Equal(t, 0.75, Usage{PromptTokens: 400, CacheReadTokens: 300}.CacheHitRate())
+	assert.Zero(t, Usage{PromptTokens: 400}.CacheHitRate())
+	assert.Zero(t, Usage{}.CacheHitRate())
+}
+
+func TestCacheHitTracker(t *testing.T) {
+	now := time.Now()
+	tracker := NewCacheHitTracker(time.Minute)
+
+	t.Run("unknown provider is cold", func(t *testing.T) {
+		assert.Zero(t, tracker.hitRate("anthropic", now))
+	})
+
+	t.Run("first response sets the rate", func(t *testing.T) {
+		tracker.record("anthropic", Usage{PromptTokens: 1000, CacheReadTokens: 1000}, now)
+		assert.Equal(t, 1.0, tracker.hitRate("anthropic", now))
+	})
+
+	t.Run("later responses are averaged in", func(t *testing.T) {
+		tracker.record("anthropic", Usage{PromptTokens: 1000}, now.Add(time.Second))
+		assert.InDelta(t, 0.7, tracker.hitRate("anthropic", now.Add(time.Second)), 1e-9)
+	})
+
+	t.Run("usage without prompt tokens is ignored", func(t *testing.T) {
+		tracker.record("anthropic", Usage{}, now.Add(2*time.Second))
+		assert.InDelta(t, 0.7, tracker.hitRate("anthropic", now.Add(2*time.Second)), 1e-9)
+	})
+
+	t.Run("rate expires with the cache", func(t *testing.T) {
+		assert.Zero(t, tracker.hitRate("anthropic", now.Add(2*time.Minute)))
+
+		// A response after expiry starts afresh
+		tracker.record("anthropic", Usage{PromptTokens: 100, CacheReadTokens: 50}, now.Add(2*time.Minute))
+		assert.Equal(t, 0.5, tracker.hitRate("anthropic", now.Add(2*time.Minute)))
+	})
+}
@@ -0,0 +1,160 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RequestFeature is a request feature that not every provider or model supports
+type RequestFeature string
+
+const (
+	// FeatureTools is tool (function) calling, requested with GenerateOptions.Tools
+	FeatureTools RequestFeature = "tools"
+
+	// FeatureVision is image input, requested with image content parts in messages
+	FeatureVision RequestFeature = "vision"
+
+	// FeatureJSONSchema is structured output, requested with GenerateOptions.ResponseFormat
+	FeatureJSONSchema RequestFeature = "json_schema"
+
+	// FeatureStreaming is a streamed response, requested with GenerateOptions.Stream
+	FeatureStreaming RequestFeature = "streaming"
+)
+
+// Capabilities lists the request features a provider supports
+type Capabilities struct {
+	Tools      bool `json:"tools"`
+	Vision     bool `json:"vision"`
+	JSONSchema bool `json:"json_schema"`
+	Streaming  bool `json:"streaming"`
+}
+
+// Supports reports whether feature is supported
+func (c Capabilities) Supports(feature RequestFeature) bool {
+	switch feature {
+	case FeatureTools:
+		return c.Tools
+	case FeatureVision:
+		return c.Vision
+	case FeatureJSONSchema:
+		return c.JSONSchema
+	case FeatureStreaming:
+		return c.Streaming
+	default:
+		return true
+	}
+}
+
+// CapabilitiesReporter is an optional interface for providers that know which request
+// features each of their models supports. Providers without it are assumed to support
+// vision and JSON schema, and tools and streaming as reported by SupportsToolCalling
+// and SupportsStreaming.
+type CapabilitiesReporter interface {
+	Capabilities(model string) Capabilities
+}
+
+// ProviderCapabilities returns the request features provider supports for model.
+// Per-model overrides in the provider's ProviderConfig.ModelCapabilities take
+// precedence; an empty model uses the configured default model.
+func ProviderCapabilities(provider Provider, model string) Capabilities {
+	config := provider.GetConfig()
+	if model == "" {
+		model = config.DefaultModel
+	}
+
+	var capabilities Capabilities
+	if reporter, ok := provider.(CapabilitiesReporter); ok {
+		capabilities = reporter.Capabilities(model)
+	} else {
+		capabilities = Capabilities{
+			Tools:      provider.SupportsToolCalling(),
+			Vision:     true,
+			JSONSchema: true,
+			Streaming:  provider.SupportsStreaming(),
+		}
+	}
+
+	if override, ok := config.ModelCapabilities[model]; ok {
+		if override.SupportsTools != nil {
+			capabilities.Tools = *override.SupportsTools
+		}
+		if override.SupportsVision != nil {
+			capabilities.Vision = *override.SupportsVision
+		}
+		if override.SupportsJSONSchema != nil {
+			capabilities.JSONSchema = *override.SupportsJSONSchema
+		}
+		if override.SupportsStreaming != nil {
+			capabilities.Streaming = *override.SupportsStreaming
+		}
+	}
+	return capabilities
+}
+
+// ModelToolFormat returns the tool format configured for model: its
+// ModelCapabilities override, then config.ToolFormat, then defaultFormat
+func ModelToolFormat(config ProviderConfig, model string, defaultFormat ToolFormat) ToolFormat {
+	if override, ok := config.ModelCapabilities[model]; ok && override.ToolFormat != "" {
+		return override.ToolFormat
+	}
+	if config.ToolFormat != "" {
+		return config.ToolFormat
+	}
+	return defaultFormat
+}
+
+// ModelContextWindow returns model's context window from its ModelCapabilities
+// override, or 0 when none is configured. common.ModelContextWindow falls back to the
+// models.dev defaults.
+func ModelContextWindow(config ProviderConfig, model string) int {
+	if override, ok := config.ModelCapabilities[model]; ok && override.ContextWindow != nil {
+		return *override.ContextWindow
+	}
+	return 0
+}
+
+// RequestFeatures returns the features used by a request, in a fixed order
+func RequestFeatures(options GenerateOptions) []RequestFeature {
+	var features []RequestFeature
+	if len(options.Tools) > 0 {
+		features = append(features, FeatureTools)
+	}
+	for i := range options.Messages {
+		if options.Messages[i].HasImages() {
+			features = append(features, FeatureVision)
+			break
+		}
+	}
+	if options.ResponseFormat != "" && options.ResponseFormat != "text" {
+		features = append(features, FeatureJSONSchema)
+	}
+	if options.Stream {
+		features = append(features, FeatureStreaming)
+	}
+	return features
+}
+
+// ErrUnsupportedFeature is matched (with errors.Is) by *UnsupportedFeatureError
+var ErrUnsupportedFeature = errors.New("unsupported feature")
+
+// UnsupportedFeatureError reports a request feature the target provider does not
+// support, found before the request was sent
+type UnsupportedFeatureError struct {
+	Provider ProviderType
+	Model    string
+	Feature  RequestFeature
+}
+
+// Error implements error
+func (e *UnsupportedFeatureError) Error() string {
+	if e.Model != "" {
+		return fmt.Sprintf("provider %s does not support %s with model %s", e.Provider, e.Feature, e.Model)
+	}
+	return fmt.Sprintf("provider %s does not support %s", e.Provider, e.Feature)
+}
+
+// Is reports whether target is ErrUnsupportedFeature
+func (e *UnsupportedFeatureError) Is(target error) bool {
+	return target == ErrUnsupportedFeature
+}
@@ -0,0 +1,289 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ChatSession holds the state of a multi-turn conversation: a system prompt, the
+// message history and the options each completion is generated with. Messages are
+// added with AddUser, AddAssistant and AddToolResult, which reject a role out of turn;
+// Complete sends the history to a provider and appends the assistant's reply.
+//
+// The rules are: a user message cannot follow another user message; an assistant
+// message must follow a user message or the tool results of the previous assistant
+// message; every tool call of an assistant message must be answered with a tool result
+// before the conversation continues.
+//
+//	session := types.NewChatSession("You are a helpful assistant.", types.GenerateOptions{Model: "gpt-4o"})
+//	_ = session.AddUser("What is the capital of France?")
+//	reply, err := session.Complete(ctx, provider)
+//
+// A session is saved and resumed with encoding/json; see MarshalJSON.
+//
+// A ChatSession is not safe for concurrent use.
+type ChatSession struct {
+	// Metadata is application data saved with the session, such as a title or user ID.
+	// It is not sent to providers.
+	Metadata map[string]interface{}
+
+	systemPrompt string
+	options      GenerateOptions
+	history      []ChatMessage
+
+	// Tool calls of the last assistant message not yet answered, by ID
+	pendingToolCalls map[string]bool
+}
+
+// NewChatSession creates an empty session. An empty systemPrompt sends no system
+// message. Complete uses options for every request, with the session's messages in
+// place of its Messages and Prompt.
+func NewChatSession(systemPrompt string, options GenerateOptions) *ChatSession {
+	return &ChatSession{systemPrompt: systemPrompt, options: options}
+}
+
+// SystemPrompt returns the session's system prompt
+func (s *ChatSession) SystemPrompt() string {
+	return s.systemPrompt
+}
+
+// Options returns the options completions are generated with
+func (s *ChatSession) Options() GenerateOptions {
+	return s.options
+}
+
+// SetOptions replaces the options completions are generated with
+func (s *ChatSession) SetOptions(options GenerateOptions) {
+	s.options = options
+}
+
+// AddUser appends a user message
+func (s *ChatSession) AddUser(content string) error {
+	return s.add(ChatMessage{Role: "user", Content: content})
+}
+
+// AddAssistant appends an assistant message, such as a reply generated elsewhere.
+// Its tool calls must each be answered with AddToolResult.
+func (s *ChatSession) AddAssistant(content string, toolCalls ...ToolCall) error {
+	return s.add(ChatMessage{Role: "assistant", Content: content, ToolCalls: toolCalls})
+}
+
+// AddToolResult appends the result of the tool call with the given ID, which must be
+// an unanswered call of the last assistant message
+func (s *ChatSession) AddToolResult(toolCallID, content string) error {
+	return s.add(ChatMessage{Role: "tool", Content: content, ToolCallID: toolCallID})
+}
+
+// PendingToolCalls returns the tool calls of the last assistant message that have no
+// result yet, in the order the assistant made them
+func (s *ChatSession) PendingToolCalls() []ToolCall {
+	if len(s.pendingToolCalls) == 0 {
+		return nil
+	}
+	var pending []ToolCall
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if s.history[i].Role != "assistant" {
+			continue
+		}
+		for _, call := range s.history[i].ToolCalls {
+			if s.pendingToolCalls[call.ID] {
+				pending = append(pending, call)
+			}
+		}
+		break
+	}
+	return pending
+}
+
+// Messages returns a copy of the conversation as sent to providers: the system prompt,
+// if any, followed by the history. It can be stored and passed to Restore.
+func (s *ChatSession) Messages() []ChatMessage {
+	messages := make([]ChatMessage, 0, len(s.history)+1)
+	if s.systemPrompt != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: s.systemPrompt})
+	}
+	return append(messages, s.history...)
+}
+
+// Restore replaces the conversation with messages, such as those saved from Messages.
+// A leading system message replaces the system prompt. The messages must follow the
+// session's ordering rules; on error the session is unchanged.
+func (s *ChatSession) Restore(messages []ChatMessage) error {
+	restored := &ChatSession{Metadata: s.Metadata, systemPrompt: s.systemPrompt, options: s.options}
+	if len(messages) > 0 && messages[0].Role == "system" {
+		restored.systemPrompt = messages[0].GetTextContent()
+		messages = messages[1:]
+	}
+	for i, message := range messages {
+		if err := restored.add(message); err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+	}
+	*s = *restored
+	return nil
+}
+
+// chatSessionVersion is the version of the JSON form of a ChatSession
+const chatSessionVersion = 1
+
+// chatSessionJSON is the JSON form of a ChatSession
+type chatSessionJSON struct {
+	Version      int                    `json:"version"`
+	SystemPrompt string                 `json:"system_prompt,omitempty"`
+	Options      GenerateOptions        `json:"options"`
+	Messages     []ChatMessage          `json:"messages"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// MarshalJSON encodes the session for storage: its system prompt, history including
+// tool calls and results, options (with the model) and Metadata. Options fields that
+// cannot be encoded, such as ContextObj, are dropped, and ProviderOptions values of
+// provider-specific types are decoded back as JSON objects.
+func (s *ChatSession) MarshalJSON() ([]byte, error) {
+	options := s.options
+	options.Messages = nil
+	options.Prompt = ""
+	options.ContextObj = nil
+	return json.Marshal(chatSessionJSON{
+		Version:      chatSessionVersion,
+		SystemPrompt: s.systemPrompt,
+		Options:      options,
+		Messages:     s.history,
+		Metadata:     s.Metadata,
+	})
+}
+
+// UnmarshalJSON resumes a session encoded by MarshalJSON. The history must follow the
+// session's ordering rules; on error the session is unchanged.
+func (s *ChatSession) UnmarshalJSON(data []byte) error {
+	var encoded chatSessionJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("failed to decode chat session: %w", err)
+	}
+	if encoded.Version != chatSessionVersion {
+		return fmt.Errorf("unsupported chat session version %d", encoded.Version)
+	}
+
+	restored := NewChatSession(encoded.SystemPrompt, encoded.Options)
+	restored.Metadata = encoded.Metadata
+	if err := restored.Restore(encoded.Messages); err != nil {
+		return fmt.Errorf("invalid chat session: %w", err)
+	}
+	*s = *restored
+	return nil
+}
+
+// Complete generates the assistant's reply to the conversation with provider, appends
+// it to the history and returns it. The last message must be a user message or the
+// results of every pending tool call. The request is never streamed.
+func (s *ChatSession) Complete(ctx context.Context, provider ChatProvider) (ChatMessage, error) {
+	if len(s.pendingToolCalls) > 0 {
+		return ChatMessage{}, NewValidationError(fmt.Sprintf("%d tool calls have no result", len(s.pendingToolCalls)))
+	}
+	if len(s.history) == 0 || s.history[len(s.history)-1].Role == "assistant" {
+		return ChatMessage{}, NewValidationError("the conversation must end with a user message or tool results to be completed")
+	}
+
+	options := s.options
+	options.Messages = s.Messages()
+	options.Prompt = ""
+	options.Stream = false
+
+	stream, err := provider.GenerateChatCompletion(ctx, options)
+	if err != nil {
+		return ChatMessage{}, err
+	}
+
+	reply := ChatMessage{Role: "assistant"}
+	var content, reasoning strings.Builder
+	err = StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+		content.WriteString(chunk.Content)
+		reasoning.WriteString(chunk.Reasoning)
+		reply.Citations = append(reply.Citations, chunk.Citations...)
+		if len(chunk.Choices) > 0 {
+			message := chunk.Choices[0].Message
+			if chunk.Content == "" {
+				content.WriteString(message.Content)
+			}
+			if len(chunk.Citations) == 0 {
+				reply.Citations = append(reply.Citations, message.Citations...)
+			}
+			reply.ToolCalls = append(reply.ToolCalls, message.ToolCalls...)
+		}
+		return nil
+	})
+	if err != nil {
+		return ChatMessage{}, err
+	}
+	reply.Content = content.String()
+	reply.Reasoning = reasoning.String()
+
+	// Some providers, such as Ollama, return tool calls without IDs; give them one so
+	// their results can be matched, rather than rejecting a reply already paid for
+	for i := range reply.ToolCalls {
+		if reply.ToolCalls[i].ID == "" {
+			reply.ToolCalls[i].ID = "call_" + uuid.New().String()
+		}
+	}
+
+	if err := s.add(reply); err != nil {
+		return ChatMessage{}, err
+	}
+	return reply, nil
+}
+
+// add appends message if its role is valid at this point of the conversation
+func (s *ChatSession) add(message ChatMessage) error {
+	var last string
+	if len(s.history) > 0 {
+		last = s.history[len(s.history)-1].Role
+	}
+
+	switch message.Role {
+	case "user":
+		if len(s.pendingToolCalls) > 0 {
+			return NewValidationError("a user message cannot follow tool calls that have no result")
+		}
+		if last == "user" {
+			return NewValidationError("a user message cannot follow another user message")
+		}
+	case "assistant":
+		if len(s.pendingToolCalls) > 0 {
+			return NewValidationError("an assistant message cannot follow tool calls that have no result")
+		}
+		if last != "user" && last != "tool" {
+			return NewValidationError("an assistant message must follow a user message or tool results")
+		}
+		for _, call := range message.ToolCalls {
+			if call.ID == "" {
+				return NewValidationError(fmt.Sprintf("tool call %q requires an ID", call.Function.Name))
+			}
+		}
+	case "tool":
+		if message.ToolCallID == "" {
+			return NewValidationError("a tool result requires a tool call ID")
+		}
+		if !s.pendingToolCalls[message.ToolCallID] {
+			return NewValidationError(fmt.Sprintf("tool call %q is not a pending call of the last assistant message", message.ToolCallID))
+		}
+		delete(s.pendingToolCalls, message.ToolCallID)
+	case "system":
+		return NewValidationError("a system message can only be the first message")
+	default:
+		return NewValidationError(fmt.Sprintf("unknown message role %q", message.Role))
+	}
+
+	if message.Role == "assistant" && len(message.ToolCalls) > 0 {
+		s.pendingToolCalls = make(map[string]bool, len(message.ToolCalls))
+		for _, call := range message.ToolCalls {
+			s.pendingToolCalls[call.ID] = true
+		}
+	}
+
+	s.history = append(s.history, message)
+	return nil
+}
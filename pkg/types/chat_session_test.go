@@ -0,0 +1,192 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedChatProvider replies with its chunks in turn, recording the options it was
+// called with
+type scriptedChatProvider struct {
+	replies []ChatCompletionChunk
+	calls   []GenerateOptions
+	err     error
+}
+
+func (p *scriptedChatProvider) GenerateChatCompletion(_ context.Context, options GenerateOptions) (ChatCompletionStream, error) {
+	p.calls = append(p.calls, options)
+	if p.err != nil {
+		return nil, p.err
+	}
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	return &mockCompletionStream{chunks: []ChatCompletionChunk{reply}}, nil
+}
+
+func TestChatSession_Complete(t *testing.T) {
+	weatherCall := ToolCall{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}
+	provider := &scriptedChatProvider{replies: []ChatCompletionChunk{
+		{Done: true, Choices: []ChatChoice{{Message: ChatMessage{Role: "assistant", ToolCalls: []ToolCall{weatherCall}}}}},
+		{Done: true, Content: "It is sunny in Paris."},
+	}}
+
+	session := NewChatSession("You are helpful.", GenerateOptions{Model: "test-model", Prompt: "ignored", Stream: true})
+	require.NoError(t, session.AddUser("Weather in Paris?"))
+
+	reply, err := session.Complete(context.Background(), provider)
+	require.NoError(t, err)
+	assert.Equal(t, []ToolCall{weatherCall}, reply.ToolCalls)
+	assert.Equal(t, []ToolCall{weatherCall}, session.PendingToolCalls())
+
+	_, err = session.Complete(context.Background(), provider)
+	assert.True(t, IsValidationError(err), "pending tool calls must be answered first")
+
+	require.NoError(t, session.AddToolResult("call_1", `{"forecast":"sunny"}`))
+	reply, err = session.Complete(context.Background(), provider)
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny in Paris.", reply.Content)
+
+	require.Len(t, provider.calls, 2)
+	sent := provider.calls[1]
+	assert.Equal(t, "test-model", sent.Model)
+	assert.Empty(t, sent.Prompt)
+	assert.False(t, sent.Stream)
+	roles := make([]string, len(sent.Messages))
+	for i, message := range sent.Messages {
+		roles[i] = message.Role
+	}
+	assert.Equal(t, []string{"system", "user", "assistant", "tool"}, roles)
+
+	messages := session.Messages()
+	require.Len(t, messages, 5)
+	assert.Equal(t, ChatMessage{Role: "assistant", Content: "It is sunny in Paris."}, messages[4])
+}
+
+func TestChatSession_CompleteAssignsMissingToolCallIDs(t *testing.T) {
+	// Ollama returns tool calls without IDs
+	call := ToolCall{Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}
+	provider := &scriptedChatProvider{replies: []ChatCompletionChunk{
+		{Done: true, Choices: []ChatChoice{{Message: ChatMessage{Role: "assistant", ToolCalls: []ToolCall{call, call}}}}},
+		{Done: true, Content: "It is sunny in Paris."},
+	}}
+
+	session := NewChatSession("", GenerateOptions{})
+	require.NoError(t, session.AddUser("Weather in Paris?"))
+
+	reply, err := session.Complete(context.Background(), provider)
+	require.NoError(t, err)
+	require.Len(t, reply.ToolCalls, 2)
+	assert.NotEmpty(t, reply.ToolCalls[0].ID)
+	assert.NotEqual(t, reply.ToolCalls[0].ID, reply.ToolCalls[1].ID)
+	assert.Equal(t, reply.ToolCalls, session.PendingToolCalls())
+
+	for _, pending := range reply.ToolCalls {
+		require.NoError(t, session.AddToolResult(pending.ID, `{"forecast":"sunny"}`))
+	}
+	_, err = session.Complete(context.Background(), provider)
+	require.NoError(t, err)
+}
+
+func TestChatSession_Citations(t *testing.T) {
+	source := Citation{Title: "go.dev", URL: "https://go.dev", Text: "Go is compiled."}
+	provider := &scriptedChatProvider{replies: []ChatCompletionChunk{
+		{Done: true, Content: "Go is compiled.", Citations: []Citation{source}, Choices: []ChatChoice{
+			{Message: ChatMessage{Role: "assistant", Content: "Go is compiled.", Citations: []Citation{source}}},
+		}},
+	}}
+
+	session := NewChatSession("", GenerateOptions{})
+	require.NoError(t, session.AddUser("Is Go compiled?"))
+
+	reply, err := session.Complete(context.Background(), provider)
+	require.NoError(t, err)
+	assert.Equal(t, []Citation{source}, reply.Citations, "citations must not be counted twice")
+}
+
+func TestChatSession_Ordering(t *testing.T) {
+	session := NewChatSession("", GenerateOptions{})
+	assert.Error(t, session.AddAssistant("Hi"), "an assistant message needs a user message first")
+	assert.Error(t, session.AddToolResult("call_1", "result"))
+
+	require.NoError(t, session.AddUser("Hello"))
+	assert.Error(t, session.AddUser("Hello again"))
+
+	call := ToolCall{ID: "call_1", Function: ToolCallFunction{Name: "lookup"}}
+	require.NoError(t, session.AddAssistant("", call))
+	assert.Error(t, session.AddUser("Never mind"), "tool calls are pending")
+	assert.Error(t, session.AddToolResult("call_2", "result"), "no such pending call")
+	require.NoError(t, session.AddToolResult("call_1", "result"))
+	assert.Error(t, session.AddToolResult("call_1", "result"), "already answered")
+	require.NoError(t, session.AddAssistant("Done"))
+
+	_, err := session.Complete(context.Background(), &scriptedChatProvider{})
+	assert.True(t, IsValidationError(err), "the last message is the assistant's")
+
+	assert.Error(t, session.AddAssistant("", ToolCall{Function: ToolCallFunction{Name: "lookup"}}), "tool calls need IDs")
+	assert.Len(t, session.Messages(), 4, "rejected messages are not added")
+}
+
+func TestChatSession_Restore(t *testing.T) {
+	original := NewChatSession("Be brief.", GenerateOptions{})
+	require.NoError(t, original.AddUser("Hi"))
+	require.NoError(t, original.AddAssistant("Hello!"))
+
+	restored := NewChatSession("", GenerateOptions{})
+	require.NoError(t, restored.Restore(original.Messages()))
+	assert.Equal(t, "Be brief.", restored.SystemPrompt())
+	assert.Equal(t, original.Messages(), restored.Messages())
+	require.NoError(t, restored.AddUser("Bye"))
+
+	err := restored.Restore([]ChatMessage{{Role: "user", Content: "a"}, {Role: "user", Content: "b"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "message 1")
+	assert.Len(t, restored.Messages(), 4, "a failed restore leaves the session unchanged")
+}
+
+func TestChatSession_ProviderError(t *testing.T) {
+	providerErr := errors.New("unavailable")
+	session := NewChatSession("", GenerateOptions{})
+	require.NoError(t, session.AddUser("Hi"))
+
+	_, err := session.Complete(context.Background(), &scriptedChatProvider{err: providerErr})
+	assert.ErrorIs(t, err, providerErr)
+	assert.Len(t, session.Messages(), 1, "a failed completion adds nothing")
+}
+
+func TestChatSession_JSON(t *testing.T) {
+	session := NewChatSession("Be brief.", GenerateOptions{Model: "gpt-4o", Temperature: 0.2, Prompt: "ignored"})
+	session.Metadata = map[string]interface{}{"title": "Weather"}
+	require.NoError(t, session.AddUser("Weather in Paris?"))
+	require.NoError(t, session.AddAssistant("", ToolCall{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}))
+
+	data, err := json.Marshal(session)
+	require.NoError(t, err)
+
+	var resumed ChatSession
+	require.NoError(t, json.Unmarshal(data, &resumed))
+	assert.Equal(t, session.Messages(), resumed.Messages())
+	assert.Equal(t, "gpt-4o", resumed.Options().Model)
+	assert.Equal(t, 0.2, resumed.Options().Temperature)
+	assert.Empty(t, resumed.Options().Prompt)
+	assert.Equal(t, "Weather", resumed.Metadata["title"])
+
+	// The pending tool call is restored along with the history
+	assert.Error(t, resumed.AddUser("Never mind"))
+	require.NoError(t, resumed.AddToolResult("call_1", "sunny"))
+
+	t.Run("InvalidHistory", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"version":1,"messages":[{"role":"assistant","content":"Hi"}]}`), &resumed)
+		require.Error(t, err)
+		assert.Len(t, resumed.Messages(), 4, "the session is unchanged")
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"version":2,"messages":[]}`), &resumed)
+		assert.EqualError(t, err, "unsupported chat session version 2")
+	})
+}
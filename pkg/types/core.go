@@ -5,6 +5,8 @@ package types
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -21,13 +23,18 @@ type StandardRequest struct {
 	MaxTokens   int      `json:"max_tokens,omitempty"`
 	Temperature float64  `json:"temperature,omitempty"`
 	Stop        []string `json:"stop,omitempty"`
+	N           int      `json:"n,omitempty"` // Completions to generate; see GenerateOptions.N
 
 	// Streaming control
 	Stream bool `json:"stream"`
 
 	// Tool support (if provider supports it)
-	Tools      []Tool      `json:"tools,omitempty"`
-	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+	Tools             []Tool      `json:"tools,omitempty"`
+	ToolChoice        *ToolChoice `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool       `json:"parallel_tool_calls,omitempty"` // See GenerateOptions.ParallelToolCalls
+
+	// Reasoning control, see GenerateOptions.ReasoningEffort
+	ReasoningEffort ReasoningEffort `json:"reasoning_effort,omitempty"`
 
 	// Response format (for providers that support structured output)
 	ResponseFormat string `json:"response_format,omitempty"`
@@ -36,6 +43,17 @@ type StandardRequest struct {
 	Context  context.Context        `json:"-"`
 	Timeout  time.Duration          `json:"-"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Store    *bool                  `json:"store,omitempty"` // See GenerateOptions.Store
+
+	// Request handling, see the GenerateOptions fields of the same names
+	ModelFallbacks  []string               `json:"model_fallbacks,omitempty"`
+	IdempotencyKey  string                 `json:"idempotency_key,omitempty"`
+	TargetProvider  string                 `json:"target_provider,omitempty"`
+	ProviderOptions map[string]interface{} `json:"provider_options,omitempty"`
+
+	// DegradationPolicy selects how unsupported features are handled; empty means
+	// DegradationStrict
+	DegradationPolicy DegradationPolicy `json:"degradation_policy,omitempty"`
 }
 
 // StandardResponse represents the core response format that all providers return
@@ -53,15 +71,19 @@ type StandardResponse struct {
 	// Usage information
 	Usage Usage `json:"usage"`
 
+	// Non-fatal notices about the request, e.g. a deprecated model
+	Warnings []Warning `json:"warnings,omitempty"`
+
 	// Provider-specific metadata
 	ProviderMetadata map[string]interface{} `json:"provider_metadata,omitempty"`
 }
 
 // StandardChoice represents a choice in a standardized response
 type StandardChoice struct {
-	Index        int         `json:"index"`
-	Message      ChatMessage `json:"message"`
-	FinishReason string      `json:"finish_reason"`
+	Index           int         `json:"index"`
+	Message         ChatMessage `json:"message"`
+	FinishReason    string      `json:"finish_reason"`               // Normalized, see NormalizeFinishReason
+	RawFinishReason string      `json:"raw_finish_reason,omitempty"` // As reported by the provider
 }
 
 // StandardStreamChunk represents a chunk in a streaming response
@@ -80,15 +102,19 @@ type StandardStreamChunk struct {
 	// Stream state
 	Done bool `json:"done"`
 
+	// Non-fatal notices about the request, set on the first chunk
+	Warnings []Warning `json:"warnings,omitempty"`
+
 	// Provider-specific metadata
 	ProviderMetadata map[string]interface{} `json:"provider_metadata,omitempty"`
 }
 
 // StandardStreamChoice represents a choice in a streaming chunk
 type StandardStreamChoice struct {
-	Index        int         `json:"index"`
-	Delta        ChatMessage `json:"delta"`
-	FinishReason string      `json:"finish_reason,omitempty"`
+	Index           int         `json:"index"`
+	Delta           ChatMessage `json:"delta"`
+	FinishReason    string      `json:"finish_reason,omitempty"`     // Normalized, see NormalizeFinishReason
+	RawFinishReason string      `json:"raw_finish_reason,omitempty"` // As reported by the provider
 }
 
 // CoreProviderExtension defines the interface for provider-specific extensions
@@ -174,6 +200,12 @@ func (b *CoreRequestBuilder) WithStop(stop []string) *CoreRequestBuilder {
 	return b
 }
 
+// WithN sets the number of completions to generate, returned as separate choices
+func (b *CoreRequestBuilder) WithN(n int) *CoreRequestBuilder {
+	b.request.N = n
+	return b
+}
+
 // WithStreaming enables or disables streaming
 func (b *CoreRequestBuilder) WithStreaming(stream bool) *CoreRequestBuilder {
 	b.request.Stream = stream
@@ -192,6 +224,35 @@ func (b *CoreRequestBuilder) WithToolChoice(toolChoice *ToolChoice) *CoreRequest
 	return b
 }
 
+// WithParallelToolCalls allows or forbids several tool calls in one response, for
+// providers that support it
+func (b *CoreRequestBuilder) WithParallelToolCalls(parallel bool) *CoreRequestBuilder {
+	b.request.ParallelToolCalls = &parallel
+	return b
+}
+
+// WithReasoningEffort sets how much a reasoning model thinks before answering, for
+// providers and models that support it
+func (b *CoreRequestBuilder) WithReasoningEffort(effort ReasoningEffort) *CoreRequestBuilder {
+	b.request.ReasoningEffort = effort
+	return b
+}
+
+// WithStore asks the provider to store the completion, with the request's metadata,
+// for providers that support it
+func (b *CoreRequestBuilder) WithStore(store bool) *CoreRequestBuilder {
+	b.request.Store = &store
+	return b
+}
+
+// WithDegradationPolicy sets how features the provider or model does not support are
+// handled: DegradationStrict (the default) sends them as is, DegradationBestEffort
+// downgrades or drops them with a warning on the response
+func (b *CoreRequestBuilder) WithDegradationPolicy(policy DegradationPolicy) *CoreRequestBuilder {
+	b.request.DegradationPolicy = policy
+	return b
+}
+
 // WithResponseFormat sets the response format for the request
 func (b *CoreRequestBuilder) WithResponseFormat(format string) *CoreRequestBuilder {
 	b.request.ResponseFormat = format
@@ -216,6 +277,31 @@ func (b *CoreRequestBuilder) WithMetadata(key string, value interface{}) *CoreRe
 	return b
 }
 
+// WithModelFallbacks sets the models tried, in order, if the model is overloaded or not found
+func (b *CoreRequestBuilder) WithModelFallbacks(models []string) *CoreRequestBuilder {
+	b.request.ModelFallbacks = models
+	return b
+}
+
+// WithIdempotencyKey sets the idempotency key reused across retries of the request
+func (b *CoreRequestBuilder) WithIdempotencyKey(key string) *CoreRequestBuilder {
+	b.request.IdempotencyKey = key
+	return b
+}
+
+// WithTargetProvider pins the request to the named child of a virtual provider,
+// bypassing its strategy
+func (b *CoreRequestBuilder) WithTargetProvider(name string) *CoreRequestBuilder {
+	b.request.TargetProvider = name
+	return b
+}
+
+// WithProviderOptions sets provider-specific options, keyed by provider type
+func (b *CoreRequestBuilder) WithProviderOptions(options map[string]interface{}) *CoreRequestBuilder {
+	b.request.ProviderOptions = options
+	return b
+}
+
 // Build builds the standard request
 func (b *CoreRequestBuilder) Build() (*StandardRequest, error) {
 	// Validate the request
@@ -245,6 +331,20 @@ func (b *CoreRequestBuilder) validate() error {
 		return ErrInvalidMaxTokens
 	}
 
+	if b.request.N < 0 {
+		return ErrInvalidN
+	}
+
+	if err := validateReasoningEffort(b.request.ReasoningEffort); err != nil {
+		return err
+	}
+
+	switch b.request.DegradationPolicy {
+	case "", DegradationStrict, DegradationBestEffort:
+	default:
+		return NewValidationError(fmt.Sprintf("unknown degradation policy %q", b.request.DegradationPolicy))
+	}
+
 	// Validate tools and tool choice consistency
 	if len(b.request.Tools) == 0 && b.request.ToolChoice != nil {
 		return ErrToolChoiceWithoutTools
@@ -253,19 +353,36 @@ func (b *CoreRequestBuilder) validate() error {
 	return nil
 }
 
-// FromGenerateOptions converts from legacy GenerateOptions to StandardRequest
+// FromGenerateOptions converts from legacy GenerateOptions to StandardRequest. A bare
+// Prompt becomes a single user message, which providers treat the same way. The legacy
+// code generation inputs (Context, OutputFile, Language, ContextFiles) have no
+// equivalent and are not used for chat completions, so they are not carried over;
+// every other field survives a round trip through ToGenerateOptions.
 func (b *CoreRequestBuilder) FromGenerateOptions(options GenerateOptions) *CoreRequestBuilder {
-	b.WithMessages(options.Messages)
+	messages := options.Messages
+	if len(messages) == 0 && options.Prompt != "" {
+		messages = []ChatMessage{{Role: "user", Content: options.Prompt}}
+	}
+
+	b.WithMessages(messages)
 	b.WithModel(options.Model)
 	b.WithMaxTokens(options.MaxTokens)
 	b.WithTemperature(options.Temperature)
 	b.WithStop(options.Stop)
+	b.WithN(options.N)
 	b.WithStreaming(options.Stream)
 	b.WithTools(options.Tools)
 	b.WithToolChoice(options.ToolChoice)
+	b.request.ParallelToolCalls = options.ParallelToolCalls
+	b.request.Store = options.Store
+	b.request.ReasoningEffort = options.ReasoningEffort
 	b.WithResponseFormat(options.ResponseFormat)
 	b.WithContext(options.ContextObj)
 	b.WithTimeout(options.Timeout)
+	b.WithModelFallbacks(options.ModelFallbacks)
+	b.WithIdempotencyKey(options.IdempotencyKey)
+	b.WithTargetProvider(options.TargetProvider)
+	b.WithProviderOptions(options.ProviderOptions)
 
 	// Copy metadata
 	if options.Metadata != nil {
@@ -280,26 +397,94 @@ func (b *CoreRequestBuilder) FromGenerateOptions(options GenerateOptions) *CoreR
 // ToGenerateOptions converts from StandardRequest to legacy GenerateOptions
 func (r *StandardRequest) ToGenerateOptions() GenerateOptions {
 	return GenerateOptions{
-		Messages:       r.Messages,
-		Model:          r.Model,
-		MaxTokens:      r.MaxTokens,
-		Temperature:    r.Temperature,
-		Stop:           r.Stop,
-		Stream:         r.Stream,
-		Tools:          r.Tools,
-		ToolChoice:     r.ToolChoice,
-		ResponseFormat: r.ResponseFormat,
-		ContextObj:     r.Context,
-		Timeout:        r.Timeout,
-		Metadata:       r.Metadata,
+		Messages:          r.Messages,
+		Model:             r.Model,
+		MaxTokens:         r.MaxTokens,
+		Temperature:       r.Temperature,
+		Stop:              r.Stop,
+		N:                 r.N,
+		Stream:            r.Stream,
+		Tools:             r.Tools,
+		ToolChoice:        r.ToolChoice,
+		ParallelToolCalls: r.ParallelToolCalls,
+		ResponseFormat:    r.ResponseFormat,
+		ContextObj:        r.Context,
+		Timeout:           r.Timeout,
+		Metadata:          r.Metadata,
+		Store:             r.Store,
+		ReasoningEffort:   r.ReasoningEffort,
+		ModelFallbacks:    r.ModelFallbacks,
+		IdempotencyKey:    r.IdempotencyKey,
+		TargetProvider:    r.TargetProvider,
+		ProviderOptions:   r.ProviderOptions,
+	}
+}
+
+// Validate applies the rules of CoreRequestBuilder.Build to options constructed
+// directly, returning a *ValidationError: a prompt or at least one message, temperature
+// between 0 and 2, non-negative max tokens and n, a known reasoning effort, and a
+// tool_choice consistent with the declared tools. Model is not required, since providers fall back to their default.
+// Providers validate options before sending a request, so calling Validate is only
+// needed to catch errors earlier.
+func (o GenerateOptions) Validate() error {
+	// The legacy API accepts a bare prompt in place of messages
+	if len(o.Messages) == 0 && o.Prompt == "" {
+		return ErrNoMessages
+	}
+
+	if o.Temperature < 0 || o.Temperature > 2 {
+		return ErrInvalidTemperature
+	}
+
+	if o.MaxTokens < 0 {
+		return ErrInvalidMaxTokens
+	}
+
+	if o.N < 0 {
+		return ErrInvalidN
+	}
+
+	if err := validateReasoningEffort(o.ReasoningEffort); err != nil {
+		return err
+	}
+
+	return validateToolChoice(o.Tools, o.ToolChoice)
+}
+
+// validateToolChoice checks that tool_choice is consistent with the declared tools
+func validateToolChoice(tools []Tool, toolChoice *ToolChoice) error {
+	if toolChoice == nil {
+		return nil
+	}
+	if len(tools) == 0 {
+		return ErrToolChoiceWithoutTools
+	}
+
+	switch toolChoice.Mode {
+	case ToolChoiceAuto, ToolChoiceRequired, ToolChoiceNone:
+		return nil
+	case ToolChoiceSpecific:
+		if toolChoice.FunctionName == "" {
+			return NewValidationError("tool_choice mode 'specific' requires a function name")
+		}
+		for _, tool := range tools {
+			if tool.Name == toolChoice.FunctionName {
+				return nil
+			}
+		}
+		return NewValidationError(fmt.Sprintf("tool_choice function %q is not among the provided tools", toolChoice.FunctionName))
+	default:
+		return NewValidationError(fmt.Sprintf("invalid tool_choice mode %q", toolChoice.Mode))
 	}
 }
 
 // Common validation errors
 var (
 	ErrNoMessages             = NewValidationError("at least one message is required")
+	ErrNoModel                = NewValidationError("model is required")
 	ErrInvalidTemperature     = NewValidationError("temperature must be between 0 and 2")
 	ErrInvalidMaxTokens       = NewValidationError("max_tokens must be non-negative")
+	ErrInvalidN               = NewValidationError("n must be non-negative")
 	ErrToolChoiceWithoutTools = NewValidationError("tool_choice specified but no tools provided")
 )
 
@@ -318,6 +503,6 @@ func (e *ValidationError) Error() string {
 
 // IsValidationError checks if an error is a validation error
 func IsValidationError(err error) bool {
-	_, ok := err.(*ValidationError)
-	return ok
+	var target *ValidationError
+	return errors.As(err, &target)
 }
@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
@@ -589,3 +590,170 @@ func BenchmarkExtensionRegistryGet(b *testing.B) {
 		_, _ = registry.Get(ProviderTypeOpenAI)
 	}
 }
+
+func TestGenerateOptions_Validate(t *testing.T) {
+	tools := []Tool{{Name: "get_weather", Description: "Get the weather"}}
+
+	tests := []struct {
+		name    string
+		options GenerateOptions
+		wantErr error
+		message string
+	}{
+		{name: "prompt only", options: GenerateOptions{Prompt: "Hello"}},
+		{name: "messages", options: GenerateOptions{Messages: []ChatMessage{{Role: "user", Content: "Hello"}}, Temperature: 2, MaxTokens: 100}},
+		{name: "no content", options: GenerateOptions{Model: "gpt-4o"}, wantErr: ErrNoMessages},
+		{name: "temperature too high", options: GenerateOptions{Prompt: "Hello", Temperature: 2.5}, wantErr: ErrInvalidTemperature},
+		{name: "negative temperature", options: GenerateOptions{Prompt: "Hello", Temperature: -0.1}, wantErr: ErrInvalidTemperature},
+		{name: "negative max tokens", options: GenerateOptions{Prompt: "Hello", MaxTokens: -1}, wantErr: ErrInvalidMaxTokens},
+		{name: "tool choice without tools", options: GenerateOptions{Prompt: "Hello", ToolChoice: &ToolChoice{Mode: ToolChoiceAuto}}, wantErr: ErrToolChoiceWithoutTools},
+		{name: "specific tool", options: GenerateOptions{Prompt: "Hello", Tools: tools, ToolChoice: &ToolChoice{Mode: ToolChoiceSpecific, FunctionName: "get_weather"}}},
+		{name: "unknown specific tool", options: GenerateOptions{Prompt: "Hello", Tools: tools, ToolChoice: &ToolChoice{Mode: ToolChoiceSpecific, FunctionName: "get_time"}}, message: "not among the provided tools"},
+		{name: "invalid mode", options: GenerateOptions{Prompt: "Hello", Tools: tools, ToolChoice: &ToolChoice{Mode: "sometimes"}}, message: "invalid tool_choice mode"},
+		{name: "reasoning effort", options: GenerateOptions{Prompt: "Hello", ReasoningEffort: ReasoningEffortLow}},
+		{name: "invalid reasoning effort", options: GenerateOptions{Prompt: "Hello", ReasoningEffort: "extreme"}, message: "invalid reasoning effort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			switch {
+			case tt.wantErr != nil:
+				assert.Equal(t, tt.wantErr, err)
+			case tt.message != "":
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Contains(t, err.Error(), tt.message)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// The same rules as the builder
+	builderErr := NewCoreRequestBuilder().WithTemperature(3).WithMessages([]ChatMessage{{Role: "user", Content: "Hi"}}).validate()
+	assert.Equal(t, builderErr, GenerateOptions{Prompt: "Hi", Temperature: 3}.Validate())
+}
+
+// generateOptionsOnlyFields are the GenerateOptions fields StandardRequest does not
+// carry, see CoreRequestBuilder.FromGenerateOptions. Adding a field to GenerateOptions
+// requires mapping it in FromGenerateOptions and ToGenerateOptions or listing it here.
+var generateOptionsOnlyFields = map[string]bool{
+	"Prompt":       true, // Converted to a user message
+	"Context":      true, // Legacy code generation inputs
+	"OutputFile":   true,
+	"Language":     true,
+	"ContextFiles": true,
+}
+
+// standardRequestOnlyFields are the StandardRequest fields GenerateOptions does not
+// carry, since CoreProviderAdapter applies them before converting the request
+var standardRequestOnlyFields = map[string]bool{
+	"DegradationPolicy": true,
+}
+
+// fillNonZero sets every field reachable from v to a non-zero value
+func fillNonZero(t *testing.T, v reflect.Value, depth int) {
+	t.Helper()
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Int, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Interface:
+		if v.Type() == contextType {
+			v.Set(reflect.ValueOf(context.Background()))
+		} else {
+			v.Set(reflect.ValueOf("value"))
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillNonZero(t, v.Elem(), depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillNonZero(t, v.Index(0), depth+1)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillNonZero(t, key, depth+1)
+		fillNonZero(t, value, depth+1)
+		v.SetMapIndex(key, value)
+	case reflect.Struct:
+		if depth > 2 {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillNonZero(t, v.Field(i), depth+1)
+			}
+		}
+	default:
+		t.Fatalf("fillNonZero: unsupported kind %s", v.Kind())
+	}
+}
+
+func TestGenerateOptionsRoundTrip(t *testing.T) {
+	t.Run("GenerateOptions to StandardRequest and back", func(t *testing.T) {
+		var options GenerateOptions
+		fillNonZero(t, reflect.ValueOf(&options).Elem(), 0)
+		options.ToolChoice = &ToolChoice{Mode: ToolChoiceAuto}
+		options.ReasoningEffort = ReasoningEffortHigh
+
+		request, err := NewCoreRequestBuilder().FromGenerateOptions(options).Build()
+		require.NoError(t, err)
+		roundTripped := request.ToGenerateOptions()
+
+		original := reflect.ValueOf(options)
+		result := reflect.ValueOf(roundTripped)
+		for i := 0; i < original.NumField(); i++ {
+			name := original.Type().Field(i).Name
+			if generateOptionsOnlyFields[name] {
+				continue
+			}
+			assert.True(t, reflect.DeepEqual(original.Field(i).Interface(), result.Field(i).Interface()),
+				"GenerateOptions.%s is lost converting to StandardRequest and back", name)
+		}
+	})
+
+	t.Run("StandardRequest to GenerateOptions and back", func(t *testing.T) {
+		var request StandardRequest
+		fillNonZero(t, reflect.ValueOf(&request).Elem(), 0)
+		request.ToolChoice = &ToolChoice{Mode: ToolChoiceAuto}
+		request.ReasoningEffort = ReasoningEffortHigh
+
+		roundTripped, err := NewCoreRequestBuilder().FromGenerateOptions(request.ToGenerateOptions()).Build()
+		require.NoError(t, err)
+
+		original := reflect.ValueOf(request)
+		result := reflect.ValueOf(*roundTripped)
+		for i := 0; i < original.NumField(); i++ {
+			name := original.Type().Field(i).Name
+			if standardRequestOnlyFields[name] {
+				continue
+			}
+			assert.True(t, reflect.DeepEqual(original.Field(i).Interface(), result.Field(i).Interface()),
+				"StandardRequest.%s is lost converting to GenerateOptions and back", name)
+		}
+	})
+
+	t.Run("excluded fields exist", func(t *testing.T) {
+		optionsType := reflect.TypeOf(GenerateOptions{})
+		for name := range generateOptionsOnlyFields {
+			_, ok := optionsType.FieldByName(name)
+			assert.True(t, ok, "generateOptionsOnlyFields lists unknown field %s", name)
+		}
+	})
+
+	t.Run("prompt becomes a user message", func(t *testing.T) {
+		request, err := NewCoreRequestBuilder().FromGenerateOptions(GenerateOptions{Prompt: "Hello"}).Build()
+		require.NoError(t, err)
+		assert.Equal(t, []ChatMessage{{Role: "user", Content: "Hello"}}, request.Messages)
+	})
+}
@@ -59,7 +59,7 @@ func (a *CoreProviderAdapter) GenerateStandardCompletion(ctx context.Context, re
 	}
 
 	// Convert to legacy GenerateOptions
-	generateOptions := a.convertToLegacyOptions(request)
+	generateOptions, warnings := a.convertToLegacyOptions(request)
 
 	// Execute using the provider's existing interface
 	stream, err := a.provider.GenerateChatCompletion(ctx, generateOptions)
@@ -89,6 +89,12 @@ func (a *CoreProviderAdapter) GenerateStandardCompletion(ctx context.Context, re
 	if err != nil {
 		return nil, err
 	}
+	if standardResponse.Warnings == nil {
+		standardResponse.Warnings = finalChunk.Warnings
+	}
+	if len(warnings) > 0 {
+		standardResponse.Warnings = append(warnings, standardResponse.Warnings...)
+	}
 
 	return standardResponse, nil
 }
@@ -101,7 +107,7 @@ func (a *CoreProviderAdapter) GenerateStandardStream(ctx context.Context, reques
 	}
 
 	// Convert to legacy GenerateOptions
-	generateOptions := a.convertToLegacyOptions(request)
+	generateOptions, warnings := a.convertToLegacyOptions(request)
 
 	// Execute using the provider's existing interface
 	stream, err := a.provider.GenerateChatCompletion(ctx, generateOptions)
@@ -113,6 +119,7 @@ func (a *CoreProviderAdapter) GenerateStandardStream(ctx context.Context, reques
 	return &StandardStreamAdapter{
 		providerStream: stream,
 		extension:      a.extension,
+		warnings:       warnings,
 	}, nil
 }
 
@@ -137,22 +144,14 @@ func (a *CoreProviderAdapter) ValidateStandardRequest(request StandardRequest) e
 	return a.extension.ValidateOptions(request.Metadata)
 }
 
-// convertToLegacyOptions converts a standard request to legacy GenerateOptions
-func (a *CoreProviderAdapter) convertToLegacyOptions(request StandardRequest) GenerateOptions {
-	return GenerateOptions{
-		Messages:       request.Messages,
-		Model:          request.Model,
-		MaxTokens:      request.MaxTokens,
-		Temperature:    request.Temperature,
-		Stop:           request.Stop,
-		Stream:         request.Stream,
-		Tools:          request.Tools,
-		ToolChoice:     request.ToolChoice,
-		ResponseFormat: request.ResponseFormat,
-		ContextObj:     request.Context,
-		Timeout:        request.Timeout,
-		Metadata:       request.Metadata,
+// convertToLegacyOptions converts a standard request to legacy GenerateOptions,
+// degrading unsupported features under DegradationBestEffort
+func (a *CoreProviderAdapter) convertToLegacyOptions(request StandardRequest) (GenerateOptions, []Warning) {
+	options := request.ToGenerateOptions()
+	if request.DegradationPolicy != DegradationBestEffort {
+		return options, nil
 	}
+	return DegradeOptions(a.provider, options)
 }
 
 // StandardStreamAdapter adapts legacy streams to the standard stream interface
@@ -161,6 +160,7 @@ type StandardStreamAdapter struct {
 	extension      CoreProviderExtension
 	done           bool
 	lastChunk      *StandardStreamChunk
+	warnings       []Warning // Added to the first chunk
 }
 
 // Next returns the next chunk from the stream
@@ -184,6 +184,10 @@ func (s *StandardStreamAdapter) Next() (*StandardStreamChunk, error) {
 	if err != nil {
 		return nil, err
 	}
+	if s.warnings != nil {
+		standardChunk.Warnings = append(s.warnings, standardChunk.Warnings...)
+		s.warnings = nil
+	}
 
 	s.lastChunk = standardChunk
 	return standardChunk, nil
@@ -147,7 +147,8 @@ func TestCoreProviderAdapter(t *testing.T) {
 			Metadata:       map[string]interface{}{"key": "value"},
 		}
 
-		options := adapter.convertToLegacyOptions(request)
+		options, warnings := adapter.convertToLegacyOptions(request)
+		assert.Nil(t, warnings, "strict is the default")
 		assert.Equal(t, request.Messages, options.Messages)
 		assert.Equal(t, request.Model, options.Model)
 		assert.Equal(t, request.MaxTokens, options.MaxTokens)
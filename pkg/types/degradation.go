@@ -0,0 +1,105 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DegradationPolicy selects what a CoreProviderAdapter does with request features the
+// provider or model does not support
+type DegradationPolicy string
+
+const (
+	// DegradationStrict sends the request as built, so unsupported features fail as
+	// the provider reports them. It is the default.
+	DegradationStrict DegradationPolicy = "strict"
+
+	// DegradationBestEffort replaces unsupported features with the closest supported
+	// ones, or drops them, and records each change as a Warning on the response. See
+	// DegradeOptions.
+	DegradationBestEffort DegradationPolicy = "best_effort"
+)
+
+// DegradeOptions adapts options to what provider supports for the requested model,
+// returning the adapted options and a warning for each change:
+//
+//   - a JSON schema ResponseFormat becomes "json_object" when JSON schema output is
+//     unsupported (WarningDegraded)
+//   - Tools and ToolChoice are dropped when tool calling is unsupported
+//     (WarningParameterIgnored)
+//   - ParallelToolCalls, Store and ReasoningEffort are dropped when the model does
+//     not accept them (WarningParameterIgnored)
+//   - N is reduced to 1 when the model returns a single choice (WarningDegraded)
+//
+// Feature support is taken from ProviderCapabilities and parameter support from
+// SupportedParameters. The caller's options are not modified.
+func DegradeOptions(provider Provider, options GenerateOptions) (GenerateOptions, []Warning) {
+	capabilities := ProviderCapabilities(provider, options.Model)
+	support := SupportedParameters(provider, options.Model)
+	providerType := provider.Type()
+	var warnings []Warning
+
+	if isJSONSchemaFormat(options.ResponseFormat) && !capabilities.JSONSchema {
+		options.ResponseFormat = "json_object"
+		warnings = append(warnings, Warning{
+			Code:    WarningDegraded,
+			Message: fmt.Sprintf("provider %s does not support JSON schema output, using json_object without the schema", providerType),
+			Param:   "response_format",
+		})
+	}
+
+	if len(options.Tools) > 0 && !capabilities.Tools {
+		options.Tools = nil
+		options.ToolChoice = nil
+		warnings = append(warnings, Warning{
+			Code:    WarningParameterIgnored,
+			Message: fmt.Sprintf("provider %s does not support tool calling, tools were dropped", providerType),
+			Param:   "tools",
+		})
+	}
+
+	if options.ParallelToolCalls != nil && !support.ParallelToolCalls {
+		options.ParallelToolCalls = nil
+		warnings = append(warnings, Warning{
+			Code:    WarningParameterIgnored,
+			Message: fmt.Sprintf("provider %s does not support parallel_tool_calls", providerType),
+			Param:   "parallel_tool_calls",
+		})
+	}
+
+	if options.Store != nil && !support.Store {
+		options.Store = nil
+		warnings = append(warnings, Warning{
+			Code:    WarningParameterIgnored,
+			Message: fmt.Sprintf("provider %s does not support store", providerType),
+			Param:   "store",
+		})
+	}
+
+	if options.ReasoningEffort != "" && !support.ReasoningEffort {
+		options.ReasoningEffort = ""
+		warnings = append(warnings, Warning{
+			Code:    WarningParameterIgnored,
+			Message: fmt.Sprintf("provider %s does not support reasoning_effort", providerType),
+			Param:   "reasoning_effort",
+		})
+	}
+
+	if options.N > 1 && !support.N {
+		warnings = append(warnings, Warning{
+			Code:    WarningDegraded,
+			Message: fmt.Sprintf("provider %s generates one choice per request, not %d", providerType, options.N),
+			Param:   "n",
+		})
+		options.N = 1
+	}
+
+	return options, warnings
+}
+
+// isJSONSchemaFormat reports whether responseFormat is a JSON schema rather than a
+// format name such as "json_object"
+func isJSONSchemaFormat(responseFormat string) bool {
+	var schema map[string]interface{}
+	return responseFormat != "" && json.Unmarshal([]byte(responseFormat), &schema) == nil
+}
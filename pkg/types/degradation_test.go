@@ -0,0 +1,155 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitedProvider reports fixed capabilities and records the options it receives
+type limitedProvider struct {
+	mockStreamProvider
+	capabilities Capabilities
+	received     GenerateOptions
+}
+
+func (p *limitedProvider) Capabilities(string) Capabilities { return p.capabilities }
+
+func (p *limitedProvider) GenerateChatCompletion(ctx context.Context, options GenerateOptions) (ChatCompletionStream, error) {
+	p.received = options
+	return p.mockStreamProvider.GenerateChatCompletion(ctx, options)
+}
+
+func newLimitedProvider(providerType ProviderType, capabilities Capabilities) *limitedProvider {
+	return &limitedProvider{
+		mockStreamProvider: mockStreamProvider{MockProvider: MockProvider{name: "limited", providerType: providerType}},
+		capabilities:       capabilities,
+	}
+}
+
+func TestDegradeOptions(t *testing.T) {
+	disabled := false
+	options := GenerateOptions{
+		Prompt:            "hi",
+		ResponseFormat:    `{"type":"object"}`,
+		Tools:             []Tool{{Name: "search"}},
+		ToolChoice:        &ToolChoice{Mode: ToolChoiceAuto},
+		ParallelToolCalls: &disabled,
+		Store:             &disabled,
+		ReasoningEffort:   ReasoningEffortHigh,
+		N:                 3,
+	}
+
+	t.Run("downgrades unsupported features", func(t *testing.T) {
+		provider := &parameterProvider{
+			limitedProvider: *newLimitedProvider(ProviderTypeAnthropic, Capabilities{Streaming: true}),
+			support:         ParameterSupport{Temperature: true},
+		}
+
+		degraded, warnings := DegradeOptions(provider, options)
+		assert.Equal(t, "json_object", degraded.ResponseFormat)
+		assert.Nil(t, degraded.Tools)
+		assert.Nil(t, degraded.ToolChoice)
+		assert.Nil(t, degraded.ParallelToolCalls)
+		assert.Nil(t, degraded.Store)
+		assert.Empty(t, degraded.ReasoningEffort)
+		assert.Equal(t, 1, degraded.N)
+
+		require.Len(t, warnings, 6)
+		params := make([]string, len(warnings))
+		for i, warning := range warnings {
+			params[i] = warning.Param
+		}
+		assert.Equal(t, []string{"response_format", "tools", "parallel_tool_calls", "store", "reasoning_effort", "n"}, params)
+		assert.Equal(t, WarningDegraded, warnings[0].Code)
+		assert.Equal(t, WarningParameterIgnored, warnings[1].Code)
+
+		assert.Equal(t, `{"type":"object"}`, options.ResponseFormat, "the caller's options must not change")
+	})
+
+	t.Run("keeps supported features", func(t *testing.T) {
+		provider := newLimitedProvider(ProviderTypeOpenAI, Capabilities{Tools: true, JSONSchema: true, Streaming: true})
+
+		degraded, warnings := DegradeOptions(provider, options)
+		assert.Empty(t, warnings)
+		assert.Equal(t, options, degraded)
+	})
+
+	t.Run("follows the model's parameter support", func(t *testing.T) {
+		support := AllParametersSupported
+		support.ReasoningEffort = false
+		provider := &parameterProvider{
+			limitedProvider: *newLimitedProvider(ProviderTypeOpenAI, Capabilities{Tools: true, JSONSchema: true, Streaming: true}),
+			support:         support,
+		}
+
+		degraded, warnings := DegradeOptions(provider, options)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "reasoning_effort", warnings[0].Param)
+		assert.Empty(t, degraded.ReasoningEffort)
+		assert.Equal(t, 3, degraded.N)
+	})
+
+	t.Run("leaves named formats", func(t *testing.T) {
+		provider := newLimitedProvider(ProviderTypeOpenAI, Capabilities{})
+
+		degraded, warnings := DegradeOptions(provider, GenerateOptions{ResponseFormat: "json_object"})
+		assert.Empty(t, warnings)
+		assert.Equal(t, "json_object", degraded.ResponseFormat)
+	})
+}
+
+func TestCoreProviderAdapter_DegradationPolicy(t *testing.T) {
+	extension := &mockExtension{BaseExtension: NewBaseExtension("test", "1.0.0", "Test", []string{"chat"})}
+	builder := func() *CoreRequestBuilder {
+		return NewCoreRequestBuilder().
+			WithMessages([]ChatMessage{{Role: "user", Content: "Hello"}}).
+			WithResponseFormat(`{"type":"object"}`)
+	}
+
+	t.Run("strict sends the request as built", func(t *testing.T) {
+		provider := newLimitedProvider(ProviderTypeGemini, Capabilities{})
+		request, err := builder().Build()
+		require.NoError(t, err)
+
+		response, err := NewCoreProviderAdapter(provider, extension).GenerateStandardCompletion(context.Background(), *request)
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"object"}`, provider.received.ResponseFormat)
+		assert.Empty(t, response.Warnings)
+	})
+
+	t.Run("best effort records downgrades on the response", func(t *testing.T) {
+		provider := newLimitedProvider(ProviderTypeGemini, Capabilities{})
+		request, err := builder().WithDegradationPolicy(DegradationBestEffort).Build()
+		require.NoError(t, err)
+
+		response, err := NewCoreProviderAdapter(provider, extension).GenerateStandardCompletion(context.Background(), *request)
+		require.NoError(t, err)
+		assert.Equal(t, "json_object", provider.received.ResponseFormat)
+		require.Len(t, response.Warnings, 1)
+		assert.Equal(t, "response_format", response.Warnings[0].Param)
+	})
+
+	t.Run("best effort warns on the first stream chunk", func(t *testing.T) {
+		provider := newLimitedProvider(ProviderTypeGemini, Capabilities{})
+		request, err := builder().WithDegradationPolicy(DegradationBestEffort).WithStreaming(true).Build()
+		require.NoError(t, err)
+
+		stream, err := NewCoreProviderAdapter(provider, extension).GenerateStandardStream(context.Background(), *request)
+		require.NoError(t, err)
+		first, err := stream.Next()
+		require.NoError(t, err)
+		require.Len(t, first.Warnings, 1)
+		second, err := stream.Next()
+		require.NoError(t, err)
+		assert.Empty(t, second.Warnings)
+	})
+
+	t.Run("unknown policy is rejected", func(t *testing.T) {
+		_, err := builder().WithDegradationPolicy("lenient").Build()
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Concrete error categories for use with errors.As.
+//
+// Providers return *ProviderError; each category below is a view of a
+// ProviderError with the matching Code. ProviderError implements As, so any
+// provider error (even when wrapped) can be matched by category:
+//
+//	var rateLimitErr *types.RateLimitError
+//	if errors.As(err, &rateLimitErr) {
+//	    time.Sleep(rateLimitErr.RetryAfter)
+//	}
+//
+// Each category embeds the original *ProviderError, so Provider, StatusCode,
+// OriginalErr and the other fields remain available, and Unwrap still reaches
+// the underlying cause (e.g. context.DeadlineExceeded for timeouts).
+
+// NetworkError is a ProviderError with code ErrCodeNetwork
+type NetworkError struct {
+	*ProviderError
+}
+
+// AuthenticationError is a ProviderError with code ErrCodeAuthentication
+type AuthenticationError struct {
+	*ProviderError
+}
+
+// RateLimitError is a ProviderError with code ErrCodeRateLimit
+type RateLimitError struct {
+	*ProviderError
+
+	// RetryAfter is how long to wait before retrying (zero if the provider gave no hint).
+	// It shadows the embedded ProviderError.RetryAfter seconds field.
+	RetryAfter time.Duration
+}
+
+// TimeoutError is a ProviderError with code ErrCodeTimeout
+type TimeoutError struct {
+	*ProviderError
+}
+
+// Unwrap returns the embedded ProviderError
+func (e *NetworkError) Unwrap() error { return e.ProviderError }
+
+// Unwrap returns the embedded ProviderError
+func (e *AuthenticationError) Unwrap() error { return e.ProviderError }
+
+// Unwrap returns the embedded ProviderError
+func (e *RateLimitError) Unwrap() error { return e.ProviderError }
+
+// Unwrap returns the embedded ProviderError
+func (e *TimeoutError) Unwrap() error { return e.ProviderError }
+
+// As allows errors.As to match a ProviderError against the concrete category
+// types (NetworkError, AuthenticationError, RateLimitError, TimeoutError)
+func (e *ProviderError) As(target interface{}) bool {
+	switch t := target.(type) {
+	case **NetworkError:
+		if e.Code == ErrCodeNetwork {
+			*t = &NetworkError{ProviderError: e}
+			return true
+		}
+	case **AuthenticationError:
+		if e.Code == ErrCodeAuthentication {
+			*t = &AuthenticationError{ProviderError: e}
+			return true
+		}
+	case **RateLimitError:
+		if e.Code == ErrCodeRateLimit {
+			*t = &RateLimitError{
+				ProviderError: e,
+				RetryAfter:    time.Duration(e.RetryAfter) * time.Second,
+			}
+			return true
+		}
+	case **TimeoutError:
+		if e.Code == ErrCodeTimeout {
+			*t = &TimeoutError{ProviderError: e}
+			return true
+		}
+	}
+	return false
+}
+
+// NewRequestError classifies an error returned while sending a request (e.g. from
+// http.Client.Do) as a timeout or network error, wrapping the original error
+func NewRequestError(provider ProviderType, message string, err error) *ProviderError {
+	code := ErrCodeNetwork
+	if isTimeout(err) {
+		code = ErrCodeTimeout
+	}
+	if err != nil {
+		message = fmt.Sprintf("%s: %v", message, err)
+	}
+	return NewProviderError(provider, code, message).WithOriginalErr(err)
+}
+
+// isTimeout reports whether err represents a deadline or network timeout
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsNetworkError checks if an error is (or wraps) a network error
+func IsNetworkError(err error) bool {
+	var target *NetworkError
+	return errors.As(err, &target)
+}
+
+// IsAuthenticationError checks if an error is (or wraps) an authentication error
+func IsAuthenticationError(err error) bool {
+	var target *AuthenticationError
+	return errors.As(err, &target)
+}
+
+// IsRateLimitError checks if an error is (or wraps) a rate limit error
+func IsRateLimitError(err error) bool {
+	var target *RateLimitError
+	return errors.As(err, &target)
+}
+
+// IsTimeoutError checks if an error is (or wraps) a timeout error
+func IsTimeoutError(err error) bool {
+	var target *TimeoutError
+	return errors.As(err, &target)
+}
@@ -0,0 +1,137 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeoutNetError is a net.Error that reports a timeout
+type timeoutNetError struct{}
+
+func (timeoutNetError) Error() string   { return "i/o timeout" }
+func (timeoutNetError) Timeout() bool   { return true }
+func (timeoutNetError) Temporary() bool { return true }
+
+var _ net.Error = timeoutNetError{}
+
+func TestErrorCategories_ErrorsAs(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   *ProviderError
+		check func(t *testing.T, err error)
+	}{
+		{
+			name: "network",
+			err:  NewNetworkError(ProviderTypeOpenAI, "connection refused"),
+			check: func(t *testing.T, err error) {
+				var target *NetworkError
+				require.True(t, errors.As(err, &target))
+				assert.Equal(t, ProviderTypeOpenAI, target.Provider)
+				assert.True(t, IsNetworkError(err))
+			},
+		},
+		{
+			name: "authentication",
+			err:  NewAuthError(ProviderTypeAnthropic, "invalid key").WithStatusCode(401),
+			check: func(t *testing.T, err error) {
+				var target *AuthenticationError
+				require.True(t, errors.As(err, &target))
+				assert.Equal(t, 401, target.StatusCode)
+				assert.True(t, IsAuthenticationError(err))
+			},
+		},
+		{
+			name: "rate limit",
+			err:  NewRateLimitError(ProviderTypeGemini, 30),
+			check: func(t *testing.T, err error) {
+				var target *RateLimitError
+				require.True(t, errors.As(err, &target))
+				assert.Equal(t, 30*time.Second, target.RetryAfter)
+				assert.Equal(t, 30, target.ProviderError.RetryAfter)
+				assert.True(t, IsRateLimitError(err))
+			},
+		},
+		{
+			name: "timeout",
+			err:  NewTimeoutError(ProviderTypeOllama, "deadline exceeded"),
+			check: func(t *testing.T, err error) {
+				var target *TimeoutError
+				require.True(t, errors.As(err, &target))
+				assert.True(t, IsTimeoutError(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, tt.err)
+
+			// Matching must also work through additional wrapping
+			tt.check(t, fmt.Errorf("openai: all API keys failed, last error: %w", tt.err))
+
+			// The category view still matches as a ProviderError
+			var providerErr *ProviderError
+			assert.True(t, errors.As(tt.err, &providerErr))
+		})
+	}
+}
+
+func TestErrorCategories_NoCrossMatch(t *testing.T) {
+	err := NewRateLimitError(ProviderTypeOpenAI, 10)
+
+	assert.False(t, IsNetworkError(err))
+	assert.False(t, IsAuthenticationError(err))
+	assert.False(t, IsTimeoutError(err))
+	assert.False(t, IsRateLimitError(errors.New("plain error")))
+	assert.False(t, IsRateLimitError(nil))
+}
+
+func TestNewRequestError(t *testing.T) {
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		cause := fmt.Errorf("Post \"https://api.openai.com\": %w", context.DeadlineExceeded)
+		err := NewRequestError(ProviderTypeOpenAI, "request failed", cause)
+
+		assert.Equal(t, ErrCodeTimeout, err.Code)
+		assert.True(t, IsTimeoutError(err))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		// The timeout view must keep the cause reachable
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &timeoutErr))
+		assert.True(t, errors.Is(timeoutErr, context.DeadlineExceeded))
+	})
+
+	t.Run("NetTimeout", func(t *testing.T) {
+		err := NewRequestError(ProviderTypeAnthropic, "request failed", timeoutNetError{})
+		assert.Equal(t, ErrCodeTimeout, err.Code)
+	})
+
+	t.Run("Network", func(t *testing.T) {
+		cause := errors.New("connection refused")
+		err := NewRequestError(ProviderTypeGemini, "request failed", cause)
+
+		assert.Equal(t, ErrCodeNetwork, err.Code)
+		assert.True(t, IsNetworkError(err))
+		assert.ErrorIs(t, err, cause)
+		assert.Contains(t, err.Error(), "request failed: connection refused")
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		err := NewRequestError(ProviderTypeOpenAI, "request failed", context.Canceled)
+		assert.Equal(t, ErrCodeNetwork, err.Code)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestIsValidationError_Wrapped(t *testing.T) {
+	assert.True(t, IsValidationError(ErrNoMessages))
+	assert.True(t, IsValidationError(fmt.Errorf("invalid request: %w", ErrNoMessages)))
+	assert.False(t, IsValidationError(NewNetworkError(ProviderTypeOpenAI, "x")))
+}
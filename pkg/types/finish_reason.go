@@ -0,0 +1,106 @@
+package types
+
+import "strings"
+
+// Normalized finish reasons. Providers report these in the FinishReason fields of
+// choices, whatever value the provider's API used; the provider's own value is kept
+// in RawFinishReason.
+const (
+	// FinishStop means the model finished its turn or hit a stop sequence
+	FinishStop = "stop"
+
+	// FinishLength means generation was cut off by max_tokens or the context window
+	FinishLength = "length"
+
+	// FinishToolCalls means the model stopped to call tools
+	FinishToolCalls = "tool_calls"
+
+	// FinishContentFilter means output was withheld or stopped by a safety filter
+	FinishContentFilter = "content_filter"
+
+	// FinishError means generation stopped abnormally, e.g. a malformed function call
+	FinishError = "error"
+)
+
+// finishReasonTables maps provider-specific finish reasons to the normalized values.
+// Keys are lowercase. Providers without a table use the OpenAI table.
+var finishReasonTables = map[ProviderType]map[string]string{
+	ProviderTypeOpenAI: {
+		"stop":           FinishStop,
+		"length":         FinishLength,
+		"tool_calls":     FinishToolCalls,
+		"function_call":  FinishToolCalls,
+		"content_filter": FinishContentFilter,
+	},
+	ProviderTypeAnthropic: {
+		"end_turn":                      FinishStop,
+		"stop_sequence":                 FinishStop,
+		"pause_turn":                    FinishStop,
+		"max_tokens":                    FinishLength,
+		"model_context_window_exceeded": FinishLength,
+		"tool_use":                      FinishToolCalls,
+		"refusal":                       FinishContentFilter,
+	},
+	ProviderTypeGemini: {
+		"stop":                      FinishStop,
+		"max_tokens":                FinishLength,
+		"safety":                    FinishContentFilter,
+		"recitation":                FinishContentFilter,
+		"blocklist":                 FinishContentFilter,
+		"prohibited_content":        FinishContentFilter,
+		"spii":                      FinishContentFilter,
+		"image_safety":              FinishContentFilter,
+		"language":                  FinishError,
+		"malformed_function_call":   FinishError,
+		"unexpected_tool_call":      FinishError,
+		"other":                     FinishError,
+		"finish_reason_unspecified": FinishError,
+	},
+	ProviderTypeOllama: {
+		"stop":   FinishStop,
+		"length": FinishLength,
+		"load":   FinishStop,
+		"unload": FinishStop,
+	},
+}
+
+// commonFinishReasons covers values shared by OpenAI-compatible APIs and values the
+// kit itself has reported, for providers whose table lacks them
+var commonFinishReasons = map[string]string{
+	"stop":             FinishStop,
+	"eos":              FinishStop,
+	"end_turn":         FinishStop,
+	"stop_sequence":    FinishStop,
+	"length":           FinishLength,
+	"max_tokens":       FinishLength,
+	"model_length":     FinishLength,
+	"tool_calls":       FinishToolCalls,
+	"tool_use":         FinishToolCalls,
+	"function_call":    FinishToolCalls,
+	"content_filter":   FinishContentFilter,
+	"content_filtered": FinishContentFilter,
+	"safety":           FinishContentFilter,
+	"error":            FinishError,
+}
+
+// NormalizeFinishReason maps a provider's finish reason to one of FinishStop,
+// FinishLength, FinishToolCalls, FinishContentFilter or FinishError. An empty reason
+// (a stream chunk before the last) stays empty; unknown values map to FinishStop.
+func NormalizeFinishReason(provider ProviderType, raw string) string {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if key == "" || key == "null" {
+		return ""
+	}
+
+	table, ok := finishReasonTables[provider]
+	if !ok {
+		table = finishReasonTables[ProviderTypeOpenAI]
+	}
+	if reason, ok := table[key]; ok {
+		return reason
+	}
+	if reason, ok := commonFinishReasons[key]; ok {
+		return reason
+	}
+	return FinishStop
+}
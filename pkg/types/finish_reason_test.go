@@ -0,0 +1,96 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeFinishReason(t *testing.T) {
+	tests := []struct {
+		provider ProviderType
+		raw      string
+		expected string
+	}{
+		// OpenAI and OpenAI-compatible APIs
+		{ProviderTypeOpenAI, "stop", FinishStop},
+		{ProviderTypeOpenAI, "length", FinishLength},
+		{ProviderTypeOpenAI, "tool_calls", FinishToolCalls},
+		{ProviderTypeOpenAI, "function_call", FinishToolCalls},
+		{ProviderTypeOpenAI, "content_filter", FinishContentFilter},
+		{ProviderTypeOpenRouter, "stop", FinishStop},
+		{ProviderTypeOpenRouter, "length", FinishLength},
+		{ProviderTypeOpenRouter, "tool_calls", FinishToolCalls},
+		{ProviderTypeOpenRouter, "error", FinishError},
+		{ProviderTypeCerebras, "stop", FinishStop},
+		{ProviderTypeCerebras, "length", FinishLength},
+		{ProviderTypeQwen, "tool_calls", FinishToolCalls},
+		{ProviderTypeQwen, "length", FinishLength},
+		{ProviderTypeMistral, "model_length", FinishLength},
+		{ProviderTypeLlamaCpp, "eos", FinishStop},
+
+		// Anthropic
+		{ProviderTypeAnthropic, "end_turn", FinishStop},
+		{ProviderTypeAnthropic, "stop_sequence", FinishStop},
+		{ProviderTypeAnthropic, "pause_turn", FinishStop},
+		{ProviderTypeAnthropic, "max_tokens", FinishLength},
+		{ProviderTypeAnthropic, "model_context_window_exceeded", FinishLength},
+		{ProviderTypeAnthropic, "tool_use", FinishToolCalls},
+		{ProviderTypeAnthropic, "refusal", FinishContentFilter},
+
+		// Gemini
+		{ProviderTypeGemini, "STOP", FinishStop},
+		{ProviderTypeGemini, "MAX_TOKENS", FinishLength},
+		{ProviderTypeGemini, "SAFETY", FinishContentFilter},
+		{ProviderTypeGemini, "RECITATION", FinishContentFilter},
+		{ProviderTypeGemini, "BLOCKLIST", FinishContentFilter},
+		{ProviderTypeGemini, "PROHIBITED_CONTENT", FinishContentFilter},
+		{ProviderTypeGemini, "SPII", FinishContentFilter},
+		{ProviderTypeGemini, "IMAGE_SAFETY", FinishContentFilter},
+		{ProviderTypeGemini, "LANGUAGE", FinishError},
+		{ProviderTypeGemini, "MALFORMED_FUNCTION_CALL", FinishError},
+		{ProviderTypeGemini, "UNEXPECTED_TOOL_CALL", FinishError},
+		{ProviderTypeGemini, "OTHER", FinishError},
+		{ProviderTypeGemini, "FINISH_REASON_UNSPECIFIED", FinishError},
+
+		// Ollama
+		{ProviderTypeOllama, "stop", FinishStop},
+		{ProviderTypeOllama, "length", FinishLength},
+		{ProviderTypeOllama, "load", FinishStop},
+
+		// Values previously reported by the kit
+		{ProviderTypeGemini, "content_filtered", FinishContentFilter},
+
+		// No finish reason yet, and unknown values
+		{ProviderTypeOpenAI, "", ""},
+		{ProviderTypeOpenAI, "null", ""},
+		{ProviderTypeAnthropic, "", ""},
+		{ProviderTypeOpenAI, "something_new", FinishStop},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider)+"/"+tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeFinishReason(tt.provider, tt.raw))
+		})
+	}
+}
+
+func TestNormalizeFinishReason_FixedEnum(t *testing.T) {
+	allowed := map[string]bool{
+		FinishStop:          true,
+		FinishLength:        true,
+		FinishToolCalls:     true,
+		FinishContentFilter: true,
+		FinishError:         true,
+	}
+
+	for provider, table := range finishReasonTables {
+		for raw := range table {
+			reason := NormalizeFinishReason(provider, raw)
+			assert.True(t, allowed[reason], "%s %q normalized to %q", provider, raw, reason)
+		}
+	}
+	for raw := range commonFinishReasons {
+		assert.True(t, allowed[NormalizeFinishReason(ProviderTypeOpenAI, raw)], "%q", raw)
+	}
+}
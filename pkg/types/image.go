@@ -0,0 +1,46 @@
+package types
+
+import "context"
+
+// ImageResponseFormat represents how generated images are returned
+type ImageResponseFormat string
+
+const (
+	ImageResponseFormatURL     ImageResponseFormat = "url"      // Hosted URL (short-lived)
+	ImageResponseFormatB64JSON ImageResponseFormat = "b64_json" // Base64-encoded image data
+)
+
+// ImageRequest represents a request to generate one or more images
+type ImageRequest struct {
+	Prompt         string                 `json:"prompt"`
+	Model          string                 `json:"model,omitempty"`           // e.g. "dall-e-3", "gpt-image-1"
+	Size           string                 `json:"size,omitempty"`            // e.g. "1024x1024"
+	N              int                    `json:"n,omitempty"`               // Number of images (0 means provider default)
+	ResponseFormat ImageResponseFormat    `json:"response_format,omitempty"` // "url" or "b64_json"
+	Quality        string                 `json:"quality,omitempty"`         // e.g. "standard", "hd", "high"
+	Style          string                 `json:"style,omitempty"`           // e.g. "vivid", "natural" (DALL·E 3)
+	User           string                 `json:"user,omitempty"`            // End-user identifier for abuse monitoring
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ImageData represents a single generated image
+type ImageData struct {
+	URL           string `json:"url,omitempty"`            // Set when the URL format was requested
+	B64JSON       string `json:"b64_json,omitempty"`       // Set when the b64_json format was requested
+	RevisedPrompt string `json:"revised_prompt,omitempty"` // Prompt as rewritten by the provider, if any
+}
+
+// ImageResponse represents the result of an image generation request
+type ImageResponse struct {
+	Created int64       `json:"created"`
+	Model   string      `json:"model,omitempty"`
+	Data    []ImageData `json:"data"`
+	Usage   *Usage      `json:"usage,omitempty"` // Token usage where the provider reports it (e.g. gpt-image-1)
+}
+
+// ImageProvider defines the image generation capability.
+// This interface is separate from ChatProvider; providers that can generate
+// images implement it in addition to Provider.
+type ImageProvider interface {
+	GenerateImage(ctx context.Context, req ImageRequest) (*ImageResponse, error)
+}
@@ -33,6 +33,19 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// Prompt tokens read from and written to the provider's prompt cache, for
+	// providers that report them. Both are included in PromptTokens.
+	CacheReadTokens     int `json:"cache_read_tokens,omitempty"`
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
+}
+
+// CacheHitRate returns the share of the prompt that was read from cache, from 0 to 1
+func (u Usage) CacheHitRate() float64 {
+	if u.PromptTokens <= 0 {
+		return 0
+	}
+	return min(float64(u.CacheReadTokens)/float64(u.PromptTokens), 1)
 }
 
 // CodeGenerationResult represents the result of code generation including token usage
@@ -53,6 +66,7 @@ type ChatMessage struct {
 	ReasoningContent string                 `json:"reasoning_content,omitempty"` // Alternative reasoning field (e.g., from vLLM/Synthetic)
 	ToolCalls        []ToolCall             `json:"tool_calls,omitempty"`
 	ToolCallID       string                 `json:"tool_call_id,omitempty"`
+	Citations        []Citation             `json:"citations,omitempty"` // Sources the provider cited for the content of a response
 	Metadata         map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -62,6 +76,10 @@ type ToolCall struct {
 	Type     string                 `json:"type"`
 	Function ToolCallFunction       `json:"function"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Index is the position of the tool call within a streamed response. OpenAI-style
+	// streams identify argument deltas only by index; the ID is sent with the first delta.
+	Index *int `json:"index,omitempty"`
 }
 
 // ToolCallFunction represents a tool call function
@@ -113,14 +131,69 @@ type ChatCompletionChunk struct {
 	ReasoningContent string                 `json:"reasoning_content,omitempty"` // Alternative reasoning field
 	Error            string                 `json:"error"`
 	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	TokenCount       *ChunkTokenCount       `json:"token_count,omitempty"`      // Set only when token counting is enabled (utils.CountStreamTokens)
+	CodeExecutions   []CodeExecution        `json:"code_executions,omitempty"`  // Code run by a provider-hosted code execution tool
+	ServerToolUses   []ServerToolUse        `json:"server_tool_uses,omitempty"` // Tool calls executed by the provider, for observation
+	Citations        []Citation             `json:"citations,omitempty"`        // Sources backing the content, e.g. search grounding
+	Warnings         []Warning              `json:"warnings,omitempty"`         // Non-fatal notices, e.g. a deprecated model
+}
+
+// ServerToolUse is a tool call the provider executed on its own servers, such as
+// Anthropic's web search. It is reported so applications can observe it; unlike
+// ToolCall it must not be executed by the application and needs no tool result.
+// When streaming, the call and its result arrive in different chunks with the same ID.
+type ServerToolUse struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name,omitempty"`
+	Input      map[string]interface{} `json:"input,omitempty"`
+	ResultType string                 `json:"result_type,omitempty"` // e.g. "web_search_tool_result"
+	Result     interface{}            `json:"result,omitempty"`      // Provider-specific result content, as decoded JSON
+}
+
+// CodeExecution is a piece of code the model ran with a provider-hosted code execution
+// tool (such as Gemini's code_execution) together with its result. When streaming, the
+// code and its result may arrive in different chunks; a chunk then carries an entry
+// with only Language and Code followed by one with only Outcome and Output.
+type CodeExecution struct {
+	Language string `json:"language,omitempty"` // e.g. "PYTHON"
+	Code     string `json:"code,omitempty"`
+	Outcome  string `json:"outcome,omitempty"` // Provider-specific, e.g. "OUTCOME_OK" or "OUTCOME_FAILED"
+	Output   string `json:"output,omitempty"`  // stdout on success, error details on failure
+}
+
+// Citation is a source the provider cited for a response, such as a web page found by
+// search grounding or a passage of a supplied document. Text, StartIndex and EndIndex
+// locate the part of the response content the source supports, when the provider
+// reports it; the indexes are byte offsets into the content. A source listed without
+// a location has only Title, URL and Snippet.
+type Citation struct {
+	Title      string `json:"title,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Snippet    string `json:"snippet,omitempty"` // Text quoted from the source
+	Text       string `json:"text,omitempty"`    // The span of the response the source supports
+	StartIndex int    `json:"start_index,omitempty"`
+	EndIndex   int    `json:"end_index,omitempty"`
+}
+
+// ChunkTokenCount is the running completion token count of a stream, as of one chunk.
+// Counts are estimated from the streamed text until the provider reports usage, at
+// which point Total is reconciled to the reported completion tokens and Delta carries
+// the correction (which may be negative).
+type ChunkTokenCount struct {
+	Delta     int  `json:"delta"`     // Tokens added by this chunk
+	Total     int  `json:"total"`     // Completion tokens so far
+	Estimated bool `json:"estimated"` // False once Total comes from provider-reported usage
 }
 
 // ChatChoice represents a choice in a chat completion
 type ChatChoice struct {
 	Index        int         `json:"index"`
 	Message      ChatMessage `json:"message"`
-	FinishReason string      `json:"finish_reason"`
+	FinishReason string      `json:"finish_reason"` // Normalized, see NormalizeFinishReason
 	Delta        ChatMessage `json:"delta"`
+
+	// RawFinishReason is the finish reason as reported by the provider
+	RawFinishReason string `json:"raw_finish_reason,omitempty"`
 }
 
 // RunningModel represents a model that is currently loaded/running
@@ -136,7 +209,8 @@ type RunningModel struct {
 // GenerateOptions represents options for generating content
 type GenerateOptions struct {
 	Prompt         string                 `json:"prompt"`
-	Model          string                 `json:"model,omitempty"` // Per-request model override
+	Model          string                 `json:"model,omitempty"`           // Per-request model override
+	ModelFallbacks []string               `json:"model_fallbacks,omitempty"` // Models tried in order, on the same provider, if Model is overloaded or not found
 	Context        string                 `json:"context"`
 	ContextObj     context.Context        `json:"-"` // Internal context for operations
 	OutputFile     string                 `json:"output_file"`
@@ -146,10 +220,36 @@ type GenerateOptions struct {
 	MaxTokens      int                    `json:"max_tokens,omitempty"`
 	Temperature    float64                `json:"temperature,omitempty"`
 	Stop           []string               `json:"stop,omitempty"`
+	N              int                    `json:"n,omitempty"` // Completions to generate, as Choices; 0 means 1. Only OpenAI supports more than 1
 	Stream         bool                   `json:"stream"`
 	Tools          []Tool                 `json:"tools,omitempty"`
 	ToolChoice     *ToolChoice            `json:"tool_choice,omitempty"` // Fine-grained tool selection control
 	ResponseFormat string                 `json:"response_format,omitempty"`
 	Timeout        time.Duration          `json:"timeout,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"` // Reused across retries; generated when empty
+
+	// ParallelToolCalls allows (true) or forbids (false) several tool calls in one
+	// response; nil leaves the provider default. Only OpenAI supports it, and only when
+	// Tools are given; other providers ignore it.
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+
+	// Store asks the provider to keep the completion, e.g. for OpenAI's evals and
+	// distillation; nil leaves the provider default. Only OpenAI supports it, and sends
+	// Metadata with a stored completion, as strings. Other providers ignore it.
+	Store *bool `json:"store,omitempty"`
+
+	// ReasoningEffort sets how much a reasoning model thinks before answering; empty
+	// leaves the model default. Only OpenAI's reasoning models support it; it is ignored,
+	// with a warning, for other OpenAI models, and ignored by other providers.
+	ReasoningEffort ReasoningEffort `json:"reasoning_effort,omitempty"`
+
+	// TargetProvider pins the request to the child of a virtual provider (load balance,
+	// fallback or racing) with this name, bypassing its strategy; the request fails if
+	// no child has the name. Concrete providers ignore it.
+	TargetProvider string `json:"target_provider,omitempty"`
+
+	// ProviderOptions holds provider-specific options keyed by provider type
+	// (e.g. "openrouter" -> openrouter.OpenRouterOptions). Providers ignore keys that aren't theirs.
+	ProviderOptions map[string]interface{} `json:"provider_options,omitempty"`
 }
@@ -1218,7 +1218,7 @@ func TestGenerateOptions(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				valid := tt.options.Validate()
+				valid := tt.options.Validate() == nil
 				assert.Equal(t, tt.valid, valid)
 			})
 		}
@@ -1236,30 +1236,6 @@ func TestGenerateOptions(t *testing.T) {
 	})
 }
 
-// Validate checks if the generate options are valid
-func (o *GenerateOptions) Validate() bool {
-	// Either prompt or messages should be provided
-	if o.Prompt == "" && len(o.Messages) == 0 {
-		return false
-	}
-
-	// Validate numerical parameters
-	if o.MaxTokens < 0 {
-		return false
-	}
-
-	if o.Temperature < 0 || o.Temperature > 2 {
-		return false
-	}
-
-	// Validate timeout if set
-	if o.Timeout < 0 {
-		return false
-	}
-
-	return true
-}
-
 // ApplyDefaults applies default values to the options
 func (o *GenerateOptions) ApplyDefaults() {
 	// No specific defaults currently, but can be added as needed
@@ -0,0 +1,190 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// OptionsBuilder builds legacy GenerateOptions fluently with validation, for
+// applications that use GenerateOptions directly rather than StandardRequest:
+//
+//	options, err := types.NewOptionsBuilder().
+//	    WithModel("gpt-4o").
+//	    AddMessage(types.ChatMessage{Role: "system", Content: "Be brief."}).
+//	    AddMessage(types.ChatMessage{Role: "user", Content: "Hello"}).
+//	    WithMaxTokens(200).
+//	    Build()
+type OptionsBuilder struct {
+	options GenerateOptions
+}
+
+// NewOptionsBuilder creates a new GenerateOptions builder
+func NewOptionsBuilder() *OptionsBuilder {
+	return &OptionsBuilder{}
+}
+
+// WithPrompt sets a bare prompt, sent as a single user message when no messages are set
+func (b *OptionsBuilder) WithPrompt(prompt string) *OptionsBuilder {
+	b.options.Prompt = prompt
+	return b
+}
+
+// WithMessages sets the conversation messages
+func (b *OptionsBuilder) WithMessages(messages []ChatMessage) *OptionsBuilder {
+	b.options.Messages = messages
+	return b
+}
+
+// AddMessage appends a message to the conversation
+func (b *OptionsBuilder) AddMessage(message ChatMessage) *OptionsBuilder {
+	b.options.Messages = append(b.options.Messages, message)
+	return b
+}
+
+// WithModel sets the model
+func (b *OptionsBuilder) WithModel(model string) *OptionsBuilder {
+	b.options.Model = model
+	return b
+}
+
+// WithModelFallbacks sets models tried in order if the requested model is unavailable
+func (b *OptionsBuilder) WithModelFallbacks(models []string) *OptionsBuilder {
+	b.options.ModelFallbacks = models
+	return b
+}
+
+// WithMaxTokens sets the maximum tokens
+func (b *OptionsBuilder) WithMaxTokens(maxTokens int) *OptionsBuilder {
+	b.options.MaxTokens = maxTokens
+	return b
+}
+
+// WithTemperature sets the temperature
+func (b *OptionsBuilder) WithTemperature(temperature float64) *OptionsBuilder {
+	b.options.Temperature = temperature
+	return b
+}
+
+// WithStop sets stop sequences
+func (b *OptionsBuilder) WithStop(stop []string) *OptionsBuilder {
+	b.options.Stop = stop
+	return b
+}
+
+// WithN sets the number of completions to generate, returned as separate choices
+func (b *OptionsBuilder) WithN(n int) *OptionsBuilder {
+	b.options.N = n
+	return b
+}
+
+// WithStreaming enables or disables streaming
+func (b *OptionsBuilder) WithStreaming(stream bool) *OptionsBuilder {
+	b.options.Stream = stream
+	return b
+}
+
+// WithTools sets the available tools
+func (b *OptionsBuilder) WithTools(tools []Tool) *OptionsBuilder {
+	b.options.Tools = tools
+	return b
+}
+
+// WithToolChoice sets the tool choice strategy
+func (b *OptionsBuilder) WithToolChoice(toolChoice *ToolChoice) *OptionsBuilder {
+	b.options.ToolChoice = toolChoice
+	return b
+}
+
+// WithParallelToolCalls allows or forbids several tool calls in one response. Only
+// OpenAI supports it; other providers ignore it.
+func (b *OptionsBuilder) WithParallelToolCalls(parallel bool) *OptionsBuilder {
+	b.options.ParallelToolCalls = &parallel
+	return b
+}
+
+// WithReasoningEffort sets how much a reasoning model thinks before answering. Only
+// OpenAI's reasoning models support it.
+func (b *OptionsBuilder) WithReasoningEffort(effort ReasoningEffort) *OptionsBuilder {
+	b.options.ReasoningEffort = effort
+	return b
+}
+
+// WithStore asks the provider to store the completion, with the options' metadata.
+// Only OpenAI supports it; other providers ignore it.
+func (b *OptionsBuilder) WithStore(store bool) *OptionsBuilder {
+	b.options.Store = &store
+	return b
+}
+
+// WithResponseFormat sets the response format
+func (b *OptionsBuilder) WithResponseFormat(format string) *OptionsBuilder {
+	b.options.ResponseFormat = format
+	return b
+}
+
+// WithContext sets the request context
+func (b *OptionsBuilder) WithContext(ctx context.Context) *OptionsBuilder {
+	b.options.ContextObj = ctx
+	return b
+}
+
+// WithTimeout sets the request timeout
+func (b *OptionsBuilder) WithTimeout(timeout time.Duration) *OptionsBuilder {
+	b.options.Timeout = timeout
+	return b
+}
+
+// WithMetadata adds metadata
+func (b *OptionsBuilder) WithMetadata(key string, value interface{}) *OptionsBuilder {
+	if b.options.Metadata == nil {
+		b.options.Metadata = make(map[string]interface{})
+	}
+	b.options.Metadata[key] = value
+	return b
+}
+
+// WithIdempotencyKey sets the idempotency key reused across retries of the request
+func (b *OptionsBuilder) WithIdempotencyKey(key string) *OptionsBuilder {
+	b.options.IdempotencyKey = key
+	return b
+}
+
+// WithTargetProvider pins the request to the named child of a virtual provider,
+// bypassing its strategy. Concrete providers ignore it.
+func (b *OptionsBuilder) WithTargetProvider(name string) *OptionsBuilder {
+	b.options.TargetProvider = name
+	return b
+}
+
+// WithProviderOptions sets provider-specific options, keyed by provider type
+func (b *OptionsBuilder) WithProviderOptions(options map[string]interface{}) *OptionsBuilder {
+	b.options.ProviderOptions = options
+	return b
+}
+
+// Build validates and returns the options; see GenerateOptions.Validate for the rules
+func (b *OptionsBuilder) Build() (GenerateOptions, error) {
+	if err := b.options.Validate(); err != nil {
+		return GenerateOptions{}, err
+	}
+
+	// Copy so later builder calls don't modify the built options
+	options := b.options
+	if b.options.Metadata != nil {
+		options.Metadata = make(map[string]interface{}, len(b.options.Metadata))
+		for k, v := range b.options.Metadata {
+			options.Metadata[k] = v
+		}
+	}
+	options.Messages = append([]ChatMessage(nil), b.options.Messages...)
+	return options, nil
+}
+
+// BuildOptions validates the request and returns it as legacy GenerateOptions
+func (b *CoreRequestBuilder) BuildOptions() (GenerateOptions, error) {
+	request, err := b.Build()
+	if err != nil {
+		return GenerateOptions{}, err
+	}
+	return request.ToGenerateOptions(), nil
+}
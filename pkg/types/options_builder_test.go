@@ -0,0 +1,91 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsBuilder(t *testing.T) {
+	t.Run("builds options", func(t *testing.T) {
+		tools := []Tool{{Name: "lookup"}}
+		options, err := NewOptionsBuilder().
+			WithModel("gpt-4o").
+			WithModelFallbacks([]string{"gpt-4o-mini"}).
+			AddMessage(ChatMessage{Role: "system", Content: "Be brief."}).
+			AddMessage(ChatMessage{Role: "user", Content: "Hello"}).
+			WithMaxTokens(200).
+			WithTemperature(0.5).
+			WithStop([]string{"END"}).
+			WithStreaming(true).
+			WithTools(tools).
+			WithToolChoice(&ToolChoice{Mode: ToolChoiceSpecific, FunctionName: "lookup"}).
+			WithResponseFormat("json").
+			WithTimeout(time.Minute).
+			WithMetadata("user", "u1").
+			WithIdempotencyKey("key-1").
+			WithProviderOptions(map[string]interface{}{"openai": "x"}).
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, "gpt-4o", options.Model)
+		assert.Equal(t, []string{"gpt-4o-mini"}, options.ModelFallbacks)
+		require.Len(t, options.Messages, 2)
+		assert.Equal(t, "Hello", options.Messages[1].Content)
+		assert.Equal(t, 200, options.MaxTokens)
+		assert.Equal(t, 0.5, options.Temperature)
+		assert.Equal(t, []string{"END"}, options.Stop)
+		assert.True(t, options.Stream)
+		assert.Equal(t, tools, options.Tools)
+		assert.Equal(t, "lookup", options.ToolChoice.FunctionName)
+		assert.Equal(t, "json", options.ResponseFormat)
+		assert.Equal(t, time.Minute, options.Timeout)
+		assert.Equal(t, "u1", options.Metadata["user"])
+		assert.Equal(t, "key-1", options.IdempotencyKey)
+		assert.Equal(t, "x", options.ProviderOptions["openai"])
+	})
+
+	t.Run("accepts a bare prompt", func(t *testing.T) {
+		options, err := NewOptionsBuilder().WithPrompt("Hello").Build()
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", options.Prompt)
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		_, err := NewOptionsBuilder().Build()
+		assert.ErrorIs(t, err, ErrNoMessages)
+
+		_, err = NewOptionsBuilder().WithPrompt("hi").WithTemperature(3).Build()
+		assert.ErrorIs(t, err, ErrInvalidTemperature)
+
+		_, err = NewOptionsBuilder().WithPrompt("hi").WithToolChoice(&ToolChoice{Mode: ToolChoiceAuto}).Build()
+		assert.ErrorIs(t, err, ErrToolChoiceWithoutTools)
+	})
+
+	t.Run("built options are independent of the builder", func(t *testing.T) {
+		builder := NewOptionsBuilder().WithPrompt("hi").WithMetadata("a", 1).AddMessage(ChatMessage{Role: "user", Content: "one"})
+		options, err := builder.Build()
+		require.NoError(t, err)
+
+		builder.WithMetadata("b", 2).AddMessage(ChatMessage{Role: "user", Content: "two"})
+		assert.NotContains(t, options.Metadata, "b")
+		assert.Len(t, options.Messages, 1)
+	})
+}
+
+func TestCoreRequestBuilder_BuildOptions(t *testing.T) {
+	options, err := NewCoreRequestBuilder().
+		WithMessages([]ChatMessage{{Role: "user", Content: "Hello"}}).
+		WithModel("claude-sonnet-4").
+		WithMaxTokens(100).
+		BuildOptions()
+	require.NoError(t, err)
+	assert.Equal(t, "claude-sonnet-4", options.Model)
+	assert.Equal(t, 100, options.MaxTokens)
+	assert.Equal(t, "Hello", options.Messages[0].Content)
+
+	_, err = NewCoreRequestBuilder().BuildOptions()
+	assert.ErrorIs(t, err, ErrNoMessages)
+}
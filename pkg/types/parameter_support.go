@@ -0,0 +1,79 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ParameterSupport lists the request parameters a model accepts. A parameter that is
+// not supported may be rejected by the provider, as OpenAI's reasoning models reject
+// temperature, so it should be left unset.
+type ParameterSupport struct {
+	Temperature       bool `json:"temperature"`
+	TopP              bool `json:"top_p"`
+	FrequencyPenalty  bool `json:"frequency_penalty"`
+	PresencePenalty   bool `json:"presence_penalty"`
+	Stop              bool `json:"stop"`
+	N                 bool `json:"n"` // More than one completion
+	Tools             bool `json:"tools"`
+	ParallelToolCalls bool `json:"parallel_tool_calls"`
+	ReasoningEffort   bool `json:"reasoning_effort"`
+	Store             bool `json:"store"`
+}
+
+// AllParametersSupported is the ParameterSupport of a model that accepts every parameter
+var AllParametersSupported = ParameterSupport{
+	Temperature:       true,
+	TopP:              true,
+	FrequencyPenalty:  true,
+	PresencePenalty:   true,
+	Stop:              true,
+	N:                 true,
+	Tools:             true,
+	ParallelToolCalls: true,
+	ReasoningEffort:   true,
+	Store:             true,
+}
+
+// ParameterSupportReporter is an optional interface for providers that know which
+// request parameters each of their models accepts
+type ParameterSupportReporter interface {
+	SupportedParameters(model string) ParameterSupport
+}
+
+// SupportedParameters returns the request parameters provider accepts for model.
+// Providers without ParameterSupportReporter are assumed to accept every parameter,
+// and tools as ProviderCapabilities reports. An empty model uses the configured
+// default model.
+func SupportedParameters(provider Provider, model string) ParameterSupport {
+	if model == "" {
+		model = provider.GetConfig().DefaultModel
+	}
+	if reporter, ok := provider.(ParameterSupportReporter); ok {
+		return reporter.SupportedParameters(model)
+	}
+	support := AllParametersSupported
+	support.Tools = ProviderCapabilities(provider, model).Tools
+	return support
+}
+
+// ErrUnsupportedParameter is matched (with errors.Is) by *UnsupportedParameterError
+var ErrUnsupportedParameter = errors.New("unsupported parameter")
+
+// UnsupportedParameterError reports a request parameter the target model does not
+// accept, found before the request was sent
+type UnsupportedParameterError struct {
+	Provider  ProviderType
+	Model     string
+	Parameter string
+}
+
+// Error implements error
+func (e *UnsupportedParameterError) Error() string {
+	return fmt.Sprintf("model %s of provider %s does not accept %s", e.Model, e.Provider, e.Parameter)
+}
+
+// Is reports whether target is ErrUnsupportedParameter
+func (e *UnsupportedParameterError) Is(target error) bool {
+	return target == ErrUnsupportedParameter
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parameterProvider reports fixed parameter support
+type parameterProvider struct {
+	limitedProvider
+	support ParameterSupport
+	model   string
+}
+
+func (p *parameterProvider) SupportedParameters(model string) ParameterSupport {
+	p.model = model
+	return p.support
+}
+
+func TestSupportedParameters(t *testing.T) {
+	// Without a reporter every parameter is assumed supported, tools as capabilities say
+	provider := newLimitedProvider(ProviderTypeOllama, Capabilities{Tools: false})
+	support := SupportedParameters(provider, "llama3")
+	assert.False(t, support.Tools)
+	support.Tools = true
+	assert.Equal(t, AllParametersSupported, support)
+
+	reporter := &parameterProvider{
+		limitedProvider: *newLimitedProvider(ProviderTypeOpenAI, Capabilities{Tools: true}),
+		support:         ParameterSupport{Tools: true},
+	}
+	assert.Equal(t, ParameterSupport{Tools: true}, SupportedParameters(reporter, "o1"))
+	assert.Equal(t, "o1", reporter.model)
+}
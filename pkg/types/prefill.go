@@ -0,0 +1,51 @@
+package types
+
+// A prefill is the start of the assistant's response, written by the caller: a trailing
+// assistant message that the model continues rather than answers. It constrains the
+// output, e.g. a prefill of "{" forces a JSON object. Providers differ:
+//
+//   - Anthropic continues the prefill. The response holds only the continuation, not
+//     the prefill itself, and trailing whitespace is trimmed from the prefill since the
+//     API rejects it.
+//   - OpenAI has no prefill; the trailing assistant message is dropped and the response
+//     carries a WarningParameterIgnored warning.
+//   - Other providers send the message as is; whether it is continued depends on the
+//     model.
+
+// WithPrefill returns a copy of options whose response starts with prefill, appended as
+// a trailing assistant message. A bare Prompt is first converted to a user message, so
+// the prefill follows it. The caller's options are not modified.
+//
+//	options = options.WithPrefill("{")
+func (o GenerateOptions) WithPrefill(prefill string) GenerateOptions {
+	messages := make([]ChatMessage, 0, len(o.Messages)+2)
+	messages = append(messages, o.Messages...)
+	if len(messages) == 0 && o.Prompt != "" {
+		messages = append(messages, ChatMessage{Role: "user", Content: o.Prompt})
+		o.Prompt = ""
+	}
+	o.Messages = append(messages, ChatMessage{Role: "assistant", Content: prefill})
+	return o
+}
+
+// Prefill returns the request's prefill: the text of a trailing assistant message
+// without tool calls. It returns false when the request has none.
+func (o GenerateOptions) Prefill() (string, bool) {
+	if len(o.Messages) == 0 {
+		return "", false
+	}
+	last := o.Messages[len(o.Messages)-1]
+	if last.Role != "assistant" || len(last.ToolCalls) > 0 {
+		return "", false
+	}
+	return last.GetTextContent(), true
+}
+
+// WithoutPrefill returns a copy of options with its prefill message, if any, removed
+func (o GenerateOptions) WithoutPrefill() GenerateOptions {
+	if _, ok := o.Prefill(); ok {
+		last := len(o.Messages) - 1
+		o.Messages = o.Messages[:last:last]
+	}
+	return o
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOptions_WithPrefill(t *testing.T) {
+	original := GenerateOptions{Messages: []ChatMessage{{Role: "user", Content: "List three colors as JSON"}}}
+
+	options := original.WithPrefill("{")
+	require.Len(t, options.Messages, 2)
+	assert.Equal(t, ChatMessage{Role: "assistant", Content: "{"}, options.Messages[1])
+	assert.Len(t, original.Messages, 1, "caller's options must not be modified")
+
+	prefill, ok := options.Prefill()
+	assert.True(t, ok)
+	assert.Equal(t, "{", prefill)
+
+	assert.Equal(t, original.Messages, options.WithoutPrefill().Messages)
+}
+
+func TestGenerateOptions_WithPrefill_ConvertsPrompt(t *testing.T) {
+	options := GenerateOptions{Prompt: "Say hi"}.WithPrefill("Hi")
+
+	assert.Empty(t, options.Prompt)
+	assert.Equal(t, []ChatMessage{
+		{Role: "user", Content: "Say hi"},
+		{Role: "assistant", Content: "Hi"},
+	}, options.Messages)
+}
+
+func TestGenerateOptions_Prefill_NotPresent(t *testing.T) {
+	tests := map[string][]ChatMessage{
+		"no messages":        nil,
+		"trailing user":      {{Role: "user", Content: "hi"}},
+		"trailing tool call": {{Role: "user", Content: "hi"}, {Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1"}}}},
+	}
+	for name, messages := range tests {
+		t.Run(name, func(t *testing.T) {
+			options := GenerateOptions{Messages: messages}
+			_, ok := options.Prefill()
+			assert.False(t, ok)
+			assert.Equal(t, messages, options.WithoutPrefill().Messages)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"net/http"
 	"time"
 )
 
@@ -24,6 +25,9 @@ const (
 	ProviderTypeLlamaCpp   ProviderType = "llamacpp"
 	ProviderTypeOllama     ProviderType = "ollama"
 
+	// ProviderTypeGrok is xAI's Grok API, registered as ProviderTypexAI
+	ProviderTypeGrok = ProviderTypexAI
+
 	// Virtual providers
 	ProviderTypeRacing      ProviderType = "racing"
 	ProviderTypeFallback    ProviderType = "fallback"
@@ -50,6 +54,11 @@ const (
 	ToolFormatXML       ToolFormat = "xml"
 	ToolFormatHermes    ToolFormat = "hermes"
 	ToolFormatText      ToolFormat = "text"
+
+	// ToolFormatOpenAIFunctions is OpenAI's legacy function calling (the "functions" and
+	// "function_call" fields), for OpenAI-compatible endpoints that predate "tools".
+	// Set it as ProviderConfig.ToolFormat, or per model as ModelCapabilityOverride.ToolFormat.
+	ToolFormatOpenAIFunctions ToolFormat = "openai_functions"
 )
 
 // HealthStatus represents the health status of a provider
@@ -74,6 +83,14 @@ type ProviderMetrics struct {
 	LastError       string        `json:"last_error"`
 	TokensUsed      int64         `json:"tokens_used"`
 	HealthStatus    HealthStatus  `json:"health_status"`
+
+	// Recent latency of successful requests. AverageLatency covers the provider's
+	// lifetime; EWMALatency weights recent requests most, and the percentiles are taken
+	// over a bounded window of the most recent requests.
+	EWMALatency time.Duration `json:"ewma_latency"`
+	P50Latency  time.Duration `json:"p50_latency"`
+	P95Latency  time.Duration `json:"p95_latency"`
+	P99Latency  time.Duration `json:"p99_latency"`
 }
 
 // ProviderInfo contains information about a provider
@@ -89,12 +106,19 @@ type ProviderInfo struct {
 
 // ModelCapabilityOverride allows users to override model capabilities
 type ModelCapabilityOverride struct {
-	MaxTokens         *int     `json:"max_tokens,omitempty"`
-	ContextWindow     *int     `json:"context_window,omitempty"`
-	SupportsStreaming *bool    `json:"supports_streaming,omitempty"`
-	SupportsTools     *bool    `json:"supports_tools,omitempty"`
-	SupportsVision    *bool    `json:"supports_vision,omitempty"`
-	Capabilities      []string `json:"capabilities,omitempty"`
+	MaxTokens          *int       `json:"max_tokens,omitempty"`
+	ContextWindow      *int       `json:"context_window,omitempty"`
+	SupportsStreaming  *bool      `json:"supports_streaming,omitempty"`
+	SupportsTools      *bool      `json:"supports_tools,omitempty"`
+	SupportsVision     *bool      `json:"supports_vision,omitempty"`
+	SupportsJSONSchema *bool      `json:"supports_json_schema,omitempty"`
+	Capabilities       []string   `json:"capabilities,omitempty"`
+	ToolFormat         ToolFormat `json:"tool_format,omitempty"` // Overrides ProviderConfig.ToolFormat for the model
+
+	// ContextUpgrades opts the model into automatic context upgrades: larger-context
+	// models, tried in order, to send a request to instead when its estimated tokens
+	// exceed ContextWindow. See common.GenerateWithContextUpgrade.
+	ContextUpgrades []string `json:"context_upgrades,omitempty"`
 }
 
 // ProviderConfig represents configuration for a specific provider
@@ -120,14 +144,65 @@ type ProviderConfig struct {
 	SupportsResponsesAPI bool `json:"supports_responses_api"`
 
 	// Limits and timeouts
-	MaxTokens int           `json:"max_tokens,omitempty"`
-	Timeout   time.Duration `json:"timeout,omitempty"`
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// Timeout bounds every request, streams included, from dialing until the response
+	// body has been read. It is ignored when RequestTimeout is set.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// ConnectTimeout bounds dialing, the TLS handshake and waiting for the response
+	// headers (the first byte) of every request. 0 leaves them bounded only by Timeout
+	// or RequestTimeout.
+	ConnectTimeout time.Duration `json:"connect_timeout,omitempty"`
+
+	// RequestTimeout bounds every request until its response body has been read, like
+	// Timeout, except that streamed responses are bounded by it only until their headers
+	// arrive, so long generations are not cut off. Pair it with ConnectTimeout to fail
+	// fast on a dead endpoint while allowing long streams.
+	//
+	// Both timeouts work alongside the context passed to each call: the request ends at
+	// whichever comes first, so a context deadline is the way to bound a whole stream.
+	RequestTimeout time.Duration `json:"request_timeout,omitempty"`
+
+	// HedgeAfter sends a second, identical request to the provider when a non-streaming
+	// request has not completed within this duration. Whichever completes first is used
+	// and the other is cancelled, which stops its generation. Hedging cuts tail latency
+	// but a hedged request may occasionally be billed twice, since both requests can
+	// have generated output by the time one wins. 0 disables hedging.
+	HedgeAfter time.Duration `json:"hedge_after,omitempty"`
+
+	// CompressRequests gzips large request bodies (Content-Encoding: gzip). Only enable
+	// it for endpoints that accept compressed request bodies. Responses are always
+	// requested and decompressed with gzip regardless of this setting.
+	CompressRequests bool `json:"compress_requests,omitempty"`
 
 	// Tool format
 	ToolFormat ToolFormat `json:"tool_format,omitempty"`
 
 	// Logging configuration
 	EnableVerboseLogging bool `json:"enable_verbose_logging,omitempty"`
+
+	// PathTemplate overrides how endpoint URLs are built, for gateways whose paths
+	// differ from the provider's API. {path} is replaced by the operation path (e.g.
+	// "chat/completions") and {base_url} by BaseURL:
+	// "https://gw.internal/ai/openai/v1/{path}" or "{base_url}/api/{path}".
+	// Supported by the OpenAI provider.
+	PathTemplate string `json:"path_template,omitempty"`
+
+	// Request customization - applied to every HTTP request the provider makes.
+	// Headers override provider defaults of the same name. OpenRouter uses
+	// HTTP-Referer and X-Title for app attribution and leaderboards.
+	Headers   map[string]string `json:"headers,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+
+	// WrapTransport wraps the transport of the provider's HTTP client, so middleware
+	// runs for every request the provider sends; see middleware.NewTransport. Providers
+	// created by a factory get an instrumented transport unless it is set.
+	WrapTransport func(http.RoundTripper) http.RoundTripper `json:"-" yaml:"-"`
+
+	// Tokenizer gives exact token counts for this provider's models. When nil the
+	// built-in heuristic is used; see utils.TokenizerFor.
+	Tokenizer Tokenizer `json:"-"`
 }
 
 // OAuthConfig represents OAuth configuration
@@ -266,6 +341,40 @@ type HealthCheckProvider interface {
 	GetMetrics() ProviderMetrics
 }
 
+// MetricsResetter is an optional interface for providers whose metrics can be reset.
+// SnapshotMetrics returns the current metrics and resets the counters in one atomic
+// operation, so a periodic exporter neither double-counts nor loses requests that
+// complete concurrently.
+type MetricsResetter interface {
+	// ResetMetrics zeroes the request, success, error, latency and token counters.
+	// Last request/success/error times, the last error, health status and the recent
+	// latency statistics (EWMA and percentiles) are kept.
+	ResetMetrics()
+
+	// SnapshotMetrics returns the metrics as GetMetrics would and resets the counters
+	SnapshotMetrics() ProviderMetrics
+}
+
+// SnapshotProviderMetrics returns provider.SnapshotMetrics() if provider implements
+// MetricsResetter, and provider.GetMetrics() otherwise
+func SnapshotProviderMetrics(provider Provider) ProviderMetrics {
+	if resetter, ok := provider.(MetricsResetter); ok {
+		return resetter.SnapshotMetrics()
+	}
+	return provider.GetMetrics()
+}
+
+// ResetCounters zeroes the counters of m, keeping timestamps, the last error, health
+// status and the recent latency statistics
+func (m *ProviderMetrics) ResetCounters() {
+	m.RequestCount = 0
+	m.SuccessCount = 0
+	m.ErrorCount = 0
+	m.TotalLatency = 0
+	m.AverageLatency = 0
+	m.TokensUsed = 0
+}
+
 // AuthMethodDetector defines methods for detecting configured authentication methods.
 // This optional interface is for providers that support multiple authentication methods
 // (e.g., both OAuth and API key) and need to expose which methods are currently configured.
@@ -277,6 +386,15 @@ type AuthMethodDetector interface {
 	IsAPIKeyConfigured() bool
 }
 
+// WarmableProvider defines a method for preparing a provider before its first request.
+// This optional interface lets servers pay cold-start costs (OAuth token refresh,
+// model list population) at startup instead of on the first user request.
+type WarmableProvider interface {
+	// Warmup refreshes credentials and loads the model list. It does not send a
+	// completion; see common.Warmup for that option.
+	Warmup(ctx context.Context) error
+}
+
 // ============================================================================
 // Composite Provider Interface
 // ============================================================================
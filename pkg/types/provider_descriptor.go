@@ -0,0 +1,134 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ConfigFieldType is the kind of value a ConfigField holds
+type ConfigFieldType string
+
+const (
+	ConfigFieldString   ConfigFieldType = "string"
+	ConfigFieldSecret   ConfigFieldType = "secret" // A string to mask in UIs and logs
+	ConfigFieldURL      ConfigFieldType = "url"
+	ConfigFieldInt      ConfigFieldType = "int"
+	ConfigFieldBool     ConfigFieldType = "bool"
+	ConfigFieldList     ConfigFieldType = "string_list"
+	ConfigFieldObject   ConfigFieldType = "object"
+	ConfigFieldOAuthSet ConfigFieldType = "oauth_credentials"
+)
+
+// ConfigField describes one ProviderConfig setting a provider reads
+type ConfigField struct {
+	// Name is the field's JSON key in ProviderConfig, e.g. "api_key", or for entries
+	// of ProviderConfig.ProviderConfig the key prefixed with "provider_config.", e.g.
+	// "provider_config.project_id"
+	Name        string          `json:"name"`
+	Type        ConfigFieldType `json:"type"`
+	Description string          `json:"description"`
+
+	// Required fields must be set. A field with an AuthMethod is only required, and only
+	// used, when the provider authenticates with that method.
+	Required   bool       `json:"required"`
+	AuthMethod AuthMethod `json:"auth_method,omitempty"`
+
+	// EnvVar is an environment variable the provider reads when the field is not set
+	EnvVar  string `json:"env_var,omitempty"`
+	Default string `json:"default,omitempty"`
+}
+
+// ProviderDescriptor describes the configuration a provider type accepts, for
+// data-driven config validation and UIs
+type ProviderDescriptor struct {
+	Type ProviderType `json:"type"`
+
+	// AuthMethods lists the ways the provider can authenticate; exactly one must be
+	// configured. It is empty for providers that need no credentials.
+	AuthMethods []AuthMethod `json:"auth_methods,omitempty"`
+
+	Fields []ConfigField `json:"fields"`
+}
+
+// Field returns the descriptor's field with the given name
+func (d ProviderDescriptor) Field(name string) (ConfigField, bool) {
+	for _, field := range d.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return ConfigField{}, false
+}
+
+// Validate checks that config sets the credentials for one of the descriptor's
+// AuthMethods and every field required for that method, returning a *ValidationError
+// naming the first that is missing. A field whose EnvVar is set in the environment
+// counts as set.
+func (d ProviderDescriptor) Validate(config ProviderConfig) error {
+	method := configuredAuthMethod(config)
+	if len(d.AuthMethods) > 0 && !d.supportsAuth(method) {
+		methods := make([]string, len(d.AuthMethods))
+		for i, m := range d.AuthMethods {
+			methods[i] = string(m)
+		}
+		return NewValidationError(fmt.Sprintf("provider %s requires one of: %s", d.Type, strings.Join(methods, ", ")))
+	}
+
+	for _, field := range d.Fields {
+		if !field.Required || (field.AuthMethod != "" && field.AuthMethod != method) {
+			continue
+		}
+		if configFieldSet(config, field.Name) || (field.EnvVar != "" && os.Getenv(field.EnvVar) != "") {
+			continue
+		}
+		if field.AuthMethod != "" {
+			return NewValidationError(fmt.Sprintf("provider %s requires %s with %s authentication", d.Type, field.Name, field.AuthMethod))
+		}
+		return NewValidationError(fmt.Sprintf("provider %s requires %s", d.Type, field.Name))
+	}
+	return nil
+}
+
+func (d ProviderDescriptor) supportsAuth(method AuthMethod) bool {
+	for _, m := range d.AuthMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredAuthMethod returns the auth method config has credentials for, preferring
+// OAuth as providers do, or "" when it has none
+func configuredAuthMethod(config ProviderConfig) AuthMethod {
+	switch {
+	case len(config.OAuthCredentials) > 0:
+		return AuthMethodOAuth
+	case config.APIKey != "" || config.APIKeyEnv != "":
+		return AuthMethodAPIKey
+	default:
+		return ""
+	}
+}
+
+// configFieldSet reports whether the ProviderConfig field named as in ConfigField.Name
+// has a value
+func configFieldSet(config ProviderConfig, name string) bool {
+	if key, ok := strings.CutPrefix(name, "provider_config."); ok {
+		value, exists := config.ProviderConfig[key]
+		return exists && value != nil && value != ""
+	}
+	switch name {
+	case "api_key":
+		return config.APIKey != "" || config.APIKeyEnv != ""
+	case "oauth_credentials":
+		return len(config.OAuthCredentials) > 0
+	case "base_url":
+		return config.BaseURL != ""
+	case "default_model":
+		return config.DefaultModel != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderDescriptor_Validate(t *testing.T) {
+	descriptor := ProviderDescriptor{
+		Type:        ProviderTypeGemini,
+		AuthMethods: []AuthMethod{AuthMethodAPIKey, AuthMethodOAuth},
+		Fields: []ConfigField{
+			{Name: "api_key", Required: true, AuthMethod: AuthMethodAPIKey},
+			{Name: "oauth_credentials", Required: true, AuthMethod: AuthMethodOAuth},
+			{Name: "provider_config.project_id", Required: true, AuthMethod: AuthMethodOAuth, EnvVar: "TEST_DESCRIPTOR_PROJECT"},
+			{Name: "default_model"},
+		},
+	}
+	oauth := []*OAuthCredentialSet{{ID: "default", AccessToken: "token"}}
+
+	tests := []struct {
+		name    string
+		config  ProviderConfig
+		env     string
+		wantErr string
+	}{
+		{name: "api key", config: ProviderConfig{APIKey: "key"}},
+		{name: "no credentials", config: ProviderConfig{}, wantErr: "requires one of: api_key, oauth"},
+		{name: "oauth without project", config: ProviderConfig{OAuthCredentials: oauth}, wantErr: "provider_config.project_id with oauth"},
+		{name: "oauth with project", config: ProviderConfig{OAuthCredentials: oauth, ProviderConfig: map[string]interface{}{"project_id": "p"}}},
+		{name: "oauth with project from env", config: ProviderConfig{OAuthCredentials: oauth}, env: "p"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_DESCRIPTOR_PROJECT", tt.env)
+			err := descriptor.Validate(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, IsValidationError(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestProviderDescriptor_Field(t *testing.T) {
+	descriptor := ProviderDescriptor{Fields: []ConfigField{{Name: "api_key", Type: ConfigFieldSecret}}}
+
+	field, ok := descriptor.Field("api_key")
+	assert.True(t, ok)
+	assert.Equal(t, ConfigFieldSecret, field.Type)
+
+	_, ok = descriptor.Field("base_url")
+	assert.False(t, ok)
+}
@@ -1,8 +1,13 @@
 package types
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // ErrorCode categorizes provider errors
@@ -93,6 +98,16 @@ func (e *ProviderError) WithRetryAfter(retryAfter int) *ProviderError {
 	return e
 }
 
+// WithRetryAfterDuration sets the retry after field from a duration, rounding up to whole seconds
+func (e *ProviderError) WithRetryAfterDuration(retryAfter time.Duration) *ProviderError {
+	if retryAfter <= 0 {
+		e.RetryAfter = 0
+		return e
+	}
+	e.RetryAfter = int(math.Ceil(retryAfter.Seconds()))
+	return e
+}
+
 // NewProviderError creates a new ProviderError
 func NewProviderError(provider ProviderType, code ErrorCode, message string) *ProviderError {
 	return &ProviderError{
@@ -203,3 +218,52 @@ func ClassifyHTTPError(statusCode int) ErrorCode {
 		return ErrCodeUnknown
 	}
 }
+
+// StatusOverloaded is the non-standard status Anthropic returns when a model is overloaded
+const StatusOverloaded = 529
+
+// IsModelUnavailable reports whether err means the requested model cannot serve the
+// request right now: the model was not found (404) or is overloaded (503, or
+// Anthropic's 529). Such errors are specific to the model, not to the credentials or
+// the request, so retrying with another API key will not help but another model may.
+func IsModelUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Check every error in the chain, since auth helpers wrap the per-key errors
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if providerErr, ok := e.(*ProviderError); ok {
+			if providerErr.Code == ErrCodeNotFound || isModelUnavailableStatus(providerErr.StatusCode) {
+				return true
+			}
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "model_not_found") ||
+		strings.Contains(msg, "model not found") ||
+		strings.Contains(msg, "overloaded")
+}
+
+func isModelUnavailableStatus(statusCode int) bool {
+	return statusCode == http.StatusNotFound ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == StatusOverloaded
+}
+
+// modelFallbackKey marks a context whose request will fall back to another model
+type modelFallbackKey struct{}
+
+// WithModelFallback returns a context marking the request as one attempt of a model
+// fallback chain with models left to try. Key failover stops at model-specific errors
+// for such requests and leaves them to the next model.
+func WithModelFallback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, modelFallbackKey{}, true)
+}
+
+// HasModelFallback reports whether ctx was marked with WithModelFallback
+func HasModelFallback(ctx context.Context) bool {
+	fallback, _ := ctx.Value(modelFallbackKey{}).(bool)
+	return fallback
+}
@@ -391,3 +391,30 @@ func TestProviderError_NilOriginalErr(t *testing.T) {
 		t.Error("Unwrap() should return nil when OriginalErr is not set")
 	}
 }
+
+func TestIsModelUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not found", NewNotFoundError(ProviderTypeOpenAI, "no such model"), true},
+		{"service unavailable", NewServerError(ProviderTypeOpenAI, http.StatusServiceUnavailable, "busy"), true},
+		{"anthropic overloaded", NewServerError(ProviderTypeAnthropic, 529, "busy"), true},
+		{"internal server error", NewServerError(ProviderTypeOpenAI, http.StatusInternalServerError, "boom"), false},
+		{"rate limit", NewRateLimitError(ProviderTypeOpenAI, 5), false},
+		{"wrapped by auth error", NewAuthError(ProviderTypeAnthropic, "all keys failed").WithOriginalErr(NewNotFoundError(ProviderTypeAnthropic, "x")), true},
+		{"fmt wrapped", fmt.Errorf("call: %w", NewServerError(ProviderTypeOpenAI, 503, "busy")), true},
+		{"message overloaded", errors.New("overloaded_error: Overloaded"), true},
+		{"plain error", errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsModelUnavailable(tt.err); got != tt.want {
+				t.Errorf("IsModelUnavailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package types
+
+import "time"
+
+// RateLimitStatus is a provider's rate-limit state as last reported in its response
+// headers. Known is false until a response with rate-limit headers has been seen, in
+// which case the other fields are zero.
+type RateLimitStatus struct {
+	Known bool   `json:"known"`
+	Model string `json:"model,omitempty"` // The model of the response the status was read from
+
+	RequestsLimit     int       `json:"requests_limit,omitempty"`
+	RequestsRemaining int       `json:"requests_remaining,omitempty"`
+	RequestsReset     time.Time `json:"requests_reset,omitempty"`
+
+	TokensLimit     int       `json:"tokens_limit,omitempty"`
+	TokensRemaining int       `json:"tokens_remaining,omitempty"`
+	TokensReset     time.Time `json:"tokens_reset,omitempty"`
+
+	// RetryAt is when the provider asked to be retried, from the Retry-After header of
+	// a rate-limited response
+	RetryAt time.Time `json:"retry_at,omitempty"`
+
+	// ObservedAt is when the headers were received
+	ObservedAt time.Time `json:"observed_at,omitempty"`
+}
+
+// UnknownRateLimitStatus is the status of a provider that has not reported rate limits
+var UnknownRateLimitStatus = RateLimitStatus{}
+
+// ReadyAt returns when the provider expects to serve requests again: RetryAt, or the
+// reset time of an exhausted request or token limit, whichever is latest. It returns
+// the zero time when no limit is known to be exhausted.
+func (s RateLimitStatus) ReadyAt() time.Time {
+	var ready time.Time
+	later := func(t time.Time) {
+		if t.After(ready) {
+			ready = t
+		}
+	}
+
+	later(s.RetryAt)
+	if s.RequestsLimit > 0 && s.RequestsRemaining <= 0 {
+		later(s.RequestsReset)
+	}
+	if s.TokensLimit > 0 && s.TokensRemaining <= 0 {
+		later(s.TokensReset)
+	}
+	return ready
+}
+
+// Limited reports whether the provider is expected to reject requests at now
+func (s RateLimitStatus) Limited(now time.Time) bool {
+	return s.ReadyAt().After(now)
+}
+
+// WaitTime returns how long to wait from now before the provider is expected to serve
+// requests again, or 0 if it is not known to be limited
+func (s RateLimitStatus) WaitTime(now time.Time) time.Duration {
+	if !s.Limited(now) {
+		return 0
+	}
+	return s.ReadyAt().Sub(now)
+}
+
+// RateLimitStatusProvider is an optional interface for providers that track the
+// rate-limit headers of their responses, so schedulers and routing providers can back
+// off from a provider that is out of quota until it resets.
+type RateLimitStatusProvider interface {
+	// RateLimitStatus returns the rate-limit state from the most recent response that
+	// reported it, or UnknownRateLimitStatus if none has
+	RateLimitStatus() RateLimitStatus
+}
+
+// ProviderRateLimitStatus returns provider.RateLimitStatus() if provider implements
+// RateLimitStatusProvider, and UnknownRateLimitStatus otherwise
+func ProviderRateLimitStatus(provider Provider) RateLimitStatus {
+	if p, ok := provider.(RateLimitStatusProvider); ok {
+		return p.RateLimitStatus()
+	}
+	return UnknownRateLimitStatus
+}
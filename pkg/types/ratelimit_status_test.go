@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitStatus(t *testing.T) {
+	now := time.Now()
+
+	t.Run("unknown", func(t *testing.T) {
+		assert.False(t, UnknownRateLimitStatus.Known)
+		assert.False(t, UnknownRateLimitStatus.Limited(now))
+		assert.Zero(t, UnknownRateLimitStatus.WaitTime(now))
+	})
+
+	t.Run("remaining quota", func(t *testing.T) {
+		status := RateLimitStatus{Known: true, RequestsLimit: 100, RequestsRemaining: 3, RequestsReset: now.Add(time.Minute)}
+		assert.False(t, status.Limited(now))
+		assert.True(t, status.ReadyAt().IsZero())
+	})
+
+	t.Run("exhausted limits wait for the latest reset", func(t *testing.T) {
+		status := RateLimitStatus{
+			Known:         true,
+			RequestsLimit: 100, RequestsRemaining: 0, RequestsReset: now.Add(10 * time.Second),
+			TokensLimit: 1000, TokensRemaining: 0, TokensReset: now.Add(30 * time.Second),
+		}
+		assert.True(t, status.Limited(now))
+		assert.Equal(t, now.Add(30*time.Second), status.ReadyAt())
+		assert.Equal(t, 30*time.Second, status.WaitTime(now))
+		assert.False(t, status.Limited(now.Add(time.Minute)), "limits reset")
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		status := RateLimitStatus{Known: true, RetryAt: now.Add(5 * time.Second)}
+		assert.True(t, status.Limited(now))
+		assert.Equal(t, 5*time.Second, status.WaitTime(now))
+	})
+}
+
+func TestProviderRateLimitStatus(t *testing.T) {
+	assert.Equal(t, UnknownRateLimitStatus, ProviderRateLimitStatus(&MockProvider{}))
+}
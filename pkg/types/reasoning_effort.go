@@ -0,0 +1,24 @@
+package types
+
+import "fmt"
+
+// ReasoningEffort is how much a reasoning model thinks before answering, trading
+// latency and cost for quality
+type ReasoningEffort string
+
+const (
+	ReasoningEffortLow    ReasoningEffort = "low"
+	ReasoningEffortMedium ReasoningEffort = "medium"
+	ReasoningEffortHigh   ReasoningEffort = "high"
+)
+
+// validateReasoningEffort accepts an empty effort, which leaves the model default, and
+// the ReasoningEffort constants
+func validateReasoningEffort(effort ReasoningEffort) error {
+	switch effort {
+	case "", ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh:
+		return nil
+	default:
+		return NewValidationError(fmt.Sprintf("invalid reasoning effort %q: must be low, medium or high", effort))
+	}
+}
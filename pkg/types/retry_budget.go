@@ -0,0 +1,71 @@
+package types
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// RetryBudget caps the retries of one logical request across every layer that retries:
+// retry executors, the HTTP client, API key failover and fallback providers. Each layer
+// takes one retry from the budget in the request's context before retrying, and once
+// the budget is spent returns its last error instead, so composed layers cannot
+// multiply attempts during an outage. The first attempt of each layer is not a retry
+// and is not counted.
+//
+// A RetryBudget is safe for concurrent use. A nil *RetryBudget is unlimited.
+type RetryBudget struct {
+	remaining atomic.Int64
+}
+
+// NewRetryBudget returns a budget allowing the given number of retries in total
+func NewRetryBudget(retries int) *RetryBudget {
+	budget := &RetryBudget{}
+	budget.remaining.Store(int64(max(retries, 0)))
+	return budget
+}
+
+// TryAcquire takes one retry from the budget, reporting false if it is spent
+func (b *RetryBudget) TryAcquire() bool {
+	if b == nil {
+		return true
+	}
+	for {
+		remaining := b.remaining.Load()
+		if remaining <= 0 {
+			return false
+		}
+		if b.remaining.CompareAndSwap(remaining, remaining-1) {
+			return true
+		}
+	}
+}
+
+// Remaining returns the number of retries left, or -1 for a nil, unlimited budget
+func (b *RetryBudget) Remaining() int {
+	if b == nil {
+		return -1
+	}
+	return int(b.remaining.Load())
+}
+
+// retryBudgetKey is the context key of the request's RetryBudget
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context carrying budget, shared by every retry layer the
+// request passes through
+func WithRetryBudget(ctx context.Context, budget *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// RetryBudgetFromContext returns the context's retry budget, or nil if it has none
+func RetryBudgetFromContext(ctx context.Context) *RetryBudget {
+	budget, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return budget
+}
+
+// AcquireRetry takes one retry from the context's budget, reporting false if it is
+// spent. It always succeeds when the context has no budget, leaving each layer to its
+// own limits.
+func AcquireRetry(ctx context.Context) bool {
+	return RetryBudgetFromContext(ctx).TryAcquire()
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := NewRetryBudget(2)
+	assert.True(t, budget.TryAcquire())
+	assert.True(t, budget.TryAcquire())
+	assert.False(t, budget.TryAcquire())
+	assert.Equal(t, 0, budget.Remaining())
+
+	assert.False(t, NewRetryBudget(-1).TryAcquire(), "a negative budget allows no retries")
+
+	var unlimited *RetryBudget
+	assert.True(t, unlimited.TryAcquire())
+	assert.Equal(t, -1, unlimited.Remaining())
+}
+
+func TestRetryBudget_Concurrent(t *testing.T) {
+	budget := NewRetryBudget(50)
+	var acquired sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 100; i++ {
+		acquired.Add(1)
+		go func() {
+			defer acquired.Done()
+			if budget.TryAcquire() {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}()
+	}
+	acquired.Wait()
+	assert.Equal(t, 50, granted)
+}
+
+func TestRetryBudget_Context(t *testing.T) {
+	assert.Nil(t, RetryBudgetFromContext(context.Background()))
+	assert.True(t, AcquireRetry(context.Background()), "without a budget, retries are left to each layer")
+
+	budget := NewRetryBudget(1)
+	ctx := WithRetryBudget(context.Background(), budget)
+	assert.Same(t, budget, RetryBudgetFromContext(ctx))
+	assert.True(t, AcquireRetry(ctx))
+	assert.False(t, AcquireRetry(ctx))
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+)
+
+// ErrStreamCancelled is matched (with errors.Is) by *StreamCancelledError
+var ErrStreamCancelled = errors.New("stream cancelled")
+
+// StreamCancelReason is why a stream ended before it finished
+type StreamCancelReason string
+
+const (
+	StreamCancelledByUser    StreamCancelReason = "user"          // The request's context was cancelled
+	StreamCancelledByTimeout StreamCancelReason = "timeout"       // The request's deadline or timeout passed
+	StreamCancelledByServer  StreamCancelReason = "server_closed" // The connection was closed mid-stream
+)
+
+// StreamCancelledError is returned by a stream's Next when the stream was aborted, so
+// applications can tell a user stopping generation from a timeout or a dropped
+// connection:
+//
+//	var cancelled *types.StreamCancelledError
+//	if errors.As(err, &cancelled) && cancelled.Reason == types.StreamCancelledByUser {
+//	    showStatus("Stopped")
+//	}
+//
+// It wraps the underlying error, so errors.Is(err, context.Canceled) still matches.
+type StreamCancelledError struct {
+	Reason StreamCancelReason
+	Err    error
+}
+
+// Error implements error
+func (e *StreamCancelledError) Error() string {
+	switch e.Reason {
+	case StreamCancelledByUser:
+		return fmt.Sprintf("stream stopped by user: %v", e.Err)
+	case StreamCancelledByTimeout:
+		return fmt.Sprintf("stream timed out: %v", e.Err)
+	case StreamCancelledByServer:
+		return fmt.Sprintf("stream closed by server: %v", e.Err)
+	default:
+		return fmt.Sprintf("%s: %v", ErrStreamCancelled, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error
+func (e *StreamCancelledError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrStreamCancelled
+func (e *StreamCancelledError) Is(target error) bool {
+	return target == ErrStreamCancelled
+}
+
+// StreamCancelError returns err from reading a stream for the request with context ctx
+// as a *StreamCancelledError when the stream was aborted: cancellation of ctx is
+// StreamCancelledByUser, its deadline or a network timeout StreamCancelledByTimeout,
+// and a connection closed or reset before the end of the stream
+// StreamCancelledByServer. Other errors, io.EOF and nil are returned unchanged.
+func StreamCancelError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, ErrStreamCancelled) {
+		return err
+	}
+
+	// Reads aborted by the context fail with assorted transport errors, so the
+	// context's state decides
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &StreamCancelledError{Reason: contextCancelReason(ctxErr), Err: err}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &StreamCancelledError{Reason: contextCancelReason(err), Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &StreamCancelledError{Reason: StreamCancelledByTimeout, Err: err}
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return &StreamCancelledError{Reason: StreamCancelledByServer, Err: err}
+	}
+	return err
+}
+
+// contextCancelReason maps a context error to its cancel reason
+func contextCancelReason(err error) StreamCancelReason {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return StreamCancelledByTimeout
+	}
+	return StreamCancelledByUser
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeoutError is a net.Error reporting a timeout, as http.Client.Timeout produces
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestStreamCancelError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		reason StreamCancelReason
+	}{
+		{"context cancelled", cancelled, context.Canceled, StreamCancelledByUser},
+		{"read aborted by cancellation", cancelled, errors.New("read: use of closed connection"), StreamCancelledByUser},
+		{"context deadline", expired, context.DeadlineExceeded, StreamCancelledByTimeout},
+		{"client timeout", context.Background(), fmt.Errorf("read body: %w", timeoutError{}), StreamCancelledByTimeout},
+		{"unexpected EOF", context.Background(), io.ErrUnexpectedEOF, StreamCancelledByServer},
+		{"connection reset", context.Background(), fmt.Errorf("read tcp: %w", syscall.ECONNRESET), StreamCancelledByServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := StreamCancelError(tt.ctx, tt.err)
+
+			var streamErr *StreamCancelledError
+			require.ErrorAs(t, err, &streamErr)
+			assert.Equal(t, tt.reason, streamErr.Reason)
+			assert.ErrorIs(t, err, ErrStreamCancelled)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestStreamCancelError_Unchanged(t *testing.T) {
+	ctx := context.Background()
+	other := errors.New("bad chunk")
+
+	assert.NoError(t, StreamCancelError(ctx, nil))
+	assert.Equal(t, io.EOF, StreamCancelError(ctx, io.EOF))
+	assert.Equal(t, other, StreamCancelError(ctx, other))
+
+	// Already classified errors are not wrapped again
+	cancelled := &StreamCancelledError{Reason: StreamCancelledByServer, Err: io.ErrUnexpectedEOF}
+	assert.Same(t, cancelled, StreamCancelError(ctx, cancelled))
+}
+
+func TestStreamCancelledError_Error(t *testing.T) {
+	err := &StreamCancelledError{Reason: StreamCancelledByUser, Err: context.Canceled}
+	assert.Equal(t, "stream stopped by user: context canceled", err.Error())
+}
@@ -0,0 +1,303 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// StreamToCallback drives a ChatCompletionStream to completion, invoking callback
+// for each chunk. It stops when the stream reports io.EOF, after delivering a chunk
+// with Done set, or when Next or the callback returns an error.
+//
+// The stream is always closed before StreamToCallback returns, so callers do not
+// need to Close it themselves. Returning an error from the callback therefore
+// closes the stream, which aborts the underlying request and cancels generation.
+// The callback's error is returned unchanged so it can be matched with errors.Is.
+//
+//	err := types.StreamToCallback(stream, func(chunk types.ChatCompletionChunk) error {
+//	    fmt.Print(chunk.Content)
+//	    return nil
+//	})
+func StreamToCallback(stream ChatCompletionStream, callback func(ChatCompletionChunk) error) error {
+	defer func() { _ = stream.Close() }()
+
+	for {
+		chunk, err := stream.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// Streams may return their last chunk, e.g. the one with usage, with io.EOF
+				if chunkHasData(chunk) {
+					return callback(chunk)
+				}
+				return nil
+			}
+			return err
+		}
+
+		if err := callback(chunk); err != nil {
+			return err
+		}
+
+		if chunk.Done {
+			return nil
+		}
+	}
+}
+
+// chunkHasData reports whether chunk carries anything beyond marking the end of the
+// stream
+func chunkHasData(chunk ChatCompletionChunk) bool {
+	return chunk.Content != "" || chunk.Reasoning != "" || chunk.ReasoningContent != "" ||
+		chunk.Error != "" || len(chunk.Choices) > 0 || chunk.Usage != (Usage{}) ||
+		len(chunk.CodeExecutions) > 0 || len(chunk.ServerToolUses) > 0 ||
+		len(chunk.Citations) > 0 || len(chunk.Warnings) > 0
+}
+
+// streamChannelBuffer is the chunk channel capacity used by StreamChannel
+const streamChannelBuffer = 16
+
+// StreamChannel forwards the chunks of a ChatCompletionStream onto a buffered channel
+// for pipeline-style consumption with range. A forwarding goroutine reads the stream
+// until io.EOF, a chunk with Done set, an error, or cancellation of ctx, then closes
+// the stream and both channels. A terminal error (including ctx.Err() on cancellation)
+// is sent on the error channel before it is closed; a clean end of stream sends nothing.
+//
+// Callers must either drain the chunk channel or cancel ctx; otherwise the forwarding
+// goroutine blocks on a full channel and leaks along with the open stream.
+//
+//	chunks, errs := types.StreamChannel(ctx, stream)
+//	for chunk := range chunks {
+//	    fmt.Print(chunk.Content)
+//	}
+//	if err := <-errs; err != nil {
+//	    return err
+//	}
+func StreamChannel(ctx context.Context, stream ChatCompletionStream) (<-chan ChatCompletionChunk, <-chan error) {
+	chunks := make(chan ChatCompletionChunk, streamChannelBuffer)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(chunks)
+
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			select {
+			case chunks <- chunk:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return chunks, errs
+}
+
+// TeeStream wraps a stream so its content is also captured for logging or storage.
+// Chunks are returned by Next unchanged while their content is accumulated; when the
+// stream is closed, the full text is written to sink in a single Write, and sink is
+// flushed if it has a Flush() error method (as *bufio.Writer does). The text is
+// written once, even if Close is called again, and also when the stream ended early
+// with an error, so partial responses are captured too.
+//
+//	var transcript strings.Builder
+//	stream = types.TeeStream(stream, &transcript)
+//	err := types.StreamToCallback(stream, func(chunk types.ChatCompletionChunk) error {
+//	    fmt.Print(chunk.Content)
+//	    return nil
+//	})
+//	saveResponse(transcript.String())
+func TeeStream(stream ChatCompletionStream, sink io.Writer) ChatCompletionStream {
+	return &teeStream{ChatCompletionStream: stream, sink: sink}
+}
+
+// teeStream accumulates a stream's content and writes it to a sink on Close
+type teeStream struct {
+	ChatCompletionStream
+	sink    io.Writer
+	content strings.Builder
+	flushed bool
+}
+
+// Next returns the next chunk, accumulating its content
+func (s *teeStream) Next() (ChatCompletionChunk, error) {
+	chunk, err := s.ChatCompletionStream.Next()
+	s.content.WriteString(chunk.Content)
+	return chunk, err
+}
+
+// Close closes the underlying stream and writes the accumulated content to the sink
+func (s *teeStream) Close() error {
+	closeErr := s.ChatCompletionStream.Close()
+	if s.flushed {
+		return closeErr
+	}
+	s.flushed = true
+
+	if _, err := io.WriteString(s.sink, s.content.String()); err != nil {
+		return err
+	}
+	if flusher, ok := s.sink.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return err
+		}
+	}
+	return closeErr
+}
+
+// smoothStreamTick is roughly how often SmoothStream delivers a piece of content. At
+// high rates a piece holds several characters rather than sleeping per character.
+const smoothStreamTick = 20 * time.Millisecond
+
+// SmoothStream wraps a stream so its content is delivered at a steady charsPerSecond,
+// for typewriter-style UIs fed by providers that send tokens in bursts. Chunks with
+// more content than one tick's worth are split into several chunks, the last of which
+// keeps the original's Done, Usage, finish reason and other fields; chunks without
+// content are passed through immediately. Pacing never delays content that arrives
+// slower than the target rate.
+//
+// While waiting, Next returns ctx.Err() once ctx is cancelled, and io.EOF once the
+// stream is closed. A charsPerSecond of zero or less returns stream unchanged.
+//
+//	stream = types.SmoothStream(ctx, stream, 200)
+//	err := types.StreamToCallback(stream, func(chunk types.ChatCompletionChunk) error {
+//	    fmt.Print(chunk.Content)
+//	    return nil
+//	})
+func SmoothStream(ctx context.Context, stream ChatCompletionStream, charsPerSecond int) ChatCompletionStream {
+	if charsPerSecond <= 0 {
+		return stream
+	}
+
+	pieceSize := int(int64(charsPerSecond) * int64(smoothStreamTick) / int64(time.Second))
+	if pieceSize < 1 {
+		pieceSize = 1
+	}
+	return &smoothStream{
+		ChatCompletionStream: stream,
+		ctx:                  ctx,
+		perChar:              time.Second / time.Duration(charsPerSecond),
+		pieceSize:            pieceSize,
+		closed:               make(chan struct{}),
+	}
+}
+
+// smoothStream paces the content of a stream
+type smoothStream struct {
+	ChatCompletionStream
+	ctx       context.Context
+	perChar   time.Duration
+	pieceSize int
+
+	pending []ChatCompletionChunk // Pieces of a split chunk not yet returned
+	due     time.Time             // When the next piece may be returned
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Next returns the next piece of content once it is due
+func (s *smoothStream) Next() (ChatCompletionChunk, error) {
+	if len(s.pending) == 0 {
+		chunk, err := s.ChatCompletionStream.Next()
+		if err != nil || chunk.Content == "" {
+			return chunk, err
+		}
+		s.pending = splitChunkContent(chunk, s.pieceSize)
+	}
+
+	chunk := s.pending[0]
+	if err := s.wait(); err != nil {
+		s.pending = nil
+		return ChatCompletionChunk{Done: true}, err
+	}
+	s.pending = s.pending[1:]
+	s.due = s.due.Add(time.Duration(utf8.RuneCountInString(chunk.Content)) * s.perChar)
+	return chunk, nil
+}
+
+// wait blocks until the next piece is due
+func (s *smoothStream) wait() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !s.due.After(now) {
+		// Content that arrived late is not owed; pace from now
+		s.due = now
+		return nil
+	}
+
+	timer := time.NewTimer(s.due.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	case <-s.closed:
+		return io.EOF
+	}
+}
+
+// Close closes the underlying stream, ending any wait in Next
+func (s *smoothStream) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return s.ChatCompletionStream.Close()
+}
+
+// splitChunkContent splits chunk into chunks of at most size characters of content.
+// The last keeps every field of chunk; the others carry only its identity and content.
+// A single choice whose delta mirrors Content is split along with it.
+func splitChunkContent(chunk ChatCompletionChunk, size int) []ChatCompletionChunk {
+	runes := []rune(chunk.Content)
+	if len(runes) <= size {
+		return []ChatCompletionChunk{chunk}
+	}
+	mirrored := len(chunk.Choices) == 1 && chunk.Choices[0].Delta.Content == chunk.Content
+
+	pieces := make([]ChatCompletionChunk, 0, (len(runes)+size-1)/size)
+	for start := 0; start < len(runes); start += size {
+		content := string(runes[start:min(start+size, len(runes))])
+
+		if start+size >= len(runes) {
+			last := chunk
+			last.Content = content
+			if mirrored {
+				last.Choices = []ChatChoice{chunk.Choices[0]}
+				last.Choices[0].Delta.Content = content
+			}
+			pieces = append(pieces, last)
+			break
+		}
+
+		piece := ChatCompletionChunk{
+			ID:      chunk.ID,
+			Object:  chunk.Object,
+			Created: chunk.Created,
+			Model:   chunk.Model,
+			Content: content,
+		}
+		if mirrored {
+			choice := chunk.Choices[0]
+			piece.Choices = []ChatChoice{{
+				Index: choice.Index,
+				Delta: ChatMessage{Role: choice.Delta.Role, Content: content},
+			}}
+		}
+		pieces = append(pieces, piece)
+	}
+	return pieces
+}
@@ -0,0 +1,343 @@
+package types
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceStream is a ChatCompletionStream backed by a slice of chunks
+type sliceStream struct {
+	chunks []ChatCompletionChunk
+	err    error                // returned once chunks are exhausted (defaults to io.EOF)
+	final  *ChatCompletionChunk // returned with io.EOF instead of an empty Done chunk
+	index  int
+	closed bool
+}
+
+func (s *sliceStream) Next() (ChatCompletionChunk, error) {
+	if s.closed {
+		return ChatCompletionChunk{}, errors.New("stream closed")
+	}
+	if s.index >= len(s.chunks) {
+		if s.err != nil {
+			return ChatCompletionChunk{}, s.err
+		}
+		if s.final != nil {
+			return *s.final, io.EOF
+		}
+		return ChatCompletionChunk{Done: true}, io.EOF
+	}
+	chunk := s.chunks[s.index]
+	s.index++
+	return chunk, nil
+}
+
+func (s *sliceStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestStreamToCallback(t *testing.T) {
+	t.Run("delivers all chunks until EOF", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "Hello"}, {Content: ", world"}}}
+
+		var content string
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+			content += chunk.Content
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, world", content)
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("stops after done chunk", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{
+			{Content: "a"},
+			{Content: "b", Done: true},
+			{Content: "never delivered"},
+		}}
+
+		var received []string
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+			received = append(received, chunk.Content)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, received)
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("delivers the chunk returned with EOF", func(t *testing.T) {
+		stream := &sliceStream{
+			chunks: []ChatCompletionChunk{{Content: "Hi"}},
+			final:  &ChatCompletionChunk{Done: true, Usage: Usage{PromptTokens: 2, CompletionTokens: 3, TotalTokens: 5}},
+		}
+
+		var received []ChatCompletionChunk
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+			received = append(received, chunk)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, received, 2)
+		assert.True(t, received[1].Done)
+		assert.Equal(t, 5, received[1].Usage.TotalTokens)
+
+		// An empty end-of-stream chunk is not delivered
+		received = nil
+		err = StreamToCallback(&sliceStream{chunks: []ChatCompletionChunk{{Content: "Hi"}}}, func(chunk ChatCompletionChunk) error {
+			received = append(received, chunk)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Len(t, received, 1)
+	})
+
+	t.Run("callback error closes stream and is returned", func(t *testing.T) {
+		stopErr := errors.New("stop")
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "a"}, {Content: "b"}, {Content: "c"}}}
+
+		calls := 0
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+			calls++
+			if chunk.Content == "b" {
+				return stopErr
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, stopErr)
+		assert.Equal(t, 2, calls)
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("stream error is returned", func(t *testing.T) {
+		streamErr := errors.New("connection reset")
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "a"}}, err: streamErr}
+
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error { return nil })
+
+		assert.ErrorIs(t, err, streamErr)
+		assert.True(t, stream.closed)
+	})
+}
+
+func TestStreamChannel(t *testing.T) {
+	t.Run("forwards chunks and closes channels on EOF", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "a"}, {Content: "b"}, {Content: "c"}}}
+
+		chunks, errs := StreamChannel(context.Background(), stream)
+
+		var content string
+		for chunk := range chunks {
+			content += chunk.Content
+		}
+		err, ok := <-errs
+
+		assert.Equal(t, "abc", content)
+		assert.NoError(t, err)
+		assert.False(t, ok, "error channel should be closed without a value")
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("reports terminal stream error", func(t *testing.T) {
+		streamErr := errors.New("connection reset")
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "a"}}, err: streamErr}
+
+		chunks, errs := StreamChannel(context.Background(), stream)
+
+		count := 0
+		for range chunks {
+			count++
+		}
+
+		assert.Equal(t, 1, count)
+		assert.ErrorIs(t, <-errs, streamErr)
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("cancellation stops forwarding", func(t *testing.T) {
+		// More chunks than the buffer holds so the goroutine blocks on send
+		many := make([]ChatCompletionChunk, streamChannelBuffer*4)
+		stream := &sliceStream{chunks: many}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, errs := StreamChannel(ctx, stream)
+
+		<-chunks
+		cancel()
+
+		// Draining after cancellation must terminate
+		for range chunks {
+		}
+
+		require.ErrorIs(t, <-errs, context.Canceled)
+		assert.True(t, stream.closed)
+		assert.Less(t, stream.index, len(many))
+	})
+}
+
+func TestTeeStream(t *testing.T) {
+	t.Run("forwards chunks and writes the full text on close", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{
+			{Content: "Hello"},
+			{Content: ", "},
+			{Content: "world", Done: true},
+		}}
+		var sink strings.Builder
+
+		var displayed string
+		err := StreamToCallback(TeeStream(stream, &sink), func(chunk ChatCompletionChunk) error {
+			displayed += chunk.Content
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Hello, world", displayed)
+		assert.Equal(t, "Hello, world", sink.String())
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("nothing is written before close", func(t *testing.T) {
+		var sink strings.Builder
+		tee := TeeStream(&sliceStream{chunks: []ChatCompletionChunk{{Content: "partial"}}}, &sink)
+
+		_, err := tee.Next()
+		require.NoError(t, err)
+		assert.Empty(t, sink.String())
+
+		require.NoError(t, tee.Close())
+		require.NoError(t, tee.Close())
+		assert.Equal(t, "partial", sink.String(), "content must be written once")
+	})
+
+	t.Run("partial content is captured on error", func(t *testing.T) {
+		streamErr := errors.New("connection reset")
+		var sink strings.Builder
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "cut "}, {Content: "off"}}, err: streamErr}
+
+		err := StreamToCallback(TeeStream(stream, &sink), func(ChatCompletionChunk) error { return nil })
+		assert.ErrorIs(t, err, streamErr)
+		assert.Equal(t, "cut off", sink.String())
+	})
+
+	t.Run("flushes buffered sinks", func(t *testing.T) {
+		var out strings.Builder
+		buffered := bufio.NewWriter(&out)
+		tee := TeeStream(&sliceStream{chunks: []ChatCompletionChunk{{Content: "logged", Done: true}}}, buffered)
+
+		require.NoError(t, StreamToCallback(tee, func(ChatCompletionChunk) error { return nil }))
+		assert.Equal(t, "logged", out.String())
+	})
+}
+
+func TestSmoothStream(t *testing.T) {
+	t.Run("splits bursts and paces delivery", func(t *testing.T) {
+		// 1000 chars/s delivers 20 characters every 20ms
+		content := strings.Repeat("x", 100)
+		stream := &sliceStream{chunks: []ChatCompletionChunk{
+			{ID: "c1", Content: content, Choices: []ChatChoice{{Delta: ChatMessage{Role: "assistant", Content: content}}}},
+			{Done: true, Usage: Usage{TotalTokens: 30}},
+		}}
+
+		start := time.Now()
+		var pieces []ChatCompletionChunk
+		err := StreamToCallback(SmoothStream(context.Background(), stream, 1000), func(chunk ChatCompletionChunk) error {
+			pieces = append(pieces, chunk)
+			return nil
+		})
+		elapsed := time.Since(start)
+
+		require.NoError(t, err)
+		require.Len(t, pieces, 6)
+		var text string
+		for _, piece := range pieces[:5] {
+			assert.Equal(t, "c1", piece.ID)
+			assert.Len(t, piece.Content, 20)
+			assert.Equal(t, piece.Content, piece.Choices[0].Delta.Content)
+			text += piece.Content
+		}
+		assert.Equal(t, content, text)
+		assert.True(t, pieces[5].Done)
+		assert.Equal(t, 30, pieces[5].Usage.TotalTokens)
+		assert.GreaterOrEqual(t, elapsed, 80*time.Millisecond)
+	})
+
+	t.Run("last piece keeps the chunk's fields", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{
+			{Content: "héllo wörld", Done: true, Choices: []ChatChoice{{FinishReason: "stop"}}},
+		}}
+		smooth := SmoothStream(context.Background(), stream, 50) // one character per piece
+
+		var text string
+		var last ChatCompletionChunk
+		for i := 0; i < 11; i++ {
+			chunk, err := smooth.Next()
+			require.NoError(t, err)
+			assert.Equal(t, i == 10, chunk.Done)
+			text += chunk.Content
+			last = chunk
+		}
+		assert.Equal(t, "héllo wörld", text)
+		require.Len(t, last.Choices, 1)
+		assert.Equal(t, "stop", last.Choices[0].FinishReason)
+	})
+
+	t.Run("returns context error while waiting", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "abcdef"}}}
+		ctx, cancel := context.WithCancel(context.Background())
+		smooth := SmoothStream(ctx, stream, 1) // one character a second
+
+		chunk, err := smooth.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "a", chunk.Content)
+
+		time.AfterFunc(10*time.Millisecond, cancel)
+		start := time.Now()
+		_, err = smooth.Next()
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("close ends a wait", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "abc"}}}
+		smooth := SmoothStream(context.Background(), stream, 1)
+
+		_, err := smooth.Next()
+		require.NoError(t, err)
+
+		time.AfterFunc(10*time.Millisecond, func() { _ = smooth.Close() })
+		_, err = smooth.Next()
+		assert.ErrorIs(t, err, io.EOF)
+		assert.True(t, stream.closed)
+	})
+
+	t.Run("passes slow content through without delay", func(t *testing.T) {
+		stream := &sliceStream{chunks: []ChatCompletionChunk{{Content: "a"}, {Content: "b"}}}
+		smooth := SmoothStream(context.Background(), stream, 1000)
+
+		start := time.Now()
+		for i := 0; i < 2; i++ {
+			_, err := smooth.Next()
+			require.NoError(t, err)
+		}
+		assert.Less(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("non-positive rate returns the stream unchanged", func(t *testing.T) {
+		stream := &sliceStream{}
+		assert.Same(t, stream, SmoothStream(context.Background(), stream, 0))
+	})
+}
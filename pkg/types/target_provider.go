@@ -0,0 +1,20 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindTargetProvider returns the provider among a virtual provider's children whose
+// Name is name, for honoring GenerateOptions.TargetProvider. It returns a
+// *ValidationError, listing the known names, if none matches.
+func FindTargetProvider(providers []Provider, name string) (Provider, error) {
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+		names[i] = provider.Name()
+	}
+	return nil, NewValidationError(fmt.Sprintf("unknown target provider %q: must be one of %s", name, strings.Join(names, ", ")))
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrTokenBudgetExceeded is matched (with errors.Is) by the error returned once a
+// TokenBudget is used up
+var ErrTokenBudgetExceeded = errors.New("token budget exceeded")
+
+// TokenBudgetExceededError reports how many tokens were consumed against the budget
+type TokenBudgetExceededError struct {
+	Limit int
+	Used  int
+}
+
+// Error implements error
+func (e *TokenBudgetExceededError) Error() string {
+	return fmt.Sprintf("%s: used %d of %d tokens", ErrTokenBudgetExceeded, e.Used, e.Limit)
+}
+
+// Is reports whether target is ErrTokenBudgetExceeded
+func (e *TokenBudgetExceededError) Is(target error) bool {
+	return target == ErrTokenBudgetExceeded
+}
+
+// TokenBudget caps the cumulative token usage of a conversation or agent loop, as a
+// safety rail against a model that keeps calling tools. Record the usage of each turn
+// with Add (or wrap streams with Stream) and stop the loop when an error is returned:
+//
+//	budget := types.NewTokenBudget(200_000)
+//	for {
+//	    if err := budget.Check(); err != nil {
+//	        return err
+//	    }
+//	    stream, err := provider.GenerateChatCompletion(ctx, options)
+//	    ...
+//	    err = types.StreamToCallback(budget.Stream(stream), handleChunk)
+//	}
+//
+// A TokenBudget is safe for concurrent use, so it can be shared by parallel turns.
+type TokenBudget struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+}
+
+// NewTokenBudget creates a budget of limit tokens. A limit of zero or less is unlimited.
+func NewTokenBudget(limit int) *TokenBudget {
+	return &TokenBudget{limit: limit}
+}
+
+// Add records the tokens of usage and returns a *TokenBudgetExceededError once the
+// total reaches the limit
+func (b *TokenBudget) Add(usage Usage) error {
+	tokens := usage.TotalTokens
+	if tokens == 0 {
+		tokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return b.AddTokens(tokens)
+}
+
+// AddTokens records a number of tokens and returns a *TokenBudgetExceededError once
+// the total reaches the limit
+func (b *TokenBudget) AddTokens(tokens int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if tokens > 0 {
+		b.used += tokens
+	}
+	return b.check()
+}
+
+// Check returns a *TokenBudgetExceededError if the budget is used up. Call it before
+// starting another turn.
+func (b *TokenBudget) Check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.check()
+}
+
+func (b *TokenBudget) check() error {
+	if b.limit > 0 && b.used >= b.limit {
+		return &TokenBudgetExceededError{Limit: b.limit, Used: b.used}
+	}
+	return nil
+}
+
+// Limit returns the budget's limit, zero or less when unlimited
+func (b *TokenBudget) Limit() int {
+	return b.limit
+}
+
+// Used returns the tokens recorded so far
+func (b *TokenBudget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Remaining returns the tokens left, or -1 when the budget is unlimited
+func (b *TokenBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit <= 0 {
+		return -1
+	}
+	if b.used >= b.limit {
+		return 0
+	}
+	return b.limit - b.used
+}
+
+// Stream wraps a stream so the usage it reports is added to the budget. Providers
+// report usage on the final chunk or cumulatively across chunks, so only increases
+// over the usage already seen on the stream are added. Once the budget is used up,
+// the chunk is returned along with a *TokenBudgetExceededError, as is every later
+// call to Next; the caller closes the stream as usual.
+func (b *TokenBudget) Stream(stream ChatCompletionStream) ChatCompletionStream {
+	return &budgetedStream{ChatCompletionStream: stream, budget: b}
+}
+
+// budgetedStream records a stream's usage against a TokenBudget
+type budgetedStream struct {
+	ChatCompletionStream
+	budget   *TokenBudget
+	recorded int
+	err      error
+}
+
+// Next returns the next chunk, or the budget error once the budget is used up
+func (s *budgetedStream) Next() (ChatCompletionChunk, error) {
+	if s.err != nil {
+		return ChatCompletionChunk{Done: true}, s.err
+	}
+
+	chunk, err := s.ChatCompletionStream.Next()
+	tokens := chunk.Usage.TotalTokens
+	if tokens == 0 {
+		tokens = chunk.Usage.PromptTokens + chunk.Usage.CompletionTokens
+	}
+	if tokens > s.recorded {
+		budgetErr := s.budget.AddTokens(tokens - s.recorded)
+		s.recorded = tokens
+		if budgetErr != nil {
+			s.err = budgetErr
+			return chunk, budgetErr
+		}
+	}
+	return chunk, err
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBudget_Add(t *testing.T) {
+	budget := NewTokenBudget(1000)
+
+	require.NoError(t, budget.Add(Usage{PromptTokens: 300, CompletionTokens: 100, TotalTokens: 400}))
+	assert.Equal(t, 400, budget.Used())
+	assert.Equal(t, 600, budget.Remaining())
+	require.NoError(t, budget.Check())
+
+	// TotalTokens missing: prompt and completion are summed
+	require.NoError(t, budget.Add(Usage{PromptTokens: 400, CompletionTokens: 100}))
+	assert.Equal(t, 900, budget.Used())
+
+	err := budget.Add(Usage{TotalTokens: 250})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTokenBudgetExceeded))
+
+	var exceeded *TokenBudgetExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, 1000, exceeded.Limit)
+	assert.Equal(t, 1150, exceeded.Used)
+	assert.Contains(t, err.Error(), "used 1150 of 1000 tokens")
+
+	assert.Equal(t, 0, budget.Remaining())
+	assert.ErrorIs(t, budget.Check(), ErrTokenBudgetExceeded)
+}
+
+func TestTokenBudget_Unlimited(t *testing.T) {
+	budget := NewTokenBudget(0)
+
+	require.NoError(t, budget.AddTokens(1_000_000))
+	require.NoError(t, budget.Check())
+	assert.Equal(t, -1, budget.Remaining())
+	assert.Equal(t, 1_000_000, budget.Used())
+}
+
+func TestTokenBudget_Concurrent(t *testing.T) {
+	budget := NewTokenBudget(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = budget.AddTokens(10)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 500, budget.Used())
+}
+
+func TestTokenBudget_Stream(t *testing.T) {
+	t.Run("records cumulative usage once", func(t *testing.T) {
+		budget := NewTokenBudget(1000)
+		stream := budget.Stream(&sliceStream{chunks: []ChatCompletionChunk{
+			{Content: "a", Usage: Usage{PromptTokens: 100, TotalTokens: 100}},
+			{Content: "b"},
+			{Done: true, Usage: Usage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150}},
+		}})
+
+		var content string
+		err := StreamToCallback(stream, func(chunk ChatCompletionChunk) error {
+			content += chunk.Content
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ab", content)
+		assert.Equal(t, 150, budget.Used())
+	})
+
+	t.Run("aborts once the budget is used up", func(t *testing.T) {
+		budget := NewTokenBudget(200)
+		require.NoError(t, budget.AddTokens(150))
+
+		inner := &sliceStream{chunks: []ChatCompletionChunk{
+			{Content: "a", Usage: Usage{TotalTokens: 80}},
+			{Content: "b"},
+		}}
+		stream := budget.Stream(inner)
+
+		chunk, err := stream.Next()
+		assert.Equal(t, "a", chunk.Content)
+		assert.ErrorIs(t, err, ErrTokenBudgetExceeded)
+
+		_, err = stream.Next()
+		assert.ErrorIs(t, err, ErrTokenBudgetExceeded)
+		assert.Equal(t, 230, budget.Used())
+
+		require.NoError(t, stream.Close())
+		assert.True(t, inner.closed)
+	})
+}
+
+func TestTokenBudget_AgentLoop(t *testing.T) {
+	budget := NewTokenBudget(1000)
+
+	turns := 0
+	var err error
+	for turns < 100 {
+		if err = budget.Check(); err != nil {
+			break
+		}
+		turns++
+		// Each tool turn resends the growing conversation
+		if err = budget.Add(Usage{TotalTokens: 150 * turns}); err != nil {
+			break
+		}
+	}
+
+	require.ErrorIs(t, err, ErrTokenBudgetExceeded)
+	assert.Equal(t, 4, turns)
+	assert.Equal(t, 1500, budget.Used())
+}
@@ -0,0 +1,12 @@
+package types
+
+// Tokenizer counts tokens the way a model does. The SDK only ships a heuristic
+// (utils.HeuristicTokenizer); implement Tokenizer to plug in an exact tokenizer such
+// as tiktoken for OpenAI models, and set it on ProviderConfig.Tokenizer.
+type Tokenizer interface {
+	// CountTokens returns the number of tokens in text
+	CountTokens(text string) int
+
+	// CountMessages returns the number of tokens the messages occupy in a request
+	CountMessages(messages []ChatMessage) int
+}
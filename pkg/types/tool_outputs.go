@@ -0,0 +1,44 @@
+package types
+
+import "context"
+
+// ToolOutput is the result of a tool call, sent back to the model
+type ToolOutput struct {
+	// ToolCallID is the ID of the ToolCall this is the result of
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
+// StatefulToolProvider is an optional interface for providers that keep a response's
+// state server-side, such as OpenAI's Responses API, so tool outputs can continue the
+// response without resending the conversation.
+type StatefulToolProvider interface {
+	// IsStoredResponse reports whether responseID is of a response stored by the
+	// provider, which SubmitToolOutputs can continue
+	IsStoredResponse(responseID string) bool
+
+	// SubmitToolOutputs continues the response with the given ID, the ID of the chunks
+	// that made the tool calls, and streams the rest of it. options is the request that
+	// made the tool calls, for its model, tools and settings. The continuation's chunks
+	// carry the ID to submit any further tool outputs to.
+	SubmitToolOutputs(ctx context.Context, responseID string, options GenerateOptions, outputs []ToolOutput) (ChatCompletionStream, error)
+}
+
+// SubmitToolOutputs continues a conversation with tool outputs. If provider implements
+// StatefulToolProvider, supports the Responses API and stored the response with
+// responseID, the outputs are submitted to the stored response. Otherwise they are appended to
+// options.Messages as tool messages and the whole conversation is resent, so options
+// must be the request that made the tool calls with the assistant's message appended.
+func SubmitToolOutputs(ctx context.Context, provider Provider, responseID string, options GenerateOptions, outputs []ToolOutput) (ChatCompletionStream, error) {
+	if stateful, ok := provider.(StatefulToolProvider); ok && responseID != "" && provider.SupportsResponsesAPI() && stateful.IsStoredResponse(responseID) {
+		return stateful.SubmitToolOutputs(ctx, responseID, options, outputs)
+	}
+
+	messages := make([]ChatMessage, len(options.Messages), len(options.Messages)+len(outputs))
+	copy(messages, options.Messages)
+	for _, output := range outputs {
+		messages = append(messages, ChatMessage{Role: "tool", Content: output.Output, ToolCallID: output.ToolCallID})
+	}
+	options.Messages = messages
+	return provider.GenerateChatCompletion(ctx, options)
+}
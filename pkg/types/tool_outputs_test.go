@@ -0,0 +1,77 @@
+package types
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolOutputsProvider records how tool outputs were sent
+type toolOutputsProvider struct {
+	mockProvider
+	responsesAPI bool
+	generated    *GenerateOptions
+	submittedTo  string
+	submitted    []ToolOutput
+}
+
+func (p *toolOutputsProvider) SupportsResponsesAPI() bool { return p.responsesAPI }
+
+func (p *toolOutputsProvider) GenerateChatCompletion(ctx context.Context, options GenerateOptions) (ChatCompletionStream, error) {
+	p.generated = &options
+	return nil, nil
+}
+
+func (p *toolOutputsProvider) IsStoredResponse(responseID string) bool {
+	return strings.HasPrefix(responseID, "resp_")
+}
+
+func (p *toolOutputsProvider) SubmitToolOutputs(ctx context.Context, responseID string, options GenerateOptions, outputs []ToolOutput) (ChatCompletionStream, error) {
+	p.submittedTo = responseID
+	p.submitted = outputs
+	return nil, nil
+}
+
+func TestSubmitToolOutputs(t *testing.T) {
+	outputs := []ToolOutput{{ToolCallID: "call_1", Output: `{"temp":21}`}}
+	options := GenerateOptions{Messages: []ChatMessage{
+		{Role: "user", Content: "Weather in Paris?"},
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}}},
+	}}
+
+	t.Run("stateful", func(t *testing.T) {
+		provider := &toolOutputsProvider{responsesAPI: true}
+		_, err := SubmitToolOutputs(context.Background(), provider, "resp_1", options, outputs)
+		require.NoError(t, err)
+		assert.Equal(t, "resp_1", provider.submittedTo)
+		assert.Equal(t, outputs, provider.submitted)
+		assert.Nil(t, provider.generated)
+	})
+
+	for name, tt := range map[string]struct {
+		responsesAPI bool
+		responseID   string
+	}{
+		"without the Responses API": {false, "resp_1"},
+		"without a response ID":     {true, ""},
+		"with a chat completion ID": {true, "chatcmpl-1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			provider := &toolOutputsProvider{responsesAPI: tt.responsesAPI}
+			_, err := SubmitToolOutputs(context.Background(), provider, tt.responseID, options, outputs)
+			require.NoError(t, err)
+			assert.Empty(t, provider.submittedTo)
+			require.NotNil(t, provider.generated)
+			require.Len(t, provider.generated.Messages, 3)
+			assert.Equal(t, ChatMessage{Role: "tool", Content: `{"temp":21}`, ToolCallID: "call_1"}, provider.generated.Messages[2])
+			assert.Len(t, options.Messages, 2, "the caller's messages are not modified")
+		})
+	}
+
+	provider := &mockProvider{}
+	_, err := SubmitToolOutputs(context.Background(), provider, "resp_1", options, outputs)
+	assert.NoError(t, err, "providers without stateful submission resend")
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Warning codes
+const (
+	// WarningDeprecated means the model or endpoint is deprecated and will be removed
+	WarningDeprecated = "deprecated"
+	// WarningModelSubstituted means the response came from a different model than requested
+	WarningModelSubstituted = "model_substituted"
+	// WarningParameterIgnored means a request parameter was not applied
+	WarningParameterIgnored = "parameter_ignored"
+	// WarningDegraded means a request parameter was replaced by a weaker supported one
+	WarningDegraded = "degraded"
+	// WarningTruncated means input or output was truncated
+	WarningTruncated = "truncated"
+	// WarningOther is a provider warning that fits no other code
+	WarningOther = "other"
+)
+
+// Warning is a non-fatal notice about a request, reported by the provider or detected
+// from its response. Warnings never fail a request; they are surfaced so applications
+// can log them, for example to notice a deprecated model before it is removed.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"` // The request parameter concerned, if any
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// WarningsFromHeaders returns the warnings carried by standard response headers:
+// Deprecation (RFC 9745) and Sunset (RFC 8594) as WarningDeprecated, and each
+// Warning header (RFC 7234) as WarningOther
+func WarningsFromHeaders(header http.Header) []Warning {
+	var warnings []Warning
+
+	deprecation := header.Get("Deprecation")
+	sunset := header.Get("Sunset")
+	if deprecation != "" && deprecation != "false" || sunset != "" {
+		message := "the requested model or endpoint is deprecated"
+		if sunset != "" {
+			message += " and will be removed after " + sunset
+		}
+		if link := header.Get("Link"); strings.Contains(link, `rel="deprecation"`) || strings.Contains(link, `rel="sunset"`) {
+			message += " (see " + link + ")"
+		}
+		warnings = append(warnings, Warning{Code: WarningDeprecated, Message: message})
+	}
+
+	for _, value := range header.Values("Warning") {
+		warnings = append(warnings, Warning{Code: WarningOther, Message: warningHeaderText(value)})
+	}
+
+	return warnings
+}
+
+// warningHeaderText extracts the quoted text of a `299 agent "text"` Warning header,
+// or returns the value unchanged if it is not in that form
+func warningHeaderText(value string) string {
+	start := strings.Index(value, `"`)
+	end := strings.LastIndex(value, `"`)
+	if start >= 0 && end > start {
+		return value[start+1 : end]
+	}
+	return value
+}
+
+// ModelSubstitutionWarning returns a WarningModelSubstituted warning when a response
+// was served by a different model than requested, or nil. A served model that is a
+// dated or versioned snapshot of the requested alias (gpt-4o served as
+// gpt-4o-2024-08-06, or claude-3-5-sonnet-latest as claude-3-5-sonnet-20241022) is
+// not a substitution.
+func ModelSubstitutionWarning(requested, served string) *Warning {
+	if requested == "" || served == "" || requested == served {
+		return nil
+	}
+	alias := strings.TrimSuffix(requested, "-latest")
+	if strings.HasPrefix(served, alias+"-") || strings.HasPrefix(served, alias+"@") {
+		return nil
+	}
+	return &Warning{
+		Code:    WarningModelSubstituted,
+		Message: fmt.Sprintf("requested model %s but the response came from %s", requested, served),
+		Param:   "model",
+	}
+}
+
+// StreamWithWarnings attaches warnings to the first chunk of a stream, for warnings
+// known before the stream is read, such as those in the response headers. The stream
+// is returned unchanged when there are no warnings.
+func StreamWithWarnings(stream ChatCompletionStream, warnings []Warning) ChatCompletionStream {
+	if len(warnings) == 0 {
+		return stream
+	}
+	return &warningStream{ChatCompletionStream: stream, warnings: warnings}
+}
+
+// warningStream adds warnings to the first chunk of a stream
+type warningStream struct {
+	ChatCompletionStream
+	warnings []Warning
+}
+
+func (s *warningStream) Next() (ChatCompletionChunk, error) {
+	chunk, err := s.ChatCompletionStream.Next()
+	if s.warnings != nil && err == nil {
+		chunk.Warnings = append(s.warnings, chunk.Warnings...)
+		s.warnings = nil
+	}
+	return chunk, err
+}
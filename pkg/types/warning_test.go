@@ -0,0 +1,89 @@
+package types
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningsFromHeaders(t *testing.T) {
+	t.Run("no warnings", func(t *testing.T) {
+		assert.Empty(t, WarningsFromHeaders(http.Header{}))
+	})
+
+	t.Run("deprecation and sunset", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Deprecation", "@1735689600")
+		header.Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+
+		warnings := WarningsFromHeaders(header)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, WarningDeprecated, warnings[0].Code)
+		assert.Contains(t, warnings[0].Message, "will be removed after Wed, 01 Jul 2026 00:00:00 GMT")
+	})
+
+	t.Run("warning headers", func(t *testing.T) {
+		header := http.Header{}
+		header.Add("Warning", `299 api.example.com "temperature is ignored for this model"`)
+		header.Add("Warning", "unquoted text")
+
+		warnings := WarningsFromHeaders(header)
+		require.Len(t, warnings, 2)
+		assert.Equal(t, Warning{Code: WarningOther, Message: "temperature is ignored for this model"}, warnings[0])
+		assert.Equal(t, "unquoted text", warnings[1].Message)
+	})
+}
+
+func TestModelSubstitutionWarning(t *testing.T) {
+	tests := []struct {
+		requested, served string
+		substituted       bool
+	}{
+		{"gpt-4o", "gpt-4o", false},
+		{"gpt-4o", "gpt-4o-2024-08-06", false},
+		{"claude-3-5-sonnet-latest", "claude-3-5-sonnet-20241022", false},
+		{"", "gpt-4o", false},
+		{"gpt-4o", "", false},
+		{"gpt-4o", "gpt-4o-mini", false}, // Indistinguishable from a snapshot by name alone
+		{"gpt-4", "gpt-3.5-turbo", true},
+		{"claude-3-opus-latest", "claude-3-5-sonnet-20241022", true},
+	}
+
+	for _, tt := range tests {
+		warning := ModelSubstitutionWarning(tt.requested, tt.served)
+		if !tt.substituted {
+			assert.Nil(t, warning, "%s served as %s", tt.requested, tt.served)
+			continue
+		}
+		require.NotNil(t, warning, "%s served as %s", tt.requested, tt.served)
+		assert.Equal(t, WarningModelSubstituted, warning.Code)
+		assert.Equal(t, "model", warning.Param)
+		assert.Contains(t, warning.Message, tt.served)
+	}
+}
+
+func TestStreamWithWarnings(t *testing.T) {
+	warnings := []Warning{{Code: WarningDeprecated, Message: "deprecated"}}
+
+	stream := StreamWithWarnings(&sliceStream{chunks: []ChatCompletionChunk{
+		{Content: "a"},
+		{Content: "b", Done: true},
+	}}, warnings)
+
+	chunk, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, warnings, chunk.Warnings)
+
+	chunk, err = stream.Next()
+	require.NoError(t, err)
+	assert.Empty(t, chunk.Warnings, "warnings are reported once")
+
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	base := &sliceStream{}
+	assert.Same(t, base, StreamWithWarnings(base, nil))
+}
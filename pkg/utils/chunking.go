@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ChunkText splits text into chunks of at most maxTokens tokens each, for processing a
+// document too long for one request, e.g. map-reduce summarization. Chunks end at
+// paragraph boundaries where possible, then at sentence boundaries, then between
+// words; only a single word longer than maxTokens is cut mid-word. Each chunk after
+// the first repeats up to overlap tokens from the end of the previous one, in whole
+// sentences or words, so context is not lost at the cut.
+//
+// Tokens are estimated with the byte-length heuristic of EstimateTokensFromString, so
+// chunks are within maxTokens by that estimate only. Use ChunkTextWith with the
+// tokenizer returned by TokenizerFor to count tokens as a provider does.
+// Chunks are trimmed of surrounding whitespace. A maxTokens of zero or less returns
+// the whole text as one chunk.
+//
+//	for _, chunk := range utils.ChunkText(document, 3000, 200) {
+//	    summaries = append(summaries, summarize(chunk))
+//	}
+func ChunkText(text string, maxTokens, overlap int) []string {
+	return ChunkTextWith(text, maxTokens, overlap, chunkEstimator{})
+}
+
+// ChunkTextWith is ChunkText counting tokens with tokenizer, such as the one returned
+// by TokenizerFor. A chunk's tokens are counted as the sum of its parts', which can
+// differ slightly from the count of the whole chunk for exact tokenizers.
+func ChunkTextWith(text string, maxTokens, overlap int, tokenizer types.Tokenizer) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if maxTokens <= 0 {
+		return []string{strings.TrimSpace(text)}
+	}
+	if overlap >= maxTokens {
+		overlap = maxTokens - 1
+	}
+
+	type unit struct {
+		text   string
+		tokens int
+	}
+	var units []unit
+	for _, piece := range splitForChunking(text, maxTokens, tokenizer) {
+		units = append(units, unit{text: piece, tokens: tokenizer.CountTokens(piece)})
+	}
+
+	var chunks []string
+	var current []unit
+	currentTokens := 0
+	emit := func() {
+		var b strings.Builder
+		for _, u := range current {
+			b.WriteString(u.text)
+		}
+		if chunk := strings.TrimSpace(b.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	for _, u := range units {
+		if len(current) > 0 && currentTokens+u.tokens > maxTokens {
+			emit()
+
+			// Carry over the longest tail of the chunk that fits in overlap, never the
+			// whole chunk, then drop from its start until the next unit fits
+			start, tailTokens := len(current), 0
+			for start > 1 && tailTokens+current[start-1].tokens <= overlap {
+				start--
+				tailTokens += current[start].tokens
+			}
+			current = append([]unit(nil), current[start:]...)
+			currentTokens = tailTokens
+			for len(current) > 0 && currentTokens+u.tokens > maxTokens {
+				currentTokens -= current[0].tokens
+				current = current[1:]
+			}
+		}
+		current = append(current, u)
+		currentTokens += u.tokens
+	}
+	emit()
+
+	return chunks
+}
+
+// chunkEstimator is HeuristicTokenizer rounding up rather than down. ChunkTextWith
+// sums the counts of a chunk's parts, and rounding each part down would undercount
+// chunks of many short parts; rounded up, the sum never falls below the estimate of
+// the whole chunk.
+type chunkEstimator struct {
+	HeuristicTokenizer
+}
+
+// CountTokens estimates the tokens in text, rounding up
+func (chunkEstimator) CountTokens(text string) int {
+	return (len(text)*10 + 46) / 47
+}
+
+// splitForChunking splits text into paragraphs, splitting those over maxTokens into
+// sentences, sentences over it into words and words over it into runs of characters.
+// Each piece keeps its trailing whitespace, so the pieces concatenate back to text.
+func splitForChunking(text string, maxTokens int, tokenizer types.Tokenizer) []string {
+	var pieces []string
+	for _, paragraph := range splitAfterBoundaries(text, isParagraphEnd) {
+		if tokenizer.CountTokens(paragraph) <= maxTokens {
+			pieces = append(pieces, paragraph)
+			continue
+		}
+		for _, sentence := range splitAfterBoundaries(paragraph, isSentenceEnd) {
+			if tokenizer.CountTokens(sentence) <= maxTokens {
+				pieces = append(pieces, sentence)
+				continue
+			}
+			for _, word := range splitAfterBoundaries(sentence, isWordEnd) {
+				if tokenizer.CountTokens(word) <= maxTokens {
+					pieces = append(pieces, word)
+					continue
+				}
+				pieces = append(pieces, splitWord(word, maxTokens, tokenizer)...)
+			}
+		}
+	}
+	return pieces
+}
+
+// splitAfterBoundaries cuts text after each run of whitespace at which isEnd reports a
+// boundary. isEnd is given the text before the whitespace and the whitespace itself.
+func splitAfterBoundaries(text string, isEnd func(before, space string) bool) []string {
+	var pieces []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		// Find the end of this run of whitespace
+		end := i
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		if end < len(text) && isEnd(text[start:i], text[i:end]) {
+			pieces = append(pieces, text[start:end])
+			start = end
+		}
+		i = end
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// isParagraphEnd reports a blank line
+func isParagraphEnd(_, space string) bool {
+	return strings.Count(space, "\n") >= 2
+}
+
+// isSentenceEnd reports sentence-ending punctuation, possibly followed by closing
+// quotes or brackets, or a line break
+func isSentenceEnd(before, space string) bool {
+	if strings.Contains(space, "\n") {
+		return true
+	}
+	trimmed := strings.TrimRight(before, `"')]}”’`)
+	return strings.HasSuffix(trimmed, ".") || strings.HasSuffix(trimmed, "!") ||
+		strings.HasSuffix(trimmed, "?") || strings.HasSuffix(trimmed, "…")
+}
+
+// isWordEnd reports any whitespace
+func isWordEnd(_, _ string) bool {
+	return true
+}
+
+// maxChunkRunesPerToken bounds the characters a single token can span
+const maxChunkRunesPerToken = 64
+
+// splitWord cuts a word longer than maxTokens into the longest runs of characters that
+// fit
+func splitWord(word string, maxTokens int, tokenizer types.Tokenizer) []string {
+	var pieces []string
+	runes := []rune(word)
+	for len(runes) > 0 {
+		// Binary search for the longest prefix that fits, taking at least one rune. No
+		// token spans more than maxChunkRunesPerToken runes, which bounds the search.
+		lo, hi := 1, min(len(runes), maxTokens*maxChunkRunesPerToken)
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if tokenizer.CountTokens(string(runes[:mid])) <= maxTokens {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		pieces = append(pieces, string(runes[:lo]))
+		runes = runes[lo:]
+	}
+	return pieces
+}
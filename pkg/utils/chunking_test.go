@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkTextWith(t *testing.T) {
+	t.Run("empty text returns no chunks", func(t *testing.T) {
+		if chunks := ChunkTextWith("  \n\n ", 10, 0, wordTokenizer{}); chunks != nil {
+			t.Errorf("ChunkTextWith() = %q, want nil", chunks)
+		}
+	})
+
+	t.Run("non-positive maxTokens returns the whole text", func(t *testing.T) {
+		chunks := ChunkTextWith("  one two.\n\nthree  ", 0, 0, wordTokenizer{})
+		if len(chunks) != 1 || chunks[0] != "one two.\n\nthree" {
+			t.Errorf("ChunkTextWith() = %q, want the trimmed text", chunks)
+		}
+	})
+
+	t.Run("packs whole paragraphs", func(t *testing.T) {
+		text := "one two three.\n\nfour five.\n\nsix seven eight nine."
+		chunks := ChunkTextWith(text, 5, 0, wordTokenizer{})
+		want := []string{"one two three.\n\nfour five.", "six seven eight nine."}
+		assertChunks(t, chunks, want)
+	})
+
+	t.Run("splits long paragraphs at sentences", func(t *testing.T) {
+		text := "One two three. Four five six! Seven eight? Nine ten."
+		chunks := ChunkTextWith(text, 6, 0, wordTokenizer{})
+		want := []string{"One two three. Four five six!", "Seven eight? Nine ten."}
+		assertChunks(t, chunks, want)
+	})
+
+	t.Run("splits long sentences at words", func(t *testing.T) {
+		chunks := ChunkTextWith("a b c d e f g", 3, 0, wordTokenizer{})
+		want := []string{"a b c", "d e f", "g"}
+		assertChunks(t, chunks, want)
+	})
+
+	t.Run("overlap repeats the end of the previous chunk", func(t *testing.T) {
+		text := "One two. Three four. Five six. Seven eight."
+		chunks := ChunkTextWith(text, 4, 2, wordTokenizer{})
+		want := []string{"One two. Three four.", "Three four. Five six.", "Five six. Seven eight."}
+		assertChunks(t, chunks, want)
+	})
+
+	t.Run("overlap never repeats a whole chunk", func(t *testing.T) {
+		chunks := ChunkTextWith("a b c d", 1, 5, wordTokenizer{})
+		want := []string{"a", "b", "c", "d"}
+		assertChunks(t, chunks, want)
+	})
+}
+
+func TestChunkText(t *testing.T) {
+	t.Run("chunks fit the estimate", func(t *testing.T) {
+		var b strings.Builder
+		for i := 0; i < 200; i++ {
+			b.WriteString("The quick brown fox jumps over the lazy dog. ")
+			if i%7 == 6 {
+				b.WriteString("\n\n")
+			}
+		}
+		text := b.String()
+
+		chunks := ChunkText(text, 50, 10)
+		if len(chunks) < 2 {
+			t.Fatalf("ChunkText() returned %d chunks, want several", len(chunks))
+		}
+		for i, chunk := range chunks {
+			if tokens := EstimateTokensFromString(chunk); tokens > 50 {
+				t.Errorf("chunk %d has %d tokens, want at most 50", i, tokens)
+			}
+			if !strings.HasPrefix(chunk, "The quick") || !strings.HasSuffix(chunk, "dog.") {
+				t.Errorf("chunk %d = %q, want whole sentences", i, chunk)
+			}
+		}
+	})
+
+	t.Run("many short words are not undercounted", func(t *testing.T) {
+		text := strings.Repeat("a ", 1000)
+		for i, chunk := range ChunkText(text, 10, 0) {
+			if tokens := EstimateTokensFromString(chunk); tokens > 10 {
+				t.Errorf("chunk %d has %d tokens, want at most 10", i, tokens)
+			}
+		}
+	})
+
+	t.Run("cuts words longer than maxTokens", func(t *testing.T) {
+		word := strings.Repeat("x", 100)
+		chunks := ChunkText(word, 5, 0)
+		if strings.Join(chunks, "") != word {
+			t.Fatalf("ChunkText() = %q, want pieces of the word", chunks)
+		}
+		for i, chunk := range chunks {
+			if tokens := EstimateTokensFromString(chunk); tokens > 5 {
+				t.Errorf("chunk %d has %d tokens, want at most 5", i, tokens)
+			}
+		}
+	})
+}
+
+func assertChunks(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d chunks %q, want %d %q", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
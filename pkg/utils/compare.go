@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ResponseDiff is how two responses to the same request differ, as returned by
+// CompareResponses. Deltas are b's value minus a's.
+type ResponseDiff struct {
+	PromptTokensDelta     int
+	CompletionTokensDelta int
+	TotalTokensDelta      int
+
+	// TextSimilarity is 1 for texts that are equal once normalized, falling to 0 for
+	// texts with no words in common; see CompareResponses
+	TextSimilarity float64
+
+	// ToolCallsMatch is true when both responses make the same tool calls, by name
+	// and arguments, in any order
+	ToolCallsMatch bool
+
+	// FinishReasonsMatch is true when both responses stopped for the same normalized
+	// reason
+	FinishReasonsMatch bool
+}
+
+// CompareResponses compares two responses to the same request, such as those of an
+// incumbent and a candidate provider, for evaluating a migration or shadow traffic.
+// The first choice of each response is compared; a nil response compares as an empty
+// one.
+//
+// Text similarity is one minus the word-level edit distance between the normalized
+// texts, relative to the longer text. Texts are normalized by lowercasing them and
+// ignoring punctuation and whitespace differences. Tool call arguments are compared
+// as JSON, as ToolCallKey does.
+func CompareResponses(a, b *types.StandardResponse) ResponseDiff {
+	first, second := firstChoice(a), firstChoice(b)
+	usageA, usageB := responseUsage(a), responseUsage(b)
+
+	return ResponseDiff{
+		PromptTokensDelta:     usageB.PromptTokens - usageA.PromptTokens,
+		CompletionTokensDelta: usageB.CompletionTokens - usageA.CompletionTokens,
+		TotalTokensDelta:      usageB.TotalTokens - usageA.TotalTokens,
+		TextSimilarity:        textSimilarity(first.Message.GetTextContent(), second.Message.GetTextContent()),
+		ToolCallsMatch:        sameToolCalls(first.Message.ToolCalls, second.Message.ToolCalls),
+		FinishReasonsMatch:    first.FinishReason == second.FinishReason,
+	}
+}
+
+// firstChoice returns the first choice of response, or an empty choice
+func firstChoice(response *types.StandardResponse) types.StandardChoice {
+	if response == nil || len(response.Choices) == 0 {
+		return types.StandardChoice{}
+	}
+	return response.Choices[0]
+}
+
+// responseUsage returns the usage of response, or zero usage
+func responseUsage(response *types.StandardResponse) types.Usage {
+	if response == nil {
+		return types.Usage{}
+	}
+	return response.Usage
+}
+
+// textSimilarity scores normalized texts from 0 to 1 by word-level edit distance
+func textSimilarity(a, b string) float64 {
+	wordsA, wordsB := normalizedWords(a), normalizedWords(b)
+	longest := max(len(wordsA), len(wordsB))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(wordsA, wordsB))/float64(longest)
+}
+
+// normalizedWords lowercases text and splits it into words, dropping punctuation
+func normalizedWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// editDistance is the Levenshtein distance between two word sequences
+func editDistance(a, b []string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// sameToolCalls reports whether two sets of tool calls have the same names and
+// arguments, ignoring order and IDs
+func sameToolCalls(a, b []types.ToolCall) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, call := range a {
+		counts[ToolCallKey(call)]++
+	}
+	for _, call := range b {
+		key := ToolCallKey(call)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}
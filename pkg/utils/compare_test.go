@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func textResponse(content, finishReason string, usage types.Usage) *types.StandardResponse {
+	return &types.StandardResponse{
+		Choices: []types.StandardChoice{{Message: types.ChatMessage{Role: "assistant", Content: content}, FinishReason: finishReason}},
+		Usage:   usage,
+	}
+}
+
+func TestCompareResponses(t *testing.T) {
+	a := textResponse("The capital of France is Paris.", "stop", types.Usage{PromptTokens: 10, CompletionTokens: 7, TotalTokens: 17})
+	b := textResponse("the capital of  France is paris", "stop", types.Usage{PromptTokens: 12, CompletionTokens: 6, TotalTokens: 18})
+
+	diff := CompareResponses(a, b)
+	if diff.PromptTokensDelta != 2 || diff.CompletionTokensDelta != -1 || diff.TotalTokensDelta != 1 {
+		t.Errorf("unexpected token deltas %+v", diff)
+	}
+	if diff.TextSimilarity != 1 {
+		t.Errorf("expected texts differing only in case, punctuation and spacing to be identical, got %v", diff.TextSimilarity)
+	}
+	if !diff.ToolCallsMatch || !diff.FinishReasonsMatch {
+		t.Errorf("expected matching tool calls and finish reasons, got %+v", diff)
+	}
+
+	diff = CompareResponses(a, textResponse("The capital of France is Lyon.", "length", types.Usage{}))
+	if want := 5.0 / 6; diff.TextSimilarity != want {
+		t.Errorf("expected one word in six to differ, got %v", diff.TextSimilarity)
+	}
+	if diff.FinishReasonsMatch {
+		t.Error("expected stop and length to disagree")
+	}
+
+	if diff = CompareResponses(a, textResponse("Berlin", "stop", types.Usage{})); diff.TextSimilarity != 0 {
+		t.Errorf("expected no similarity, got %v", diff.TextSimilarity)
+	}
+}
+
+func TestCompareResponses_ToolCalls(t *testing.T) {
+	withCalls := func(calls ...types.ToolCall) *types.StandardResponse {
+		return &types.StandardResponse{Choices: []types.StandardChoice{{
+			Message:      types.ChatMessage{Role: "assistant", ToolCalls: calls},
+			FinishReason: "tool_calls",
+		}}}
+	}
+	paris := weatherCall("a", `{"city":"Paris"}`)
+	rome := weatherCall("b", `{"city":"Rome"}`)
+
+	tests := []struct {
+		name  string
+		a, b  *types.StandardResponse
+		match bool
+	}{
+		{"reordered with new IDs", withCalls(paris, rome), withCalls(weatherCall("x", `{ "city": "Rome" }`), weatherCall("y", `{"city":"Paris"}`)), true},
+		{"different arguments", withCalls(paris), withCalls(rome), false},
+		{"missing call", withCalls(paris, rome), withCalls(paris), false},
+		{"duplicate call", withCalls(paris, paris), withCalls(paris, rome), false},
+		{"no calls", withCalls(paris), textResponse("It is sunny", "stop", types.Usage{}), false},
+	}
+	for _, tt := range tests {
+		if got := CompareResponses(tt.a, tt.b).ToolCallsMatch; got != tt.match {
+			t.Errorf("%s: expected ToolCallsMatch %v, got %v", tt.name, tt.match, got)
+		}
+	}
+}
+
+func TestCompareResponses_Nil(t *testing.T) {
+	diff := CompareResponses(nil, textResponse("Hello there", "stop", types.Usage{TotalTokens: 5}))
+	if diff.TotalTokensDelta != 5 || diff.TextSimilarity != 0 || diff.FinishReasonsMatch || !diff.ToolCallsMatch {
+		t.Errorf("unexpected diff against nil %+v", diff)
+	}
+	if diff = CompareResponses(nil, nil); diff.TextSimilarity != 1 || !diff.FinishReasonsMatch {
+		t.Errorf("expected two nil responses to agree, got %+v", diff)
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/json"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/models"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// WillFit estimates whether a request fits model's context window before it is sent,
+// so an application can report a conversation as too long without a failed API call.
+//
+// estimatedTokens is the request's input, its prompt, messages and tool definitions,
+// estimated as EstimateTokensFromString does, plus its output: options.MaxTokens, or
+// when zero the default providers send for the model (see models.ResolveMaxTokens).
+// window is the model's context window from models.GetContextWindow. When the window
+// is unknown it is 0 and the request is assumed to fit.
+//
+// model is the model the request will use; options.Model is not consulted, so pass
+// the provider's default model for requests that leave it empty:
+//
+//	fits, tokens, window := utils.WillFit(options, model, types.ProviderTypeOpenAI)
+//	if !fits {
+//	    return fmt.Errorf("conversation too long: ~%d tokens, limit %d", tokens, window)
+//	}
+func WillFit(options types.GenerateOptions, model string, provider types.ProviderType) (fits bool, estimatedTokens int, window int) {
+	estimatedTokens = EstimateTokensFromString(options.Prompt) + EstimateTokensFromMessages(options.Messages)
+	if len(options.Tools) > 0 {
+		// Tools are sent as their JSON definitions
+		if data, err := json.Marshal(options.Tools); err == nil {
+			estimatedTokens += EstimateTokensFromBytes(len(data))
+		}
+	}
+	estimatedTokens += models.ResolveMaxTokens(provider, model, options.MaxTokens).MaxTokens
+
+	window = models.GetContextWindow(provider, model)
+	return window == 0 || estimatedTokens <= window, estimatedTokens, window
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func TestWillFit(t *testing.T) {
+	// gpt-4 has an 8192-token context window, and a 4096-token default output
+	fits, tokens, window := WillFit(types.GenerateOptions{Prompt: "Hello"}, "gpt-4", types.ProviderTypeOpenAI)
+	if !fits || tokens != EstimateTokensFromString("Hello")+4096 || window != 8192 {
+		t.Errorf("short prompt: got fits=%v tokens=%d window=%d", fits, tokens, window)
+	}
+
+	long := types.GenerateOptions{
+		Messages: []types.ChatMessage{{Role: "user", Content: strings.Repeat("a", 20000)}},
+	}
+	if fits, tokens, _ := WillFit(long, "gpt-4", types.ProviderTypeOpenAI); fits || tokens <= 8192 {
+		t.Errorf("long conversation: got fits=%v tokens=%d, want it not to fit", fits, tokens)
+	}
+
+	// Requested output counts in full
+	long.Messages[0].Content = strings.Repeat("a", 100)
+	long.MaxTokens = 8190
+	if fits, _, _ := WillFit(long, "gpt-4", types.ProviderTypeOpenAI); fits {
+		t.Error("large MaxTokens: want it not to fit")
+	}
+}
+
+func TestWillFit_CountsTools(t *testing.T) {
+	options := types.GenerateOptions{Prompt: "Weather?", MaxTokens: 100}
+	_, without, _ := WillFit(options, "gpt-4o", types.ProviderTypeOpenAI)
+
+	options.Tools = []types.Tool{{
+		Name:        "get_weather",
+		Description: "Get the current weather for a city",
+		InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}}},
+	}}
+	_, with, _ := WillFit(options, "gpt-4o", types.ProviderTypeOpenAI)
+	if with <= without {
+		t.Errorf("tools were not counted: %d tokens with, %d without", with, without)
+	}
+}
+
+func TestWillFit_UnknownModel(t *testing.T) {
+	fits, tokens, window := WillFit(types.GenerateOptions{Prompt: "Hello", MaxTokens: 10}, "unknown-model", types.ProviderTypeOpenAI)
+	if !fits || window != 0 || tokens != EstimateTokensFromString("Hello")+10 {
+		t.Errorf("got fits=%v tokens=%d window=%d, want an unknown window to fit", fits, tokens, window)
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// hashableRequest holds the fields of GenerateOptions that affect the model's output.
+// Field order is fixed by the struct and encoding/json sorts map keys, so the
+// encoding is canonical.
+type hashableRequest struct {
+	Model             string                 `json:"model"`
+	ModelFallbacks    []string               `json:"model_fallbacks,omitempty"`
+	TargetProvider    string                 `json:"target_provider,omitempty"`
+	Prompt            string                 `json:"prompt,omitempty"`
+	Context           string                 `json:"context,omitempty"`
+	ContextFiles      []string               `json:"context_files,omitempty"`
+	Language          *string                `json:"language,omitempty"`
+	Messages          []types.ChatMessage    `json:"messages,omitempty"`
+	MaxTokens         int                    `json:"max_tokens,omitempty"`
+	Temperature       float64                `json:"temperature,omitempty"`
+	Stop              []string               `json:"stop,omitempty"`
+	N                 int                    `json:"n,omitempty"`
+	Tools             []types.Tool           `json:"tools,omitempty"`
+	ToolChoice        *types.ToolChoice      `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool                  `json:"parallel_tool_calls,omitempty"`
+	ResponseFormat    string                 `json:"response_format,omitempty"`
+	Store             *bool                  `json:"store,omitempty"`
+	ReasoningEffort   types.ReasoningEffort  `json:"reasoning_effort,omitempty"`
+	ProviderOptions   map[string]interface{} `json:"provider_options,omitempty"`
+}
+
+// HashRequest returns a stable SHA-256 hex digest of the fields of a request that
+// shape the response: the model and its fallbacks, the target provider, the prompt,
+// context and messages, sampling and tool settings, the response format, storage,
+// reasoning effort and provider options. Volatile fields that don't change the
+// response (metadata, idempotency key, context object, timeout, streaming, output
+// file) are ignored, as is per-message metadata, so equivalent requests hash
+// identically.
+// Intended as a key for response caching and request de-duplication.
+func HashRequest(options types.GenerateOptions) string {
+	req := hashableRequest{
+		Model:             options.Model,
+		ModelFallbacks:    options.ModelFallbacks,
+		TargetProvider:    options.TargetProvider,
+		Prompt:            options.Prompt,
+		Context:           options.Context,
+		ContextFiles:      options.ContextFiles,
+		Language:          options.Language,
+		MaxTokens:         options.MaxTokens,
+		Temperature:       options.Temperature,
+		Stop:              options.Stop,
+		N:                 options.N,
+		Tools:             options.Tools,
+		ToolChoice:        options.ToolChoice,
+		ParallelToolCalls: options.ParallelToolCalls,
+		ResponseFormat:    options.ResponseFormat,
+		Store:             options.Store,
+		ReasoningEffort:   options.ReasoningEffort,
+		ProviderOptions:   options.ProviderOptions,
+	}
+
+	if len(options.Messages) > 0 {
+		req.Messages = make([]types.ChatMessage, len(options.Messages))
+		for i, msg := range options.Messages {
+			msg.Metadata = nil
+			req.Messages[i] = msg
+		}
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		// Unencodable provider options; hash what we can so the result stays deterministic
+		req.ProviderOptions = nil
+		data, _ = json.Marshal(req)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
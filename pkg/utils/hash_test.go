@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func baseHashOptions() types.GenerateOptions {
+	return types.GenerateOptions{
+		Model:       "gpt-4o",
+		Temperature: 0.2,
+		Messages: []types.ChatMessage{
+			{Role: "user", Content: "What's the weather?"},
+		},
+		Tools: []types.Tool{{
+			Name: "get_weather",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"city":  map[string]interface{}{"type": "string"},
+					"units": map[string]interface{}{"type": "string"},
+				},
+			},
+		}},
+		ToolChoice: &types.ToolChoice{Mode: types.ToolChoiceAuto},
+	}
+}
+
+func TestHashRequest_Stable(t *testing.T) {
+	first := HashRequest(baseHashOptions())
+	for i := 0; i < 20; i++ {
+		if got := HashRequest(baseHashOptions()); got != first {
+			t.Fatalf("hash changed between identical requests: %s != %s", got, first)
+		}
+	}
+	if len(first) != 64 {
+		t.Errorf("expected 64 hex characters, got %d", len(first))
+	}
+}
+
+func TestHashRequest_IgnoresVolatileFields(t *testing.T) {
+	expected := HashRequest(baseHashOptions())
+
+	options := baseHashOptions()
+	options.Metadata = map[string]interface{}{"request_id": "abc"}
+	options.IdempotencyKey = "key-1"
+	options.ContextObj = context.Background()
+	options.Timeout = 5 * time.Second
+	options.Stream = true
+	options.Messages[0].Metadata = map[string]interface{}{"trace": "xyz"}
+
+	if got := HashRequest(options); got != expected {
+		t.Errorf("expected volatile fields to be ignored, got different hash")
+	}
+}
+
+func TestHashRequest_DetectsSemanticChanges(t *testing.T) {
+	base := HashRequest(baseHashOptions())
+
+	mutations := map[string]func(*types.GenerateOptions){
+		"model":           func(o *types.GenerateOptions) { o.Model = "gpt-4o-mini" },
+		"temperature":     func(o *types.GenerateOptions) { o.Temperature = 0.3 },
+		"message content": func(o *types.GenerateOptions) { o.Messages[0].Content = "What's the time?" },
+		"tools":           func(o *types.GenerateOptions) { o.Tools[0].Name = "get_forecast" },
+		"tool choice":     func(o *types.GenerateOptions) { o.ToolChoice = &types.ToolChoice{Mode: types.ToolChoiceRequired} },
+		"response format": func(o *types.GenerateOptions) { o.ResponseFormat = "json_object" },
+		"n":               func(o *types.GenerateOptions) { o.N = 2 },
+		"parallel tools":  func(o *types.GenerateOptions) { o.ParallelToolCalls = new(bool) },
+		"store":           func(o *types.GenerateOptions) { o.Store = new(bool) },
+		"effort":          func(o *types.GenerateOptions) { o.ReasoningEffort = types.ReasoningEffortHigh },
+		"fallbacks":       func(o *types.GenerateOptions) { o.ModelFallbacks = []string{"gpt-4o-mini"} },
+		"target provider": func(o *types.GenerateOptions) { o.TargetProvider = "primary" },
+		"context":         func(o *types.GenerateOptions) { o.Context = "The user is in Paris." },
+		"context files":   func(o *types.GenerateOptions) { o.ContextFiles = []string{"notes.md"} },
+	}
+
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			options := baseHashOptions()
+			mutate(&options)
+			if HashRequest(options) == base {
+				t.Errorf("expected %s change to change the hash", name)
+			}
+		})
+	}
+}
+
+// hashExcludedFields are the GenerateOptions fields HashRequest deliberately ignores
+var hashExcludedFields = map[string]bool{
+	"ContextObj":     true,
+	"OutputFile":     true,
+	"Stream":         true,
+	"Timeout":        true,
+	"Metadata":       true,
+	"IdempotencyKey": true,
+}
+
+// TestHashRequest_CoversAllFields fails when a GenerateOptions field is added without
+// being hashed or listed in hashExcludedFields
+func TestHashRequest_CoversAllFields(t *testing.T) {
+	hashed := reflect.TypeOf(hashableRequest{})
+	options := reflect.TypeOf(types.GenerateOptions{})
+	for i := 0; i < options.NumField(); i++ {
+		name := options.Field(i).Name
+		_, isHashed := hashed.FieldByName(name)
+		if isHashed == hashExcludedFields[name] {
+			t.Errorf("GenerateOptions.%s must be either hashed or excluded, not both or neither", name)
+		}
+	}
+}
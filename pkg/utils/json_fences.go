@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ExtractJSON returns the JSON object or array in content with any surrounding Markdown
+// code fence and prose removed, e.g. the {...} in "Sure:\n```json\n{...}\n```". Content
+// that is already valid JSON, or in which no complete object or array is found, is
+// returned unchanged.
+func ExtractJSON(content string) string {
+	if jsonStart(content) < 0 || json.Valid([]byte(content)) {
+		return content
+	}
+	if body, ok := codeFence(content); ok {
+		if body = strings.TrimSpace(body); json.Valid([]byte(body)) {
+			return body
+		}
+	}
+
+	var stripper jsonFenceStripper
+	extracted := stripper.write(content)
+	if stripper.phase != jsonPhaseDone {
+		return content
+	}
+	return extracted
+}
+
+// StripJSONFences wraps a stream so that, when options requested a JSON response
+// format ("json_object" or "json_schema", not "text"), the response content is reduced to its JSON value: a leading code fence and
+// any prose before the first '{' or '[' are dropped, as is everything after the value
+// closes. Streams for other requests are returned unchanged.
+//
+// It is conservative: leading text is held back, not dropped, until JSON starts, and is
+// released unchanged if the stream ends without any; if the content turns out not to be
+// valid JSON the rest of it is passed through untouched.
+//
+//	stream = utils.StripJSONFences(stream, options)
+func StripJSONFences(stream types.ChatCompletionStream, options types.GenerateOptions) types.ChatCompletionStream {
+	if options.ResponseFormat == "" || options.ResponseFormat == "text" {
+		return stream
+	}
+	return &jsonFenceStrippingStream{inner: stream}
+}
+
+// jsonStart returns the index of the first '{' or '[' in text, or -1
+func jsonStart(text string) int {
+	return strings.IndexAny(text, "{[")
+}
+
+// jsonFenceStripper phases
+const (
+	jsonPhaseLeading     = iota // holding text until JSON starts
+	jsonPhaseValue              // passing the JSON value through
+	jsonPhaseDone               // dropping text after the value
+	jsonPhasePassthrough        // the content was not JSON; passing everything through
+)
+
+// jsonFenceStripper extracts a JSON value from text written to it in pieces
+type jsonFenceStripper struct {
+	phase   int
+	held    strings.Builder
+	scanner jsonScanner
+}
+
+// write consumes the next piece of text and returns the part to emit
+func (s *jsonFenceStripper) write(text string) string {
+	switch s.phase {
+	case jsonPhaseLeading:
+		start := jsonStart(text)
+		if start < 0 {
+			s.held.WriteString(text)
+			return ""
+		}
+		leading := s.held.String() + text[:start]
+		s.held.Reset()
+		s.phase = jsonPhaseValue
+		value := s.scan(text[start:])
+		if s.phase == jsonPhasePassthrough {
+			// Not JSON after all; the held text goes out as it was
+			return leading + value
+		}
+		return value
+	case jsonPhaseValue:
+		return s.scan(text)
+	case jsonPhaseDone:
+		return ""
+	default:
+		return text
+	}
+}
+
+// flush returns the held leading text when the content ended before any JSON
+func (s *jsonFenceStripper) flush() string {
+	held := s.held.String()
+	s.held.Reset()
+	return held
+}
+
+func (s *jsonFenceStripper) scan(text string) string {
+	for i := 0; i < len(text); i++ {
+		if err := s.scanner.step(text[i]); err != nil {
+			s.phase = jsonPhasePassthrough
+			return text
+		}
+		if s.scanner.state == scanEnd {
+			s.phase = jsonPhaseDone
+			return text[:i+1]
+		}
+	}
+	return text
+}
+
+// jsonFenceStrippingStream applies a jsonFenceStripper to a stream's content
+type jsonFenceStrippingStream struct {
+	inner    types.ChatCompletionStream
+	stripper jsonFenceStripper
+}
+
+func (s *jsonFenceStrippingStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return chunk, err
+	}
+
+	text := chunkContent(chunk)
+	out := s.stripper.write(text)
+	if (err == nil && chunk.Done) || errors.Is(err, io.EOF) {
+		out = s.stripper.flush() + out
+	}
+	if out != text {
+		chunk = setChunkContent(chunk, text, out)
+	}
+	return chunk, err
+}
+
+func (s *jsonFenceStrippingStream) Close() error {
+	return s.inner.Close()
+}
+
+// chunkContent returns a chunk's response content: Content, or else the first choice's
+// delta or, for complete responses, message content
+func chunkContent(chunk types.ChatCompletionChunk) string {
+	if chunk.Content != "" || len(chunk.Choices) == 0 {
+		return chunk.Content
+	}
+	if chunk.Choices[0].Delta.Content != "" {
+		return chunk.Choices[0].Delta.Content
+	}
+	return chunk.Choices[0].Message.Content
+}
+
+// setChunkContent replaces the content chunkContent returned, original, with content,
+// wherever the chunk carries it. A chunk without content gets it in Content.
+func setChunkContent(chunk types.ChatCompletionChunk, original, content string) types.ChatCompletionChunk {
+	if chunk.Content == original {
+		chunk.Content = content
+	}
+	if original != "" && len(chunk.Choices) > 0 {
+		choices := make([]types.ChatChoice, len(chunk.Choices))
+		copy(choices, chunk.Choices)
+		if choices[0].Delta.Content == original {
+			choices[0].Delta.Content = content
+		}
+		if choices[0].Message.Content == original {
+			choices[0].Message.Content = content
+		}
+		chunk.Choices = choices
+	}
+	return chunk
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectContent drains a stream and returns its concatenated content
+func collectContent(t *testing.T, stream types.ChatCompletionStream) string {
+	t.Helper()
+	var b strings.Builder
+	for {
+		chunk, err := stream.Next()
+		b.WriteString(chunk.Content)
+		if errors.Is(err, io.EOF) {
+			return b.String()
+		}
+		require.NoError(t, err)
+	}
+}
+
+// contentStream streams each piece as a chunk with Content and a mirrored delta
+func contentStream(pieces ...string) types.ChatCompletionStream {
+	chunks := make([]types.ChatCompletionChunk, len(pieces))
+	for i, piece := range pieces {
+		chunks[i] = types.ChatCompletionChunk{
+			Content: piece,
+			Choices: []types.ChatChoice{{Delta: types.ChatMessage{Content: piece}}},
+		}
+	}
+	return &chunkStream{chunks: chunks}
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "plain json", content: `{"a": 1}`, want: `{"a": 1}`},
+		{name: "fenced", content: "```json\n{\"a\": 1}\n```", want: `{"a": 1}`},
+		{name: "prose and fence", content: "Here it is:\n```json\n[1, {\"b\": \"}\"}]\n```\nHope that helps!", want: `[1, {"b": "}"}]`},
+		{name: "no json", content: "I cannot help with that.", want: "I cannot help with that."},
+		{name: "not valid json", content: "Options: [a] or [b]", want: "Options: [a] or [b]"},
+		{name: "incomplete json", content: "```json\n{\"a\": ", want: "```json\n{\"a\": "},
+		{name: "single line fence", content: "```{\"a\": 1}```\nthanks", want: `{"a": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractJSON(tt.content))
+		})
+	}
+}
+
+func TestStripJSONFences_Stream(t *testing.T) {
+	options := types.GenerateOptions{ResponseFormat: "json_object"}
+
+	stream := StripJSONFences(contentStream("```js", "on\n{\"city\": ", "\"Paris\"}", "\n```"), options)
+	assert.Equal(t, `{"city": "Paris"}`, collectContent(t, stream))
+
+	stream = StripJSONFences(contentStream("```json\n{\"a\"", ": 1}\n```"), options)
+	first, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a"`, first.Content)
+	assert.Equal(t, `{"a"`, first.Choices[0].Delta.Content, "the delta is rewritten too")
+}
+
+func TestStripJSONFences_Conservative(t *testing.T) {
+	// Not JSON mode: untouched
+	stream := contentStream("```json\n{}\n```")
+	assert.Same(t, stream, StripJSONFences(stream, types.GenerateOptions{}))
+
+	// JSON mode without any JSON: the held text is released at the end
+	stream = StripJSONFences(contentStream("Sorry, ", "I can't."), types.GenerateOptions{ResponseFormat: "json_object"})
+	assert.Equal(t, "Sorry, I can't.", collectContent(t, stream))
+
+	// A text response format is not JSON
+	stream = contentStream("The list [a, b] is short.")
+	assert.Same(t, stream, StripJSONFences(stream, types.GenerateOptions{ResponseFormat: "text"}))
+
+	// JSON mode with a bracket that doesn't start JSON: the leading text is kept
+	stream = StripJSONFences(contentStream("The list ", "[a, b] is short."), types.GenerateOptions{ResponseFormat: "json_object"})
+	assert.Equal(t, "The list [a, b] is short.", collectContent(t, stream))
+}
@@ -0,0 +1,348 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/toolvalidator"
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// JSONValidationError reports streamed output that is not valid JSON or does not match
+// the expected schema
+type JSONValidationError struct {
+	// Offset is the byte offset in the content at which the JSON became invalid, or -1
+	// when the JSON is well-formed but incomplete or does not match the schema
+	Offset int
+	Err    error
+}
+
+func (e *JSONValidationError) Error() string {
+	if e.Offset >= 0 {
+		return fmt.Sprintf("invalid JSON output at byte %d: %v", e.Offset, e.Err)
+	}
+	return fmt.Sprintf("invalid JSON output: %v", e.Err)
+}
+
+func (e *JSONValidationError) Unwrap() error {
+	return e.Err
+}
+
+// JSONValidatingStream checks, as content streams in, that it forms a single JSON value.
+// Chunks are passed through unchanged, so output can be displayed as it arrives.
+type JSONValidatingStream struct {
+	inner     types.ChatCompletionStream
+	schema    map[string]interface{}
+	scanner   jsonScanner
+	content   strings.Builder
+	err       error
+	finalized bool
+}
+
+// ValidatingJSONStream wraps a stream whose content should be a JSON value, for example
+// one requested with a JSON response format. Syntax errors are detected as soon as the
+// offending chunk arrives and reported by Err. When the stream ends, the final Next call
+// returns a *JSONValidationError in place of io.EOF (together with the last chunk, if
+// any) when the complete output is not valid JSON.
+func ValidatingJSONStream(stream types.ChatCompletionStream) *JSONValidatingStream {
+	return &JSONValidatingStream{inner: stream}
+}
+
+// ValidatingJSONStreamWithSchema is ValidatingJSONStream that also validates the complete
+// output against a JSON schema; see toolvalidator.Validator.ValidateValue for the
+// supported keywords.
+func ValidatingJSONStreamWithSchema(stream types.ChatCompletionStream, schema map[string]interface{}) *JSONValidatingStream {
+	return &JSONValidatingStream{inner: stream, schema: schema}
+}
+
+// Next returns the next chunk. At the end of the stream it returns the validation error,
+// if any, instead of io.EOF.
+func (s *JSONValidatingStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+	if s.finalized {
+		return chunk, err
+	}
+
+	if s.err == nil {
+		text := chunk.Content
+		if text == "" && len(chunk.Choices) > 0 {
+			text = chunk.Choices[0].Delta.Content
+		}
+		s.write(text)
+	}
+
+	if (err == nil && chunk.Done) || errors.Is(err, io.EOF) {
+		s.finalized = true
+		if verr := s.finalize(); verr != nil {
+			return chunk, verr
+		}
+	}
+	return chunk, err
+}
+
+// Close closes the underlying stream
+func (s *JSONValidatingStream) Close() error {
+	return s.inner.Close()
+}
+
+// Err returns the first validation error found so far. Syntax errors are available as
+// soon as they are streamed; incompleteness and schema errors once the stream has ended.
+func (s *JSONValidatingStream) Err() error {
+	return s.err
+}
+
+// Content returns the content streamed so far
+func (s *JSONValidatingStream) Content() string {
+	return s.content.String()
+}
+
+func (s *JSONValidatingStream) write(text string) {
+	for i := 0; i < len(text); i++ {
+		if err := s.scanner.step(text[i]); err != nil {
+			s.err = &JSONValidationError{Offset: s.content.Len() + i, Err: err}
+			break
+		}
+	}
+	s.content.WriteString(text)
+}
+
+// finalize checks that the output is complete and matches the schema
+func (s *JSONValidatingStream) finalize() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.scanner.end(); err != nil {
+		s.err = &JSONValidationError{Offset: -1, Err: err}
+		return s.err
+	}
+
+	if s.schema != nil {
+		var value interface{}
+		if err := json.Unmarshal([]byte(s.content.String()), &value); err != nil {
+			s.err = &JSONValidationError{Offset: -1, Err: err}
+			return s.err
+		}
+		if err := toolvalidator.New(false).ValidateValue(value, s.schema); err != nil {
+			s.err = &JSONValidationError{Offset: -1, Err: fmt.Errorf("schema mismatch: %w", err)}
+			return s.err
+		}
+	}
+	return nil
+}
+
+// jsonScanner states: what the scanner expects next
+const (
+	scanValue        = iota // any value
+	scanValueOrClose        // a value or ']' after '['
+	scanKeyOrClose          // a key or '}' after '{'
+	scanKey                 // a key after ',' in an object
+	scanColon               // ':' after a key
+	scanCommaOrClose        // ',' or the container's closing bracket
+	scanEnd                 // only whitespace after the top-level value
+)
+
+// jsonScanner validates JSON one byte at a time, so errors are found without waiting
+// for the whole document. Strings and literals are checked as they stream; numbers
+// are checked once complete.
+type jsonScanner struct {
+	state   int
+	stack   []byte // open containers, '{' or '['
+	started bool
+
+	inString bool
+	isKey    bool
+	escape   bool
+	hex      int // remaining hex digits of a \u escape
+
+	literal strings.Builder // number, true, false or null being scanned
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func (s *jsonScanner) step(c byte) error {
+	if s.inString {
+		return s.stepString(c)
+	}
+
+	if s.literal.Len() > 0 {
+		if isLiteralByte(c) {
+			s.literal.WriteByte(c)
+			return checkLiteralPrefix(s.literal.String())
+		}
+		if err := s.endLiteral(); err != nil {
+			return err
+		}
+	}
+
+	if isJSONSpace(c) {
+		return nil
+	}
+
+	switch s.state {
+	case scanEnd:
+		return fmt.Errorf("unexpected %q after top-level value", c)
+
+	case scanColon:
+		if c != ':' {
+			return fmt.Errorf("expected ':' after object key, got %q", c)
+		}
+		s.state = scanValue
+		return nil
+
+	case scanCommaOrClose:
+		switch {
+		case c == ',':
+			if s.top() == '{' {
+				s.state = scanKey
+			} else {
+				s.state = scanValue
+			}
+			return nil
+		case c == '}' || c == ']':
+			return s.close(c)
+		}
+		return fmt.Errorf("expected ',' or closing bracket, got %q", c)
+
+	case scanKeyOrClose, scanKey:
+		if c == '}' && s.state == scanKeyOrClose {
+			return s.close(c)
+		}
+		if c != '"' {
+			return fmt.Errorf("expected object key, got %q", c)
+		}
+		s.inString, s.isKey = true, true
+		return nil
+	}
+
+	// scanValue or scanValueOrClose
+	if c == ']' && s.state == scanValueOrClose {
+		return s.close(c)
+	}
+	s.started = true
+	switch {
+	case c == '{' || c == '[':
+		s.stack = append(s.stack, c)
+		if c == '{' {
+			s.state = scanKeyOrClose
+		} else {
+			s.state = scanValueOrClose
+		}
+		return nil
+	case c == '"':
+		s.inString, s.isKey = true, false
+		return nil
+	case isLiteralByte(c):
+		s.literal.WriteByte(c)
+		return checkLiteralPrefix(s.literal.String())
+	}
+	return fmt.Errorf("unexpected %q, expected a value", c)
+}
+
+func (s *jsonScanner) stepString(c byte) error {
+	switch {
+	case s.hex > 0:
+		if !strings.ContainsRune("0123456789abcdefABCDEF", rune(c)) {
+			return fmt.Errorf("invalid \\u escape digit %q", c)
+		}
+		s.hex--
+	case s.escape:
+		if !strings.ContainsRune(`"\/bfnrtu`, rune(c)) {
+			return fmt.Errorf("invalid escape character %q", c)
+		}
+		s.escape = false
+		if c == 'u' {
+			s.hex = 4
+		}
+	case c == '\\':
+		s.escape = true
+	case c == '"':
+		s.inString = false
+		if s.isKey {
+			s.state = scanColon
+		} else {
+			s.valueDone()
+		}
+	case c < 0x20:
+		return fmt.Errorf("control character %q in string", c)
+	}
+	return nil
+}
+
+// end reports whether the scanned input is a complete JSON value
+func (s *jsonScanner) end() error {
+	if s.literal.Len() > 0 {
+		if err := s.endLiteral(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case !s.started:
+		return errors.New("empty output")
+	case s.inString:
+		return errors.New("unterminated string")
+	case len(s.stack) > 0:
+		return fmt.Errorf("unexpected end of output: %d unclosed bracket(s)", len(s.stack))
+	}
+	return nil
+}
+
+func (s *jsonScanner) endLiteral() error {
+	literal := s.literal.String()
+	s.literal.Reset()
+	switch literal {
+	case "true", "false", "null":
+	default:
+		if !json.Valid([]byte(literal)) {
+			return fmt.Errorf("invalid literal %q", literal)
+		}
+	}
+	s.valueDone()
+	return nil
+}
+
+func (s *jsonScanner) close(c byte) error {
+	open := s.top()
+	if (c == '}' && open != '{') || (c == ']' && open != '[') {
+		return fmt.Errorf("mismatched %q", c)
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	s.valueDone()
+	return nil
+}
+
+func (s *jsonScanner) valueDone() {
+	if len(s.stack) == 0 {
+		s.state = scanEnd
+	} else {
+		s.state = scanCommaOrClose
+	}
+}
+
+func (s *jsonScanner) top() byte {
+	if len(s.stack) == 0 {
+		return 0
+	}
+	return s.stack[len(s.stack)-1]
+}
+
+func isLiteralByte(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || c == '-' || c == '+' || c == '.' || c == 'E'
+}
+
+// checkLiteralPrefix rejects literals that cannot become true, false, null or a number
+func checkLiteralPrefix(literal string) error {
+	for _, keyword := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(keyword, literal) {
+			return nil
+		}
+	}
+	if strings.Trim(literal, "0123456789-+.eE") == "" && literal[0] != '+' {
+		return nil
+	}
+	return fmt.Errorf("invalid literal %q", literal)
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// drainJSONStream reads a validating stream to the end and returns the streamed content
+// and the error that ended it
+func drainJSONStream(stream *JSONValidatingStream) (string, error) {
+	var content string
+	for {
+		chunk, err := stream.Next()
+		content += chunk.Content
+		if err != nil {
+			return content, err
+		}
+	}
+}
+
+func contentChunks(parts ...string) []types.ChatCompletionChunk {
+	chunks := make([]types.ChatCompletionChunk, len(parts))
+	for i, part := range parts {
+		chunks[i] = types.ChatCompletionChunk{Content: part}
+	}
+	return chunks
+}
+
+func TestValidatingJSONStream_Valid(t *testing.T) {
+	valid := [][]string{
+		{`{"name": "Ada", `, `"tags": ["a", "b"], "age": 3`, `6, "ok": tr`, `ue, "x": null}`},
+		{`[1, -2.5e3, {"a": {}}, [], "é\n"]`},
+		{`  "just a string"  `},
+		{`42`},
+	}
+
+	for _, parts := range valid {
+		inner := &chunkStream{chunks: contentChunks(parts...)}
+		stream := ValidatingJSONStream(inner)
+
+		content, err := drainJSONStream(stream)
+		if err != io.EOF {
+			t.Errorf("%q: expected io.EOF, got %v", content, err)
+		}
+		if stream.Err() != nil {
+			t.Errorf("%q: unexpected validation error %v", content, stream.Err())
+		}
+	}
+}
+
+func TestValidatingJSONStream_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		parts  []string
+		offset int
+	}{
+		{"trailing comma", []string{`{"a": 1,`, ` }`}, 9},
+		{"unquoted key", []string{`{a: 1}`}, 1},
+		{"mismatched bracket", []string{`[1, 2}`}, 5},
+		{"bad literal", []string{`{"a": tru`, `th}`}, 9},
+		{"bad number", []string{`[1.2.3]`}, 6},
+		{"prose after value", []string{`{"a": 1}`, ` Hope this helps!`}, 9},
+		{"truncated", []string{`{"a": [1, 2`}, -1},
+		{"unterminated string", []string{`"abc`}, -1},
+		{"empty", []string{``}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &chunkStream{chunks: contentChunks(tt.parts...)}
+			stream := ValidatingJSONStream(inner)
+
+			_, err := drainJSONStream(stream)
+			var verr *JSONValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *JSONValidationError, got %v", err)
+			}
+			if verr.Offset != tt.offset {
+				t.Errorf("expected offset %d, got %d (%v)", tt.offset, verr.Offset, verr)
+			}
+			if stream.Err() != err {
+				t.Errorf("expected Err to return the final error, got %v", stream.Err())
+			}
+		})
+	}
+}
+
+func TestValidatingJSONStream_PassesChunksThrough(t *testing.T) {
+	inner := &chunkStream{chunks: contentChunks(`{"a": `, `oops`, `}`)}
+	stream := ValidatingJSONStream(inner)
+
+	first, err := stream.Next()
+	if err != nil || first.Content != `{"a": ` {
+		t.Fatalf("unexpected first chunk %q, %v", first.Content, err)
+	}
+
+	// The syntax error is visible immediately but does not interrupt the stream
+	second, err := stream.Next()
+	if err != nil || second.Content != "oops" {
+		t.Fatalf("unexpected second chunk %q, %v", second.Content, err)
+	}
+	if stream.Err() == nil {
+		t.Error("expected the syntax error to be reported by Err before the stream ends")
+	}
+
+	content, err := drainJSONStream(stream)
+	if content != "}" {
+		t.Errorf("expected remaining content to be passed through, got %q", content)
+	}
+	if err == io.EOF || err == nil {
+		t.Errorf("expected a validation error at the end of the stream, got %v", err)
+	}
+
+	if err := stream.Close(); err != nil || !inner.closed {
+		t.Error("expected Close to close the inner stream")
+	}
+}
+
+func TestValidatingJSONStream_DoneChunk(t *testing.T) {
+	inner := &chunkStream{chunks: []types.ChatCompletionChunk{
+		{Choices: []types.ChatChoice{{Delta: types.ChatMessage{Content: `{"a": `}}}},
+		{Content: `1`, Done: true},
+	}}
+	stream := ValidatingJSONStream(inner)
+
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	final, err := stream.Next()
+	if !final.Done {
+		t.Error("expected the done chunk to be returned")
+	}
+	var verr *JSONValidationError
+	if !errors.As(err, &verr) || verr.Offset != -1 {
+		t.Errorf("expected an incomplete-output error with the done chunk, got %v", err)
+	}
+	if stream.Content() != `{"a": 1` {
+		t.Errorf("expected content from deltas and chunks, got %q", stream.Content())
+	}
+}
+
+func TestValidatingJSONStreamWithSchema(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"city": map[string]interface{}{"type": "string"},
+		},
+		"required": []interface{}{"city"},
+	}
+
+	stream := ValidatingJSONStreamWithSchema(&chunkStream{chunks: contentChunks(`{"city": "Paris"}`)}, schema)
+	if _, err := drainJSONStream(stream); err != io.EOF {
+		t.Errorf("expected matching output to end with io.EOF, got %v", err)
+	}
+
+	stream = ValidatingJSONStreamWithSchema(&chunkStream{chunks: contentChunks(`{"town": "Paris"}`)}, schema)
+	_, err := drainJSONStream(stream)
+	var verr *JSONValidationError
+	if !errors.As(err, &verr) || verr.Offset != -1 {
+		t.Fatalf("expected a schema validation error, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// Standard message roles accepted by NormalizeMessages
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+
+	// RoleDeveloper is OpenAI's replacement for "system" on reasoning models
+	RoleDeveloper = "developer"
+)
+
+// roleAliases maps role spellings used by other APIs to the standard roles
+var roleAliases = map[string]string{
+	"model": RoleAssistant, // Gemini
+	"human": RoleUser,      // Legacy Anthropic prompt format
+	"ai":    RoleAssistant,
+}
+
+// MessageValidationError describes an invalid message or message sequence
+type MessageValidationError struct {
+	MessageIndex int
+	Role         string
+	Issue        string // "invalid_role", "orphan_tool_message", "misplaced_system", etc.
+	Detail       string
+}
+
+// Error implements the error interface
+func (e *MessageValidationError) Error() string {
+	return fmt.Sprintf("invalid message at index %d (role %q): %s", e.MessageIndex, e.Role, e.Detail)
+}
+
+// NormalizeMessages validates a conversation and adapts it to the conventions of the
+// given provider before it is sent. It returns a new slice; the input is not modified.
+//
+// All providers:
+//   - Roles are lowercased and trimmed, and aliases ("model", "human", "ai") are mapped
+//     to the standard roles. Unknown or empty roles are rejected.
+//   - A tool message must answer (via ToolCallID or tool_result parts) a tool call from
+//     the closest preceding assistant message, with only other tool messages in between.
+//
+// Provider conventions:
+//   - OpenAI keeps "developer" messages; every other provider receives them as "system".
+//   - Anthropic and Gemini take the system prompt as a top-level field, so system
+//     messages must come before any other message.
+//   - Anthropic requires the first non-system message to be from the user.
+//
+// Errors are returned as *MessageValidationError for the first problem found.
+func NormalizeMessages(messages []types.ChatMessage, provider types.ProviderType) ([]types.ChatMessage, error) {
+	normalized := make([]types.ChatMessage, len(messages))
+	copy(normalized, messages)
+
+	for i := range normalized {
+		role, err := normalizeRole(normalized[i].Role, provider)
+		if err != nil {
+			return nil, &MessageValidationError{MessageIndex: i, Role: normalized[i].Role, Issue: "invalid_role", Detail: err.Error()}
+		}
+		normalized[i].Role = role
+	}
+
+	if err := validateToolMessages(normalized); err != nil {
+		return nil, err
+	}
+
+	if systemPromptIsTopLevel(provider) {
+		if err := ValidateLeadingSystemMessages(normalized, provider); err != nil {
+			return nil, err
+		}
+	}
+
+	if provider == types.ProviderTypeAnthropic {
+		for i, msg := range normalized {
+			if msg.Role == RoleSystem {
+				continue
+			}
+			if msg.Role != RoleUser {
+				return nil, &MessageValidationError{
+					MessageIndex: i,
+					Role:         msg.Role,
+					Issue:        "invalid_first_message",
+					Detail:       "anthropic requires the first non-system message to be from the user",
+				}
+			}
+			break
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizeRole returns the canonical role name for the provider
+func normalizeRole(role string, provider types.ProviderType) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(role))
+	if alias, ok := roleAliases[normalized]; ok {
+		normalized = alias
+	}
+
+	switch normalized {
+	case RoleSystem, RoleUser, RoleAssistant, RoleTool:
+		return normalized, nil
+	case RoleDeveloper:
+		if provider == types.ProviderTypeOpenAI {
+			return RoleDeveloper, nil
+		}
+		return RoleSystem, nil
+	case "":
+		return "", fmt.Errorf("role is required")
+	default:
+		return "", fmt.Errorf("unknown role %q", role)
+	}
+}
+
+// validateToolMessages checks that every tool message answers a tool call from the
+// assistant message that immediately precedes its block of tool messages
+func validateToolMessages(messages []types.ChatMessage) error {
+	var openCalls map[string]bool
+
+	for i, msg := range messages {
+		switch msg.Role {
+		case RoleAssistant:
+			openCalls = make(map[string]bool, len(msg.ToolCalls))
+			for _, tc := range msg.ToolCalls {
+				openCalls[tc.ID] = true
+			}
+			continue
+		case RoleTool:
+			// handled below
+		default:
+			openCalls = nil
+			continue
+		}
+
+		ids := toolResultIDs(msg)
+		if len(ids) == 0 {
+			return &MessageValidationError{
+				MessageIndex: i,
+				Role:         msg.Role,
+				Issue:        "missing_tool_call_id",
+				Detail:       "tool message must set ToolCallID or contain a tool_result part",
+			}
+		}
+		for _, id := range ids {
+			if openCalls == nil {
+				return &MessageValidationError{
+					MessageIndex: i,
+					Role:         msg.Role,
+					Issue:        "orphan_tool_message",
+					Detail:       fmt.Sprintf("tool message for %q must follow an assistant message with tool calls", id),
+				}
+			}
+			if !openCalls[id] {
+				return &MessageValidationError{
+					MessageIndex: i,
+					Role:         msg.Role,
+					Issue:        "unknown_tool_call_id",
+					Detail:       fmt.Sprintf("tool call ID %q does not match any tool call in the preceding assistant message", id),
+				}
+			}
+			// Each tool call is answered once
+			delete(openCalls, id)
+		}
+	}
+
+	return nil
+}
+
+// toolResultIDs returns the tool call IDs a tool message responds to, from either the
+// legacy ToolCallID field or tool_result content parts
+func toolResultIDs(msg types.ChatMessage) []string {
+	if msg.ToolCallID != "" {
+		return []string{msg.ToolCallID}
+	}
+	var ids []string
+	for _, part := range msg.Parts {
+		if part.Type == types.ContentTypeToolResult && part.ToolUseID != "" {
+			ids = append(ids, part.ToolUseID)
+		}
+	}
+	return ids
+}
+
+// ValidateLeadingSystemMessages rejects system messages after the conversation has
+// started, for providers that take the system prompt as a request field. The error is
+// a *MessageValidationError with Issue "misplaced_system".
+func ValidateLeadingSystemMessages(messages []types.ChatMessage, provider types.ProviderType) error {
+	started := false
+	for i, msg := range messages {
+		if msg.Role != RoleSystem {
+			started = true
+			continue
+		}
+		if started {
+			return &MessageValidationError{
+				MessageIndex: i,
+				Role:         msg.Role,
+				Issue:        "misplaced_system",
+				Detail:       fmt.Sprintf("%s only accepts system messages at the start of the conversation", provider),
+			}
+		}
+	}
+	return nil
+}
+
+// systemPromptIsTopLevel reports whether the provider takes the system prompt as a
+// request field rather than as a message
+func systemPromptIsTopLevel(provider types.ProviderType) bool {
+	return provider == types.ProviderTypeAnthropic || provider == types.ProviderTypeGemini
+}
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func toolCallMessage(ids ...string) types.ChatMessage {
+	msg := types.ChatMessage{Role: "assistant"}
+	for _, id := range ids {
+		msg.ToolCalls = append(msg.ToolCalls, types.ToolCall{ID: id, Type: "function", Function: types.ToolCallFunction{Name: "lookup"}})
+	}
+	return msg
+}
+
+func TestNormalizeMessages_Valid(t *testing.T) {
+	messages := []types.ChatMessage{
+		{Role: "system", Content: "Be brief"},
+		{Role: "User", Content: "Weather in Paris and Rome?"},
+		toolCallMessage("call_1", "call_2"),
+		{Role: "tool", ToolCallID: "call_1", Content: "Sunny"},
+		{Role: "tool", Parts: []types.ContentPart{{Type: types.ContentTypeToolResult, ToolUseID: "call_2", Content: "Rainy"}}},
+		{Role: "model", Content: "Paris is sunny, Rome is rainy."},
+	}
+
+	for _, provider := range []types.ProviderType{types.ProviderTypeOpenAI, types.ProviderTypeAnthropic, types.ProviderTypeGemini} {
+		t.Run(string(provider), func(t *testing.T) {
+			normalized, err := NormalizeMessages(messages, provider)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if normalized[1].Role != "user" {
+				t.Errorf("expected role 'user', got %q", normalized[1].Role)
+			}
+			if normalized[5].Role != "assistant" {
+				t.Errorf("expected role 'assistant', got %q", normalized[5].Role)
+			}
+		})
+	}
+
+	// The input must not be modified
+	if messages[1].Role != "User" {
+		t.Errorf("expected input to be unchanged, got role %q", messages[1].Role)
+	}
+}
+
+func TestNormalizeMessages_DeveloperRole(t *testing.T) {
+	messages := []types.ChatMessage{
+		{Role: "developer", Content: "Be brief"},
+		{Role: "user", Content: "Hi"},
+	}
+
+	openai, err := NormalizeMessages(messages, types.ProviderTypeOpenAI)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if openai[0].Role != "developer" {
+		t.Errorf("expected OpenAI to keep developer role, got %q", openai[0].Role)
+	}
+
+	anthropic, err := NormalizeMessages(messages, types.ProviderTypeAnthropic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if anthropic[0].Role != "system" {
+		t.Errorf("expected developer to become system for Anthropic, got %q", anthropic[0].Role)
+	}
+}
+
+func TestNormalizeMessages_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider types.ProviderType
+		messages []types.ChatMessage
+		index    int
+		issue    string
+	}{
+		{
+			name:     "unknown role",
+			provider: types.ProviderTypeOpenAI,
+			messages: []types.ChatMessage{{Role: "narrator", Content: "Once upon a time"}},
+			index:    0,
+			issue:    "invalid_role",
+		},
+		{
+			name:     "empty role",
+			provider: types.ProviderTypeOpenAI,
+			messages: []types.ChatMessage{{Content: "Hi"}},
+			index:    0,
+			issue:    "invalid_role",
+		},
+		{
+			name:     "tool message without preceding tool call",
+			provider: types.ProviderTypeOpenAI,
+			messages: []types.ChatMessage{
+				{Role: "user", Content: "Hi"},
+				{Role: "tool", ToolCallID: "call_1", Content: "Sunny"},
+			},
+			index: 1,
+			issue: "orphan_tool_message",
+		},
+		{
+			name:     "tool message with mismatched id",
+			provider: types.ProviderTypeOpenAI,
+			messages: []types.ChatMessage{
+				{Role: "user", Content: "Hi"},
+				toolCallMessage("call_1"),
+				{Role: "tool", ToolCallID: "call_9", Content: "Sunny"},
+			},
+			index: 2,
+			issue: "unknown_tool_call_id",
+		},
+		{
+			name:     "tool call answered twice",
+			provider: types.ProviderTypeOpenAI,
+			messages: []types.ChatMessage{
+				{Role: "user", Content: "Hi"},
+				toolCallMessage("call_1"),
+				{Role: "tool", ToolCallID: "call_1", Content: "Sunny"},
+				{Role: "tool", ToolCallID: "call_1", Content: "Sunny"},
+			},
+			index: 3,
+			issue: "unknown_tool_call_id",
+		},
+		{
+			name:     "tool message without id",
+			provider: types.ProviderTypeOpenAI,
+			messages: []types.ChatMessage{
+				{Role: "user", Content: "Hi"},
+				toolCallMessage("call_1"),
+				{Role: "tool", Content: "Sunny"},
+			},
+			index: 2,
+			issue: "missing_tool_call_id",
+		},
+		{
+			name:     "mid-conversation system message for anthropic",
+			provider: types.ProviderTypeAnthropic,
+			messages: []types.ChatMessage{
+				{Role: "user", Content: "Hi"},
+				{Role: "system", Content: "Be brief"},
+			},
+			index: 1,
+			issue: "misplaced_system",
+		},
+		{
+			name:     "anthropic conversation starting with assistant",
+			provider: types.ProviderTypeAnthropic,
+			messages: []types.ChatMessage{
+				{Role: "system", Content: "Be brief"},
+				{Role: "assistant", Content: "Hello"},
+			},
+			index: 1,
+			issue: "invalid_first_message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NormalizeMessages(tt.messages, tt.provider)
+			var msgErr *MessageValidationError
+			if !errors.As(err, &msgErr) {
+				t.Fatalf("expected MessageValidationError, got %v", err)
+			}
+			if msgErr.MessageIndex != tt.index {
+				t.Errorf("expected index %d, got %d", tt.index, msgErr.MessageIndex)
+			}
+			if msgErr.Issue != tt.issue {
+				t.Errorf("expected issue %q, got %q (%v)", tt.issue, msgErr.Issue, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeMessages_MidConversationSystemAllowedForOpenAI(t *testing.T) {
+	messages := []types.ChatMessage{
+		{Role: "user", Content: "Hi"},
+		{Role: "system", Content: "Be brief"},
+	}
+	if _, err := NormalizeMessages(messages, types.ProviderTypeOpenAI); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
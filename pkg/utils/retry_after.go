@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/providers/common/retry"
+)
+
+// ParseRetryAfter extracts how long to wait before retrying from a rate-limited response.
+// Returns false if the response carries no usable retry hint. The headers recognized are
+// those of retry.LookupRetryAfter, including retry-after-ms; unlike
+// ProviderError.RetryAfter, the duration keeps sub-second precision.
+func ParseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	return retry.LookupRetryAfter(resp.Header)
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func responseWithHeaders(headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header)}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			name:    "no headers",
+			headers: nil,
+			ok:      false,
+		},
+		{
+			name:     "retry-after seconds",
+			headers:  map[string]string{"Retry-After": "30"},
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		{
+			name:     "retry-after zero",
+			headers:  map[string]string{"Retry-After": "0"},
+			expected: 0,
+			ok:       true,
+		},
+		{
+			name:     "retry-after-ms keeps millisecond precision",
+			headers:  map[string]string{"retry-after-ms": "1250"},
+			expected: 1250 * time.Millisecond,
+			ok:       true,
+		},
+		{
+			name:     "retry-after-ms takes precedence over retry-after",
+			headers:  map[string]string{"retry-after-ms": "250", "Retry-After": "1"},
+			expected: 250 * time.Millisecond,
+			ok:       true,
+		},
+		{
+			name:    "unparseable retry-after",
+			headers: map[string]string{"Retry-After": "soon"},
+			ok:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(responseWithHeaders(tt.headers))
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter_NilResponse(t *testing.T) {
+	if _, ok := ParseRetryAfter(nil); ok {
+		t.Error("expected nil response to report no retry hint")
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// CountStreamTokens wraps a stream so that every chunk carries a running completion
+// token count in its TokenCount field, for live usage and cost displays.
+//
+// Most providers only report usage at the end of a stream, so counts are estimated
+// from the streamed content, reasoning and tool call arguments using the same
+// estimator as EstimateTokensFromString. When a chunk reports completion tokens in
+// its Usage, the running total is reconciled to that authoritative value and
+// Estimated becomes false.
+//
+// Counting is opt-in because it adds per-chunk work; unwrapped streams leave
+// TokenCount nil.
+func CountStreamTokens(stream types.ChatCompletionStream) types.ChatCompletionStream {
+	return &tokenCountingStream{inner: stream, estimated: true}
+}
+
+// CountStreamTokensWith is CountStreamTokens using the given tokenizer, typically
+// TokenizerFor(provider.GetConfig()), to count the text of each chunk. A nil or
+// HeuristicTokenizer behaves exactly like CountStreamTokens.
+func CountStreamTokensWith(stream types.ChatCompletionStream, tokenizer types.Tokenizer) types.ChatCompletionStream {
+	if _, heuristic := tokenizer.(HeuristicTokenizer); heuristic {
+		tokenizer = nil
+	}
+	return &tokenCountingStream{inner: stream, tokenizer: tokenizer, estimated: true}
+}
+
+type tokenCountingStream struct {
+	inner     types.ChatCompletionStream
+	tokenizer types.Tokenizer // nil uses the byte heuristic
+
+	// reconciled is the last provider-reported completion token count; bytes and
+	// counted are the text streamed since then, in bytes (heuristic) or tokens
+	reconciled int
+	bytes      int
+	counted    int
+	total      int
+	estimated  bool
+}
+
+func (s *tokenCountingStream) Next() (types.ChatCompletionChunk, error) {
+	chunk, err := s.inner.Next()
+
+	previous := s.total
+	if chunk.Usage.CompletionTokens > 0 {
+		s.reconciled = chunk.Usage.CompletionTokens
+		s.bytes = 0
+		s.counted = 0
+		s.total = s.reconciled
+		s.estimated = false
+	} else if text := chunkText(chunk); text != "" {
+		if s.tokenizer != nil {
+			s.counted += s.tokenizer.CountTokens(text)
+		} else {
+			// Estimate from the accumulated length so per-chunk rounding doesn't add up
+			s.bytes += len(text)
+			s.counted = EstimateTokensFromBytes(s.bytes)
+		}
+		s.total = s.reconciled + s.counted
+		s.estimated = true
+	}
+
+	chunk.TokenCount = &types.ChunkTokenCount{
+		Delta:     s.total - previous,
+		Total:     s.total,
+		Estimated: s.estimated,
+	}
+	return chunk, err
+}
+
+func (s *tokenCountingStream) Close() error {
+	return s.inner.Close()
+}
+
+// chunkText returns the generated text in a chunk
+func chunkText(chunk types.ChatCompletionChunk) string {
+	var b strings.Builder
+	b.WriteString(chunk.Content)
+
+	// Providers populate one of the two reasoning fields
+	if chunk.Reasoning != "" {
+		b.WriteString(chunk.Reasoning)
+	} else {
+		b.WriteString(chunk.ReasoningContent)
+	}
+
+	for _, choice := range chunk.Choices {
+		// Content is usually mirrored from the first choice's delta; count it once
+		if chunk.Content == "" {
+			b.WriteString(choice.Delta.Content)
+		}
+		for _, tc := range choice.Delta.ToolCalls {
+			b.WriteString(tc.Function.Name)
+			b.WriteString(tc.Function.Arguments)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"io"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// chunkStream returns its chunks in order, then io.EOF
+type chunkStream struct {
+	chunks []types.ChatCompletionChunk
+	index  int
+	closed bool
+}
+
+func (s *chunkStream) Next() (types.ChatCompletionChunk, error) {
+	if s.index >= len(s.chunks) {
+		return types.ChatCompletionChunk{Done: true}, io.EOF
+	}
+	s.index++
+	return s.chunks[s.index-1], nil
+}
+
+func (s *chunkStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestCountStreamTokens(t *testing.T) {
+	inner := &chunkStream{chunks: []types.ChatCompletionChunk{
+		{Content: "Hello, this is a streamed"},
+		{Content: " response from the model."},
+		{Content: "", Done: true, Usage: types.Usage{PromptTokens: 5, CompletionTokens: 9, TotalTokens: 14}},
+	}}
+	stream := CountStreamTokens(inner)
+
+	first, err := stream.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := EstimateTokensFromString("Hello, this is a streamed")
+	if first.TokenCount == nil || first.TokenCount.Total != want || first.TokenCount.Delta != want || !first.TokenCount.Estimated {
+		t.Errorf("first chunk: expected estimated total and delta %d, got %+v", want, first.TokenCount)
+	}
+
+	second, _ := stream.Next()
+	wantTotal := EstimateTokensFromString("Hello, this is a streamed response from the model.")
+	if second.TokenCount.Total != wantTotal {
+		t.Errorf("second chunk: expected total %d estimated from all text, got %d", wantTotal, second.TokenCount.Total)
+	}
+	if second.TokenCount.Delta != wantTotal-want {
+		t.Errorf("second chunk: expected delta %d, got %d", wantTotal-want, second.TokenCount.Delta)
+	}
+
+	final, _ := stream.Next()
+	if final.TokenCount.Total != 9 || final.TokenCount.Estimated {
+		t.Errorf("final chunk: expected reconciled total 9, got %+v", final.TokenCount)
+	}
+	if final.TokenCount.Delta != 9-wantTotal {
+		t.Errorf("final chunk: expected correction delta %d, got %d", 9-wantTotal, final.TokenCount.Delta)
+	}
+
+	if _, err := stream.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if err := stream.Close(); err != nil || !inner.closed {
+		t.Error("expected Close to close the underlying stream")
+	}
+}
+
+func TestCountStreamTokens_ReasoningAndToolCalls(t *testing.T) {
+	stream := CountStreamTokens(&chunkStream{chunks: []types.ChatCompletionChunk{
+		{Reasoning: "Let me think about the weather request"},
+		{Choices: []types.ChatChoice{{Delta: types.ChatMessage{ToolCalls: []types.ToolCall{
+			{Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Paris, France"}`}},
+		}}}}},
+	}})
+
+	reasoning, _ := stream.Next()
+	if reasoning.TokenCount.Delta == 0 {
+		t.Error("expected reasoning text to be counted")
+	}
+
+	toolCall, _ := stream.Next()
+	if toolCall.TokenCount.Delta == 0 {
+		t.Error("expected tool call arguments to be counted")
+	}
+}
+
+func TestCountStreamTokensWith(t *testing.T) {
+	stream := CountStreamTokensWith(&chunkStream{chunks: []types.ChatCompletionChunk{
+		{Content: "one two"},
+		{Content: " three"},
+		{Done: true, Usage: types.Usage{CompletionTokens: 4}},
+	}}, wordTokenizer{})
+
+	var totals []int
+	for {
+		chunk, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		totals = append(totals, chunk.TokenCount.Total)
+	}
+
+	want := []int{2, 3, 4}
+	if len(totals) != len(want) {
+		t.Fatalf("expected %d chunks, got %d", len(want), len(totals))
+	}
+	for i := range want {
+		if totals[i] != want[i] {
+			t.Errorf("chunk %d: expected total %d, got %d", i, want[i], totals[i])
+		}
+	}
+}
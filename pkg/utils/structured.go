@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// structuredSnippetLength is the most content quoted in a StructuredOutputError
+const structuredSnippetLength = 200
+
+// StructuredOutputError reports response content that could not be parsed into the
+// requested type
+type StructuredOutputError struct {
+	// Content is the content that failed to parse, after code fences were stripped
+	Content string
+	Err     error
+}
+
+func (e *StructuredOutputError) Error() string {
+	snippet := e.Content
+	if len(snippet) > structuredSnippetLength {
+		snippet = snippet[:structuredSnippetLength] + "..."
+	}
+	return fmt.Sprintf("failed to parse structured output: %v; content: %q", e.Err, snippet)
+}
+
+func (e *StructuredOutputError) Unwrap() error {
+	return e.Err
+}
+
+// ParseStructured unmarshals the assistant content of a completed response into T,
+// stripping a Markdown code fence around the JSON if present (see StripCodeFence). It
+// returns a *StructuredOutputError quoting the start of the content when the content is
+// not valid JSON for T.
+//
+//	type Weather struct {
+//	    City string  `json:"city"`
+//	    TempC float64 `json:"temp_c"`
+//	}
+//	weather, err := utils.ParseStructured[Weather](&chunk)
+func ParseStructured[T any](resp *types.ChatCompletionChunk) (T, error) {
+	var zero T
+	if resp == nil {
+		return zero, errors.New("failed to parse structured output: no response")
+	}
+	return ParseStructuredContent[T](ResponseContent(resp))
+}
+
+// ParseStructuredContent is ParseStructured for content already extracted from a response
+func ParseStructuredContent[T any](content string) (T, error) {
+	var value T
+	stripped := StripCodeFence(content)
+	if stripped == "" {
+		return value, &StructuredOutputError{Content: content, Err: errors.New("empty content")}
+	}
+	if err := json.Unmarshal([]byte(stripped), &value); err != nil {
+		return value, &StructuredOutputError{Content: stripped, Err: err}
+	}
+	return value, nil
+}
+
+// ResponseContent returns the assistant content of a completed response: its Content,
+// or the content of its first choice's message when Content is empty
+func ResponseContent(resp *types.ChatCompletionChunk) string {
+	if resp.Content != "" || len(resp.Choices) == 0 {
+		return resp.Content
+	}
+	if content := resp.Choices[0].Message.GetTextContent(); content != "" {
+		return content
+	}
+	return resp.Choices[0].Delta.GetTextContent()
+}
+
+// StripCodeFence returns the body of the first Markdown code fence in content, such as
+// "```json\n{...}\n```", with any text around the fence dropped. Content that is
+// already valid JSON, such as an object with "```" in a string value, or that has no
+// fence is returned with surrounding whitespace trimmed.
+func StripCodeFence(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if json.Valid([]byte(trimmed)) {
+		return trimmed
+	}
+	if body, ok := codeFence(trimmed); ok {
+		return strings.TrimSpace(body)
+	}
+	return trimmed
+}
+
+// codeFence returns the body of the first Markdown code fence in content, reporting
+// false when there is none. An opening fence followed by a language tag and a newline
+// ("```json\n") has the tag skipped; one followed directly by its body
+// ("```{...}```") has none. An unterminated fence runs to the end of content.
+func codeFence(content string) (string, bool) {
+	start := strings.Index(content, "```")
+	if start < 0 {
+		return "", false
+	}
+
+	body := content[start+3:]
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 && isFenceInfo(body[:newline]) {
+		body = body[newline+1:]
+	}
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return body, true
+}
+
+// isFenceInfo reports whether the rest of an opening fence's line is a language tag
+// such as "json" or "c++", or empty
+func isFenceInfo(line string) bool {
+	for _, r := range strings.TrimSpace(line) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+-_.#", r) {
+			return false
+		}
+	}
+	return true
+}
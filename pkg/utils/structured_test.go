@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type structuredWeather struct {
+	City  string  `json:"city"`
+	TempC float64 `json:"temp_c"`
+}
+
+func TestStripCodeFence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "no fence", content: "  {\"a\":1}\n", want: `{"a":1}`},
+		{name: "json fence", content: "```json\n{\"a\":1}\n```", want: `{"a":1}`},
+		{name: "bare fence", content: "```\n[1, 2]\n```", want: `[1, 2]`},
+		{name: "prose around fence", content: "Here you go:\n```json\n{\"a\":1}\n```\nAnything else?", want: `{"a":1}`},
+		{name: "single line fence", content: "```{\"a\":1}```", want: `{"a":1}`},
+		{name: "unterminated fence", content: "```json\n{\"a\":1}", want: `{"a":1}`},
+		{name: "single line fence before prose", content: "```{\"a\":1}```\nthanks", want: `{"a":1}`},
+		{name: "json containing backticks", content: " {\"code\":\"```go\\nfmt.Println()\\n```\"}\n", want: `{"code":"` + "```go\\nfmt.Println()\\n```" + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripCodeFence(tt.content))
+		})
+	}
+}
+
+func TestParseStructured(t *testing.T) {
+	resp := &types.ChatCompletionChunk{Content: "```json\n{\"city\": \"Paris\", \"temp_c\": 21.5}\n```"}
+	weather, err := ParseStructured[structuredWeather](resp)
+	require.NoError(t, err)
+	assert.Equal(t, structuredWeather{City: "Paris", TempC: 21.5}, weather)
+
+	resp = &types.ChatCompletionChunk{Choices: []types.ChatChoice{
+		{Message: types.ChatMessage{Role: "assistant", Content: `{"city": "Oslo", "temp_c": -3}`}},
+	}}
+	weather, err = ParseStructured[structuredWeather](resp)
+	require.NoError(t, err)
+	assert.Equal(t, "Oslo", weather.City)
+
+	cities, err := ParseStructuredContent[[]string](`["Paris", "Oslo"]`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Paris", "Oslo"}, cities)
+}
+
+func TestParseStructured_Errors(t *testing.T) {
+	_, err := ParseStructured[structuredWeather](nil)
+	assert.Error(t, err)
+
+	_, err = ParseStructured[structuredWeather](&types.ChatCompletionChunk{})
+	var structuredErr *StructuredOutputError
+	require.ErrorAs(t, err, &structuredErr)
+
+	_, err = ParseStructuredContent[structuredWeather](`{"city": 42}`)
+	require.ErrorAs(t, err, &structuredErr)
+	assert.Contains(t, err.Error(), `{\"city\": 42}`, "the offending content is quoted")
+
+	_, err = ParseStructuredContent[structuredWeather]("Sorry, " + strings.Repeat("I can't do that. ", 50))
+	require.ErrorAs(t, err, &structuredErr)
+	assert.Less(t, len(err.Error()), 400, "long content is truncated to a snippet")
+}
@@ -27,6 +27,32 @@ func EstimateTokensFromMessages(messages []types.ChatMessage) int {
 	return total
 }
 
+// HeuristicTokenizer implements types.Tokenizer with the byte-length estimate used by
+// EstimateTokensFromString. It needs no model vocabulary, so it works for every
+// provider, but counts are approximate.
+type HeuristicTokenizer struct{}
+
+// CountTokens estimates the tokens in text
+func (HeuristicTokenizer) CountTokens(text string) int {
+	return EstimateTokensFromString(text)
+}
+
+// CountMessages estimates the tokens across all messages
+func (HeuristicTokenizer) CountMessages(messages []types.ChatMessage) int {
+	return EstimateTokensFromMessages(messages)
+}
+
+// TokenizerFor returns the tokenizer registered in the provider config, falling back
+// to HeuristicTokenizer when none is set:
+//
+//	tokens := utils.TokenizerFor(provider.GetConfig()).CountMessages(messages)
+func TokenizerFor(config types.ProviderConfig) types.Tokenizer {
+	if config.Tokenizer != nil {
+		return config.Tokenizer
+	}
+	return HeuristicTokenizer{}
+}
+
 // BytesPerToken is the empirically-derived average bytes per token.
 // Can be used by consumers for custom calculations.
 const BytesPerToken = 4.7
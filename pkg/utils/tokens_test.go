@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
@@ -382,3 +383,37 @@ func abs(x int) int {
 	}
 	return x
 }
+
+// wordTokenizer counts whitespace-separated words, standing in for an exact tokenizer
+type wordTokenizer struct{}
+
+func (wordTokenizer) CountTokens(text string) int { return len(strings.Fields(text)) }
+
+func (w wordTokenizer) CountMessages(messages []types.ChatMessage) int {
+	total := 0
+	for _, msg := range messages {
+		total += w.CountTokens(msg.GetTextContent())
+	}
+	return total
+}
+
+func TestTokenizerFor(t *testing.T) {
+	t.Run("falls back to heuristic", func(t *testing.T) {
+		tokenizer := TokenizerFor(types.ProviderConfig{Type: types.ProviderTypeOpenAI})
+		if _, ok := tokenizer.(HeuristicTokenizer); !ok {
+			t.Fatalf("expected HeuristicTokenizer, got %T", tokenizer)
+		}
+		text := "The quick brown fox jumps over the lazy dog"
+		if got, want := tokenizer.CountTokens(text), EstimateTokensFromString(text); got != want {
+			t.Errorf("CountTokens() = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("uses registered tokenizer", func(t *testing.T) {
+		tokenizer := TokenizerFor(types.ProviderConfig{Type: types.ProviderTypeOpenAI, Tokenizer: wordTokenizer{}})
+		messages := []types.ChatMessage{{Role: "user", Content: "one two three"}, {Role: "assistant", Content: "four"}}
+		if got := tokenizer.CountMessages(messages); got != 4 {
+			t.Errorf("CountMessages() = %d, want 4", got)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+// ToolExecutor executes a single tool call and returns the content of its result
+type ToolExecutor func(ctx context.Context, call types.ToolCall) (string, error)
+
+// ToolExecutionOptions configures ExecuteToolCalls
+type ToolExecutionOptions struct {
+	// DeduplicateCalls executes identical tool calls of a turn (same name and
+	// arguments, see ToolCallKey) once and answers each of their IDs with the shared
+	// result. Off by default, since some tools are meant to be called repeatedly,
+	// e.g. to draw several random values.
+	DeduplicateCalls bool
+}
+
+// ToolCallGroup is a tool call together with the IDs of all identical calls in a turn
+type ToolCallGroup struct {
+	// Call is the first occurrence of the call
+	Call types.ToolCall
+
+	// IDs are the tool call IDs answered by Call's result, in order of occurrence
+	IDs []string
+}
+
+// ToolCallKey returns a stable hash of a tool call's name and normalized arguments.
+// Arguments that are valid JSON are re-encoded, so formatting and object key order
+// do not matter; other arguments are compared with surrounding whitespace trimmed.
+func ToolCallKey(call types.ToolCall) string {
+	args := strings.TrimSpace(call.Function.Arguments)
+	if args == "" {
+		args = "{}"
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(args), &value); err == nil {
+		if normalized, err := json.Marshal(value); err == nil {
+			args = string(normalized)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(call.Function.Name + "\x00" + args))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeduplicateToolCalls groups identical tool calls by ToolCallKey, in order of first
+// occurrence
+func DeduplicateToolCalls(calls []types.ToolCall) []ToolCallGroup {
+	groups := make([]ToolCallGroup, 0, len(calls))
+	byKey := make(map[string]int, len(calls))
+	for _, call := range calls {
+		key := ToolCallKey(call)
+		if i, ok := byKey[key]; ok {
+			groups[i].IDs = append(groups[i].IDs, call.ID)
+			continue
+		}
+		byKey[key] = len(groups)
+		groups = append(groups, ToolCallGroup{Call: call, IDs: []string{call.ID}})
+	}
+	return groups
+}
+
+// ExecuteToolCalls executes the tool calls of one assistant turn and returns a tool
+// result message for every call, in the order of calls, so that each tool call ID is
+// answered and the conversation stays well-formed. With DeduplicateCalls set, each
+// unique call is executed once. Execution stops at the first error.
+func ExecuteToolCalls(ctx context.Context, calls []types.ToolCall, execute ToolExecutor, options ToolExecutionOptions) ([]types.ChatMessage, error) {
+	var groups []ToolCallGroup
+	if options.DeduplicateCalls {
+		groups = DeduplicateToolCalls(calls)
+	} else {
+		groups = make([]ToolCallGroup, len(calls))
+		for i, call := range calls {
+			groups[i] = ToolCallGroup{Call: call, IDs: []string{call.ID}}
+		}
+	}
+
+	results := make(map[string]string, len(calls))
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := execute(ctx, group.Call)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s (call %s) failed: %w", group.Call.Function.Name, group.Call.ID, err)
+		}
+		for _, id := range group.IDs {
+			results[id] = result
+		}
+	}
+
+	messages := make([]types.ChatMessage, len(calls))
+	for i, call := range calls {
+		messages[i] = types.ChatMessage{
+			Role:       RoleTool,
+			Content:    results[call.ID],
+			ToolCallID: call.ID,
+		}
+	}
+	return messages, nil
+}
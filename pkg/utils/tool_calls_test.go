@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cecil-the-coder/ai-provider-kit/pkg/types"
+)
+
+func weatherCall(id, args string) types.ToolCall {
+	return types.ToolCall{ID: id, Type: "function", Function: types.ToolCallFunction{Name: "get_weather", Arguments: args}}
+}
+
+func TestToolCallKey(t *testing.T) {
+	base := ToolCallKey(weatherCall("a", `{"city":"NYC","units":"c"}`))
+
+	same := []types.ToolCall{
+		weatherCall("b", `{"units":"c","city":"NYC"}`),
+		weatherCall("c", " {\n  \"city\": \"NYC\",\n  \"units\": \"c\"\n} "),
+	}
+	for _, call := range same {
+		if key := ToolCallKey(call); key != base {
+			t.Errorf("expected %q to normalize to the same key", call.Function.Arguments)
+		}
+	}
+
+	different := []types.ToolCall{
+		weatherCall("d", `{"city":"LA","units":"c"}`),
+		{ID: "e", Function: types.ToolCallFunction{Name: "get_forecast", Arguments: `{"city":"NYC","units":"c"}`}},
+	}
+	for _, call := range different {
+		if key := ToolCallKey(call); key == base {
+			t.Errorf("expected %s(%s) to have a different key", call.Function.Name, call.Function.Arguments)
+		}
+	}
+
+	if ToolCallKey(weatherCall("f", "")) != ToolCallKey(weatherCall("g", "{}")) {
+		t.Error("expected empty arguments to equal an empty object")
+	}
+}
+
+func TestDeduplicateToolCalls(t *testing.T) {
+	groups := DeduplicateToolCalls([]types.ToolCall{
+		weatherCall("call_1", `{"city":"NYC"}`),
+		weatherCall("call_2", `{"city":"LA"}`),
+		weatherCall("call_3", `{ "city": "NYC" }`),
+	})
+
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Call.ID != "call_1" || strings.Join(groups[0].IDs, ",") != "call_1,call_3" {
+		t.Errorf("unexpected first group %+v", groups[0])
+	}
+	if groups[1].Call.ID != "call_2" || strings.Join(groups[1].IDs, ",") != "call_2" {
+		t.Errorf("unexpected second group %+v", groups[1])
+	}
+}
+
+func TestExecuteToolCalls(t *testing.T) {
+	calls := []types.ToolCall{
+		weatherCall("call_1", `{"city":"NYC"}`),
+		weatherCall("call_2", `{"city":"LA"}`),
+		weatherCall("call_3", `{"city": "NYC"}`),
+	}
+
+	tests := []struct {
+		name          string
+		options       ToolExecutionOptions
+		expectedCalls int
+	}{
+		{name: "duplicates executed by default", options: ToolExecutionOptions{}, expectedCalls: 3},
+		{name: "deduplicated when enabled", options: ToolExecutionOptions{DeduplicateCalls: true}, expectedCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed := 0
+			execute := func(_ context.Context, call types.ToolCall) (string, error) {
+				executed++
+				return "weather for " + call.Function.Arguments, nil
+			}
+
+			messages, err := ExecuteToolCalls(context.Background(), calls, execute, tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if executed != tt.expectedCalls {
+				t.Errorf("expected %d executions, got %d", tt.expectedCalls, executed)
+			}
+			if len(messages) != len(calls) {
+				t.Fatalf("expected a result for each of %d calls, got %d", len(calls), len(messages))
+			}
+			for i, msg := range messages {
+				if msg.Role != RoleTool || msg.ToolCallID != calls[i].ID || msg.Content == "" {
+					t.Errorf("unexpected result %d: %+v", i, msg)
+				}
+			}
+			if messages[2].Content != messages[0].Content && tt.options.DeduplicateCalls {
+				t.Errorf("expected call_3 to reuse call_1's result, got %q", messages[2].Content)
+			}
+
+			// Results answer the assistant turn's tool calls
+			conversation := []types.ChatMessage{{Role: "user", Content: "weather?"}, {Role: "assistant", ToolCalls: calls}}
+			if _, err := NormalizeMessages(append(conversation, messages...), types.ProviderTypeOpenAI); err != nil {
+				t.Errorf("conversation is not well-formed: %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteToolCalls_Error(t *testing.T) {
+	toolErr := errors.New("service unavailable")
+	execute := func(_ context.Context, call types.ToolCall) (string, error) {
+		return "", toolErr
+	}
+
+	_, err := ExecuteToolCalls(context.Background(), []types.ToolCall{weatherCall("call_1", "{}")}, execute, ToolExecutionOptions{})
+	if !errors.Is(err, toolErr) {
+		t.Fatalf("expected wrapped tool error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "get_weather") {
+		t.Errorf("expected tool name in error, got %v", err)
+	}
+}